@@ -0,0 +1,311 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Measures computed by MaxDepth, ComplexityScore and Aliases expand
+// fragment spreads, so a fragment spread n times counts n times. Spreads
+// of unknown fragments and spreads closing fragment cycles count as empty
+// selections. Results of fragments are memoized, so documents nesting
+// many spreads are measured in linear time.
+
+// MaxDepth returns maximum nesting of fields of operations of doc, e.g. 2
+// for { user { name } }. Inline fragments and fragment spreads do not add
+// depth.
+func MaxDepth(doc *ast.Document) int {
+	return maxOverOperations(doc, OperationDepth)
+}
+
+// OperationDepth returns maximum nesting of fields of op.
+func OperationDepth(doc *ast.Document, op *ast.OperationDefinition) int {
+	m := newMeasure(doc, func(m *measure, set *ast.SelectionSet, _ string) int {
+		depth := 0
+		m.fields(set, func(f *ast.Field) {
+			depth = max(depth, 1+m.selectionSet(f.SelectionSet, ""))
+		}, func(n int) {
+			depth = max(depth, n)
+		})
+		return depth
+	})
+	return m.selectionSet(op.SelectionSet, "")
+}
+
+// Aliases returns maximum number of aliased fields of operations of doc.
+// Many aliases of the same field multiply work of a single request.
+func Aliases(doc *ast.Document) int {
+	return maxOverOperations(doc, OperationAliases)
+}
+
+// OperationAliases returns number of aliased fields of op.
+func OperationAliases(doc *ast.Document, op *ast.OperationDefinition) int {
+	m := newMeasure(doc, func(m *measure, set *ast.SelectionSet, _ string) int {
+		aliases := 0
+		m.fields(set, func(f *ast.Field) {
+			if f.Alias != nil {
+				aliases++
+			}
+			aliases += m.selectionSet(f.SelectionSet, "")
+		}, func(n int) {
+			aliases += n
+		})
+		return aliases
+	})
+	return m.selectionSet(op.SelectionSet, "")
+}
+
+// Weights configure ComplexityScore. The zero value costs every field 1.
+type Weights struct {
+	// Fields are costs of fields by schema coordinate, e.g.
+	// "Query.search". Fields not listed cost Default.
+	Fields map[string]int
+
+	// Default is cost of fields without weight, 1 when zero. __typename
+	// costs nothing.
+	Default int
+
+	// ListSizeArguments name integer arguments bounding sizes of lists
+	// returned by fields, "first", "last" and "limit" by default.
+	ListSizeArguments []string
+
+	// DefaultListSize is assumed size of lists when list size argument is
+	// absent or a variable. Lists are not multiplied when it is zero.
+	DefaultListSize int
+}
+
+var defaultListSizeArguments = []string{"first", "last", "limit"}
+
+// ComplexityScore returns maximum complexity of operations of doc
+// against s. See OperationComplexity.
+func ComplexityScore(doc *ast.Document, s *schema.Schema, w Weights) int {
+	return maxOverOperations(doc, func(doc *ast.Document, op *ast.OperationDefinition) int {
+		return OperationComplexity(doc, op, s, w)
+	})
+}
+
+// OperationComplexity returns complexity of op against s: the sum of
+// costs of its fields, where cost of field returning list is multiplied
+// by list size. Fragments on different types of abstract type are all
+// counted, so the score is an upper bound. Fields not defined in s cost
+// Default and are not multiplied.
+func OperationComplexity(doc *ast.Document, op *ast.OperationDefinition, s *schema.Schema, w Weights) int {
+	if w.Default == 0 {
+		w.Default = 1
+	}
+	if w.ListSizeArguments == nil {
+		w.ListSizeArguments = defaultListSizeArguments
+	}
+	root := ""
+	if t := s.RootType(op.OperationType); t != nil {
+		root = t.Name
+	}
+	m := newMeasure(doc, func(m *measure, set *ast.SelectionSet, parent string) int {
+		cost := 0
+		m.fieldsOn(set, parent, func(f *ast.Field, parent string) {
+			if f.Name.Value == "__typename" {
+				return
+			}
+			var def *schema.Field
+			if t := s.Type(parent); t != nil {
+				def = t.Field(f.Name.Value)
+			}
+			weight, ok := w.Fields[parent+"."+f.Name.Value]
+			if !ok {
+				weight = w.Default
+			}
+			if def == nil {
+				cost += weight + m.selectionSet(f.SelectionSet, "")
+				return
+			}
+			size := 1
+			if isList(def.Type) {
+				size = listSize(f, w)
+			}
+			cost += weight + size*m.selectionSet(f.SelectionSet, namedType(def.Type))
+		}, func(n int) {
+			cost += n
+		})
+		return cost
+	})
+	return m.selectionSet(op.SelectionSet, root)
+}
+
+// Limits are maximum measures of operations; zero fields are not checked.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+	MaxAliases    int
+}
+
+// Check returns error describing every limit exceeded by operations of
+// doc, with complexity computed against s using w, or nil.
+func (l Limits) Check(doc *ast.Document, s *schema.Schema, w Weights) error {
+	var errs []error
+	check := func(op *ast.OperationDefinition, measure string, value, limit int) {
+		if limit > 0 && value > limit {
+			name := "anonymous operation"
+			if op.Name != nil {
+				name = "operation " + strconv.Quote(op.Name.Value)
+			}
+			errs = append(errs, fmt.Errorf("analysis: %s: %s %d exceeds limit %d", name, measure, value, limit))
+		}
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if l.MaxDepth > 0 {
+			check(op, "depth", OperationDepth(doc, op), l.MaxDepth)
+		}
+		if l.MaxComplexity > 0 {
+			check(op, "complexity", OperationComplexity(doc, op, s, w), l.MaxComplexity)
+		}
+		if l.MaxAliases > 0 {
+			check(op, "aliases", OperationAliases(doc, op), l.MaxAliases)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func maxOverOperations(doc *ast.Document, fn func(*ast.Document, *ast.OperationDefinition) int) int {
+	result := 0
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			result = max(result, fn(doc, op))
+		}
+	}
+	return result
+}
+
+// measure computes a measure of selection sets, memoizing measures of
+// fragments by name and type they are spread on.
+type measure struct {
+	fragments map[string]*ast.FragmentDefinition
+	memo      map[fragmentKey]int
+	visiting  map[string]bool
+	fn        func(m *measure, set *ast.SelectionSet, parent string) int
+}
+
+type fragmentKey struct {
+	name, parent string
+}
+
+func newMeasure(doc *ast.Document, fn func(m *measure, set *ast.SelectionSet, parent string) int) *measure {
+	m := &measure{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		memo:      make(map[fragmentKey]int),
+		visiting:  make(map[string]bool),
+		fn:        fn,
+	}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			m.fragments[f.Name.Value] = f
+		}
+	}
+	return m
+}
+
+// selectionSet returns measure of set selected on type parent, which is
+// empty when unknown.
+func (m *measure) selectionSet(set *ast.SelectionSet, parent string) int {
+	if set == nil {
+		return 0
+	}
+	return m.fn(m, set, parent)
+}
+
+// fields calls field for fields of set and of its inline fragments, and
+// spread with measures of spread fragments.
+func (m *measure) fields(set *ast.SelectionSet, field func(*ast.Field), spread func(int)) {
+	m.fieldsOn(set, "", func(f *ast.Field, _ string) { field(f) }, spread)
+}
+
+// fieldsOn is fields passing types fields are selected on, which are
+// type conditions of inline fragments or parent.
+func (m *measure) fieldsOn(set *ast.SelectionSet, parent string, field func(f *ast.Field, parent string), spread func(int)) {
+	for _, sel := range set.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			field(sel, parent)
+		case *ast.InlineFragment:
+			on := parent
+			if sel.TypeCondition != nil && parent != "" {
+				on = sel.TypeCondition.Name.Value
+			}
+			m.fieldsOn(sel.SelectionSet, on, field, spread)
+		case *ast.FragmentSpread:
+			spread(m.fragment(sel.Name.Value, parent))
+		}
+	}
+}
+
+// fragment returns measure of fragment spread on parent, 0 for unknown
+// fragments and cycles.
+func (m *measure) fragment(name, parent string) int {
+	f := m.fragments[name]
+	if f == nil || m.visiting[name] {
+		return 0
+	}
+	if parent != "" {
+		parent = f.TypeCondition.Name.Value
+	}
+	key := fragmentKey{name, parent}
+	if v, ok := m.memo[key]; ok {
+		return v
+	}
+	m.visiting[name] = true
+	v := m.selectionSet(f.SelectionSet, parent)
+	delete(m.visiting, name)
+	m.memo[key] = v
+	return v
+}
+
+func isList(t ast.Type) bool {
+	if nonNull, ok := t.(*ast.NonNullType); ok {
+		t = nonNull.Type
+	}
+	_, ok := t.(*ast.ListType)
+	return ok
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch wrapped := t.(type) {
+		case *ast.NonNullType:
+			t = wrapped.Type
+		case *ast.ListType:
+			t = wrapped.Type
+		case *ast.NamedType:
+			return wrapped.Name.Value
+		default:
+			return ""
+		}
+	}
+}
+
+// listSize returns size of list returned by f given by its literal list
+// size argument, or DefaultListSize, or 1 when it is zero.
+func listSize(f *ast.Field, w Weights) int {
+	for _, arg := range f.Arguments {
+		for _, name := range w.ListSizeArguments {
+			if arg.Name.Value != name {
+				continue
+			}
+			if v, ok := arg.Value.(*ast.IntValue); ok {
+				if n, err := strconv.Atoi(v.Value); err == nil && n >= 0 {
+					return n
+				}
+			}
+		}
+	}
+	if w.DefaultListSize > 0 {
+		return w.DefaultListSize
+	}
+	return 1
+}
@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+const complexitySchema = `
+type Query { user(id: ID): User users(first: Int, after: String): [User!]! node: Node search: [Result] }
+interface Node { id: ID! }
+type User implements Node { id: ID! name: String friends(first: Int): [User] posts(limit: Int): [Post] }
+type Post implements Node { id: ID! title: String author: User }
+union Result = User | Post
+type Mutation { like(id: ID!): Post }
+`
+
+func TestMaxDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{"flat", `{ a b }`, 1},
+		{"nested", `{ a { b { c } } d }`, 3},
+		{"inline fragment", `{ a { ... on A { b { c } } } }`, 3},
+		{"fragment", `{ a { ...F } } fragment F on A { b { c } }`, 3},
+		{"deepest operation", `query A { a } query B { a { b } }`, 2},
+		{"fragment cycle", `{ a { ...F } } fragment F on A { b { ...G } } fragment G on B { c { ...F } }`, 3},
+		{"unknown fragment", `{ a { ...F } }`, 1},
+		{"no operations", `fragment F on A { b { c } }`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaxDepth(asttest.Parse(t, tt.input)); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{"none", `{ a b }`, 0},
+		{"aliases", `{ x: a y: a { z: b } }`, 3},
+		{"fragment spread twice", `{ a { ...F } b { ...F } } fragment F on A { x: c y: c }`, 4},
+		{"fragment cycle", `{ ...F } fragment F on Query { x: a { ...F } }`, 1},
+		{"most aliased operation", `query A { x: a } query B { x: a y: a }`, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Aliases(asttest.Parse(t, tt.input)); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestComplexityScore(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, complexitySchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		weights  Weights
+		expected int
+	}{
+		{"fields", `{ user { id name } }`, Weights{}, 3},
+		{"typename", `{ __typename user { __typename id } }`, Weights{}, 2},
+		{"list size argument", `{ users(first: 10) { id name } }`, Weights{}, 21},
+		{"nested lists", `{ users(first: 10) { friends(first: 5) { id } } }`, Weights{}, 1 + 10*(1+5*1)},
+		{"variable list size", `query($n: Int) { users(first: $n) { id } }`, Weights{DefaultListSize: 20}, 21},
+		{"list without size", `{ users { id } }`, Weights{}, 2},
+		{"custom size argument", `{ user { posts(limit: 3) { id } } }`, Weights{ListSizeArguments: []string{"limit"}}, 5},
+		{"field weights", `{ user { name } users(first: 2) { name } }`, Weights{Fields: map[string]int{"Query.users": 5, "User.name": 3}}, (1 + 3) + (5 + 2*3)},
+		{"default weight", `{ user { id } }`, Weights{Default: 2}, 4},
+		{"abstract type", `{ search { ... on User { name } ... on Post { title author { id } } } }`, Weights{Fields: map[string]int{"Post.author": 10}}, 1 + 1 + 1 + 10 + 1},
+		{"fragments", `{ user { ...F friends { ...F } } } fragment F on User { id name }`, Weights{}, 1 + 2 + 1 + 2},
+		{"fragment cycle", `{ user { ...F } } fragment F on User { friends(first: 2) { ...F } }`, Weights{}, 1 + 1},
+		{"unknown fields", `{ unknown { a b } }`, Weights{}, 3},
+		{"mutation", `mutation { like(id: "1") { id } }`, Weights{Fields: map[string]int{"Mutation.like": 10}}, 11},
+		{"most complex operation", `query A { user { id } } query B { users(first: 3) { id } }`, Weights{}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComplexityScore(asttest.Parse(t, tt.input), s, tt.weights); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLimits_Check(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, complexitySchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := asttest.Parse(t, `query Deep { user { friends { friends { id } } } }
+query Wide { a: user { id } b: user { id } c: user { id } }
+{ users(first: 100) { id } }`)
+
+	tests := []struct {
+		name     string
+		limits   Limits
+		expected string
+	}{
+		{"unlimited", Limits{}, ""},
+		{"within limits", Limits{MaxDepth: 4, MaxComplexity: 101, MaxAliases: 3}, ""},
+		{"depth", Limits{MaxDepth: 3}, `analysis: operation "Deep": depth 4 exceeds limit 3`},
+		{"aliases", Limits{MaxAliases: 2}, `analysis: operation "Wide": aliases 3 exceeds limit 2`},
+		{"complexity", Limits{MaxComplexity: 50}, "analysis: anonymous operation: complexity 101 exceeds limit 50"},
+		{"several", Limits{MaxDepth: 1, MaxAliases: 1}, `analysis: operation "Deep": depth 4 exceeds limit 1
+analysis: operation "Wide": depth 2 exceeds limit 1
+analysis: operation "Wide": aliases 3 exceeds limit 1
+analysis: anonymous operation: depth 2 exceeds limit 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Check(doc, s, Weights{})
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+// Package analysis computes metrics of GraphQL documents.
+package analysis
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+// Statistics of a document. Byte sizes are measured in source spans of
+// nodes, so they are only meaningful for parsed documents.
+type Statistics struct {
+	// Definitions counts definitions by kind: "query", "mutation",
+	// "subscription", "fragment", "schema", "scalar", "type", "interface",
+	// "union", "enum", "input", "directive" and "extend <kind>" for
+	// extensions.
+	Definitions map[string]int
+	// DefinitionBytes is total size of definitions by kind.
+	DefinitionBytes map[string]int
+
+	Selections      int // Fields, fragment spreads and inline fragments.
+	Fields          int // Fields selected in executable definitions.
+	FragmentSpreads int
+	InlineFragments int
+	MaxDepth        int // Maximum nesting of selection sets.
+
+	FieldDefinitions int // Fields of objects, interfaces and input objects.
+	Arguments        int // Arguments of fields and directives.
+	Directives       int // Applied directives.
+	Variables        int // Variable definitions.
+
+	Literals        int // Scalar, enum and null literal values.
+	LiteralBytes    int
+	MaxLiteralBytes int
+
+	Descriptions     int
+	DescriptionBytes int
+
+	// The following are only set by SourceStats.
+	Bytes        int // Size of source.
+	Tokens       int // Lexical tokens except comments.
+	Comments     int
+	CommentBytes int
+	IgnoredBytes int // Whitespace, commas and line terminators.
+}
+
+// Stats returns statistics of doc.
+func Stats(doc *ast.Document) Statistics {
+	s := Statistics{
+		Definitions:     make(map[string]int),
+		DefinitionBytes: make(map[string]int),
+	}
+	for _, def := range doc.Definitions {
+		k := kind(def)
+		s.Definitions[k]++
+		s.DefinitionBytes[k] += def.End() - def.Pos()
+		s.definition(def)
+	}
+	return s
+}
+
+// SourceStats parses input and returns its statistics including lexical
+// ones.
+func SourceStats(input string) (Statistics, error) {
+	p, err := parser.New(lexer.New(input))
+	if err != nil {
+		return Statistics{}, err
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		return Statistics{}, err
+	}
+	s := Stats(doc)
+	s.Bytes = len(input)
+
+	significant := 0
+	l := lexer.New(input)
+	for {
+		tok, err := l.NextToken()
+		if err != nil {
+			return Statistics{}, err
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+		if tok.Type == token.COMMENT {
+			s.Comments++
+			s.CommentBytes += tok.End - tok.Start
+			continue
+		}
+		s.Tokens++
+		significant += tok.End - tok.Start
+	}
+	s.IgnoredBytes = s.Bytes - significant - s.CommentBytes
+	return s, nil
+}
+
+func kind(def ast.Definition) string {
+	switch d := def.(type) {
+	case *ast.OperationDefinition:
+		return string(d.OperationType)
+	case *ast.FragmentDefinition:
+		return "fragment"
+	case *ast.SchemaDefinition:
+		return "schema"
+	case *ast.ScalarTypeDefinition:
+		return "scalar"
+	case *ast.ObjectTypeDefinition:
+		return "type"
+	case *ast.InterfaceTypeDefinition:
+		return "interface"
+	case *ast.UnionTypeDefinition:
+		return "union"
+	case *ast.EnumTypeDefinition:
+		return "enum"
+	case *ast.InputObjectTypeDefinition:
+		return "input"
+	case *ast.DirectiveDefinition:
+		return "directive"
+	case *ast.SchemaExtension:
+		return "extend schema"
+	case *ast.ScalarTypeExtension:
+		return "extend scalar"
+	case *ast.ObjectTypeExtension:
+		return "extend type"
+	case *ast.InterfaceTypeExtension:
+		return "extend interface"
+	case *ast.UnionTypeExtension:
+		return "extend union"
+	case *ast.EnumTypeExtension:
+		return "extend enum"
+	case *ast.InputObjectTypeExtension:
+		return "extend input"
+	}
+	return "unknown"
+}
+
+func (s *Statistics) definition(def ast.Definition) {
+	switch d := def.(type) {
+	case *ast.OperationDefinition:
+		for _, v := range d.VariableDefs {
+			s.Variables++
+			s.value(v.DefaultValue)
+			s.directives(v.Directives)
+		}
+		s.directives(d.Directives)
+		s.selectionSet(d.SelectionSet, 1)
+	case *ast.FragmentDefinition:
+		s.directives(d.Directives)
+		s.selectionSet(d.SelectionSet, 1)
+	case *ast.SchemaDefinition:
+		s.description(d.Description)
+		s.directives(d.Directives)
+	case *ast.SchemaExtension:
+		s.directives(d.Directives)
+	case *ast.ScalarTypeDefinition:
+		s.description(d.Description)
+		s.directives(d.Directives)
+	case *ast.ScalarTypeExtension:
+		s.directives(d.Directives)
+	case *ast.ObjectTypeDefinition:
+		s.description(d.Description)
+		s.directives(d.Directives)
+		s.fieldDefinitions(d.Fields)
+	case *ast.ObjectTypeExtension:
+		s.directives(d.Directives)
+		s.fieldDefinitions(d.Fields)
+	case *ast.InterfaceTypeDefinition:
+		s.description(d.Description)
+		s.directives(d.Directives)
+		s.fieldDefinitions(d.Fields)
+	case *ast.InterfaceTypeExtension:
+		s.directives(d.Directives)
+		s.fieldDefinitions(d.Fields)
+	case *ast.UnionTypeDefinition:
+		s.description(d.Description)
+		s.directives(d.Directives)
+	case *ast.UnionTypeExtension:
+		s.directives(d.Directives)
+	case *ast.EnumTypeDefinition:
+		s.description(d.Description)
+		s.directives(d.Directives)
+		s.enumValues(d.Values)
+	case *ast.EnumTypeExtension:
+		s.directives(d.Directives)
+		s.enumValues(d.Values)
+	case *ast.InputObjectTypeDefinition:
+		s.description(d.Description)
+		s.directives(d.Directives)
+		s.FieldDefinitions += len(d.Fields)
+		s.inputValues(d.Fields)
+	case *ast.InputObjectTypeExtension:
+		s.directives(d.Directives)
+		s.FieldDefinitions += len(d.Fields)
+		s.inputValues(d.Fields)
+	case *ast.DirectiveDefinition:
+		s.description(d.Description)
+		s.inputValues(d.Arguments)
+	}
+}
+
+func (s *Statistics) selectionSet(set *ast.SelectionSet, depth int) {
+	if set == nil {
+		return
+	}
+	s.MaxDepth = max(s.MaxDepth, depth)
+	for _, sel := range set.Selections {
+		s.Selections++
+		switch sel := sel.(type) {
+		case *ast.Field:
+			s.Fields++
+			s.arguments(sel.Arguments)
+			s.directives(sel.Directives)
+			s.selectionSet(sel.SelectionSet, depth+1)
+		case *ast.FragmentSpread:
+			s.FragmentSpreads++
+			s.directives(sel.Directives)
+		case *ast.InlineFragment:
+			s.InlineFragments++
+			s.directives(sel.Directives)
+			s.selectionSet(sel.SelectionSet, depth+1)
+		}
+	}
+}
+
+func (s *Statistics) fieldDefinitions(fields []*ast.FieldDefinition) {
+	for _, f := range fields {
+		s.FieldDefinitions++
+		s.description(f.Description)
+		s.inputValues(f.Arguments)
+		s.directives(f.Directives)
+	}
+}
+
+func (s *Statistics) inputValues(values []*ast.InputValueDefinition) {
+	for _, v := range values {
+		s.description(v.Description)
+		s.value(v.DefaultValue)
+		s.directives(v.Directives)
+	}
+}
+
+func (s *Statistics) enumValues(values []*ast.EnumValueDefinition) {
+	for _, v := range values {
+		s.description(v.Description)
+		s.directives(v.Directives)
+	}
+}
+
+func (s *Statistics) directives(directives []*ast.Directive) {
+	for _, d := range directives {
+		s.Directives++
+		s.arguments(d.Arguments)
+	}
+}
+
+func (s *Statistics) arguments(args []*ast.Argument) {
+	for _, arg := range args {
+		s.Arguments++
+		s.value(arg.Value)
+	}
+}
+
+func (s *Statistics) description(d *ast.Description) {
+	if d == nil {
+		return
+	}
+	s.Descriptions++
+	s.DescriptionBytes += d.End() - d.Pos()
+}
+
+func (s *Statistics) value(v ast.Value) {
+	switch v := v.(type) {
+	case nil, *ast.Variable:
+	case *ast.ListValue:
+		for _, item := range v.Values {
+			s.value(item)
+		}
+	case *ast.ObjectValue:
+		for _, f := range v.Fields {
+			s.value(f.Value)
+		}
+	default:
+		size := v.End() - v.Pos()
+		s.Literals++
+		s.LiteralBytes += size
+		s.MaxLiteralBytes = max(s.MaxLiteralBytes, size)
+	}
+}
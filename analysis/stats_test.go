@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSourceStats_Executable(t *testing.T) {
+	input := `# comment
+query Q($id: ID = "1") {
+  user(id: $id) { name friends(first: 10) { ...F } }
+}
+fragment F on User { ... on User @include(if: true) { name } }`
+	s, err := SourceStats(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(s.Definitions, map[string]int{"query": 1, "fragment": 1}) {
+		t.Errorf("unexpected definitions: %v", s.Definitions)
+	}
+	if s.DefinitionBytes["fragment"] != len(`fragment F on User { ... on User @include(if: true) { name } }`) {
+		t.Errorf("unexpected fragment size: %d", s.DefinitionBytes["fragment"])
+	}
+	expected := [][2]int{
+		{s.Selections, 6},
+		{s.Fields, 4},
+		{s.FragmentSpreads, 1},
+		{s.InlineFragments, 1},
+		{s.MaxDepth, 3},
+		{s.Arguments, 3},
+		{s.Directives, 1},
+		{s.Variables, 1},
+		{s.Literals, 3},
+		{s.LiteralBytes, 9},
+		{s.MaxLiteralBytes, 4},
+		{s.Bytes, len(input)},
+		{s.Tokens, 51},
+		{s.Comments, 1},
+		{s.CommentBytes, 9},
+	}
+	for i, e := range expected {
+		if e[0] != e[1] {
+			t.Errorf("metric %d: expected %d, got %d", i, e[1], e[0])
+		}
+	}
+}
+
+func TestSourceStats_TypeSystem(t *testing.T) {
+	s, err := SourceStats(`"Query root" type Query { a(x: Int = 1): A @deprecated }
+type A { b: Int }
+extend type A { c: Int }
+input I { d: Int }
+enum E { "V" V }
+directive @d(arg: String) on FIELD`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int{"type": 2, "extend type": 1, "input": 1, "enum": 1, "directive": 1}
+	if !reflect.DeepEqual(s.Definitions, expected) {
+		t.Errorf("unexpected definitions: %v", s.Definitions)
+	}
+	if s.FieldDefinitions != 4 || s.Directives != 1 || s.Literals != 1 || s.Descriptions != 2 || s.DescriptionBytes != 15 {
+		t.Errorf("unexpected statistics: %+v", s)
+	}
+	if s.Selections != 0 || s.MaxDepth != 0 {
+		t.Errorf("unexpected selection statistics: %+v", s)
+	}
+}
+
+func TestSourceStats_Invalid(t *testing.T) {
+	if _, err := SourceStats(`{ a`); err == nil {
+		t.Errorf("expected error")
+	}
+}
@@ -3,12 +3,18 @@ package ast
 // Node is base interface for all AST nodes.
 type Node interface {
 	Pos() int // Returns starting position of node.
-	End() int // Returns ending position of node.
+	End() int // Returns position right after the last character of node.
 }
 
 // Document is root node.
 type Document struct {
-	Definitions []Definition
+	Definitions []Definition `json:"definitions,omitempty"`
+}
+
+// Describable is implemented by nodes which can have a description.
+type Describable interface {
+	Node
+	GetDescription() *Description
 }
 
 // Definition can be Executable, TypeSystem, or Extension.
@@ -28,36 +34,72 @@ const (
 	OperationTypeSubscription OperationType = "subscription"
 )
 
+// DirectiveLocation represents location directive can be applied to.
+//
+// https://spec.graphql.org/draft/#DirectiveLocation
+type DirectiveLocation string
+
+const (
+	// ExecutableDirectiveLocation
+	DirectiveLocationQuery              DirectiveLocation = "QUERY"
+	DirectiveLocationMutation           DirectiveLocation = "MUTATION"
+	DirectiveLocationSubscription       DirectiveLocation = "SUBSCRIPTION"
+	DirectiveLocationField              DirectiveLocation = "FIELD"
+	DirectiveLocationFragmentDefinition DirectiveLocation = "FRAGMENT_DEFINITION"
+	DirectiveLocationFragmentSpread     DirectiveLocation = "FRAGMENT_SPREAD"
+	DirectiveLocationInlineFragment     DirectiveLocation = "INLINE_FRAGMENT"
+	DirectiveLocationVariableDefinition DirectiveLocation = "VARIABLE_DEFINITION"
+
+	// TypeSystemDirectiveLocation
+	DirectiveLocationSchema               DirectiveLocation = "SCHEMA"
+	DirectiveLocationScalar               DirectiveLocation = "SCALAR"
+	DirectiveLocationObject               DirectiveLocation = "OBJECT"
+	DirectiveLocationFieldDefinition      DirectiveLocation = "FIELD_DEFINITION"
+	DirectiveLocationArgumentDefinition   DirectiveLocation = "ARGUMENT_DEFINITION"
+	DirectiveLocationInterface            DirectiveLocation = "INTERFACE"
+	DirectiveLocationUnion                DirectiveLocation = "UNION"
+	DirectiveLocationEnum                 DirectiveLocation = "ENUM"
+	DirectiveLocationEnumValue            DirectiveLocation = "ENUM_VALUE"
+	DirectiveLocationInputObject          DirectiveLocation = "INPUT_OBJECT"
+	DirectiveLocationInputFieldDefinition DirectiveLocation = "INPUT_FIELD_DEFINITION"
+)
+
 // OperationDefinition
 //
 // https://spec.graphql.org/draft/#OperationDefinition
 type OperationDefinition struct {
-	Position      int
-	OperationType OperationType
-	Name          *Name
-	VariableDefs  []*VariableDefinition
-	Directives    []*Directive
-	SelectionSet  *SelectionSet
+	Position      int                   `json:"pos"`
+	EndPosition   int                   `json:"end"`
+	Description   *Description          `json:"description,omitempty"`
+	OperationType OperationType         `json:"operationType"`
+	Name          *Name                 `json:"name,omitempty"`
+	VariableDefs  []*VariableDefinition `json:"variableDefs,omitempty"`
+	Directives    []*Directive          `json:"directives,omitempty"`
+	SelectionSet  *SelectionSet         `json:"selectionSet,omitempty"`
 }
 
-func (o *OperationDefinition) Pos() int        { return o.Position }
-func (o *OperationDefinition) End() int        { return -1 }
-func (o *OperationDefinition) definitionNode() {}
+func (o *OperationDefinition) Pos() int                     { return o.Position }
+func (o *OperationDefinition) End() int                     { return o.EndPosition }
+func (o *OperationDefinition) GetDescription() *Description { return o.Description }
+func (o *OperationDefinition) definitionNode()              {}
 
 // FragmentDefinition
 //
 // https://spec.graphql.org/draft/#FragmentDefinition
 type FragmentDefinition struct {
-	Position      int
-	Name          *Name
-	TypeCondition *NamedType
-	Directives    []*Directive
-	SelectionSet  *SelectionSet
+	Position      int           `json:"pos"`
+	EndPosition   int           `json:"end"`
+	Description   *Description  `json:"description,omitempty"`
+	Name          *Name         `json:"name,omitempty"`
+	TypeCondition *NamedType    `json:"typeCondition,omitempty"`
+	Directives    []*Directive  `json:"directives,omitempty"`
+	SelectionSet  *SelectionSet `json:"selectionSet,omitempty"`
 }
 
-func (f *FragmentDefinition) Pos() int        { return f.Position }
-func (f *FragmentDefinition) End() int        { return -1 }
-func (f *FragmentDefinition) definitionNode() {}
+func (f *FragmentDefinition) Pos() int                     { return f.Position }
+func (f *FragmentDefinition) End() int                     { return f.EndPosition }
+func (f *FragmentDefinition) GetDescription() *Description { return f.Description }
+func (f *FragmentDefinition) definitionNode()              {}
 
 // TypeDefinition covers schema, scalar, object, interface, union, enum, input.
 //
@@ -71,40 +113,44 @@ type TypeDefinition interface {
 //
 // https://spec.graphql.org/draft/#SchemaDefinition
 type SchemaDefinition struct {
-	Position          int
-	Description       *StringValue
-	Directives        []*Directive
-	RootOperationDefs []*RootOperationTypeDefinition
+	Position          int                            `json:"pos"`
+	EndPosition       int                            `json:"end"`
+	Description       *Description                   `json:"description,omitempty"`
+	Directives        []*Directive                   `json:"directives,omitempty"`
+	RootOperationDefs []*RootOperationTypeDefinition `json:"rootOperationDefs,omitempty"`
 }
 
-func (s *SchemaDefinition) Pos() int                  { return s.Position }
-func (s *SchemaDefinition) End() int                  { return -1 }
-func (s *SchemaDefinition) definitionNode()           {}
-func (s *SchemaDefinition) typeSystemDefinitionNode() {}
+func (s *SchemaDefinition) Pos() int                     { return s.Position }
+func (s *SchemaDefinition) End() int                     { return s.EndPosition }
+func (s *SchemaDefinition) GetDescription() *Description { return s.Description }
+func (s *SchemaDefinition) definitionNode()              {}
+func (s *SchemaDefinition) typeSystemDefinitionNode()    {}
 
 // RootOperationTypeDefinition
 //
 // https://spec.graphql.org/draft/#RootOperationTypeDefinition
 type RootOperationTypeDefinition struct {
-	Position      int
-	OperationType OperationType
-	Type          *NamedType
+	Position      int           `json:"pos"`
+	EndPosition   int           `json:"end"`
+	OperationType OperationType `json:"operationType"`
+	Type          *NamedType    `json:"type,omitempty"`
 }
 
 func (r *RootOperationTypeDefinition) Pos() int { return r.Position }
-func (r *RootOperationTypeDefinition) End() int { return -1 }
+func (r *RootOperationTypeDefinition) End() int { return r.EndPosition }
 
 // ScalarTypeExtension
 //
 // https://spec.graphql.org/draft/#ScalarTypeExtension
 type ScalarTypeExtension struct {
-	Position   int
-	Name       *Name
-	Directives []*Directive
+	Position    int          `json:"pos"`
+	EndPosition int          `json:"end"`
+	Name        *Name        `json:"name,omitempty"`
+	Directives  []*Directive `json:"directives,omitempty"`
 }
 
 func (s *ScalarTypeExtension) Pos() int                 { return s.Position }
-func (s *ScalarTypeExtension) End() int                 { return -1 }
+func (s *ScalarTypeExtension) End() int                 { return s.EndPosition }
 func (s *ScalarTypeExtension) definitionNode()          {}
 func (s *ScalarTypeExtension) typeSystemExtensionNode() {}
 
@@ -112,15 +158,16 @@ func (s *ScalarTypeExtension) typeSystemExtensionNode() {}
 //
 // https://spec.graphql.org/draft/#ObjectTypeExtension
 type ObjectTypeExtension struct {
-	Position   int
-	Name       *Name
-	Interfaces []*NamedType
-	Directives []*Directive
-	Fields     []*FieldDefinition
+	Position    int                `json:"pos"`
+	EndPosition int                `json:"end"`
+	Name        *Name              `json:"name,omitempty"`
+	Interfaces  []*NamedType       `json:"interfaces,omitempty"`
+	Directives  []*Directive       `json:"directives,omitempty"`
+	Fields      []*FieldDefinition `json:"fields,omitempty"`
 }
 
 func (o *ObjectTypeExtension) Pos() int                 { return o.Position }
-func (o *ObjectTypeExtension) End() int                 { return -1 }
+func (o *ObjectTypeExtension) End() int                 { return o.EndPosition }
 func (o *ObjectTypeExtension) definitionNode()          {}
 func (o *ObjectTypeExtension) typeSystemExtensionNode() {}
 
@@ -128,15 +175,16 @@ func (o *ObjectTypeExtension) typeSystemExtensionNode() {}
 //
 // https://spec.graphql.org/draft/#InterfaceTypeExtension
 type InterfaceTypeExtension struct {
-	Position   int
-	Name       *Name
-	Interfaces []*NamedType
-	Directives []*Directive
-	Fields     []*FieldDefinition
+	Position    int                `json:"pos"`
+	EndPosition int                `json:"end"`
+	Name        *Name              `json:"name,omitempty"`
+	Interfaces  []*NamedType       `json:"interfaces,omitempty"`
+	Directives  []*Directive       `json:"directives,omitempty"`
+	Fields      []*FieldDefinition `json:"fields,omitempty"`
 }
 
 func (i *InterfaceTypeExtension) Pos() int                 { return i.Position }
-func (i *InterfaceTypeExtension) End() int                 { return -1 }
+func (i *InterfaceTypeExtension) End() int                 { return i.EndPosition }
 func (i *InterfaceTypeExtension) definitionNode()          {}
 func (i *InterfaceTypeExtension) typeSystemExtensionNode() {}
 
@@ -144,14 +192,15 @@ func (i *InterfaceTypeExtension) typeSystemExtensionNode() {}
 //
 // https://spec.graphql.org/draft/#UnionTypeExtension
 type UnionTypeExtension struct {
-	Position   int
-	Name       *Name
-	Directives []*Directive
-	Types      []*NamedType
+	Position    int          `json:"pos"`
+	EndPosition int          `json:"end"`
+	Name        *Name        `json:"name,omitempty"`
+	Directives  []*Directive `json:"directives,omitempty"`
+	Types       []*NamedType `json:"types,omitempty"`
 }
 
 func (u *UnionTypeExtension) Pos() int                 { return u.Position }
-func (u *UnionTypeExtension) End() int                 { return -1 }
+func (u *UnionTypeExtension) End() int                 { return u.EndPosition }
 func (u *UnionTypeExtension) definitionNode()          {}
 func (u *UnionTypeExtension) typeSystemExtensionNode() {}
 
@@ -159,14 +208,15 @@ func (u *UnionTypeExtension) typeSystemExtensionNode() {}
 //
 // https://spec.graphql.org/draft/#EnumTypeExtension
 type EnumTypeExtension struct {
-	Position   int
-	Name       *Name
-	Directives []*Directive
-	Values     []*EnumValueDefinition
+	Position    int                    `json:"pos"`
+	EndPosition int                    `json:"end"`
+	Name        *Name                  `json:"name,omitempty"`
+	Directives  []*Directive           `json:"directives,omitempty"`
+	Values      []*EnumValueDefinition `json:"values,omitempty"`
 }
 
 func (e *EnumTypeExtension) Pos() int                 { return e.Position }
-func (e *EnumTypeExtension) End() int                 { return -1 }
+func (e *EnumTypeExtension) End() int                 { return e.EndPosition }
 func (e *EnumTypeExtension) definitionNode()          {}
 func (e *EnumTypeExtension) typeSystemExtensionNode() {}
 
@@ -174,14 +224,15 @@ func (e *EnumTypeExtension) typeSystemExtensionNode() {}
 //
 // https://spec.graphql.org/draft/#InputObjectTypeExtension
 type InputObjectTypeExtension struct {
-	Position   int
-	Name       *Name
-	Directives []*Directive
-	Fields     []*InputValueDefinition
+	Position    int                     `json:"pos"`
+	EndPosition int                     `json:"end"`
+	Name        *Name                   `json:"name,omitempty"`
+	Directives  []*Directive            `json:"directives,omitempty"`
+	Fields      []*InputValueDefinition `json:"fields,omitempty"`
 }
 
 func (i *InputObjectTypeExtension) Pos() int                 { return i.Position }
-func (i *InputObjectTypeExtension) End() int                 { return -1 }
+func (i *InputObjectTypeExtension) End() int                 { return i.EndPosition }
 func (i *InputObjectTypeExtension) definitionNode()          {}
 func (i *InputObjectTypeExtension) typeSystemExtensionNode() {}
 
@@ -189,126 +240,142 @@ func (i *InputObjectTypeExtension) typeSystemExtensionNode() {}
 //
 // https://spec.graphql.org/draft/#FieldDefinition
 type FieldDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Arguments   []*InputValueDefinition
-	Type        Type
-	Directives  []*Directive
+	Position    int                     `json:"pos"`
+	EndPosition int                     `json:"end"`
+	Description *Description            `json:"description,omitempty"`
+	Name        *Name                   `json:"name,omitempty"`
+	Arguments   []*InputValueDefinition `json:"arguments,omitempty"`
+	Type        Type                    `json:"type,omitempty"`
+	Directives  []*Directive            `json:"directives,omitempty"`
 }
 
-func (f *FieldDefinition) Pos() int { return f.Position }
-func (f *FieldDefinition) End() int { return -1 }
+func (f *FieldDefinition) Pos() int                     { return f.Position }
+func (f *FieldDefinition) End() int                     { return f.EndPosition }
+func (f *FieldDefinition) GetDescription() *Description { return f.Description }
 
 // InterfaceTypeDefinition
 //
 // https://spec.graphql.org/draft/#InterfaceTypeDefinition
 type InterfaceTypeDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Interfaces  []*NamedType
-	Directives  []*Directive
-	Fields      []*FieldDefinition
+	Position    int                `json:"pos"`
+	EndPosition int                `json:"end"`
+	Description *Description       `json:"description,omitempty"`
+	Name        *Name              `json:"name,omitempty"`
+	Interfaces  []*NamedType       `json:"interfaces,omitempty"`
+	Directives  []*Directive       `json:"directives,omitempty"`
+	Fields      []*FieldDefinition `json:"fields,omitempty"`
 }
 
-func (i *InterfaceTypeDefinition) Pos() int                  { return i.Position }
-func (i *InterfaceTypeDefinition) End() int                  { return -1 }
-func (i *InterfaceTypeDefinition) definitionNode()           {}
-func (i *InterfaceTypeDefinition) typeSystemDefinitionNode() {}
+func (i *InterfaceTypeDefinition) Pos() int                     { return i.Position }
+func (i *InterfaceTypeDefinition) End() int                     { return i.EndPosition }
+func (i *InterfaceTypeDefinition) GetDescription() *Description { return i.Description }
+func (i *InterfaceTypeDefinition) definitionNode()              {}
+func (i *InterfaceTypeDefinition) typeSystemDefinitionNode()    {}
 
 // UnionTypeDefinition
 //
 // https://spec.graphql.org/draft/#UnionTypeDefinition
 type UnionTypeDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Directives  []*Directive
-	Types       []*NamedType
+	Position    int          `json:"pos"`
+	EndPosition int          `json:"end"`
+	Description *Description `json:"description,omitempty"`
+	Name        *Name        `json:"name,omitempty"`
+	Directives  []*Directive `json:"directives,omitempty"`
+	Types       []*NamedType `json:"types,omitempty"`
 }
 
-func (u *UnionTypeDefinition) Pos() int                  { return u.Position }
-func (u *UnionTypeDefinition) End() int                  { return -1 }
-func (u *UnionTypeDefinition) definitionNode()           {}
-func (u *UnionTypeDefinition) typeSystemDefinitionNode() {}
+func (u *UnionTypeDefinition) Pos() int                     { return u.Position }
+func (u *UnionTypeDefinition) End() int                     { return u.EndPosition }
+func (u *UnionTypeDefinition) GetDescription() *Description { return u.Description }
+func (u *UnionTypeDefinition) definitionNode()              {}
+func (u *UnionTypeDefinition) typeSystemDefinitionNode()    {}
 
 // EnumTypeDefinition
 //
 // https://spec.graphql.org/draft/#EnumTypeDefinition
 type EnumTypeDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Directives  []*Directive
-	Values      []*EnumValueDefinition
+	Position    int                    `json:"pos"`
+	EndPosition int                    `json:"end"`
+	Description *Description           `json:"description,omitempty"`
+	Name        *Name                  `json:"name,omitempty"`
+	Directives  []*Directive           `json:"directives,omitempty"`
+	Values      []*EnumValueDefinition `json:"values,omitempty"`
 }
 
-func (e *EnumTypeDefinition) Pos() int                  { return e.Position }
-func (e *EnumTypeDefinition) End() int                  { return -1 }
-func (e *EnumTypeDefinition) definitionNode()           {}
-func (e *EnumTypeDefinition) typeSystemDefinitionNode() {}
+func (e *EnumTypeDefinition) Pos() int                     { return e.Position }
+func (e *EnumTypeDefinition) End() int                     { return e.EndPosition }
+func (e *EnumTypeDefinition) GetDescription() *Description { return e.Description }
+func (e *EnumTypeDefinition) definitionNode()              {}
+func (e *EnumTypeDefinition) typeSystemDefinitionNode()    {}
 
 // EnumValueDefinition
 //
 // https://spec.graphql.org/draft/#EnumValueDefinition
 type EnumValueDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Directives  []*Directive
+	Position    int          `json:"pos"`
+	EndPosition int          `json:"end"`
+	Description *Description `json:"description,omitempty"`
+	Name        *Name        `json:"name,omitempty"`
+	Directives  []*Directive `json:"directives,omitempty"`
 }
 
-func (e *EnumValueDefinition) Pos() int { return e.Position }
-func (e *EnumValueDefinition) End() int { return -1 }
+func (e *EnumValueDefinition) Pos() int                     { return e.Position }
+func (e *EnumValueDefinition) End() int                     { return e.EndPosition }
+func (e *EnumValueDefinition) GetDescription() *Description { return e.Description }
 
 // InputObjectTypeDefinition
 //
 // https://spec.graphql.org/draft/#InputObjectTypeDefinition
 type InputObjectTypeDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Directives  []*Directive
-	Fields      []*InputValueDefinition
+	Position    int                     `json:"pos"`
+	EndPosition int                     `json:"end"`
+	Description *Description            `json:"description,omitempty"`
+	Name        *Name                   `json:"name,omitempty"`
+	Directives  []*Directive            `json:"directives,omitempty"`
+	Fields      []*InputValueDefinition `json:"fields,omitempty"`
 }
 
-func (i *InputObjectTypeDefinition) Pos() int                  { return i.Position }
-func (i *InputObjectTypeDefinition) End() int                  { return -1 }
-func (i *InputObjectTypeDefinition) definitionNode()           {}
-func (i *InputObjectTypeDefinition) typeSystemDefinitionNode() {}
+func (i *InputObjectTypeDefinition) Pos() int                     { return i.Position }
+func (i *InputObjectTypeDefinition) End() int                     { return i.EndPosition }
+func (i *InputObjectTypeDefinition) GetDescription() *Description { return i.Description }
+func (i *InputObjectTypeDefinition) definitionNode()              {}
+func (i *InputObjectTypeDefinition) typeSystemDefinitionNode()    {}
 
 // InputValueDefinition
 //
 // https://spec.graphql.org/draft/#InputValueDefinition
 type InputValueDefinition struct {
-	Position     int
-	Description  *StringValue
-	Name         *Name
-	Type         Type
-	DefaultValue Value
-	Directives   []*Directive
+	Position     int          `json:"pos"`
+	EndPosition  int          `json:"end"`
+	Description  *Description `json:"description,omitempty"`
+	Name         *Name        `json:"name,omitempty"`
+	Type         Type         `json:"type,omitempty"`
+	DefaultValue Value        `json:"defaultValue,omitempty"`
+	Directives   []*Directive `json:"directives,omitempty"`
 }
 
-func (i *InputValueDefinition) Pos() int { return i.Position }
-func (i *InputValueDefinition) End() int { return -1 }
+func (i *InputValueDefinition) Pos() int                     { return i.Position }
+func (i *InputValueDefinition) End() int                     { return i.EndPosition }
+func (i *InputValueDefinition) GetDescription() *Description { return i.Description }
 
 // DirectiveDefinition
 //
 // https://spec.graphql.org/draft/#DirectiveDefinition
 type DirectiveDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Arguments   []*InputValueDefinition
-	Repeatable  bool
-	Locations   []*Name
+	Position    int                     `json:"pos"`
+	EndPosition int                     `json:"end"`
+	Description *Description            `json:"description,omitempty"`
+	Name        *Name                   `json:"name,omitempty"`
+	Arguments   []*InputValueDefinition `json:"arguments,omitempty"`
+	Repeatable  bool                    `json:"repeatable,omitempty"`
+	Locations   []*Name                 `json:"locations,omitempty"`
 }
 
-func (d *DirectiveDefinition) Pos() int                  { return d.Position }
-func (d *DirectiveDefinition) End() int                  { return -1 }
-func (d *DirectiveDefinition) definitionNode()           {}
-func (d *DirectiveDefinition) typeSystemDefinitionNode() {}
+func (d *DirectiveDefinition) Pos() int                     { return d.Position }
+func (d *DirectiveDefinition) End() int                     { return d.EndPosition }
+func (d *DirectiveDefinition) GetDescription() *Description { return d.Description }
+func (d *DirectiveDefinition) definitionNode()              {}
+func (d *DirectiveDefinition) typeSystemDefinitionNode()    {}
 
 // TypeSystemExtension
 //
@@ -322,13 +389,14 @@ type TypeSystemExtension interface {
 //
 // https://spec.graphql.org/draft/#SchemaExtension
 type SchemaExtension struct {
-	Position          int
-	Directives        []*Directive
-	RootOperationDefs []*RootOperationTypeDefinition
+	Position          int                            `json:"pos"`
+	EndPosition       int                            `json:"end"`
+	Directives        []*Directive                   `json:"directives,omitempty"`
+	RootOperationDefs []*RootOperationTypeDefinition `json:"rootOperationDefs,omitempty"`
 }
 
 func (s *SchemaExtension) Pos() int                 { return s.Position }
-func (s *SchemaExtension) End() int                 { return -1 }
+func (s *SchemaExtension) End() int                 { return s.EndPosition }
 func (s *SchemaExtension) definitionNode()          {}
 func (s *SchemaExtension) typeSystemExtensionNode() {}
 
@@ -336,44 +404,49 @@ func (s *SchemaExtension) typeSystemExtensionNode() {}
 //
 // https://spec.graphql.org/draft/#ScalarTypeDefinition
 type ScalarTypeDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Directives  []*Directive
+	Position    int          `json:"pos"`
+	EndPosition int          `json:"end"`
+	Description *Description `json:"description,omitempty"`
+	Name        *Name        `json:"name,omitempty"`
+	Directives  []*Directive `json:"directives,omitempty"`
 }
 
-func (s *ScalarTypeDefinition) Pos() int                  { return s.Position }
-func (s *ScalarTypeDefinition) End() int                  { return -1 }
-func (s *ScalarTypeDefinition) definitionNode()           {}
-func (s *ScalarTypeDefinition) typeSystemDefinitionNode() {}
+func (s *ScalarTypeDefinition) Pos() int                     { return s.Position }
+func (s *ScalarTypeDefinition) End() int                     { return s.EndPosition }
+func (s *ScalarTypeDefinition) GetDescription() *Description { return s.Description }
+func (s *ScalarTypeDefinition) definitionNode()              {}
+func (s *ScalarTypeDefinition) typeSystemDefinitionNode()    {}
 
 // ObjectTypeDefinition
 //
 // https://spec.graphql.org/draft/#ObjectTypeDefinition
 type ObjectTypeDefinition struct {
-	Position    int
-	Description *StringValue
-	Name        *Name
-	Interfaces  []*NamedType
-	Directives  []*Directive
-	Fields      []*FieldDefinition
+	Position    int                `json:"pos"`
+	EndPosition int                `json:"end"`
+	Description *Description       `json:"description,omitempty"`
+	Name        *Name              `json:"name,omitempty"`
+	Interfaces  []*NamedType       `json:"interfaces,omitempty"`
+	Directives  []*Directive       `json:"directives,omitempty"`
+	Fields      []*FieldDefinition `json:"fields,omitempty"`
 }
 
-func (o *ObjectTypeDefinition) Pos() int                  { return o.Position }
-func (o *ObjectTypeDefinition) End() int                  { return -1 }
-func (o *ObjectTypeDefinition) definitionNode()           {}
-func (o *ObjectTypeDefinition) typeSystemDefinitionNode() {}
+func (o *ObjectTypeDefinition) Pos() int                     { return o.Position }
+func (o *ObjectTypeDefinition) End() int                     { return o.EndPosition }
+func (o *ObjectTypeDefinition) GetDescription() *Description { return o.Description }
+func (o *ObjectTypeDefinition) definitionNode()              {}
+func (o *ObjectTypeDefinition) typeSystemDefinitionNode()    {}
 
 // SelectionSet
 //
 // https://spec.graphql.org/draft/#SelectionSet
 type SelectionSet struct {
-	Position   int
-	Selections []Selection
+	Position    int         `json:"pos"`
+	EndPosition int         `json:"end"`
+	Selections  []Selection `json:"selections,omitempty"`
 }
 
 func (s *SelectionSet) Pos() int { return s.Position }
-func (s *SelectionSet) End() int { return -1 }
+func (s *SelectionSet) End() int { return s.EndPosition }
 
 // Selection can be Field, FragmentSpread, InlineFragment
 //
@@ -387,52 +460,56 @@ type Selection interface {
 //
 // https://spec.graphql.org/draft/#Field
 type Field struct {
-	Position     int
-	Alias        *Name
-	Name         *Name
-	Arguments    []*Argument
-	Directives   []*Directive
-	SelectionSet *SelectionSet
+	Position     int           `json:"pos"`
+	EndPosition  int           `json:"end"`
+	Alias        *Name         `json:"alias,omitempty"`
+	Name         *Name         `json:"name,omitempty"`
+	Arguments    []*Argument   `json:"arguments,omitempty"`
+	Directives   []*Directive  `json:"directives,omitempty"`
+	SelectionSet *SelectionSet `json:"selectionSet,omitempty"`
 }
 
 func (f *Field) Pos() int       { return f.Position }
-func (f *Field) End() int       { return -1 }
+func (f *Field) End() int       { return f.EndPosition }
 func (f *Field) selectionNode() {}
 
 // FragmentSpread
 //
 // https://spec.graphql.org/draft/#FragmentSpread
 type FragmentSpread struct {
-	Position   int
-	Name       *Name
-	Directives []*Directive
+	Position    int          `json:"pos"`
+	EndPosition int          `json:"end"`
+	Name        *Name        `json:"name,omitempty"`
+	Directives  []*Directive `json:"directives,omitempty"`
 }
 
 func (fs *FragmentSpread) Pos() int       { return fs.Position }
-func (fs *FragmentSpread) End() int       { return -1 }
+func (fs *FragmentSpread) End() int       { return fs.EndPosition }
 func (fs *FragmentSpread) selectionNode() {}
 
 // InlineFragment
 //
 // https://spec.graphql.org/draft/#InlineFragment
 type InlineFragment struct {
-	Position      int
-	TypeCondition *NamedType
-	Directives    []*Directive
-	SelectionSet  *SelectionSet
+	Position      int           `json:"pos"`
+	EndPosition   int           `json:"end"`
+	TypeCondition *NamedType    `json:"typeCondition,omitempty"`
+	Directives    []*Directive  `json:"directives,omitempty"`
+	SelectionSet  *SelectionSet `json:"selectionSet,omitempty"`
 }
 
 func (inf *InlineFragment) Pos() int       { return inf.Position }
-func (inf *InlineFragment) End() int       { return -1 }
+func (inf *InlineFragment) End() int       { return inf.EndPosition }
 func (inf *InlineFragment) selectionNode() {}
 
 // Directive
 //
 // https://spec.graphql.org/draft/#Directive
 type Directive struct {
-	Position  int
-	Name      *Name
-	Arguments []*Argument
+	Position    int         `json:"pos"`
+	EndPosition int         `json:"end"`
+	Name        *Name       `json:"name,omitempty"`
+	Arguments   []*Argument `json:"arguments,omitempty"`
 }
 
 //// Directives
@@ -441,19 +518,20 @@ type Directive struct {
 //type Directives = []Directive
 
 func (d *Directive) Pos() int { return d.Position }
-func (d *Directive) End() int { return -1 }
+func (d *Directive) End() int { return d.EndPosition }
 
 // Argument
 //
 // https://spec.graphql.org/draft/#Argument
 type Argument struct {
-	Position int
-	Name     *Name
-	Value    Value
+	Position    int   `json:"pos"`
+	EndPosition int   `json:"end"`
+	Name        *Name `json:"name,omitempty"`
+	Value       Value `json:"value,omitempty"`
 }
 
 func (a *Argument) Pos() int { return a.Position }
-func (a *Argument) End() int { return -1 }
+func (a *Argument) End() int { return a.EndPosition }
 
 // Value can be IntValue, FloatValue, StringValue, BooleanValue,
 // NullValue, EnumValue, ListValue, ObjectValue, Variable.
@@ -468,135 +546,171 @@ type Value interface {
 //
 // https://spec.graphql.org/draft/#IntValue
 type IntValue struct {
-	Position int
-	Value    string
+	Position    int    `json:"pos"`
+	EndPosition int    `json:"end"`
+	Value       string `json:"value"`
 }
 
 func (v *IntValue) Pos() int   { return v.Position }
-func (v *IntValue) End() int   { return -1 }
+func (v *IntValue) End() int   { return v.EndPosition }
 func (v *IntValue) valueNode() {}
 
 // FloatValue
 //
 // https://spec.graphql.org/draft/#FloatValue
 type FloatValue struct {
-	Position int
-	Value    string
+	Position    int    `json:"pos"`
+	EndPosition int    `json:"end"`
+	Value       string `json:"value"`
 }
 
 func (v *FloatValue) Pos() int   { return v.Position }
-func (v *FloatValue) End() int   { return -1 }
+func (v *FloatValue) End() int   { return v.EndPosition }
 func (v *FloatValue) valueNode() {}
 
 // StringValue
 //
 // https://spec.graphql.org/draft/#StringValue
 type StringValue struct {
-	Position int
-	Value    string
-	Block    bool
+	Position    int    `json:"pos"`
+	EndPosition int    `json:"end"`
+	Value       string `json:"value"`
+	Block       bool   `json:"block,omitempty"`
 }
 
 func (v *StringValue) Pos() int   { return v.Position }
-func (v *StringValue) End() int   { return -1 }
+func (v *StringValue) End() int   { return v.EndPosition }
 func (v *StringValue) valueNode() {}
 
+// Description
+//
+// https://spec.graphql.org/draft/#Description
+type Description struct {
+	Position    int    `json:"pos"`
+	EndPosition int    `json:"end"`
+	Value       string `json:"value"`
+	Block       bool   `json:"block,omitempty"`
+}
+
+func (d *Description) Pos() int { return d.Position }
+func (d *Description) End() int { return d.EndPosition }
+
+// StringValue returns description as string value node.
+func (d *Description) StringValue() *StringValue {
+	return &StringValue{
+		Position:    d.Position,
+		EndPosition: d.EndPosition,
+		Value:       d.Value,
+		Block:       d.Block,
+	}
+}
+
 // BooleanValue
 //
 // https://spec.graphql.org/draft/#BooleanValue
 type BooleanValue struct {
-	Position int
-	Value    bool
+	Position    int  `json:"pos"`
+	EndPosition int  `json:"end"`
+	Value       bool `json:"value"`
 }
 
 func (v *BooleanValue) Pos() int   { return v.Position }
-func (v *BooleanValue) End() int   { return -1 }
+func (v *BooleanValue) End() int   { return v.EndPosition }
 func (v *BooleanValue) valueNode() {}
 
 // NullValue
 //
 // https://spec.graphql.org/draft/#NullValue
 type NullValue struct {
-	Position int
+	Position    int `json:"pos"`
+	EndPosition int `json:"end"`
 }
 
 func (v *NullValue) Pos() int   { return v.Position }
-func (v *NullValue) End() int   { return -1 }
+func (v *NullValue) End() int   { return v.EndPosition }
 func (v *NullValue) valueNode() {}
 
 // EnumValue
 //
 // https://spec.graphql.org/draft/#EnumValue
 type EnumValue struct {
-	Position int
-	Value    string
+	Position    int    `json:"pos"`
+	EndPosition int    `json:"end"`
+	Value       string `json:"value"`
 }
 
 func (v *EnumValue) Pos() int   { return v.Position }
-func (v *EnumValue) End() int   { return -1 }
+func (v *EnumValue) End() int   { return v.EndPosition }
 func (v *EnumValue) valueNode() {}
 
 // ListValue
 //
 // https://spec.graphql.org/draft/#ListValue
 type ListValue struct {
-	Position int
-	Values   []Value
+	Position    int     `json:"pos"`
+	EndPosition int     `json:"end"`
+	Values      []Value `json:"values,omitempty"`
 }
 
 func (v *ListValue) Pos() int   { return v.Position }
-func (v *ListValue) End() int   { return -1 }
+func (v *ListValue) End() int   { return v.EndPosition }
 func (v *ListValue) valueNode() {}
 
 // ObjectValue
 //
 // https://spec.graphql.org/draft/#ObjectValue
 type ObjectValue struct {
-	Position int
-	Fields   []*ObjectField
+	Position    int            `json:"pos"`
+	EndPosition int            `json:"end"`
+	Fields      []*ObjectField `json:"fields,omitempty"`
 }
 
 func (v *ObjectValue) Pos() int   { return v.Position }
-func (v *ObjectValue) End() int   { return -1 }
+func (v *ObjectValue) End() int   { return v.EndPosition }
 func (v *ObjectValue) valueNode() {}
 
 // ObjectField
 //
 // https://spec.graphql.org/draft/#ObjectField
 type ObjectField struct {
-	Position int
-	Name     *Name
-	Value    Value
+	Position    int   `json:"pos"`
+	EndPosition int   `json:"end"`
+	Name        *Name `json:"name,omitempty"`
+	Value       Value `json:"value,omitempty"`
 }
 
 func (o *ObjectField) Pos() int { return o.Position }
-func (o *ObjectField) End() int { return -1 }
+func (o *ObjectField) End() int { return o.EndPosition }
 
 // Variable
 //
 // https://spec.graphql.org/draft/#Variable
 type Variable struct {
-	Position int
-	Name     *Name
+	Position    int   `json:"pos"`
+	EndPosition int   `json:"end"`
+	Name        *Name `json:"name,omitempty"`
 }
 
 func (v *Variable) Pos() int   { return v.Position }
-func (v *Variable) End() int   { return -1 }
+func (v *Variable) End() int   { return v.EndPosition }
 func (v *Variable) valueNode() {}
 
 // VariableDefinition
 //
 // https://spec.graphql.org/draft/#VariableDefinition
 type VariableDefinition struct {
-	Position     int
-	Variable     *Variable
-	Type         Type
-	DefaultValue Value
-	Directives   []*Directive
+	Position     int          `json:"pos"`
+	EndPosition  int          `json:"end"`
+	Description  *Description `json:"description,omitempty"`
+	Variable     *Variable    `json:"variable,omitempty"`
+	Type         Type         `json:"type,omitempty"`
+	DefaultValue Value        `json:"defaultValue,omitempty"`
+	Directives   []*Directive `json:"directives,omitempty"`
 }
 
-func (vd *VariableDefinition) Pos() int { return vd.Position }
-func (vd *VariableDefinition) End() int { return -1 }
+func (vd *VariableDefinition) Pos() int                     { return vd.Position }
+func (vd *VariableDefinition) End() int                     { return vd.EndPosition }
+func (vd *VariableDefinition) GetDescription() *Description { return vd.Description }
 
 // Type can be NamedType, ListType, NonNullType.
 //
@@ -610,45 +724,60 @@ type Type interface {
 //
 // https://spec.graphql.org/draft/#NamedType
 type NamedType struct {
-	Position int
-	Name     *Name
+	Position    int   `json:"pos"`
+	EndPosition int   `json:"end"`
+	Name        *Name `json:"name,omitempty"`
 }
 
 func (n *NamedType) Pos() int  { return n.Position }
-func (n *NamedType) End() int  { return -1 }
+func (n *NamedType) End() int  { return n.EndPosition }
 func (n *NamedType) typeNode() {}
 
 // ListType
 //
 // https://spec.graphql.org/draft/#ListType
 type ListType struct {
-	Position int
-	Type     Type
+	Position    int  `json:"pos"`
+	EndPosition int  `json:"end"`
+	Type        Type `json:"type,omitempty"`
 }
 
 func (l *ListType) Pos() int  { return l.Position }
-func (l *ListType) End() int  { return -1 }
+func (l *ListType) End() int  { return l.EndPosition }
 func (l *ListType) typeNode() {}
 
 // NonNullType
 //
 // https://spec.graphql.org/draft/#NonNullType
 type NonNullType struct {
-	Position int
-	Type     Type
+	Position    int  `json:"pos"`
+	EndPosition int  `json:"end"`
+	Type        Type `json:"type,omitempty"`
 }
 
 func (n *NonNullType) Pos() int  { return n.Position }
-func (n *NonNullType) End() int  { return -1 }
+func (n *NonNullType) End() int  { return n.EndPosition }
 func (n *NonNullType) typeNode() {}
 
 // Name
 //
 // https://spec.graphql.org/draft/#Name
 type Name struct {
-	Position int
-	Value    string
+	Position    int    `json:"pos"`
+	EndPosition int    `json:"end"`
+	Value       string `json:"value"`
 }
 
 func (n *Name) Pos() int { return n.Position }
-func (n *Name) End() int { return -1 }
+func (n *Name) End() int { return n.EndPosition }
+
+// BadNode is a placeholder of source that could not be parsed, produced
+// by parser in error recovery mode in place of broken definitions.
+type BadNode struct {
+	Position    int `json:"pos"`
+	EndPosition int `json:"end"`
+}
+
+func (b *BadNode) Pos() int        { return b.Position }
+func (b *BadNode) End() int        { return b.EndPosition }
+func (b *BadNode) definitionNode() {}
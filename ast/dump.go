@@ -0,0 +1,105 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Dump returns n as indented tree for debugging, like go/ast's Print.
+// Every node is labeled with its type and source span [Pos:End]; nil and
+// empty fields are omitted. Output is deterministic, so it may be used in
+// tests and issue reports:
+//
+//	Field [2:13] {
+//	  Name: Name [2:6] {
+//	    Value: "user"
+//	  }
+//	  SelectionSet: SelectionSet [7:13] {
+//	    ...
+func Dump(n Node) string {
+	d := &dumper{}
+	d.value(reflect.ValueOf(n))
+	return d.String()
+}
+
+// DumpDocument returns doc and its definitions dumped as by Dump.
+func DumpDocument(doc *Document) string {
+	d := &dumper{}
+	d.value(reflect.ValueOf(doc))
+	return d.String()
+}
+
+type dumper struct {
+	strings.Builder
+	indent int
+}
+
+func (d *dumper) line(s string) {
+	d.WriteString("\n")
+	d.WriteString(strings.Repeat("  ", d.indent))
+	d.WriteString(s)
+}
+
+// value writes v, which starts at the current line.
+func (d *dumper) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			d.WriteString("nil")
+			return
+		}
+		d.value(v.Elem())
+	case reflect.Pointer:
+		if v.IsNil() {
+			d.WriteString("nil")
+			return
+		}
+		d.WriteString(v.Elem().Type().Name())
+		if n, ok := v.Interface().(Node); ok {
+			fmt.Fprintf(d, " [%d:%d]", n.Pos(), n.End())
+		}
+		d.WriteString(" {")
+		d.indent++
+		d.fields(v.Elem())
+		d.indent--
+		d.line("}")
+	case reflect.Slice:
+		fmt.Fprintf(d, "[%d] {", v.Len())
+		d.indent++
+		for i := range v.Len() {
+			d.line(strconv.Itoa(i) + ": ")
+			d.value(v.Index(i))
+		}
+		d.indent--
+		d.line("}")
+	case reflect.String:
+		d.WriteString(strconv.Quote(v.String()))
+	default:
+		fmt.Fprint(d, v.Interface())
+	}
+}
+
+// fields writes fields of struct v other than positions.
+func (d *dumper) fields(v reflect.Value) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f, fv := t.Field(i), v.Field(i)
+		if f.Name == "Position" || f.Name == "EndPosition" || !f.IsExported() {
+			continue
+		}
+		switch fv.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			if fv.IsNil() {
+				continue
+			}
+		case reflect.Slice:
+			if fv.Len() == 0 {
+				continue
+			}
+		}
+		d.line(f.Name + ": ")
+		d.value(fv)
+	}
+}
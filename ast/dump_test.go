@@ -0,0 +1,92 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestDump(t *testing.T) {
+	doc := asttest.Parse(t, `{ a: f(x: [1]) @skip(if: true) }`)
+	field := doc.Definitions[0].(*ast.OperationDefinition).SelectionSet.Selections[0]
+	expected := `Field [2:30] {
+  Alias: Name [2:3] {
+    Value: "a"
+  }
+  Name: Name [5:6] {
+    Value: "f"
+  }
+  Arguments: [1] {
+    0: Argument [7:13] {
+      Name: Name [7:8] {
+        Value: "x"
+      }
+      Value: ListValue [10:13] {
+        Values: [1] {
+          0: IntValue [11:12] {
+            Value: "1"
+          }
+        }
+      }
+    }
+  }
+  Directives: [1] {
+    0: Directive [15:30] {
+      Name: Name [16:20] {
+        Value: "skip"
+      }
+      Arguments: [1] {
+        0: Argument [21:29] {
+          Name: Name [21:23] {
+            Value: "if"
+          }
+          Value: BooleanValue [25:29] {
+            Value: true
+          }
+        }
+      }
+    }
+  }
+}`
+	if got := ast.Dump(field); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestDumpDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      *ast.Document
+		expected string
+	}{
+		{"Empty", &ast.Document{}, "Document {\n}"},
+		{"Parsed", asttest.Parse(t, `"""d""" scalar S`), `Document {
+  Definitions: [1] {
+    0: ScalarTypeDefinition [0:16] {
+      Description: Description [0:7] {
+        Value: "d"
+        Block: true
+      }
+      Name: Name [15:16] {
+        Value: "S"
+      }
+    }
+  }
+}`},
+		{"Built", &ast.Document{Definitions: []ast.Definition{&ast.OperationDefinition{OperationType: ast.OperationTypeQuery}}}, `Document {
+  Definitions: [1] {
+    0: OperationDefinition [0:0] {
+      OperationType: "query"
+    }
+  }
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ast.DumpDocument(tt.doc); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
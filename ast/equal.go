@@ -0,0 +1,55 @@
+package ast
+
+import "reflect"
+
+// Equal reports whether a and b are structurally equal: of the same types
+// with equal fields, ignoring positions and whether strings and
+// descriptions are block strings, like asthash. Nil and empty lists are
+// equal.
+func Equal(a, b Node) bool {
+	return equal(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// EqualDocument reports whether documents have equal definitions in the
+// same order.
+func EqualDocument(a, b *Document) bool {
+	return equal(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func equal(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equal(a.Elem(), b.Elem())
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := range a.Len() {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		t := a.Type()
+		for i := range t.NumField() {
+			switch f := t.Field(i); {
+			case f.Name == "Position" || f.Name == "EndPosition" || f.Name == "Block" || !f.IsExported():
+			case !equal(a.Field(i), b.Field(i)):
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
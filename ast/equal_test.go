@@ -0,0 +1,59 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{`{ a(x: 1) { b } }`, `{a(x:1){b}}`, true},
+		{`{ a(x: 1) }`, `{ a(x: 2) }`, false},
+		{`{ a(x: 1) }`, `{ a(x: 1.0) }`, false},
+		{`{ a: f }`, `{ f }`, false},
+		{`{ a { b c } }`, `{ a { c b } }`, false},
+		{`{ a(s: "x") }`, `{ a(s: """x""") }`, true},
+		{`type T { "d" f: Int }`, `type T { """d""" f: Int }`, true},
+		{`type T { f: Int }`, `type T { f: Int! }`, false},
+		{`type T { f: Int }`, `interface T { f: Int }`, false},
+		{`type T { f: Int }`, `type T { f: Int @deprecated }`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, b := asttest.Parse(t, tt.a), asttest.Parse(t, tt.b)
+			if got := ast.Equal(a.Definitions[0], b.Definitions[0]); got != tt.expected {
+				t.Errorf("expected Equal %t, got %t", tt.expected, got)
+			}
+			if got := ast.EqualDocument(a, b); got != tt.expected {
+				t.Errorf("expected EqualDocument %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEqualDocument(t *testing.T) {
+	a, b := asttest.Parse(t, `query Q { ...F } fragment F on T { a }`), asttest.Parse(t, `query Q { ...F }`)
+	if !ast.Equal(a.Definitions[0], b.Definitions[0]) {
+		t.Error("expected operations to be equal")
+	}
+	if ast.EqualDocument(a, b) {
+		t.Error("expected documents with different definitions not to be equal")
+	}
+}
+
+func TestEqual_Nil(t *testing.T) {
+	if !ast.Equal(nil, nil) {
+		t.Error("expected nil nodes to be equal")
+	}
+	if ast.Equal(&ast.Name{Value: "a"}, nil) {
+		t.Error("expected node not to equal nil")
+	}
+	if !ast.Equal(&ast.Field{Name: &ast.Name{Value: "a"}}, &ast.Field{Name: &ast.Name{Value: "a"}, Arguments: []*ast.Argument{}}) {
+		t.Error("expected nil and empty arguments to be equal")
+	}
+}
@@ -0,0 +1,221 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// kinds maps node kinds, the names of node types, to the types.
+var kinds = make(map[string]reflect.Type)
+
+func init() {
+	for _, n := range []Node{
+		(*OperationDefinition)(nil), (*FragmentDefinition)(nil),
+		(*SchemaDefinition)(nil), (*SchemaExtension)(nil), (*RootOperationTypeDefinition)(nil),
+		(*ScalarTypeDefinition)(nil), (*ScalarTypeExtension)(nil),
+		(*ObjectTypeDefinition)(nil), (*ObjectTypeExtension)(nil),
+		(*InterfaceTypeDefinition)(nil), (*InterfaceTypeExtension)(nil),
+		(*UnionTypeDefinition)(nil), (*UnionTypeExtension)(nil),
+		(*EnumTypeDefinition)(nil), (*EnumTypeExtension)(nil), (*EnumValueDefinition)(nil),
+		(*InputObjectTypeDefinition)(nil), (*InputObjectTypeExtension)(nil),
+		(*FieldDefinition)(nil), (*InputValueDefinition)(nil), (*DirectiveDefinition)(nil),
+		(*SelectionSet)(nil), (*Field)(nil), (*FragmentSpread)(nil), (*InlineFragment)(nil),
+		(*Directive)(nil), (*Argument)(nil), (*VariableDefinition)(nil),
+		(*IntValue)(nil), (*FloatValue)(nil), (*StringValue)(nil), (*BooleanValue)(nil),
+		(*NullValue)(nil), (*EnumValue)(nil), (*ListValue)(nil), (*ObjectValue)(nil),
+		(*ObjectField)(nil), (*Variable)(nil), (*Description)(nil),
+		(*NamedType)(nil), (*ListType)(nil), (*NonNullType)(nil), (*Name)(nil), (*BadNode)(nil),
+	} {
+		t := reflect.TypeOf(n).Elem()
+		kinds[t.Name()] = t
+	}
+}
+
+// MarshalJSON returns JSON encoding of n. Every node is encoded as an
+// object with "kind", the name of its type, followed by its fields named
+// by their JSON tags; nil and empty fields are omitted:
+//
+//	{"kind":"Field","pos":2,"end":6,"name":{"kind":"Name","pos":2,"end":6,"value":"user"}}
+//
+// Kinds tell types of nodes in fields of interface types, e.g. Value, so
+// UnmarshalJSON can decode them.
+func MarshalJSON(n Node) ([]byte, error) {
+	var b bytes.Buffer
+	if err := encodeJSON(&b, reflect.ValueOf(n)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON decodes node encoded by MarshalJSON.
+func UnmarshalJSON(data []byte) (Node, error) {
+	v, err := decodeJSON(data, reflect.TypeFor[Node]())
+	if err != nil {
+		return nil, err
+	}
+	n, _ := v.Interface().(Node)
+	return n, nil
+}
+
+// MarshalDocumentJSON returns JSON encoding of doc, an object of kind
+// "Document" with its definitions encoded as by MarshalJSON.
+func MarshalDocumentJSON(doc *Document) ([]byte, error) {
+	var b bytes.Buffer
+	if err := encodeJSON(&b, reflect.ValueOf(doc)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalDocumentJSON decodes document encoded by MarshalDocumentJSON.
+func UnmarshalDocumentJSON(data []byte) (*Document, error) {
+	v, err := decodeJSON(data, reflect.TypeFor[*Document]())
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface().(*Document), nil
+}
+
+// MarshalJSON implements json.Marshaler with MarshalDocumentJSON, so
+// documents can be encoded along with other values.
+func (d *Document) MarshalJSON() ([]byte, error) {
+	return MarshalDocumentJSON(d)
+}
+
+// UnmarshalJSON implements json.Unmarshaler with UnmarshalDocumentJSON.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	doc, err := UnmarshalDocumentJSON(data)
+	if err != nil {
+		return err
+	}
+	*d = *doc
+	return nil
+}
+
+// encodeJSON writes v, a node, document, slice of them or scalar field.
+func encodeJSON(b *bytes.Buffer, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			b.WriteString("null")
+			return nil
+		}
+		if v.Kind() == reflect.Interface {
+			return encodeJSON(b, v.Elem())
+		}
+		v = v.Elem()
+		t := v.Type()
+		fmt.Fprintf(b, `{"kind":%q`, t.Name())
+		for i := range t.NumField() {
+			f, fv := t.Field(i), v.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" || isEmpty(fv, opts == "omitempty") {
+				continue
+			}
+			fmt.Fprintf(b, ",%q:", name)
+			if err := encodeJSON(b, fv); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case reflect.Slice:
+		b.WriteByte('[')
+		for i := range v.Len() {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := encodeJSON(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	default:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+	}
+	return nil
+}
+
+// isEmpty reports whether field v is omitted: nil and empty nodes and
+// slices always, zero scalars with omitempty.
+func isEmpty(v reflect.Value, omitempty bool) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	case reflect.Slice:
+		return v.Len() == 0
+	}
+	return omitempty && v.IsZero()
+}
+
+// decodeJSON decodes data into value of type t, a node type, interface
+// of nodes, document, slice of them or scalar.
+func decodeJSON(data []byte, t reflect.Type) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+			return reflect.Zero(t), nil
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return reflect.Value{}, fmt.Errorf("ast: %s: %w", t, err)
+		}
+		var kind string
+		if err := json.Unmarshal(fields["kind"], &kind); err != nil {
+			return reflect.Value{}, fmt.Errorf("ast: missing kind of %s", t)
+		}
+		nt, ok := kinds[kind]
+		if kind == "Document" {
+			nt, ok = reflect.TypeFor[Document](), true
+		}
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("ast: unknown node kind %q", kind)
+		}
+		if !reflect.PointerTo(nt).AssignableTo(t) {
+			return reflect.Value{}, fmt.Errorf("ast: node of kind %s is not %s", kind, t)
+		}
+		v := reflect.New(nt)
+		for i := range nt.NumField() {
+			f := nt.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			raw, ok := fields[name]
+			if !ok || name == "" || name == "-" {
+				continue
+			}
+			fv, err := decodeJSON(raw, f.Type)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.Elem().Field(i).Set(fv)
+		}
+		return v, nil
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return reflect.Value{}, fmt.Errorf("ast: %s: %w", t, err)
+		}
+		if items == nil {
+			return reflect.Zero(t), nil
+		}
+		s := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			v, err := decodeJSON(item, t.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			s.Index(i).Set(v)
+		}
+		return s, nil
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("ast: %s: %w", t, err)
+	}
+	return v.Elem(), nil
+}
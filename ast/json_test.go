@@ -0,0 +1,102 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	doc := asttest.Parse(t, `{ a: f(x: [1]) @skip(if: false) }`)
+	field := doc.Definitions[0].(*ast.OperationDefinition).SelectionSet.Selections[0]
+	data, err := ast.MarshalJSON(field)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"kind":"Field","pos":2,"end":31,` +
+		`"alias":{"kind":"Name","pos":2,"end":3,"value":"a"},` +
+		`"name":{"kind":"Name","pos":5,"end":6,"value":"f"},` +
+		`"arguments":[{"kind":"Argument","pos":7,"end":13,"name":{"kind":"Name","pos":7,"end":8,"value":"x"},` +
+		`"value":{"kind":"ListValue","pos":10,"end":13,"values":[{"kind":"IntValue","pos":11,"end":12,"value":"1"}]}}],` +
+		`"directives":[{"kind":"Directive","pos":15,"end":31,"name":{"kind":"Name","pos":16,"end":20,"value":"skip"},` +
+		`"arguments":[{"kind":"Argument","pos":21,"end":30,"name":{"kind":"Name","pos":21,"end":23,"value":"if"},` +
+		`"value":{"kind":"BooleanValue","pos":25,"end":30,"value":false}}]}]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	n, err := ast.UnmarshalJSON(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, expected := ast.Dump(n), ast.Dump(field); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestMarshalDocumentJSON(t *testing.T) {
+	tests := []string{
+		`query Q($id: ID! = "1" @d) @live { node(id: $id) { ... on User { name } ...F } }
+		fragment F on Node { id, list(in: {a: null, b: [ENUM, 1.5, """block"""]}) }`,
+		`"""schema""" schema @d { query: Query }
+		extend schema { mutation: Mutation }
+		"scalar" scalar Date @specifiedBy(url: "u")
+		type Query implements Node & Entity @key(fields: "id") {
+		  "field" f("arg" a: [Int!]! = [1] @d): String @deprecated
+		}
+		extend type Query { g: Int }
+		interface Node implements Entity { id: ID! }
+		union U = A | B
+		enum E { "value" A @d B }
+		input I { a: Int = 1 }
+		directive @d(a: Int) repeatable on FIELD | OBJECT`,
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			doc := asttest.Parse(t, tt)
+			data, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var decoded ast.Document
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, expected := ast.DumpDocument(&decoded), ast.DumpDocument(doc); got != expected {
+				t.Errorf("expected %s, got %s", expected, got)
+			}
+			if !ast.EqualDocument(doc, &decoded) {
+				t.Error("expected equal documents")
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Missing kind", `{"value":"a"}`, `ast: missing kind of ast.Node`},
+		{"Unknown kind", `{"kind":"Unknown"}`, `ast: unknown node kind "Unknown"`},
+		{"Document", `{"kind":"Document"}`, `ast: node of kind Document is not ast.Node`},
+		{"Wrong kind", `{"kind":"Argument","value":{"kind":"Name","value":"a"}}`, `ast: node of kind Name is not ast.Value`},
+		{"Wrong field type", `{"kind":"Name","value":1}`, `ast: string: json: cannot unmarshal number`},
+		{"Not an object", `[]`, `ast: ast.Node: json: cannot unmarshal array`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ast.UnmarshalJSON([]byte(tt.input))
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tt.expected)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %q", tt.expected, err)
+			}
+		})
+	}
+}
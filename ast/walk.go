@@ -0,0 +1,321 @@
+package ast
+
+// Action tells Walk how to continue after a visitor hook.
+type Action int
+
+const (
+	// Continue walks children of the entered node.
+	Continue Action = iota
+	// Skip does not walk children of the entered node; Leave is still
+	// called for it. Returned from Leave it is the same as Continue.
+	Skip
+	// Break stops the walk.
+	Break
+)
+
+// Visitor is called by Walk when entering a node, before its children,
+// and when leaving it, after them.
+type Visitor interface {
+	Enter(n Node) Action
+	Leave(n Node) Action
+}
+
+// VisitorFuncs is a Visitor calling EnterFunc and LeaveFunc, either of
+// which may be nil.
+type VisitorFuncs struct {
+	EnterFunc func(n Node) Action
+	LeaveFunc func(n Node) Action
+}
+
+func (v VisitorFuncs) Enter(n Node) Action {
+	if v.EnterFunc == nil {
+		return Continue
+	}
+	return v.EnterFunc(n)
+}
+
+func (v VisitorFuncs) Leave(n Node) Action {
+	if v.LeaveFunc == nil {
+		return Continue
+	}
+	return v.LeaveFunc(n)
+}
+
+// TypeVisitor is a Visitor calling hooks registered for node types with
+// OnEnter and OnLeave. Hooks are called in order they were registered;
+// Break from any of them wins over Skip, which wins over Continue. The
+// zero value is ready to use.
+type TypeVisitor struct {
+	enter []func(Node) (Action, bool)
+	leave []func(Node) (Action, bool)
+}
+
+// OnEnter registers fn called when entering nodes of type T, which may be
+// a node type such as *Field or an interface such as Value or Definition.
+func OnEnter[T Node](v *TypeVisitor, fn func(n T) Action) {
+	v.enter = append(v.enter, hook(fn))
+}
+
+// OnLeave registers fn called when leaving nodes of type T.
+func OnLeave[T Node](v *TypeVisitor, fn func(n T) Action) {
+	v.leave = append(v.leave, hook(fn))
+}
+
+func hook[T Node](fn func(T) Action) func(Node) (Action, bool) {
+	return func(n Node) (Action, bool) {
+		t, ok := n.(T)
+		if !ok {
+			return Continue, false
+		}
+		return fn(t), true
+	}
+}
+
+func (v *TypeVisitor) Enter(n Node) Action {
+	return dispatch(v.enter, n)
+}
+
+func (v *TypeVisitor) Leave(n Node) Action {
+	return dispatch(v.leave, n)
+}
+
+func dispatch(hooks []func(Node) (Action, bool), n Node) Action {
+	result := Continue
+	for _, h := range hooks {
+		if action, ok := h(n); ok {
+			result = max(result, action)
+		}
+	}
+	return result
+}
+
+// Walk traverses n and its descendants depth-first in source order,
+// calling v.Enter and v.Leave for each node. It reports false when the
+// walk was stopped with Break.
+func Walk(v Visitor, n Node) bool {
+	w := walker{v: v}
+	w.node(n)
+	return !w.stopped
+}
+
+// WalkDocument walks definitions of doc in order. It reports false when
+// the walk was stopped with Break.
+func WalkDocument(v Visitor, doc *Document) bool {
+	w := walker{v: v}
+	for _, def := range doc.Definitions {
+		if w.node(def); w.stopped {
+			return false
+		}
+	}
+	return true
+}
+
+// walker holds state of a single walk.
+type walker struct {
+	v       Visitor
+	stopped bool
+}
+
+func (w *walker) node(n Node) {
+	if w.stopped {
+		return
+	}
+	switch w.v.Enter(n) {
+	case Break:
+		w.stopped = true
+		return
+	case Continue:
+		w.children(n)
+		if w.stopped {
+			return
+		}
+	}
+	if w.v.Leave(n) == Break {
+		w.stopped = true
+	}
+}
+
+func (w *walker) children(n Node) {
+	switch n := n.(type) {
+	case *OperationDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.VariableDefs)
+		walkList(w, n.Directives)
+		w.selectionSet(n.SelectionSet)
+	case *FragmentDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		w.namedType(n.TypeCondition)
+		walkList(w, n.Directives)
+		w.selectionSet(n.SelectionSet)
+	case *VariableDefinition:
+		w.description(n.Description)
+		w.node(n.Variable)
+		w.node(n.Type)
+		w.value(n.DefaultValue)
+		walkList(w, n.Directives)
+	case *SelectionSet:
+		walkList(w, n.Selections)
+	case *Field:
+		w.name(n.Alias)
+		w.name(n.Name)
+		walkList(w, n.Arguments)
+		walkList(w, n.Directives)
+		w.selectionSet(n.SelectionSet)
+	case *FragmentSpread:
+		w.name(n.Name)
+		walkList(w, n.Directives)
+	case *InlineFragment:
+		w.namedType(n.TypeCondition)
+		walkList(w, n.Directives)
+		w.selectionSet(n.SelectionSet)
+	case *Directive:
+		w.name(n.Name)
+		walkList(w, n.Arguments)
+	case *Argument:
+		w.name(n.Name)
+		w.value(n.Value)
+	case *ListValue:
+		walkList(w, n.Values)
+	case *ObjectValue:
+		walkList(w, n.Fields)
+	case *ObjectField:
+		w.name(n.Name)
+		w.value(n.Value)
+	case *Variable:
+		w.name(n.Name)
+	case *NamedType:
+		w.name(n.Name)
+	case *ListType:
+		w.node(n.Type)
+	case *NonNullType:
+		w.node(n.Type)
+
+	case *SchemaDefinition:
+		w.description(n.Description)
+		walkList(w, n.Directives)
+		walkList(w, n.RootOperationDefs)
+	case *SchemaExtension:
+		walkList(w, n.Directives)
+		walkList(w, n.RootOperationDefs)
+	case *RootOperationTypeDefinition:
+		w.namedType(n.Type)
+	case *ScalarTypeDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Directives)
+	case *ScalarTypeExtension:
+		w.name(n.Name)
+		walkList(w, n.Directives)
+	case *ObjectTypeDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Interfaces)
+		walkList(w, n.Directives)
+		walkList(w, n.Fields)
+	case *ObjectTypeExtension:
+		w.name(n.Name)
+		walkList(w, n.Interfaces)
+		walkList(w, n.Directives)
+		walkList(w, n.Fields)
+	case *InterfaceTypeDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Interfaces)
+		walkList(w, n.Directives)
+		walkList(w, n.Fields)
+	case *InterfaceTypeExtension:
+		w.name(n.Name)
+		walkList(w, n.Interfaces)
+		walkList(w, n.Directives)
+		walkList(w, n.Fields)
+	case *UnionTypeDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Directives)
+		walkList(w, n.Types)
+	case *UnionTypeExtension:
+		w.name(n.Name)
+		walkList(w, n.Directives)
+		walkList(w, n.Types)
+	case *EnumTypeDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Directives)
+		walkList(w, n.Values)
+	case *EnumTypeExtension:
+		w.name(n.Name)
+		walkList(w, n.Directives)
+		walkList(w, n.Values)
+	case *EnumValueDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Directives)
+	case *InputObjectTypeDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Directives)
+		walkList(w, n.Fields)
+	case *InputObjectTypeExtension:
+		w.name(n.Name)
+		walkList(w, n.Directives)
+		walkList(w, n.Fields)
+	case *FieldDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Arguments)
+		w.node(n.Type)
+		walkList(w, n.Directives)
+	case *InputValueDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		w.node(n.Type)
+		w.value(n.DefaultValue)
+		walkList(w, n.Directives)
+	case *DirectiveDefinition:
+		w.description(n.Description)
+		w.name(n.Name)
+		walkList(w, n.Arguments)
+		walkList(w, n.Locations)
+	}
+}
+
+func walkList[T Node](w *walker, nodes []T) {
+	for _, n := range nodes {
+		w.node(n)
+	}
+}
+
+// The following skip optional children, which would otherwise be passed
+// to visitors as typed nil nodes.
+
+func (w *walker) name(n *Name) {
+	if n != nil {
+		w.node(n)
+	}
+}
+
+func (w *walker) namedType(n *NamedType) {
+	if n != nil {
+		w.node(n)
+	}
+}
+
+func (w *walker) description(n *Description) {
+	if n != nil {
+		w.node(n)
+	}
+}
+
+func (w *walker) selectionSet(n *SelectionSet) {
+	if n != nil {
+		w.node(n)
+	}
+}
+
+func (w *walker) value(n Value) {
+	if n != nil {
+		w.node(n)
+	}
+}
@@ -0,0 +1,157 @@
+package ast_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+// trace walks doc and returns entered and left nodes as type names and
+// source spans.
+func trace(t *testing.T, input string, enter func(ast.Node) ast.Action) ([]string, bool) {
+	t.Helper()
+	var events []string
+	event := func(prefix string, n ast.Node) {
+		name := strings.TrimPrefix(fmt.Sprintf("%T", n), "*ast.")
+		events = append(events, prefix+name+" "+input[n.Pos():n.End()])
+	}
+	ok := ast.WalkDocument(ast.VisitorFuncs{
+		EnterFunc: func(n ast.Node) ast.Action {
+			event("> ", n)
+			if enter != nil {
+				return enter(n)
+			}
+			return ast.Continue
+		},
+		LeaveFunc: func(n ast.Node) ast.Action {
+			event("< ", n)
+			return ast.Continue
+		},
+	}, asttest.Parse(t, input))
+	return events, ok
+}
+
+func TestWalkDocument(t *testing.T) {
+	events, ok := trace(t, `query Q($v: [Int!]) { a: f(x: {y: $v}) ... on T { g } }`, nil)
+	expected := []string{
+		"> OperationDefinition query Q($v: [Int!]) { a: f(x: {y: $v}) ... on T { g } }",
+		"> Name Q", "< Name Q",
+		"> VariableDefinition $v: [Int!]",
+		"> Variable $v", "> Name v", "< Name v", "< Variable $v",
+		"> ListType [Int!]", "> NonNullType Int!", "> NamedType Int", "> Name Int", "< Name Int", "< NamedType Int",
+		"< NonNullType Int!", "< ListType [Int!]",
+		"< VariableDefinition $v: [Int!]",
+		"> SelectionSet { a: f(x: {y: $v}) ... on T { g } }",
+		"> Field a: f(x: {y: $v})",
+		"> Name a", "< Name a", "> Name f", "< Name f",
+		"> Argument x: {y: $v}", "> Name x", "< Name x",
+		"> ObjectValue {y: $v}", "> ObjectField y: $v", "> Name y", "< Name y",
+		"> Variable $v", "> Name v", "< Name v", "< Variable $v",
+		"< ObjectField y: $v", "< ObjectValue {y: $v}",
+		"< Argument x: {y: $v}",
+		"< Field a: f(x: {y: $v})",
+		"> InlineFragment ... on T { g }",
+		"> NamedType T", "> Name T", "< Name T", "< NamedType T",
+		"> SelectionSet { g }", "> Field g", "> Name g", "< Name g", "< Field g", "< SelectionSet { g }",
+		"< InlineFragment ... on T { g }",
+		"< SelectionSet { a: f(x: {y: $v}) ... on T { g } }",
+		"< OperationDefinition query Q($v: [Int!]) { a: f(x: {y: $v}) ... on T { g } }",
+	}
+	if !ok {
+		t.Errorf("expected complete walk")
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(events, "\n"))
+	}
+}
+
+func TestWalkDocument_Actions(t *testing.T) {
+	input := `type A { a: Int } type B { b: Int }`
+
+	events, ok := trace(t, input, func(n ast.Node) ast.Action {
+		if _, ok := n.(*ast.ObjectTypeDefinition); ok {
+			return ast.Skip
+		}
+		return ast.Continue
+	})
+	expected := []string{
+		"> ObjectTypeDefinition type A { a: Int }", "< ObjectTypeDefinition type A { a: Int }",
+		"> ObjectTypeDefinition type B { b: Int }", "< ObjectTypeDefinition type B { b: Int }",
+	}
+	if !ok || !reflect.DeepEqual(events, expected) {
+		t.Errorf("skip: expected %q, got %q", expected, events)
+	}
+
+	events, ok = trace(t, input, func(n ast.Node) ast.Action {
+		if _, ok := n.(*ast.FieldDefinition); ok {
+			return ast.Break
+		}
+		return ast.Continue
+	})
+	expected = []string{
+		"> ObjectTypeDefinition type A { a: Int }", "> Name A", "< Name A", "> FieldDefinition a: Int",
+	}
+	if ok || !reflect.DeepEqual(events, expected) {
+		t.Errorf("break: expected %q, got %q", expected, events)
+	}
+}
+
+func TestTypeVisitor(t *testing.T) {
+	doc := asttest.Parse(t, `"""D""" type A @d(x: 1) { a(y: [Int] = [1, 2]): A @deprecated } extend type A { b: Int }`)
+	var v ast.TypeVisitor
+	var got []string
+	ast.OnEnter(&v, func(n *ast.FieldDefinition) ast.Action {
+		got = append(got, "field "+n.Name.Value)
+		return ast.Continue
+	})
+	ast.OnEnter(&v, func(n ast.Value) ast.Action {
+		got = append(got, fmt.Sprintf("value %T", n))
+		return ast.Skip
+	})
+	ast.OnLeave(&v, func(n *ast.Directive) ast.Action {
+		got = append(got, "directive "+n.Name.Value)
+		if n.Name.Value == "deprecated" {
+			return ast.Break
+		}
+		return ast.Continue
+	})
+	ast.OnEnter(&v, func(n *ast.Description) ast.Action {
+		got = append(got, "description "+n.Value)
+		return ast.Continue
+	})
+	if ast.WalkDocument(&v, doc) {
+		t.Errorf("expected walk to be stopped")
+	}
+	expected := []string{
+		"description D",
+		"value *ast.IntValue",
+		"directive d",
+		"field a",
+		"value *ast.ListValue",
+		"directive deprecated",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestWalk_Node(t *testing.T) {
+	doc := asttest.Parse(t, `{ a { b c } }`)
+	field := doc.Definitions[0].(*ast.OperationDefinition).SelectionSet.Selections[0]
+	var names []string
+	var v ast.TypeVisitor
+	ast.OnEnter(&v, func(n *ast.Field) ast.Action {
+		names = append(names, n.Name.Value)
+		return ast.Continue
+	})
+	if !ast.Walk(&v, field) {
+		t.Errorf("expected complete walk")
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected fields %q", names)
+	}
+}
@@ -0,0 +1,499 @@
+// Package astdiff compares schema documents, reporting types, fields,
+// arguments, input fields, enum values, union members, implemented
+// interfaces and directive definitions added, removed or changed between
+// them, and classifying them by impact on existing clients, e.g. to
+// reject breaking schema changes in CI:
+//
+//	for _, c := range astdiff.BreakingChanges(oldDoc, newDoc) {
+//		fmt.Println(c.Kind, c.Coordinate, c.Message)
+//	}
+//
+// Type extensions are merged into the types they extend. Directives
+// applied to schema elements are not compared, except @deprecated.
+package astdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// ChangeKind is kind of schema change.
+type ChangeKind string
+
+const (
+	TypeAdded       ChangeKind = "TYPE_ADDED"
+	TypeRemoved     ChangeKind = "TYPE_REMOVED"
+	TypeKindChanged ChangeKind = "TYPE_KIND_CHANGED"
+
+	FieldAdded       ChangeKind = "FIELD_ADDED"
+	FieldRemoved     ChangeKind = "FIELD_REMOVED"
+	FieldTypeChanged ChangeKind = "FIELD_TYPE_CHANGED"
+
+	ArgumentAdded          ChangeKind = "ARGUMENT_ADDED"
+	ArgumentRemoved        ChangeKind = "ARGUMENT_REMOVED"
+	ArgumentTypeChanged    ChangeKind = "ARGUMENT_TYPE_CHANGED"
+	ArgumentDefaultChanged ChangeKind = "ARGUMENT_DEFAULT_CHANGED"
+
+	InputFieldAdded          ChangeKind = "INPUT_FIELD_ADDED"
+	InputFieldRemoved        ChangeKind = "INPUT_FIELD_REMOVED"
+	InputFieldTypeChanged    ChangeKind = "INPUT_FIELD_TYPE_CHANGED"
+	InputFieldDefaultChanged ChangeKind = "INPUT_FIELD_DEFAULT_CHANGED"
+
+	EnumValueAdded   ChangeKind = "ENUM_VALUE_ADDED"
+	EnumValueRemoved ChangeKind = "ENUM_VALUE_REMOVED"
+
+	MemberAdded   ChangeKind = "MEMBER_ADDED"   // Member type added to union.
+	MemberRemoved ChangeKind = "MEMBER_REMOVED" // Member type removed from union.
+
+	InterfaceAdded   ChangeKind = "INTERFACE_ADDED"   // Interface implemented by type added.
+	InterfaceRemoved ChangeKind = "INTERFACE_REMOVED" // Interface implemented by type removed.
+
+	DirectiveAdded             ChangeKind = "DIRECTIVE_ADDED"
+	DirectiveRemoved           ChangeKind = "DIRECTIVE_REMOVED"
+	DirectiveLocationAdded     ChangeKind = "DIRECTIVE_LOCATION_ADDED"
+	DirectiveLocationRemoved   ChangeKind = "DIRECTIVE_LOCATION_REMOVED"
+	DirectiveRepeatableChanged ChangeKind = "DIRECTIVE_REPEATABLE_CHANGED"
+
+	DescriptionChanged ChangeKind = "DESCRIPTION_CHANGED"
+	DeprecationAdded   ChangeKind = "DEPRECATION_ADDED"
+	DeprecationRemoved ChangeKind = "DEPRECATION_REMOVED"
+
+	RootTypeAdded   ChangeKind = "ROOT_TYPE_ADDED"
+	RootTypeRemoved ChangeKind = "ROOT_TYPE_REMOVED"
+	RootTypeChanged ChangeKind = "ROOT_TYPE_CHANGED"
+)
+
+// Change is a difference between schema documents.
+type Change struct {
+	Kind ChangeKind
+
+	// Coordinate of changed element; of the type or directive for members,
+	// interfaces and locations added or removed. Empty for root types.
+	Coordinate coordinate.Coordinate
+
+	// Old and New are changed nodes in the old and new document: the
+	// elements named by Coordinate, or the members, interfaces, locations
+	// and root operation types added or removed. Old is nil for additions
+	// and New for removals; both are nil for root operation types not
+	// defined explicitly by schema definition.
+	Old, New ast.Node
+
+	// Message describes change, e.g. "field User.name changed type from
+	// String to String!".
+	Message string
+
+	Severity Severity
+}
+
+// Diff returns changes from schema document old to new, classified by
+// severity. Changes are ordered by elements of old, followed by elements
+// added in new.
+func Diff(old, new *ast.Document) []Change {
+	o, n := index(old), index(new)
+	d := &differ{new: n}
+	d.roots(o, n)
+	for _, name := range o.typeOrder {
+		if t, ok := n.types[name]; ok {
+			d.typ(o.types[name], t)
+		} else {
+			d.add(TypeRemoved, coordinate.Type(name), o.types[name].node, nil, "type %s removed", name)
+		}
+	}
+	for _, name := range n.typeOrder {
+		if _, ok := o.types[name]; !ok {
+			d.add(TypeAdded, coordinate.Type(name), nil, n.types[name].node, "type %s added", name)
+		}
+	}
+	for _, name := range o.directiveOrder {
+		if def, ok := n.directives[name]; ok {
+			d.directive(o.directives[name], def)
+		} else {
+			d.add(DirectiveRemoved, coordinate.Directive(name), o.directives[name], nil, "directive @%s removed", name)
+		}
+	}
+	for _, name := range n.directiveOrder {
+		if _, ok := o.directives[name]; !ok {
+			d.add(DirectiveAdded, coordinate.Directive(name), nil, n.directives[name], "directive @%s added", name)
+		}
+	}
+	return d.changes
+}
+
+type differ struct {
+	new     *schemaIndex
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, c coordinate.Coordinate, old, new ast.Node, format string, args ...any) {
+	change := Change{Kind: kind, Coordinate: c, Old: old, New: new, Message: fmt.Sprintf(format, args...)}
+	change.Severity = d.severity(change)
+	d.changes = append(d.changes, change)
+}
+
+func (d *differ) roots(o, n *schemaIndex) {
+	for _, op := range []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription} {
+		oldName, newName := o.rootName(op), n.rootName(op)
+		if oldName == newName {
+			continue
+		}
+		oldRoot, newRoot := o.roots[op], n.roots[op]
+		switch {
+		case oldName == "":
+			d.add(RootTypeAdded, coordinate.Coordinate{}, oldRoot, newRoot, "%s root type %s added", op, newName)
+		case newName == "":
+			d.add(RootTypeRemoved, coordinate.Coordinate{}, oldRoot, newRoot, "%s root type %s removed", op, oldName)
+		default:
+			d.add(RootTypeChanged, coordinate.Coordinate{}, oldRoot, newRoot, "%s root type changed from %s to %s", op, oldName, newName)
+		}
+	}
+}
+
+func (d *differ) typ(o, n *typeIndex) {
+	name := o.name
+	c := coordinate.Type(name)
+	if o.kind != n.kind {
+		d.add(TypeKindChanged, c, o.node, n.node, "type %s changed kind from %s to %s", name, o.kind, n.kind)
+		return
+	}
+	d.description("type "+name, c, o.node, n.node, o.description, n.description)
+	d.namedTypes(name, o.interfaces, n.interfaces, InterfaceRemoved, InterfaceAdded,
+		"type %s no longer implements interface %s", "type %s implements interface %s")
+	d.namedTypes(name, o.members, n.members, MemberRemoved, MemberAdded,
+		"union %s no longer has member %s", "union %s has member %s")
+
+	newFields := make(map[string]*ast.FieldDefinition)
+	for _, f := range n.fields {
+		newFields[f.Name.Value] = f
+	}
+	for _, of := range o.fields {
+		fc := coordinate.Member(name, of.Name.Value)
+		nf, ok := newFields[of.Name.Value]
+		if !ok {
+			d.add(FieldRemoved, fc, of, nil, "field %s removed", fc)
+			continue
+		}
+		d.description("field "+fc.String(), fc, of, nf, of.Description, nf.Description)
+		d.deprecation("field "+fc.String(), fc, of, nf, of.Directives, nf.Directives)
+		if !ast.Equal(of.Type, nf.Type) {
+			d.add(FieldTypeChanged, fc, of, nf, "field %s changed type from %s to %s", fc, printer.Print(of.Type), printer.Print(nf.Type))
+		}
+		d.inputValues(of.Arguments, nf.Arguments, func(arg string) coordinate.Coordinate {
+			return coordinate.Argument(name, of.Name.Value, arg)
+		}, "argument", ArgumentAdded, ArgumentRemoved, ArgumentTypeChanged, ArgumentDefaultChanged)
+	}
+	oldFields := make(map[string]bool)
+	for _, f := range o.fields {
+		oldFields[f.Name.Value] = true
+	}
+	for _, nf := range n.fields {
+		if !oldFields[nf.Name.Value] {
+			fc := coordinate.Member(name, nf.Name.Value)
+			d.add(FieldAdded, fc, nil, nf, "field %s added", fc)
+		}
+	}
+
+	d.inputValues(o.inputFields, n.inputFields, func(field string) coordinate.Coordinate {
+		return coordinate.Member(name, field)
+	}, "input field", InputFieldAdded, InputFieldRemoved, InputFieldTypeChanged, InputFieldDefaultChanged)
+
+	newValues := make(map[string]*ast.EnumValueDefinition)
+	for _, v := range n.values {
+		newValues[v.Name.Value] = v
+	}
+	for _, ov := range o.values {
+		vc := coordinate.Member(name, ov.Name.Value)
+		nv, ok := newValues[ov.Name.Value]
+		if !ok {
+			d.add(EnumValueRemoved, vc, ov, nil, "enum value %s removed", vc)
+			continue
+		}
+		d.description("enum value "+vc.String(), vc, ov, nv, ov.Description, nv.Description)
+		d.deprecation("enum value "+vc.String(), vc, ov, nv, ov.Directives, nv.Directives)
+	}
+	oldValues := make(map[string]bool)
+	for _, v := range o.values {
+		oldValues[v.Name.Value] = true
+	}
+	for _, nv := range n.values {
+		if !oldValues[nv.Name.Value] {
+			vc := coordinate.Member(name, nv.Name.Value)
+			d.add(EnumValueAdded, vc, nil, nv, "enum value %s added", vc)
+		}
+	}
+}
+
+// namedTypes reports interfaces or union members of type name removed and
+// added.
+func (d *differ) namedTypes(name string, o, n []*ast.NamedType, removed, added ChangeKind, removedFormat, addedFormat string) {
+	for _, ot := range o {
+		if findNamedType(n, ot.Name.Value) == nil {
+			d.add(removed, coordinate.Type(name), ot, nil, removedFormat, name, ot.Name.Value)
+		}
+	}
+	for _, nt := range n {
+		if findNamedType(o, nt.Name.Value) == nil {
+			d.add(added, coordinate.Type(name), nil, nt, addedFormat, name, nt.Name.Value)
+		}
+	}
+}
+
+// inputValues reports changes of arguments or input fields, named what,
+// with coordinates given by coord.
+func (d *differ) inputValues(o, n []*ast.InputValueDefinition, coord func(string) coordinate.Coordinate, what string, added, removed, typeChanged, defaultChanged ChangeKind) {
+	for _, ov := range o {
+		c := coord(ov.Name.Value)
+		nv := findInputValue(n, ov.Name.Value)
+		if nv == nil {
+			d.add(removed, c, ov, nil, "%s %s removed", what, c)
+			continue
+		}
+		d.description(what+" "+c.String(), c, ov, nv, ov.Description, nv.Description)
+		d.deprecation(what+" "+c.String(), c, ov, nv, ov.Directives, nv.Directives)
+		if !ast.Equal(ov.Type, nv.Type) {
+			d.add(typeChanged, c, ov, nv, "%s %s changed type from %s to %s", what, c, printer.Print(ov.Type), printer.Print(nv.Type))
+		}
+		if !ast.Equal(ov.DefaultValue, nv.DefaultValue) {
+			d.add(defaultChanged, c, ov, nv, "%s %s changed default value from %s to %s", what, c, defaultValue(ov), defaultValue(nv))
+		}
+	}
+	for _, nv := range n {
+		if findInputValue(o, nv.Name.Value) == nil {
+			c := coord(nv.Name.Value)
+			d.add(added, c, nil, nv, "%s %s added", what, c)
+		}
+	}
+}
+
+func (d *differ) directive(o, n *ast.DirectiveDefinition) {
+	name := o.Name.Value
+	c := coordinate.Directive(name)
+	d.description("directive @"+name, c, o, n, o.Description, n.Description)
+	if o.Repeatable != n.Repeatable {
+		if n.Repeatable {
+			d.add(DirectiveRepeatableChanged, c, o, n, "directive @%s became repeatable", name)
+		} else {
+			d.add(DirectiveRepeatableChanged, c, o, n, "directive @%s is no longer repeatable", name)
+		}
+	}
+	d.inputValues(o.Arguments, n.Arguments, func(arg string) coordinate.Coordinate {
+		return coordinate.DirectiveArgument(name, arg)
+	}, "argument", ArgumentAdded, ArgumentRemoved, ArgumentTypeChanged, ArgumentDefaultChanged)
+	for _, ol := range o.Locations {
+		if findName(n.Locations, ol.Value) == nil {
+			d.add(DirectiveLocationRemoved, c, ol, nil, "location %s removed from directive @%s", ol.Value, name)
+		}
+	}
+	for _, nl := range n.Locations {
+		if findName(o.Locations, nl.Value) == nil {
+			d.add(DirectiveLocationAdded, c, nil, nl, "location %s added to directive @%s", nl.Value, name)
+		}
+	}
+}
+
+func (d *differ) description(element string, c coordinate.Coordinate, o, n ast.Node, od, nd *ast.Description) {
+	if descriptionText(od) != descriptionText(nd) {
+		d.add(DescriptionChanged, c, o, n, "description of %s changed", element)
+	}
+}
+
+func (d *differ) deprecation(element string, c coordinate.Coordinate, o, n ast.Node, od, nd []*ast.Directive) {
+	oldDeprecated, newDeprecated := findDirective(od, "deprecated") != nil, findDirective(nd, "deprecated") != nil
+	switch {
+	case !oldDeprecated && newDeprecated:
+		d.add(DeprecationAdded, c, o, n, "%s deprecated", element)
+	case oldDeprecated && !newDeprecated:
+		d.add(DeprecationRemoved, c, o, n, "%s no longer deprecated", element)
+	}
+}
+
+// schemaIndex indexes types and directive definitions of a document.
+type schemaIndex struct {
+	types          map[string]*typeIndex
+	typeOrder      []string
+	directives     map[string]*ast.DirectiveDefinition
+	directiveOrder []string
+	roots          map[ast.OperationType]*ast.RootOperationTypeDefinition
+	explicitRoots  bool
+}
+
+// typeIndex is a type with members of its definition and extensions.
+type typeIndex struct {
+	name        string
+	kind        schema.Kind
+	node        ast.Definition // Definition, or first extension without one.
+	description *ast.Description
+	interfaces  []*ast.NamedType
+	fields      []*ast.FieldDefinition
+	inputFields []*ast.InputValueDefinition
+	values      []*ast.EnumValueDefinition
+	members     []*ast.NamedType
+}
+
+func index(doc *ast.Document) *schemaIndex {
+	s := &schemaIndex{
+		types:      make(map[string]*typeIndex),
+		directives: make(map[string]*ast.DirectiveDefinition),
+		roots:      make(map[ast.OperationType]*ast.RootOperationTypeDefinition),
+	}
+	for _, def := range doc.Definitions {
+		var roots []*ast.RootOperationTypeDefinition
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			roots = d.RootOperationDefs
+		case *ast.SchemaExtension:
+			roots = d.RootOperationDefs
+		case *ast.DirectiveDefinition:
+			if _, ok := s.directives[d.Name.Value]; !ok {
+				s.directives[d.Name.Value] = d
+				s.directiveOrder = append(s.directiveOrder, d.Name.Value)
+			}
+			continue
+		default:
+			s.addType(def)
+			continue
+		}
+		for _, r := range roots {
+			s.roots[r.OperationType] = r
+			s.explicitRoots = true
+		}
+	}
+	return s
+}
+
+func (s *schemaIndex) addType(def ast.Definition) {
+	var (
+		name        *ast.Name
+		kind        schema.Kind
+		description *ast.Description
+		t           typeIndex
+	)
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		name, kind, description = d.Name, schema.KindScalar, d.Description
+	case *ast.ScalarTypeExtension:
+		name, kind = d.Name, schema.KindScalar
+	case *ast.ObjectTypeDefinition:
+		name, kind, description = d.Name, schema.KindObject, d.Description
+		t.interfaces, t.fields = d.Interfaces, d.Fields
+	case *ast.ObjectTypeExtension:
+		name, kind = d.Name, schema.KindObject
+		t.interfaces, t.fields = d.Interfaces, d.Fields
+	case *ast.InterfaceTypeDefinition:
+		name, kind, description = d.Name, schema.KindInterface, d.Description
+		t.interfaces, t.fields = d.Interfaces, d.Fields
+	case *ast.InterfaceTypeExtension:
+		name, kind = d.Name, schema.KindInterface
+		t.interfaces, t.fields = d.Interfaces, d.Fields
+	case *ast.UnionTypeDefinition:
+		name, kind, description = d.Name, schema.KindUnion, d.Description
+		t.members = d.Types
+	case *ast.UnionTypeExtension:
+		name, kind = d.Name, schema.KindUnion
+		t.members = d.Types
+	case *ast.EnumTypeDefinition:
+		name, kind, description = d.Name, schema.KindEnum, d.Description
+		t.values = d.Values
+	case *ast.EnumTypeExtension:
+		name, kind = d.Name, schema.KindEnum
+		t.values = d.Values
+	case *ast.InputObjectTypeDefinition:
+		name, kind, description = d.Name, schema.KindInputObject, d.Description
+		t.inputFields = d.Fields
+	case *ast.InputObjectTypeExtension:
+		name, kind = d.Name, schema.KindInputObject
+		t.inputFields = d.Fields
+	default:
+		return
+	}
+	_, extension := def.(ast.TypeSystemExtension)
+
+	existing := s.types[name.Value]
+	if existing == nil {
+		existing = &typeIndex{name: name.Value, kind: kind, node: def}
+		s.types[name.Value] = existing
+		s.typeOrder = append(s.typeOrder, name.Value)
+	} else if !extension {
+		if _, ok := existing.node.(ast.TypeSystemExtension); ok {
+			existing.node, existing.kind = def, kind
+		}
+	}
+	if !extension && existing.description == nil {
+		existing.description = description
+	}
+	existing.interfaces = append(existing.interfaces, t.interfaces...)
+	existing.fields = append(existing.fields, t.fields...)
+	existing.inputFields = append(existing.inputFields, t.inputFields...)
+	existing.values = append(existing.values, t.values...)
+	existing.members = append(existing.members, t.members...)
+}
+
+// rootName returns name of root operation type, which defaults to
+// capitalized operation type when the document has no schema definition.
+func (s *schemaIndex) rootName(op ast.OperationType) string {
+	if r, ok := s.roots[op]; ok {
+		return r.Type.Name.Value
+	}
+	if s.explicitRoots {
+		return ""
+	}
+	name := strings.ToUpper(string(op[:1])) + string(op[1:])
+	if _, ok := s.types[name]; ok {
+		return name
+	}
+	return ""
+}
+
+func findNamedType(types []*ast.NamedType, name string) *ast.NamedType {
+	for _, t := range types {
+		if t.Name.Value == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func findInputValue(values []*ast.InputValueDefinition, name string) *ast.InputValueDefinition {
+	for _, v := range values {
+		if v.Name.Value == name {
+			return v
+		}
+	}
+	return nil
+}
+
+func findName(names []*ast.Name, name string) *ast.Name {
+	for _, n := range names {
+		if n.Value == name {
+			return n
+		}
+	}
+	return nil
+}
+
+func findDirective(directives []*ast.Directive, name string) *ast.Directive {
+	for _, d := range directives {
+		if d.Name.Value == name {
+			return d
+		}
+	}
+	return nil
+}
+
+func descriptionText(d *ast.Description) string {
+	if d == nil {
+		return ""
+	}
+	return d.Value
+}
+
+func defaultValue(v *ast.InputValueDefinition) string {
+	if v.DefaultValue == nil {
+		return "none"
+	}
+	return printer.Value(v.DefaultValue)
+}
@@ -0,0 +1,145 @@
+package astdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func messages(changes []Change) string {
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, string(c.Kind)+" "+c.Coordinate.String()+": "+c.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		expected string
+	}{
+		{
+			name:     "identical",
+			old:      `type Query { a(x: Int = 1): String }`,
+			new:      `type Query {  a(x: Int = 1): String }`,
+			expected: "",
+		},
+		{
+			name: "types",
+			old:  `type Query { a: A } type A { x: Int } scalar S`,
+			new:  `type Query { a: A } interface A { x: Int } enum E { V }`,
+			expected: `TYPE_KIND_CHANGED A: type A changed kind from OBJECT to INTERFACE
+TYPE_REMOVED S: type S removed
+TYPE_ADDED E: type E added`,
+		},
+		{
+			name: "fields",
+			old:  `type Query { a: String b: Int c: [Int] }`,
+			new:  `type Query { a: String! c: [Int] d: ID }`,
+			expected: `FIELD_TYPE_CHANGED Query.a: field Query.a changed type from String to String!
+FIELD_REMOVED Query.b: field Query.b removed
+FIELD_ADDED Query.d: field Query.d added`,
+		},
+		{
+			name: "arguments",
+			old:  `type Query { a(x: Int, y: Int = 1, z: String): String }`,
+			new:  `type Query { a(x: Int!, y: Int = 2, w: ID): String }`,
+			expected: `ARGUMENT_TYPE_CHANGED Query.a(x:): argument Query.a(x:) changed type from Int to Int!
+ARGUMENT_DEFAULT_CHANGED Query.a(y:): argument Query.a(y:) changed default value from 1 to 2
+ARGUMENT_REMOVED Query.a(z:): argument Query.a(z:) removed
+ARGUMENT_ADDED Query.a(w:): argument Query.a(w:) added`,
+		},
+		{
+			name: "input fields",
+			old:  `input I { a: Int b: Int c: Int = 1 }`,
+			new:  `input I { a: Float c: Int d: Int! }`,
+			expected: `INPUT_FIELD_TYPE_CHANGED I.a: input field I.a changed type from Int to Float
+INPUT_FIELD_REMOVED I.b: input field I.b removed
+INPUT_FIELD_DEFAULT_CHANGED I.c: input field I.c changed default value from 1 to none
+INPUT_FIELD_ADDED I.d: input field I.d added`,
+		},
+		{
+			name: "enum values",
+			old:  `enum E { A B }`,
+			new:  `enum E { A @deprecated C }`,
+			expected: `DEPRECATION_ADDED E.A: enum value E.A deprecated
+ENUM_VALUE_REMOVED E.B: enum value E.B removed
+ENUM_VALUE_ADDED E.C: enum value E.C added`,
+		},
+		{
+			name: "members and interfaces",
+			old:  `union U = A | B type T implements I & J { id: ID }`,
+			new:  `union U = A | C type T implements J & K { id: ID }`,
+			expected: `MEMBER_REMOVED U: union U no longer has member B
+MEMBER_ADDED U: union U has member C
+INTERFACE_REMOVED T: type T no longer implements interface I
+INTERFACE_ADDED T: type T implements interface K`,
+		},
+		{
+			name: "descriptions and deprecations",
+			old:  `"Old" type Query { "a" a: Int @deprecated b: Int }`,
+			new:  `"""New""" type Query { """a""" a: Int b: Int @deprecated(reason: "Use a.") }`,
+			expected: `DESCRIPTION_CHANGED Query: description of type Query changed
+DEPRECATION_REMOVED Query.a: field Query.a no longer deprecated
+DEPRECATION_ADDED Query.b: field Query.b deprecated`,
+		},
+		{
+			name: "directives",
+			old:  `directive @a(x: Int) on FIELD | QUERY directive @b on FIELD`,
+			new:  `directive @a(x: Int!) repeatable on FIELD | MUTATION directive @c on FIELD`,
+			expected: `DIRECTIVE_REPEATABLE_CHANGED @a: directive @a became repeatable
+ARGUMENT_TYPE_CHANGED @a(x:): argument @a(x:) changed type from Int to Int!
+DIRECTIVE_LOCATION_REMOVED @a: location QUERY removed from directive @a
+DIRECTIVE_LOCATION_ADDED @a: location MUTATION added to directive @a
+DIRECTIVE_REMOVED @b: directive @b removed
+DIRECTIVE_ADDED @c: directive @c added`,
+		},
+		{
+			name:     "extensions",
+			old:      `type Query { a: Int } extend type Query { b: Int }`,
+			new:      `extend type Query { b: Int } type Query { a: Int c: Int }`,
+			expected: `FIELD_ADDED Query.c: field Query.c added`,
+		},
+		{
+			name: "root types",
+			old:  `type Query { a: Int } type Mutation { a: Int }`,
+			new:  `schema { query: Root } type Root { a: Int } type Mutation { a: Int }`,
+			expected: `ROOT_TYPE_CHANGED : query root type changed from Query to Root
+ROOT_TYPE_REMOVED : mutation root type Mutation removed
+TYPE_REMOVED Query: type Query removed
+TYPE_ADDED Root: type Root added`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messages(Diff(asttest.Parse(t, tt.old), asttest.Parse(t, tt.new))); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDiff_Nodes(t *testing.T) {
+	old := asttest.Parse(t, `type Query { a: Int b: Int }`)
+	new := asttest.Parse(t, `type Query { a: String c: Int }`)
+	changes := Diff(old, new)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %d", len(changes))
+	}
+	fields := old.Definitions[0].(*ast.ObjectTypeDefinition).Fields
+	newFields := new.Definitions[0].(*ast.ObjectTypeDefinition).Fields
+	expected := []struct{ old, new ast.Node }{
+		{fields[0], newFields[0]},
+		{fields[1], nil},
+		{nil, newFields[1]},
+	}
+	for i, e := range expected {
+		if changes[i].Old != e.old || changes[i].New != e.new {
+			t.Errorf("change %d: expected nodes %v and %v, got %v and %v", i, e.old, e.new, changes[i].Old, changes[i].New)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package astdiff
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Severity is impact of change on existing clients, following
+// graphql-js findBreakingChanges and findDangerousChanges.
+type Severity string
+
+const (
+	// Breaking changes make valid operations invalid or change types of
+	// results clients rely on, e.g. removed fields or enum values.
+	Breaking Severity = "BREAKING"
+	// Dangerous changes keep operations valid but may change their
+	// results or break exhaustive handling, e.g. added enum values or
+	// changed default values.
+	Dangerous Severity = "DANGEROUS"
+	// Safe changes do not affect existing clients.
+	Safe Severity = "SAFE"
+)
+
+// BreakingChanges returns changes from schema document old to new that
+// are Breaking, in order of Diff.
+func BreakingChanges(old, new *ast.Document) []Change {
+	var breaking []Change
+	for _, c := range Diff(old, new) {
+		if c.Severity == Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// Positions returns positions of Old in the old document and New in the
+// new document, -1 for absent nodes.
+func (c Change) Positions() (old, new int) {
+	old, new = -1, -1
+	if c.Old != nil {
+		old = c.Old.Pos()
+	}
+	if c.New != nil {
+		new = c.New.Pos()
+	}
+	return old, new
+}
+
+func (d *differ) severity(c Change) Severity {
+	switch c.Kind {
+	case TypeRemoved, TypeKindChanged, FieldRemoved, ArgumentRemoved, InputFieldRemoved,
+		EnumValueRemoved, MemberRemoved, InterfaceRemoved, DirectiveRemoved, DirectiveLocationRemoved,
+		RootTypeRemoved, RootTypeChanged:
+		return Breaking
+	case EnumValueAdded, MemberAdded, InterfaceAdded, ArgumentDefaultChanged, InputFieldDefaultChanged:
+		return Dangerous
+	case FieldTypeChanged:
+		if d.safeOutputChange(c.Old.(*ast.FieldDefinition).Type, c.New.(*ast.FieldDefinition).Type) {
+			return Safe
+		}
+		return Breaking
+	case ArgumentTypeChanged, InputFieldTypeChanged:
+		if safeInputChange(c.Old.(*ast.InputValueDefinition).Type, c.New.(*ast.InputValueDefinition).Type) {
+			return Safe
+		}
+		return Breaking
+	case ArgumentAdded, InputFieldAdded:
+		switch {
+		case isRequired(c.New.(*ast.InputValueDefinition)):
+			return Breaking
+		case c.Coordinate.Directive:
+			return Safe
+		}
+		return Dangerous
+	case DirectiveRepeatableChanged:
+		if c.New.(*ast.DirectiveDefinition).Repeatable {
+			return Safe
+		}
+		return Breaking
+	}
+	return Safe
+}
+
+// safeOutputChange reports whether changing type of field from o to n
+// keeps results valid for existing clients: n is o, or it is narrowed to
+// non-null or to a possible type of o in the new schema.
+func (d *differ) safeOutputChange(o, n ast.Type) bool {
+	switch o := o.(type) {
+	case *ast.ListType:
+		switch n := n.(type) {
+		case *ast.ListType:
+			return d.safeOutputChange(o.Type, n.Type)
+		case *ast.NonNullType:
+			return d.safeOutputChange(o, n.Type)
+		}
+	case *ast.NonNullType:
+		if n, ok := n.(*ast.NonNullType); ok {
+			return d.safeOutputChange(o.Type, n.Type)
+		}
+	case *ast.NamedType:
+		switch n := n.(type) {
+		case *ast.NamedType:
+			return o.Name.Value == n.Name.Value || d.possibleType(o.Name.Value, n.Name.Value)
+		case *ast.NonNullType:
+			return d.safeOutputChange(o, n.Type)
+		}
+	}
+	return false
+}
+
+// possibleType reports whether type name is an implementation or member
+// of abstract type in the new schema.
+func (d *differ) possibleType(abstract, name string) bool {
+	a, t := d.new.types[abstract], d.new.types[name]
+	if a == nil || t == nil {
+		return false
+	}
+	switch a.kind {
+	case schema.KindInterface:
+		return findNamedType(t.interfaces, abstract) != nil
+	case schema.KindUnion:
+		return findNamedType(a.members, name) != nil
+	}
+	return false
+}
+
+// safeInputChange reports whether changing type of argument or input
+// field from o to n keeps existing values valid: n is o, or it is relaxed
+// to nullable.
+func safeInputChange(o, n ast.Type) bool {
+	switch o := o.(type) {
+	case *ast.ListType:
+		if n, ok := n.(*ast.ListType); ok {
+			return safeInputChange(o.Type, n.Type)
+		}
+	case *ast.NonNullType:
+		if n, ok := n.(*ast.NonNullType); ok {
+			return safeInputChange(o.Type, n.Type)
+		}
+		return safeInputChange(o.Type, n)
+	case *ast.NamedType:
+		if n, ok := n.(*ast.NamedType); ok {
+			return o.Name.Value == n.Name.Value
+		}
+	}
+	return false
+}
+
+func isRequired(v *ast.InputValueDefinition) bool {
+	_, nonNull := v.Type.(*ast.NonNullType)
+	return nonNull && v.DefaultValue == nil
+}
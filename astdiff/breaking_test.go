@@ -0,0 +1,105 @@
+package astdiff
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestDiff_Severity(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		expected Severity
+	}{
+		{"type removed", `type Query { a: Int } type T { a: Int }`, `type Query { a: Int }`, Breaking},
+		{"type added", `type Query { a: Int }`, `type Query { a: Int } type T { a: Int }`, Safe},
+		{"field removed", `type Query { a: Int b: Int }`, `type Query { a: Int }`, Breaking},
+		{"field added", `type Query { a: Int }`, `type Query { a: Int b: Int }`, Safe},
+		{"field made non-null", `type Query { a: Int }`, `type Query { a: Int! }`, Safe},
+		{"field made nullable", `type Query { a: Int! }`, `type Query { a: Int }`, Breaking},
+		{"field list item made non-null", `type Query { a: [Int] }`, `type Query { a: [Int!]! }`, Safe},
+		{"field made list", `type Query { a: Int }`, `type Query { a: [Int] }`, Breaking},
+		{"field type changed", `type Query { a: Int }`, `type Query { a: String }`, Breaking},
+		{"field narrowed to implementation", `type Query { a: I } interface I { x: Int } type T implements I { x: Int }`, `type Query { a: T } interface I { x: Int } type T implements I { x: Int }`, Safe},
+		{"field narrowed to member", `type Query { a: U } union U = T type T { x: Int }`, `type Query { a: T! } union U = T type T { x: Int }`, Safe},
+		{"field changed to unrelated object", `type Query { a: I } interface I { x: Int } type T { x: Int }`, `type Query { a: T } interface I { x: Int } type T { x: Int }`, Breaking},
+		{"required argument added", `type Query { a: Int }`, `type Query { a(x: Int!): Int }`, Breaking},
+		{"optional argument added", `type Query { a: Int }`, `type Query { a(x: Int): Int }`, Dangerous},
+		{"argument with default added", `type Query { a: Int }`, `type Query { a(x: Int! = 1): Int }`, Dangerous},
+		{"argument removed", `type Query { a(x: Int): Int }`, `type Query { a: Int }`, Breaking},
+		{"argument made nullable", `type Query { a(x: Int!): Int }`, `type Query { a(x: Int): Int }`, Safe},
+		{"argument made non-null", `type Query { a(x: Int): Int }`, `type Query { a(x: Int!): Int }`, Breaking},
+		{"argument list item made nullable", `type Query { a(x: [Int!]!): Int }`, `type Query { a(x: [Int]): Int }`, Safe},
+		{"argument default changed", `type Query { a(x: Int = 1): Int }`, `type Query { a(x: Int = 2): Int }`, Dangerous},
+		{"required input field added", `input I { a: Int }`, `input I { a: Int b: Int! }`, Breaking},
+		{"optional input field added", `input I { a: Int }`, `input I { a: Int b: Int }`, Dangerous},
+		{"input field type changed", `input I { a: Int }`, `input I { a: Float }`, Breaking},
+		{"enum value removed", `enum E { A B }`, `enum E { A }`, Breaking},
+		{"enum value added", `enum E { A }`, `enum E { A B }`, Dangerous},
+		{"member removed", `union U = A | B`, `union U = A`, Breaking},
+		{"member added", `union U = A`, `union U = A | B`, Dangerous},
+		{"interface removed", `type T implements I { a: Int }`, `type T { a: Int }`, Breaking},
+		{"interface added", `type T { a: Int }`, `type T implements I { a: Int }`, Dangerous},
+		{"kind changed", `type T { a: Int }`, `interface T { a: Int }`, Breaking},
+		{"directive removed", `directive @d on FIELD`, ``, Breaking},
+		{"directive location removed", `directive @d on FIELD | QUERY`, `directive @d on FIELD`, Breaking},
+		{"directive location added", `directive @d on FIELD`, `directive @d on FIELD | QUERY`, Safe},
+		{"directive no longer repeatable", `directive @d repeatable on FIELD`, `directive @d on FIELD`, Breaking},
+		{"directive repeatable", `directive @d on FIELD`, `directive @d repeatable on FIELD`, Safe},
+		{"optional directive argument added", `directive @d on FIELD`, `directive @d(x: Int) on FIELD`, Safe},
+		{"required directive argument added", `directive @d on FIELD`, `directive @d(x: Int!) on FIELD`, Breaking},
+		{"description changed", `"a" scalar S`, `"b" scalar S`, Safe},
+		{"deprecation added", `enum E { A }`, `enum E { A @deprecated }`, Safe},
+		{"root type removed", `schema { query: Q mutation: M }`, `schema { query: Q }`, Breaking},
+		{"root type added", `schema { query: Q }`, `schema { query: Q mutation: M }`, Safe},
+		{"root type changed", `schema { query: Q }`, `schema { query: R }`, Breaking},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Diff(asttest.Parse(t, tt.old), asttest.Parse(t, tt.new))
+			if len(changes) != 1 {
+				t.Fatalf("expected 1 change, got:\n%s", messages(changes))
+			}
+			if changes[0].Severity != tt.expected {
+				t.Errorf("expected %s, got %s for %s", tt.expected, changes[0].Severity, changes[0].Message)
+			}
+		})
+	}
+}
+
+func TestBreakingChanges(t *testing.T) {
+	old := asttest.Parse(t, `type Query {
+  user(id: ID!): User
+  users: [User]
+}
+type User { id: ID! name: String role: Role }
+enum Role { ADMIN MEMBER }`)
+	new := asttest.Parse(t, `type Query {
+  user(id: ID!, tenant: ID!): User
+  users: [User!]!
+}
+type User { id: ID! role: Role }
+enum Role { ADMIN MEMBER GUEST }`)
+
+	changes := BreakingChanges(old, new)
+	expected := []struct {
+		message  string
+		old, new int
+	}{
+		{"argument Query.user(tenant:) added", -1, 29},
+		{"field User.name removed", 73, -1},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got:\n%s", len(expected), messages(changes))
+	}
+	for i, e := range expected {
+		c := changes[i]
+		if c.Message != e.message {
+			t.Errorf("change %d: expected %q, got %q", i, e.message, c.Message)
+		}
+		if o, n := c.Positions(); o != e.old || n != e.new {
+			t.Errorf("change %d: expected positions %d and %d, got %d and %d", i, e.old, e.new, o, n)
+		}
+	}
+}
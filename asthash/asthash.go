@@ -0,0 +1,367 @@
+// Package asthash computes stable content hashes of AST nodes, so results
+// of analyses over structurally identical subtrees, e.g. shared fragments,
+// can be memoized across documents.
+package asthash
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"reflect"
+	"sync"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// Sum is SHA-256 content hash of a node.
+type Sum [sha256.Size]byte
+
+func (s Sum) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Hasher hashes nodes caching hashes of every visited node, so hashing a
+// document containing already hashed subtrees only hashes the new nodes.
+// Hashes ignore positions and whether strings and descriptions are block
+// strings; fragment spreads are hashed by name, without expanding them.
+// Nodes must not be modified once hashed. Hasher is safe for concurrent
+// use. The zero value hashes without caching.
+type Hasher struct {
+	mu    sync.Mutex
+	cache map[ast.Node]Sum
+}
+
+// New returns hasher with empty cache.
+func New() *Hasher {
+	return &Hasher{cache: make(map[ast.Node]Sum)}
+}
+
+// Hash returns content hash of n.
+func (h *Hasher) Hash(n ast.Node) Sum {
+	return h.hash(n)
+}
+
+// Len returns number of cached hashes.
+func (h *Hasher) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.cache)
+}
+
+// Hash returns content hash of n without caching.
+func Hash(n ast.Node) Sum {
+	var h Hasher
+	return h.hash(n)
+}
+
+// Document returns content hash of doc.
+func Document(doc *ast.Document) Sum {
+	var h Hasher
+	return h.Document(doc)
+}
+
+// Document returns content hash of doc, which itself is not a node.
+func (h *Hasher) Document(doc *ast.Document) Sum {
+	e := encoder{h: h}
+	e.tag("Document")
+	nodes(&e, doc.Definitions)
+	return sha256.Sum256(e.buf)
+}
+
+func (h *Hasher) hash(n ast.Node) Sum {
+	if h.cache != nil {
+		h.mu.Lock()
+		sum, ok := h.cache[n]
+		h.mu.Unlock()
+		if ok {
+			return sum
+		}
+	}
+	e := encoder{h: h}
+	e.encode(n)
+	sum := sha256.Sum256(e.buf)
+	if h.cache != nil {
+		h.mu.Lock()
+		h.cache[n] = sum
+		h.mu.Unlock()
+	}
+	return sum
+}
+
+// encoder serializes node into unambiguous byte sequence: node kind tag
+// followed by its fields, where strings are length-prefixed and child
+// nodes are represented by their hashes.
+type encoder struct {
+	h   *Hasher
+	buf []byte
+}
+
+func (e *encoder) tag(kind string) {
+	e.str(kind)
+}
+
+func (e *encoder) str(s string) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+// child appends hash of n or a zero byte when n is nil.
+func (e *encoder) child(n ast.Node) {
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		e.buf = append(e.buf, 0)
+		return
+	}
+	sum := e.h.hash(n)
+	e.buf = append(e.buf, 1)
+	e.buf = append(e.buf, sum[:]...)
+}
+
+func nodes[T ast.Node](e *encoder, list []T) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(list)))
+	for _, n := range list {
+		e.child(n)
+	}
+}
+
+func (e *encoder) name(n *ast.Name) {
+	if n == nil {
+		e.buf = append(e.buf, 0)
+		return
+	}
+	e.buf = append(e.buf, 1)
+	e.str(n.Value)
+}
+
+// executableDescription appends description of executable definition
+// only when there is one, so hashes of definitions without descriptions
+// stay the same as before they could have one.
+func (e *encoder) executableDescription(d *ast.Description) {
+	if d != nil {
+		e.description(d)
+	}
+}
+
+func (e *encoder) description(d *ast.Description) {
+	if d == nil {
+		e.buf = append(e.buf, 0)
+		return
+	}
+	e.buf = append(e.buf, 1)
+	e.str(d.Value)
+}
+
+func (e *encoder) encode(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.OperationDefinition:
+		e.tag("OperationDefinition")
+		e.str(string(n.OperationType))
+		e.name(n.Name)
+		nodes(e, n.VariableDefs)
+		nodes(e, n.Directives)
+		e.child(n.SelectionSet)
+		e.executableDescription(n.Description)
+	case *ast.FragmentDefinition:
+		e.tag("FragmentDefinition")
+		e.name(n.Name)
+		e.child(n.TypeCondition)
+		nodes(e, n.Directives)
+		e.child(n.SelectionSet)
+		e.executableDescription(n.Description)
+	case *ast.SchemaDefinition:
+		e.tag("SchemaDefinition")
+		e.description(n.Description)
+		nodes(e, n.Directives)
+		nodes(e, n.RootOperationDefs)
+	case *ast.SchemaExtension:
+		e.tag("SchemaExtension")
+		nodes(e, n.Directives)
+		nodes(e, n.RootOperationDefs)
+	case *ast.RootOperationTypeDefinition:
+		e.tag("RootOperationTypeDefinition")
+		e.str(string(n.OperationType))
+		e.child(n.Type)
+	case *ast.ScalarTypeDefinition:
+		e.tag("ScalarTypeDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Directives)
+	case *ast.ScalarTypeExtension:
+		e.tag("ScalarTypeExtension")
+		e.name(n.Name)
+		nodes(e, n.Directives)
+	case *ast.ObjectTypeDefinition:
+		e.tag("ObjectTypeDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Interfaces)
+		nodes(e, n.Directives)
+		nodes(e, n.Fields)
+	case *ast.ObjectTypeExtension:
+		e.tag("ObjectTypeExtension")
+		e.name(n.Name)
+		nodes(e, n.Interfaces)
+		nodes(e, n.Directives)
+		nodes(e, n.Fields)
+	case *ast.InterfaceTypeDefinition:
+		e.tag("InterfaceTypeDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Interfaces)
+		nodes(e, n.Directives)
+		nodes(e, n.Fields)
+	case *ast.InterfaceTypeExtension:
+		e.tag("InterfaceTypeExtension")
+		e.name(n.Name)
+		nodes(e, n.Interfaces)
+		nodes(e, n.Directives)
+		nodes(e, n.Fields)
+	case *ast.UnionTypeDefinition:
+		e.tag("UnionTypeDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Directives)
+		nodes(e, n.Types)
+	case *ast.UnionTypeExtension:
+		e.tag("UnionTypeExtension")
+		e.name(n.Name)
+		nodes(e, n.Directives)
+		nodes(e, n.Types)
+	case *ast.EnumTypeDefinition:
+		e.tag("EnumTypeDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Directives)
+		nodes(e, n.Values)
+	case *ast.EnumTypeExtension:
+		e.tag("EnumTypeExtension")
+		e.name(n.Name)
+		nodes(e, n.Directives)
+		nodes(e, n.Values)
+	case *ast.EnumValueDefinition:
+		e.tag("EnumValueDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Directives)
+	case *ast.InputObjectTypeDefinition:
+		e.tag("InputObjectTypeDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Directives)
+		nodes(e, n.Fields)
+	case *ast.InputObjectTypeExtension:
+		e.tag("InputObjectTypeExtension")
+		e.name(n.Name)
+		nodes(e, n.Directives)
+		nodes(e, n.Fields)
+	case *ast.FieldDefinition:
+		e.tag("FieldDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Arguments)
+		e.child(n.Type)
+		nodes(e, n.Directives)
+	case *ast.InputValueDefinition:
+		e.tag("InputValueDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		e.child(n.Type)
+		e.child(n.DefaultValue)
+		nodes(e, n.Directives)
+	case *ast.DirectiveDefinition:
+		e.tag("DirectiveDefinition")
+		e.description(n.Description)
+		e.name(n.Name)
+		nodes(e, n.Arguments)
+		e.bool(n.Repeatable)
+		nodes(e, n.Locations)
+	case *ast.SelectionSet:
+		e.tag("SelectionSet")
+		nodes(e, n.Selections)
+	case *ast.Field:
+		e.tag("Field")
+		e.name(n.Alias)
+		e.name(n.Name)
+		nodes(e, n.Arguments)
+		nodes(e, n.Directives)
+		e.child(n.SelectionSet)
+	case *ast.FragmentSpread:
+		e.tag("FragmentSpread")
+		e.name(n.Name)
+		nodes(e, n.Directives)
+	case *ast.InlineFragment:
+		e.tag("InlineFragment")
+		e.child(n.TypeCondition)
+		nodes(e, n.Directives)
+		e.child(n.SelectionSet)
+	case *ast.Directive:
+		e.tag("Directive")
+		e.name(n.Name)
+		nodes(e, n.Arguments)
+	case *ast.Argument:
+		e.tag("Argument")
+		e.name(n.Name)
+		e.child(n.Value)
+	case *ast.VariableDefinition:
+		e.tag("VariableDefinition")
+		e.child(n.Variable)
+		e.child(n.Type)
+		e.child(n.DefaultValue)
+		nodes(e, n.Directives)
+		e.executableDescription(n.Description)
+	case *ast.Variable:
+		e.tag("Variable")
+		e.name(n.Name)
+	case *ast.IntValue:
+		e.tag("IntValue")
+		e.str(n.Value)
+	case *ast.FloatValue:
+		e.tag("FloatValue")
+		e.str(n.Value)
+	case *ast.StringValue:
+		e.tag("StringValue")
+		e.str(n.Value)
+	case *ast.BooleanValue:
+		e.tag("BooleanValue")
+		e.bool(n.Value)
+	case *ast.NullValue:
+		e.tag("NullValue")
+	case *ast.EnumValue:
+		e.tag("EnumValue")
+		e.str(n.Value)
+	case *ast.ListValue:
+		e.tag("ListValue")
+		nodes(e, n.Values)
+	case *ast.ObjectValue:
+		e.tag("ObjectValue")
+		nodes(e, n.Fields)
+	case *ast.ObjectField:
+		e.tag("ObjectField")
+		e.name(n.Name)
+		e.child(n.Value)
+	case *ast.NamedType:
+		e.tag("NamedType")
+		e.name(n.Name)
+	case *ast.ListType:
+		e.tag("ListType")
+		e.child(n.Type)
+	case *ast.NonNullType:
+		e.tag("NonNullType")
+		e.child(n.Type)
+	case *ast.Name:
+		e.tag("Name")
+		e.str(n.Value)
+	case *ast.Description:
+		e.tag("Description")
+		e.str(n.Value)
+	default:
+		e.tag(reflect.TypeOf(n).String())
+	}
+}
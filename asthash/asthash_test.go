@@ -0,0 +1,66 @@
+package asthash_test
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asthash"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestDocument(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     string
+		b     string
+		equal bool
+	}{
+		{"Positions are ignored", `{ a(x: 1) { b } }`, "\n\n{\n  a(x: 1),\n  { b }\n}", true},
+		{"Block strings", `type A { a(x: String = "v"): Int }`, `type A { a(x: String = """v"""): Int }`, true},
+		{"Different names", `{ a }`, `{ b }`, false},
+		{"Alias", `{ a }`, `{ a: a }`, false},
+		{"Field order", `{ a b }`, `{ b a }`, false},
+		{"Value kinds", `{ a(x: 1) }`, `{ a(x: "1") }`, false},
+		{"Enum and string", `{ a(x: A) }`, `{ a(x: "A") }`, false},
+		{"Nullability", `type A { a: Int }`, `type A { a: Int! }`, false},
+		{"List type", `type A { a: [Int] }`, `type A { a: Int }`, false},
+		{"Repeatable", `directive @a on FIELD`, `directive @a repeatable on FIELD`, false},
+		{"Length-prefixed strings", `{ a(x: "ab", y: "c") }`, `{ a(x: "a", y: "bc") }`, false},
+		{"Missing optional node", `query { a }`, `query Q { a }`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := asthash.Document(asttest.Parse(t, tt.a)), asthash.Document(asttest.Parse(t, tt.b))
+			if (a == b) != tt.equal {
+				t.Errorf("expected equal hashes %v, got %s and %s", tt.equal, a, b)
+			}
+		})
+	}
+}
+
+func TestHasher_Cache(t *testing.T) {
+	doc := asttest.Parse(t, `query A { ...F } query B { ...F } fragment F on T { a { b } }`)
+	h := asthash.New()
+
+	first := h.Hash(doc.Definitions[0])
+	cached := h.Len()
+	if cached == 0 {
+		t.Fatalf("expected hashes to be cached")
+	}
+	if h.Hash(doc.Definitions[0]) != first || h.Len() != cached {
+		t.Errorf("expected cached hash to be reused")
+	}
+	if asthash.Hash(doc.Definitions[0]) != first {
+		t.Errorf("expected uncached hash to match cached one")
+	}
+
+	// Selection set of B is structurally identical, only operation name differs.
+	a := doc.Definitions[0].(*ast.OperationDefinition)
+	b := doc.Definitions[1].(*ast.OperationDefinition)
+	if h.Hash(a.SelectionSet) != h.Hash(b.SelectionSet) {
+		t.Errorf("expected identical selection sets to have equal hashes")
+	}
+	if h.Hash(a) == h.Hash(b) {
+		t.Errorf("expected operations to have different hashes")
+	}
+}
@@ -0,0 +1,78 @@
+// Package asttest provides property tests of parsing and printing, for
+// this module and for extensions producing or transforming ASTs:
+//
+//	func TestPrinter(t *testing.T) {
+//		asttest.Check(t, asttest.Generate(rand.New(rand.NewPCG(1, 2)), 1000))
+//	}
+//
+// Documents are compared structurally with asthash, ignoring positions and
+// whether strings are block strings.
+//
+// Parse and Schema are fixtures of tests working with parsed documents and
+// built schemas:
+//
+//	s := asttest.Schema(t, `type Query { user: User } type User { id: ID }`)
+//	doc := asttest.Parse(t, `{ user { id } }`)
+package asttest
+
+import (
+	"fmt"
+	"iter"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asthash"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// RoundTrip checks that parse(print(parse(input))) equals parse(input)
+// structurally, printing with opts. It returns error describing the first
+// failing step.
+func RoundTrip(input string, opts ...printer.Option) error {
+	doc, err := parse(input)
+	if err != nil {
+		return fmt.Errorf("parse input: %w", err)
+	}
+	return RoundTripDocument(doc, opts...)
+}
+
+// RoundTripDocument checks that parse(print(doc)) equals doc structurally,
+// printing with opts. Doc may be built by hand or generated, as positions
+// are ignored.
+func RoundTripDocument(doc *ast.Document, opts ...printer.Option) error {
+	printed := printer.PrintDocument(doc, opts...)
+	reparsed, err := parse(printed)
+	if err != nil {
+		return fmt.Errorf("parse printed document: %w\n%s", err, printed)
+	}
+	if asthash.Document(reparsed) != asthash.Document(doc) {
+		reprinted := printer.PrintDocument(reparsed, opts...)
+		if reprinted == printed {
+			return fmt.Errorf("printed document parses to a different document, printed the same:\n%s", printed)
+		}
+		return fmt.Errorf("printed document parses to a different document:\n%s\nwhich prints as:\n%s", printed, reprinted)
+	}
+	return nil
+}
+
+// Check runs RoundTrip for every input, reporting failures to t.
+func Check(t testing.TB, inputs iter.Seq[string], opts ...printer.Option) {
+	t.Helper()
+	i := 0
+	for input := range inputs {
+		if err := RoundTrip(input, opts...); err != nil {
+			t.Errorf("input %d: %v\ninput:\n%s", i, err, input)
+		}
+		i++
+	}
+}
+
+func parse(input string) (*ast.Document, error) {
+	p, err := parser.New(lexer.New(input))
+	if err != nil {
+		return nil, err
+	}
+	return p.ParseDocument()
+}
@@ -0,0 +1,69 @@
+package asttest
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+func TestRoundTrip(t *testing.T) {
+	Check(t, slices.Values([]string{
+		`query Q($id: ID! = "1" @d) @live { user(id: $id) { ...F ... on User @include(if: true) { name } } }`,
+		`fragment F on User { friends(first: 10, filter: {name: "a", tags: [A, B], score: -1.5e3}) { edges { node { id } } } }`,
+		`"""
+Description
+  with indentation
+"""
+type User implements Node & Entity @key(fields: "id") {
+  "Field description" id: ID!
+  friends(first: Int = 10, after: String): [User!]! @deprecated(reason: "Use connection.")
+}`,
+		`schema @link(url: "https://specs.apollo.dev/federation/v2.3") { query: Query mutation: Mutation }`,
+		`extend schema @tag(name: "x")`,
+		`directive @key(fields: String!, resolvable: Boolean = true) repeatable on OBJECT | INTERFACE`,
+		`enum Role { ADMIN @deprecated EDITOR } extend enum Role { VIEWER } union Result = | User | Post`,
+		`input In @oneOf { a: String "b" b: [[Int!]]! = [[1]] } extend input In { c: In }`,
+		`scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339") extend scalar Date @tag`,
+	}))
+}
+
+func TestRoundTrip_Errors(t *testing.T) {
+	if err := RoundTrip("{ a"); err == nil || !strings.HasPrefix(err.Error(), "parse input:") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+	// A field without name prints as invalid source.
+	doc := &ast.Document{Definitions: []ast.Definition{&ast.OperationDefinition{
+		OperationType: ast.OperationTypeQuery,
+		SelectionSet:  &ast.SelectionSet{Selections: []ast.Selection{&ast.Field{Name: &ast.Name{}}}},
+	}}}
+	if err := RoundTripDocument(doc); err == nil || !strings.HasPrefix(err.Error(), "parse printed document:") {
+		t.Errorf("expected error for unprintable document, got %v", err)
+	}
+	// Enum value true is printed as a boolean.
+	doc = &ast.Document{Definitions: []ast.Definition{&ast.OperationDefinition{
+		OperationType: ast.OperationTypeQuery,
+		SelectionSet: &ast.SelectionSet{Selections: []ast.Selection{&ast.Field{
+			Name:      &ast.Name{Value: "a"},
+			Arguments: []*ast.Argument{{Name: &ast.Name{Value: "b"}, Value: &ast.EnumValue{Value: "true"}}},
+		}}},
+	}}}
+	if err := RoundTripDocument(doc); err == nil || !strings.HasPrefix(err.Error(), "printed document parses to a different document, printed the same") {
+		t.Errorf("expected mismatch, got %v", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 500 {
+		doc := Document(r)
+		if err := RoundTripDocument(doc); err != nil {
+			t.Fatalf("generated document does not round-trip: %v", err)
+		}
+	}
+	Check(t, Generate(r, 500))
+	Check(t, Generate(r, 100), printer.WithStringEncoding(printer.StringsASCII), printer.WithUnicodeEscape(printer.EscapeVariableWidth))
+}
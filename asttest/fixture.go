@@ -0,0 +1,30 @@
+package asttest
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Parse returns document parsed from src, failing t when it does not
+// parse.
+func Parse(t testing.TB, src string) *ast.Document {
+	t.Helper()
+	doc, err := parse(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return doc
+}
+
+// Schema returns schema built with opts from SDL src, failing t when it
+// does not parse or build.
+func Schema(t testing.TB, src string, opts ...schema.Option) *schema.Schema {
+	t.Helper()
+	s, err := schema.FromDocument(Parse(t, src), opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return s
+}
@@ -0,0 +1,306 @@
+package asttest
+
+import (
+	"iter"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// Generate returns iterator over n random documents printed by Document.
+func Generate(r *rand.Rand, n int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for range n {
+			if !yield(printer.PrintDocument(Document(r))) {
+				return
+			}
+		}
+	}
+}
+
+// Document returns random syntactically valid document mixing executable
+// and type system definitions and extensions. Documents are not
+// necessarily valid against any schema.
+func Document(r *rand.Rand) *ast.Document {
+	g := &generator{r: r}
+	doc := &ast.Document{}
+	for i := range 1 + r.IntN(6) {
+		g.first = i == 0
+		doc.Definitions = append(doc.Definitions, g.definition())
+	}
+	return doc
+}
+
+type generator struct {
+	r     *rand.Rand
+	depth int  // Nesting of selection sets and values.
+	first bool // Generating the first definition.
+}
+
+var (
+	names   = []string{"a", "id", "name", "user", "query", "type", "on", "fragment", "Node", "_private", "x1", "extend"}
+	strs    = []string{"", "plain", `with "quotes"`, "back\\slash", "tab\there", "multi\nline", "unicode é 😀", "  indented\n  block", `"""`}
+	ints    = []string{"0", "-1", "42", "9007199254740993"}
+	floats  = []string{"1.5", "-0.25", "1e10", "2.5E-3", "0.0"}
+	opTypes = []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription}
+)
+
+func (g *generator) chance(n int) bool {
+	return g.r.IntN(n) == 0
+}
+
+func (g *generator) name() *ast.Name {
+	return &ast.Name{Value: names[g.r.IntN(len(names))]}
+}
+
+// fragmentName returns name other than "on", which fragments cannot have.
+func (g *generator) fragmentName() *ast.Name {
+	for {
+		if n := g.name(); n.Value != "on" {
+			return n
+		}
+	}
+}
+
+func (g *generator) namedType() *ast.NamedType {
+	return &ast.NamedType{Name: g.name()}
+}
+
+func (g *generator) definition() ast.Definition {
+	switch g.r.IntN(14) {
+	case 0, 1, 2:
+		return g.operation()
+	case 3:
+		return &ast.FragmentDefinition{Name: g.fragmentName(), TypeCondition: g.namedType(), Directives: g.directives(false), SelectionSet: g.selectionSet()}
+	case 4:
+		return &ast.SchemaDefinition{Description: g.description(), Directives: g.directives(true), RootOperationDefs: g.roots(1)}
+	case 5:
+		return &ast.ScalarTypeDefinition{Description: g.description(), Name: g.name(), Directives: g.directives(true)}
+	case 6:
+		return &ast.ObjectTypeDefinition{Description: g.description(), Name: g.name(), Interfaces: g.namedTypes(0), Directives: g.directives(true), Fields: g.fieldDefinitions()}
+	case 7:
+		return &ast.InterfaceTypeDefinition{Description: g.description(), Name: g.name(), Interfaces: g.namedTypes(0), Directives: g.directives(true), Fields: g.fieldDefinitions()}
+	case 8:
+		return &ast.UnionTypeDefinition{Description: g.description(), Name: g.name(), Directives: g.directives(true), Types: g.namedTypes(0)}
+	case 9:
+		return &ast.EnumTypeDefinition{Description: g.description(), Name: g.name(), Directives: g.directives(true), Values: g.enumValues()}
+	case 10:
+		return &ast.InputObjectTypeDefinition{Description: g.description(), Name: g.name(), Directives: g.directives(true), Fields: g.inputValues(0)}
+	case 11:
+		def := &ast.DirectiveDefinition{Description: g.description(), Name: g.name(), Arguments: g.inputValues(0), Repeatable: g.chance(2)}
+		for range 1 + g.r.IntN(3) {
+			locations := []ast.DirectiveLocation{ast.DirectiveLocationField, ast.DirectiveLocationQuery, ast.DirectiveLocationObject, ast.DirectiveLocationEnumValue}
+			def.Locations = append(def.Locations, &ast.Name{Value: string(locations[g.r.IntN(len(locations))])})
+		}
+		return def
+	case 12:
+		return g.extension()
+	}
+	return &ast.SchemaExtension{Directives: g.directives(true), RootOperationDefs: g.roots(1)}
+}
+
+func (g *generator) operation() *ast.OperationDefinition {
+	op := &ast.OperationDefinition{OperationType: opTypes[g.r.IntN(len(opTypes))], SelectionSet: g.selectionSet()}
+	if g.chance(2) {
+		op.Name = g.name()
+	}
+	for range g.r.IntN(3) {
+		def := &ast.VariableDefinition{Variable: &ast.Variable{Name: g.name()}, Type: g.typ(), Directives: g.directives(true)}
+		if g.chance(2) {
+			def.DefaultValue = g.value(true)
+		}
+		op.VariableDefs = append(op.VariableDefs, def)
+	}
+	op.Directives = g.directives(false)
+	if op.OperationType == ast.OperationTypeQuery && op.Name == nil && len(op.VariableDefs) == 0 && len(op.Directives) == 0 && !g.first {
+		// Printed as shorthand query, which could be read as fields of
+		// preceding type definition without fields.
+		op.Name = g.name()
+	}
+	return op
+}
+
+// extension returns type extension, which must extend something.
+func (g *generator) extension() ast.Definition {
+	directives := g.directives(true)
+	for len(directives) == 0 {
+		directives = g.directives(true)
+	}
+	switch g.r.IntN(6) {
+	case 0:
+		return &ast.ScalarTypeExtension{Name: g.name(), Directives: directives}
+	case 1:
+		return &ast.ObjectTypeExtension{Name: g.name(), Interfaces: g.namedTypes(0), Directives: directives, Fields: g.fieldDefinitions()}
+	case 2:
+		return &ast.InterfaceTypeExtension{Name: g.name(), Directives: directives, Fields: g.fieldDefinitions()}
+	case 3:
+		return &ast.UnionTypeExtension{Name: g.name(), Directives: directives, Types: g.namedTypes(0)}
+	case 4:
+		return &ast.EnumTypeExtension{Name: g.name(), Directives: directives, Values: g.enumValues()}
+	}
+	return &ast.InputObjectTypeExtension{Name: g.name(), Directives: directives, Fields: g.inputValues(0)}
+}
+
+func (g *generator) roots(minimum int) []*ast.RootOperationTypeDefinition {
+	var roots []*ast.RootOperationTypeDefinition
+	for i := range minimum + g.r.IntN(len(opTypes)-minimum+1) {
+		roots = append(roots, &ast.RootOperationTypeDefinition{OperationType: opTypes[i], Type: g.namedType()})
+	}
+	return roots
+}
+
+func (g *generator) namedTypes(minimum int) []*ast.NamedType {
+	var types []*ast.NamedType
+	for range minimum + g.r.IntN(3) {
+		types = append(types, g.namedType())
+	}
+	return types
+}
+
+func (g *generator) description() *ast.Description {
+	if !g.chance(3) {
+		return nil
+	}
+	return &ast.Description{Value: strs[g.r.IntN(len(strs))], Block: g.chance(2)}
+}
+
+func (g *generator) fieldDefinitions() []*ast.FieldDefinition {
+	var fields []*ast.FieldDefinition
+	for range g.r.IntN(4) {
+		fields = append(fields, &ast.FieldDefinition{Description: g.description(), Name: g.name(), Arguments: g.inputValues(0), Type: g.typ(), Directives: g.directives(true)})
+	}
+	return fields
+}
+
+func (g *generator) inputValues(minimum int) []*ast.InputValueDefinition {
+	var values []*ast.InputValueDefinition
+	for range minimum + g.r.IntN(3) {
+		v := &ast.InputValueDefinition{Description: g.description(), Name: g.name(), Type: g.typ(), Directives: g.directives(true)}
+		if g.chance(3) {
+			v.DefaultValue = g.value(true)
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func (g *generator) enumValues() []*ast.EnumValueDefinition {
+	var values []*ast.EnumValueDefinition
+	for range g.r.IntN(4) {
+		values = append(values, &ast.EnumValueDefinition{Description: g.description(), Name: g.enumName(), Directives: g.directives(true)})
+	}
+	return values
+}
+
+// enumName returns name valid as enum value.
+func (g *generator) enumName() *ast.Name {
+	return &ast.Name{Value: "V_" + g.name().Value}
+}
+
+func (g *generator) typ() ast.Type {
+	var t ast.Type = g.namedType()
+	for range g.r.IntN(3) {
+		if g.chance(2) {
+			t = &ast.ListType{Type: t}
+		}
+		if _, nonNull := t.(*ast.NonNullType); !nonNull && g.chance(2) {
+			t = &ast.NonNullType{Type: t}
+		}
+	}
+	return t
+}
+
+// directives returns directives with constant arguments when constant.
+func (g *generator) directives(constant bool) []*ast.Directive {
+	n := 0
+	if g.chance(3) {
+		n = 1 + g.r.IntN(2)
+	}
+	var directives []*ast.Directive
+	for range n {
+		directives = append(directives, &ast.Directive{Name: g.name(), Arguments: g.arguments(constant)})
+	}
+	return directives
+}
+
+func (g *generator) arguments(constant bool) []*ast.Argument {
+	var args []*ast.Argument
+	for range g.r.IntN(3) {
+		args = append(args, &ast.Argument{Name: g.name(), Value: g.value(constant)})
+	}
+	return args
+}
+
+func (g *generator) selectionSet() *ast.SelectionSet {
+	g.depth++
+	defer func() { g.depth-- }()
+	set := &ast.SelectionSet{}
+	for range 1 + g.r.IntN(3) {
+		set.Selections = append(set.Selections, g.selection())
+	}
+	return set
+}
+
+func (g *generator) selection() ast.Selection {
+	nested := g.depth < 4
+	switch {
+	case nested && g.chance(6):
+		f := &ast.InlineFragment{Directives: g.directives(false), SelectionSet: g.selectionSet()}
+		if g.chance(2) {
+			f.TypeCondition = g.namedType()
+		}
+		return f
+	case g.chance(6):
+		return &ast.FragmentSpread{Name: g.fragmentName(), Directives: g.directives(false)}
+	}
+	f := &ast.Field{Name: g.name(), Arguments: g.arguments(false), Directives: g.directives(false)}
+	if g.chance(4) {
+		f.Alias = g.name()
+	}
+	if nested && g.chance(3) {
+		f.SelectionSet = g.selectionSet()
+	}
+	return f
+}
+
+// value returns random value, without variables when constant.
+func (g *generator) value(constant bool) ast.Value {
+	g.depth++
+	defer func() { g.depth-- }()
+	kinds := 7
+	if g.depth < 4 {
+		kinds = 9
+	}
+	switch g.r.IntN(kinds) {
+	case 0:
+		return &ast.IntValue{Value: ints[g.r.IntN(len(ints))]}
+	case 1:
+		return &ast.FloatValue{Value: floats[g.r.IntN(len(floats))]}
+	case 2:
+		return &ast.StringValue{Value: strs[g.r.IntN(len(strs))], Block: g.chance(3)}
+	case 3:
+		return &ast.BooleanValue{Value: g.chance(2)}
+	case 4:
+		return &ast.NullValue{}
+	case 5:
+		return &ast.EnumValue{Value: g.enumName().Value}
+	case 6:
+		if constant {
+			return &ast.IntValue{Value: strconv.Itoa(g.r.IntN(10))}
+		}
+		return &ast.Variable{Name: g.name()}
+	case 7:
+		list := &ast.ListValue{}
+		for range g.r.IntN(3) {
+			list.Values = append(list.Values, g.value(constant))
+		}
+		return list
+	}
+	obj := &ast.ObjectValue{}
+	for range g.r.IntN(3) {
+		obj.Fields = append(obj.Fields, &ast.ObjectField{Name: g.name(), Value: g.value(constant)})
+	}
+	return obj
+}
@@ -0,0 +1,390 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// Operation builds an operation at runtime, for applications that cannot
+// use static query strings, e.g. query UIs:
+//
+//	op := client.NewOperation(ast.OperationTypeQuery, "Users")
+//	first := op.Var("first", "Int!", 10)
+//	op.Select(
+//		client.Field("users", client.Field("id"), client.Spread("UserFields")).
+//			Arg("first", first).
+//			Arg("role", client.Enum("ADMIN")),
+//	)
+//	op.Fragment("UserFields", "User", client.Field("name"))
+//	query, variables, err := op.Build()
+//
+// Argument values are Go values converted to literals: nil, booleans,
+// numbers, strings, Enum, Variable, slices and maps with string keys.
+// Errors, e.g. invalid names or undeclared variables, are reported by
+// Document and Build.
+type Operation struct {
+	typ        ast.OperationType
+	name       string
+	vars       []variableDecl
+	selections []Selection
+	fragments  []fragmentDecl
+}
+
+type variableDecl struct {
+	name, typ string
+	value     any
+}
+
+type fragmentDecl struct {
+	name, typeCondition string
+	selections          []Selection
+}
+
+// Variable refers to a variable declared with Operation.Var.
+type Variable string
+
+// Enum is an enum value argument.
+type Enum string
+
+// NewOperation returns builder of operation of type typ. Name may be empty
+// for anonymous operation.
+func NewOperation(typ ast.OperationType, name string) *Operation {
+	return &Operation{typ: typ, name: name}
+}
+
+// Var declares variable of GraphQL type typ, e.g. "[ID!]!", sent with
+// value, and returns reference to use as argument value.
+func (o *Operation) Var(name, typ string, value any) Variable {
+	o.vars = append(o.vars, variableDecl{name: name, typ: typ, value: value})
+	return Variable(name)
+}
+
+// Select adds selections to the operation.
+func (o *Operation) Select(selections ...Selection) *Operation {
+	o.selections = append(o.selections, selections...)
+	return o
+}
+
+// Fragment adds fragment definition, to be used with Spread.
+func (o *Operation) Fragment(name, typeCondition string, selections ...Selection) *Operation {
+	o.fragments = append(o.fragments, fragmentDecl{name: name, typeCondition: typeCondition, selections: selections})
+	return o
+}
+
+// Document returns document with the operation followed by fragments.
+func (o *Operation) Document() (*ast.Document, error) {
+	b := &opBuilder{declared: make(map[string]bool)}
+	op := &ast.OperationDefinition{OperationType: o.typ}
+	if o.name != "" {
+		op.Name = b.name(o.name)
+	}
+	for _, v := range o.vars {
+		if b.declared[v.name] {
+			b.fail(fmt.Errorf("variable $%s is declared more than once", v.name))
+		}
+		b.declared[v.name] = true
+		op.VariableDefs = append(op.VariableDefs, &ast.VariableDefinition{
+			Variable: &ast.Variable{Name: b.name(v.name)},
+			Type:     b.parseType(v.typ),
+		})
+	}
+	op.SelectionSet = b.selectionSet(o.selections)
+	doc := &ast.Document{Definitions: []ast.Definition{op}}
+	for _, f := range o.fragments {
+		doc.Definitions = append(doc.Definitions, &ast.FragmentDefinition{
+			Name:          b.name(f.name),
+			TypeCondition: &ast.NamedType{Name: b.name(f.typeCondition)},
+			SelectionSet:  b.selectionSet(f.selections),
+		})
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	return doc, nil
+}
+
+// Build returns printed operation and its variables.
+func (o *Operation) Build() (query string, variables map[string]any, err error) {
+	doc, err := o.Document()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(o.vars) > 0 {
+		variables = make(map[string]any, len(o.vars))
+		for _, v := range o.vars {
+			variables[v.name] = v.value
+		}
+	}
+	return printer.PrintDocument(doc), variables, nil
+}
+
+// Selection is a field, inline fragment or fragment spread.
+type Selection interface {
+	selection(b *opBuilder) ast.Selection
+}
+
+// FieldSelection is a field selection created by Field.
+type FieldSelection struct {
+	alias, name string
+	args        []argument
+	directives  []directive
+	selections  []Selection
+}
+
+type argument struct {
+	name  string
+	value any
+}
+
+type directive struct {
+	name string
+	args []argument
+}
+
+// Field returns field selection with subselections.
+func Field(name string, selections ...Selection) *FieldSelection {
+	return &FieldSelection{name: name, selections: selections}
+}
+
+// As sets alias of the field.
+func (f *FieldSelection) As(alias string) *FieldSelection {
+	f.alias = alias
+	return f
+}
+
+// Arg adds argument to the field.
+func (f *FieldSelection) Arg(name string, value any) *FieldSelection {
+	f.args = append(f.args, argument{name: name, value: value})
+	return f
+}
+
+// Directive adds directive to the field. Args are pairs of argument
+// names and values, e.g. Directive("include", "if", client.Variable("v")).
+func (f *FieldSelection) Directive(name string, args ...any) *FieldSelection {
+	f.directives = append(f.directives, newDirective(name, args))
+	return f
+}
+
+// Select adds subselections to the field.
+func (f *FieldSelection) Select(selections ...Selection) *FieldSelection {
+	f.selections = append(f.selections, selections...)
+	return f
+}
+
+func (f *FieldSelection) selection(b *opBuilder) ast.Selection {
+	field := &ast.Field{Name: b.name(f.name), Arguments: b.arguments(f.args), Directives: b.directives(f.directives)}
+	if f.alias != "" {
+		field.Alias = b.name(f.alias)
+	}
+	if len(f.selections) > 0 {
+		field.SelectionSet = b.selectionSet(f.selections)
+	}
+	return field
+}
+
+type inlineFragment struct {
+	typeCondition string
+	selections    []Selection
+}
+
+// On returns inline fragment with type condition, which may be empty.
+func On(typeCondition string, selections ...Selection) Selection {
+	return &inlineFragment{typeCondition: typeCondition, selections: selections}
+}
+
+func (f *inlineFragment) selection(b *opBuilder) ast.Selection {
+	fragment := &ast.InlineFragment{SelectionSet: b.selectionSet(f.selections)}
+	if f.typeCondition != "" {
+		fragment.TypeCondition = &ast.NamedType{Name: b.name(f.typeCondition)}
+	}
+	return fragment
+}
+
+type fragmentSpread struct {
+	name string
+}
+
+// Spread returns spread of fragment added with Operation.Fragment.
+func Spread(name string) Selection {
+	return &fragmentSpread{name: name}
+}
+
+func (s *fragmentSpread) selection(b *opBuilder) ast.Selection {
+	return &ast.FragmentSpread{Name: b.name(s.name)}
+}
+
+func newDirective(name string, args []any) directive {
+	d := directive{name: name}
+	for i := 0; i+1 < len(args); i += 2 {
+		argName, _ := args[i].(string)
+		d.args = append(d.args, argument{name: argName, value: args[i+1]})
+	}
+	if len(args)%2 != 0 {
+		// Reported by opBuilder as argument with empty name.
+		d.args = append(d.args, argument{})
+	}
+	return d
+}
+
+// opBuilder converts builders to AST, remembering the first error.
+type opBuilder struct {
+	declared map[string]bool
+	err      error
+}
+
+func (b *opBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = fmt.Errorf("graphql: build operation: %w", err)
+	}
+}
+
+func (b *opBuilder) name(value string) *ast.Name {
+	if !isName(value) {
+		b.fail(fmt.Errorf("invalid name %q", value))
+	}
+	return &ast.Name{Value: value}
+}
+
+func (b *opBuilder) selectionSet(selections []Selection) *ast.SelectionSet {
+	if len(selections) == 0 {
+		b.fail(fmt.Errorf("empty selection set"))
+	}
+	set := &ast.SelectionSet{}
+	for _, s := range selections {
+		set.Selections = append(set.Selections, s.selection(b))
+	}
+	return set
+}
+
+func (b *opBuilder) arguments(args []argument) []*ast.Argument {
+	var result []*ast.Argument
+	for _, arg := range args {
+		result = append(result, &ast.Argument{Name: b.name(arg.name), Value: b.value(arg.value)})
+	}
+	return result
+}
+
+func (b *opBuilder) directives(directives []directive) []*ast.Directive {
+	var result []*ast.Directive
+	for _, d := range directives {
+		result = append(result, &ast.Directive{Name: b.name(d.name), Arguments: b.arguments(d.args)})
+	}
+	return result
+}
+
+// parseType parses variable type.
+func (b *opBuilder) parseType(typ string) ast.Type {
+	p, err := parser.New(lexer.New("query ($v: " + typ + ") { a }"))
+	if err == nil {
+		var doc *ast.Document
+		if doc, err = p.ParseDocument(); err == nil && len(doc.Definitions) == 1 {
+			if op, ok := doc.Definitions[0].(*ast.OperationDefinition); ok && len(op.VariableDefs) == 1 && op.VariableDefs[0].DefaultValue == nil {
+				return op.VariableDefs[0].Type
+			}
+		}
+	}
+	b.fail(fmt.Errorf("invalid type %q", typ))
+	return &ast.NamedType{Name: &ast.Name{Value: typ}}
+}
+
+// value converts Go value to literal.
+func (b *opBuilder) value(v any) ast.Value {
+	switch v := v.(type) {
+	case nil:
+		return &ast.NullValue{}
+	case Variable:
+		if !b.declared[string(v)] {
+			b.fail(fmt.Errorf("variable $%s is not declared", v))
+		}
+		return &ast.Variable{Name: b.name(string(v))}
+	case Enum:
+		if v == "true" || v == "false" || v == "null" {
+			b.fail(fmt.Errorf("invalid enum value %q", v))
+		}
+		return &ast.EnumValue{Value: b.name(string(v)).Value}
+	case ast.Value:
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return &ast.BooleanValue{Value: rv.Bool()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &ast.IntValue{Value: strconv.FormatInt(rv.Int(), 10)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &ast.IntValue{Value: strconv.FormatUint(rv.Uint(), 10)}
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			b.fail(fmt.Errorf("invalid float value %v", f))
+		}
+		if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+			return &ast.IntValue{Value: strconv.FormatFloat(f, 'f', -1, 64)}
+		}
+		return &ast.FloatValue{Value: strconv.FormatFloat(f, 'g', -1, 64)}
+	case reflect.String:
+		return &ast.StringValue{Value: rv.String()}
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return &ast.NullValue{}
+		}
+		list := &ast.ListValue{}
+		for i := 0; i < rv.Len(); i++ {
+			list.Values = append(list.Values, b.value(rv.Index(i).Interface()))
+		}
+		return list
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if rv.IsNil() {
+			return &ast.NullValue{}
+		}
+		// Sort keys so that built operations are stable.
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		slices.Sort(keys)
+		obj := &ast.ObjectValue{}
+		for _, k := range keys {
+			obj.Fields = append(obj.Fields, &ast.ObjectField{
+				Name:  b.name(k),
+				Value: b.value(rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface()),
+			})
+		}
+		return obj
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return &ast.NullValue{}
+		}
+		return b.value(rv.Elem().Interface())
+	}
+	b.fail(fmt.Errorf("unsupported argument value of type %T", v))
+	return &ast.NullValue{}
+}
+
+// isName reports whether s is a valid GraphQL name.
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, ch := range s {
+		switch {
+		case ch == '_', ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z':
+		case ch >= '0' && ch <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
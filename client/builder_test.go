@@ -0,0 +1,96 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+func TestOperation_Build(t *testing.T) {
+	op := NewOperation(ast.OperationTypeQuery, "Users")
+	first := op.Var("first", "Int!", 10)
+	withEmail := op.Var("withEmail", "Boolean", false)
+	op.Select(
+		Field("users",
+			Field("id"),
+			Field("email").Directive("include", "if", withEmail),
+			On("Admin", Field("level")),
+			Spread("UserFields"),
+		).
+			As("list").
+			Arg("first", first).
+			Arg("role", Enum("ADMIN")).
+			Arg("filter", map[string]any{"tags": []string{"a", "b"}, "score": 1.5, "active": true, "parent": nil}),
+	)
+	op.Fragment("UserFields", "User", Field("name"))
+
+	query, variables, err := op.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `query Users($first: Int!, $withEmail: Boolean) {
+  list: users(
+    first: $first
+    role: ADMIN
+    filter: {active: true, parent: null, score: 1.5, tags: ["a", "b"]}
+  ) {
+    id
+    email @include(if: $withEmail)
+    ... on Admin {
+      level
+    }
+    ...UserFields
+  }
+}
+
+fragment UserFields on User {
+  name
+}`
+	if strings.TrimSpace(query) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, query)
+	}
+	if !reflect.DeepEqual(variables, map[string]any{"first": 10, "withEmail": false}) {
+		t.Errorf("unexpected variables %v", variables)
+	}
+}
+
+func TestOperation_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() *Operation
+		expected string
+	}{
+		{"Invalid name", func() *Operation {
+			return NewOperation(ast.OperationTypeQuery, "").Select(Field("user-name"))
+		}, `invalid name "user-name"`},
+		{"Undeclared variable", func() *Operation {
+			return NewOperation(ast.OperationTypeQuery, "").Select(Field("user").Arg("id", Variable("id")))
+		}, "variable $id is not declared"},
+		{"Duplicate variable", func() *Operation {
+			op := NewOperation(ast.OperationTypeQuery, "")
+			op.Var("id", "ID", 1)
+			op.Var("id", "ID", 2)
+			return op.Select(Field("user"))
+		}, "variable $id is declared more than once"},
+		{"Invalid type", func() *Operation {
+			op := NewOperation(ast.OperationTypeQuery, "")
+			op.Var("id", "[ID", 1)
+			return op.Select(Field("user"))
+		}, `invalid type "[ID"`},
+		{"Empty selection set", func() *Operation {
+			return NewOperation(ast.OperationTypeMutation, "")
+		}, "empty selection set"},
+		{"Unsupported value", func() *Operation {
+			return NewOperation(ast.OperationTypeQuery, "").Select(Field("user").Arg("id", struct{}{}))
+		}, "unsupported argument value of type struct {}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.build().Build(); err == nil || !strings.HasSuffix(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
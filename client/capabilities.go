@@ -0,0 +1,57 @@
+package client
+
+import (
+	"github.com/gqlhub/gqlhub-core/link"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// federationIdentity is identity of Apollo Federation specification.
+const federationIdentity = "https://specs.apollo.dev/federation"
+
+// Capabilities are features of a remote endpoint detected from its schema.
+type Capabilities struct {
+	Defer       bool // @defer directive is defined.
+	Stream      bool // @stream directive is defined.
+	OneOf       bool // @oneOf directive is defined.
+	SpecifiedBy bool // @specifiedBy directive is defined.
+
+	// Subscriptions reports whether schema has subscription root type.
+	// Schemas do not describe transports, so the transport (e.g.
+	// graphql-ws or SSE) still has to be negotiated with the endpoint.
+	Subscriptions bool
+
+	// FederationVersion is version of Apollo Federation the schema is a
+	// subgraph of, e.g. "v2.3", or "v1" for subgraphs not using @link.
+	// Empty for schemas that are not subgraphs.
+	FederationVersion string
+}
+
+// ProbeCapabilities inspects fetched remote schema s, e.g. built from SDL
+// or introspection, and reports its capabilities.
+func ProbeCapabilities(s *schema.Schema) Capabilities {
+	c := Capabilities{
+		Defer:       s.Directive("defer") != nil,
+		Stream:      s.Directive("stream") != nil,
+		OneOf:       s.Directive("oneOf") != nil,
+		SpecifiedBy: s.Directive("specifiedBy") != nil,
+	}
+
+	c.Subscriptions = s.SubscriptionType() != nil
+
+	if links, err := link.FromDirectives(s.Directives); err == nil {
+		if l, ok := links.ByIdentity(federationIdentity); ok {
+			c.FederationVersion = l.URL.Version.String()
+		}
+	}
+	if c.FederationVersion == "" && isFederationV1(s) {
+		c.FederationVersion = "v1"
+	}
+	return c
+}
+
+// isFederationV1 reports whether schema exposes subgraph fields of
+// Federation 1, which predates @link.
+func isFederationV1(s *schema.Schema) bool {
+	q := s.QueryType()
+	return (q != nil && q.Field("_service") != nil) || s.Type("_Service") != nil
+}
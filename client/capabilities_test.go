@@ -0,0 +1,49 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		sdl      string
+		expected Capabilities
+	}{
+		{
+			name:     "plain",
+			sdl:      `type Query { a: Int }`,
+			expected: Capabilities{},
+		},
+		{
+			name: "incremental delivery and oneOf",
+			sdl: `type Query { a: Int } type Subscription { b: Int }
+directive @defer(label: String, if: Boolean! = true) on FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @stream(label: String, if: Boolean! = true, initialCount: Int = 0) on FIELD
+directive @oneOf on INPUT_OBJECT
+directive @specifiedBy(url: String!) on SCALAR`,
+			expected: Capabilities{Defer: true, Stream: true, OneOf: true, SpecifiedBy: true, Subscriptions: true},
+		},
+		{
+			name: "federation 2",
+			sdl: `extend schema @link(url: "https://specs.apollo.dev/link/v1.0")
+  @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key"])
+type Query { a: Int }`,
+			expected: Capabilities{FederationVersion: "v2.3"},
+		},
+		{
+			name:     "federation 1",
+			sdl:      `type Query { _service: _Service! } type _Service { sdl: String }`,
+			expected: Capabilities{FederationVersion: "v1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProbeCapabilities(asttest.Schema(t, tt.sdl)); got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// Client sends GraphQL requests to an endpoint over HTTP, combining
+// Requester with optional local validation and resilience policy:
+//
+//	c := client.New("https://example.com/graphql",
+//		client.WithHeader("Authorization", "Bearer "+token),
+//		client.WithGET(),
+//		client.WithPersistedQueries(),
+//	)
+//	var result struct {
+//		User struct{ Name string } `json:"user"`
+//	}
+//	op := client.NewOperation(ast.OperationTypeQuery, "User")
+//	op.Select(client.Field("user", client.Field("name")).Arg("id", op.Var("id", "ID!", "1")))
+//	err := c.Run(ctx, op, &result)
+//
+// A Client is safe for concurrent use.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+	requester  Requester
+	policy     *Policy
+	validator  *Validator
+}
+
+// Option configures Client.
+type Option func(*Client)
+
+// WithHTTPClient sets HTTP client sending requests. Default is
+// http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithHeader adds header sent with every request. It may be given more
+// than once, also for the same key.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.requester.Header == nil {
+			c.requester.Header = make(http.Header)
+		}
+		c.requester.Header.Add(key, value)
+	}
+}
+
+// WithGET sends queries with GET, so that CDNs can cache them, unless the
+// URL would be too long. Mutations are always sent with POST.
+func WithGET() Option {
+	return func(c *Client) {
+		c.requester.UseGET = true
+	}
+}
+
+// WithMaxURLLength sets the longest URL of queries sent with GET. Default
+// is DefaultMaxURLLength.
+func WithMaxURLLength(length int) Option {
+	return func(c *Client) {
+		c.requester.MaxURLLength = length
+	}
+}
+
+// WithPersistedQueries enables automatic persisted queries, see
+// Requester.PersistedQueries.
+func WithPersistedQueries() Option {
+	return func(c *Client) {
+		c.requester.PersistedQueries = true
+	}
+}
+
+// WithPolicy applies timeouts, retries, hedging and circuit breaking of p
+// to requests.
+func WithPolicy(p *Policy) Option {
+	return func(c *Client) {
+		c.policy = p
+	}
+}
+
+// WithValidator checks operations with v before sending them. Invalid
+// operations fail with *ValidationError.
+func WithValidator(v *Validator) Option {
+	return func(c *Client) {
+		c.validator = v
+	}
+}
+
+// New returns client of GraphQL endpoint. By default requests are sent
+// with POST by http.DefaultClient.
+func New(endpoint string, opts ...Option) *Client {
+	c := &Client{endpoint: endpoint, httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Do sends req and decodes the response. Errors are classified as by
+// Response.Err; the response is returned along with *PartialDataError and
+// *RequestError.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	if c.validator != nil {
+		if err := c.validator.Validate(req.Query, req.OperationName, req.Variables); err != nil {
+			return nil, err
+		}
+	}
+	attempt := func(ctx context.Context) (*Response, error) {
+		return c.requester.Do(ctx, c.httpClient, c.endpoint, req)
+	}
+	if c.policy == nil {
+		return attempt(ctx)
+	}
+	opType, err := OperationTypeOf(req.Query, req.OperationName)
+	if err != nil {
+		// Not retried, as if it was a mutation; the server reports the
+		// syntax error.
+		opType = ast.OperationTypeMutation
+	}
+	return c.policy.Do(ctx, opType, attempt)
+}
+
+// Query sends query with variables and decodes its data into v. Errors
+// are those of Do and Response.Decode.
+func (c *Client) Query(ctx context.Context, query string, variables map[string]any, v any) error {
+	return c.decode(ctx, &Request{Query: query, Variables: variables}, v)
+}
+
+// Run sends operation built by op and decodes its data into v. Errors are
+// those of Operation.Build, Do and Response.Decode.
+func (c *Client) Run(ctx context.Context, op *Operation, v any) error {
+	query, variables, err := op.Build()
+	if err != nil {
+		return err
+	}
+	return c.decode(ctx, &Request{Query: query, OperationName: op.name, Variables: variables}, v)
+}
+
+func (c *Client) decode(ctx context.Context, req *Request, v any) error {
+	resp, err := c.Do(ctx, req)
+	if resp == nil {
+		return err
+	}
+	return resp.Decode(v)
+}
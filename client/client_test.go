@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+// echoServer responds with request it received as data.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if r.Method == http.MethodGet {
+			req.Query = r.URL.Query().Get("query")
+			req.OperationName = r.URL.Query().Get("operationName")
+			json.Unmarshal([]byte(r.URL.Query().Get("variables")), &req.Variables)
+		} else {
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &req)
+		}
+		data, _ := json.Marshal(map[string]any{
+			"method":        r.Method,
+			"authorization": r.Header.Values("Authorization"),
+			"query":         req.Query,
+			"operationName": req.OperationName,
+			"variables":     req.Variables,
+		})
+		w.Write([]byte(`{"data": ` + string(data) + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+type echo struct {
+	Method        string         `json:"method"`
+	Authorization []string       `json:"authorization"`
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func TestClient_Run(t *testing.T) {
+	srv := echoServer(t)
+	tests := []struct {
+		name     string
+		opts     []Option
+		op       func() *Operation
+		expected echo
+	}{
+		{
+			name: "POST",
+			opts: []Option{WithHeader("Authorization", "Bearer a"), WithHeader("Authorization", "Bearer b")},
+			op: func() *Operation {
+				op := NewOperation(ast.OperationTypeQuery, "User")
+				op.Select(Field("user", Field("name")).Arg("id", op.Var("id", "ID!", "1")))
+				return op
+			},
+			expected: echo{
+				Method:        http.MethodPost,
+				Authorization: []string{"Bearer a", "Bearer b"},
+				Query:         "query User($id: ID!) {\n  user(id: $id) {\n    name\n  }\n}",
+				OperationName: "User",
+				Variables:     map[string]any{"id": "1"},
+			},
+		},
+		{
+			name: "GET",
+			opts: []Option{WithGET()},
+			op: func() *Operation {
+				return NewOperation(ast.OperationTypeQuery, "").Select(Field("a"))
+			},
+			expected: echo{Method: http.MethodGet, Query: "{\n  a\n}"},
+		},
+		{
+			name: "GET of mutation",
+			opts: []Option{WithGET()},
+			op: func() *Operation {
+				return NewOperation(ast.OperationTypeMutation, "M").Select(Field("a"))
+			},
+			expected: echo{Method: http.MethodPost, Query: "mutation M {\n  a\n}", OperationName: "M"},
+		},
+		{
+			name: "long URL",
+			opts: []Option{WithGET(), WithMaxURLLength(10)},
+			op: func() *Operation {
+				return NewOperation(ast.OperationTypeQuery, "").Select(Field("a"))
+			},
+			expected: echo{Method: http.MethodPost, Query: "{\n  a\n}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got echo
+			if err := New(srv.URL, tt.opts...).Run(context.Background(), tt.op(), &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			expectedJSON, _ := json.Marshal(tt.expected)
+			if string(gotJSON) != string(expectedJSON) {
+				t.Errorf("expected %s, got %s", expectedJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestClient_Query(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"user": {"name": "A", "avatar": null}}, "errors": [{"message": "unavailable", "path": ["user", "avatar"]}]}`))
+	}))
+	defer srv.Close()
+
+	var result struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+	err := New(srv.URL).Query(context.Background(), "{ user { name avatar } }", nil, &result)
+	var partial *PartialDataError
+	if !errors.As(err, &partial) || !partial.Failed("user", "avatar") {
+		t.Fatalf("expected partial data error at user.avatar, got %v", err)
+	}
+	if result.User.Name != "A" {
+		t.Errorf("expected name A, got %q", result.User.Name)
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data": {"a": 1}}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithPolicy(&Policy{Retry: &RetryPolicy{MaxAttempts: 2}}), WithHTTPClient(srv.Client()))
+	resp, err := c.Do(context.Background(), &Request{Query: "{ a }"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Data) != `{"a": 1}` || attempts.Load() != 2 {
+		t.Errorf("unexpected result %s after %d attempts", resp.Data, attempts.Load())
+	}
+
+	attempts.Store(0)
+	_, err = c.Do(context.Background(), &Request{Query: "mutation { a }"})
+	var te *TransportError
+	if !errors.As(err, &te) || te.StatusCode != http.StatusServiceUnavailable || attempts.Load() != 1 {
+		t.Errorf("expected single attempt failing with 503, got %v after %d attempts", err, attempts.Load())
+	}
+}
+
+func TestClient_Validator(t *testing.T) {
+	srv := echoServer(t)
+	v := &Validator{Schema: asttest.Schema(t, `type Query { a: Int }`)}
+	c := New(srv.URL, WithValidator(v))
+	var got echo
+	if err := c.Query(context.Background(), "{ a }", nil, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := c.Query(context.Background(), "{ b }", nil, &got)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
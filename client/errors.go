@@ -0,0 +1,246 @@
+// Package client implements a GraphQL-over-HTTP client, see New, and
+// contains its building blocks: response envelope decoding, error
+// classification and resilience policies.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/response"
+)
+
+// Location of GraphQL error in request document.
+type Location = response.Location
+
+// Path of response field an error refers to. Elements are either string
+// field names (response keys) or int list indices.
+type Path = response.Path
+
+// Error is a GraphQL error as defined by the response format, the same
+// type servers write with package response.
+type Error = response.Error
+
+// ErrorList is a list of GraphQL errors.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "graphql: no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0].Error(), len(l)-1)
+}
+
+// At returns errors whose path is equal to or nested under given path.
+func (l ErrorList) At(path ...any) ErrorList {
+	var result ErrorList
+	for _, e := range l {
+		if e.Path.HasPrefix(path) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Failed reports whether any error refers to given path or its children.
+func (l ErrorList) Failed(path ...any) bool {
+	for _, e := range l {
+		if e.Path.HasPrefix(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransportError is returned when server could not be reached or did not
+// reply with a GraphQL response.
+type TransportError struct {
+	StatusCode int    // 0 if request did not produce HTTP response.
+	Body       []byte // Response body if available.
+	Err        error
+}
+
+func (e *TransportError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("graphql: transport error: status %d: %v", e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("graphql: transport error: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// RequestError is returned when response contains errors but no data,
+// meaning the request failed as a whole (e.g. validation failed).
+type RequestError struct {
+	Errors ErrorList
+}
+
+func (e *RequestError) Error() string {
+	return e.Errors.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Errors
+}
+
+// PartialDataError is returned when response contains both data and field
+// errors. Decoded data is still usable except for failed fields, which can
+// be checked with Failed or At.
+type PartialDataError struct {
+	Errors ErrorList
+}
+
+func (e *PartialDataError) Error() string {
+	return "graphql: partial data: " + e.Errors.Error()
+}
+
+func (e *PartialDataError) Unwrap() error {
+	return e.Errors
+}
+
+// Failed reports whether field at given response path (or any of its
+// children) failed.
+func (e *PartialDataError) Failed(path ...any) bool {
+	return e.Errors.Failed(path...)
+}
+
+// At returns errors for field at given response path and its children.
+func (e *PartialDataError) At(path ...any) ErrorList {
+	return e.Errors.At(path...)
+}
+
+// Response is the GraphQL response envelope.
+//
+// https://spec.graphql.org/draft/#sec-Response-Format
+type Response struct {
+	Data       json.RawMessage `json:"data,omitempty"`
+	Errors     ErrorList       `json:"errors,omitempty"`
+	Extensions map[string]any  `json:"extensions,omitempty"`
+}
+
+// HasData reports whether response contains non-null data.
+func (r *Response) HasData() bool {
+	return len(r.Data) > 0 && !bytes.Equal(bytes.TrimSpace(r.Data), []byte("null"))
+}
+
+// Err classifies response errors. It returns nil when response has no
+// errors, *RequestError when there is no data and *PartialDataError when
+// data is accompanied by errors.
+func (r *Response) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	if !r.HasData() {
+		return &RequestError{Errors: r.Errors}
+	}
+	return &PartialDataError{Errors: r.Errors}
+}
+
+// Decode unmarshals response data into v and returns result of Err.
+// With *PartialDataError, v is populated with data that was resolved.
+func (r *Response) Decode(v any) error {
+	if r.HasData() {
+		if err := json.Unmarshal(r.Data, v); err != nil {
+			return fmt.Errorf("graphql: decode data: %w", err)
+		}
+	}
+	return r.Err()
+}
+
+// DecodeResponse decodes HTTP response body into GraphQL response.
+// A non-2xx status is reported as *TransportError unless body is a
+// well-formed GraphQL response, which servers using
+// application/graphql-response+json send along with 4xx/5xx statuses.
+func DecodeResponse(statusCode int, body []byte) (*Response, error) {
+	var resp Response
+	err := json.Unmarshal(body, &resp)
+	isResponse := err == nil && (resp.Data != nil || resp.Errors != nil)
+
+	if statusCode < 200 || statusCode > 299 {
+		if !isResponse {
+			return nil, &TransportError{StatusCode: statusCode, Body: body, Err: errors.New("unexpected status")}
+		}
+		return &resp, nil
+	}
+	if err != nil {
+		return nil, &TransportError{StatusCode: statusCode, Body: body, Err: fmt.Errorf("invalid response body: %w", err)}
+	}
+	if !isResponse {
+		return nil, &TransportError{StatusCode: statusCode, Body: body, Err: errors.New("response has neither data nor errors")}
+	}
+	return &resp, nil
+}
+
+// FieldPath maps response path onto Go struct v (or pointer to it) that
+// data was decoded into, using json tags the same way encoding/json does.
+// It returns Go selector such as "User.Friends[0].Name".
+func FieldPath(v any, path Path) (string, error) {
+	var b strings.Builder
+	t := reflect.TypeOf(v)
+	for _, elem := range path {
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil {
+			return "", fmt.Errorf("graphql: cannot map path %s onto nil", path)
+		}
+		switch e := elem.(type) {
+		case int:
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+				return "", fmt.Errorf("graphql: path %s: index %d on non-list type %s", path, e, t)
+			}
+			fmt.Fprintf(&b, "[%d]", e)
+			t = t.Elem()
+		case string:
+			if t.Kind() != reflect.Struct {
+				return "", fmt.Errorf("graphql: path %s: field %q on non-struct type %s", path, e, t)
+			}
+			f, ok := fieldByJSONName(t, e)
+			if !ok {
+				return "", fmt.Errorf("graphql: path %s: no field for %q in %s", path, e, t)
+			}
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(f.Name)
+			t = f.Type
+		default:
+			return "", fmt.Errorf("graphql: path %s: invalid element %v", path, elem)
+		}
+	}
+	return b.String(), nil
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	var folded bool
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if tag == name {
+			return f, true
+		}
+		if !folded && strings.EqualFold(tag, name) {
+			fold, folded = f, true
+		}
+	}
+	return fold, folded
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+const partialBody = `{
+  "data": {"hero": {"name": "R2-D2", "friends": [{"name": "Luke"}, null]}},
+  "errors": [{"message": "Name for character with ID 1002 could not be fetched.", "locations": [{"line": 6, "column": 7}], "path": ["hero", "friends", 1, "name"]}]
+}`
+
+type heroData struct {
+	Hero struct {
+		Name    string `json:"name"`
+		Friends []*struct {
+			Name string `json:"name"`
+		} `json:"friends"`
+	} `json:"hero"`
+}
+
+func TestDecodeResponse_PartialData(t *testing.T) {
+	resp, err := DecodeResponse(200, []byte(partialBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var data heroData
+	err = resp.Decode(&data)
+	var partial *PartialDataError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *PartialDataError, got %v", err)
+	}
+	if data.Hero.Name != "R2-D2" || data.Hero.Friends[0].Name != "Luke" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+	if !partial.Failed("hero", "friends", 1) {
+		t.Errorf("expected hero.friends[1] to fail")
+	}
+	if partial.Failed("hero", "friends", 0) || partial.Failed("hero", "name") {
+		t.Errorf("expected other fields not to fail")
+	}
+	if got := len(partial.At("hero")); got != 1 {
+		t.Errorf("expected 1 error under hero, got %d", got)
+	}
+
+	e := partial.Errors[0]
+	if e.Path.String() != "hero.friends[1].name" {
+		t.Errorf("unexpected path %s", e.Path)
+	}
+	goPath, err := FieldPath(&data, e.Path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if goPath != "Hero.Friends[1].Name" {
+		t.Errorf("unexpected Go path %s", goPath)
+	}
+}
+
+func TestDecodeResponse_RequestError(t *testing.T) {
+	body := `{"errors": [{"message": "Cannot query field \"foo\" on type \"Query\"."}]}`
+	resp, err := DecodeResponse(400, []byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var reqErr *RequestError
+	if err := resp.Decode(&heroData{}); !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequestError, got %v", err)
+	}
+	if reqErr.Errors[0].IsFieldError() {
+		t.Errorf("expected request error not to be field error")
+	}
+}
+
+func TestDecodeResponse_TransportError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"Bad gateway", 502, "<html>Bad Gateway</html>"},
+		{"Invalid JSON", 200, "not json"},
+		{"Not a GraphQL response", 200, `{"status": "ok"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeResponse(tt.status, []byte(tt.body))
+			var transportErr *TransportError
+			if !errors.As(err, &transportErr) {
+				t.Fatalf("expected *TransportError, got %v", err)
+			}
+			if transportErr.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, transportErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestResponse_NoErrors(t *testing.T) {
+	resp, err := DecodeResponse(200, []byte(`{"data": {"hero": {"name": "R2-D2"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var data heroData
+	if err := resp.Decode(&data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Hero.Name != "R2-D2" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// PageFunc fetches a page of a connection starting after cursor, which is
+// nil for the first page. Typically it sends a query with cursor as the
+// after argument.
+type PageFunc func(ctx context.Context, after *string) (*Response, error)
+
+// PageInfo is the pageInfo field of a connection.
+//
+// https://relay.dev/graphql/connections.htm#sec-undefined.PageInfo
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+// Paginator configures Paginate.
+type Paginator struct {
+	// Path is response path of the connection field, e.g.
+	// []string{"repository", "issues"}. The connection must select edges
+	// and pageInfo { hasNextPage endCursor }.
+	Path []string
+
+	// MaxPages limits number of fetched pages. Zero means no limit.
+	MaxPages int
+
+	// Retry configures backoff of pages failed due to rate limiting. Its
+	// Retryable defaults to RateLimited. Pages are not retried when nil.
+	Retry *RetryPolicy
+}
+
+// Paginate fetches all pages of a connection with fetch, following
+// pageInfo.endCursor until hasNextPage is false, and returns edges of all
+// pages decoded as T. On error, including cancellation of ctx, edges
+// fetched so far are returned along with the error.
+func Paginate[T any](ctx context.Context, p *Paginator, fetch PageFunc) ([]T, error) {
+	var policy Policy
+	if p.Retry != nil {
+		retry := *p.Retry
+		if retry.Retryable == nil {
+			retry.Retryable = RateLimited
+		}
+		policy.Retry = &retry
+	}
+
+	var (
+		edges []T
+		after *string
+	)
+	for page := 0; p.MaxPages == 0 || page < p.MaxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return edges, err
+		}
+		resp, err := policy.Do(ctx, ast.OperationTypeQuery, func(ctx context.Context) (*Response, error) {
+			return fetch(ctx, after)
+		})
+		if err != nil {
+			return edges, err
+		}
+		var conn struct {
+			Edges    []T       `json:"edges"`
+			PageInfo *PageInfo `json:"pageInfo"`
+		}
+		if err := decodeAt(resp.Data, p.Path, &conn); err != nil {
+			return edges, err
+		}
+		edges = append(edges, conn.Edges...)
+		switch {
+		case conn.PageInfo == nil:
+			return edges, errors.New("graphql: paginate: connection has no pageInfo")
+		case !conn.PageInfo.HasNextPage:
+			return edges, nil
+		case conn.PageInfo.EndCursor == nil:
+			return edges, errors.New("graphql: paginate: next page has no cursor")
+		case after != nil && *after == *conn.PageInfo.EndCursor:
+			// Guard against servers returning the same page forever.
+			return edges, fmt.Errorf("graphql: paginate: cursor %q did not advance", *after)
+		}
+		after = conn.PageInfo.EndCursor
+	}
+	return edges, nil
+}
+
+// decodeAt unmarshals value at path in data into v.
+func decodeAt(data json.RawMessage, path []string, v any) error {
+	for i, name := range path {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return fmt.Errorf("graphql: paginate: decode connection: %w", err)
+		}
+		if data = obj[name]; data == nil || string(data) == "null" {
+			return fmt.Errorf("graphql: paginate: no connection at %s", strings.Join(path[:i+1], "."))
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("graphql: paginate: decode connection: %w", err)
+	}
+	return nil
+}
+
+// RateLimited reports whether request failed due to rate limiting: with
+// HTTP status 429 or with a GraphQL error with extensions.code
+// RATE_LIMITED.
+func RateLimited(_ *Response, err error) bool {
+	var te *TransportError
+	if errors.As(err, &te) {
+		return te.StatusCode == http.StatusTooManyRequests
+	}
+	var list ErrorList
+	if !errors.As(err, &list) {
+		return false
+	}
+	for _, e := range list {
+		if code, _ := e.Extensions["code"].(string); code == "RATE_LIMITED" {
+			return true
+		}
+	}
+	return false
+}
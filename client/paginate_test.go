@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testEdge struct {
+	Node struct {
+		ID int `json:"id"`
+	} `json:"node"`
+}
+
+// pages returns PageFunc serving n pages of two edges each, failing calls
+// for which fail returns non-nil error.
+func pages(n int, fail func(call int) error) (PageFunc, *int) {
+	calls := 0
+	return func(ctx context.Context, after *string) (*Response, error) {
+		calls++
+		if fail != nil {
+			if err := fail(calls); err != nil {
+				return nil, err
+			}
+		}
+		page := 0
+		if after != nil {
+			fmt.Sscanf(*after, "c%d", &page)
+		}
+		data := fmt.Sprintf(`{"viewer": {"items": {"edges": [{"node": {"id": %d}}, {"node": {"id": %d}}], "pageInfo": {"hasNextPage": %t, "endCursor": "c%d"}}}}`,
+			page*2, page*2+1, page+1 < n, page+1)
+		return &Response{Data: json.RawMessage(data)}, nil
+	}, &calls
+}
+
+func TestPaginate(t *testing.T) {
+	rateLimited := &RequestError{Errors: ErrorList{{Message: "slow down", Extensions: map[string]any{"code": "RATE_LIMITED"}}}}
+	tests := []struct {
+		name          string
+		paginator     Paginator
+		pages         int
+		fail          func(call int) error
+		expectedIDs   int
+		expectedCalls int
+		expectedErr   error
+	}{
+		{"Single page", Paginator{}, 1, nil, 2, 1, nil},
+		{"All pages", Paginator{}, 3, nil, 6, 3, nil},
+		{"Max pages", Paginator{MaxPages: 2}, 3, nil, 4, 2, nil},
+		{
+			"Rate limited retried", Paginator{Retry: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}}, 2,
+			func(call int) error {
+				if call == 2 {
+					return &TransportError{StatusCode: 429}
+				}
+				if call == 3 {
+					return rateLimited
+				}
+				return nil
+			},
+			4, 4, nil,
+		},
+		{
+			"Rate limited without retry", Paginator{}, 2,
+			func(call int) error {
+				if call == 2 {
+					return rateLimited
+				}
+				return nil
+			},
+			2, 2, rateLimited,
+		},
+		{
+			"Other errors not retried", Paginator{Retry: &RetryPolicy{MaxAttempts: 3}}, 2,
+			func(call int) error {
+				if call == 2 {
+					return &TransportError{StatusCode: 500}
+				}
+				return nil
+			},
+			2, 2, &TransportError{StatusCode: 500},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.paginator.Path = []string{"viewer", "items"}
+			fetch, calls := pages(tt.pages, tt.fail)
+			edges, err := Paginate[testEdge](context.Background(), &tt.paginator, fetch)
+			if tt.expectedErr == nil && err != nil || tt.expectedErr != nil && fmt.Sprint(err) != fmt.Sprint(tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if len(edges) != tt.expectedIDs {
+				t.Fatalf("expected %d edges, got %d", tt.expectedIDs, len(edges))
+			}
+			for i, e := range edges {
+				if e.Node.ID != i {
+					t.Errorf("expected edge %d to have id %d, got %d", i, i, e.Node.ID)
+				}
+			}
+			if *calls != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, *calls)
+			}
+		})
+	}
+}
+
+func TestPaginate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"Missing connection", `{"viewer": null}`, "graphql: paginate: no connection at viewer"},
+		{"Missing pageInfo", `{"viewer": {"items": {"edges": []}}}`, "graphql: paginate: connection has no pageInfo"},
+		{"Missing cursor", `{"viewer": {"items": {"edges": [], "pageInfo": {"hasNextPage": true}}}}`, "graphql: paginate: next page has no cursor"},
+		{"Cursor not advancing", `{"viewer": {"items": {"edges": [], "pageInfo": {"hasNextPage": true, "endCursor": "a"}}}}`, `graphql: paginate: cursor "a" did not advance`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Paginator{Path: []string{"viewer", "items"}}
+			_, err := Paginate[testEdge](context.Background(), p, func(ctx context.Context, after *string) (*Response, error) {
+				return &Response{Data: json.RawMessage(tt.data)}, nil
+			})
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestPaginate_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fetch, calls := pages(5, func(call int) error {
+		if call == 2 {
+			cancel()
+		}
+		return nil
+	})
+	edges, err := Paginate[testEdge](ctx, &Paginator{Path: []string{"viewer", "items"}}, fetch)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(edges) != 4 || *calls != 2 {
+		t.Errorf("expected 4 edges from 2 calls, got %d from %d", len(edges), *calls)
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Parallelism configures concurrent execution of requests by Do.
+type Parallelism struct {
+	// Limit is maximum number of requests in flight. Zero means no limit.
+	Limit int
+
+	// FailFast cancels remaining requests when one fails and returns its
+	// error. By default all requests run to completion and failures are
+	// reported together as *ParallelError.
+	FailFast bool
+}
+
+// ParallelError reports failed requests of Parallel. Errors has an entry
+// for every request, nil for requests that succeeded.
+type ParallelError struct {
+	Errors []error
+}
+
+func (e *ParallelError) Error() string {
+	var b strings.Builder
+	b.WriteString("graphql: parallel requests failed:")
+	for i, err := range e.Errors {
+		if err != nil {
+			fmt.Fprintf(&b, " request %d: %v;", i, err)
+		}
+	}
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+// Unwrap returns errors of failed requests, so that errors.Is and
+// errors.As match any of them.
+func (e *ParallelError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Parallel executes requests concurrently without a limit and waits for
+// all of them. See Parallelism.Do.
+func Parallel(ctx context.Context, requests ...AttemptFunc) ([]*Response, error) {
+	var p Parallelism
+	return p.Do(ctx, requests...)
+}
+
+// Do executes requests concurrently and returns their responses in order
+// of requests. Responses of failed requests are nil unless the request
+// returned both, e.g. with *PartialDataError. Use Into to decode responses
+// into typed results as requests complete.
+func (p *Parallelism) Do(ctx context.Context, requests ...AttemptFunc) ([]*Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limit := p.Limit
+	if limit <= 0 || limit > len(requests) {
+		limit = len(requests)
+	}
+	sem := make(chan struct{}, limit)
+	responses := make([]*Response, len(requests))
+	errs := make([]error, len(requests))
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, request := range requests {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			// Requests not started are failed with the context error.
+			for j := i; j < len(requests); j++ {
+				errs[j] = err
+			}
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			responses[i], errs[i] = request(ctx)
+			if errs[i] != nil && p.FailFast {
+				once.Do(func() {
+					firstErr = errs[i]
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return responses, firstErr
+	}
+	for _, err := range errs {
+		if err != nil {
+			return responses, &ParallelError{Errors: errs}
+		}
+	}
+	return responses, nil
+}
+
+// Into returns request decoding response data of request into v, for
+// typed result collection with Parallel:
+//
+//	var user UserResult
+//	var repos ReposResult
+//	_, err := client.Parallel(ctx,
+//		client.Into(fetchUser, &user),
+//		client.Into(fetchRepos, &repos),
+//	)
+//
+// Errors are those of request or Response.Decode.
+func Into[T any](request AttemptFunc, v *T) AttemptFunc {
+	return func(ctx context.Context) (*Response, error) {
+		resp, err := request(ctx)
+		if err != nil {
+			return resp, err
+		}
+		return resp, resp.Decode(v)
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelism_Do(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name         string
+		parallelism  Parallelism
+		fail         map[int]bool
+		expectedErrs []error
+		expectedMax  int32
+	}{
+		{"All succeed", Parallelism{}, nil, nil, 4},
+		{"Limit", Parallelism{Limit: 2}, nil, nil, 2},
+		{"Aggregate errors", Parallelism{Limit: 1}, map[int]bool{1: true, 3: true}, []error{nil, errFailed, nil, errFailed}, 1},
+		{"Fail fast", Parallelism{Limit: 1, FailFast: true}, map[int]bool{1: true}, []error{errFailed}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxInFlight atomic.Int32
+			var requests []AttemptFunc
+			for i := range 4 {
+				requests = append(requests, func(ctx context.Context) (*Response, error) {
+					n := inFlight.Add(1)
+					defer inFlight.Add(-1)
+					for {
+						m := maxInFlight.Load()
+						if n <= m || maxInFlight.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					if tt.fail[i] {
+						return nil, errFailed
+					}
+					return &Response{Data: json.RawMessage(`{"i": 1}`)}, nil
+				})
+			}
+			responses, err := tt.parallelism.Do(context.Background(), requests...)
+			if got := maxInFlight.Load(); got != tt.expectedMax {
+				t.Errorf("expected %d requests in flight, got %d", tt.expectedMax, got)
+			}
+			switch {
+			case tt.expectedErrs == nil:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for i, resp := range responses {
+					if resp == nil {
+						t.Errorf("expected response %d", i)
+					}
+				}
+			case tt.parallelism.FailFast:
+				if err != errFailed {
+					t.Fatalf("expected first error, got %v", err)
+				}
+				if responses[0] == nil || responses[2] != nil || responses[3] != nil {
+					t.Errorf("expected only response 0, got %v", responses)
+				}
+			default:
+				var pe *ParallelError
+				if !errors.As(err, &pe) || !errors.Is(err, errFailed) {
+					t.Fatalf("expected *ParallelError, got %v", err)
+				}
+				for i, e := range pe.Errors {
+					if e != tt.expectedErrs[i] {
+						t.Errorf("expected error %d to be %v, got %v", i, tt.expectedErrs[i], e)
+					}
+				}
+				if pe.Error() != "graphql: parallel requests failed: request 1: failed; request 3: failed" {
+					t.Errorf("unexpected message %q", pe.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestParallel_Into(t *testing.T) {
+	var a struct{ A int }
+	var b struct{ B string }
+	_, err := Parallel(context.Background(),
+		Into(func(ctx context.Context) (*Response, error) {
+			return &Response{Data: json.RawMessage(`{"A": 1}`)}, nil
+		}, &a),
+		Into(func(ctx context.Context) (*Response, error) {
+			return &Response{Data: json.RawMessage(`{"B": "b"}`)}, nil
+		}, &b),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.A != 1 || b.B != "b" {
+		t.Errorf("unexpected results %v, %v", a, b)
+	}
+}
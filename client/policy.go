@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+// AttemptFunc performs a single request attempt.
+type AttemptFunc func(ctx context.Context) (*Response, error)
+
+// RetryPolicy configures retries of failed attempts.
+type RetryPolicy struct {
+	// MaxAttempts is total number of attempts including the first one.
+	MaxAttempts int
+
+	// BaseDelay and MaxDelay bound exponential backoff with full jitter
+	// applied between attempts. Zero BaseDelay retries immediately.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// RetryMutations enables retries of mutations. By default only
+	// queries, which are expected to be idempotent, are retried.
+	RetryMutations bool
+
+	// Retryable decides whether attempt result should be retried.
+	// DefaultRetryable is used when nil.
+	Retryable func(resp *Response, err error) bool
+}
+
+// HedgePolicy configures hedged requests: when an attempt did not finish
+// within Delay another one is started concurrently and the first
+// successful result wins. Hedging applies to queries only.
+type HedgePolicy struct {
+	Delay     time.Duration
+	MaxHedges int // Number of extra attempts, at least 1.
+}
+
+// CircuitBreaker is consulted before every attempt and informed about its
+// outcome. Allow returns non-nil error to reject the attempt.
+type CircuitBreaker interface {
+	Allow() error
+	Record(err error)
+}
+
+// Policy combines resilience controls applied to requests.
+type Policy struct {
+	Timeout        time.Duration // Overall timeout, including retries.
+	AttemptTimeout time.Duration // Timeout of a single attempt.
+	Retry          *RetryPolicy
+	Hedge          *HedgePolicy
+	Breaker        CircuitBreaker
+}
+
+// ErrCircuitOpen may be returned by CircuitBreaker implementations.
+var ErrCircuitOpen = errors.New("graphql: circuit breaker is open")
+
+// DefaultRetryable retries transport errors caused by network failures,
+// 429 and 5xx statuses. GraphQL errors are never retried.
+func DefaultRetryable(_ *Response, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var te *TransportError
+	if !errors.As(err, &te) {
+		return false
+	}
+	return te.StatusCode == 0 || te.StatusCode == http.StatusTooManyRequests || te.StatusCode >= 500
+}
+
+// Do executes attempt according to policy. Operation type decides whether
+// retries and hedging are allowed; see OperationTypeOf.
+func (p *Policy) Do(ctx context.Context, opType ast.OperationType, attempt AttemptFunc) (*Response, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	maxAttempts := 1
+	retryable := DefaultRetryable
+	if r := p.Retry; r != nil && (opType == ast.OperationTypeQuery || r.RetryMutations) {
+		maxAttempts = max(r.MaxAttempts, 1)
+		if r.Retryable != nil {
+			retryable = r.Retryable
+		}
+	}
+
+	var (
+		resp *Response
+		err  error
+	)
+	for i := 0; i < maxAttempts; i++ {
+		if i > 0 {
+			if err := sleep(ctx, p.Retry.backoff(i)); err != nil {
+				return nil, err
+			}
+		}
+		if opType == ast.OperationTypeQuery && p.Hedge != nil {
+			resp, err = p.hedged(ctx, attempt)
+		} else {
+			resp, err = p.attempt(ctx, attempt)
+		}
+		if !retryable(resp, err) || ctx.Err() != nil {
+			break
+		}
+	}
+	return resp, err
+}
+
+func (p *Policy) attempt(ctx context.Context, attempt AttemptFunc) (*Response, error) {
+	if p.Breaker != nil {
+		if err := p.Breaker.Allow(); err != nil {
+			return nil, err
+		}
+	}
+	if p.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.AttemptTimeout)
+		defer cancel()
+	}
+	resp, err := attempt(ctx)
+	if p.Breaker != nil {
+		p.Breaker.Record(err)
+	}
+	return resp, err
+}
+
+type attemptResult struct {
+	resp *Response
+	err  error
+}
+
+func (p *Policy) hedged(ctx context.Context, attempt AttemptFunc) (*Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	total := 1 + max(p.Hedge.MaxHedges, 1)
+	results := make(chan attemptResult, total)
+	launch := func() {
+		go func() {
+			resp, err := p.attempt(ctx, attempt)
+			results <- attemptResult{resp, err}
+		}()
+	}
+
+	launch()
+	launched, finished := 1, 0
+	timer := time.NewTimer(p.Hedge.Delay)
+	defer timer.Stop()
+
+	var last attemptResult
+	for {
+		select {
+		case r := <-results:
+			finished++
+			if r.err == nil {
+				return r.resp, nil
+			}
+			last = r
+			if finished == launched && launched == total {
+				return last.resp, last.err
+			}
+			if finished == launched {
+				// All in-flight attempts failed, hedge right away.
+				launch()
+				launched++
+			}
+		case <-timer.C:
+			if launched < total {
+				launch()
+				launched++
+				timer.Reset(p.Hedge.Delay)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (r *RetryPolicy) backoff(attempt int) time.Duration {
+	if r.BaseDelay <= 0 {
+		return 0
+	}
+	d := r.BaseDelay << (attempt - 1)
+	if r.MaxDelay > 0 && (d > r.MaxDelay || d <= 0) {
+		d = r.MaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// OperationTypeOf returns type of operation operationName in query, or of
+// the first operation when operationName is empty. It scans tokens instead
+// of parsing the whole document, so it is cheap enough to run per request.
+func OperationTypeOf(query, operationName string) (ast.OperationType, error) {
+	l := lexer.New(query)
+	depth := 0
+	header := false // Inside definition header, before its selection set.
+	for {
+		tok, err := l.NextToken()
+		if err != nil {
+			return "", err
+		}
+		switch tok.Type {
+		case token.EOF:
+			if operationName != "" {
+				return "", errors.New("graphql: unknown operation " + operationName)
+			}
+			return "", errors.New("graphql: document contains no operation")
+		case token.LBRACE:
+			if depth == 0 && !header && operationName == "" {
+				return ast.OperationTypeQuery, nil
+			}
+			header = false
+			depth++
+		case token.LPAREN:
+			depth++
+		case token.RBRACE, token.RPAREN:
+			depth--
+		case token.NAME:
+			if depth != 0 || header {
+				continue
+			}
+			header = true
+			opType := ast.OperationType(tok.Literal)
+			if opType != ast.OperationTypeQuery && opType != ast.OperationTypeMutation && opType != ast.OperationTypeSubscription {
+				continue
+			}
+			if operationName == "" {
+				return opType, nil
+			}
+			next, err := l.NextToken()
+			if err != nil {
+				return "", err
+			}
+			switch {
+			case next.Type == token.NAME && next.Literal == operationName:
+				return opType, nil
+			case next.Type == token.LBRACE:
+				header = false
+				depth++
+			case next.Type == token.LPAREN:
+				depth++
+			}
+		}
+	}
+}
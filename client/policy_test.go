@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+func TestPolicy_Retry(t *testing.T) {
+	tests := []struct {
+		name             string
+		opType           ast.OperationType
+		retry            *RetryPolicy
+		err              error
+		expectedAttempts int32
+	}{
+		{"Query retried on 503", ast.OperationTypeQuery, &RetryPolicy{MaxAttempts: 3}, &TransportError{StatusCode: 503}, 3},
+		{"Query not retried on 400", ast.OperationTypeQuery, &RetryPolicy{MaxAttempts: 3}, &TransportError{StatusCode: 400}, 1},
+		{"Query not retried on GraphQL error", ast.OperationTypeQuery, &RetryPolicy{MaxAttempts: 3}, &RequestError{}, 1},
+		{"Mutation not retried by default", ast.OperationTypeMutation, &RetryPolicy{MaxAttempts: 3}, &TransportError{}, 1},
+		{"Mutation retried when enabled", ast.OperationTypeMutation, &RetryPolicy{MaxAttempts: 2, RetryMutations: true}, &TransportError{}, 2},
+		{"No retry policy", ast.OperationTypeQuery, nil, &TransportError{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			p := Policy{Retry: tt.retry}
+			_, err := p.Do(context.Background(), tt.opType, func(ctx context.Context) (*Response, error) {
+				attempts.Add(1)
+				return nil, tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+			if got := attempts.Load(); got != tt.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestPolicy_RetrySucceeds(t *testing.T) {
+	var attempts atomic.Int32
+	p := Policy{Retry: &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}}
+	resp, err := p.Do(context.Background(), ast.OperationTypeQuery, func(ctx context.Context) (*Response, error) {
+		if attempts.Add(1) < 3 {
+			return nil, &TransportError{Err: errors.New("connection reset")}
+		}
+		return &Response{}, nil
+	})
+	if err != nil || resp == nil {
+		t.Fatalf("unexpected result: %v, %v", resp, err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestPolicy_Hedge(t *testing.T) {
+	var attempts atomic.Int32
+	p := Policy{Hedge: &HedgePolicy{Delay: 10 * time.Millisecond, MaxHedges: 1}}
+	fast := &Response{}
+	resp, err := p.Do(context.Background(), ast.OperationTypeQuery, func(ctx context.Context) (*Response, error) {
+		if attempts.Add(1) == 1 {
+			<-ctx.Done() // First attempt hangs until cancelled.
+			return nil, ctx.Err()
+		}
+		return fast, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp != fast {
+		t.Errorf("expected hedged response")
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+type countingBreaker struct {
+	open     bool
+	failures int
+}
+
+func (b *countingBreaker) Allow() error {
+	if b.open {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+func (b *countingBreaker) Record(err error) {
+	if err != nil {
+		b.failures++
+		b.open = b.failures >= 2
+	}
+}
+
+func TestPolicy_CircuitBreaker(t *testing.T) {
+	var attempts int
+	p := Policy{Retry: &RetryPolicy{MaxAttempts: 5}, Breaker: &countingBreaker{}}
+	_, err := p.Do(context.Background(), ast.OperationTypeQuery, func(ctx context.Context) (*Response, error) {
+		attempts++
+		return nil, &TransportError{StatusCode: 500}
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestPolicy_AttemptTimeout(t *testing.T) {
+	p := Policy{AttemptTimeout: time.Millisecond}
+	_, err := p.Do(context.Background(), ast.OperationTypeQuery, func(ctx context.Context) (*Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestOperationTypeOf(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		expected      ast.OperationType
+	}{
+		{"Anonymous", `{ a }`, "", ast.OperationTypeQuery},
+		{"Query", `query { a }`, "", ast.OperationTypeQuery},
+		{"Mutation", `mutation M($a: Int) { a(a: $a) }`, "", ast.OperationTypeMutation},
+		{"Fragment first", `fragment F on T { query } subscription S { ...F }`, "", ast.OperationTypeSubscription},
+		{"By name", `query Q { mutation } mutation M { a }`, "M", ast.OperationTypeMutation},
+		{"Comments", "# mutation\nquery { a }", "", ast.OperationTypeQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OperationTypeOf(tt.query, tt.operationName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := OperationTypeOf(`query Q { a }`, "M"); err == nil {
+		t.Errorf("expected error for unknown operation")
+	}
+}
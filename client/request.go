@@ -0,0 +1,226 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// Request is a GraphQL-over-HTTP request.
+//
+// https://graphql.github.io/graphql-over-http/draft/#sec-Request-Parameters
+type Request struct {
+	Query         string         `json:"query,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
+
+// DefaultMaxURLLength is URL length supported by common CDNs and proxies.
+const DefaultMaxURLLength = 2048
+
+// Requester constructs and sends HTTP requests of GraphQL requests. With
+// UseGET, queries are sent with GET, so that CDNs can cache them, unless
+// the URL would be too long; mutations and subscriptions are always sent
+// with POST. The zero value is ready to use and sends everything with
+// POST.
+type Requester struct {
+	// UseGET enables GET for queries.
+	UseGET bool
+
+	// MaxURLLength is the longest URL sent with GET, DefaultMaxURLLength
+	// when zero. Longer requests are sent with POST.
+	MaxURLLength int
+
+	// PersistedQueries enables automatic persisted queries: Do first sends
+	// only hash of the query and sends the query itself when server does
+	// not know it yet. Hash-only GET requests are short, so they are
+	// rarely sent with POST. Once the server reports it does not support
+	// persisted queries, they are disabled.
+	PersistedQueries bool
+
+	Header http.Header // Added to requests.
+
+	apqUnsupported atomic.Bool
+}
+
+// NewHTTPRequest returns HTTP request of req to endpoint, using GET when
+// enabled, req is a query and URL fits MaxURLLength. Requests without
+// query, i.e. hash-only requests of persisted queries, are assumed to be
+// queries.
+func (r *Requester) NewHTTPRequest(ctx context.Context, endpoint string, req *Request) (*http.Request, error) {
+	return r.newHTTPRequest(ctx, endpoint, req, r.UseGET && (req.Query == "" || isQuery(req)))
+}
+
+func (r *Requester) newHTTPRequest(ctx context.Context, endpoint string, req *Request, get bool) (*http.Request, error) {
+	var httpReq *http.Request
+	var err error
+	if u, ok := r.getURL(endpoint, req); get && ok {
+		if httpReq, err = http.NewRequestWithContext(ctx, http.MethodGet, u, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		if httpReq, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body)); err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range r.Header {
+		httpReq.Header[k] = v
+	}
+	httpReq.Header.Set("Accept", "application/graphql-response+json, application/json")
+	return httpReq, nil
+}
+
+// getURL returns GET URL of req and whether it fits MaxURLLength.
+func (r *Requester) getURL(endpoint string, req *Request) (string, bool) {
+	if !r.UseGET {
+		return "", false
+	}
+	u, err := GetURL(endpoint, req)
+	if err != nil {
+		return "", false
+	}
+	limit := r.MaxURLLength
+	if limit == 0 {
+		limit = DefaultMaxURLLength
+	}
+	return u, len(u) <= limit
+}
+
+// isQuery reports whether req is a query, which may be sent with GET.
+func isQuery(req *Request) bool {
+	typ, err := OperationTypeOf(req.Query, req.OperationName)
+	return err == nil && typ == ast.OperationTypeQuery
+}
+
+// Do sends req with client, negotiating persisted queries when enabled,
+// and decodes the response. Errors are classified as by Response.Err.
+// http.DefaultClient is used when client is nil.
+func (r *Requester) Do(ctx context.Context, client *http.Client, endpoint string, req *Request) (*Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	get := r.UseGET && isQuery(req)
+	if !r.PersistedQueries || r.apqUnsupported.Load() || req.Query == "" {
+		return r.send(ctx, client, endpoint, req, get)
+	}
+
+	persisted := *req
+	persisted.Query = ""
+	persisted.Extensions = withPersistedQuery(req.Extensions, req.Query)
+	resp, err := r.send(ctx, client, endpoint, &persisted, get)
+	if resp == nil {
+		return nil, err
+	}
+	switch {
+	case hasErrorCode(resp, "PERSISTED_QUERY_NOT_SUPPORTED", "PersistedQueryNotSupported"):
+		r.apqUnsupported.Store(true)
+		return r.send(ctx, client, endpoint, req, get)
+	case hasErrorCode(resp, "PERSISTED_QUERY_NOT_FOUND", "PersistedQueryNotFound"):
+		// Register the query along with its hash.
+		persisted.Query = req.Query
+		return r.send(ctx, client, endpoint, &persisted, get)
+	}
+	return resp, err
+}
+
+func (r *Requester) send(ctx context.Context, client *http.Client, endpoint string, req *Request, get bool) (*Response, error) {
+	httpReq, err := r.newHTTPRequest(ctx, endpoint, req, get)
+	if err != nil {
+		return nil, err
+	}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, &TransportError{Err: err}
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, &TransportError{StatusCode: httpResp.StatusCode, Err: err}
+	}
+	resp, err := DecodeResponse(httpResp.StatusCode, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp, resp.Err()
+}
+
+// GetURL returns URL of GET request of req to endpoint, with query,
+// operationName, variables and extensions as query parameters, the latter
+// two encoded as JSON.
+//
+// https://graphql.github.io/graphql-over-http/draft/#sec-GET
+func GetURL(endpoint string, req *Request) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	params := u.Query()
+	if req.Query != "" {
+		params.Set("query", req.Query)
+	}
+	if req.OperationName != "" {
+		params.Set("operationName", req.OperationName)
+	}
+	if len(req.Variables) > 0 {
+		data, err := json.Marshal(req.Variables)
+		if err != nil {
+			return "", err
+		}
+		params.Set("variables", string(data))
+	}
+	if len(req.Extensions) > 0 {
+		data, err := json.Marshal(req.Extensions)
+		if err != nil {
+			return "", err
+		}
+		params.Set("extensions", string(data))
+	}
+	u.RawQuery = params.Encode()
+	return u.String(), nil
+}
+
+// PersistedQueryHash returns SHA-256 hash of query in hex, as automatic
+// persisted queries identify queries.
+func PersistedQueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// withPersistedQuery returns copy of extensions with persistedQuery
+// extension of query.
+func withPersistedQuery(extensions map[string]any, query string) map[string]any {
+	result := make(map[string]any, len(extensions)+1)
+	for k, v := range extensions {
+		result[k] = v
+	}
+	result["persistedQuery"] = map[string]any{
+		"version":    1,
+		"sha256Hash": PersistedQueryHash(query),
+	}
+	return result
+}
+
+// hasErrorCode reports whether response has error with extensions.code
+// code or with message message, which older servers send instead.
+func hasErrorCode(resp *Response, code, message string) bool {
+	for _, e := range resp.Errors {
+		if c, _ := e.Extensions["code"].(string); c == code || e.Message == message {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetURL(t *testing.T) {
+	u, err := GetURL("https://example.com/graphql?app=web", &Request{
+		Query:         "query Q($id: ID) { user(id: $id) { name } }",
+		OperationName: "Q",
+		Variables:     map[string]any{"id": "1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "https://example.com/graphql?app=web&operationName=Q&query=query+Q%28%24id%3A+ID%29+%7B+user%28id%3A+%24id%29+%7B+name+%7D+%7D&variables=%7B%22id%22%3A%221%22%7D"
+	if u != expected {
+		t.Errorf("expected %s, got %s", expected, u)
+	}
+}
+
+func TestRequester_NewHTTPRequest(t *testing.T) {
+	long := "{ " + strings.Repeat("a ", DefaultMaxURLLength) + "}"
+	tests := []struct {
+		name      string
+		requester *Requester
+		req       *Request
+		expected  string
+	}{
+		{"POST by default", &Requester{}, &Request{Query: "{ a }"}, http.MethodPost},
+		{"Query with GET", &Requester{UseGET: true}, &Request{Query: "{ a }"}, http.MethodGet},
+		{"Mutation with POST", &Requester{UseGET: true}, &Request{Query: "mutation { a }"}, http.MethodPost},
+		{"Named mutation with POST", &Requester{UseGET: true}, &Request{Query: "query A { a } mutation B { b }", OperationName: "B"}, http.MethodPost},
+		{"Long query with POST", &Requester{UseGET: true}, &Request{Query: long}, http.MethodPost},
+		{"Custom limit", &Requester{UseGET: true, MaxURLLength: 10}, &Request{Query: "{ a }"}, http.MethodPost},
+		{"Hash-only with GET", &Requester{UseGET: true}, &Request{Extensions: withPersistedQuery(nil, "{ a }")}, http.MethodGet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.requester.NewHTTPRequest(context.Background(), "https://example.com/graphql", tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if req.Method != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, req.Method)
+			}
+			if req.Method == http.MethodPost && req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("expected JSON content type, got %q", req.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+// apqServer is a server supporting automatic persisted queries. It records
+// methods and whether requests contained query.
+type apqServer struct {
+	mu        sync.Mutex
+	supported bool
+	known     map[string]bool
+	requests  []string
+}
+
+func (s *apqServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if r.Method == http.MethodGet {
+		req.Query = r.URL.Query().Get("query")
+		json.Unmarshal([]byte(r.URL.Query().Get("extensions")), &req.Extensions)
+	} else {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &req)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" query="+map[bool]string{true: "yes", false: "no"}[req.Query != ""])
+
+	pq, _ := req.Extensions["persistedQuery"].(map[string]any)
+	switch {
+	case pq != nil && !s.supported:
+		w.Write([]byte(`{"errors": [{"message": "PersistedQueryNotSupported"}]}`))
+		return
+	case pq != nil && req.Query != "":
+		s.known[pq["sha256Hash"].(string)] = true
+	case pq != nil && !s.known[pq["sha256Hash"].(string)]:
+		w.Write([]byte(`{"errors": [{"message": "not found", "extensions": {"code": "PERSISTED_QUERY_NOT_FOUND"}}]}`))
+		return
+	}
+	w.Write([]byte(`{"data": {"a": 1}}`))
+}
+
+func TestRequester_PersistedQueries(t *testing.T) {
+	tests := []struct {
+		name      string
+		supported bool
+		query     string
+		expected  []string
+	}{
+		{"Negotiated", true, "{ a }", []string{"GET query=no", "GET query=yes", "GET query=no"}},
+		{"Mutation", true, "mutation { a }", []string{"POST query=no", "POST query=yes", "POST query=no"}},
+		{"Not supported", false, "{ a }", []string{"GET query=no", "GET query=yes", "GET query=yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &apqServer{supported: tt.supported, known: make(map[string]bool)}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+
+			r := &Requester{UseGET: true, PersistedQueries: true}
+			for range 2 {
+				resp, err := r.Do(context.Background(), nil, srv.URL, &Request{Query: tt.query})
+				if err != nil || string(resp.Data) != `{"a": 1}` {
+					t.Fatalf("unexpected result: %v, %v", resp, err)
+				}
+			}
+			if strings.Join(s.requests, ", ") != strings.Join(tt.expected, ", ") {
+				t.Errorf("expected requests %v, got %v", tt.expected, s.requests)
+			}
+		})
+	}
+}
+
+func TestPersistedQueryHash(t *testing.T) {
+	if got := PersistedQueryHash("{ a }"); got != "1c7e1e347f726166b5b1c55afd61f278cc9b45e00c108ec33d540a566379811b" {
+		t.Errorf("unexpected hash %s", got)
+	}
+}
@@ -0,0 +1,262 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/introspection"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// SchemaCache fetches schemas of remote endpoints with introspection and
+// keeps them in memory and, when Dir is set, on disk, so that outgoing
+// operations can be validated locally.
+//
+// A cached schema is used without contacting the endpoint until TTL
+// elapses. Then it is revalidated with If-None-Match when the endpoint sent
+// an ETag, or fetched again otherwise. Concurrent calls for an endpoint
+// that needs fetching may fetch it more than once.
+type SchemaCache struct {
+	HTTPClient *http.Client // http.DefaultClient when nil.
+	Header     http.Header  // Added to introspection requests.
+
+	// Options select introspection query. DefaultOptions are used when
+	// nil.
+	Options *introspection.Options
+
+	// TTL is how long a fetched schema is used without revalidation.
+	// Zero revalidates on every call.
+	TTL time.Duration
+
+	// Dir is directory schemas are persisted to, keyed by endpoint. It is
+	// created when needed. Persistence is disabled when empty.
+	Dir string
+
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*schemaEntry
+}
+
+// schemaEntry is a cached introspection result, also the format of
+// persisted files.
+type schemaEntry struct {
+	Endpoint  string          `json:"endpoint"`
+	ETag      string          `json:"etag,omitempty"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Schema    json.RawMessage `json:"schema"` // __schema field of result.
+
+	built *schema.Schema
+}
+
+// Schema returns schema of endpoint, fetching it when it is not cached or
+// cache entry expired.
+func (c *SchemaCache) Schema(ctx context.Context, endpoint string) (*schema.Schema, error) {
+	entry := c.lookup(endpoint)
+	if entry != nil && c.clock().Sub(entry.FetchedAt) < c.TTL {
+		return entry.built, nil
+	}
+	entry, err := c.fetch(ctx, endpoint, entry)
+	if err != nil {
+		return nil, err
+	}
+	c.store(entry)
+	return entry.built, nil
+}
+
+// Invalidate drops cached schema of endpoint, including persisted one.
+func (c *SchemaCache) Invalidate(endpoint string) error {
+	c.mu.Lock()
+	delete(c.entries, endpoint)
+	c.mu.Unlock()
+	if c.Dir == "" {
+		return nil
+	}
+	if err := os.Remove(c.path(endpoint)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// lookup returns cached entry of endpoint, loading persisted one if
+// needed, or nil. Unreadable files are ignored; the schema is fetched
+// again.
+func (c *SchemaCache) lookup(endpoint string) *schemaEntry {
+	c.mu.Lock()
+	entry := c.entries[endpoint]
+	c.mu.Unlock()
+	if entry != nil || c.Dir == "" {
+		return entry
+	}
+	data, err := os.ReadFile(c.path(endpoint))
+	if err != nil {
+		return nil
+	}
+	entry = &schemaEntry{}
+	if err := json.Unmarshal(data, entry); err != nil || entry.Endpoint != endpoint {
+		return nil
+	}
+	if entry.built, err = introspectedSchema(entry.Schema); err != nil {
+		return nil
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*schemaEntry)
+	}
+	c.entries[endpoint] = entry
+	c.mu.Unlock()
+	return entry
+}
+
+// store caches entry in memory and persists it. Persistence errors are
+// ignored as the schema is still usable.
+func (c *SchemaCache) store(entry *schemaEntry) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*schemaEntry)
+	}
+	c.entries[entry.Endpoint] = entry
+	c.mu.Unlock()
+	if c.Dir == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return
+	}
+	// Write to temporary file first so that readers never see partial
+	// content.
+	f, err := os.CreateTemp(c.Dir, ".schema-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(entry.Endpoint))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// fetch sends introspection query to endpoint. When cached is not nil and
+// has ETag, the request is conditional and cached is returned refreshed
+// when the schema did not change.
+func (c *SchemaCache) fetch(ctx context.Context, endpoint string, cached *schemaEntry) (*schemaEntry, error) {
+	opts := introspection.DefaultOptions
+	if c.Options != nil {
+		opts = *c.Options
+	}
+	body, err := json.Marshal(map[string]string{
+		"query":         introspection.Query(opts),
+		"operationName": "IntrospectionQuery",
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/graphql-response+json, application/json")
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return nil, &TransportError{Err: err}
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, &TransportError{StatusCode: httpResp.StatusCode, Err: err}
+	}
+
+	if httpResp.StatusCode == http.StatusNotModified && cached != nil {
+		refreshed := *cached
+		refreshed.FetchedAt = c.clock()
+		return &refreshed, nil
+	}
+	resp, err := DecodeResponse(httpResp.StatusCode, data)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Schema json.RawMessage `json:"__schema"`
+	}
+	if err := resp.Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Schema) == 0 {
+		return nil, errors.New("graphql: introspection result has no __schema")
+	}
+	built, err := introspectedSchema(result.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return &schemaEntry{
+		Endpoint:  endpoint,
+		ETag:      httpResp.Header.Get("ETag"),
+		FetchedAt: c.clock(),
+		Schema:    result.Schema,
+		built:     built,
+	}, nil
+}
+
+// path returns name of file endpoint schema is persisted to.
+func (c *SchemaCache) path(endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *SchemaCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// introspectedSchema builds schema from __schema field of introspection
+// result.
+func introspectedSchema(data json.RawMessage) (*schema.Schema, error) {
+	var result introspection.Schema
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("graphql: decode introspection result: %w", err)
+	}
+	doc, err := result.Document()
+	if err != nil {
+		return nil, fmt.Errorf("graphql: introspection result: %w", err)
+	}
+	s, err := schema.FromDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("graphql: build schema: %w", err)
+	}
+	return s, nil
+}
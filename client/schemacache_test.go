@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testIntrospection = `{"data": {"__schema": {
+  "queryType": {"name": "Query"},
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "hello", "args": [], "type": {"kind": "SCALAR", "name": "String", "ofType": null}}
+    ], "interfaces": []},
+    {"kind": "SCALAR", "name": "String"}
+  ],
+  "directives": []
+}}}`
+
+// introspectionServer serves testIntrospection with ETag "v1" and counts
+// requests and conditional requests.
+func introspectionServer(t *testing.T) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	var requests, conditional atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(testIntrospection))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests, &conditional
+}
+
+func TestSchemaCache(t *testing.T) {
+	srv, requests, conditional := introspectionServer(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &SchemaCache{TTL: time.Minute, now: func() time.Time { return now }}
+
+	s, err := c.Schema(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.QueryType() == nil || s.QueryType().Field("hello") == nil {
+		t.Fatalf("unexpected schema: %v", s.TypeNames())
+	}
+
+	now = now.Add(30 * time.Second)
+	if s2, err := c.Schema(context.Background(), srv.URL); err != nil || s2 != s {
+		t.Fatalf("expected cached schema, got %v, %v", s2, err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request within TTL, got %d", got)
+	}
+
+	now = now.Add(time.Minute)
+	if s2, err := c.Schema(context.Background(), srv.URL); err != nil || s2 != s {
+		t.Fatalf("expected revalidated schema, got %v, %v", s2, err)
+	}
+	if got := conditional.Load(); got != 1 {
+		t.Errorf("expected 1 conditional request, got %d", got)
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, err := c.Schema(context.Background(), srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected revalidation to restart TTL, got %d requests", got)
+	}
+}
+
+func TestSchemaCache_Persistence(t *testing.T) {
+	srv, requests, _ := introspectionServer(t)
+	dir := t.TempDir()
+
+	c := &SchemaCache{TTL: time.Hour, Dir: dir}
+	if _, err := c.Schema(context.Background(), srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	c = &SchemaCache{TTL: time.Hour, Dir: dir}
+	s, err := c.Schema(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.QueryType() == nil {
+		t.Error("expected persisted schema to have query type")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected persisted schema to be used, got %d requests", got)
+	}
+
+	if err := c.Invalidate(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	c = &SchemaCache{TTL: time.Hour, Dir: dir}
+	if _, err := c.Schema(context.Background(), srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected invalidated schema to be fetched, got %d requests", got)
+	}
+}
+
+func TestSchemaCache_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(err error) bool
+	}{
+		{"Server error", http.StatusBadGateway, "bad gateway", func(err error) bool {
+			var te *TransportError
+			return errors.As(err, &te) && te.StatusCode == http.StatusBadGateway
+		}},
+		{"Introspection disabled", http.StatusOK, `{"errors": [{"message": "introspection is disabled"}]}`, func(err error) bool {
+			var re *RequestError
+			return errors.As(err, &re)
+		}},
+		{"Invalid result", http.StatusOK, `{"data": {"__schema": {"types": [{"kind": "THING", "name": "X"}]}}}`, func(err error) bool {
+			return err != nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			c := &SchemaCache{}
+			if _, err := c.Schema(context.Background(), srv.URL); !tt.check(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/edit"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/schema"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+// ValidationError is returned when operation failed local validation and
+// was not sent. Errors are formatted as the server would report them.
+type ValidationError struct {
+	Errors ErrorList
+}
+
+func (e *ValidationError) Error() string {
+	return "graphql: invalid operation: " + e.Errors.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Errors
+}
+
+// Validator checks outgoing operations against schema of the endpoint
+// they are sent to, so that invalid operations fail without a network
+// round trip.
+type Validator struct {
+	Schema *schema.Schema
+
+	// Rules are validation rules to check, validation.SpecifiedRules when
+	// empty.
+	Rules []validation.Rule
+}
+
+// Validate parses query and checks it and variable values of operation
+// operationName, which may be empty when query has a single operation.
+// Variables may be any values encodable to JSON. It returns
+// *ValidationError when operation is invalid.
+func (v *Validator) Validate(query, operationName string, variables map[string]any) error {
+	p, err := parser.New(lexer.New(query))
+	if err == nil {
+		var doc *ast.Document
+		if doc, err = p.ParseDocument(); err == nil {
+			return v.validate(query, doc, operationName, variables)
+		}
+	}
+	e := &Error{Message: "Syntax Error: " + err.Error()}
+	var lexErr *lexer.LexError
+	if errors.As(err, &lexErr) {
+		e.Locations = []Location{{Line: lexErr.Line, Column: lexErr.Column}}
+	}
+	return &ValidationError{Errors: ErrorList{e}}
+}
+
+func (v *Validator) validate(query string, doc *ast.Document, operationName string, variables map[string]any) error {
+	errs := validation.Validate(v.Schema, doc, v.Rules...)
+	if len(errs) == 0 {
+		op, err := selectOperation(doc, operationName)
+		if err != nil {
+			return &ValidationError{Errors: ErrorList{{Message: err.Error()}}}
+		}
+		values, err := jsonValues(variables)
+		if err != nil {
+			return err
+		}
+		errs = validation.VariableValues(v.Schema, op, values)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	list := make(ErrorList, len(errs))
+	for i, e := range errs {
+		list[i] = &Error{Message: e.Message}
+		for _, pos := range e.Positions {
+			p := edit.Position(query, pos)
+			list[i].Locations = append(list[i].Locations, Location{Line: p.Line, Column: p.Column})
+		}
+	}
+	return &ValidationError{Errors: list}
+}
+
+// selectOperation returns operation named name, or the only operation of
+// doc when name is empty.
+func selectOperation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "" && found != nil:
+			return nil, errors.New("Must provide operation name if query contains multiple operations.")
+		case name == "" || op.Name != nil && op.Name.Value == name:
+			found = op
+		}
+	}
+	switch {
+	case found != nil:
+		return found, nil
+	case name != "":
+		return nil, fmt.Errorf("Unknown operation named %q.", name)
+	}
+	return nil, errors.New("Must provide an operation.")
+}
+
+// jsonValues returns variables as decoded from their JSON encoding, which
+// is what the server receives.
+func jsonValues(variables map[string]any) (map[string]any, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return nil, fmt.Errorf("graphql: encode variables: %w", err)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var values map[string]any
+	if err := d.Decode(&values); err != nil {
+		return nil, fmt.Errorf("graphql: encode variables: %w", err)
+	}
+	return values, nil
+}
+
+// Validate validates operation against schema of endpoint, fetching the
+// schema if needed. See Validator.Validate.
+func (c *SchemaCache) Validate(ctx context.Context, endpoint, query, operationName string, variables map[string]any) error {
+	s, err := c.Schema(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	v := Validator{Schema: s}
+	return v.Validate(query, operationName, variables)
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestValidator(t *testing.T) {
+	v := &Validator{Schema: asttest.Schema(t, `
+type Query { user(id: ID!): User users(page: Page): [User] }
+type User { name: String }
+input Page { first: Int }`)}
+	type page struct {
+		First any `json:"first"`
+	}
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		variables     map[string]any
+		expected      []*Error
+	}{
+		{"Valid", `query Q($id: ID!) { user(id: $id) { name } }`, "", map[string]any{"id": 1}, nil},
+		{"Unknown field", "{\n  user(id: 1) { email }\n}", "", nil, []*Error{
+			{Message: `Cannot query field "email" on type "User".`, Locations: []Location{{Line: 2, Column: 17}}},
+		}},
+		{"Missing variable", `query Q($id: ID!) { user(id: $id) { name } }`, "", nil, []*Error{
+			{Message: `Variable "$id" of required type "ID!" was not provided.`, Locations: []Location{{Line: 1, Column: 9}}},
+		}},
+		{"Struct variable", `query Q($p: Page) { users(page: $p) { name } }`, "", map[string]any{"p": page{First: "ten"}}, []*Error{
+			{Message: `Variable "$p" got invalid value "ten" at "p.first"; Int cannot represent non-integer value: "ten"`, Locations: []Location{{Line: 1, Column: 9}}},
+		}},
+		{"Operation name", `query A { user(id: 1) { name } } query B { user(id: 2) { name } }`, "", nil, []*Error{
+			{Message: "Must provide operation name if query contains multiple operations."},
+		}},
+		{"Unknown operation", `query A { user(id: 1) { name } }`, "B", nil, []*Error{
+			{Message: `Unknown operation named "B".`},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.query, tt.operationName, tt.variables)
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("expected *ValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(ve.Errors, ErrorList(tt.expected)) {
+				t.Errorf("expected %+v, got %+v", tt.expected, ve.Errors)
+			}
+		})
+	}
+}
+
+func TestValidator_SyntaxError(t *testing.T) {
+	v := &Validator{Schema: asttest.Schema(t, `type Query { a: Int }`)}
+	err := v.Validate(`{ a `, "", nil)
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Errors) != 1 {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+}
+
+func TestSchemaCache_Validate(t *testing.T) {
+	srv, requests, _ := introspectionServer(t)
+	c := &SchemaCache{TTL: time.Hour}
+	if err := c.Validate(context.Background(), srv.URL, `{ hello }`, "", nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := c.Validate(context.Background(), srv.URL, `{ goodbye }`, "", nil)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("expected *ValidationError, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected schema to be fetched once, got %d requests", got)
+	}
+}
@@ -0,0 +1,574 @@
+// Package contract emits contract variants of schemas, exposing only
+// elements selected by @tag applications, like Apollo GraphOS contracts:
+//
+//	doc, err := contract.Apply(schemaDoc, contract.Filter{Include: []string{"public"}, Exclude: []string{"beta"}})
+//
+// The @tag directive may be renamed by @link imports of Federation 2
+// subgraphs. Tags of type extensions apply to the types they extend.
+package contract
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/federation"
+	"github.com/gqlhub/gqlhub-core/introspection"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Filter selects elements of contract by tags. The zero value selects
+// the whole schema.
+type Filter struct {
+	// Include lists tags of fields of object and interface types in
+	// contract. Fields are selected when they or their types are tagged
+	// with any of them; all fields are selected when Include is empty.
+	Include []string
+
+	// Exclude lists tags of types, fields, arguments, input fields and
+	// enum values left out of contract, even when selected by Include.
+	Exclude []string
+}
+
+// Apply returns contract of schema doc selected by f, as a single
+// document without type extensions. Besides excluded elements it leaves
+// out:
+//
+//   - fields, arguments and input fields of types left out,
+//   - object, interface and input object types without fields, enum types
+//     without values and union types without members,
+//   - mutation and subscription root operations without fields,
+//   - types no longer reachable from root types and directive
+//     definitions.
+//
+// Apply returns error when contract is not a valid schema: when query
+// type has no fields, when required arguments or input fields would be
+// left out, or when types no longer have fields of interfaces they
+// implement. doc is not modified.
+func Apply(doc *ast.Document, f Filter) (*ast.Document, error) {
+	result, _, err := apply(doc, f)
+	return result, err
+}
+
+// Introspection returns contract of doc selected by f as introspection
+// result JSON, as served to clients of the contract.
+func Introspection(doc *ast.Document, f Filter) ([]byte, error) {
+	_, s, err := apply(doc, f)
+	if err != nil {
+		return nil, err
+	}
+	return introspection.MarshalSchema(s)
+}
+
+func apply(doc *ast.Document, f Filter) (*ast.Document, *schema.Schema, error) {
+	fed, err := federation.FromDocument(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("contract: %w", err)
+	}
+	merged, err := schema.Merge(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("contract: %w", err)
+	}
+	c := &contractor{
+		tag:     fed.DirectiveName("tag"),
+		include: set(f.Include),
+		exclude: set(f.Exclude),
+		removed: make(map[string]bool),
+	}
+	result, err := c.apply(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := schema.FromDocument(result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("contract: %w", err)
+	}
+	return result, s, nil
+}
+
+type contractor struct {
+	tag              string
+	include, exclude map[string]bool
+	removed          map[string]bool // Names of types left out.
+}
+
+func (c *contractor) apply(doc *ast.Document) (*ast.Document, error) {
+	var defs []ast.Definition
+	for _, def := range doc.Definitions {
+		def, err := c.filter(def)
+		if err != nil {
+			return nil, err
+		}
+		if def != nil {
+			defs = append(defs, def)
+		}
+	}
+
+	// Leaving out types leaves out fields of their types, which may leave
+	// out more types.
+	for changed := true; changed; {
+		changed = false
+		for i, def := range defs {
+			name := definitionName(def)
+			if name == "" || c.removed[name] {
+				continue
+			}
+			pruned, err := c.prune(def)
+			if err != nil {
+				return nil, err
+			}
+			defs[i] = pruned
+			if isEmpty(pruned) {
+				c.removed[name] = true
+				changed = true
+			}
+		}
+	}
+
+	roots := rootTypes(defs)
+	if c.removed[roots[ast.OperationTypeQuery]] {
+		return nil, fmt.Errorf("contract: query type %s has no fields", roots[ast.OperationTypeQuery])
+	}
+	reached := c.reachable(defs, roots)
+	var result []ast.Definition
+	for _, def := range defs {
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			s := *d
+			s.RootOperationDefs = c.rootOperations(d.RootOperationDefs)
+			def = &s
+		case *ast.SchemaExtension:
+			e := *d
+			e.RootOperationDefs = c.rootOperations(d.RootOperationDefs)
+			def = &e
+		case *ast.DirectiveDefinition:
+			for _, arg := range d.Arguments {
+				if name := namedType(arg.Type); c.removed[name] {
+					return nil, fmt.Errorf("contract: argument @%s(%s:) has type %s left out of contract", d.Name.Value, arg.Name.Value, name)
+				}
+			}
+		default:
+			if name := definitionName(def); c.removed[name] || !reached[name] {
+				continue
+			}
+		}
+		result = append(result, def)
+	}
+	if err := checkInterfaces(result); err != nil {
+		return nil, err
+	}
+	return &ast.Document{Definitions: result}, nil
+}
+
+// filter returns def without excluded and not included members, or nil
+// when def is excluded.
+func (c *contractor) filter(def ast.Definition) (ast.Definition, error) {
+	name := definitionName(def)
+	if name != "" && c.excluded(directivesOf(def)) {
+		c.removed[name] = true
+		return nil, nil
+	}
+	switch d := def.(type) {
+	case *ast.ObjectTypeDefinition:
+		fields, err := c.filterFields(name, c.included(d.Directives), d.Fields)
+		if err != nil {
+			return nil, err
+		}
+		o := *d
+		o.Fields = fields
+		return &o, nil
+	case *ast.InterfaceTypeDefinition:
+		fields, err := c.filterFields(name, c.included(d.Directives), d.Fields)
+		if err != nil {
+			return nil, err
+		}
+		i := *d
+		i.Fields = fields
+		return &i, nil
+	case *ast.InputObjectTypeDefinition:
+		fields, err := c.filterInputValues(name+".", "", d.Fields)
+		if err != nil {
+			return nil, err
+		}
+		i := *d
+		i.Fields = fields
+		return &i, nil
+	case *ast.EnumTypeDefinition:
+		e := *d
+		e.Values = slices.DeleteFunc(slices.Clone(d.Values), func(v *ast.EnumValueDefinition) bool {
+			return c.excluded(v.Directives)
+		})
+		return &e, nil
+	}
+	return def, nil
+}
+
+func (c *contractor) filterFields(typeName string, included bool, fields []*ast.FieldDefinition) ([]*ast.FieldDefinition, error) {
+	var result []*ast.FieldDefinition
+	for _, f := range fields {
+		if c.excluded(f.Directives) || len(c.include) > 0 && !included && !c.included(f.Directives) {
+			continue
+		}
+		args, err := c.filterInputValues(typeName+"."+f.Name.Value+"(", ":)", f.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != len(f.Arguments) {
+			copied := *f
+			copied.Arguments = args
+			f = &copied
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+// filterInputValues leaves out excluded arguments or input fields, whose
+// coordinates are prefix+name+suffix.
+func (c *contractor) filterInputValues(prefix, suffix string, values []*ast.InputValueDefinition) ([]*ast.InputValueDefinition, error) {
+	var result []*ast.InputValueDefinition
+	for _, v := range values {
+		if !c.excluded(v.Directives) {
+			result = append(result, v)
+			continue
+		}
+		if isRequired(v) {
+			return nil, fmt.Errorf("contract: required %s%s%s cannot be excluded", prefix, v.Name.Value, suffix)
+		}
+	}
+	return result, nil
+}
+
+// prune returns def without members referring to types left out.
+func (c *contractor) prune(def ast.Definition) (ast.Definition, error) {
+	name := definitionName(def)
+	switch d := def.(type) {
+	case *ast.ObjectTypeDefinition:
+		fields, err := c.pruneFields(name, d.Fields)
+		if err != nil {
+			return nil, err
+		}
+		o := *d
+		o.Interfaces, o.Fields = c.pruneNamedTypes(d.Interfaces), fields
+		return &o, nil
+	case *ast.InterfaceTypeDefinition:
+		fields, err := c.pruneFields(name, d.Fields)
+		if err != nil {
+			return nil, err
+		}
+		i := *d
+		i.Interfaces, i.Fields = c.pruneNamedTypes(d.Interfaces), fields
+		return &i, nil
+	case *ast.UnionTypeDefinition:
+		u := *d
+		u.Types = c.pruneNamedTypes(d.Types)
+		return &u, nil
+	case *ast.InputObjectTypeDefinition:
+		fields, err := c.pruneInputValues(name+".", "", d.Fields)
+		if err != nil {
+			return nil, err
+		}
+		i := *d
+		i.Fields = fields
+		return &i, nil
+	}
+	return def, nil
+}
+
+func (c *contractor) pruneFields(typeName string, fields []*ast.FieldDefinition) ([]*ast.FieldDefinition, error) {
+	var result []*ast.FieldDefinition
+	for _, f := range fields {
+		if c.removed[namedType(f.Type)] {
+			continue
+		}
+		args, err := c.pruneInputValues(typeName+"."+f.Name.Value+"(", ":)", f.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != len(f.Arguments) {
+			copied := *f
+			copied.Arguments = args
+			f = &copied
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+func (c *contractor) pruneInputValues(prefix, suffix string, values []*ast.InputValueDefinition) ([]*ast.InputValueDefinition, error) {
+	var result []*ast.InputValueDefinition
+	for _, v := range values {
+		name := namedType(v.Type)
+		if !c.removed[name] {
+			result = append(result, v)
+			continue
+		}
+		if isRequired(v) {
+			return nil, fmt.Errorf("contract: required %s%s%s has type %s left out of contract", prefix, v.Name.Value, suffix, name)
+		}
+	}
+	return result, nil
+}
+
+func (c *contractor) pruneNamedTypes(types []*ast.NamedType) []*ast.NamedType {
+	var result []*ast.NamedType
+	for _, t := range types {
+		if !c.removed[t.Name.Value] {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func (c *contractor) rootOperations(roots []*ast.RootOperationTypeDefinition) []*ast.RootOperationTypeDefinition {
+	var result []*ast.RootOperationTypeDefinition
+	for _, r := range roots {
+		if !c.removed[r.Type.Name.Value] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// reachable returns names of types reachable from root types and
+// arguments of directive definitions. Interfaces reach their
+// implementations.
+func (c *contractor) reachable(defs []ast.Definition, roots map[ast.OperationType]string) map[string]bool {
+	byName := make(map[string]ast.Definition)
+	implementations := make(map[string][]string)
+	var queue []string
+	for _, def := range defs {
+		if name := definitionName(def); name != "" && !c.removed[name] {
+			byName[name] = def
+		}
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			for _, i := range d.Interfaces {
+				implementations[i.Name.Value] = append(implementations[i.Name.Value], d.Name.Value)
+			}
+		case *ast.InterfaceTypeDefinition:
+			for _, i := range d.Interfaces {
+				implementations[i.Name.Value] = append(implementations[i.Name.Value], d.Name.Value)
+			}
+		case *ast.DirectiveDefinition:
+			for _, arg := range d.Arguments {
+				queue = append(queue, namedType(arg.Type))
+			}
+		}
+	}
+	for _, name := range roots {
+		queue = append(queue, name)
+	}
+
+	reached := make(map[string]bool)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		def := byName[name]
+		if reached[name] || def == nil {
+			continue
+		}
+		reached[name] = true
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			queue = appendNamedTypes(queue, d.Interfaces)
+			queue = appendFieldTypes(queue, d.Fields)
+		case *ast.InterfaceTypeDefinition:
+			queue = appendNamedTypes(queue, d.Interfaces)
+			queue = appendFieldTypes(queue, d.Fields)
+			queue = append(queue, implementations[name]...)
+		case *ast.UnionTypeDefinition:
+			queue = appendNamedTypes(queue, d.Types)
+		case *ast.InputObjectTypeDefinition:
+			for _, f := range d.Fields {
+				queue = append(queue, namedType(f.Type))
+			}
+		}
+	}
+	return reached
+}
+
+func appendNamedTypes(queue []string, types []*ast.NamedType) []string {
+	for _, t := range types {
+		queue = append(queue, t.Name.Value)
+	}
+	return queue
+}
+
+func appendFieldTypes(queue []string, fields []*ast.FieldDefinition) []string {
+	for _, f := range fields {
+		queue = append(queue, namedType(f.Type))
+		for _, arg := range f.Arguments {
+			queue = append(queue, namedType(arg.Type))
+		}
+	}
+	return queue
+}
+
+// checkInterfaces returns error when a type lacks a field of an interface
+// it implements, which happens when tags select the field of only one of
+// them.
+func checkInterfaces(defs []ast.Definition) error {
+	interfaces := make(map[string]*ast.InterfaceTypeDefinition)
+	for _, def := range defs {
+		if d, ok := def.(*ast.InterfaceTypeDefinition); ok {
+			interfaces[d.Name.Value] = d
+		}
+	}
+	for _, def := range defs {
+		var implements []*ast.NamedType
+		var fields []*ast.FieldDefinition
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			implements, fields = d.Interfaces, d.Fields
+		case *ast.InterfaceTypeDefinition:
+			implements, fields = d.Interfaces, d.Fields
+		default:
+			continue
+		}
+		for _, i := range implements {
+			iface := interfaces[i.Name.Value]
+			if iface == nil {
+				continue
+			}
+			for _, f := range iface.Fields {
+				if !slices.ContainsFunc(fields, func(other *ast.FieldDefinition) bool { return other.Name.Value == f.Name.Value }) {
+					return fmt.Errorf("contract: field %s.%s of interface %s is left out of contract", definitionName(def), f.Name.Value, iface.Name.Value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *contractor) excluded(directives []*ast.Directive) bool {
+	return c.tagged(directives, c.exclude)
+}
+
+func (c *contractor) included(directives []*ast.Directive) bool {
+	return c.tagged(directives, c.include)
+}
+
+// tagged reports whether directives apply @tag with any of tags.
+func (c *contractor) tagged(directives []*ast.Directive, tags map[string]bool) bool {
+	for _, d := range directives {
+		if d.Name.Value != c.tag {
+			continue
+		}
+		for _, arg := range d.Arguments {
+			if s, ok := arg.Value.(*ast.StringValue); ok && arg.Name.Value == "name" && tags[s.Value] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func set(values []string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+// rootTypes returns names of root operation types of defs.
+func rootTypes(defs []ast.Definition) map[ast.OperationType]string {
+	roots := make(map[ast.OperationType]string)
+	explicit := false
+	for _, def := range defs {
+		var defs []*ast.RootOperationTypeDefinition
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			defs = d.RootOperationDefs
+		case *ast.SchemaExtension:
+			defs = d.RootOperationDefs
+		default:
+			continue
+		}
+		for _, r := range defs {
+			explicit = true
+			roots[r.OperationType] = r.Type.Name.Value
+		}
+	}
+	if !explicit {
+		roots[ast.OperationTypeQuery] = "Query"
+		roots[ast.OperationTypeMutation] = "Mutation"
+		roots[ast.OperationTypeSubscription] = "Subscription"
+	}
+	return roots
+}
+
+func definitionName(def ast.Definition) string {
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		return d.Name.Value
+	case *ast.ObjectTypeDefinition:
+		return d.Name.Value
+	case *ast.InterfaceTypeDefinition:
+		return d.Name.Value
+	case *ast.UnionTypeDefinition:
+		return d.Name.Value
+	case *ast.EnumTypeDefinition:
+		return d.Name.Value
+	case *ast.InputObjectTypeDefinition:
+		return d.Name.Value
+	}
+	return ""
+}
+
+func directivesOf(def ast.Definition) []*ast.Directive {
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		return d.Directives
+	case *ast.ObjectTypeDefinition:
+		return d.Directives
+	case *ast.InterfaceTypeDefinition:
+		return d.Directives
+	case *ast.UnionTypeDefinition:
+		return d.Directives
+	case *ast.EnumTypeDefinition:
+		return d.Directives
+	case *ast.InputObjectTypeDefinition:
+		return d.Directives
+	}
+	return nil
+}
+
+// isEmpty reports whether type definition has no fields, values or
+// members left.
+func isEmpty(def ast.Definition) bool {
+	switch d := def.(type) {
+	case *ast.ObjectTypeDefinition:
+		return len(d.Fields) == 0
+	case *ast.InterfaceTypeDefinition:
+		return len(d.Fields) == 0
+	case *ast.UnionTypeDefinition:
+		return len(d.Types) == 0
+	case *ast.EnumTypeDefinition:
+		return len(d.Values) == 0
+	case *ast.InputObjectTypeDefinition:
+		return len(d.Fields) == 0
+	}
+	return false
+}
+
+func isRequired(v *ast.InputValueDefinition) bool {
+	_, nonNull := v.Type.(*ast.NonNullType)
+	return nonNull && v.DefaultValue == nil
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch wrapped := t.(type) {
+		case *ast.NonNullType:
+			t = wrapped.Type
+		case *ast.ListType:
+			t = wrapped.Type
+		case *ast.NamedType:
+			return wrapped.Name.Value
+		default:
+			return ""
+		}
+	}
+}
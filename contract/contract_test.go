@@ -0,0 +1,230 @@
+package contract
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+const testSchema = `directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+type Query {
+  user(id: ID!, debug: Boolean @tag(name: "internal")): User @tag(name: "public")
+  search(filter: Filter): [Result] @tag(name: "public")
+  audit: [AuditEntry]
+}
+type Mutation { deleteUser(id: ID!): Boolean @tag(name: "internal") }
+interface Node { id: ID! }
+type User implements Node @tag(name: "public") { id: ID! name: String role: Role secret: String @tag(name: "internal") }
+type Post implements Node { id: ID! @tag(name: "public") title: String @tag(name: "public") }
+type AuditEntry @tag(name: "internal") { at: String }
+union Result = User | Post | AuditEntry
+enum Role { ADMIN @tag(name: "internal") MEMBER }
+input Filter { term: String audit: AuditFilter }
+input AuditFilter @tag(name: "internal") { after: String }
+extend type Post @tag(name: "public")
+`
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected string
+	}{
+		{
+			name:   "exclude",
+			filter: Filter{Exclude: []string{"internal"}},
+			expected: `directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+
+type Query {
+  user(id: ID!): User @tag(name: "public")
+  search(filter: Filter): [Result] @tag(name: "public")
+}
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node @tag(name: "public") {
+  id: ID!
+  name: String
+  role: Role
+}
+
+type Post implements Node @tag(name: "public") {
+  id: ID! @tag(name: "public")
+  title: String @tag(name: "public")
+}
+
+union Result = User | Post
+
+enum Role {
+  MEMBER
+}
+
+input Filter {
+  term: String
+}`,
+		},
+		{
+			name:   "include",
+			filter: Filter{Include: []string{"public"}},
+			expected: `directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+
+type Query {
+  user(id: ID!, debug: Boolean @tag(name: "internal")): User @tag(name: "public")
+  search(filter: Filter): [Result] @tag(name: "public")
+}
+
+type User @tag(name: "public") {
+  id: ID!
+  name: String
+  role: Role
+  secret: String @tag(name: "internal")
+}
+
+type Post @tag(name: "public") {
+  id: ID! @tag(name: "public")
+  title: String @tag(name: "public")
+}
+
+union Result = User | Post
+
+enum Role {
+  ADMIN @tag(name: "internal")
+  MEMBER
+}
+
+input Filter {
+  term: String
+  audit: AuditFilter
+}
+
+input AuditFilter @tag(name: "internal") {
+  after: String
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Apply(asttest.Parse(t, testSchema), tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := printer.PrintDocument(doc); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestApply_FederationLink(t *testing.T) {
+	doc := asttest.Parse(t, `extend schema @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", {name: "@tag", as: "@label"}])
+directive @label(name: String!) repeatable on FIELD_DEFINITION | OBJECT
+type Query { a: String @label(name: "internal") b: String @tag(name: "internal") }
+directive @tag(name: String!) on FIELD_DEFINITION`)
+	result, err := Apply(doc, Filter{Exclude: []string{"internal"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `type Query {
+  b: String @tag(name: "internal")
+}`
+	var query ast.Node
+	for _, def := range result.Definitions {
+		if d, ok := def.(*ast.ObjectTypeDefinition); ok {
+			query = d
+		}
+	}
+	if got := printer.Print(query); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	const tag = `directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT | INTERFACE | ARGUMENT_DEFINITION | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+`
+	tests := []struct {
+		name     string
+		input    string
+		filter   Filter
+		expected string
+	}{
+		{
+			name:     "empty query",
+			input:    tag + `type Query { a: String @tag(name: "internal") }`,
+			filter:   Filter{Exclude: []string{"internal"}},
+			expected: "contract: query type Query has no fields",
+		},
+		{
+			name:     "excluded query",
+			input:    tag + `type Query @tag(name: "internal") { a: String }`,
+			filter:   Filter{Exclude: []string{"internal"}},
+			expected: "contract: query type Query has no fields",
+		},
+		{
+			name:     "required argument",
+			input:    tag + `type Query { a(x: Int! @tag(name: "internal")): String }`,
+			filter:   Filter{Exclude: []string{"internal"}},
+			expected: "contract: required Query.a(x:) cannot be excluded",
+		},
+		{
+			name:     "required argument of excluded type",
+			input:    tag + `type Query { a(x: X!): String b: String } input X @tag(name: "internal") { y: Int }`,
+			filter:   Filter{Exclude: []string{"internal"}},
+			expected: "contract: required Query.a(x:) has type X left out of contract",
+		},
+		{
+			name:     "required input field",
+			input:    tag + `type Query { a(x: X): String } input X { y: Int! @tag(name: "internal") z: Int }`,
+			filter:   Filter{Exclude: []string{"internal"}},
+			expected: "contract: required X.y cannot be excluded",
+		},
+		{
+			name:     "interface field",
+			input:    tag + `type Query { a: I } interface I { x: Int y: Int } type T implements I { x: Int y: Int @tag(name: "internal") }`,
+			filter:   Filter{Exclude: []string{"internal"}},
+			expected: "contract: field T.y of interface I is left out of contract",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply(asttest.Parse(t, tt.input), tt.filter)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	data, err := Introspection(asttest.Parse(t, testSchema), Filter{Exclude: []string{"internal"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result struct {
+		Schema struct {
+			MutationType *struct{ Name string }
+			Types        []struct{ Name string }
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Schema.MutationType != nil {
+		t.Errorf("expected no mutation type, got %s", result.Schema.MutationType.Name)
+	}
+	var names []string
+	for _, typ := range result.Schema.Types {
+		if !strings.HasPrefix(typ.Name, "__") {
+			names = append(names, typ.Name)
+		}
+	}
+	expected := "Query Node User Post Result Role Filter Int Float String Boolean ID"
+	if got := strings.Join(names, " "); got != expected {
+		t.Errorf("expected types %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,243 @@
+// Package coordinate implements schema coordinates: parsing strings like
+// "User.friends(first:)", resolving them against a schema document and
+// producing coordinates for schema elements.
+//
+// https://spec.graphql.org/draft/#sec-Schema-Coordinates
+package coordinate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// Coordinate identifies element of a schema.
+type Coordinate struct {
+	Directive bool   // Name refers to directive rather than type.
+	Name      string // Type or directive name.
+	Member    string // Field, input field or enum value name.
+	Argument  string // Field or directive argument name.
+}
+
+// Type returns coordinate of named type.
+func Type(name string) Coordinate {
+	return Coordinate{Name: name}
+}
+
+// Member returns coordinate of field, input field or enum value.
+func Member(typeName, member string) Coordinate {
+	return Coordinate{Name: typeName, Member: member}
+}
+
+// Argument returns coordinate of field argument.
+func Argument(typeName, field, arg string) Coordinate {
+	return Coordinate{Name: typeName, Member: field, Argument: arg}
+}
+
+// Directive returns coordinate of directive.
+func Directive(name string) Coordinate {
+	return Coordinate{Directive: true, Name: name}
+}
+
+// DirectiveArgument returns coordinate of directive argument.
+func DirectiveArgument(directive, arg string) Coordinate {
+	return Coordinate{Directive: true, Name: directive, Argument: arg}
+}
+
+func (c Coordinate) String() string {
+	var b strings.Builder
+	if c.Directive {
+		b.WriteByte('@')
+	}
+	b.WriteString(c.Name)
+	if c.Member != "" {
+		b.WriteByte('.')
+		b.WriteString(c.Member)
+	}
+	if c.Argument != "" {
+		b.WriteByte('(')
+		b.WriteString(c.Argument)
+		b.WriteString(":)")
+	}
+	return b.String()
+}
+
+// Parse parses schema coordinate. Ignored tokens (whitespace, commas,
+// comments) are not allowed within coordinates.
+func Parse(s string) (Coordinate, error) {
+	var c Coordinate
+	rest := s
+	if strings.HasPrefix(rest, "@") {
+		c.Directive = true
+		rest = rest[1:]
+	}
+
+	var ok bool
+	if c.Name, rest, ok = cutName(rest); !ok {
+		return Coordinate{}, parseError(s, "expected name")
+	}
+
+	if !c.Directive && strings.HasPrefix(rest, ".") {
+		if c.Member, rest, ok = cutName(rest[1:]); !ok {
+			return Coordinate{}, parseError(s, "expected member name after '.'")
+		}
+	}
+
+	if strings.HasPrefix(rest, "(") && (c.Directive || c.Member != "") {
+		if c.Argument, rest, ok = cutName(rest[1:]); !ok {
+			return Coordinate{}, parseError(s, "expected argument name after '('")
+		}
+		if !strings.HasPrefix(rest, ":)") {
+			return Coordinate{}, parseError(s, "expected ':)' after argument name")
+		}
+		rest = rest[2:]
+	}
+
+	if rest != "" {
+		return Coordinate{}, parseError(s, fmt.Sprintf("unexpected %q", rest))
+	}
+	return c, nil
+}
+
+// MustParse is like Parse but panics on error.
+func MustParse(s string) Coordinate {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseError(s, msg string) error {
+	return fmt.Errorf("invalid schema coordinate %q: %s", s, msg)
+}
+
+func cutName(s string) (name, rest string, ok bool) {
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		if ch == '_' || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') || (i > 0 && '0' <= ch && ch <= '9') {
+			i++
+			continue
+		}
+		break
+	}
+	return s[:i], s[i:], i > 0
+}
+
+// ErrNotFound is returned when coordinate does not refer to any element.
+var ErrNotFound = errors.New("schema element not found")
+
+// Resolve returns definition c refers to in schema: type definition,
+// *ast.FieldDefinition, *ast.InputValueDefinition (argument or input
+// field), *ast.EnumValueDefinition or *ast.DirectiveDefinition.
+// Members declared in type extensions are resolved as well.
+func Resolve(schema *ast.Document, c Coordinate) (ast.Node, error) {
+	var found ast.Node
+	Walk(schema, func(other Coordinate, node ast.Node) bool {
+		if other == c {
+			found = node
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, c)
+	}
+	return found, nil
+}
+
+// Of returns coordinate of node defined in schema.
+func Of(schema *ast.Document, node ast.Node) (Coordinate, bool) {
+	var found Coordinate
+	var ok bool
+	Walk(schema, func(c Coordinate, other ast.Node) bool {
+		if other == node {
+			found, ok = c, true
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// Walk calls fn for every element of schema that has a coordinate, in
+// document order. Types are reported once, for their definition; members
+// of type extensions are reported under the extended type name. Walk stops
+// when fn returns false.
+func Walk(schema *ast.Document, fn func(c Coordinate, node ast.Node) bool) {
+	for _, def := range schema.Definitions {
+		if !walkDefinition(def, fn) {
+			return
+		}
+	}
+}
+
+func walkDefinition(def ast.Definition, fn func(Coordinate, ast.Node) bool) bool {
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		return fn(Type(d.Name.Value), d)
+	case *ast.ObjectTypeDefinition:
+		return fn(Type(d.Name.Value), d) && walkFields(d.Name.Value, d.Fields, fn)
+	case *ast.InterfaceTypeDefinition:
+		return fn(Type(d.Name.Value), d) && walkFields(d.Name.Value, d.Fields, fn)
+	case *ast.UnionTypeDefinition:
+		return fn(Type(d.Name.Value), d)
+	case *ast.EnumTypeDefinition:
+		return fn(Type(d.Name.Value), d) && walkEnumValues(d.Name.Value, d.Values, fn)
+	case *ast.InputObjectTypeDefinition:
+		return fn(Type(d.Name.Value), d) && walkInputFields(d.Name.Value, d.Fields, fn)
+	case *ast.DirectiveDefinition:
+		if !fn(Directive(d.Name.Value), d) {
+			return false
+		}
+		for _, arg := range d.Arguments {
+			if !fn(DirectiveArgument(d.Name.Value, arg.Name.Value), arg) {
+				return false
+			}
+		}
+	case *ast.ObjectTypeExtension:
+		return walkFields(d.Name.Value, d.Fields, fn)
+	case *ast.InterfaceTypeExtension:
+		return walkFields(d.Name.Value, d.Fields, fn)
+	case *ast.EnumTypeExtension:
+		return walkEnumValues(d.Name.Value, d.Values, fn)
+	case *ast.InputObjectTypeExtension:
+		return walkInputFields(d.Name.Value, d.Fields, fn)
+	}
+	return true
+}
+
+func walkFields(typeName string, fields []*ast.FieldDefinition, fn func(Coordinate, ast.Node) bool) bool {
+	for _, f := range fields {
+		if !fn(Member(typeName, f.Name.Value), f) {
+			return false
+		}
+		for _, arg := range f.Arguments {
+			if !fn(Argument(typeName, f.Name.Value, arg.Name.Value), arg) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func walkEnumValues(typeName string, values []*ast.EnumValueDefinition, fn func(Coordinate, ast.Node) bool) bool {
+	for _, v := range values {
+		if !fn(Member(typeName, v.Name.Value), v) {
+			return false
+		}
+	}
+	return true
+}
+
+func walkInputFields(typeName string, fields []*ast.InputValueDefinition, fn func(Coordinate, ast.Node) bool) bool {
+	for _, f := range fields {
+		if !fn(Member(typeName, f.Name.Value), f) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package coordinate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Coordinate
+	}{
+		{"User", Type("User")},
+		{"User.friends", Member("User", "friends")},
+		{"User.friends(first:)", Argument("User", "friends", "first")},
+		{"@deprecated", Directive("deprecated")},
+		{"@deprecated(reason:)", DirectiveArgument("deprecated", "reason")},
+		{"__Type.fields", Member("__Type", "fields")},
+		{"Query.user2(id_1:)", Argument("Query", "user2", "id_1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+			if got.String() != tt.input {
+				t.Errorf("expected %q to round-trip, got %q", tt.input, got.String())
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"@",
+		"1User",
+		"User.",
+		"User .friends",
+		"User(first:)",
+		"User.friends(first)",
+		"User.friends(first:",
+		"User.friends(:)",
+		"@deprecated.reason",
+		"User.friends(first:).x",
+		"User.friends.name",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+const sdl = `
+type Query { user(id: ID!): User }
+type User { name: String }
+extend type User { friends(first: Int): [User] }
+enum Role { ADMIN }
+input Filter { role: Role }
+union Result = User
+directive @auth(role: Role) on FIELD_DEFINITION
+`
+
+func TestResolve(t *testing.T) {
+	doc := asttest.Parse(t, sdl)
+	query := doc.Definitions[0].(*ast.ObjectTypeDefinition)
+	ext := doc.Definitions[2].(*ast.ObjectTypeExtension)
+	enum := doc.Definitions[3].(*ast.EnumTypeDefinition)
+	input := doc.Definitions[4].(*ast.InputObjectTypeDefinition)
+	directive := doc.Definitions[6].(*ast.DirectiveDefinition)
+
+	tests := []struct {
+		coordinate string
+		expected   ast.Node
+	}{
+		{"Query", query},
+		{"Query.user", query.Fields[0]},
+		{"Query.user(id:)", query.Fields[0].Arguments[0]},
+		{"User.friends(first:)", ext.Fields[0].Arguments[0]},
+		{"Role.ADMIN", enum.Values[0]},
+		{"Filter.role", input.Fields[0]},
+		{"Result", doc.Definitions[5]},
+		{"@auth", directive},
+		{"@auth(role:)", directive.Arguments[0]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.coordinate, func(t *testing.T) {
+			c := MustParse(tt.coordinate)
+			got, err := Resolve(doc, c)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %T at %d, got %T at %d", tt.expected, tt.expected.Pos(), got, got.Pos())
+			}
+			back, ok := Of(doc, got)
+			if !ok || back != c {
+				t.Errorf("expected Of to return %s, got %s", c, back)
+			}
+		})
+	}
+}
+
+func TestResolve_NotFound(t *testing.T) {
+	doc := asttest.Parse(t, sdl)
+	for _, c := range []string{"Missing", "Query.missing", "Query.user(missing:)", "Result.User", "@missing", "@auth(missing:)"} {
+		if _, err := Resolve(doc, MustParse(c)); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: expected ErrNotFound, got %v", c, err)
+		}
+	}
+}
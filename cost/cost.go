@@ -0,0 +1,253 @@
+// Package cost computes costs of GraphQL-over-HTTP requests with package
+// analysis, reports them in response headers and extensions, and charges
+// them to per-client token bucket budgets, rejecting requests exceeding
+// budgets before they reach the GraphQL handler:
+//
+//	h := cost.Middleware(graphqlHandler, cost.Options{
+//		Schema: s,
+//		Client: func(r *http.Request) string { return r.Header.Get("X-Api-Key") },
+//		Store:  cost.NewMemoryStore(cost.Bucket{Capacity: 10000, Rate: 100}),
+//	})
+package cost
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/analysis"
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/response"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Response headers set by Middleware.
+const (
+	HeaderCost      = "X-GraphQL-Cost"             // Cost of request.
+	HeaderRemaining = "X-GraphQL-Budget-Remaining" // Tokens left in budget of client.
+)
+
+// Error codes in extensions of errors of rejected requests.
+const (
+	CodeCostLimitExceeded = "COST_LIMIT_EXCEEDED"
+	CodeBudgetExceeded    = "COST_BUDGET_EXCEEDED"
+)
+
+const (
+	maxRequestBody          = 1 << 20 // Longest request body cost is computed for.
+	graphQLResponseJSONType = "application/graphql-response+json"
+)
+
+// Options configure Middleware.
+type Options struct {
+	// Schema and Weights compute costs with analysis.OperationComplexity.
+	Schema  *schema.Schema
+	Weights analysis.Weights
+
+	// MaxCost rejects requests costing more with status 400; zero does
+	// not limit cost of single requests.
+	MaxCost int
+
+	// Client returns key of budget request is charged to, e.g. API key or
+	// user ID. Requests are not charged when Client or Store is nil or it
+	// returns "".
+	Client func(r *http.Request) string
+
+	// Store keeps budgets of clients. Requests costing more than budget
+	// left are rejected with status 429.
+	Store Store
+
+	// Extensions adds "cost" to extensions of JSON responses, with
+	// "estimated" cost and "remaining" budget when charged. Responses are
+	// buffered to rewrite them, so it must not be enabled for handlers
+	// streaming responses, e.g. subscriptions over server-sent events.
+	Extensions bool
+}
+
+// Middleware returns handler computing cost of GraphQL requests served
+// by next. Requests next would reject anyway, i.e. not GET or POST with
+// JSON body, without query or with invalid query, are passed to it
+// without being charged. Requests are also passed through without being
+// charged when Store fails, so outages of shared stores do not take the
+// API down.
+func Middleware(next http.Handler, opts Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cost, ok := requestCost(r, opts)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set(HeaderCost, strconv.Itoa(cost))
+		ext := map[string]any{"estimated": cost}
+		if opts.MaxCost > 0 && cost > opts.MaxCost {
+			reject(w, r, http.StatusBadRequest, &response.Error{
+				Message:    fmt.Sprintf("Operation cost %d exceeds limit %d", cost, opts.MaxCost),
+				Extensions: map[string]any{"code": CodeCostLimitExceeded, "cost": ext},
+			})
+			return
+		}
+
+		var client string
+		if opts.Client != nil && opts.Store != nil {
+			client = opts.Client(r)
+		}
+		if client != "" {
+			remaining, ok, err := opts.Store.Take(r.Context(), client, cost)
+			if err == nil {
+				w.Header().Set(HeaderRemaining, strconv.Itoa(remaining))
+				ext["remaining"] = remaining
+				if !ok {
+					reject(w, r, http.StatusTooManyRequests, &response.Error{
+						Message:    fmt.Sprintf("Operation cost %d exceeds remaining budget %d", cost, remaining),
+						Extensions: map[string]any{"code": CodeBudgetExceeded, "cost": ext},
+					})
+					return
+				}
+			}
+		}
+
+		if !opts.Extensions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		buf := &bufferedWriter{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+		buf.flush(w, ext)
+	})
+}
+
+// requestCost returns cost of GraphQL request r, restoring its body for
+// the next handler.
+func requestCost(r *http.Request, opts Options) (int, bool) {
+	var params struct {
+		Query         string `json:"query"`
+		OperationName string `json:"operationName"`
+	}
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		params.Query, params.OperationName = q.Get("query"), q.Get("operationName")
+	case http.MethodPost:
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" || r.Body == nil {
+			return 0, false
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		if err != nil || json.Unmarshal(body, &params) != nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if params.Query == "" {
+		return 0, false
+	}
+
+	p, err := parser.New(lexer.New(params.Query))
+	if err != nil {
+		return 0, false
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		return 0, false
+	}
+	op := operation(doc, params.OperationName)
+	if op == nil {
+		return 0, false
+	}
+	return analysis.OperationComplexity(doc, op, opts.Schema, opts.Weights), true
+}
+
+// operation returns operation of doc selected by name, or nil.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "" && found != nil:
+			return nil
+		case name == "" || op.Name != nil && op.Name.Value == name:
+			found = op
+		}
+	}
+	return found
+}
+
+// reject writes request error response.
+func reject(w http.ResponseWriter, r *http.Request, status int, e *response.Error) {
+	contentType := "application/json"
+	if strings.Contains(r.Header.Get("Accept"), graphQLResponseJSONType) {
+		contentType = graphQLResponseJSONType
+	}
+	body, _ := json.Marshal(struct {
+		Errors []*response.Error `json:"errors"`
+	}{[]*response.Error{e}})
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// bufferedWriter buffers response to add cost to its extensions.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header         { return b.header }
+func (b *bufferedWriter) WriteHeader(status int)      { b.status = status }
+func (b *bufferedWriter) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// flush writes buffered response to w, with ext added to extensions of
+// JSON responses.
+func (b *bufferedWriter) flush(w http.ResponseWriter, ext map[string]any) {
+	body := b.body.Bytes()
+	mediaType, _, _ := mime.ParseMediaType(b.header.Get("Content-Type"))
+	if mediaType == "application/json" || mediaType == graphQLResponseJSONType {
+		if withCost, err := addExtension(body, "cost", ext); err == nil {
+			body = withCost
+			b.header.Del("Content-Length")
+		}
+	}
+	w.WriteHeader(b.status)
+	w.Write(body)
+}
+
+// addExtension returns JSON encoded response body with extension added.
+// Members of response other than extensions are kept as they are.
+func addExtension(body []byte, name string, value any) ([]byte, error) {
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	extensions := make(map[string]any)
+	if raw, ok := resp["extensions"]; ok {
+		var existing map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return nil, err
+		}
+		for k, v := range existing {
+			extensions[k] = v
+		}
+	}
+	extensions[name] = value
+	encoded, err := json.Marshal(extensions)
+	if err != nil {
+		return nil, err
+	}
+	resp["extensions"] = encoded
+	return json.Marshal(resp)
+}
@@ -0,0 +1,149 @@
+package cost
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+// echo responds with request body, so tests see whether it was restored.
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"data":null,"extensions":{"request":` + string(body) + `}}`))
+})
+
+func post(h http.Handler, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"`+query+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", "k")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	h := Middleware(echo, Options{
+		Schema:  asttest.Schema(t, `type Query { user: User users(first: Int): [User] } type User { id: ID name: String }`),
+		Client:  func(r *http.Request) string { return r.Header.Get("X-Api-Key") },
+		Store:   NewMemoryStore(Bucket{Capacity: 25}),
+		MaxCost: 50,
+	})
+
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		cost      string
+		remaining string
+		body      string
+	}{
+		{"charged", "{ users(first: 10) { id } }", 200, "11", "14", `{"data":null,"extensions":{"request":{"query":"{ users(first: 10) { id } }"}}}`},
+		{"over limit", "{ users(first: 100) { id } }", 400, "101", "", `{"errors":[{"message":"Operation cost 101 exceeds limit 50","extensions":{"code":"COST_LIMIT_EXCEEDED","cost":{"estimated":101}}}]}`},
+		{"over budget", "{ users(first: 20) { id } }", 429, "21", "14", `{"errors":[{"message":"Operation cost 21 exceeds remaining budget 14","extensions":{"code":"COST_BUDGET_EXCEEDED","cost":{"estimated":21,"remaining":14}}}]}`},
+		{"within budget", "{ user { id name } }", 200, "3", "11", `{"data":null,"extensions":{"request":{"query":"{ user { id name } }"}}}`},
+		{"invalid query", "{ user", 200, "", "", `{"data":null,"extensions":{"request":{"query":"{ user"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := post(h, tt.query)
+			if rec.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			if got := rec.Header().Get(HeaderCost); got != tt.cost {
+				t.Errorf("expected cost %q, got %q", tt.cost, got)
+			}
+			if got := rec.Header().Get(HeaderRemaining); got != tt.remaining {
+				t.Errorf("expected remaining %q, got %q", tt.remaining, got)
+			}
+			if got := rec.Body.String(); got != tt.body {
+				t.Errorf("expected body:\n%s\ngot:\n%s", tt.body, got)
+			}
+		})
+	}
+}
+
+func TestMiddleware_GET(t *testing.T) {
+	h := Middleware(echo, Options{Schema: asttest.Schema(t, `type Query { user: User users(first: Int): [User] } type User { id: ID name: String }`)})
+	q := url.Values{"query": {"query A { user { id } } query B { users(first: 5) { id } }"}, "operationName": {"B"}}
+	req := httptest.NewRequest(http.MethodGet, "/graphql?"+q.Encode(), nil)
+	req.Header.Set("Accept", "application/graphql-response+json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get(HeaderCost); got != "6" {
+		t.Errorf("expected cost 6, got %q", got)
+	}
+	if got := rec.Header().Get(HeaderRemaining); got != "" {
+		t.Errorf("expected no budget header, got %q", got)
+	}
+}
+
+func TestMiddleware_Extensions(t *testing.T) {
+	h := Middleware(echo, Options{
+		Schema:     asttest.Schema(t, `type Query { user: User users(first: Int): [User] } type User { id: ID name: String }`),
+		Client:     func(*http.Request) string { return "client" },
+		Store:      NewMemoryStore(Bucket{Capacity: 100}),
+		Extensions: true,
+	})
+	rec := post(h, "{ user { id } }")
+	expected := `{"data":null,"extensions":{"cost":{"estimated":2,"remaining":98},"request":{"query":"{ user { id } }"}}}`
+	if got := rec.Body.String(); got != expected {
+		t.Errorf("expected body:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, int) (int, bool, error) {
+	return 0, false, context.DeadlineExceeded
+}
+
+func TestMiddleware_StoreError(t *testing.T) {
+	h := Middleware(echo, Options{Schema: asttest.Schema(t, `type Query { user: User users(first: Int): [User] } type User { id: ID name: String }`), Client: func(*http.Request) string { return "client" }, Store: failingStore{}})
+	rec := post(h, "{ user { id } }")
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get(HeaderRemaining); got != "" {
+		t.Errorf("expected no budget header, got %q", got)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore(Bucket{Capacity: 10, Rate: 2})
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	steps := []struct {
+		advance   time.Duration
+		client    string
+		take      int
+		remaining int
+		ok        bool
+	}{
+		{0, "a", 8, 2, true},
+		{0, "a", 3, 2, false},
+		{0, "b", 10, 0, true},
+		{time.Second, "a", 3, 1, true},
+		{10 * time.Second, "a", 10, 0, true},
+		{500 * time.Millisecond, "a", 2, 1, false},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		remaining, ok, err := s.Take(ctx, step.client, step.take)
+		if err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if remaining != step.remaining || ok != step.ok {
+			t.Errorf("step %d: expected %d, %t, got %d, %t", i, step.remaining, step.ok, remaining, ok)
+		}
+	}
+}
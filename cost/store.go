@@ -0,0 +1,79 @@
+package cost
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Store keeps budgets of clients as token buckets. Implementations backed
+// by shared storage, e.g. Redis, share budgets between server instances.
+type Store interface {
+	// Take takes n tokens from bucket of client when it holds at least n.
+	// It returns tokens left and whether they were taken.
+	Take(ctx context.Context, client string, n int) (remaining int, ok bool, err error)
+}
+
+// Bucket configures token buckets. Buckets start full and are refilled
+// continuously at Rate tokens per second up to Capacity.
+type Bucket struct {
+	Capacity int
+	Rate     float64
+}
+
+// MemoryStore is Store keeping buckets of a single server instance in
+// memory. It is safe for concurrent use.
+type MemoryStore struct {
+	bucket Bucket
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokens
+	takes   int
+}
+
+type tokens struct {
+	n       float64
+	updated time.Time
+}
+
+// sweepInterval is number of takes after which buckets refilled to
+// capacity are dropped, as they are equivalent to new ones.
+const sweepInterval = 1024
+
+// NewMemoryStore returns store of buckets configured by b.
+func NewMemoryStore(b Bucket) *MemoryStore {
+	return &MemoryStore{bucket: b, now: time.Now, buckets: make(map[string]*tokens)}
+}
+
+func (s *MemoryStore) Take(_ context.Context, client string, n int) (int, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.takes++; s.takes%sweepInterval == 0 {
+		for key, t := range s.buckets {
+			if s.refill(t, now) >= float64(s.bucket.Capacity) {
+				delete(s.buckets, key)
+			}
+		}
+	}
+
+	t := s.buckets[client]
+	if t == nil {
+		t = &tokens{n: float64(s.bucket.Capacity), updated: now}
+		s.buckets[client] = t
+	}
+	t.n = s.refill(t, now)
+	t.updated = now
+	if t.n < float64(n) {
+		return int(math.Floor(t.n)), false, nil
+	}
+	t.n -= float64(n)
+	return int(math.Floor(t.n)), true, nil
+}
+
+// refill returns tokens of t refilled until now.
+func (s *MemoryStore) refill(t *tokens, now time.Time) float64 {
+	return min(float64(s.bucket.Capacity), t.n+now.Sub(t.updated).Seconds()*s.bucket.Rate)
+}
@@ -0,0 +1,197 @@
+// Package directive indexes directive applications across documents,
+// powering directive-driven transforms, federation tooling and audits.
+package directive
+
+import (
+	"sort"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+)
+
+// Application is a single use of a directive.
+type Application struct {
+	Directive *ast.Directive
+	Target    ast.Node // Node directive is applied to.
+	Location  ast.DirectiveLocation
+
+	// Coordinate of the annotated type system element. It is zero for
+	// executable locations and schema definitions.
+	Coordinate coordinate.Coordinate
+}
+
+// Name returns directive name.
+func (a Application) Name() string {
+	return a.Directive.Name.Value
+}
+
+// Position returns position of the directive in source.
+func (a Application) Position() int {
+	return a.Directive.Pos()
+}
+
+// Arg returns value of argument passed to the directive or nil when the
+// argument is absent.
+func (a Application) Arg(name string) ast.Value {
+	for _, arg := range a.Directive.Arguments {
+		if arg.Name.Value == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
+
+// Index holds directive applications of a document grouped by name.
+type Index struct {
+	all    []Application
+	byName map[string][]Application
+}
+
+// NewIndex indexes every directive application in doc, both in type
+// system and executable definitions.
+func NewIndex(doc *ast.Document) *Index {
+	idx := &Index{byName: make(map[string][]Application)}
+	for _, def := range doc.Definitions {
+		idx.definition(def)
+	}
+	return idx
+}
+
+// Applications returns applications of directive name in document order.
+func (idx *Index) Applications(name string) []Application {
+	return idx.byName[name]
+}
+
+// All returns all applications in document order.
+func (idx *Index) All() []Application {
+	return idx.all
+}
+
+// Names returns sorted names of applied directives.
+func (idx *Index) Names() []string {
+	names := make([]string, 0, len(idx.byName))
+	for name := range idx.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// At returns applications on given location.
+func (idx *Index) At(loc ast.DirectiveLocation) []Application {
+	var result []Application
+	for _, a := range idx.all {
+		if a.Location == loc {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func (idx *Index) add(directives []*ast.Directive, target ast.Node, loc ast.DirectiveLocation, c coordinate.Coordinate) {
+	for _, d := range directives {
+		a := Application{Directive: d, Target: target, Location: loc, Coordinate: c}
+		idx.all = append(idx.all, a)
+		idx.byName[d.Name.Value] = append(idx.byName[d.Name.Value], a)
+	}
+}
+
+func (idx *Index) definition(def ast.Definition) {
+	switch d := def.(type) {
+	case *ast.OperationDefinition:
+		loc := ast.DirectiveLocationQuery
+		switch d.OperationType {
+		case ast.OperationTypeMutation:
+			loc = ast.DirectiveLocationMutation
+		case ast.OperationTypeSubscription:
+			loc = ast.DirectiveLocationSubscription
+		}
+		for _, v := range d.VariableDefs {
+			idx.add(v.Directives, v, ast.DirectiveLocationVariableDefinition, coordinate.Coordinate{})
+		}
+		idx.add(d.Directives, d, loc, coordinate.Coordinate{})
+		idx.selectionSet(d.SelectionSet)
+	case *ast.FragmentDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationFragmentDefinition, coordinate.Coordinate{})
+		idx.selectionSet(d.SelectionSet)
+	case *ast.SchemaDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationSchema, coordinate.Coordinate{})
+	case *ast.SchemaExtension:
+		idx.add(d.Directives, d, ast.DirectiveLocationSchema, coordinate.Coordinate{})
+	case *ast.ScalarTypeDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationScalar, coordinate.Type(d.Name.Value))
+	case *ast.ScalarTypeExtension:
+		idx.add(d.Directives, d, ast.DirectiveLocationScalar, coordinate.Type(d.Name.Value))
+	case *ast.ObjectTypeDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationObject, coordinate.Type(d.Name.Value))
+		idx.fields(d.Name.Value, d.Fields)
+	case *ast.ObjectTypeExtension:
+		idx.add(d.Directives, d, ast.DirectiveLocationObject, coordinate.Type(d.Name.Value))
+		idx.fields(d.Name.Value, d.Fields)
+	case *ast.InterfaceTypeDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationInterface, coordinate.Type(d.Name.Value))
+		idx.fields(d.Name.Value, d.Fields)
+	case *ast.InterfaceTypeExtension:
+		idx.add(d.Directives, d, ast.DirectiveLocationInterface, coordinate.Type(d.Name.Value))
+		idx.fields(d.Name.Value, d.Fields)
+	case *ast.UnionTypeDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationUnion, coordinate.Type(d.Name.Value))
+	case *ast.UnionTypeExtension:
+		idx.add(d.Directives, d, ast.DirectiveLocationUnion, coordinate.Type(d.Name.Value))
+	case *ast.EnumTypeDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationEnum, coordinate.Type(d.Name.Value))
+		idx.enumValues(d.Name.Value, d.Values)
+	case *ast.EnumTypeExtension:
+		idx.add(d.Directives, d, ast.DirectiveLocationEnum, coordinate.Type(d.Name.Value))
+		idx.enumValues(d.Name.Value, d.Values)
+	case *ast.InputObjectTypeDefinition:
+		idx.add(d.Directives, d, ast.DirectiveLocationInputObject, coordinate.Type(d.Name.Value))
+		idx.inputFields(d.Name.Value, d.Fields)
+	case *ast.InputObjectTypeExtension:
+		idx.add(d.Directives, d, ast.DirectiveLocationInputObject, coordinate.Type(d.Name.Value))
+		idx.inputFields(d.Name.Value, d.Fields)
+	case *ast.DirectiveDefinition:
+		for _, arg := range d.Arguments {
+			idx.add(arg.Directives, arg, ast.DirectiveLocationArgumentDefinition, coordinate.DirectiveArgument(d.Name.Value, arg.Name.Value))
+		}
+	}
+}
+
+func (idx *Index) fields(typeName string, fields []*ast.FieldDefinition) {
+	for _, f := range fields {
+		idx.add(f.Directives, f, ast.DirectiveLocationFieldDefinition, coordinate.Member(typeName, f.Name.Value))
+		for _, arg := range f.Arguments {
+			idx.add(arg.Directives, arg, ast.DirectiveLocationArgumentDefinition, coordinate.Argument(typeName, f.Name.Value, arg.Name.Value))
+		}
+	}
+}
+
+func (idx *Index) enumValues(typeName string, values []*ast.EnumValueDefinition) {
+	for _, v := range values {
+		idx.add(v.Directives, v, ast.DirectiveLocationEnumValue, coordinate.Member(typeName, v.Name.Value))
+	}
+}
+
+func (idx *Index) inputFields(typeName string, fields []*ast.InputValueDefinition) {
+	for _, f := range fields {
+		idx.add(f.Directives, f, ast.DirectiveLocationInputFieldDefinition, coordinate.Member(typeName, f.Name.Value))
+	}
+}
+
+func (idx *Index) selectionSet(ss *ast.SelectionSet) {
+	if ss == nil {
+		return
+	}
+	for _, sel := range ss.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			idx.add(s.Directives, s, ast.DirectiveLocationField, coordinate.Coordinate{})
+			idx.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			idx.add(s.Directives, s, ast.DirectiveLocationFragmentSpread, coordinate.Coordinate{})
+		case *ast.InlineFragment:
+			idx.add(s.Directives, s, ast.DirectiveLocationInlineFragment, coordinate.Coordinate{})
+			idx.selectionSet(s.SelectionSet)
+		}
+	}
+}
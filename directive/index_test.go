@@ -0,0 +1,92 @@
+package directive
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestIndex_Schema(t *testing.T) {
+	idx := NewIndex(asttest.Parse(t, `
+schema @link(url: "https://specs.apollo.dev/federation/v2.3") { query: Query }
+type Query @tag(name: "public") {
+  user(id: ID! @tag(name: "arg")): User @deprecated(reason: "use node")
+}
+extend type User @key(fields: "id") { id: ID! @tag(name: "id") }
+enum Role { ADMIN @deprecated }
+input Filter { role: Role @tag(name: "input") }
+directive @auth(role: Role @deprecated) on FIELD_DEFINITION
+`))
+
+	if got := idx.Names(); !reflect.DeepEqual(got, []string{"deprecated", "key", "link", "tag"}) {
+		t.Errorf("unexpected names: %v", got)
+	}
+
+	tags := idx.Applications("tag")
+	expected := []struct {
+		loc        ast.DirectiveLocation
+		coordinate string
+		name       string
+	}{
+		{ast.DirectiveLocationObject, "Query", "public"},
+		{ast.DirectiveLocationArgumentDefinition, "Query.user(id:)", "arg"},
+		{ast.DirectiveLocationFieldDefinition, "User.id", "id"},
+		{ast.DirectiveLocationInputFieldDefinition, "Filter.role", "input"},
+	}
+	if len(tags) != len(expected) {
+		t.Fatalf("expected %d @tag applications, got %d", len(expected), len(tags))
+	}
+	for i, e := range expected {
+		a := tags[i]
+		if a.Location != e.loc || a.Coordinate.String() != e.coordinate {
+			t.Errorf("%d: expected %s at %s, got %s at %s", i, e.coordinate, e.loc, a.Coordinate, a.Location)
+		}
+		if v, ok := a.Arg("name").(*ast.StringValue); !ok || v.Value != e.name {
+			t.Errorf("%d: unexpected name argument %v", i, a.Arg("name"))
+		}
+	}
+
+	deprecated := idx.Applications("deprecated")
+	if len(deprecated) != 3 {
+		t.Fatalf("expected 3 @deprecated applications, got %d", len(deprecated))
+	}
+	if c := deprecated[2].Coordinate.String(); c != "@auth(role:)" {
+		t.Errorf("unexpected coordinate %s", c)
+	}
+	if deprecated[1].Arg("reason") != nil {
+		t.Errorf("expected missing argument to be nil")
+	}
+	if len(idx.At(ast.DirectiveLocationSchema)) != 1 {
+		t.Errorf("expected single schema directive")
+	}
+}
+
+func TestIndex_Executable(t *testing.T) {
+	src := `query Q($a: Int @var) @op {
+  user @field { ...F @spread ... on User @inline { name @include(if: true) } }
+}
+fragment F on User @fragment { id }`
+	idx := NewIndex(asttest.Parse(t, src))
+
+	var got []ast.DirectiveLocation
+	for _, a := range idx.All() {
+		got = append(got, a.Location)
+		if src[a.Position()] != '@' {
+			t.Errorf("unexpected position of @%s", a.Name())
+		}
+	}
+	expected := []ast.DirectiveLocation{
+		ast.DirectiveLocationVariableDefinition,
+		ast.DirectiveLocationQuery,
+		ast.DirectiveLocationField,
+		ast.DirectiveLocationFragmentSpread,
+		ast.DirectiveLocationInlineFragment,
+		ast.DirectiveLocationField,
+		ast.DirectiveLocationFragmentDefinition,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected locations %v, got %v", expected, got)
+	}
+}
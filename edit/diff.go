@@ -0,0 +1,147 @@
+package edit
+
+import (
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+// Diff returns minimal edits turning src into dst. Both are compared token
+// by token, so reformatting yields edits touching only whitespace and
+// commas between tokens, which keeps version control diffs and editor
+// formatting edits small. Applying the result to src gives dst.
+func Diff(src, dst string) ([]Edit, error) {
+	a, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	b, err := tokenize(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []Edit
+	srcEnd, dstEnd := 0, 0
+	for _, m := range matches(a, b) {
+		edits = appendEdit(edits, src, srcEnd, a[m[0]].Start, dst[dstEnd:b[m[1]].Start])
+		srcEnd, dstEnd = a[m[0]].End, b[m[1]].End
+	}
+	return edits, nil
+}
+
+// FormatEdits formats src and returns edits applying the formatting.
+func FormatEdits(src string, format func(src string) (string, error)) ([]Edit, error) {
+	formatted, err := format(src)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(src, formatted)
+}
+
+// appendEdit appends edit replacing src[start:end] with text, narrowed to
+// the part that actually changes.
+func appendEdit(edits []Edit, src string, start, end int, text string) []Edit {
+	old := src[start:end]
+	if old == text {
+		return edits
+	}
+	prefix := 0
+	for prefix < len(old) && prefix < len(text) && old[prefix] == text[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(text)-prefix && old[len(old)-1-suffix] == text[len(text)-1-suffix] {
+		suffix++
+	}
+	return append(edits, Edit{
+		Start:   start + prefix,
+		End:     end - suffix,
+		NewText: text[prefix : len(text)-suffix],
+	})
+}
+
+// lexeme is a token together with its source text.
+type lexeme struct {
+	Type  token.Type
+	Text  string
+	Start int
+	End   int
+}
+
+// tokenize returns tokens of src including comments and the final EOF
+// token, so that trailing whitespace is compared as well.
+func tokenize(src string) ([]lexeme, error) {
+	var result []lexeme
+	l := lexer.New(src)
+	for {
+		tok, err := l.NextToken()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, lexeme{Type: tok.Type, Text: src[tok.Start:tok.End], Start: tok.Start, End: tok.End})
+		if tok.Type == token.EOF {
+			return result, nil
+		}
+	}
+}
+
+// matches returns pairs of indexes of equal tokens of the longest common
+// subsequence of a and b, using Myers' algorithm. Both end with EOF, which
+// is always matched.
+func matches(a, b []lexeme) [][2]int {
+	equal := func(i, j int) bool {
+		return a[i].Type == b[j].Type && a[i].Text == b[j].Text
+	}
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		// Only diagonals -d-1..d+1 are read when backtracking step d.
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && equal(x, y) {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(trace [][]int, x, y int) [][2]int {
+	var pairs [][2]int
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			pairs = append(pairs, [2]int{x, y})
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(pairs)-1; i < j; i, j = i+1, j-1 {
+		pairs[i], pairs[j] = pairs[j], pairs[i]
+	}
+	return pairs
+}
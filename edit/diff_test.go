@@ -0,0 +1,85 @@
+package edit
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		dst      string
+		expected []Edit
+	}{
+		{"Equal", "type A { a: Int }", "type A { a: Int }", nil},
+		{"Whitespace only", "type A {a:Int}", "type A { a: Int }", []Edit{
+			{Start: 8, End: 8, NewText: " "},
+			{Start: 10, End: 10, NewText: " "},
+			{Start: 13, End: 13, NewText: " "},
+		}},
+		{"Commas", "{ a, b }", "{ a b }", []Edit{{Start: 3, End: 4}}},
+		{"Trailing newline", "type A", "type A\n", []Edit{{Start: 6, End: 6, NewText: "\n"}}},
+		{"Changed token", "type A { a: Int }", "type A { a: String }", []Edit{{Start: 12, End: 15, NewText: "String"}}},
+		{"Inserted tokens", "type A { a: Int }", "type A { a: Int b: Int }", []Edit{{Start: 16, End: 16, NewText: "b: Int "}}},
+		{"Removed tokens", "{ a b c }", "{ a c }", []Edit{{Start: 4, End: 6}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits, err := Diff(tt.src, tt.dst)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(edits, tt.expected) {
+				t.Errorf("expected edits %v, got %v", tt.expected, edits)
+			}
+			got, err := Apply(tt.src, edits)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.dst {
+				t.Errorf("expected %q, got %q", tt.dst, got)
+			}
+		})
+	}
+}
+
+func TestDiff_LargeReformat(t *testing.T) {
+	var src, dst strings.Builder
+	for i := 0; i < 2000; i++ {
+		src.WriteString("type T{a:Int}\n")
+		dst.WriteString("type T {\n  a: Int\n}\n\n")
+	}
+	edits, err := Diff(src.String(), dst.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(edits) != 2000*5 {
+		t.Errorf("expected %d edits, got %d", 2000*5, len(edits))
+	}
+	for _, e := range edits {
+		if strings.TrimSpace(e.NewText) != "" || e.End != e.Start {
+			t.Fatalf("expected whitespace inserts only, got %v", e)
+		}
+	}
+}
+
+func TestFormatEdits(t *testing.T) {
+	edits, err := FormatEdits("{a}", func(src string) (string, error) { return "{ a }", nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(edits) != 2 {
+		t.Errorf("expected 2 edits, got %v", edits)
+	}
+
+	formatErr := errors.New("format failed")
+	if _, err := FormatEdits("{a}", func(src string) (string, error) { return "", formatErr }); !errors.Is(err, formatErr) {
+		t.Errorf("expected format error, got %v", err)
+	}
+	if _, err := Diff(`"unterminated`, ""); err == nil {
+		t.Errorf("expected lexer error")
+	}
+}
@@ -0,0 +1,146 @@
+// Package edit provides text edits derived from AST positions and an
+// applier used to implement automatic fixes.
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+// Edit replaces source bytes in range [Start, End) with NewText.
+// Start == End inserts NewText, empty NewText deletes the range.
+type Edit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+func (e Edit) String() string {
+	return fmt.Sprintf("%d-%d: %q", e.Start, e.End, e.NewText)
+}
+
+// Replace returns an edit replacing source of node with text.
+func Replace(node ast.Node, text string) Edit {
+	return Edit{Start: node.Pos(), End: node.End(), NewText: text}
+}
+
+// Delete returns an edit removing source of node.
+func Delete(node ast.Node) Edit {
+	return Edit{Start: node.Pos(), End: node.End()}
+}
+
+// InsertBefore returns an edit inserting text right before node.
+func InsertBefore(node ast.Node, text string) Edit {
+	return Edit{Start: node.Pos(), End: node.Pos(), NewText: text}
+}
+
+// InsertAfter returns an edit inserting text right after node.
+func InsertAfter(node ast.Node, text string) Edit {
+	return Edit{Start: node.End(), End: node.End(), NewText: text}
+}
+
+var (
+	ErrOutOfRange = errors.New("edit: range out of bounds")
+	ErrOverlap    = errors.New("edit: overlapping edits")
+)
+
+// Applier applies edits to source and optionally post-processes the result.
+// The zero value applies edits without any verification.
+type Applier struct {
+	// Format is called with the edited source, e.g. to pretty-print it.
+	Format func(src string) (string, error)
+
+	// Validate makes Apply parse the result (before formatting) and
+	// reject edits producing syntactically invalid documents.
+	Validate bool
+}
+
+// Apply applies edits to src. Edits are expected to be expressed in terms
+// of the original source; their order does not matter but ranges must not
+// overlap. An insert at the start of a replaced or deleted range goes
+// before it, and inserts at the same position are applied in the given
+// order.
+func (a *Applier) Apply(src string, edits []Edit) (string, error) {
+	sorted := make([]Edit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Start != sorted[j].Start {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return sorted[i].Start == sorted[i].End && sorted[j].Start != sorted[j].End
+	})
+
+	var b strings.Builder
+	b.Grow(len(src))
+	last := 0
+	for _, e := range sorted {
+		if e.Start < 0 || e.End < e.Start || e.End > len(src) {
+			return "", fmt.Errorf("%w: %s", ErrOutOfRange, e)
+		}
+		if e.Start < last {
+			return "", fmt.Errorf("%w: %s", ErrOverlap, e)
+		}
+		b.WriteString(src[last:e.Start])
+		b.WriteString(e.NewText)
+		last = e.End
+	}
+	b.WriteString(src[last:])
+	result := b.String()
+
+	if a.Validate {
+		if err := parse(result); err != nil {
+			return "", fmt.Errorf("edit: result is not a valid document: %w", err)
+		}
+	}
+	if a.Format != nil {
+		return a.Format(result)
+	}
+	return result, nil
+}
+
+// Apply applies edits to src using zero Applier.
+func Apply(src string, edits []Edit) (string, error) {
+	var a Applier
+	return a.Apply(src, edits)
+}
+
+// Position converts byte offset in src into line and column numbers
+// counted the same way as the lexer does.
+func Position(src string, offset int) token.Position {
+	pos := token.Position{Offset: offset, Line: 1, Column: 1}
+	for i, ch := range src {
+		if i >= offset {
+			break
+		}
+		switch ch {
+		case '\r':
+			if i+1 < len(src) && src[i+1] == '\n' {
+				continue
+			}
+			pos.Line++
+			pos.Column = 1
+		case '\n':
+			pos.Line++
+			pos.Column = 1
+		default:
+			pos.Column++
+		}
+	}
+	return pos
+}
+
+func parse(src string) error {
+	p, err := parser.New(lexer.New(src))
+	if err != nil {
+		return err
+	}
+	_, err = p.ParseDocument()
+	return err
+}
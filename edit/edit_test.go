@@ -0,0 +1,105 @@
+package edit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		edits    []Edit
+		expected string
+	}{
+		{"No edits", "type A", nil, "type A"},
+		{"Replace", "type A", []Edit{{Start: 5, End: 6, NewText: "B"}}, "type B"},
+		{"Delete", "type A @a", []Edit{{Start: 7, End: 9}}, "type A "},
+		{"Insert", "type A", []Edit{{Start: 6, End: 6, NewText: " @a"}}, "type A @a"},
+		{"Unordered", "abc", []Edit{{Start: 2, End: 3, NewText: "C"}, {Start: 0, End: 1, NewText: "A"}}, "AbC"},
+		{"Inserts at same position keep order", "", []Edit{{NewText: "a"}, {NewText: "b"}}, "ab"},
+		{"Insert before replace", "type A", []Edit{{Start: 5, End: 6, NewText: "B"}, {Start: 5, End: 5, NewText: "_"}}, "type _B"},
+		{"Replace after insert", "type A", []Edit{{Start: 5, End: 5, NewText: "_"}, {Start: 5, End: 6, NewText: "B"}}, "type _B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.src, tt.edits)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	if _, err := Apply("abc", []Edit{{Start: 0, End: 2}, {Start: 1, End: 3}}); !errors.Is(err, ErrOverlap) {
+		t.Errorf("expected ErrOverlap, got %v", err)
+	}
+	if _, err := Apply("abc", []Edit{{Start: 2, End: 4}}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+	a := Applier{Validate: true}
+	if _, err := a.Apply("type A", []Edit{{Start: 5, End: 6, NewText: "{"}}); err == nil {
+		t.Errorf("expected validation error")
+	}
+}
+
+func TestApply_Format(t *testing.T) {
+	a := Applier{Validate: true, Format: func(src string) (string, error) {
+		return src + "\n", nil
+	}}
+	got, err := a.Apply("type A", []Edit{{Start: 5, End: 6, NewText: "B"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "type B\n" {
+		t.Errorf("unexpected result %q", got)
+	}
+}
+
+func TestApply_NodeEdits(t *testing.T) {
+	src := `type Query { user(id: ID!): User @deprecated(reason: "no") posts: [Post!] }`
+	doc := asttest.Parse(t, src)
+	obj := doc.Definitions[0].(*ast.ObjectTypeDefinition)
+	user, posts := obj.Fields[0], obj.Fields[1]
+
+	got, err := Apply(src, []Edit{
+		Delete(user.Directives[0]),
+		Replace(user.Arguments[0].Type, "String"),
+		Replace(posts.Type, "[Post!]!"),
+		InsertBefore(posts, `"All posts" `),
+		InsertAfter(obj, " scalar Date"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `type Query { user(id: String): User  "All posts" posts: [Post!]! } scalar Date`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestPosition(t *testing.T) {
+	src := "a\r\nbc\rd\n🫶e"
+	tests := []struct {
+		offset   int
+		expected token.Position
+	}{
+		{0, token.Position{Offset: 0, Line: 1, Column: 1}},
+		{4, token.Position{Offset: 4, Line: 2, Column: 2}},
+		{6, token.Position{Offset: 6, Line: 3, Column: 1}},
+		{12, token.Position{Offset: 12, Line: 4, Column: 2}},
+	}
+	for _, tt := range tests {
+		if got := Position(src, tt.offset); got != tt.expected {
+			t.Errorf("offset %d: expected %v, got %v", tt.offset, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Package federation recognizes Apollo Federation directives of subgraph
+// schemas and adds the definitions a router queries subgraphs with.
+// Federation 2 subgraphs link the specification with @link, which may
+// import or rename its directives; schemas without the link are treated
+// as Federation 1 subgraphs, whose directives have fixed names.
+//
+// https://www.apollographql.com/docs/federation/subgraph-spec
+package federation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/link"
+	"github.com/gqlhub/gqlhub-core/parser"
+)
+
+// Identity is identity of Apollo Federation specification.
+const Identity = "https://specs.apollo.dev/federation"
+
+// Subgraph is federation metadata of a subgraph schema.
+type Subgraph struct {
+	// Version of federation, e.g. "v2.3", or "v1" when the schema does not
+	// link the specification.
+	Version string
+
+	Link     *link.Link // Link to the specification; nil for Federation 1.
+	Links    link.Links // All links of the schema.
+	Entities []*Entity  // Types with @key in definition order.
+	Fields   []*Field   // Fields with federation directives in definition order.
+
+	names map[string]string // Local directive names by specification name.
+}
+
+// Entity is an object or interface type with @key.
+type Entity struct {
+	Name      string
+	Interface bool
+	Keys      []*Key
+}
+
+// Resolvable reports whether the subgraph resolves entity by any of its
+// keys, so it is a member of _Entity.
+func (e *Entity) Resolvable() bool {
+	for _, k := range e.Keys {
+		if k.Resolvable {
+			return true
+		}
+	}
+	return false
+}
+
+// Key is an application of @key.
+type Key struct {
+	FieldSet
+	Resolvable bool
+}
+
+// FieldSet is a selection of fields given as string argument, e.g.
+// "id organization { id }".
+type FieldSet struct {
+	Source       string
+	SelectionSet *ast.SelectionSet
+}
+
+// Field is a field with federation directives, applied to it or to its
+// type.
+type Field struct {
+	Type      string
+	Name      string
+	External  bool
+	Shareable bool
+	Requires  *FieldSet // nil without @requires.
+	Provides  *FieldSet // nil without @provides.
+
+	// Override is name of subgraph field is taken over from, empty without
+	// @override. OverrideLabel is its progressive override label.
+	Override      string
+	OverrideLabel string
+}
+
+// directives are specification names of recognized directives by version.
+var (
+	directivesV1 = []string{"key", "external", "requires", "provides", "extends"}
+	directivesV2 = []string{"key", "external", "requires", "provides", "shareable", "override"}
+)
+
+// FromDocument recognizes federation directives in subgraph schema doc.
+// Type extensions are merged into the types they extend.
+func FromDocument(doc *ast.Document) (*Subgraph, error) {
+	links, err := link.Extract(doc)
+	if err != nil {
+		return nil, fmt.Errorf("federation: %w", err)
+	}
+	s := &Subgraph{Version: "v1", Links: links, names: make(map[string]string)}
+	directives := directivesV1
+	if l, ok := links.ByIdentity(Identity); ok {
+		s.Version = l.URL.Version.String()
+		s.Link = l
+		directives = directivesV2
+	}
+	byLocal := make(map[string]string)
+	for _, name := range directives {
+		local := name
+		if s.Link != nil {
+			local = strings.TrimPrefix(s.Link.LocalName("@"+name), "@")
+		}
+		s.names[name] = local
+		byLocal[local] = name
+	}
+
+	entities := make(map[string]*Entity)
+	for _, def := range doc.Definitions {
+		var (
+			name       *ast.Name
+			directives []*ast.Directive
+			fields     []*ast.FieldDefinition
+			iface      bool
+		)
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			name, directives, fields = d.Name, d.Directives, d.Fields
+		case *ast.ObjectTypeExtension:
+			name, directives, fields = d.Name, d.Directives, d.Fields
+		case *ast.InterfaceTypeDefinition:
+			name, directives, fields, iface = d.Name, d.Directives, d.Fields, true
+		case *ast.InterfaceTypeExtension:
+			name, directives, fields, iface = d.Name, d.Directives, d.Fields, true
+		default:
+			continue
+		}
+
+		var external, shareable bool
+		for _, d := range directives {
+			switch byLocal[d.Name.Value] {
+			case "key":
+				k, err := parseKey(d, name.Value)
+				if err != nil {
+					return nil, err
+				}
+				e := entities[name.Value]
+				if e == nil {
+					e = &Entity{Name: name.Value, Interface: iface}
+					entities[name.Value] = e
+					s.Entities = append(s.Entities, e)
+				}
+				e.Keys = append(e.Keys, k)
+			case "external":
+				external = true
+			case "shareable":
+				shareable = true
+			}
+		}
+		for _, f := range fields {
+			field, err := parseField(byLocal, name.Value, f)
+			if err != nil {
+				return nil, err
+			}
+			field.External = field.External || external
+			field.Shareable = field.Shareable || shareable
+			if field.External || field.Shareable || field.Requires != nil || field.Provides != nil || field.Override != "" {
+				s.Fields = append(s.Fields, field)
+			}
+		}
+	}
+	return s, nil
+}
+
+// DirectiveName returns local name of federation directive, e.g. "key" ->
+// "federation__key" when it is not imported.
+func (s *Subgraph) DirectiveName(name string) string {
+	if local, ok := s.names[name]; ok {
+		return local
+	}
+	if s.Link != nil {
+		return strings.TrimPrefix(s.Link.LocalName("@"+name), "@")
+	}
+	return name
+}
+
+// Entity returns entity of given name or nil.
+func (s *Subgraph) Entity(name string) *Entity {
+	for _, e := range s.Entities {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// Field returns federated field or nil.
+func (s *Subgraph) Field(typeName, name string) *Field {
+	for _, f := range s.Fields {
+		if f.Type == typeName && f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func parseKey(d *ast.Directive, typeName string) (*Key, error) {
+	fs, err := fieldSetArg(d, typeName)
+	if err != nil {
+		return nil, err
+	}
+	k := &Key{FieldSet: *fs, Resolvable: true}
+	switch v := argument(d, "resolvable").(type) {
+	case nil:
+	case *ast.BooleanValue:
+		k.Resolvable = v.Value
+	default:
+		return nil, fmt.Errorf("federation: @%s on %s at %d: resolvable must be a boolean", d.Name.Value, typeName, d.Pos())
+	}
+	return k, nil
+}
+
+func parseField(byLocal map[string]string, typeName string, def *ast.FieldDefinition) (*Field, error) {
+	f := &Field{Type: typeName, Name: def.Name.Value}
+	coordinate := typeName + "." + def.Name.Value
+	for _, d := range def.Directives {
+		var err error
+		switch byLocal[d.Name.Value] {
+		case "external":
+			f.External = true
+		case "shareable":
+			f.Shareable = true
+		case "requires":
+			f.Requires, err = fieldSetArg(d, coordinate)
+		case "provides":
+			f.Provides, err = fieldSetArg(d, coordinate)
+		case "override":
+			var ok bool
+			if f.Override, ok = stringArg(d, "from"); !ok {
+				err = fmt.Errorf("federation: @%s on %s at %d: from must be a string", d.Name.Value, coordinate, d.Pos())
+			}
+			f.OverrideLabel, _ = stringArg(d, "label")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// fieldSetArg parses fields argument of directive applied to element.
+func fieldSetArg(d *ast.Directive, element string) (*FieldSet, error) {
+	source, ok := stringArg(d, "fields")
+	if !ok {
+		return nil, fmt.Errorf("federation: @%s on %s at %d: fields must be a string", d.Name.Value, element, d.Pos())
+	}
+	set, err := ParseFieldSet(source)
+	if err != nil {
+		return nil, fmt.Errorf("federation: @%s on %s at %d: %w", d.Name.Value, element, d.Pos(), err)
+	}
+	return &FieldSet{Source: source, SelectionSet: set}, nil
+}
+
+// ParseFieldSet parses selection of fields without enclosing braces.
+// Positions are relative to source preceded by "{".
+func ParseFieldSet(source string) (*ast.SelectionSet, error) {
+	p, err := parser.New(lexer.New("{" + source + "}"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid field set %q: %w", source, err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		return nil, fmt.Errorf("invalid field set %q: %w", source, err)
+	}
+	op, ok := doc.Definitions[0].(*ast.OperationDefinition)
+	if len(doc.Definitions) != 1 || !ok {
+		return nil, fmt.Errorf("invalid field set %q", source)
+	}
+	return op.SelectionSet, nil
+}
+
+func argument(d *ast.Directive, name string) ast.Value {
+	for _, arg := range d.Arguments {
+		if arg.Name.Value == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
+
+func stringArg(d *ast.Directive, name string) (string, bool) {
+	s, ok := argument(d, name).(*ast.StringValue)
+	if !ok {
+		return "", false
+	}
+	return s.Value, true
+}
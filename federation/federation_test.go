@@ -0,0 +1,122 @@
+package federation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+const subgraphV2 = `
+extend schema
+  @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", {name: "@shareable", as: "@share"}, "FieldSet"])
+
+type Query { me: User @share }
+
+type User @key(fields: "id") @key(fields: "org { id } login", resolvable: false) {
+  id: ID!
+  login: String @federation__external
+  org: Org @federation__provides(fields: "name")
+  reviews: [String] @federation__requires(fields: "login") @federation__override(from: "reviews", label: "percent(5)")
+}
+
+type Org @share { id: ID! name: String }
+
+interface Node @key(fields: "id") { id: ID! }
+extend type Org @key(fields: "id")
+`
+
+func TestFromDocument(t *testing.T) {
+	s, err := FromDocument(asttest.Parse(t, subgraphV2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version != "v2.3" || s.Link == nil {
+		t.Errorf("expected federation v2.3 link, got %s", s.Version)
+	}
+	for name, expected := range map[string]string{"key": "key", "shareable": "share", "external": "federation__external", "override": "federation__override"} {
+		if got := s.DirectiveName(name); got != expected {
+			t.Errorf("expected @%s to be named %s, got %s", name, expected, got)
+		}
+	}
+
+	var entities []string
+	for _, e := range s.Entities {
+		var keys []string
+		for _, k := range e.Keys {
+			keys = append(keys, printer.Print(k.SelectionSet)+map[bool]string{true: "", false: " (unresolvable)"}[k.Resolvable])
+		}
+		entities = append(entities, e.Name+": "+strings.Join(keys, ", "))
+	}
+	expectedEntities := "User: {\n  id\n}, {\n  org {\n    id\n  }\n  login\n} (unresolvable)\nNode: {\n  id\n}\nOrg: {\n  id\n}"
+	if got := strings.Join(entities, "\n"); got != expectedEntities {
+		t.Errorf("expected entities:\n%s\ngot:\n%s", expectedEntities, got)
+	}
+	if !s.Entity("Node").Interface || s.Entity("User").Keys[1].Source != "org { id } login" {
+		t.Errorf("unexpected entities")
+	}
+
+	tests := []struct {
+		typ, field string
+		check      func(f *Field) bool
+	}{
+		{"Query", "me", func(f *Field) bool { return f.Shareable && !f.External }},
+		{"User", "login", func(f *Field) bool { return f.External && !f.Shareable }},
+		{"User", "org", func(f *Field) bool { return f.Provides != nil && f.Provides.Source == "name" }},
+		{"User", "reviews", func(f *Field) bool {
+			return f.Requires != nil && f.Requires.Source == "login" && f.Override == "reviews" && f.OverrideLabel == "percent(5)"
+		}},
+		{"Org", "id", func(f *Field) bool { return f.Shareable }},
+		{"Org", "name", func(f *Field) bool { return f.Shareable }},
+	}
+	for _, tt := range tests {
+		if f := s.Field(tt.typ, tt.field); f == nil || !tt.check(f) {
+			t.Errorf("unexpected field %s.%s: %+v", tt.typ, tt.field, f)
+		}
+	}
+	if len(s.Fields) != len(tests) {
+		t.Errorf("expected %d fields, got %d", len(tests), len(s.Fields))
+	}
+}
+
+func TestFromDocument_V1(t *testing.T) {
+	s, err := FromDocument(asttest.Parse(t, `
+extend type Query { me: User }
+type User @key(fields: "id") { id: ID! name: String @external @shareable }
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version != "v1" || s.Link != nil || len(s.Entities) != 1 {
+		t.Errorf("unexpected subgraph %+v", s)
+	}
+	// @shareable is not a Federation 1 directive.
+	if f := s.Field("User", "name"); f == nil || !f.External || f.Shareable {
+		t.Errorf("unexpected field %+v", f)
+	}
+}
+
+func TestFromDocument_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Link", `extend schema @link(import: ["@key"])`, "federation: @link at 14: missing url argument"},
+		{"Key fields", `type T @key(fields: 1) { id: ID }`, "federation: @key on T at 7: fields must be a string"},
+		{"Key syntax", `type T @key(fields: "id {") { id: ID }`, `federation: @key on T at 7: invalid field set "id {"`},
+		{"Resolvable", `type T @key(fields: "id", resolvable: "no") { id: ID }`, "federation: @key on T at 7: resolvable must be a boolean"},
+		{"Requires", `type T { a: Int @requires(fields: "") }`, `federation: @requires on T.a at 16: invalid field set ""`},
+		{"Override", `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@override"]) type T { a: Int @override }`,
+			"federation: @override on T.a at 108: from must be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromDocument(asttest.Parse(t, tt.input))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
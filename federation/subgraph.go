@@ -0,0 +1,206 @@
+package federation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/link"
+	"github.com/gqlhub/gqlhub-core/parser"
+)
+
+// SubgraphSchema returns subgraph schema doc with the additions a
+// subgraph serves to the router: definitions of federation directives
+// and scalars, the _Any scalar, the _Service type, the _Entity union of
+// resolvable entity objects, and the _service and _entities fields of the
+// query type. Definitions doc already has are not added, and _Entity and
+// _entities are left out when there are no resolvable entities. doc is
+// not modified; _service.sdl should return doc as printed.
+func SubgraphSchema(doc *ast.Document) (*ast.Document, error) {
+	s, err := FromDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	defined := definedNames(doc)
+	result := &ast.Document{Definitions: append([]ast.Definition(nil), doc.Definitions...)}
+	add := func(sdl string) error {
+		p, err := parser.New(lexer.New(sdl))
+		if err != nil {
+			return err
+		}
+		additions, err := p.ParseDocument()
+		if err != nil {
+			return err
+		}
+		for _, def := range additions.Definitions {
+			_, extension := def.(ast.TypeSystemExtension)
+			if name := definitionName(def); extension || !defined[name] {
+				defined[name] = true
+				result.Definitions = append(result.Definitions, def)
+			}
+		}
+		return nil
+	}
+
+	if err := add(s.definitions()); err != nil {
+		return nil, fmt.Errorf("federation: %w", err)
+	}
+
+	var members []string
+	for _, e := range s.Entities {
+		if !e.Interface && e.Resolvable() {
+			members = append(members, e.Name)
+		}
+	}
+	var sdl strings.Builder
+	sdl.WriteString("scalar _Any\ntype _Service { sdl: String }\n")
+	if len(members) > 0 {
+		sdl.WriteString("union _Entity = " + strings.Join(members, " | ") + "\n")
+	}
+	if err := add(sdl.String()); err != nil {
+		return nil, fmt.Errorf("federation: %w", err)
+	}
+
+	query := queryTypeName(doc)
+	var fields []string
+	if !hasField(doc, query, "_service") {
+		fields = append(fields, "_service: _Service!")
+	}
+	if len(members) > 0 && !hasField(doc, query, "_entities") {
+		fields = append(fields, "_entities(representations: [_Any!]!): [_Entity]!")
+	}
+	if len(fields) > 0 {
+		keyword := "type"
+		if defined[query] {
+			keyword = "extend type"
+		}
+		if err := add(keyword + " " + query + " { " + strings.Join(fields, " ") + " }"); err != nil {
+			return nil, fmt.Errorf("federation: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// definitions returns SDL defining federation directives and scalars
+// under their local names.
+func (s *Subgraph) definitions() string {
+	name := s.DirectiveName
+	var lines []string
+	if s.Link == nil {
+		lines = []string{
+			"scalar _FieldSet",
+			fmt.Sprintf("directive @%s(fields: _FieldSet!) repeatable on OBJECT | INTERFACE", name("key")),
+			fmt.Sprintf("directive @%s on FIELD_DEFINITION", name("external")),
+			fmt.Sprintf("directive @%s(fields: _FieldSet!) on FIELD_DEFINITION", name("requires")),
+			fmt.Sprintf("directive @%s(fields: _FieldSet!) on FIELD_DEFINITION", name("provides")),
+			fmt.Sprintf("directive @%s on OBJECT | INTERFACE", name("extends")),
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	fieldSet := s.Link.LocalName("FieldSet")
+	lines = []string{
+		"scalar " + fieldSet,
+		fmt.Sprintf("directive @%s(fields: %s!, resolvable: Boolean = true) repeatable on OBJECT | INTERFACE", name("key"), fieldSet),
+		fmt.Sprintf("directive @%s on OBJECT | FIELD_DEFINITION", name("external")),
+		fmt.Sprintf("directive @%s(fields: %s!) on FIELD_DEFINITION", name("requires"), fieldSet),
+		fmt.Sprintf("directive @%s(fields: %s!) on FIELD_DEFINITION", name("provides"), fieldSet),
+		fmt.Sprintf("directive @%s repeatable on OBJECT | FIELD_DEFINITION", name("shareable")),
+		fmt.Sprintf("directive @%s(from: String!, label: String) on FIELD_DEFINITION", name("override")),
+	}
+
+	// The link specification is linked implicitly by the first @link.
+	linkSpec := &link.Link{URL: link.URL{Name: "link"}}
+	if l, ok := s.Links.ByIdentity(link.SpecIdentity); ok {
+		linkSpec = l
+	}
+	importType, purpose := linkSpec.LocalName("Import"), linkSpec.LocalName("Purpose")
+	lines = append(lines,
+		fmt.Sprintf("directive %s(url: String!, as: String, for: %s, import: [%s]) repeatable on SCHEMA", linkSpec.LocalName("@link"), purpose, importType),
+		"scalar "+importType,
+		"enum "+purpose+" { SECURITY EXECUTION }",
+	)
+	return strings.Join(lines, "\n")
+}
+
+// definedNames returns names of types and, prefixed with "@", directives
+// defined in doc.
+func definedNames(doc *ast.Document) map[string]bool {
+	names := make(map[string]bool)
+	for _, def := range doc.Definitions {
+		if _, ok := def.(ast.TypeSystemExtension); ok {
+			continue
+		}
+		if name := definitionName(def); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// definitionName returns name of type definition, or name of directive
+// definition prefixed with "@".
+func definitionName(def ast.Definition) string {
+	switch d := def.(type) {
+	case *ast.DirectiveDefinition:
+		return "@" + d.Name.Value
+	case *ast.ScalarTypeDefinition:
+		return d.Name.Value
+	case *ast.ObjectTypeDefinition:
+		return d.Name.Value
+	case *ast.InterfaceTypeDefinition:
+		return d.Name.Value
+	case *ast.UnionTypeDefinition:
+		return d.Name.Value
+	case *ast.EnumTypeDefinition:
+		return d.Name.Value
+	case *ast.InputObjectTypeDefinition:
+		return d.Name.Value
+	}
+	return ""
+}
+
+// queryTypeName returns name of query root type of doc.
+func queryTypeName(doc *ast.Document) string {
+	for _, def := range doc.Definitions {
+		var roots []*ast.RootOperationTypeDefinition
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			roots = d.RootOperationDefs
+		case *ast.SchemaExtension:
+			roots = d.RootOperationDefs
+		}
+		for _, r := range roots {
+			if r.OperationType == ast.OperationTypeQuery {
+				return r.Type.Name.Value
+			}
+		}
+	}
+	return "Query"
+}
+
+// hasField reports whether object type or its extensions define field.
+func hasField(doc *ast.Document, typeName, field string) bool {
+	for _, def := range doc.Definitions {
+		var name *ast.Name
+		var fields []*ast.FieldDefinition
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			name, fields = d.Name, d.Fields
+		case *ast.ObjectTypeExtension:
+			name, fields = d.Name, d.Fields
+		default:
+			continue
+		}
+		if name.Value != typeName {
+			continue
+		}
+		for _, f := range fields {
+			if f.Name.Value == field {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+func TestSubgraphSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string // Added definitions.
+	}{
+		{"V2", subgraphV2, `scalar FieldSet
+
+directive @key(fields: FieldSet!, resolvable: Boolean = true) repeatable on OBJECT | INTERFACE
+
+directive @federation__external on OBJECT | FIELD_DEFINITION
+
+directive @federation__requires(fields: FieldSet!) on FIELD_DEFINITION
+
+directive @federation__provides(fields: FieldSet!) on FIELD_DEFINITION
+
+directive @share repeatable on OBJECT | FIELD_DEFINITION
+
+directive @federation__override(from: String!, label: String) on FIELD_DEFINITION
+
+directive @link(url: String!, as: String, for: link__Purpose, import: [link__Import]) repeatable on SCHEMA
+
+scalar link__Import
+
+enum link__Purpose {
+  SECURITY
+  EXECUTION
+}
+
+scalar _Any
+
+type _Service {
+  sdl: String
+}
+
+union _Entity = User | Org
+
+extend type Query {
+  _service: _Service!
+  _entities(representations: [_Any!]!): [_Entity]!
+}`},
+		{"V1", `
+extend type Query { me: User }
+type User @key(fields: "id") { id: ID! }
+directive @external on FIELD_DEFINITION
+`, `scalar _FieldSet
+
+directive @key(fields: _FieldSet!) repeatable on OBJECT | INTERFACE
+
+directive @requires(fields: _FieldSet!) on FIELD_DEFINITION
+
+directive @provides(fields: _FieldSet!) on FIELD_DEFINITION
+
+directive @extends on OBJECT | INTERFACE
+
+scalar _Any
+
+type _Service {
+  sdl: String
+}
+
+union _Entity = User
+
+type Query {
+  _service: _Service!
+  _entities(representations: [_Any!]!): [_Entity]!
+}`},
+		{"No entities", `schema { query: Root } type Root { a: Int } type User @key(fields: "id", resolvable: false) { id: ID }`, `scalar _FieldSet
+
+directive @key(fields: _FieldSet!) repeatable on OBJECT | INTERFACE
+
+directive @external on FIELD_DEFINITION
+
+directive @requires(fields: _FieldSet!) on FIELD_DEFINITION
+
+directive @provides(fields: _FieldSet!) on FIELD_DEFINITION
+
+directive @extends on OBJECT | INTERFACE
+
+scalar _Any
+
+type _Service {
+  sdl: String
+}
+
+extend type Root {
+  _service: _Service!
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.input)
+			before := printer.PrintDocument(doc)
+			result, err := SubgraphSchema(doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if printer.PrintDocument(doc) != before {
+				t.Errorf("expected document to be unmodified")
+			}
+			added := &ast.Document{Definitions: result.Definitions[len(doc.Definitions):]}
+			if got := printer.PrintDocument(added); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+			if _, err := schema.FromDocument(result); err != nil {
+				t.Errorf("unexpected error building subgraph schema: %v", err)
+			}
+
+			// Adding again changes nothing.
+			again, err := SubgraphSchema(result)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(again.Definitions) != len(result.Definitions) {
+				t.Errorf("expected no additions to subgraph schema, got %d", len(again.Definitions)-len(result.Definitions))
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package format
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+// comments are comments of a document attached to nodes printed on lines
+// of their own, see printer.WithComments.
+type comments struct {
+	nodes    map[ast.Node]*printer.Comments
+	dangling []string // Comments of document without definitions.
+}
+
+func (c *comments) get(n ast.Node) *printer.Comments {
+	return c.nodes[n]
+}
+
+func (c *comments) of(n ast.Node) *printer.Comments {
+	if c.nodes[n] == nil {
+		c.nodes[n] = &printer.Comments{}
+	}
+	return c.nodes[n]
+}
+
+// block is span of source enclosing nodes printed on lines of their own.
+type block struct {
+	pos, end int
+	items    []ast.Node
+}
+
+// attach attaches comment tokens of src parsed into doc to nodes: comment
+// ending line of a node trails it, other comments lead the node of the
+// innermost enclosing block they are within or precede or, when there is
+// none, follow the last one.
+func attach(src string, doc *ast.Document, tokens []token.Token) *comments {
+	c := &comments{nodes: make(map[ast.Node]*printer.Comments)}
+	blocks := collect(src, doc)
+	var items []ast.Node
+	for _, b := range blocks {
+		items = append(items, b.items...)
+	}
+	// Outer nodes are collected first and win ties of stable sort.
+	sort.SliceStable(items, func(i, j int) bool { return items[i].End() < items[j].End() })
+
+	for _, tok := range tokens {
+		if afterCode(src, tok.Start) {
+			if n := lineEnding(src, items, tok.Start); n != nil && c.of(n).Trailing == "" {
+				c.of(n).Trailing = tok.Literal
+				continue
+			}
+		}
+		b := innermost(blocks, tok.Start)
+		i := sort.Search(len(b.items), func(i int) bool { return b.items[i].Pos() > tok.Start })
+		if i > 0 && b.items[i-1].End() > tok.Start {
+			i-- // Within node, e.g. between arguments.
+		}
+		switch {
+		case i < len(b.items):
+			c.of(b.items[i]).Leading = append(c.of(b.items[i]).Leading, tok.Literal)
+		case i > 0:
+			c.of(b.items[i-1]).After = append(c.of(b.items[i-1]).After, tok.Literal)
+		default:
+			c.dangling = append(c.dangling, tok.Literal)
+		}
+	}
+	return c
+}
+
+// collect returns blocks of doc, the document first and outer blocks
+// before blocks they enclose.
+func collect(src string, doc *ast.Document) []*block {
+	blocks := []*block{{pos: 0, end: len(src), items: make([]ast.Node, len(doc.Definitions))}}
+	add := func(n ast.Node, items []ast.Node) {
+		if len(items) > 0 {
+			blocks = append(blocks, &block{pos: n.Pos(), end: n.End(), items: items})
+		}
+	}
+	var selectionSet func(set *ast.SelectionSet)
+	selectionSet = func(set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+		add(set, nodes(set.Selections))
+		for _, sel := range set.Selections {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				selectionSet(sel.SelectionSet)
+			case *ast.InlineFragment:
+				selectionSet(sel.SelectionSet)
+			}
+		}
+	}
+	for i, def := range doc.Definitions {
+		blocks[0].items[i] = def
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			selectionSet(d.SelectionSet)
+		case *ast.FragmentDefinition:
+			selectionSet(d.SelectionSet)
+		case *ast.SchemaDefinition:
+			add(d, nodes(d.RootOperationDefs))
+		case *ast.SchemaExtension:
+			add(d, nodes(d.RootOperationDefs))
+		case *ast.ObjectTypeDefinition:
+			add(d, nodes(d.Fields))
+		case *ast.ObjectTypeExtension:
+			add(d, nodes(d.Fields))
+		case *ast.InterfaceTypeDefinition:
+			add(d, nodes(d.Fields))
+		case *ast.InterfaceTypeExtension:
+			add(d, nodes(d.Fields))
+		case *ast.EnumTypeDefinition:
+			add(d, nodes(d.Values))
+		case *ast.EnumTypeExtension:
+			add(d, nodes(d.Values))
+		case *ast.InputObjectTypeDefinition:
+			add(d, nodes(d.Fields))
+		case *ast.InputObjectTypeExtension:
+			add(d, nodes(d.Fields))
+		}
+	}
+	// Sorted fields keep source order of blocks, which searches rely on.
+	for _, b := range blocks {
+		slices.SortStableFunc(b.items, func(a, b ast.Node) int { return a.Pos() - b.Pos() })
+	}
+	return blocks
+}
+
+func nodes[T ast.Node](list []T) []ast.Node {
+	result := make([]ast.Node, len(list))
+	for i, n := range list {
+		result[i] = n
+	}
+	return result
+}
+
+// innermost returns the innermost block enclosing offset.
+func innermost(blocks []*block, offset int) *block {
+	result := blocks[0]
+	for _, b := range blocks[1:] {
+		if b.pos <= offset && offset < b.end && b.pos >= result.pos {
+			result = b
+		}
+	}
+	return result
+}
+
+// afterCode reports whether offset is preceded by code on its line.
+func afterCode(src string, offset int) bool {
+	for i := offset - 1; i >= 0; i-- {
+		switch src[i] {
+		case ' ', '\t', ',':
+		case '\n', '\r':
+			return false
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// lineEnding returns the outermost of items sorted by end which end
+// last before offset on its line, or nil.
+func lineEnding(src string, items []ast.Node, offset int) ast.Node {
+	i := sort.Search(len(items), func(i int) bool { return items[i].End() > offset })
+	if i == 0 {
+		return nil
+	}
+	end := items[i-1].End()
+	for i > 1 && items[i-2].End() == end {
+		i--
+	}
+	for _, ch := range []byte(src[end:offset]) {
+		if ch == '\n' || ch == '\r' {
+			return nil
+		}
+	}
+	return items[i-1]
+}
@@ -0,0 +1,179 @@
+// Package format formats GraphQL documents in a canonical style, like
+// gofmt does Go source. Comments are kept, so it can rewrite files in
+// place, e.g. from pre-commit hooks:
+//
+//	out, err := format.Source(src, format.WithSortedFields())
+package format
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// Formatter formats documents. The zero value formats with default
+// options.
+type Formatter struct {
+	indent       string
+	lineWidth    int
+	descriptions printer.DescriptionStyle
+	sortFields   bool
+}
+
+// Option configures Formatter.
+type Option func(*Formatter)
+
+// WithIndent sets string indenting nested lines by one level. Default is
+// two spaces.
+func WithIndent(indent string) Option {
+	return func(f *Formatter) {
+		f.indent = indent
+	}
+}
+
+// WithLineWidth sets length of lines, excluding indentation, above which
+// arguments and argument definitions are put on lines of their own.
+// Default is 80.
+func WithLineWidth(width int) Option {
+	return func(f *Formatter) {
+		f.lineWidth = width
+	}
+}
+
+// WithDescriptionStyle sets how descriptions are printed. Default is
+// printer.DescriptionsAsWritten.
+func WithDescriptionStyle(style printer.DescriptionStyle) Option {
+	return func(f *Formatter) {
+		f.descriptions = style
+	}
+}
+
+// WithSortedFields sorts fields of object and interface types and input
+// fields of input objects by name. Order of enum values, arguments and
+// selections, which is significant to clients, is kept. By default fields
+// keep their order.
+func WithSortedFields() Option {
+	return func(f *Formatter) {
+		f.sortFields = true
+	}
+}
+
+// New returns formatter configured with opts.
+func New(opts ...Option) *Formatter {
+	f := &Formatter{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Source returns formatted src, ending with a line break unless empty.
+// Comments are kept with the definitions, fields, selections and values
+// they precede or end the line of; comments elsewhere, e.g. between
+// arguments, are moved to the closest line of its own. Formatting
+// formatted source returns it unchanged.
+func (f *Formatter) Source(src []byte) ([]byte, error) {
+	p, err := parser.New(lexer.New(string(src)), parser.WithComments())
+	if err != nil {
+		return nil, fmt.Errorf("format: %w", err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		return nil, fmt.Errorf("format: %w", err)
+	}
+	doc = f.sort(doc)
+
+	comments := attach(string(src), doc, p.Comments())
+	if len(doc.Definitions) == 0 {
+		var out []byte
+		for _, text := range comments.dangling {
+			out = append(out, "#"+text+"\n"...)
+		}
+		return out, nil
+	}
+	return []byte(f.print(doc, printer.WithComments(comments.get)) + "\n"), nil
+}
+
+// Document returns formatted doc, without comments, which are not part of
+// AST, and without trailing line break.
+func (f *Formatter) Document(doc *ast.Document) string {
+	return f.print(f.sort(doc))
+}
+
+// Source returns src formatted with opts. See Formatter.Source.
+func Source(src []byte, opts ...Option) ([]byte, error) {
+	return New(opts...).Source(src)
+}
+
+// Document returns doc formatted with opts. See Formatter.Document.
+func Document(doc *ast.Document, opts ...Option) string {
+	return New(opts...).Document(doc)
+}
+
+func (f *Formatter) print(doc *ast.Document, opts ...printer.Option) string {
+	opts = append(opts,
+		printer.WithLineWidth(f.lineWidth),
+		printer.WithArgumentDefinitionWrapping(),
+		printer.WithDescriptionStyle(f.descriptions),
+	)
+	if f.indent != "" {
+		opts = append(opts, printer.WithIndent(f.indent))
+	}
+	return printer.PrintDocument(doc, opts...)
+}
+
+// sort returns doc with fields sorted when requested. doc is not
+// modified; sorted definitions are copies sharing fields with it.
+func (f *Formatter) sort(doc *ast.Document) *ast.Document {
+	if !f.sortFields {
+		return doc
+	}
+	result := &ast.Document{Definitions: make([]ast.Definition, len(doc.Definitions))}
+	for i, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			c := *d
+			c.Fields = sortedFields(d.Fields)
+			def = &c
+		case *ast.ObjectTypeExtension:
+			c := *d
+			c.Fields = sortedFields(d.Fields)
+			def = &c
+		case *ast.InterfaceTypeDefinition:
+			c := *d
+			c.Fields = sortedFields(d.Fields)
+			def = &c
+		case *ast.InterfaceTypeExtension:
+			c := *d
+			c.Fields = sortedFields(d.Fields)
+			def = &c
+		case *ast.InputObjectTypeDefinition:
+			c := *d
+			c.Fields = sortedInputFields(d.Fields)
+			def = &c
+		case *ast.InputObjectTypeExtension:
+			c := *d
+			c.Fields = sortedInputFields(d.Fields)
+			def = &c
+		}
+		result.Definitions[i] = def
+	}
+	return result
+}
+
+func sortedFields(fields []*ast.FieldDefinition) []*ast.FieldDefinition {
+	return slices.SortedStableFunc(slices.Values(fields), func(a, b *ast.FieldDefinition) int {
+		return cmp.Compare(a.Name.Value, b.Name.Value)
+	})
+}
+
+func sortedInputFields(fields []*ast.InputValueDefinition) []*ast.InputValueDefinition {
+	return slices.SortedStableFunc(slices.Values(fields), func(a, b *ast.InputValueDefinition) int {
+		return cmp.Compare(a.Name.Value, b.Name.Value)
+	})
+}
@@ -0,0 +1,129 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected string
+	}{
+		{
+			"Normalized",
+			"type Query{a:Int,b(x:Int):String}\n\n\n{a}",
+			nil,
+			"type Query {\n  a: Int\n  b(x: Int): String\n}\n\n{\n  a\n}\n",
+		},
+		{
+			"Comments",
+			`# Schema of the API.
+type Query { # roots
+  # The user.
+  user(id: ID): User # by ID
+  # Deprecated soon.
+}
+
+# Trailing comment.`,
+			nil,
+			`# Schema of the API.
+type Query {
+  # roots
+  # The user.
+  user(id: ID): User # by ID
+  # Deprecated soon.
+}
+# Trailing comment.
+`,
+		},
+		{
+			"Comments of selections",
+			"query Q {\n  # Current user.\n  me { id # primary key\n  name } # end of me\n}",
+			nil,
+			"query Q {\n  # Current user.\n  me {\n    id # primary key\n    name\n  } # end of me\n}\n",
+		},
+		{
+			"Comment between arguments",
+			"{ search(\n  # Text.\n  text: \"a\") { id } }",
+			nil,
+			"{\n  # Text.\n  search(text: \"a\") {\n    id\n  }\n}\n",
+		},
+		{
+			"Comments only",
+			"# a\n\n#b",
+			nil,
+			"# a\n#b\n",
+		},
+		{
+			"Empty",
+			"",
+			nil,
+			"",
+		},
+		{
+			"Indent",
+			"type Query { a: Int }",
+			[]Option{WithIndent("    ")},
+			"type Query {\n    a: Int\n}\n",
+		},
+		{
+			"Line width",
+			"type Query { search(text: String, first: Int): [String] }",
+			[]Option{WithLineWidth(30)},
+			"type Query {\n  search(\n    text: String\n    first: Int\n  ): [String]\n}\n",
+		},
+		{
+			"Description style",
+			"\"Root.\" type Query { a: Int }",
+			[]Option{WithDescriptionStyle(printer.DescriptionsBlockMultiline)},
+			"\"\"\"\nRoot.\n\"\"\"\ntype Query {\n  a: Int\n}\n",
+		},
+		{
+			"Sorted fields",
+			"type Query {\n  # B.\n  b: Int\n  a: Int # A\n}\ninput I { z: Int y: Int }\nenum E { Z Y }\n{ b a }",
+			[]Option{WithSortedFields()},
+			"type Query {\n  a: Int # A\n  # B.\n  b: Int\n}\n\ninput I {\n  y: Int\n  z: Int\n}\n\nenum E {\n  Z\n  Y\n}\n\n{\n  b\n  a\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Source([]byte(tt.input), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+			again, err := Source(got, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(again) != string(got) {
+				t.Errorf("expected formatting to be idempotent, got:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestSource_SyntaxError(t *testing.T) {
+	if _, err := Source([]byte("type {")); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc := asttest.Parse(t, "type Query { b: Int a: Int }")
+	expected := "type Query {\n  a: Int\n  b: Int\n}"
+	if got := Document(doc, WithSortedFields()); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if name := doc.Definitions[0].(*ast.ObjectTypeDefinition).Fields[0].Name.Value; name != "b" {
+		t.Errorf("expected document to be unchanged, got first field %s", name)
+	}
+}
@@ -0,0 +1,346 @@
+// Package fuzz generates random but valid operations from a schema, to
+// stress-test servers and measure validation performance:
+//
+//	g := fuzz.New(s, rand.New(rand.NewPCG(1, 2)), fuzz.Options{MaxDepth: 4})
+//	doc, err := g.Operation(ast.OperationTypeQuery)
+//	query := printer.PrintDocument(doc)
+//
+// Arguments are generated as literals. Required arguments of custom
+// scalars need a generator in Options.Scalars, otherwise fields and input
+// fields requiring them are never selected.
+package fuzz
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Options configure generated operations. Zero values select defaults.
+type Options struct {
+	// MaxDepth is maximum nesting of selection sets, 3 by default.
+	MaxDepth int
+
+	// MaxFields is maximum number of fields selected in a selection set,
+	// 5 by default. Subscriptions select a single root field.
+	MaxFields int
+
+	// MaxListLength is maximum number of items of generated lists, 3 by
+	// default.
+	MaxListLength int
+
+	// OptionalArguments is probability of generating an optional argument
+	// or input field, 0.5 by default. Negative disables them.
+	OptionalArguments float64
+
+	// Weight returns relative probability of selecting field of parent;
+	// fields with weight 0 or less are never selected. All fields weigh 1
+	// when nil.
+	Weight func(parent *schema.Type, field *schema.Field) float64
+
+	// Scalars generate values of custom scalars by name.
+	Scalars map[string]func(r *rand.Rand) ast.Value
+}
+
+// Generator generates operations. It is not safe for concurrent use, as
+// its random source is not.
+type Generator struct {
+	schema *schema.Schema
+	rand   *rand.Rand
+	opts   Options
+
+	// inputs caches whether values of input types can be generated; false
+	// while being computed, which breaks cycles.
+	inputs map[string]bool
+}
+
+// New returns generator of operations of s using random source r.
+func New(s *schema.Schema, r *rand.Rand, opts Options) *Generator {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 3
+	}
+	if opts.MaxFields <= 0 {
+		opts.MaxFields = 5
+	}
+	if opts.MaxListLength <= 0 {
+		opts.MaxListLength = 3
+	}
+	if opts.OptionalArguments == 0 {
+		opts.OptionalArguments = 0.5
+	}
+	return &Generator{schema: s, rand: r, opts: opts, inputs: make(map[string]bool)}
+}
+
+// Operation returns document with an anonymous operation of type typ.
+func (g *Generator) Operation(typ ast.OperationType) (*ast.Document, error) {
+	root := g.schema.RootType(typ)
+	if root == nil {
+		return nil, fmt.Errorf("fuzz: schema has no %s type", typ)
+	}
+	maxFields := g.opts.MaxFields
+	if typ == ast.OperationTypeSubscription {
+		maxFields = 1
+	}
+	selections := g.fields(root, 1, maxFields, "")
+	if len(selections) == 0 {
+		return nil, errors.New("fuzz: no selectable fields of " + root.Name)
+	}
+	return &ast.Document{Definitions: []ast.Definition{&ast.OperationDefinition{
+		OperationType: typ,
+		SelectionSet:  &ast.SelectionSet{Selections: selections},
+	}}}, nil
+}
+
+// selectionSet returns selection set of composite type t at depth, or nil
+// when nothing can be selected.
+func (g *Generator) selectionSet(t *schema.Type, depth int) *ast.SelectionSet {
+	var selections []ast.Selection
+	if t.Kind == schema.KindUnion {
+		selections = g.fragments(t, depth)
+	} else {
+		selections = g.fields(t, depth, g.opts.MaxFields, "")
+	}
+	if len(selections) == 0 {
+		return nil
+	}
+	return &ast.SelectionSet{Selections: selections}
+}
+
+// fragments returns __typename and inline fragments on random members of
+// union t. Fields of fragments are aliased with member names, so that
+// fields of the same name but different types never overlap.
+func (g *Generator) fragments(t *schema.Type, depth int) []ast.Selection {
+	selections := []ast.Selection{&ast.Field{Name: &ast.Name{Value: "__typename"}}}
+	members := g.schema.PossibleTypes(t)
+	g.rand.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+	for _, member := range members[:min(len(members), g.opts.MaxFields)] {
+		fields := g.fields(member, depth, g.opts.MaxFields, member.Name+"_")
+		if len(fields) == 0 {
+			continue
+		}
+		selections = append(selections, &ast.InlineFragment{
+			TypeCondition: &ast.NamedType{Name: &ast.Name{Value: member.Name}},
+			SelectionSet:  &ast.SelectionSet{Selections: fields},
+		})
+	}
+	return selections
+}
+
+// fields returns up to limit distinct fields of t picked by weight. Fields
+// are aliased with prefix when it is not empty.
+func (g *Generator) fields(t *schema.Type, depth, limit int, prefix string) []ast.Selection {
+	var (
+		candidates []*schema.Field
+		weights    []float64
+		total      float64
+	)
+	for _, f := range t.Fields {
+		w := 1.0
+		if g.opts.Weight != nil {
+			w = g.opts.Weight(t, f)
+		}
+		if w <= 0 || !g.canGenerateArguments(f.Arguments) {
+			continue
+		}
+		candidates = append(candidates, f)
+		weights = append(weights, w)
+		total += w
+	}
+
+	var selections []ast.Selection
+	for len(selections) < limit && len(candidates) > 0 {
+		// Pick without replacement.
+		i, x := 0, g.rand.Float64()*total
+		for ; i < len(candidates)-1 && x >= weights[i]; i++ {
+			x -= weights[i]
+		}
+		f := candidates[i]
+		total -= weights[i]
+		candidates = append(candidates[:i], candidates[i+1:]...)
+		weights = append(weights[:i], weights[i+1:]...)
+
+		if field := g.field(f, depth); field != nil {
+			if prefix != "" {
+				field.Alias = &ast.Name{Value: prefix + f.Name}
+			}
+			selections = append(selections, field)
+		}
+	}
+	return selections
+}
+
+// field returns selection of f at depth, or nil when its type is
+// composite and nothing can be selected within MaxDepth.
+func (g *Generator) field(f *schema.Field, depth int) *ast.Field {
+	field := &ast.Field{Name: &ast.Name{Value: f.Name}, Arguments: g.arguments(f.Arguments, 0)}
+	if t := g.schema.NamedType(f.Type); t != nil && t.Kind != schema.KindScalar && t.Kind != schema.KindEnum {
+		if depth >= g.opts.MaxDepth {
+			return nil
+		}
+		if field.SelectionSet = g.selectionSet(t, depth+1); field.SelectionSet == nil {
+			return nil
+		}
+	}
+	return field
+}
+
+// arguments returns values of required arguments and, randomly, of
+// optional ones. Optional arguments are not generated beyond MaxDepth
+// levels of nested input objects, which bounds recursive input types.
+func (g *Generator) arguments(defs []*schema.InputValue, depth int) []*ast.Argument {
+	var args []*ast.Argument
+	for _, def := range defs {
+		if !g.included(def, depth) {
+			continue
+		}
+		args = append(args, &ast.Argument{Name: &ast.Name{Value: def.Name}, Value: g.value(def.Type, depth)})
+	}
+	return args
+}
+
+// included reports whether value of def is generated.
+func (g *Generator) included(def *schema.InputValue, depth int) bool {
+	if required(def) {
+		return true
+	}
+	return depth < g.opts.MaxDepth && g.canGenerate(def.Type) && g.rand.Float64() < g.opts.OptionalArguments
+}
+
+func required(def *schema.InputValue) bool {
+	_, nonNull := def.Type.(*ast.NonNullType)
+	return nonNull && def.DefaultValue == nil
+}
+
+// value returns random literal of input type t.
+func (g *Generator) value(t ast.Type, depth int) ast.Value {
+	switch t := t.(type) {
+	case *ast.NonNullType:
+		return g.value(t.Type, depth)
+	case *ast.ListType:
+		list := &ast.ListValue{}
+		for range g.rand.IntN(g.opts.MaxListLength + 1) {
+			list.Values = append(list.Values, g.value(t.Type, depth))
+		}
+		return list
+	}
+
+	name := t.(*ast.NamedType).Name.Value
+	if gen := g.opts.Scalars[name]; gen != nil {
+		return gen(g.rand)
+	}
+	typ := g.schema.Type(name)
+	switch {
+	case typ == nil || typ.Kind == schema.KindScalar:
+		return g.scalar(name)
+	case typ.Kind == schema.KindEnum:
+		return &ast.EnumValue{Value: typ.EnumValues[g.rand.IntN(len(typ.EnumValues))].Name}
+	}
+
+	obj := &ast.ObjectValue{}
+	fields := typ.InputFields
+	if typ.IsOneOf() {
+		// Exactly one field, which must not be null.
+		var candidates, leaves []*schema.InputValue
+		for _, f := range fields {
+			if g.canGenerate(f.Type) {
+				candidates = append(candidates, f)
+				if t := g.schema.NamedType(f.Type); t == nil || t.Kind != schema.KindInputObject {
+					leaves = append(leaves, f)
+				}
+			}
+		}
+		if depth >= g.opts.MaxDepth && len(leaves) > 0 {
+			// Stop recursion through oneOf fields.
+			candidates = leaves
+		}
+		f := candidates[g.rand.IntN(len(candidates))]
+		return &ast.ObjectValue{Fields: []*ast.ObjectField{{Name: &ast.Name{Value: f.Name}, Value: g.value(f.Type, depth+1)}}}
+	}
+	for _, f := range fields {
+		if g.included(f, depth+1) {
+			obj.Fields = append(obj.Fields, &ast.ObjectField{Name: &ast.Name{Value: f.Name}, Value: g.value(f.Type, depth+1)})
+		}
+	}
+	return obj
+}
+
+// scalar returns value of built-in scalar name.
+func (g *Generator) scalar(name string) ast.Value {
+	switch name {
+	case "Int":
+		return &ast.IntValue{Value: strconv.Itoa(g.rand.IntN(100))}
+	case "Float":
+		return &ast.FloatValue{Value: strconv.FormatFloat(float64(g.rand.IntN(10000))/100, 'f', 2, 64)}
+	case "Boolean":
+		return &ast.BooleanValue{Value: g.rand.IntN(2) == 0}
+	case "ID":
+		return &ast.StringValue{Value: strconv.Itoa(1 + g.rand.IntN(1000))}
+	}
+	return &ast.StringValue{Value: words[g.rand.IntN(len(words))]}
+}
+
+var words = []string{"graph", "query", "schema", "field", "type", "node", "edge", "cursor", "value", "list"}
+
+// canGenerateArguments reports whether all required arguments can be
+// generated.
+func (g *Generator) canGenerateArguments(defs []*schema.InputValue) bool {
+	for _, def := range defs {
+		if required(def) && !g.canGenerate(def.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// canGenerate reports whether values of input type t can be generated.
+func (g *Generator) canGenerate(t ast.Type) bool {
+	for {
+		switch wrapped := t.(type) {
+		case *ast.NonNullType:
+			t = wrapped.Type
+			continue
+		case *ast.ListType:
+			t = wrapped.Type
+			continue
+		}
+		break
+	}
+	name := t.(*ast.NamedType).Name.Value
+	if g.opts.Scalars[name] != nil {
+		return true
+	}
+	typ := g.schema.Type(name)
+	switch {
+	case typ == nil:
+		return builtInScalars[name]
+	case typ.Kind == schema.KindScalar:
+		return builtInScalars[name]
+	case typ.Kind == schema.KindEnum:
+		return len(typ.EnumValues) > 0
+	case typ.Kind != schema.KindInputObject:
+		return false
+	}
+
+	if ok, seen := g.inputs[name]; seen {
+		return ok
+	}
+	g.inputs[name] = false
+	ok := g.canGenerateArguments(typ.InputFields)
+	if typ.IsOneOf() {
+		ok = false
+		for _, f := range typ.InputFields {
+			if g.canGenerate(f.Type) {
+				ok = true
+				break
+			}
+		}
+	}
+	g.inputs[name] = ok
+	return ok
+}
+
+var builtInScalars = map[string]bool{"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true}
@@ -0,0 +1,130 @@
+package fuzz
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+const testSchema = `
+type Query {
+  node(id: ID!): Node
+  search(term: String!, filter: Filter, first: Int = 10): [Result!]!
+  user(by: UserBy!): User
+  users(role: Role, page: Page): [User]
+  byDate(date: Date!): [Post]
+  optionalDate(date: Date): Post
+}
+type Mutation { createPost(input: PostInput!): Post }
+type Subscription { postAdded: Post, userAdded: User }
+
+interface Node { id: ID! }
+union Result = User | Post
+type User implements Node { id: ID! name: String role: Role posts(first: Int): [Post!]! friends: [User] }
+type Post implements Node { id: ID! name: Int author: User! tags: [String!] score: Float }
+enum Role { ADMIN EDITOR VIEWER }
+scalar Date
+input Filter { roles: [Role!] and: Filter not: Filter published: Boolean }
+input Page { first: Int! after: String }
+input UserBy @oneOf { id: ID name: String nested: UserBy }
+input PostInput { title: String! tags: [String!]! author: UserBy! published: Date }
+directive @oneOf on INPUT_OBJECT
+`
+
+func TestGenerator_Valid(t *testing.T) {
+	s := asttest.Schema(t, testSchema)
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"Defaults", Options{}},
+		{"Deep", Options{MaxDepth: 6, MaxFields: 10, OptionalArguments: 1}},
+		{"Shallow", Options{MaxDepth: 2, MaxFields: 1, OptionalArguments: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(s, rand.New(rand.NewPCG(1, 2)), tt.opts)
+			for i := range 200 {
+				typ := []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription}[i%3]
+				doc, err := g.Operation(typ)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if errs := validation.Validate(s, doc); len(errs) > 0 {
+					t.Fatalf("invalid operation %s: %v", printer.PrintDocument(doc), errs[0].Message)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Options(t *testing.T) {
+	s := asttest.Schema(t, testSchema)
+	tests := []struct {
+		name        string
+		opts        Options
+		contains    string
+		notContains string
+	}{
+		{"Custom scalar skipped", Options{MaxFields: 10}, "", "byDate"},
+		{"Custom scalar generated", Options{MaxFields: 10, Scalars: map[string]func(*rand.Rand) ast.Value{
+			"Date": func(*rand.Rand) ast.Value { return &ast.StringValue{Value: "2024-01-01"} },
+		}}, "byDate", ""},
+		{"Weight excludes", Options{MaxFields: 10, Weight: func(parent *schema.Type, f *schema.Field) float64 {
+			if f.Name == "search" {
+				return 0
+			}
+			return 1
+		}}, "", "search"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(s, rand.New(rand.NewPCG(3, 4)), tt.opts)
+			var all strings.Builder
+			for range 50 {
+				doc, err := g.Operation(ast.OperationTypeQuery)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				all.WriteString(printer.PrintDocument(doc))
+			}
+			if tt.contains != "" && !strings.Contains(all.String(), tt.contains) {
+				t.Errorf("expected operations to contain %q", tt.contains)
+			}
+			if tt.notContains != "" && strings.Contains(all.String(), tt.notContains) {
+				t.Errorf("expected operations not to contain %q", tt.notContains)
+			}
+		})
+	}
+}
+
+func TestGenerator_Errors(t *testing.T) {
+	g := New(asttest.Schema(t, testSchema), rand.New(rand.NewPCG(1, 2)), Options{Weight: func(*schema.Type, *schema.Field) float64 { return 0 }})
+	expected := "fuzz: no selectable fields of Query"
+	if _, err := g.Operation(ast.OperationTypeQuery); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	s := asttest.Schema(b, testSchema)
+	g := New(s, rand.New(rand.NewPCG(1, 2)), Options{MaxDepth: 5, MaxFields: 8})
+	docs := make([]*ast.Document, 100)
+	for i := range docs {
+		doc, err := g.Operation(ast.OperationTypeQuery)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+		docs[i] = doc
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		validation.Validate(s, docs[i%len(docs)])
+	}
+}
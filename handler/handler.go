@@ -0,0 +1,323 @@
+// Package handler serves GraphQL over HTTP: it parses requests, validates
+// operations against a schema and writes responses of a pluggable
+// executor with status codes of the GraphQL-over-HTTP specification:
+//
+//	http.Handle("/graphql", &handler.Handler{Schema: s, Executor: executor})
+//
+// https://graphql.github.io/graphql-over-http/draft/
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/edit"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/response"
+	"github.com/gqlhub/gqlhub-core/schema"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+// Media types of responses.
+const (
+	MediaTypeGraphQLResponse = "application/graphql-response+json"
+	MediaTypeJSON            = "application/json"
+)
+
+// DefaultMaxBodySize limits size of POST request bodies.
+const DefaultMaxBodySize = 1 << 20
+
+// Params are an operation to execute, already validated.
+type Params struct {
+	Document   *ast.Document
+	Operation  *ast.OperationDefinition
+	Variables  map[string]any // Decoded from JSON with numbers as json.Number.
+	Extensions map[string]any
+}
+
+// Executor executes operations. Fields errors are reported in the
+// response; request errors are already handled by Handler.
+type Executor interface {
+	Execute(ctx context.Context, params *Params) *response.Response
+}
+
+// ExecutorFunc adapts a function to Executor.
+type ExecutorFunc func(ctx context.Context, params *Params) *response.Response
+
+func (f ExecutorFunc) Execute(ctx context.Context, params *Params) *response.Response {
+	return f(ctx, params)
+}
+
+// Handler is an http.Handler serving GraphQL requests. Queries may be sent
+// with GET or POST, mutations only with POST. Subscriptions need a
+// streaming transport such as transport/ws and are rejected with status
+// 405. POST bodies must be application/json.
+//
+// Responses are application/graphql-response+json when the client accepts
+// it and application/json otherwise; requests without Accept header get
+// application/json, as sent by legacy clients. Requests that fail before
+// execution, e.g. with invalid operations, get status 400 with
+// application/graphql-response+json and 200 with application/json.
+// Executed operations get status 200. Responses to introspection
+// operations carry provenance of Schema in their extensions.
+type Handler struct {
+	Schema   *schema.Schema
+	Executor Executor
+
+	// Rules are validation rules checked, validation.SpecifiedRules when
+	// empty.
+	Rules []validation.Rule
+
+	// MaxBodySize limits size of POST bodies, DefaultMaxBodySize when
+	// zero.
+	MaxBodySize int64
+
+	// IDs, when set, decodes IDs in arguments and variables of operations
+	// before they are executed and encodes IDs in data of their responses,
+	// which must be built of response.Object values. Its Schema is usually
+	// Schema. Operations IDs fail to decode are not executed.
+	IDs *response.IDs
+}
+
+// requestError is a request that cannot be executed.
+type requestError struct {
+	status int    // Status regardless of media type, or 0.
+	allow  string // Allow header of status 405.
+	errors []*response.Error
+}
+
+func (e *requestError) Error() string {
+	return e.errors[0].Message
+}
+
+func badRequest(format string, args ...any) *requestError {
+	return &requestError{status: http.StatusBadRequest, errors: []*response.Error{{Message: fmt.Sprintf(format, args...)}}}
+}
+
+// invalid returns error of request that fails before execution with
+// errors.
+func invalid(errs ...*response.Error) *requestError {
+	return &requestError{errors: errs}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Executor == nil {
+		http.Error(w, "Handler has no Executor", http.StatusInternalServerError)
+		return
+	}
+	mediaType := negotiate(r.Header.Values("Accept"))
+	if mediaType == "" {
+		http.Error(w, "Not acceptable, accepted media types are "+MediaTypeGraphQLResponse+" and "+MediaTypeJSON, http.StatusNotAcceptable)
+		return
+	}
+
+	params, err := h.params(w, r)
+	if err != nil {
+		var reqErr *requestError
+		if !errors.As(err, &reqErr) {
+			reqErr = badRequest("%v", err)
+		}
+		status := reqErr.status
+		switch {
+		case status == http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", reqErr.allow)
+		case status == 0 && mediaType == MediaTypeGraphQLResponse:
+			status = http.StatusBadRequest
+		case status == 0:
+			status = http.StatusOK
+		}
+		data, _ := json.Marshal(struct {
+			Errors []*response.Error `json:"errors"`
+		}{reqErr.errors})
+		write(w, mediaType, status, data)
+		return
+	}
+
+	resp := h.Executor.Execute(r.Context(), params)
+	if h.IDs != nil {
+		h.IDs.Encode(resp, params.Document, params.Operation)
+	}
+	if introspects(params.Document, params.Operation) {
+		resp.Extensions = h.Schema.Provenance.Expose(resp.Extensions)
+	}
+	data, err := resp.Encode()
+	if err != nil {
+		http.Error(w, "Cannot encode response", http.StatusInternalServerError)
+		return
+	}
+	write(w, mediaType, http.StatusOK, data)
+}
+
+func write(w http.ResponseWriter, mediaType string, status int, data []byte) {
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// params reads, parses and validates request of r.
+func (h *Handler) params(w http.ResponseWriter, r *http.Request) (*Params, error) {
+	req, err := h.read(w, r)
+	if err != nil {
+		return nil, err
+	}
+	if req.Query == nil || *req.Query == "" {
+		return nil, badRequest("Must provide query string.")
+	}
+	query := *req.Query
+
+	p, err := parser.New(lexer.New(query), parser.WithErrorRecovery())
+	var doc *ast.Document
+	if err == nil {
+		doc, err = p.ParseDocument()
+	}
+	if err != nil {
+		return nil, invalid(syntaxErrors(query, err)...)
+	}
+	if errs := validation.Validate(h.Schema, doc, h.Rules...); len(errs) > 0 {
+		return nil, invalid(convert(query, errs)...)
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return nil, invalid(&response.Error{Message: err.Error()})
+	}
+	if op.OperationType == ast.OperationTypeSubscription {
+		return nil, &requestError{
+			status: http.StatusMethodNotAllowed,
+			allow:  "GET, POST",
+			errors: []*response.Error{{Message: "Subscriptions are not supported over this transport."}},
+		}
+	}
+	if r.Method == http.MethodGet && op.OperationType != ast.OperationTypeQuery {
+		return nil, &requestError{
+			status: http.StatusMethodNotAllowed,
+			allow:  "POST",
+			errors: []*response.Error{{Message: fmt.Sprintf("Can only perform a %s operation from a POST request.", op.OperationType)}},
+		}
+	}
+	if errs := validation.VariableValues(h.Schema, op, req.Variables); len(errs) > 0 {
+		return nil, invalid(convert(query, errs)...)
+	}
+	variables := req.Variables
+	if h.IDs != nil {
+		if doc, variables, err = h.IDs.Decode(doc, op, variables); err != nil {
+			return nil, invalid(&response.Error{Message: err.Error()})
+		}
+		// Decoded document holds a copy of op when its arguments changed.
+		if op, err = selectOperation(doc, req.OperationName); err != nil {
+			return nil, invalid(&response.Error{Message: err.Error()})
+		}
+	}
+	return &Params{Document: doc, Operation: op, Variables: variables, Extensions: req.Extensions}, nil
+}
+
+// syntaxErrors returns errors of parsing query as response errors located
+// in query.
+func syntaxErrors(query string, err error) []*response.Error {
+	var errs parser.SyntaxErrors
+	if !errors.As(err, &errs) {
+		return []*response.Error{syntaxError(query, err)}
+	}
+	result := make([]*response.Error, len(errs))
+	for i, e := range errs {
+		result[i] = syntaxError(query, e)
+	}
+	return result
+}
+
+func syntaxError(query string, err error) *response.Error {
+	var syntaxErr *parser.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		err = syntaxErr.Err
+	}
+	e := &response.Error{Message: "Syntax Error: " + err.Error()}
+	var lexErr *lexer.LexError
+	switch {
+	case errors.As(err, &lexErr):
+		e.Locations = []response.Location{{Line: lexErr.Line, Column: lexErr.Column}}
+	case syntaxErr != nil:
+		p := edit.Position(query, syntaxErr.Position)
+		e.Locations = []response.Location{{Line: p.Line, Column: p.Column}}
+	}
+	return e
+}
+
+// convert returns validation errors as response errors located in query.
+func convert(query string, errs []*validation.Error) []*response.Error {
+	result := make([]*response.Error, len(errs))
+	for i, e := range errs {
+		result[i] = &response.Error{Message: e.Message}
+		for _, pos := range e.Positions {
+			p := edit.Position(query, pos)
+			result[i].Locations = append(result[i].Locations, response.Location{Line: p.Line, Column: p.Column})
+		}
+	}
+	return result
+}
+
+// introspects reports whether root selection set of op selects __schema
+// or __type. Fragments are looked up in doc.
+func introspects(doc *ast.Document, op *ast.OperationDefinition) bool {
+	visited := make(map[string]bool)
+	var walk func(set *ast.SelectionSet) bool
+	walk = func(set *ast.SelectionSet) bool {
+		for _, sel := range set.Selections {
+			switch s := sel.(type) {
+			case *ast.Field:
+				if s.Name.Value == "__schema" || s.Name.Value == "__type" {
+					return true
+				}
+			case *ast.InlineFragment:
+				if walk(s.SelectionSet) {
+					return true
+				}
+			case *ast.FragmentSpread:
+				if visited[s.Name.Value] {
+					continue
+				}
+				visited[s.Name.Value] = true
+				for _, def := range doc.Definitions {
+					if f, ok := def.(*ast.FragmentDefinition); ok && f.Name.Value == s.Name.Value && walk(f.SelectionSet) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	return walk(op.SelectionSet)
+}
+
+// selectOperation returns operation named name, or the only operation of
+// doc when name is empty.
+func selectOperation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "" && found != nil:
+			return nil, errors.New("Must provide operation name if query contains multiple operations.")
+		case name == "" || op.Name != nil && op.Name.Value == name:
+			found = op
+		}
+	}
+	switch {
+	case found != nil:
+		return found, nil
+	case name != "":
+		return nil, fmt.Errorf("Unknown operation named %q.", name)
+	}
+	return nil, errors.New("Must provide an operation.")
+}
@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/provenance"
+	"github.com/gqlhub/gqlhub-core/response"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+const testSDL = `
+type Query { user(id: ID!): User fail: Int }
+type Mutation { rename(name: String!): User }
+type Subscription { renamed: User }
+type User { id: ID! name: String }
+`
+
+// echo responds with name of executed operation and its variables, or
+// with field error of operations selecting fail first.
+var echo = ExecutorFunc(func(ctx context.Context, params *Params) *response.Response {
+	if f, ok := params.Operation.SelectionSet.Selections[0].(*ast.Field); ok && f.Name.Value == "fail" {
+		return &response.Response{Data: map[string]any{"fail": nil}, Errors: []*response.Error{{Message: "failed", Path: []any{"fail"}}}}
+	}
+	name := ""
+	if params.Operation.Name != nil {
+		name = params.Operation.Name.Value
+	}
+	return &response.Response{Data: map[string]any{"operation": name, "variables": params.Variables}}
+})
+
+func TestHandler(t *testing.T) {
+	h := &Handler{Schema: asttest.Schema(t, testSDL), Executor: echo, MaxBodySize: 256}
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		accept      string
+		body        string
+
+		status            int
+		expectedType      string
+		expectedBody      string
+		expectedAllowance string
+	}{
+		{
+			name:         "POST",
+			method:       http.MethodPost,
+			contentType:  "application/json; charset=utf-8",
+			accept:       MediaTypeGraphQLResponse,
+			body:         `{"query": "query U($id: ID!) { user(id: $id) { name } }", "variables": {"id": 1}}`,
+			status:       http.StatusOK,
+			expectedType: MediaTypeGraphQLResponse,
+			expectedBody: `{"data":{"operation":"U","variables":{"id":1}}}`,
+		},
+		{
+			name:         "GET",
+			method:       http.MethodGet,
+			url:          "?" + url.Values{"query": {"query A { fail } query B { user(id: 1) { id } }"}, "operationName": {"B"}, "variables": {`{}`}}.Encode(),
+			accept:       "application/graphql-response+json, application/json;q=0.9",
+			status:       http.StatusOK,
+			expectedType: MediaTypeGraphQLResponse,
+			expectedBody: `{"data":{"operation":"B","variables":{}}}`,
+		},
+		{
+			name:         "field errors",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			accept:       MediaTypeGraphQLResponse,
+			body:         `{"query": "{ fail }"}`,
+			status:       http.StatusOK,
+			expectedType: MediaTypeGraphQLResponse,
+			expectedBody: `{"data":{"fail":null},"errors":[{"message":"failed","path":["fail"]}]}`,
+		},
+		{
+			name:         "legacy client",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			body:         `{"query": "{ unknown }"}`,
+			status:       http.StatusOK,
+			expectedType: MediaTypeJSON,
+			expectedBody: `{"errors":[{"message":"Cannot query field \"unknown\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:         "validation errors",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			accept:       "*/*",
+			body:         `{"query": "{ unknown }"}`,
+			status:       http.StatusBadRequest,
+			expectedType: MediaTypeGraphQLResponse,
+			expectedBody: `{"errors":[{"message":"Cannot query field \"unknown\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:         "syntax errors",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			accept:       MediaTypeJSON,
+			body:         `{"query": "{ user(id: \"1) }"}`,
+			status:       http.StatusOK,
+			expectedType: MediaTypeJSON,
+			expectedBody: `{"errors":[{"message":"Syntax Error: Error at 1:17: unterminated string","locations":[{"line":1,"column":17}]}]}`,
+		},
+		{
+			name:         "parse errors",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			accept:       MediaTypeGraphQLResponse,
+			body:         `{"query": "{ user(id: 1) { id } }\nquery A { user(id:) { id } }\nquery B { user(id: 1 { id } }"}`,
+			status:       http.StatusBadRequest,
+			expectedType: MediaTypeGraphQLResponse,
+			expectedBody: `{"errors":[{"message":"Syntax Error: unexpected value token: RPAREN","locations":[{"line":2,"column":19}]},{"message":"Syntax Error: expected NAME, got LBRACE","locations":[{"line":3,"column":22}]}]}`,
+		},
+		{
+			name:         "invalid variables",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			accept:       MediaTypeGraphQLResponse,
+			body:         `{"query": "query($id: ID!) { user(id: $id) { id } }", "variables": {"id": true}}`,
+			status:       http.StatusBadRequest,
+			expectedType: MediaTypeGraphQLResponse,
+			expectedBody: `{"errors":[{"message":"Variable \"$id\" got invalid value true; ID cannot represent value: true","locations":[{"line":1,"column":7}]}]}`,
+		},
+		{
+			name:         "missing query",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			accept:       MediaTypeJSON,
+			body:         `{"variables": {}}`,
+			status:       http.StatusBadRequest,
+			expectedType: MediaTypeJSON,
+			expectedBody: `{"errors":[{"message":"Must provide query string."}]}`,
+		},
+		{
+			name:         "invalid body",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			body:         `{"query": 1}`,
+			status:       http.StatusBadRequest,
+			expectedType: MediaTypeJSON,
+			expectedBody: `{"errors":[{"message":"Invalid request body: json: cannot unmarshal number into Go struct field request.query of type string"}]}`,
+		},
+		{
+			name:         "body too large",
+			method:       http.MethodPost,
+			contentType:  MediaTypeJSON,
+			body:         `{"query": "{ ` + strings.Repeat("fail ", 60) + `}"}`,
+			status:       http.StatusRequestEntityTooLarge,
+			expectedType: MediaTypeJSON,
+			expectedBody: `{"errors":[{"message":"Request body is larger than 256 bytes."}]}`,
+		},
+		{
+			name:         "unsupported media type",
+			method:       http.MethodPost,
+			contentType:  "text/plain",
+			body:         `{ fail }`,
+			status:       http.StatusUnsupportedMediaType,
+			expectedType: MediaTypeJSON,
+			expectedBody: `{"errors":[{"message":"Unsupported media type \"text/plain\", requests must be application/json."}]}`,
+		},
+		{
+			name:              "mutation with GET",
+			method:            http.MethodGet,
+			url:               "?query=mutation+%7B+rename(name:%22a%22)+%7B+id+%7D+%7D",
+			status:            http.StatusMethodNotAllowed,
+			expectedType:      MediaTypeJSON,
+			expectedBody:      `{"errors":[{"message":"Can only perform a mutation operation from a POST request."}]}`,
+			expectedAllowance: "POST",
+		},
+		{
+			name:              "subscription",
+			method:            http.MethodPost,
+			contentType:       MediaTypeJSON,
+			accept:            MediaTypeGraphQLResponse,
+			body:              `{"query": "subscription { renamed { id } }"}`,
+			status:            http.StatusMethodNotAllowed,
+			expectedType:      MediaTypeGraphQLResponse,
+			expectedBody:      `{"errors":[{"message":"Subscriptions are not supported over this transport."}]}`,
+			expectedAllowance: "GET, POST",
+		},
+		{
+			name:              "unsupported method",
+			method:            http.MethodPut,
+			status:            http.StatusMethodNotAllowed,
+			expectedType:      "text/plain",
+			expectedBody:      "Method not allowed\n",
+			expectedAllowance: "GET, POST",
+		},
+		{
+			name:         "not acceptable",
+			method:       http.MethodGet,
+			url:          "?query=%7Bfail%7D",
+			accept:       "text/html, application/json;q=0",
+			status:       http.StatusNotAcceptable,
+			expectedType: "text/plain",
+			expectedBody: "Not acceptable, accepted media types are application/graphql-response+json and application/json\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/graphql"+tt.url, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.expectedType+";") {
+				t.Errorf("expected content type %s, got %s", tt.expectedType, got)
+			}
+			if got := w.Body.String(); got != tt.expectedBody {
+				t.Errorf("expected body %s, got %s", tt.expectedBody, got)
+			}
+			if got := w.Header().Get("Allow"); got != tt.expectedAllowance {
+				t.Errorf("expected Allow %q, got %q", tt.expectedAllowance, got)
+			}
+		})
+	}
+}
+
+func TestHandler_NoExecutor(t *testing.T) {
+	h := &Handler{Schema: asttest.Schema(t, testSDL)}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query=%7Bfail%7D", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if expected, got := "Handler has no Executor\n", w.Body.String(); got != expected {
+		t.Errorf("expected body %q, got %q", expected, got)
+	}
+}
+
+func TestHandler_Provenance(t *testing.T) {
+	s := asttest.Schema(t, testSDL, schema.WithProvenance(provenance.Provenance{GitSHA: "3f2a9c1"}))
+	h := &Handler{Schema: s, Executor: echo}
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "introspection",
+			query:    `query I { __schema { queryType { name } } }`,
+			expected: `{"data":{"operation":"I","variables":null},"extensions":{"schemaProvenance":{"gitSha":"3f2a9c1"}}}`,
+		},
+		{
+			name:     "introspection in fragment",
+			query:    `query T { ...F } fragment F on Query { __type(name: "User") { name } }`,
+			expected: `{"data":{"operation":"T","variables":null},"extensions":{"schemaProvenance":{"gitSha":"3f2a9c1"}}}`,
+		},
+		{
+			name:     "other operations",
+			query:    `query U { user(id: 1) { __typename } }`,
+			expected: `{"data":{"operation":"U","variables":null}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+url.Values{"query": {tt.query}}.Encode(), nil))
+			if got := w.Body.String(); got != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// prefixCodec prefixes IDs with its value.
+type prefixCodec string
+
+func (p prefixCodec) Encode(id string) (string, error) {
+	return string(p) + id, nil
+}
+
+func (p prefixCodec) Decode(id string) (string, error) {
+	decoded, ok := strings.CutPrefix(id, string(p))
+	if !ok {
+		return "", errors.New("unknown prefix")
+	}
+	return decoded, nil
+}
+
+func TestHandler_IDs(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	// user responds with user of ID passed to the first field, either
+	// literally or as variable $id.
+	user := ExecutorFunc(func(ctx context.Context, params *Params) *response.Response {
+		f := params.Operation.SelectionSet.Selections[0].(*ast.Field)
+		var id any
+		switch v := f.Arguments[0].Value.(type) {
+		case *ast.StringValue:
+			id = v.Value
+		case *ast.Variable:
+			id = params.Variables[v.Name.Value]
+		}
+		u := response.NewObject()
+		u.Set("id", id)
+		data := response.NewObject()
+		data.Set("user", u)
+		return &response.Response{Data: data}
+	})
+	h := &Handler{
+		Schema:   s,
+		Executor: user,
+		IDs:      &response.IDs{Schema: s, Codec: response.IDCodecs(nil, prefixCodec("u"))},
+	}
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "literal",
+			body:     `{"query": "{ user(id: \"u1\") { id } }"}`,
+			status:   http.StatusOK,
+			expected: `{"data":{"user":{"id":"u1"}}}`,
+		},
+		{
+			name:     "variable",
+			body:     `{"query": "query($id: ID!) { user(id: $id) { id } }", "variables": {"id": "u2"}}`,
+			status:   http.StatusOK,
+			expected: `{"data":{"user":{"id":"u2"}}}`,
+		},
+		{
+			name:     "invalid ID",
+			body:     `{"query": "{ user(id: \"1\") { id } }"}`,
+			status:   http.StatusBadRequest,
+			expected: `{"errors":[{"message":"invalid ID \"1\" for Query.user(id:): unknown prefix"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", MediaTypeJSON)
+			r.Header.Set("Accept", MediaTypeGraphQLResponse)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Body.String(); got != tt.expected {
+				t.Errorf("expected body %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept   []string
+		expected string
+	}{
+		{nil, MediaTypeJSON},
+		{[]string{"application/json"}, MediaTypeJSON},
+		{[]string{"application/graphql-response+json"}, MediaTypeGraphQLResponse},
+		{[]string{"application/json, application/graphql-response+json"}, MediaTypeGraphQLResponse},
+		{[]string{"application/json", "application/graphql-response+json;q=0.5"}, MediaTypeJSON},
+		{[]string{"application/*"}, MediaTypeGraphQLResponse},
+		{[]string{"*/*;q=0.1, application/json"}, MediaTypeJSON},
+		{[]string{"text/html"}, ""},
+		{[]string{"application/*;q=0"}, ""},
+	}
+	for _, tt := range tests {
+		if got := negotiate(tt.accept); got != tt.expected {
+			t.Errorf("negotiate(%q): expected %q, got %q", tt.accept, tt.expected, got)
+		}
+	}
+}
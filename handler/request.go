@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// request holds GraphQL-over-HTTP request parameters.
+//
+// https://graphql.github.io/graphql-over-http/draft/#sec-Request-Parameters
+type request struct {
+	Query         *string        `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	Extensions    map[string]any `json:"extensions"`
+}
+
+// read returns parameters of r, from URL query of GET requests and from
+// JSON body of POST requests.
+func (h *Handler) read(w http.ResponseWriter, r *http.Request) (*request, error) {
+	var req request
+	if r.Method == http.MethodGet {
+		params := r.URL.Query()
+		if params.Has("query") {
+			query := params.Get("query")
+			req.Query = &query
+		}
+		req.OperationName = params.Get("operationName")
+		if s := params.Get("variables"); s != "" {
+			if err := decode([]byte(s), &req.Variables); err != nil {
+				return nil, badRequest("Invalid variables: %v", err)
+			}
+		}
+		if s := params.Get("extensions"); s != "" {
+			if err := decode([]byte(s), &req.Extensions); err != nil {
+				return nil, badRequest("Invalid extensions: %v", err)
+			}
+		}
+		return &req, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != MediaTypeJSON {
+		e := badRequest("Unsupported media type %q, requests must be %s.", mediaType, MediaTypeJSON)
+		e.status = http.StatusUnsupportedMediaType
+		return nil, e
+	}
+	limit := h.MaxBodySize
+	if limit == 0 {
+		limit = DefaultMaxBodySize
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			e := badRequest("Request body is larger than %d bytes.", limit)
+			e.status = http.StatusRequestEntityTooLarge
+			return nil, e
+		}
+		return nil, badRequest("Cannot read request body: %v", err)
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, badRequest("Invalid request body: %v", err)
+	}
+	return &req, nil
+}
+
+// decode decodes JSON data into v, keeping numbers as json.Number.
+func decode(data []byte, v any) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+	if d.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// negotiate returns media type of response to request with Accept header
+// values accept, or "" when neither media type is acceptable.
+func negotiate(accept []string) string {
+	if len(accept) == 0 {
+		return MediaTypeJSON
+	}
+	type mediaRange struct {
+		typ     string
+		quality float64
+	}
+	var ranges []mediaRange
+	for _, value := range accept {
+		for _, s := range strings.Split(value, ",") {
+			typ, params, err := mime.ParseMediaType(s)
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if s, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(s, 64); err != nil {
+					continue
+				}
+			}
+			ranges = append(ranges, mediaRange{typ, q})
+		}
+	}
+
+	// quality returns quality of the most specific range matching
+	// mediaType.
+	quality := func(mediaType string) float64 {
+		q, specificity := 0.0, 0
+		for _, r := range ranges {
+			s := 0
+			switch r.typ {
+			case mediaType:
+				s = 3
+			case "application/*":
+				s = 2
+			case "*/*":
+				s = 1
+			}
+			if s > specificity {
+				q, specificity = r.quality, s
+			}
+		}
+		return q
+	}
+	best, bestQuality := "", 0.0
+	for _, mediaType := range []string{MediaTypeGraphQLResponse, MediaTypeJSON} {
+		if q := quality(mediaType); q > bestQuality {
+			best, bestQuality = mediaType, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,59 @@
+// Package incremental recognizes the @defer and @stream directives of
+// incremental delivery: validation rules for where they may appear, and
+// Split, which splits an operation into shapes of the initial payload and
+// of the payloads delivered after it.
+//
+//	errs := validation.Validate(s, doc, append(validation.SpecifiedRules, incremental.Rules...)...)
+package incremental
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+// Names of directives of incremental delivery.
+const (
+	DeferDirective  = "defer"
+	StreamDirective = "stream"
+)
+
+// DirectivesSDL declares @defer and @stream. It can be appended to schemas
+// supporting incremental delivery, so that KnownDirectives and other rules
+// check them like any other directive.
+const DirectivesSDL = `
+"Delivers fields of the fragment after the rest of the response."
+directive @defer(if: Boolean! = true, label: String) on FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+"Delivers items of the list field after the first initialCount ones."
+directive @stream(if: Boolean! = true, label: String, initialCount: Int! = 0) on FIELD
+`
+
+// Rules check use of @defer and @stream, in addition to
+// validation.SpecifiedRules. Rules depending on types are skipped without
+// schema.
+var Rules = []validation.Rule{
+	DeferStreamDirectiveOnRootField,
+	DeferStreamDirectiveOnValidOperations,
+	DeferStreamDirectiveLabel,
+	StreamDirectiveOnListField,
+}
+
+// directive returns directive name of directives or nil.
+func directive(directives []*ast.Directive, name string) *ast.Directive {
+	for _, d := range directives {
+		if d.Name.Value == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// argument returns value of argument name of d or nil.
+func argument(d *ast.Directive, name string) ast.Value {
+	for _, arg := range d.Arguments {
+		if arg.Name.Value == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package incremental
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/introspection"
+	"github.com/gqlhub/gqlhub-core/schema"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+// DeferStreamDirectiveOnRootField checks that @defer is not applied to
+// fragments selected on, and @stream not to fields of, the mutation and
+// subscription root types, whose root fields are executed serially or
+// produce events of their own.
+var DeferStreamDirectiveOnRootField = validation.Rule{
+	Name: "DeferStreamDirectiveOnRootField",
+	Check: func(ctx *validation.Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		mutation, subscription := ctx.Schema.MutationType(), ctx.Schema.SubscriptionType()
+		walkSelections(ctx, func(parent *schema.Type, sel ast.Selection) {
+			d, subject := incrementalDirective(sel)
+			switch {
+			case d == nil || parent == nil:
+			case parent == mutation:
+				ctx.Reportf([]ast.Node{d}, "%s directive cannot be used on root mutation type %q.", subject, parent.Name)
+			case parent == subscription:
+				ctx.Reportf([]ast.Node{d}, "%s directive cannot be used on root subscription type %q.", subject, parent.Name)
+			}
+		})
+	},
+}
+
+// DeferStreamDirectiveOnValidOperations checks that subscription
+// operations, including fragments they spread, use @defer and @stream only
+// with if argument which can be false, so that servers can disable
+// incremental delivery of subscription events.
+var DeferStreamDirectiveOnValidOperations = validation.Rule{
+	Name: "DeferStreamDirectiveOnValidOperations",
+	Check: func(ctx *validation.Context) {
+		fragments := make(map[string]*ast.FragmentDefinition)
+		for _, def := range ctx.Document.Definitions {
+			if f, ok := def.(*ast.FragmentDefinition); ok {
+				if _, ok := fragments[f.Name.Value]; !ok {
+					fragments[f.Name.Value] = f
+				}
+			}
+		}
+		reported := make(map[*ast.Directive]bool)
+
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok || op.OperationType != ast.OperationTypeSubscription {
+				continue
+			}
+			visited := make(map[string]bool)
+			var walk func(set *ast.SelectionSet)
+			walk = func(set *ast.SelectionSet) {
+				if set == nil {
+					return
+				}
+				for _, sel := range set.Selections {
+					if d, subject := incrementalDirective(sel); d != nil && !reported[d] && !canBeFalse(d) {
+						reported[d] = true
+						ctx.Reportf([]ast.Node{d}, "%s directive not supported on subscription operations. Disable `@%s` by setting the `if` argument to `false`.", subject, d.Name.Value)
+					}
+					switch s := sel.(type) {
+					case *ast.Field:
+						walk(s.SelectionSet)
+					case *ast.InlineFragment:
+						walk(s.SelectionSet)
+					case *ast.FragmentSpread:
+						name := s.Name.Value
+						if f, ok := fragments[name]; ok && !visited[name] {
+							visited[name] = true
+							walk(f.SelectionSet)
+						}
+					}
+				}
+			}
+			walk(op.SelectionSet)
+		}
+	},
+}
+
+// DeferStreamDirectiveLabel checks that labels of @defer and @stream are
+// static strings unique within the document, so that payloads can be
+// told apart by their labels.
+var DeferStreamDirectiveLabel = validation.Rule{
+	Name: "DeferStreamDirectiveLabel",
+	Check: func(ctx *validation.Context) {
+		labels := make(map[string]*ast.Directive)
+		ast.WalkDocument(ast.VisitorFuncs{EnterFunc: func(n ast.Node) ast.Action {
+			d, ok := n.(*ast.Directive)
+			if !ok || d.Name.Value != DeferDirective && d.Name.Value != StreamDirective {
+				return ast.Continue
+			}
+			switch label := argument(d, "label").(type) {
+			case nil:
+			case *ast.StringValue:
+				if first, ok := labels[label.Value]; ok {
+					ctx.Reportf([]ast.Node{first, d}, "Defer/Stream directive label argument must be unique.")
+				} else {
+					labels[label.Value] = d
+				}
+			default:
+				ctx.Reportf([]ast.Node{d}, "Defer/Stream directive label argument must be a static string.")
+			}
+			return ast.Skip
+		}}, ctx.Document)
+	},
+}
+
+// StreamDirectiveOnListField checks that @stream is applied to list fields
+// only.
+var StreamDirectiveOnListField = validation.Rule{
+	Name: "StreamDirectiveOnListField",
+	Check: func(ctx *validation.Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		walkSelections(ctx, func(parent *schema.Type, sel ast.Selection) {
+			f, ok := sel.(*ast.Field)
+			if !ok {
+				return
+			}
+			d := directive(f.Directives, StreamDirective)
+			def := fieldDefinition(ctx.Schema, parent, f.Name.Value)
+			if d == nil || def == nil {
+				return
+			}
+			t := def.Type
+			if nonNull, ok := t.(*ast.NonNullType); ok {
+				t = nonNull.Type
+			}
+			if _, ok := t.(*ast.ListType); !ok {
+				ctx.Reportf([]ast.Node{d}, "Stream directive cannot be used on non-list field %q on type %q.", f.Name.Value, parent.Name)
+			}
+		})
+	},
+}
+
+// incrementalDirective returns @stream of field or @defer of fragment sel
+// and its name as subject of messages, or nil.
+func incrementalDirective(sel ast.Selection) (*ast.Directive, string) {
+	switch s := sel.(type) {
+	case *ast.Field:
+		return directive(s.Directives, StreamDirective), "Stream"
+	case *ast.InlineFragment:
+		return directive(s.Directives, DeferDirective), "Defer"
+	case *ast.FragmentSpread:
+		return directive(s.Directives, DeferDirective), "Defer"
+	}
+	return nil, ""
+}
+
+// canBeFalse reports whether if argument of d is false or a variable.
+func canBeFalse(d *ast.Directive) bool {
+	switch v := argument(d, "if").(type) {
+	case *ast.BooleanValue:
+		return !v.Value
+	case *ast.Variable:
+		return true
+	}
+	return false
+}
+
+// walkSelections calls fn for every selection of operations and fragment
+// definitions of ctx.Document, including nested ones, with the type it is
+// selected on, which is nil when unknown. Fragment spreads are not
+// followed; fragment definitions are walked on their own.
+func walkSelections(ctx *validation.Context, fn func(parent *schema.Type, sel ast.Selection)) {
+	var walk func(parent *schema.Type, set *ast.SelectionSet)
+	walk = func(parent *schema.Type, set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+		for _, sel := range set.Selections {
+			fn(parent, sel)
+			switch s := sel.(type) {
+			case *ast.Field:
+				var t *schema.Type
+				if def := fieldDefinition(ctx.Schema, parent, s.Name.Value); def != nil {
+					t = lookupType(ctx.Schema, namedType(def.Type))
+				}
+				walk(t, s.SelectionSet)
+			case *ast.InlineFragment:
+				t := parent
+				if s.TypeCondition != nil {
+					t = lookupType(ctx.Schema, s.TypeCondition.Name.Value)
+				}
+				walk(t, s.SelectionSet)
+			}
+		}
+	}
+	for _, def := range ctx.Document.Definitions {
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			walk(ctx.Schema.RootType(d.OperationType), d.SelectionSet)
+		case *ast.FragmentDefinition:
+			walk(lookupType(ctx.Schema, d.TypeCondition.Name.Value), d.SelectionSet)
+		}
+	}
+}
+
+// fieldDefinition returns definition of field name of parent, including
+// meta fields, or nil.
+func fieldDefinition(s *schema.Schema, parent *schema.Type, name string) *schema.Field {
+	if parent == nil {
+		return nil
+	}
+	if f := parent.Field(name); f != nil {
+		return f
+	}
+	var query string
+	if t := s.QueryType(); t != nil {
+		query = t.Name
+	}
+	return introspection.MetaField(name, parent.Name, query)
+}
+
+// lookupType returns named type of s or of the introspection system, or
+// nil.
+func lookupType(s *schema.Schema, name string) *schema.Type {
+	if t := s.Type(name); t != nil {
+		return t
+	}
+	return introspection.SystemType(name)
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch tt := t.(type) {
+		case *ast.NamedType:
+			return tt.Name.Value
+		case *ast.ListType:
+			t = tt.Type
+		case *ast.NonNullType:
+			t = tt.Type
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package incremental
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+const testSDL = `
+schema { query: Query mutation: Mutation subscription: Subscription }
+type Query { user: User users: [User!]! names: [String] }
+type Mutation { update: User users: [User] }
+type Subscription { user: User users: [User] }
+type User { id: ID! name: String friends: [User!] }
+`
+
+type expectedError struct {
+	message   string
+	positions []int
+}
+
+// expectErrors validates input against s with rule and compares reported
+// errors.
+func expectErrors(t *testing.T, s *schema.Schema, rule validation.Rule, input string, expected ...expectedError) {
+	t.Helper()
+	var got []expectedError
+	for _, e := range validation.Validate(s, asttest.Parse(t, input), rule) {
+		if e.Rule != rule.Name {
+			t.Errorf("expected rule %s, got %s", rule.Name, e.Rule)
+		}
+		got = append(got, expectedError{e.Message, e.Positions})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestDirectivesSDL(t *testing.T) {
+	s := asttest.Schema(t, testSDL+DirectivesSDL)
+	input := `{ users @stream(initialCount: 1) { ... @defer { name } } }`
+	if errs := validation.Validate(s, asttest.Parse(t, input), append(validation.SpecifiedRules, Rules...)...); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	errs := validation.Validate(s, asttest.Parse(t, `{ user @defer { name } }`), validation.KnownDirectives)
+	if len(errs) != 1 || errs[0].Message != `Directive "@defer" may not be used on FIELD.` {
+		t.Errorf("expected misplaced @defer, got %v", errs)
+	}
+}
+
+func TestDeferStreamDirectiveOnRootField(t *testing.T) {
+	s := asttest.Schema(t, testSDL+DirectivesSDL)
+	rule := DeferStreamDirectiveOnRootField
+	input := `mutation { ... @defer { update { id } } update { ... @defer { name } } users @stream { id } }
+subscription { ...F @defer users @stream { friends @stream { id } } }
+fragment F on Subscription { user { id } }
+{ ... @defer { user { id } } users @stream { id } }`
+	expectErrors(t, nil, rule, input)
+	expectErrors(t, s, rule, input,
+		expectedError{`Defer directive cannot be used on root mutation type "Mutation".`, []int{15}},
+		expectedError{`Stream directive cannot be used on root mutation type "Mutation".`, []int{strings.Index(input, "@stream")}},
+		expectedError{`Defer directive cannot be used on root subscription type "Subscription".`, []int{strings.Index(input, "@defer users")}},
+		expectedError{`Stream directive cannot be used on root subscription type "Subscription".`, []int{strings.Index(input, "@stream { friends")}},
+	)
+}
+
+func TestDeferStreamDirectiveOnValidOperations(t *testing.T) {
+	rule := DeferStreamDirectiveOnValidOperations
+	input := `subscription S($v: Boolean!) { user { ...F ... @defer(if: false) { id } ... @defer(if: $v) { id } } }
+subscription T { user { ...F } }
+fragment F on User { ... @defer(if: true) { name } friends @stream { id } }
+fragment G on User { ... @defer { name } }
+{ user { ...F } }`
+	expectErrors(t, nil, rule, input,
+		expectedError{"Defer directive not supported on subscription operations. Disable `@defer` by setting the `if` argument to `false`.", []int{strings.Index(input, "@defer(if: true)")}},
+		expectedError{"Stream directive not supported on subscription operations. Disable `@stream` by setting the `if` argument to `false`.", []int{strings.Index(input, "@stream")}},
+	)
+}
+
+func TestDeferStreamDirectiveLabel(t *testing.T) {
+	rule := DeferStreamDirectiveLabel
+	input := `query($l: String) { users @stream(label: "a") { ... @defer(label: "b") { id } ... @defer(label: "a") { name } ... @defer(label: $l) { id } ... @defer { id } } }
+fragment F on User { friends @stream(label: "b") { id } }`
+	expectErrors(t, nil, rule, input,
+		expectedError{`Defer/Stream directive label argument must be unique.`, []int{26, strings.Index(input, `@defer(label: "a")`)}},
+		expectedError{`Defer/Stream directive label argument must be a static string.`, []int{strings.Index(input, `@defer(label: $l)`)}},
+		expectedError{`Defer/Stream directive label argument must be unique.`, []int{strings.Index(input, `@defer(label: "b")`), strings.Index(input, `@stream(label: "b")`)}},
+	)
+}
+
+func TestStreamDirectiveOnListField(t *testing.T) {
+	s := asttest.Schema(t, testSDL+DirectivesSDL)
+	rule := StreamDirectiveOnListField
+	input := `{ users @stream { name @stream friends @stream { id } } user @stream { id } names @stream unknown @stream __typename @stream }`
+	expectErrors(t, nil, rule, input)
+	expectErrors(t, s, rule, input,
+		expectedError{`Stream directive cannot be used on non-list field "name" on type "User".`, []int{23}},
+		expectedError{`Stream directive cannot be used on non-list field "user" on type "Query".`, []int{strings.Index(input, "user @stream") + 5}},
+		expectedError{`Stream directive cannot be used on non-list field "__typename" on type "Query".`, []int{strings.Index(input, "__typename") + 11}},
+	)
+}
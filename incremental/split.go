@@ -0,0 +1,281 @@
+package incremental
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// Kind of payload.
+type Kind string
+
+const (
+	KindInitial Kind = "initial" // The initial response.
+	KindDefer   Kind = "defer"   // Fields of a deferred fragment.
+	KindStream  Kind = "stream"  // Remaining items of a streamed list field.
+)
+
+// Payload is shape of a payload of incremental delivery.
+type Payload struct {
+	Kind Kind
+
+	// Path of response keys from the operation root to the object
+	// fields of a deferred fragment are added to, or to the streamed
+	// field. List indices are not part of the path.
+	Path []string
+
+	// Label is label argument of the directive, empty when not set.
+	Label string
+
+	// Directive is @defer or @stream directive the payload is created by,
+	// nil for the initial payload.
+	Directive *ast.Directive
+
+	// TypeCondition is type condition of a deferred fragment, empty when
+	// an inline fragment has none.
+	TypeCondition string
+
+	// InitialCount is number of items of a streamed field delivered by
+	// the payload of the field itself.
+	InitialCount int
+
+	// SelectionSet selects fields delivered by the payload: fields of a
+	// deferred fragment or of items of a streamed field. Fragment spreads
+	// are inlined, and deferred fragments are moved to payloads of their
+	// own. Nil for streamed leaf fields.
+	SelectionSet *ast.SelectionSet
+
+	// Parent is payload delivering the field or object the payload adds
+	// to, nil for the initial payload. Payloads are delivered after their
+	// parents.
+	Parent *Payload
+}
+
+// Plan is shape of a response delivered incrementally.
+type Plan struct {
+	Initial *Payload
+
+	// Incremental are deferred and streamed payloads in document order,
+	// each after its parent.
+	Incremental []*Payload
+}
+
+// Split splits selection set of op into shape of the initial payload and
+// shapes of payloads of its @defer fragments and @stream fields. Fragment
+// definitions are looked up in doc; spreads of unknown fragments and
+// fragment cycles are skipped.
+//
+// If arguments are evaluated with variables, falling back to default
+// values of variable definitions; directives disabled by them are ignored.
+// Fields selected both by a deferred fragment and outside of it are part
+// of both shapes, and selection sets of deferred fields only are left
+// empty. The document is expected to be valid, see Rules; Split
+// fails only for initialCount arguments that are not non-negative
+// integers.
+func Split(doc *ast.Document, op *ast.OperationDefinition, variables map[string]any) (*Plan, error) {
+	s := &splitter{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: make(map[string]any),
+		visiting:  make(map[string]bool),
+		plan:      &Plan{Initial: &Payload{Kind: KindInitial}},
+	}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			if _, ok := s.fragments[f.Name.Value]; !ok {
+				s.fragments[f.Name.Value] = f
+			}
+		}
+	}
+	for _, def := range op.VariableDefs {
+		name := def.Variable.Name.Value
+		if v, ok := variables[name]; ok {
+			s.variables[name] = v
+		} else if def.DefaultValue != nil {
+			s.variables[name] = def.DefaultValue
+		}
+	}
+
+	set, err := s.selectionSet(op.SelectionSet, nil, s.plan.Initial)
+	if err != nil {
+		return nil, err
+	}
+	s.plan.Initial.SelectionSet = set
+	return s.plan, nil
+}
+
+type splitter struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any // Values of variables, or literal default values.
+	visiting  map[string]bool
+	plan      *Plan
+}
+
+// selectionSet returns shape of set at path delivered by payload.
+func (s *splitter) selectionSet(set *ast.SelectionSet, path []string, payload *Payload) (*ast.SelectionSet, error) {
+	result := &ast.SelectionSet{Position: set.Position, EndPosition: set.EndPosition}
+	for _, sel := range set.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			f, err := s.field(sel, path, payload)
+			if err != nil {
+				return nil, err
+			}
+			result.Selections = append(result.Selections, f)
+		case *ast.InlineFragment:
+			var condition string
+			if sel.TypeCondition != nil {
+				condition = sel.TypeCondition.Name.Value
+			}
+			if d := s.deferred(sel.Directives); d != nil {
+				if err := s.deferFragment(d, condition, sel.SelectionSet, path, payload); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			shape, err := s.selectionSet(sel.SelectionSet, path, payload)
+			if err != nil {
+				return nil, err
+			}
+			c := *sel
+			c.SelectionSet = shape
+			result.Selections = append(result.Selections, &c)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			frag, ok := s.fragments[name]
+			if !ok || s.visiting[name] {
+				continue
+			}
+			s.visiting[name] = true
+			var err error
+			if d := s.deferred(sel.Directives); d != nil {
+				err = s.deferFragment(d, frag.TypeCondition.Name.Value, frag.SelectionSet, path, payload)
+			} else {
+				var shape *ast.SelectionSet
+				if shape, err = s.selectionSet(frag.SelectionSet, path, payload); err == nil {
+					result.Selections = append(result.Selections, &ast.InlineFragment{
+						Position:      sel.Position,
+						EndPosition:   sel.EndPosition,
+						TypeCondition: frag.TypeCondition,
+						Directives:    sel.Directives,
+						SelectionSet:  shape,
+					})
+				}
+			}
+			delete(s.visiting, name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *splitter) field(f *ast.Field, path []string, payload *Payload) (*ast.Field, error) {
+	key := f.Name.Value
+	if f.Alias != nil {
+		key = f.Alias.Value
+	}
+	path = append(path[:len(path):len(path)], key)
+
+	var stream *Payload
+	if d := directive(f.Directives, StreamDirective); d != nil && s.enabled(d) {
+		count, err := s.initialCount(d)
+		if err != nil {
+			return nil, err
+		}
+		stream = &Payload{Kind: KindStream, Path: path, Label: s.label(d), Directive: d, InitialCount: count, Parent: payload}
+		s.plan.Incremental = append(s.plan.Incremental, stream)
+	}
+	if f.SelectionSet == nil {
+		return f, nil
+	}
+	shape, err := s.selectionSet(f.SelectionSet, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	if stream != nil {
+		stream.SelectionSet = shape
+	}
+	c := *f
+	c.SelectionSet = shape
+	return &c, nil
+}
+
+// deferFragment adds payload of fragment deferred by d.
+func (s *splitter) deferFragment(d *ast.Directive, condition string, set *ast.SelectionSet, path []string, parent *Payload) error {
+	payload := &Payload{Kind: KindDefer, Path: path, Label: s.label(d), Directive: d, TypeCondition: condition, Parent: parent}
+	s.plan.Incremental = append(s.plan.Incremental, payload)
+	shape, err := s.selectionSet(set, path, payload)
+	payload.SelectionSet = shape
+	return err
+}
+
+// deferred returns enabled @defer of directives or nil.
+func (s *splitter) deferred(directives []*ast.Directive) *ast.Directive {
+	if d := directive(directives, DeferDirective); d != nil && s.enabled(d) {
+		return d
+	}
+	return nil
+}
+
+// enabled reports whether if argument of d is true, which is its default.
+func (s *splitter) enabled(d *ast.Directive) bool {
+	switch v := s.value(argument(d, "if")).(type) {
+	case bool:
+		return v
+	case *ast.BooleanValue:
+		return v.Value
+	}
+	return true
+}
+
+func (s *splitter) label(d *ast.Directive) string {
+	switch v := s.value(argument(d, "label")).(type) {
+	case string:
+		return v
+	case *ast.StringValue:
+		return v.Value
+	}
+	return ""
+}
+
+func (s *splitter) initialCount(d *ast.Directive) (int, error) {
+	var n float64
+	switch v := s.value(argument(d, "initialCount")).(type) {
+	case nil:
+		return 0, nil
+	case *ast.IntValue:
+		i, err := strconv.ParseInt(v.Value, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("incremental: invalid initialCount %s", v.Value)
+		}
+		n = float64(i)
+	case float64:
+		n = v
+	case int:
+		n = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("incremental: invalid initialCount %s", v)
+		}
+		n = f
+	default:
+		return 0, fmt.Errorf("incremental: invalid initialCount %v", v)
+	}
+	if n < 0 || n != float64(int(n)) {
+		return 0, fmt.Errorf("incremental: initialCount must be a non-negative integer, got %v", n)
+	}
+	return int(n), nil
+}
+
+// value returns value v resolving variables, which is a literal for
+// literals and default values of variables, or nil when not set.
+func (s *splitter) value(v ast.Value) any {
+	if variable, ok := v.(*ast.Variable); ok {
+		return s.variables[variable.Name.Value]
+	}
+	return v
+}
@@ -0,0 +1,136 @@
+package incremental
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// describe returns payloads of plan, one per line.
+func describe(plan *Plan) string {
+	var b strings.Builder
+	index := make(map[*Payload]int)
+	for i, p := range append([]*Payload{plan.Initial}, plan.Incremental...) {
+		index[p] = i
+		fmt.Fprintf(&b, "%d %s", i, p.Kind)
+		if p.Parent != nil {
+			fmt.Fprintf(&b, " parent=%d", index[p.Parent])
+		}
+		if len(p.Path) > 0 {
+			fmt.Fprintf(&b, " path=%s", strings.Join(p.Path, "."))
+		}
+		if p.Label != "" {
+			fmt.Fprintf(&b, " label=%s", p.Label)
+		}
+		if p.TypeCondition != "" {
+			fmt.Fprintf(&b, " on=%s", p.TypeCondition)
+		}
+		if p.Kind == KindStream {
+			fmt.Fprintf(&b, " initialCount=%d", p.InitialCount)
+		}
+		if p.SelectionSet != nil {
+			fmt.Fprintf(&b, " %s", printer.PrintCompact(&ast.Document{Definitions: []ast.Definition{
+				&ast.OperationDefinition{OperationType: ast.OperationTypeQuery, SelectionSet: p.SelectionSet},
+			}}))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expected  string
+	}{
+		{
+			name:     "without directives",
+			query:    `{ user { id ...F } } fragment F on User { name }`,
+			expected: "0 initial {user{id...on User{name}}}\n",
+		},
+		{
+			name:  "deferred fragments",
+			query: `{ user { id ...F @defer(label: "f") ... @defer { friends { id } } } } fragment F on User { name }`,
+			expected: "0 initial {user{id}}\n" +
+				"1 defer parent=0 path=user label=f on=User {name}\n" +
+				"2 defer parent=0 path=user {friends{id}}\n",
+		},
+		{
+			name:  "nested",
+			query: `{ ... @defer(label: "outer") { user { id ... on User @defer(label: "inner") { name } } } }`,
+			expected: "0 initial {}\n" +
+				"1 defer parent=0 label=outer {user{id}}\n" +
+				"2 defer parent=1 path=user label=inner on=User {name}\n",
+		},
+		{
+			name:  "streamed fields",
+			query: `{ users @stream(initialCount: 2) { id ... @defer { name } } names @stream }`,
+			expected: "0 initial {users@stream(initialCount:2){id}names@stream}\n" +
+				"1 stream parent=0 path=users initialCount=2 {id}\n" +
+				"2 defer parent=0 path=users {name}\n" +
+				"3 stream parent=0 path=names initialCount=0\n",
+		},
+		{
+			name:      "variables",
+			query:     `query($defer: Boolean = true, $stream: Boolean!, $count: Int = 1) { user { ... @defer(if: $defer) { id } } users @stream(if: $stream, initialCount: $count) { id } all: users @stream(if: false) { id } }`,
+			variables: map[string]any{"stream": true, "count": 3.0},
+			expected: "0 initial {user{}users@stream(if:$stream initialCount:$count){id}all:users@stream(if:false){id}}\n" +
+				"1 defer parent=0 path=user {id}\n" +
+				"2 stream parent=0 path=users initialCount=3 {id}\n",
+		},
+		{
+			name:      "disabled",
+			query:     `query($defer: Boolean = true) { user { ... @defer(if: $defer) { id } } }`,
+			variables: map[string]any{"defer": false},
+			expected:  "0 initial {user{...@defer(if:$defer){id}}}\n",
+		},
+		{
+			name:     "unknown fragments and cycles",
+			query:    `{ ...Unknown ...A } fragment A on Query { a ...B } fragment B on Query { b ...A @defer }`,
+			expected: "0 initial {...on Query{a...on Query{b}}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			plan, err := Split(doc, doc.Definitions[0].(*ast.OperationDefinition), tt.variables)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describe(plan); got != tt.expected {
+				t.Errorf("expected\n%s\ngot\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSplit_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expected  string
+	}{
+		{"negative", `{ users @stream(initialCount: -1) { id } }`, nil, "incremental: initialCount must be a non-negative integer, got -1"},
+		{"fraction", `query($n: Int!) { users @stream(initialCount: $n) { id } }`, map[string]any{"n": 1.5}, "incremental: initialCount must be a non-negative integer, got 1.5"},
+		{"not a number", `query($n: Int!) { users @stream(initialCount: $n) { id } }`, map[string]any{"n": "1"}, "incremental: invalid initialCount 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			_, err := Split(doc, doc.Definitions[0].(*ast.OperationDefinition), tt.variables)
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tt.expected)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, err)
+			}
+		})
+	}
+}
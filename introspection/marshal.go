@@ -0,0 +1,392 @@
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// CanonicalQuery is introspection query selecting everything
+// MarshalSchema emits. Servers not implementing the latest specification
+// may need a query tailored by Query.
+var CanonicalQuery = Query(Options{
+	Descriptions:          true,
+	Deprecated:            true,
+	SpecifiedByURL:        true,
+	DirectiveIsRepeatable: true,
+	SchemaDescription:     true,
+	InputValueDeprecation: true,
+	OneOf:                 true,
+	TypeDepth:             DefaultTypeDepth,
+})
+
+// MarshalSchema returns introspection result of s as JSON, in the form
+// {"__schema": ...} of data of CanonicalQuery. See FromSchema.
+func MarshalSchema(s *schema.Schema) ([]byte, error) {
+	data, err := json.Marshal(struct {
+		Schema *Schema `json:"__schema"`
+	}{FromSchema(s)})
+	if err != nil {
+		return nil, fmt.Errorf("introspection: encode schema: %w", err)
+	}
+	return data, nil
+}
+
+// FromSchema returns introspection of s as a server answers it. Types of
+// the introspection system, built-in scalars and built-in directives are
+// added unless s defines them, and deprecated elements are included.
+func FromSchema(s *schema.Schema) *Schema {
+	c := &converter{schema: s, system: systemSchema()}
+	result := &Schema{
+		Description:      s.Description,
+		QueryType:        typeName(s.QueryType()),
+		MutationType:     typeName(s.MutationType()),
+		SubscriptionType: typeName(s.SubscriptionType()),
+	}
+	for _, t := range s.Types() {
+		result.Types = append(result.Types, c.typ(s, t))
+	}
+	for _, t := range c.system.Types() {
+		if s.Type(t.Name) == nil {
+			result.Types = append(result.Types, c.typ(c.system, t))
+		}
+	}
+	for _, d := range s.DirectiveDefinitions() {
+		result.Directives = append(result.Directives, c.directive(d))
+	}
+	for _, d := range c.system.DirectiveDefinitions() {
+		if s.Directive(d.Name) == nil {
+			result.Directives = append(result.Directives, c.directive(d))
+		}
+	}
+	return result
+}
+
+// systemSDL defines types and directives every schema has.
+//
+// https://spec.graphql.org/draft/#sec-Schema-Introspection.Schema-Introspection-Schema
+const systemSDL = `
+scalar Int
+scalar Float
+scalar String
+scalar Boolean
+scalar ID
+
+directive @skip(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @include(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @deprecated(reason: String = "No longer supported") on FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | ENUM_VALUE
+directive @specifiedBy(url: String!) on SCALAR
+directive @oneOf on INPUT_OBJECT
+
+type __Schema {
+  description: String
+  types: [__Type!]!
+  queryType: __Type!
+  mutationType: __Type
+  subscriptionType: __Type
+  directives: [__Directive!]!
+}
+
+type __Type {
+  kind: __TypeKind!
+  name: String
+  description: String
+  fields(includeDeprecated: Boolean = false): [__Field!]
+  interfaces: [__Type!]
+  possibleTypes: [__Type!]
+  enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
+  inputFields(includeDeprecated: Boolean = false): [__InputValue!]
+  ofType: __Type
+  specifiedByURL: String
+  isOneOf: Boolean
+}
+
+enum __TypeKind {
+  SCALAR
+  OBJECT
+  INTERFACE
+  UNION
+  ENUM
+  INPUT_OBJECT
+  LIST
+  NON_NULL
+}
+
+type __Field {
+  name: String!
+  description: String
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  type: __Type!
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __InputValue {
+  name: String!
+  description: String
+  type: __Type!
+  defaultValue: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __EnumValue {
+  name: String!
+  description: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __Directive {
+  name: String!
+  description: String
+  isRepeatable: Boolean!
+  locations: [__DirectiveLocation!]!
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+}
+
+enum __DirectiveLocation {
+  QUERY
+  MUTATION
+  SUBSCRIPTION
+  FIELD
+  FRAGMENT_DEFINITION
+  FRAGMENT_SPREAD
+  INLINE_FRAGMENT
+  VARIABLE_DEFINITION
+  SCHEMA
+  SCALAR
+  OBJECT
+  FIELD_DEFINITION
+  ARGUMENT_DEFINITION
+  INTERFACE
+  UNION
+  ENUM
+  ENUM_VALUE
+  INPUT_OBJECT
+  INPUT_FIELD_DEFINITION
+}
+`
+
+// systemSchema returns schema built from systemSDL.
+var systemSchema = sync.OnceValue(func() *schema.Schema {
+	p, err := parser.New(lexer.New(systemSDL))
+	if err != nil {
+		panic(err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		panic(err)
+	}
+	s, err := schema.FromDocument(doc, schema.WithNamePolicy(schema.AllowIntrospectionNames))
+	if err != nil {
+		panic(err)
+	}
+	return s
+})
+
+// converter converts types and directives of schema, resolving kinds of
+// referenced types in it and then in the system schema.
+type converter struct {
+	schema *schema.Schema
+	system *schema.Schema
+}
+
+func (c *converter) typ(s *schema.Schema, t *schema.Type) *Type {
+	result := &Type{
+		Kind:           string(t.Kind),
+		Name:           t.Name,
+		Description:    t.Description,
+		SpecifiedByURL: t.SpecifiedByURL(),
+		IsOneOf:        t.IsOneOf(),
+	}
+	switch t.Kind {
+	case schema.KindObject, schema.KindInterface:
+		result.Interfaces = make([]*TypeRef, len(t.Interfaces))
+		for i, name := range t.Interfaces {
+			result.Interfaces[i] = c.named(name)
+		}
+		result.Fields = make([]*Field, len(t.Fields))
+		for i, f := range t.Fields {
+			reason, deprecated := f.Deprecated()
+			result.Fields[i] = &Field{
+				Name:              f.Name,
+				Description:       f.Description,
+				Args:              c.inputValues(f.Arguments),
+				Type:              c.typeRef(f.Type),
+				IsDeprecated:      deprecated,
+				DeprecationReason: reasonOf(reason, deprecated),
+			}
+		}
+	case schema.KindEnum:
+		result.EnumValues = make([]*EnumValue, len(t.EnumValues))
+		for i, v := range t.EnumValues {
+			reason, deprecated := v.Deprecated()
+			result.EnumValues[i] = &EnumValue{
+				Name:              v.Name,
+				Description:       v.Description,
+				IsDeprecated:      deprecated,
+				DeprecationReason: reasonOf(reason, deprecated),
+			}
+		}
+	case schema.KindInputObject:
+		result.InputFields = c.inputValues(t.InputFields)
+	}
+	if t.IsAbstract() {
+		result.PossibleTypes = []*TypeRef{}
+		for _, member := range s.PossibleTypes(t) {
+			result.PossibleTypes = append(result.PossibleTypes, c.named(member.Name))
+		}
+	}
+	return result
+}
+
+func (c *converter) directive(d *schema.Directive) *Directive {
+	locations := make([]string, len(d.Locations))
+	for i, l := range d.Locations {
+		locations[i] = string(l)
+	}
+	return &Directive{
+		Name:         d.Name,
+		Description:  d.Description,
+		IsRepeatable: d.Repeatable,
+		Locations:    locations,
+		Args:         c.inputValues(d.Arguments),
+	}
+}
+
+func (c *converter) inputValues(values []*schema.InputValue) []*InputValue {
+	result := make([]*InputValue, len(values))
+	for i, v := range values {
+		reason, deprecated := v.Deprecated()
+		result[i] = &InputValue{
+			Name:              v.Name,
+			Description:       v.Description,
+			Type:              c.typeRef(v.Type),
+			IsDeprecated:      deprecated,
+			DeprecationReason: reasonOf(reason, deprecated),
+		}
+		if v.DefaultValue != nil {
+			literal := printer.Value(v.DefaultValue)
+			result[i].DefaultValue = &literal
+		}
+	}
+	return result
+}
+
+func (c *converter) typeRef(t ast.Type) *TypeRef {
+	switch t := t.(type) {
+	case *ast.ListType:
+		return &TypeRef{Kind: "LIST", OfType: c.typeRef(t.Type)}
+	case *ast.NonNullType:
+		return &TypeRef{Kind: "NON_NULL", OfType: c.typeRef(t.Type)}
+	case *ast.NamedType:
+		return c.named(t.Name.Value)
+	}
+	return nil
+}
+
+// named returns reference to named type. Kind of types defined nowhere,
+// which built schemas do not have, is left empty.
+func (c *converter) named(name string) *TypeRef {
+	t := c.schema.Type(name)
+	if t == nil {
+		t = c.system.Type(name)
+	}
+	if t == nil {
+		return &TypeRef{Name: name}
+	}
+	return &TypeRef{Kind: string(t.Kind), Name: name}
+}
+
+func typeName(t *schema.Type) *TypeName {
+	if t == nil {
+		return nil
+	}
+	return &TypeName{Name: t.Name}
+}
+
+func reasonOf(reason string, deprecated bool) *string {
+	if !deprecated {
+		return nil
+	}
+	return &reason
+}
+
+// JSON encoding follows the introspection schema: empty descriptions and
+// fields not applicable to kind of type are null.
+
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	return json.Marshal(struct {
+		*plain
+		Description *string `json:"description"`
+	}{(*plain)(s), nullable(s.Description)})
+}
+
+func (t *Type) MarshalJSON() ([]byte, error) {
+	type plain Type
+	var isOneOf *bool
+	if t.Kind == "INPUT_OBJECT" {
+		isOneOf = &t.IsOneOf
+	}
+	return json.Marshal(struct {
+		*plain
+		Description    *string `json:"description"`
+		SpecifiedByURL *string `json:"specifiedByURL"`
+		IsOneOf        *bool   `json:"isOneOf"`
+	}{(*plain)(t), nullable(t.Description), nullable(t.SpecifiedByURL), isOneOf})
+}
+
+func (r *TypeRef) MarshalJSON() ([]byte, error) {
+	type plain TypeRef
+	return json.Marshal(struct {
+		*plain
+		Name *string `json:"name"`
+	}{(*plain)(r), nullable(r.Name)})
+}
+
+func (f *Field) MarshalJSON() ([]byte, error) {
+	type plain Field
+	return json.Marshal(struct {
+		*plain
+		Description *string `json:"description"`
+	}{(*plain)(f), nullable(f.Description)})
+}
+
+func (v *InputValue) MarshalJSON() ([]byte, error) {
+	type plain InputValue
+	return json.Marshal(struct {
+		*plain
+		Description *string `json:"description"`
+	}{(*plain)(v), nullable(v.Description)})
+}
+
+func (v *EnumValue) MarshalJSON() ([]byte, error) {
+	type plain EnumValue
+	return json.Marshal(struct {
+		*plain
+		Description *string `json:"description"`
+	}{(*plain)(v), nullable(v.Description)})
+}
+
+func (d *Directive) MarshalJSON() ([]byte, error) {
+	type plain Directive
+	return json.Marshal(struct {
+		*plain
+		Description *string `json:"description"`
+	}{(*plain)(d), nullable(d.Description)})
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,150 @@
+package introspection
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+const marshalSDL = `schema {
+  query: Root
+}
+
+"""Cache hint."""
+directive @cached(ttl: Int = 60) repeatable on FIELD | QUERY
+
+type Root {
+  node(id: ID!): Node
+  search(in: In = {limit: 10}): [Result!]!
+  old: String @deprecated
+  legacy: Int @deprecated(reason: "Use node.")
+}
+
+"""Object with ID."""
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  name: String
+}
+
+union Result = User
+
+input In @oneOf {
+  query: String
+  limit: Int
+}
+
+enum Color {
+  RED
+  BLUE @deprecated
+}
+
+scalar URL @specifiedBy(url: "https://url.spec.whatwg.org")`
+
+func TestMarshalSchema_RoundTrip(t *testing.T) {
+	data, err := MarshalSchema(asttest.Schema(t, marshalSDL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sdl, err := SDL(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sdl != marshalSDL {
+		t.Errorf("expected:\n%s\ngot:\n%s", marshalSDL, sdl)
+	}
+}
+
+func TestMarshalSchema(t *testing.T) {
+	data, err := MarshalSchema(asttest.Schema(t, marshalSDL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result struct {
+		Schema struct {
+			Description *string          `json:"description"`
+			Types       []map[string]any `json:"types"`
+			Directives  []map[string]any `json:"directives"`
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Schema.Description != nil {
+		t.Errorf("expected null description, got %q", *result.Schema.Description)
+	}
+
+	types := make(map[string]map[string]any)
+	for _, typ := range result.Schema.Types {
+		types[typ["name"].(string)] = typ
+	}
+	for _, name := range []string{"Int", "Float", "String", "Boolean", "ID", "__Schema", "__Type", "__TypeKind", "__Field", "__InputValue", "__EnumValue", "__Directive", "__DirectiveLocation"} {
+		if types[name] == nil {
+			t.Errorf("expected type %s", name)
+		}
+	}
+	var directives []string
+	for _, d := range result.Schema.Directives {
+		directives = append(directives, d["name"].(string))
+	}
+	if got, expected := strings.Join(directives, " "), "cached skip include deprecated specifiedBy oneOf"; got != expected {
+		t.Errorf("expected directives %q, got %q", expected, got)
+	}
+
+	tests := []struct {
+		typ, path string
+		expected  any
+	}{
+		{"Root", "description", nil},
+		{"Root", "interfaces", []any{}},
+		{"Root", "possibleTypes", nil},
+		{"Root", "inputFields", nil},
+		{"Root", "specifiedByURL", nil},
+		{"Root", "isOneOf", nil},
+		{"Node", "description", "Object with ID."},
+		{"Node", "possibleTypes", []any{map[string]any{"kind": "OBJECT", "name": "User", "ofType": nil}}},
+		{"Result", "possibleTypes", []any{map[string]any{"kind": "OBJECT", "name": "User", "ofType": nil}}},
+		{"Result", "fields", nil},
+		{"In", "isOneOf", true},
+		{"URL", "specifiedByURL", "https://url.spec.whatwg.org"},
+		{"URL", "fields", nil},
+	}
+	for _, tt := range tests {
+		got, ok := types[tt.typ][tt.path]
+		if !ok {
+			t.Errorf("%s: expected %s", tt.typ, tt.path)
+			continue
+		}
+		if gotJSON, expectedJSON := mustJSON(t, got), mustJSON(t, tt.expected); gotJSON != expectedJSON {
+			t.Errorf("%s: expected %s %s, got %s", tt.typ, tt.path, expectedJSON, gotJSON)
+		}
+	}
+
+	search := types["Root"]["fields"].([]any)[1].(map[string]any)
+	expected := `{"args":[{"defaultValue":"{limit: 10}","deprecationReason":null,"description":null,"isDeprecated":false,"name":"in","type":{"kind":"INPUT_OBJECT","name":"In","ofType":null}}],"deprecationReason":null,"description":null,"isDeprecated":false,"name":"search","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"LIST","name":null,"ofType":{"kind":"NON_NULL","name":null,"ofType":{"kind":"UNION","name":"Result","ofType":null}}}}}`
+	if got := mustJSON(t, search); got != expected {
+		t.Errorf("expected field:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	for _, field := range []string{"description", "specifiedByURL", "isRepeatable", "isOneOf", "inputFields(includeDeprecated: true)", "args(includeDeprecated: true)"} {
+		if !strings.Contains(CanonicalQuery, field) {
+			t.Errorf("expected query to select %s", field)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package introspection
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Names of meta fields, which are implicitly defined on types of every
+// schema.
+//
+// https://spec.graphql.org/draft/#sec-Type-Name-Introspection
+const (
+	TypenameField = "__typename"
+	SchemaField   = "__schema"
+	TypeField     = "__type"
+)
+
+// metaSDL defines meta fields on a type of its own, so that they can be
+// resolved along with the introspection types.
+const metaSDL = `
+type __Meta {
+  __typename: String!
+  __schema: __Schema!
+  __type(name: String!): __Type
+}
+`
+
+// metaSchema returns schema built from systemSDL and metaSDL.
+var metaSchema = sync.OnceValue(func() *schema.Schema {
+	p, err := parser.New(lexer.New(systemSDL + metaSDL))
+	if err != nil {
+		panic(err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		panic(err)
+	}
+	s, err := schema.FromDocument(doc, schema.WithNamePolicy(schema.AllowReservedNames))
+	if err != nil {
+		panic(err)
+	}
+	return s
+})
+
+// MetaField returns meta field name selected on composite type
+// parentType of schema whose query root type is queryType: __typename is
+// defined on every composite type, __schema and __type only on the query
+// root type. It returns nil for other fields, so executors and tools can
+// resolve meta fields the same way, whether selected directly, under an
+// alias or in a fragment. Types of meta fields are resolved by
+// SystemType. The field is shared and must not be modified.
+func MetaField(name, parentType, queryType string) *schema.Field {
+	switch name {
+	case SchemaField, TypeField:
+		if parentType != queryType {
+			return nil
+		}
+	case TypenameField:
+	default:
+		return nil
+	}
+	return metaSchema().Type("__Meta").Field(name)
+}
+
+// SystemType returns type of the introspection system, such as __Schema
+// or __Type, or nil when name is not one. The type is shared and must not
+// be modified.
+func SystemType(name string) *schema.Type {
+	if !strings.HasPrefix(name, "__") || name == "__Meta" {
+		return nil
+	}
+	return metaSchema().Type(name)
+}
@@ -0,0 +1,46 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+func TestMetaField(t *testing.T) {
+	tests := []struct {
+		name, parent string
+		expected     string
+	}{
+		{TypenameField, "User", "__typename: String!"},
+		{TypenameField, "Query", "__typename: String!"},
+		{SchemaField, "Query", "__schema: __Schema!"},
+		{TypeField, "Query", "__type(name: String!): __Type"},
+		{SchemaField, "User", ""},
+		{TypeField, "User", ""},
+		{"name", "Query", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.parent+"."+tt.name, func(t *testing.T) {
+			var got string
+			if f := MetaField(tt.name, tt.parent, "Query"); f != nil {
+				got = printer.Print(f.Definition)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSystemType(t *testing.T) {
+	for _, name := range []string{"__Schema", "__Type", "__TypeKind", "__Field", "__InputValue", "__EnumValue", "__Directive", "__DirectiveLocation"} {
+		if SystemType(name) == nil {
+			t.Errorf("expected type %s, got nil", name)
+		}
+	}
+	for _, name := range []string{"__Meta", "String", "Query"} {
+		if got := SystemType(name); got != nil {
+			t.Errorf("expected nil for %s, got %s", name, got.Name)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package introspection works with the GraphQL introspection system.
+//
+// https://spec.graphql.org/draft/#sec-Introspection
+package introspection
+
+import (
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+)
+
+// DefaultTypeDepth is depth of nested ofType selections used when
+// Options.TypeDepth is not set. It covers types like [[Int!]!]!.
+const DefaultTypeDepth = 9
+
+// Options tailor introspection query to features supported by server.
+// Older servers reject queries selecting fields they do not know.
+type Options struct {
+	Descriptions          bool // Select descriptions.
+	Deprecated            bool // Include deprecated elements and select deprecation fields.
+	SpecifiedByURL        bool // Select __Type.specifiedByURL.
+	DirectiveIsRepeatable bool // Select __Directive.isRepeatable.
+	SchemaDescription     bool // Select __Schema.description.
+	InputValueDeprecation bool // Include deprecated arguments and input fields.
+	OneOf                 bool // Select __Type.isOneOf.
+
+	// TypeDepth is number of nested ofType selections of type references.
+	TypeDepth int
+}
+
+// DefaultOptions select everything supported by servers implementing
+// October 2021 specification.
+var DefaultOptions = Options{
+	Descriptions:          true,
+	Deprecated:            true,
+	SpecifiedByURL:        true,
+	DirectiveIsRepeatable: true,
+	TypeDepth:             DefaultTypeDepth,
+}
+
+// Query returns introspection operation named IntrospectionQuery
+// selecting schema as configured by opts.
+func Query(opts Options) string {
+	depth := opts.TypeDepth
+	if depth <= 0 {
+		depth = DefaultTypeDepth
+	}
+	w := &queryWriter{}
+	deprecatedArg := ""
+	if opts.Deprecated {
+		deprecatedArg = "(includeDeprecated: true)"
+	}
+	inputDeprecatedArg := ""
+	if opts.InputValueDeprecation {
+		inputDeprecatedArg = "(includeDeprecated: true)"
+	}
+
+	w.open("query IntrospectionQuery")
+	w.open("__schema")
+	w.lineIf(opts.SchemaDescription, "description")
+	w.line("queryType { name }")
+	w.line("mutationType { name }")
+	w.line("subscriptionType { name }")
+	w.open("types")
+	w.line("...FullType")
+	w.close()
+	w.open("directives")
+	w.line("name")
+	w.lineIf(opts.Descriptions, "description")
+	w.lineIf(opts.DirectiveIsRepeatable, "isRepeatable")
+	w.line("locations")
+	w.open("args" + inputDeprecatedArg)
+	w.line("...InputValue")
+	w.close()
+	w.close()
+	w.close()
+	w.close()
+
+	w.open("fragment FullType on __Type")
+	w.line("kind")
+	w.line("name")
+	w.lineIf(opts.Descriptions, "description")
+	w.lineIf(opts.SpecifiedByURL, "specifiedByURL")
+	w.lineIf(opts.OneOf, "isOneOf")
+	w.open("fields" + deprecatedArg)
+	w.line("name")
+	w.lineIf(opts.Descriptions, "description")
+	w.open("args" + inputDeprecatedArg)
+	w.line("...InputValue")
+	w.close()
+	w.open("type")
+	w.line("...TypeRef")
+	w.close()
+	w.lineIf(opts.Deprecated, "isDeprecated")
+	w.lineIf(opts.Deprecated, "deprecationReason")
+	w.close()
+	w.open("inputFields" + inputDeprecatedArg)
+	w.line("...InputValue")
+	w.close()
+	w.open("interfaces")
+	w.line("...TypeRef")
+	w.close()
+	w.open("enumValues" + deprecatedArg)
+	w.line("name")
+	w.lineIf(opts.Descriptions, "description")
+	w.lineIf(opts.Deprecated, "isDeprecated")
+	w.lineIf(opts.Deprecated, "deprecationReason")
+	w.close()
+	w.open("possibleTypes")
+	w.line("...TypeRef")
+	w.close()
+	w.close()
+
+	w.open("fragment InputValue on __InputValue")
+	w.line("name")
+	w.lineIf(opts.Descriptions, "description")
+	w.open("type")
+	w.line("...TypeRef")
+	w.close()
+	w.line("defaultValue")
+	w.lineIf(opts.InputValueDeprecation, "isDeprecated")
+	w.lineIf(opts.InputValueDeprecation, "deprecationReason")
+	w.close()
+
+	w.open("fragment TypeRef on __Type")
+	for range depth {
+		w.line("kind")
+		w.line("name")
+		w.open("ofType")
+	}
+	w.line("kind")
+	w.line("name")
+	for range depth {
+		w.close()
+	}
+	w.close()
+	return w.String()
+}
+
+// QueryDocument returns parsed Query.
+func QueryDocument(opts Options) *ast.Document {
+	p, err := parser.New(lexer.New(Query(opts)))
+	if err != nil {
+		panic("introspection: " + err.Error())
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		panic("introspection: invalid generated query: " + err.Error())
+	}
+	return doc
+}
+
+// queryWriter writes indented selection sets.
+type queryWriter struct {
+	b      strings.Builder
+	indent int
+}
+
+func (w *queryWriter) line(s string) {
+	w.b.WriteString(strings.Repeat("  ", w.indent))
+	w.b.WriteString(s)
+	w.b.WriteByte('\n')
+}
+
+func (w *queryWriter) lineIf(cond bool, s string) {
+	if cond {
+		w.line(s)
+	}
+}
+
+func (w *queryWriter) open(s string) {
+	if w.indent == 0 && w.b.Len() > 0 {
+		w.b.WriteByte('\n')
+	}
+	w.line(s + " {")
+	w.indent++
+}
+
+func (w *queryWriter) close() {
+	w.indent--
+	w.line("}")
+}
+
+func (w *queryWriter) String() string {
+	return w.b.String()
+}
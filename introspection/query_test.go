@@ -0,0 +1,148 @@
+package introspection
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+func TestQuery_Default(t *testing.T) {
+	q := Query(Options{Descriptions: true, Deprecated: true, TypeDepth: 1})
+	expected := `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      ...FullType
+    }
+    directives {
+      name
+      description
+      locations
+      args {
+        ...InputValue
+      }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args {
+      ...InputValue
+    }
+    type {
+      ...TypeRef
+    }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields {
+    ...InputValue
+  }
+  interfaces {
+    ...TypeRef
+  }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes {
+    ...TypeRef
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type {
+    ...TypeRef
+  }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+  }
+}
+`
+	if q != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, q)
+	}
+}
+
+func TestQuery_Options(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "default",
+			opts:     DefaultOptions,
+			contains: []string{"description", "specifiedByURL", "isRepeatable", "fields(includeDeprecated: true)"},
+			excludes: []string{"isOneOf", "args(includeDeprecated: true)", "inputFields(includeDeprecated: true)"},
+		},
+		{
+			name:     "minimal",
+			opts:     Options{},
+			excludes: []string{"description", "specifiedByURL", "isRepeatable", "includeDeprecated", "isDeprecated"},
+		},
+		{
+			name: "all",
+			opts: Options{
+				Descriptions: true, Deprecated: true, SpecifiedByURL: true, DirectiveIsRepeatable: true,
+				SchemaDescription: true, InputValueDeprecation: true, OneOf: true,
+			},
+			contains: []string{"__schema {\n    description", "isOneOf", "args(includeDeprecated: true)", "inputFields(includeDeprecated: true)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := Query(tt.opts)
+			for _, s := range tt.contains {
+				if !strings.Contains(q, s) {
+					t.Errorf("expected query to contain %q", s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(q, s) {
+					t.Errorf("expected query not to contain %q", s)
+				}
+			}
+			QueryDocument(tt.opts)
+		})
+	}
+}
+
+func TestQueryDocument_TypeDepth(t *testing.T) {
+	for _, tt := range []struct{ depth, expected int }{{0, DefaultTypeDepth}, {3, 3}} {
+		doc := QueryDocument(Options{TypeDepth: tt.depth})
+		typeRef := doc.Definitions[len(doc.Definitions)-1].(*ast.FragmentDefinition)
+		depth := 0
+		for set := typeRef.SelectionSet; ; depth++ {
+			f := set.Selections[len(set.Selections)-1].(*ast.Field)
+			if f.Name.Value != "ofType" {
+				break
+			}
+			set = f.SelectionSet
+		}
+		if depth != tt.expected {
+			t.Errorf("depth %d: expected %d nested ofType, got %d", tt.depth, tt.expected, depth)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+)
+
+// Schema is the __schema field of introspection result, as selected by
+// Query. Fields not selected by the query are left zero.
+type Schema struct {
+	Description      string       `json:"description"`
+	QueryType        *TypeName    `json:"queryType"`
+	MutationType     *TypeName    `json:"mutationType"`
+	SubscriptionType *TypeName    `json:"subscriptionType"`
+	Types            []*Type      `json:"types"`
+	Directives       []*Directive `json:"directives"`
+}
+
+// TypeName refers to a named type.
+type TypeName struct {
+	Name string `json:"name"`
+}
+
+// Type is a named type.
+type Type struct {
+	Kind           string        `json:"kind"`
+	Name           string        `json:"name"`
+	Description    string        `json:"description"`
+	SpecifiedByURL string        `json:"specifiedByURL"`
+	IsOneOf        bool          `json:"isOneOf"`
+	Fields         []*Field      `json:"fields"`
+	InputFields    []*InputValue `json:"inputFields"`
+	Interfaces     []*TypeRef    `json:"interfaces"`
+	EnumValues     []*EnumValue  `json:"enumValues"`
+	PossibleTypes  []*TypeRef    `json:"possibleTypes"`
+}
+
+// TypeRef is a reference to a named, list or non-null type.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// Field of an object or interface type.
+type Field struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description"`
+	Args              []*InputValue `json:"args"`
+	Type              *TypeRef      `json:"type"`
+	IsDeprecated      bool          `json:"isDeprecated"`
+	DeprecationReason *string       `json:"deprecationReason"`
+}
+
+// InputValue is an argument or input field. DefaultValue is GraphQL
+// literal, e.g. `"x"` or `[1, 2]`.
+type InputValue struct {
+	Name              string   `json:"name"`
+	Description       string   `json:"description"`
+	Type              *TypeRef `json:"type"`
+	DefaultValue      *string  `json:"defaultValue"`
+	IsDeprecated      bool     `json:"isDeprecated"`
+	DeprecationReason *string  `json:"deprecationReason"`
+}
+
+// EnumValue is a value of enum type.
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+// Directive is a directive definition.
+type Directive struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	IsRepeatable bool          `json:"isRepeatable"`
+	Locations    []string      `json:"locations"`
+	Args         []*InputValue `json:"args"`
+}
+
+// defaultDeprecationReason is reason of @deprecated without arguments.
+const defaultDeprecationReason = "No longer supported"
+
+// builtInScalars and builtInDirectives are part of every schema and are
+// left out of SDL.
+var (
+	builtInScalars    = map[string]bool{"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true}
+	builtInDirectives = map[string]bool{"skip": true, "include": true, "deprecated": true, "specifiedBy": true, "oneOf": true}
+)
+
+// Document converts s to SDL document. Introspection types, built-in
+// scalars and built-in directives are omitted. Schema definition is only
+// added when root types do not have default names.
+func (s *Schema) Document() (*ast.Document, error) {
+	doc := &ast.Document{}
+	if def := s.schemaDefinition(); def != nil {
+		doc.Definitions = append(doc.Definitions, def)
+	}
+	for _, d := range s.Directives {
+		if builtInDirectives[d.Name] {
+			continue
+		}
+		def, err := directiveDefinition(d)
+		if err != nil {
+			return nil, err
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") || builtInScalars[t.Name] {
+			continue
+		}
+		def, err := typeDefinition(t)
+		if err != nil {
+			return nil, err
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+	return doc, nil
+}
+
+func (s *Schema) schemaDefinition() *ast.SchemaDefinition {
+	roots := []struct {
+		op   ast.OperationType
+		t    *TypeName
+		name string
+	}{
+		{ast.OperationTypeQuery, s.QueryType, "Query"},
+		{ast.OperationTypeMutation, s.MutationType, "Mutation"},
+		{ast.OperationTypeSubscription, s.SubscriptionType, "Subscription"},
+	}
+	def := &ast.SchemaDefinition{Description: description(s.Description)}
+	conventional := s.Description == ""
+	for _, r := range roots {
+		if r.t == nil {
+			continue
+		}
+		conventional = conventional && r.t.Name == r.name
+		def.RootOperationDefs = append(def.RootOperationDefs, &ast.RootOperationTypeDefinition{
+			OperationType: r.op,
+			Type:          namedType(r.t.Name),
+		})
+	}
+	if conventional {
+		return nil
+	}
+	return def
+}
+
+func typeDefinition(t *Type) (ast.Definition, error) {
+	var directives []*ast.Directive
+	if t.SpecifiedByURL != "" {
+		directives = append(directives, directive("specifiedBy", "url", t.SpecifiedByURL))
+	}
+	if t.IsOneOf {
+		directives = append(directives, directive("oneOf", "", ""))
+	}
+	switch t.Kind {
+	case "SCALAR":
+		return &ast.ScalarTypeDefinition{
+			Description: description(t.Description),
+			Name:        name(t.Name),
+			Directives:  directives,
+		}, nil
+	case "OBJECT", "INTERFACE":
+		interfaces, err := namedTypes(t.Interfaces)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", t.Name, err)
+		}
+		fields, err := fieldDefinitions(t.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", t.Name, err)
+		}
+		if t.Kind == "INTERFACE" {
+			return &ast.InterfaceTypeDefinition{
+				Description: description(t.Description),
+				Name:        name(t.Name),
+				Interfaces:  interfaces,
+				Directives:  directives,
+				Fields:      fields,
+			}, nil
+		}
+		return &ast.ObjectTypeDefinition{
+			Description: description(t.Description),
+			Name:        name(t.Name),
+			Interfaces:  interfaces,
+			Directives:  directives,
+			Fields:      fields,
+		}, nil
+	case "UNION":
+		types, err := namedTypes(t.PossibleTypes)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", t.Name, err)
+		}
+		return &ast.UnionTypeDefinition{
+			Description: description(t.Description),
+			Name:        name(t.Name),
+			Directives:  directives,
+			Types:       types,
+		}, nil
+	case "ENUM":
+		values := make([]*ast.EnumValueDefinition, len(t.EnumValues))
+		for i, v := range t.EnumValues {
+			values[i] = &ast.EnumValueDefinition{
+				Description: description(v.Description),
+				Name:        name(v.Name),
+				Directives:  deprecated(v.IsDeprecated, v.DeprecationReason),
+			}
+		}
+		return &ast.EnumTypeDefinition{
+			Description: description(t.Description),
+			Name:        name(t.Name),
+			Directives:  directives,
+			Values:      values,
+		}, nil
+	case "INPUT_OBJECT":
+		fields, err := inputValueDefinitions(t.InputFields)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", t.Name, err)
+		}
+		return &ast.InputObjectTypeDefinition{
+			Description: description(t.Description),
+			Name:        name(t.Name),
+			Directives:  directives,
+			Fields:      fields,
+		}, nil
+	}
+	return nil, fmt.Errorf("type %s: unknown kind %q", t.Name, t.Kind)
+}
+
+func directiveDefinition(d *Directive) (*ast.DirectiveDefinition, error) {
+	args, err := inputValueDefinitions(d.Args)
+	if err != nil {
+		return nil, fmt.Errorf("directive @%s: %w", d.Name, err)
+	}
+	locations := make([]*ast.Name, len(d.Locations))
+	for i, l := range d.Locations {
+		locations[i] = name(l)
+	}
+	return &ast.DirectiveDefinition{
+		Description: description(d.Description),
+		Name:        name(d.Name),
+		Arguments:   args,
+		Repeatable:  d.IsRepeatable,
+		Locations:   locations,
+	}, nil
+}
+
+func fieldDefinitions(fields []*Field) ([]*ast.FieldDefinition, error) {
+	defs := make([]*ast.FieldDefinition, len(fields))
+	for i, f := range fields {
+		args, err := inputValueDefinitions(f.Args)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		t, err := typeRef(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		defs[i] = &ast.FieldDefinition{
+			Description: description(f.Description),
+			Name:        name(f.Name),
+			Arguments:   args,
+			Type:        t,
+			Directives:  deprecated(f.IsDeprecated, f.DeprecationReason),
+		}
+	}
+	return defs, nil
+}
+
+func inputValueDefinitions(values []*InputValue) ([]*ast.InputValueDefinition, error) {
+	defs := make([]*ast.InputValueDefinition, len(values))
+	for i, v := range values {
+		t, err := typeRef(v.Type)
+		if err != nil {
+			return nil, fmt.Errorf("input value %s: %w", v.Name, err)
+		}
+		var defaultValue ast.Value
+		if v.DefaultValue != nil {
+			if defaultValue, err = parseValue(*v.DefaultValue); err != nil {
+				return nil, fmt.Errorf("input value %s: default value: %w", v.Name, err)
+			}
+		}
+		defs[i] = &ast.InputValueDefinition{
+			Description:  description(v.Description),
+			Name:         name(v.Name),
+			Type:         t,
+			DefaultValue: defaultValue,
+			Directives:   deprecated(v.IsDeprecated, v.DeprecationReason),
+		}
+	}
+	return defs, nil
+}
+
+func typeRef(ref *TypeRef) (ast.Type, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("missing type reference")
+	}
+	switch ref.Kind {
+	case "LIST":
+		t, err := typeRef(ref.OfType)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ListType{Type: t}, nil
+	case "NON_NULL":
+		t, err := typeRef(ref.OfType)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := t.(*ast.NonNullType); ok {
+			return nil, fmt.Errorf("non-null of non-null type")
+		}
+		return &ast.NonNullType{Type: t}, nil
+	}
+	if ref.Name == "" {
+		return nil, fmt.Errorf("type reference of kind %q has no name; increase Options.TypeDepth", ref.Kind)
+	}
+	return namedType(ref.Name), nil
+}
+
+func namedTypes(refs []*TypeRef) ([]*ast.NamedType, error) {
+	types := make([]*ast.NamedType, len(refs))
+	for i, ref := range refs {
+		if ref == nil || ref.Name == "" {
+			return nil, fmt.Errorf("missing named type")
+		}
+		types[i] = namedType(ref.Name)
+	}
+	return types, nil
+}
+
+// parseValue parses GraphQL literal by parsing it as argument of a field.
+func parseValue(literal string) (ast.Value, error) {
+	p, err := parser.New(lexer.New("{ f(v: " + literal + ") }"))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := doc.Definitions[0].(*ast.OperationDefinition)
+	if len(doc.Definitions) != 1 || !ok || len(op.SelectionSet.Selections) != 1 {
+		return nil, fmt.Errorf("invalid literal %q", literal)
+	}
+	f, ok := op.SelectionSet.Selections[0].(*ast.Field)
+	if !ok || len(f.Arguments) != 1 {
+		return nil, fmt.Errorf("invalid literal %q", literal)
+	}
+	return f.Arguments[0].Value, nil
+}
+
+func deprecated(isDeprecated bool, reason *string) []*ast.Directive {
+	if !isDeprecated {
+		return nil
+	}
+	if reason == nil || *reason == defaultDeprecationReason {
+		return []*ast.Directive{directive("deprecated", "", "")}
+	}
+	return []*ast.Directive{directive("deprecated", "reason", *reason)}
+}
+
+// directive returns directive with optional string argument.
+func directive(directiveName, argName, value string) *ast.Directive {
+	d := &ast.Directive{Name: name(directiveName)}
+	if argName != "" {
+		d.Arguments = []*ast.Argument{{Name: name(argName), Value: &ast.StringValue{Value: value}}}
+	}
+	return d
+}
+
+func description(s string) *ast.Description {
+	if s == "" {
+		return nil
+	}
+	return &ast.Description{Value: s, Block: true}
+}
+
+func name(value string) *ast.Name {
+	return &ast.Name{Value: value}
+}
+
+func namedType(value string) *ast.NamedType {
+	return &ast.NamedType{Name: name(value)}
+}
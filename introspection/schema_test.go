@@ -0,0 +1,136 @@
+package introspection
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// testResult is __schema of a small schema as returned by servers.
+const testResult = `{
+  "queryType": {"name": "Root"},
+  "mutationType": null,
+  "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Root", "description": "Entry point.", "fields": [
+      {"name": "node", "description": null, "args": [
+        {"name": "id", "description": null, "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "ID", "ofType": null}}, "defaultValue": null}
+      ], "type": {"kind": "INTERFACE", "name": "Node", "ofType": null}, "isDeprecated": false, "deprecationReason": null},
+      {"name": "search", "description": null, "args": [
+        {"name": "in", "description": null, "type": {"kind": "INPUT_OBJECT", "name": "In", "ofType": null}, "defaultValue": "{limit: 10}"}
+      ], "type": {"kind": "LIST", "name": null, "ofType": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "UNION", "name": "Result", "ofType": null}}}, "isDeprecated": true, "deprecationReason": "Use node."}
+    ], "inputFields": null, "interfaces": [], "enumValues": null, "possibleTypes": null},
+    {"kind": "INTERFACE", "name": "Node", "fields": [
+      {"name": "id", "args": [], "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "ID", "ofType": null}}, "isDeprecated": false}
+    ], "interfaces": [], "possibleTypes": [{"kind": "OBJECT", "name": "User", "ofType": null}]},
+    {"kind": "OBJECT", "name": "User", "fields": [
+      {"name": "id", "args": [], "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "ID", "ofType": null}}, "isDeprecated": false},
+      {"name": "role", "args": [], "type": {"kind": "ENUM", "name": "Role", "ofType": null}, "isDeprecated": false}
+    ], "interfaces": [{"kind": "INTERFACE", "name": "Node", "ofType": null}]},
+    {"kind": "UNION", "name": "Result", "possibleTypes": [{"kind": "OBJECT", "name": "User", "ofType": null}]},
+    {"kind": "ENUM", "name": "Role", "enumValues": [
+      {"name": "ADMIN", "isDeprecated": false, "deprecationReason": null},
+      {"name": "GUEST", "isDeprecated": true, "deprecationReason": "No longer supported"}
+    ]},
+    {"kind": "INPUT_OBJECT", "name": "In", "isOneOf": false, "inputFields": [
+      {"name": "limit", "type": {"kind": "SCALAR", "name": "Int", "ofType": null}, "defaultValue": null}
+    ]},
+    {"kind": "SCALAR", "name": "URL", "specifiedByURL": "https://url.spec.whatwg.org/"},
+    {"kind": "SCALAR", "name": "ID"},
+    {"kind": "SCALAR", "name": "Int"},
+    {"kind": "OBJECT", "name": "__Schema", "fields": []}
+  ],
+  "directives": [
+    {"name": "cached", "description": "Cache hint.", "isRepeatable": true, "locations": ["FIELD", "QUERY"], "args": [
+      {"name": "ttl", "type": {"kind": "SCALAR", "name": "Int", "ofType": null}, "defaultValue": "60"}
+    ]},
+    {"name": "skip", "locations": ["FIELD"], "args": []}
+  ]
+}`
+
+func TestSchema_Document(t *testing.T) {
+	var s Schema
+	if err := json.Unmarshal([]byte(testResult), &s); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := s.Document()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `schema {
+  query: Root
+}
+
+"""Cache hint."""
+directive @cached(ttl: Int = 60) repeatable on FIELD | QUERY
+
+"""Entry point."""
+type Root {
+  node(id: ID!): Node
+  search(in: In = {limit: 10}): [Result!] @deprecated(reason: "Use node.")
+}
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  role: Role
+}
+
+union Result = User
+
+enum Role {
+  ADMIN
+  GUEST @deprecated
+}
+
+input In {
+  limit: Int
+}
+
+scalar URL @specifiedBy(url: "https://url.spec.whatwg.org/")
+`
+	if got := printer.PrintDocument(doc); strings.TrimSpace(got) != strings.TrimSpace(expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestSchema_DocumentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"Unknown kind",
+			`{"types": [{"kind": "THING", "name": "X"}]}`,
+			`type X: unknown kind "THING"`,
+		},
+		{
+			"Truncated type reference",
+			`{"types": [{"kind": "OBJECT", "name": "X", "fields": [{"name": "f", "type": {"kind": "LIST", "name": null, "ofType": null}}]}]}`,
+			"type X: field f: missing type reference",
+		},
+		{
+			"Invalid default value",
+			`{"directives": [{"name": "d", "locations": ["FIELD"], "args": [{"name": "a", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "1)"}]}]}`,
+			"directive @d: input value a: default value:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Schema
+			if err := json.Unmarshal([]byte(tt.input), &s); err != nil {
+				t.Fatal(err)
+			}
+			_, err := s.Document()
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package introspection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// Unmarshal decodes introspection result in any of the forms it is
+// commonly stored in: a complete response {"data": {"__schema": ...}}, its
+// data {"__schema": ...}, or the __schema object itself. Errors of a
+// response without data are returned as error.
+func Unmarshal(data []byte) (*Schema, error) {
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+		Schema    json.RawMessage `json:"__schema"`
+		Types     json.RawMessage `json:"types"`
+		QueryType json.RawMessage `json:"queryType"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("introspection: decode result: %w", err)
+	}
+
+	raw := envelope.Schema
+	switch {
+	case !isNull(envelope.Data):
+		var result struct {
+			Schema json.RawMessage `json:"__schema"`
+		}
+		if err := json.Unmarshal(envelope.Data, &result); err != nil {
+			return nil, fmt.Errorf("introspection: decode result: %w", err)
+		}
+		raw = result.Schema
+	case len(envelope.Errors) > 0:
+		messages := make([]string, len(envelope.Errors))
+		for i, e := range envelope.Errors {
+			messages[i] = e.Message
+		}
+		return nil, errors.New("introspection: query failed: " + strings.Join(messages, "; "))
+	case envelope.Types != nil || envelope.QueryType != nil:
+		raw = data
+	}
+	if isNull(raw) {
+		return nil, errors.New("introspection: result has no __schema")
+	}
+
+	var s Schema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("introspection: decode __schema: %w", err)
+	}
+	return &s, nil
+}
+
+func isNull(data json.RawMessage) bool {
+	return len(data) == 0 || bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// SDL returns s printed as SDL. See Document.
+func (s *Schema) SDL() (string, error) {
+	doc, err := s.Document()
+	if err != nil {
+		return "", err
+	}
+	return printer.PrintDocument(doc), nil
+}
+
+// SDL converts introspection result, in any form accepted by Unmarshal,
+// to SDL.
+func SDL(data []byte) (string, error) {
+	s, err := Unmarshal(data)
+	if err != nil {
+		return "", err
+	}
+	return s.SDL()
+}
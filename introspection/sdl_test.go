@@ -0,0 +1,70 @@
+package introspection
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Schema", testResult},
+		{"Data", `{"__schema": ` + testResult + `}`},
+		{"Response", `{"data": {"__schema": ` + testResult + `}, "extensions": {}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Unmarshal([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.QueryType == nil || s.QueryType.Name != "Root" || len(s.Types) != 10 || len(s.Directives) != 2 {
+				t.Errorf("unexpected schema %+v", s)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Invalid JSON", `{"data": `, "introspection: decode result: unexpected end of JSON input"},
+		{"Errors", `{"data": null, "errors": [{"message": "forbidden"}, {"message": "try later"}]}`, "introspection: query failed: forbidden; try later"},
+		{"No schema", `{"data": {"user": null}}`, "introspection: result has no __schema"},
+		{"Unknown object", `{"hello": "world"}`, "introspection: result has no __schema"},
+		{"Invalid schema", `{"__schema": {"types": 1}}`, "introspection: decode __schema: json: cannot unmarshal number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(tt.input))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestSDL(t *testing.T) {
+	sdl, err := SDL([]byte(`{"data": {"__schema": {
+  "queryType": {"name": "Query"},
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "hello", "args": [], "type": {"kind": "SCALAR", "name": "String", "ofType": null}, "isDeprecated": false}
+    ], "interfaces": []},
+    {"kind": "SCALAR", "name": "String"}
+  ],
+  "directives": []
+}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "type Query {\n  hello: String\n}"
+	if sdl != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, sdl)
+	}
+}
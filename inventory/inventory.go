@@ -0,0 +1,261 @@
+// Package inventory flattens schemas into tabular records for governance
+// audits in spreadsheets and BI tools.
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+)
+
+// Kinds of records.
+const (
+	KindScalar            = "SCALAR"
+	KindObject            = "OBJECT"
+	KindInterface         = "INTERFACE"
+	KindUnion             = "UNION"
+	KindEnum              = "ENUM"
+	KindInputObject       = "INPUT_OBJECT"
+	KindField             = "FIELD"
+	KindArgument          = "ARGUMENT"
+	KindEnumValue         = "ENUM_VALUE"
+	KindInputField        = "INPUT_FIELD"
+	KindDirective         = "DIRECTIVE"
+	KindDirectiveArgument = "DIRECTIVE_ARGUMENT"
+)
+
+// defaultDeprecationReason is default value of @deprecated reason.
+const defaultDeprecationReason = "No longer supported"
+
+// Record describes one schema element.
+type Record struct {
+	Kind              string `json:"kind"`
+	Coordinate        string `json:"coordinate"`
+	Type              string `json:"type"`               // Type or directive ("@name") the element belongs to.
+	Field             string `json:"field,omitempty"`    // Field, enum value or input field name.
+	Argument          string `json:"argument,omitempty"` // Argument name.
+	TypeRef           string `json:"typeRef,omitempty"`  // Type reference of fields and arguments, e.g. "[ID!]!".
+	Deprecated        bool   `json:"deprecated"`
+	DeprecationReason string `json:"deprecationReason,omitempty"`
+	Description       string `json:"description,omitempty"`
+	Directives        string `json:"directives,omitempty"` // Applied directives, e.g. `@tag(name: "public")`.
+}
+
+// Header is CSV header matching Record fields.
+var Header = []string{
+	"kind", "coordinate", "type", "field", "argument", "type_ref",
+	"deprecated", "deprecation_reason", "description", "directives",
+}
+
+func (r Record) row() []string {
+	return []string{
+		r.Kind, r.Coordinate, r.Type, r.Field, r.Argument, r.TypeRef,
+		strconv.FormatBool(r.Deprecated), r.DeprecationReason, r.Description, r.Directives,
+	}
+}
+
+// Records returns records of every type, field, argument, enum value,
+// input field and directive definition of schema in document order.
+// Members and directives of type extensions are attributed to the
+// extended type.
+func Records(schema *ast.Document) []Record {
+	var records []Record
+	types := make(map[string]int)
+	coordinate.Walk(schema, func(c coordinate.Coordinate, node ast.Node) bool {
+		r := Record{Coordinate: c.String(), Type: c.Name, Field: c.Member, Argument: c.Argument}
+		if c.Directive {
+			r.Type = "@" + c.Name
+		}
+		var directives []*ast.Directive
+		switch n := node.(type) {
+		case *ast.ScalarTypeDefinition:
+			r.Kind, directives = KindScalar, n.Directives
+		case *ast.ObjectTypeDefinition:
+			r.Kind, directives = KindObject, n.Directives
+		case *ast.InterfaceTypeDefinition:
+			r.Kind, directives = KindInterface, n.Directives
+		case *ast.UnionTypeDefinition:
+			r.Kind, directives = KindUnion, n.Directives
+		case *ast.EnumTypeDefinition:
+			r.Kind, directives = KindEnum, n.Directives
+		case *ast.InputObjectTypeDefinition:
+			r.Kind, directives = KindInputObject, n.Directives
+		case *ast.DirectiveDefinition:
+			r.Kind = KindDirective
+		case *ast.FieldDefinition:
+			r.Kind, directives = KindField, n.Directives
+			r.TypeRef = typeRef(n.Type)
+		case *ast.EnumValueDefinition:
+			r.Kind, directives = KindEnumValue, n.Directives
+		case *ast.InputValueDefinition:
+			switch {
+			case c.Directive:
+				r.Kind = KindDirectiveArgument
+			case c.Argument != "":
+				r.Kind = KindArgument
+			default:
+				r.Kind = KindInputField
+			}
+			directives = n.Directives
+			r.TypeRef = typeRef(n.Type)
+		}
+		if d, ok := node.(ast.Describable); ok && d.GetDescription() != nil {
+			r.Description = d.GetDescription().Value
+		}
+		r.setDirectives(directives)
+		if c.Member == "" && !c.Directive {
+			types[c.Name] = len(records)
+		}
+		records = append(records, r)
+		return true
+	})
+
+	for _, def := range schema.Definitions {
+		name, directives := extension(def)
+		if i, ok := types[name]; ok && len(directives) > 0 {
+			records[i].setDirectives(directives)
+		}
+	}
+	return records
+}
+
+// setDirectives appends directives to r, recording deprecation.
+func (r *Record) setDirectives(directives []*ast.Directive) {
+	var b strings.Builder
+	b.WriteString(r.Directives)
+	for _, d := range directives {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		writeDirective(&b, d)
+		if d.Name.Value == "deprecated" {
+			r.Deprecated = true
+			r.DeprecationReason = defaultDeprecationReason
+			for _, arg := range d.Arguments {
+				if s, ok := arg.Value.(*ast.StringValue); ok && arg.Name.Value == "reason" {
+					r.DeprecationReason = s.Value
+				}
+			}
+		}
+	}
+	r.Directives = b.String()
+}
+
+func extension(def ast.Definition) (string, []*ast.Directive) {
+	switch d := def.(type) {
+	case *ast.ScalarTypeExtension:
+		return d.Name.Value, d.Directives
+	case *ast.ObjectTypeExtension:
+		return d.Name.Value, d.Directives
+	case *ast.InterfaceTypeExtension:
+		return d.Name.Value, d.Directives
+	case *ast.UnionTypeExtension:
+		return d.Name.Value, d.Directives
+	case *ast.EnumTypeExtension:
+		return d.Name.Value, d.Directives
+	case *ast.InputObjectTypeExtension:
+		return d.Name.Value, d.Directives
+	}
+	return "", nil
+}
+
+// WriteCSV writes records as CSV with Header.
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Header); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := cw.Write(r.row()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONLines writes records as JSON objects, one per line.
+func WriteJSONLines(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func typeRef(t ast.Type) string {
+	switch t := t.(type) {
+	case *ast.NamedType:
+		return t.Name.Value
+	case *ast.ListType:
+		return "[" + typeRef(t.Type) + "]"
+	case *ast.NonNullType:
+		return typeRef(t.Type) + "!"
+	}
+	return ""
+}
+
+func writeDirective(b *strings.Builder, d *ast.Directive) {
+	b.WriteByte('@')
+	b.WriteString(d.Name.Value)
+	if len(d.Arguments) == 0 {
+		return
+	}
+	b.WriteByte('(')
+	for i, arg := range d.Arguments {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(arg.Name.Value)
+		b.WriteString(": ")
+		writeValue(b, arg.Value)
+	}
+	b.WriteByte(')')
+}
+
+func writeValue(b *strings.Builder, v ast.Value) {
+	switch v := v.(type) {
+	case *ast.Variable:
+		b.WriteByte('$')
+		b.WriteString(v.Name.Value)
+	case *ast.IntValue:
+		b.WriteString(v.Value)
+	case *ast.FloatValue:
+		b.WriteString(v.Value)
+	case *ast.StringValue:
+		b.WriteString(strconv.Quote(v.Value))
+	case *ast.BooleanValue:
+		b.WriteString(strconv.FormatBool(v.Value))
+	case *ast.NullValue:
+		b.WriteString("null")
+	case *ast.EnumValue:
+		b.WriteString(v.Value)
+	case *ast.ListValue:
+		b.WriteByte('[')
+		for i, item := range v.Values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeValue(b, item)
+		}
+		b.WriteByte(']')
+	case *ast.ObjectValue:
+		b.WriteByte('{')
+		for i, f := range v.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(f.Name.Value)
+			b.WriteString(": ")
+			writeValue(b, f.Value)
+		}
+		b.WriteByte('}')
+	}
+}
@@ -0,0 +1,85 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+const schema = `
+"A user"
+type User @key(fields: "id") {
+  id: ID!
+  "Display name"
+  name(format: Format = SHORT): String @deprecated(reason: "use displayName")
+}
+extend type User @tag(name: "public") { friends: [User!]! @deprecated }
+enum Format { SHORT LONG @deprecated }
+input Filter { ids: [ID!] }
+directive @tag(name: String!) repeatable on OBJECT
+`
+
+func TestRecords(t *testing.T) {
+	records := Records(asttest.Parse(t, schema))
+
+	var got []string
+	for _, r := range records {
+		got = append(got, strings.Join(r.row(), "|"))
+	}
+	expected := []string{
+		`OBJECT|User|User||||false||A user|@key(fields: "id") @tag(name: "public")`,
+		`FIELD|User.id|User|id||ID!|false|||`,
+		`FIELD|User.name|User|name||String|true|use displayName|Display name|@deprecated(reason: "use displayName")`,
+		`ARGUMENT|User.name(format:)|User|name|format|Format|false|||`,
+		`FIELD|User.friends|User|friends||[User!]!|true|No longer supported||@deprecated`,
+		`ENUM|Format|Format||||false|||`,
+		`ENUM_VALUE|Format.SHORT|Format|SHORT|||false|||`,
+		`ENUM_VALUE|Format.LONG|Format|LONG|||true|No longer supported||@deprecated`,
+		`INPUT_OBJECT|Filter|Filter||||false|||`,
+		`INPUT_FIELD|Filter.ids|Filter|ids||[ID!]|false|||`,
+		`DIRECTIVE|@tag|@tag||||false|||`,
+		`DIRECTIVE_ARGUMENT|@tag(name:)|@tag||name|String!|false|||`,
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d records, got %d:\n%s", len(expected), len(got), strings.Join(got, "\n"))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("record %d:\nexpected %s\ngot      %s", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, Records(asttest.Parse(t, `type Q { "a, \"b\"" f: Int }`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "kind,coordinate,type,field,argument,type_ref,deprecated,deprecation_reason,description,directives\n" +
+		"OBJECT,Q,Q,,,,false,,,\n" +
+		"FIELD,Q.f,Q,f,,Int,false,,\"a, \"\"b\"\"\",\n"
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSONLines(&buf, Records(asttest.Parse(t, schema))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 12 {
+		t.Fatalf("expected 12 lines, got %d", len(lines))
+	}
+	var r Record
+	if err := json.Unmarshal([]byte(lines[2]), &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Coordinate != "User.name" || !r.Deprecated || r.Description != "Display name" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+}
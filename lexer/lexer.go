@@ -4,41 +4,128 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gqlhub/gqlhub-core/token"
+	"io"
 	"strings"
 	"unicode/utf8"
 )
 
 type Lexer struct {
-	input string
-	ch    rune // current char
+	input string // Whole source, or window of it when reading from r.
+	ch    rune   // current char
 	cursor
 	savedCursor cursor
+
+	// Source read by NewReader, nil once exhausted. Input is then a window
+	// starting at offset base of the source, which drops consumed tokens.
+	r       io.Reader
+	buf     []byte
+	base    int
+	readErr error
+
+	utf8Mode   UTF8Mode
+	invalidAt  int   // Offset of the first invalid UTF-8 sequence read in UTF8Strict mode or -1.
+	invalidErr error // Error reporting sequence at invalidAt.
 }
 
 type cursor struct {
-	offset   int // Current position in input (points to current char)
-	rdOffset int // Current reading position in input (after current char)
+	offset   int // Current position in input (points to current char), relative to base
+	rdOffset int // Current reading position in input (after current char), relative to base
 	line     int // Current line number
 	column   int // Current column number
 }
 
 const (
 	eof = -1
+
+	// lookahead is number of bytes kept buffered after the current char,
+	// enough for peekCharAt.
+	lookahead = 16
+	// chunkSize is minimum size of reads from io.Reader.
+	chunkSize = 64 << 10
 )
 
-func New(input string) *Lexer {
-	l := &Lexer{
-		input: input,
-		cursor: cursor{
-			line: 1,
-		},
+func New(input string, opts ...Option) *Lexer {
+	l := &Lexer{}
+	for _, opt := range opts {
+		opt(l)
 	}
+	l.Reset(input)
+	return l
+}
 
-	l.readChar()
+// NewReader returns lexer reading source from r as tokens are requested.
+// Only the current token and a small lookahead are buffered, so large
+// documents, e.g. supergraph schemas, can be tokenized without loading
+// them into memory. Read errors are returned by NextToken.
+func NewReader(r io.Reader, opts ...Option) *Lexer {
+	l := &Lexer{}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.ResetReader(r)
 	return l
 }
 
+// Reset makes lexer read input from the beginning, keeping its options,
+// so that lexers can be pooled.
+func (l *Lexer) Reset(input string) {
+	*l = Lexer{
+		input:     input,
+		cursor:    cursor{line: 1},
+		utf8Mode:  l.utf8Mode,
+		invalidAt: -1,
+	}
+	l.readChar()
+}
+
+// ResetReader is like Reset but reads source from r, as NewReader does.
+// The read buffer is reused.
+func (l *Lexer) ResetReader(r io.Reader) {
+	*l = Lexer{
+		r:         r,
+		buf:       l.buf,
+		cursor:    cursor{line: 1},
+		utf8Mode:  l.utf8Mode,
+		invalidAt: -1,
+	}
+	l.readChar()
+}
+
+// fill reads from r until lookahead bytes after the current char are
+// buffered or r is exhausted. Reads grow with the window, so that long
+// tokens are read in linear time.
+func (l *Lexer) fill() {
+	for l.r != nil && len(l.input)-l.rdOffset < lookahead {
+		size := max(chunkSize, len(l.input))
+		if cap(l.buf) < size {
+			l.buf = make([]byte, size)
+		}
+		n, err := l.r.Read(l.buf[:size])
+		l.input += string(l.buf[:n])
+		if err != nil {
+			if err != io.EOF {
+				l.readErr = err
+			}
+			l.r = nil
+		}
+	}
+}
+
+// discard drops consumed input before the current char from the window
+// of a reader source. Whole string inputs are kept, as they are in memory
+// anyway.
+func (l *Lexer) discard() {
+	if l.offset == 0 || (l.r == nil && l.base == 0) {
+		return
+	}
+	l.input = l.input[l.offset:]
+	l.base += l.offset
+	l.rdOffset -= l.offset
+	l.offset = 0
+}
+
 func (l *Lexer) readChar() {
+	l.fill()
 	if l.ch == '\r' {
 		l.line++
 		l.column = 0
@@ -50,6 +137,7 @@ func (l *Lexer) readChar() {
 		l.column = 0
 	}
 
+	invalid := false
 	if l.rdOffset < len(l.input) {
 		l.offset = l.rdOffset
 		b := l.input[l.rdOffset]
@@ -60,20 +148,45 @@ func (l *Lexer) readChar() {
 			var size int
 			l.ch, size = utf8.DecodeRuneInString(l.input[l.rdOffset:])
 			l.rdOffset += size
+			invalid = l.ch == utf8.RuneError && size == 1
 		}
 	} else {
 		l.ch = eof
 		l.offset = len(l.input)
 	}
 	l.column++
+	if invalid && l.utf8Mode == UTF8Strict && l.invalidAt < 0 {
+		l.invalidAt = l.base + l.offset
+		l.invalidErr = &LexError{
+			Line:   l.line,
+			Column: l.column,
+			Err:    fmt.Errorf("invalid UTF-8 byte 0x%02X", l.input[l.offset]),
+		}
+	}
+}
+
+func (l *Lexer) NextToken() (token.Token, error) {
+	tok, err := l.nextToken()
+	if l.readErr != nil {
+		// Input read so far may be truncated in the middle of a token.
+		return token.Token{}, fmt.Errorf("read input: %w", l.readErr)
+	}
+	// Invalid sequence belongs to the token when the lexer either passed
+	// it or stopped at it with an error.
+	offset := l.base + l.offset
+	if l.invalidAt >= 0 && tok.Start <= l.invalidAt && (offset > l.invalidAt || (err != nil && offset == l.invalidAt)) {
+		return token.Token{}, l.invalidErr
+	}
+	return tok, err
 }
 
-func (l *Lexer) NextToken() (tok token.Token, err error) {
+func (l *Lexer) nextToken() (tok token.Token, err error) {
 	l.skipInsignificantChars()
+	l.discard()
 
 	l.savedCursor = cursor{}
 
-	tok.Start = l.offset
+	tok.Start = l.base + l.offset
 
 	switch {
 	case isNameStart(l.ch):
@@ -164,7 +277,7 @@ func (l *Lexer) NextToken() (tok token.Token, err error) {
 			return
 		}
 	}
-	tok.End = l.offset
+	tok.End = l.base + l.offset
 	return
 }
 
@@ -274,7 +387,11 @@ func (l *Lexer) readComment() string {
 	for !isLineTerminator(l.ch) && l.ch != eof {
 		l.readChar()
 	}
-	return l.input[start:l.offset]
+	comment := l.input[start:l.offset]
+	if !utf8.ValidString(comment) {
+		return strings.ToValidUTF8(comment, string(utf8.RuneError))
+	}
+	return comment
 }
 
 func (l *Lexer) skipInsignificantChars() {
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gqlhub/gqlhub-core/token"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNextToken_Punctuator(t *testing.T) {
@@ -374,6 +377,95 @@ func TestNextToken_UnknownCharacters(t *testing.T) {
 	})
 }
 
+func TestNextToken_InvalidUTF8Strict(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr error
+	}{
+		{"Outside of token", "a \xff", &LexError{Line: 1, Column: 3, Err: errors.New("invalid UTF-8 byte 0xFF")}},
+		{"Encoded surrogate", "\xed\xa0\x80", &LexError{Line: 1, Column: 1, Err: errors.New("invalid UTF-8 byte 0xED")}},
+		{"In string", "\"héllo \xc3\"", &LexError{Line: 1, Column: 8, Err: errors.New("invalid UTF-8 byte 0xC3")}},
+		{"In unterminated string", "\"a\xff", &LexError{Line: 1, Column: 3, Err: errors.New("invalid UTF-8 byte 0xFF")}},
+		{"In block string", "\"\"\"\r\n\n  \x80\"\"\"", &LexError{Line: 3, Column: 3, Err: errors.New("invalid UTF-8 byte 0x80")}},
+		{"In comment", "# \xfe", &LexError{Line: 1, Column: 3, Err: errors.New("invalid UTF-8 byte 0xFE")}},
+		{"After number", "12\xff", &LexError{Line: 1, Column: 3, Err: errors.New("invalid UTF-8 byte 0xFF")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.input, WithUTF8Mode(UTF8Strict))
+			var err error
+			for err == nil {
+				var tok token.Token
+				if tok, err = l.NextToken(); tok.Type == token.EOF && err == nil {
+					t.Fatalf("expected error")
+				}
+			}
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestNextToken_ValidUTF8Strict(t *testing.T) {
+	l := New("a # é\n\"😀\"", WithUTF8Mode(UTF8Strict))
+	for {
+		tok, err := l.NextToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+}
+
+func TestNextToken_InvalidUTF8Replace(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"\"a\xffb\"", "a\uFFFDb"},
+		{"\"\"\"a\xffb\"\"\"", "a\uFFFDb"},
+		{"#a\xffb", "a\uFFFDb"},
+	}
+	for _, tt := range tests {
+		tok, err := New(tt.input).NextToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tok.Literal != tt.expected {
+			t.Errorf("%q: expected literal %q, got %q", tt.input, tt.expected, tok.Literal)
+		}
+	}
+	if _, err := New("\xff").NextToken(); err == nil {
+		t.Errorf("expected error for invalid byte outside of literal")
+	}
+}
+
+func TestReset(t *testing.T) {
+	l := New("a\nb", WithUTF8Mode(UTF8Strict))
+	for {
+		tok, err := l.NextToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	l.Reset("c \xff")
+	tok, err := l.NextToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (token.Token{Type: token.NAME, Literal: "c", Start: 0, End: 1}); tok != expected {
+		t.Errorf("expected %v, got %v", expected, tok)
+	}
+	_, err = l.NextToken()
+	assertError(t, err, &LexError{Line: 1, Column: 3, Err: errors.New("invalid UTF-8 byte 0xFF")})
+}
+
 func TestNextToken_Query(t *testing.T) {
 	input := `query {
            user(id: 123) {
@@ -430,6 +522,72 @@ func TestNextToken_Query(t *testing.T) {
 	}
 }
 
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+	}{
+		{"Query", "query Q($id: ID = 100) {\r\n  user(id: $id) { ...F @include(if: true) } # comment\n}", nil},
+		{"Strings", "\"a\\u00e9\\\"b\" \"\"\"\n  block\n  \\\"\"\" é\n\"\"\" \"😀\"", nil},
+		{"Numbers", "0 -12 3.5e-2 1E3", nil},
+		{"Lex error", "a 01", nil},
+		{"Unterminated block string", `"""abc`, nil},
+		{"Invalid UTF-8 replaced", "\"a\xffb\" # \xfe", nil},
+		{"Invalid UTF-8 strict", "a\r\n\"\"\"\n\xed\xa0\x80\"\"\"", []Option{WithUTF8Mode(UTF8Strict)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := New(tt.input, tt.opts...)
+			// Byte-by-byte reads split runes and tokens across reads.
+			l := NewReader(iotest.OneByteReader(strings.NewReader(tt.input)), tt.opts...)
+			for {
+				expectedTok, expectedErr := expected.NextToken()
+				tok, err := l.NextToken()
+				assertError(t, err, expectedErr)
+				assertToken(t, tok, expectedTok)
+				if err != nil || tok.Type == token.EOF {
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNewReader_Window(t *testing.T) {
+	input := strings.Repeat("field(arg: \"value\") { sub }\n", 1<<16)
+	l := NewReader(strings.NewReader(input))
+	count := 0
+	for {
+		tok, err := l.NextToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(l.input) > 2*chunkSize {
+			t.Fatalf("expected window of at most %d bytes, got %d", 2*chunkSize, len(l.input))
+		}
+		if tok.Type == token.EOF {
+			if tok.Start != len(input) {
+				t.Errorf("expected EOF at %d, got %d", len(input), tok.Start)
+			}
+			break
+		}
+		count++
+	}
+	if expected := 9 << 16; count != expected {
+		t.Errorf("expected %d tokens, got %d", expected, count)
+	}
+}
+
+func TestNewReader_ReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	l := NewReader(io.MultiReader(strings.NewReader("a b"), iotest.ErrReader(readErr)))
+	_, err := l.NextToken()
+	if !errors.Is(err, readErr) {
+		t.Errorf("expected read error, got %v", err)
+	}
+}
+
 func assertToken(t *testing.T, actual, expected token.Token) {
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Tokens do not match.\nWant: %+v\nGot:      %+v", expected, actual)
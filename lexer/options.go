@@ -0,0 +1,27 @@
+package lexer
+
+// Option configures Lexer.
+type Option func(*Lexer)
+
+// UTF8Mode controls handling of byte sequences which are not valid UTF-8,
+// including encoded surrogate halves.
+type UTF8Mode int
+
+const (
+	// UTF8Replace decodes invalid byte sequences as U+FFFD replacement
+	// character. Within strings, block strings and comments the
+	// replacement character becomes part of the literal, elsewhere it is
+	// reported as unexpected character.
+	UTF8Replace UTF8Mode = iota
+	// UTF8Strict rejects source containing invalid byte sequences,
+	// reporting position of the first one when the token containing it is
+	// read. Use it for untrusted input.
+	UTF8Strict
+)
+
+// WithUTF8Mode sets handling of invalid UTF-8. Default is UTF8Replace.
+func WithUTF8Mode(mode UTF8Mode) Option {
+	return func(l *Lexer) {
+		l.utf8Mode = mode
+	}
+}
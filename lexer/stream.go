@@ -0,0 +1,127 @@
+package lexer
+
+import (
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+// TokenStream reads tokens of a Lexer with arbitrary lookahead and
+// backtracking, for parsers of GraphQL-adjacent languages. Comments are
+// not returned as tokens; they are collected, see Comments.
+//
+// Tokens are buffered from the oldest mark not released, or only those
+// peeked at when there are none, so streams without marks use memory
+// proportional to the lookahead.
+type TokenStream struct {
+	l        *Lexer
+	buf      []token.Token // Tokens read from index start on.
+	start    int
+	pos      int   // Index of the next token.
+	err      error // Error of lexer after the buffered tokens.
+	marks    []Mark
+	comments []token.Token
+}
+
+// Mark is a position in TokenStream to reset it to.
+type Mark int
+
+// NewTokenStream returns stream of tokens of l.
+func NewTokenStream(l *Lexer) *TokenStream {
+	return &TokenStream{l: l}
+}
+
+// Next returns the next token and advances past it. At the end of input
+// it keeps returning EOF token. Lexer errors are returned once all tokens
+// before them are read, and keep being returned afterwards.
+func (s *TokenStream) Next() (token.Token, error) {
+	tok, err := s.Peek(0)
+	if err != nil {
+		return tok, err
+	}
+	if tok.Type != token.EOF {
+		s.pos++
+		s.trim()
+	}
+	return tok, nil
+}
+
+// Peek returns token n positions after the next one without advancing;
+// Peek(0) is the token Next returns. Tokens after the end of input are
+// EOF tokens.
+func (s *TokenStream) Peek(n int) (token.Token, error) {
+	i := s.pos - s.start + n
+	for i >= len(s.buf) {
+		if s.err != nil {
+			return token.Token{}, s.err
+		}
+		if len(s.buf) > 0 && s.buf[len(s.buf)-1].Type == token.EOF {
+			return s.buf[len(s.buf)-1], nil
+		}
+		s.read()
+	}
+	return s.buf[i], nil
+}
+
+// read appends the next token of lexer other than comment to buffer, or
+// records its error.
+func (s *TokenStream) read() {
+	for {
+		tok, err := s.l.NextToken()
+		if err != nil {
+			s.err = err
+			return
+		}
+		if tok.Type == token.COMMENT {
+			s.comments = append(s.comments, tok)
+			continue
+		}
+		s.buf = append(s.buf, tok)
+		return
+	}
+}
+
+// Mark returns the current position, which Reset returns to. Tokens from
+// the position on stay buffered until the mark is released.
+func (s *TokenStream) Mark() Mark {
+	m := Mark(s.pos)
+	s.marks = append(s.marks, m)
+	return m
+}
+
+// Reset makes the token at mark m the next one. m stays valid, so the
+// stream can be reset to it again.
+func (s *TokenStream) Reset(m Mark) {
+	if !slices.Contains(s.marks, m) {
+		panic("lexer: reset to mark not held")
+	}
+	s.pos = int(m)
+}
+
+// Release releases mark m, e.g. once the alternative it was taken for
+// succeeds.
+func (s *TokenStream) Release(m Mark) {
+	if i := slices.Index(s.marks, m); i >= 0 {
+		s.marks = slices.Delete(s.marks, i, i+1)
+		s.trim()
+	}
+}
+
+// trim drops buffered tokens before the oldest mark and the next token.
+func (s *TokenStream) trim() {
+	keep := s.pos
+	for _, m := range s.marks {
+		keep = min(keep, int(m))
+	}
+	if drop := keep - s.start; drop > 0 {
+		s.buf = s.buf[drop:]
+		s.start = keep
+	}
+}
+
+// Comments returns comment tokens read so far, in source order. Tokens
+// read once are not read again after Reset, so comments are not
+// duplicated.
+func (s *TokenStream) Comments() []token.Token {
+	return s.comments
+}
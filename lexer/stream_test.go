@@ -0,0 +1,95 @@
+package lexer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+func literals(t *testing.T, s *TokenStream, n int) string {
+	t.Helper()
+	var lits []string
+	for range n {
+		tok, err := s.Next()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		lits = append(lits, tok.Type.String()+":"+tok.Literal)
+	}
+	return strings.Join(lits, " ")
+}
+
+func TestTokenStream_Peek(t *testing.T) {
+	s := NewTokenStream(New("a # first\n b c"))
+	for n, expected := range []string{"a", "b", "c", ""} {
+		tok, err := s.Peek(n)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tok.Literal != expected {
+			t.Errorf("expected Peek(%d) %q, got %q", n, expected, tok.Literal)
+		}
+	}
+	if tok, _ := s.Peek(10); tok.Type != token.EOF {
+		t.Errorf("expected EOF, got %s", tok)
+	}
+	if got, expected := literals(t, s, 5), "NAME:a NAME:b NAME:c EOF: EOF:"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if got := s.Comments(); len(got) != 1 || got[0].Literal != " first" {
+		t.Errorf("expected comment \" first\", got %v", got)
+	}
+}
+
+func TestTokenStream_Mark(t *testing.T) {
+	s := NewTokenStream(New("type T { f: Int }"))
+	literals(t, s, 1)
+
+	outer := s.Mark()
+	if got, expected := literals(t, s, 2), "NAME:T LBRACE:"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	inner := s.Mark()
+	literals(t, s, 2)
+	s.Reset(inner)
+	if got, expected := literals(t, s, 3), "NAME:f COLON: NAME:Int"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	s.Release(inner)
+
+	s.Reset(outer)
+	s.Reset(outer)
+	if got, expected := literals(t, s, 2), "NAME:T LBRACE:"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	s.Release(outer)
+	if len(s.buf) != 3 {
+		t.Errorf("expected 3 buffered tokens, got %d", len(s.buf))
+	}
+	if got, expected := literals(t, s, 4), "NAME:f COLON: NAME:Int RBRACE:"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if len(s.buf) != 0 {
+		t.Errorf("expected no buffered tokens, got %d", len(s.buf))
+	}
+}
+
+func TestTokenStream_Error(t *testing.T) {
+	s := NewTokenStream(New("a ? b"))
+	if _, err := s.Peek(1); err == nil {
+		t.Fatal("expected error, got none")
+	}
+	if got, expected := literals(t, s, 1), "NAME:a"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	_, err := s.Next()
+	var lexErr *LexError
+	if !errors.As(err, &lexErr) || lexErr.Column != 3 {
+		t.Errorf("expected lex error at column 3, got %v", err)
+	}
+	if _, again := s.Next(); again != err {
+		t.Errorf("expected the same error, got %v", again)
+	}
+}
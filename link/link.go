@@ -0,0 +1,397 @@
+// Package link processes @link directives which bring definitions of
+// external specifications (e.g. Apollo Federation) into a schema under
+// namespaced or imported names.
+//
+// https://specs.apollo.dev/link/v1.0
+package link
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// SpecIdentity is identity of the link specification itself.
+const SpecIdentity = "https://specs.apollo.dev/link"
+
+// Version of linked specification, e.g. v2.3.
+type Version struct {
+	Major int
+	Minor int
+}
+
+func (v Version) String() string {
+	return "v" + strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor)
+}
+
+// ParseVersion parses version in form "vMAJOR.MINOR".
+func ParseVersion(s string) (Version, error) {
+	rest, ok := strings.CutPrefix(s, "v")
+	if !ok {
+		return Version{}, fmt.Errorf("invalid version %q: expected 'v' prefix", s)
+	}
+	majorStr, minorStr, ok := strings.Cut(rest, ".")
+	if !ok {
+		return Version{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR", s)
+	}
+	major, err := strconv.Atoi(majorStr)
+	if err != nil || major < 0 {
+		return Version{}, fmt.Errorf("invalid version %q: bad major version", s)
+	}
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil || minor < 0 {
+		return Version{}, fmt.Errorf("invalid version %q: bad minor version", s)
+	}
+	return Version{Major: major, Minor: minor}, nil
+}
+
+// Satisfies reports whether definitions of version v can be used where
+// version required is expected: majors must match and minor must not be
+// lower. Pre-1.0 versions are compatible only with themselves.
+func (v Version) Satisfies(required Version) bool {
+	if v.Major != required.Major {
+		return false
+	}
+	if v.Major == 0 {
+		return v.Minor == required.Minor
+	}
+	return v.Minor >= required.Minor
+}
+
+// URL of linked specification, e.g.
+// "https://specs.apollo.dev/federation/v2.3".
+type URL struct {
+	Raw       string
+	Identity  string // URL without version, e.g. "https://specs.apollo.dev/federation".
+	Name      string // Specification name, e.g. "federation". May be empty.
+	Version   Version
+	Versioned bool // Whether URL carries a version.
+}
+
+// ParseURL parses URL of linked specification. The last path segment is
+// version when it looks like one, the segment before it is the name.
+func ParseURL(raw string) (URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return URL{}, fmt.Errorf("invalid link url %q: %w", raw, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return URL{}, fmt.Errorf("invalid link url %q: expected absolute url", raw)
+	}
+	result := URL{Raw: raw}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if last := segments[len(segments)-1]; strings.HasPrefix(last, "v") {
+		if v, err := ParseVersion(last); err == nil {
+			result.Version = v
+			result.Versioned = true
+			segments = segments[:len(segments)-1]
+		}
+	}
+	if len(segments) > 0 && segments[len(segments)-1] != "" {
+		result.Name = segments[len(segments)-1]
+		if !isName(result.Name) {
+			result.Name = ""
+		}
+	}
+
+	u.RawQuery, u.Fragment = "", ""
+	u.Path = "/" + strings.Join(segments, "/")
+	result.Identity = strings.TrimSuffix(u.String(), "/")
+	return result, nil
+}
+
+// Import of specification element under local name. Directive names
+// start with '@'.
+type Import struct {
+	Name string // Name in specification, e.g. "@key".
+	As   string // Local name, e.g. "@primaryKey". Equals Name when not renamed.
+}
+
+// Link is a processed @link application.
+type Link struct {
+	URL       URL
+	As        string // Namespace override from "as" argument.
+	Imports   []Import
+	For       string // Purpose, e.g. "SECURITY" or "EXECUTION".
+	Directive *ast.Directive
+}
+
+// Namespace returns prefix of non-imported elements.
+func (l *Link) Namespace() string {
+	if l.As != "" {
+		return l.As
+	}
+	return l.URL.Name
+}
+
+// LocalName returns name element of linked specification has in schema,
+// e.g. "@key" -> "@federation__key" or "@key" when imported. Directive
+// named after the namespace is available without prefix, e.g. "@tag".
+func (l *Link) LocalName(element string) string {
+	for _, imp := range l.Imports {
+		if imp.Name == element {
+			return imp.As
+		}
+	}
+	name, isDirective := strings.CutPrefix(element, "@")
+	ns := l.Namespace()
+	if isDirective {
+		if name == l.URL.Name {
+			return "@" + ns
+		}
+		return "@" + ns + "__" + name
+	}
+	return ns + "__" + name
+}
+
+// SpecName returns element of linked specification local name refers to.
+func (l *Link) SpecName(local string) (string, bool) {
+	for _, imp := range l.Imports {
+		if imp.As == local {
+			return imp.Name, true
+		}
+	}
+	name, isDirective := strings.CutPrefix(local, "@")
+	ns := l.Namespace()
+	if ns == "" {
+		return "", false
+	}
+	if isDirective && name == ns {
+		return "@" + l.URL.Name, true
+	}
+	element, ok := strings.CutPrefix(name, ns+"__")
+	if !ok || element == "" {
+		return "", false
+	}
+	if isDirective {
+		return "@" + element, true
+	}
+	return element, true
+}
+
+// Links are links of a schema.
+type Links []*Link
+
+// Lookup returns link and specification element local name refers to.
+func (ls Links) Lookup(local string) (*Link, string, bool) {
+	for _, l := range ls {
+		if name, ok := l.SpecName(local); ok {
+			return l, name, true
+		}
+	}
+	return nil, "", false
+}
+
+// ByIdentity returns link to specification with given identity.
+func (ls Links) ByIdentity(identity string) (*Link, bool) {
+	for _, l := range ls {
+		if l.URL.Identity == identity {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// ErrIncompatibleVersion is returned by Require.
+var ErrIncompatibleVersion = errors.New("incompatible specification version")
+
+// Require returns link to specification identity, checking that its
+// version satisfies required one.
+func (ls Links) Require(identity string, required Version) (*Link, error) {
+	l, ok := ls.ByIdentity(identity)
+	if !ok {
+		return nil, fmt.Errorf("specification %s is not linked", identity)
+	}
+	if !l.URL.Version.Satisfies(required) {
+		return nil, fmt.Errorf("%w: %s links %s, %s required", ErrIncompatibleVersion, identity, l.URL.Version, required)
+	}
+	return l, nil
+}
+
+// Extract processes @link directives applied to schema definition and
+// extensions of doc. The link directive may itself be renamed by linking
+// the link specification with "as" or by importing it under another name.
+func Extract(doc *ast.Document) (Links, error) {
+	var directives []*ast.Directive
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			directives = append(directives, d.Directives...)
+		case *ast.SchemaExtension:
+			directives = append(directives, d.Directives...)
+		}
+	}
+	return FromDirectives(directives)
+}
+
+// FromDirectives processes @link directives among directives applied to
+// schema, e.g. collected by a schema builder.
+func FromDirectives(directives []*ast.Directive) (Links, error) {
+	linkName := bootstrapName(directives)
+	var links Links
+	namespaces := map[string]*Link{}
+	imported := map[string]*Link{}
+	for _, d := range directives {
+		if d.Name.Value != linkName {
+			continue
+		}
+		l, err := parseLink(d)
+		if err != nil {
+			return nil, err
+		}
+		if ns := l.Namespace(); ns != "" {
+			if other, ok := namespaces[ns]; ok {
+				return nil, fmt.Errorf("namespace %q of %s at %d is already used by %s", ns, l.URL.Raw, d.Pos(), other.URL.Raw)
+			}
+			namespaces[ns] = l
+		}
+		for _, imp := range l.Imports {
+			if other, ok := imported[imp.As]; ok {
+				return nil, fmt.Errorf("name %q imported from %s at %d is already imported from %s", imp.As, l.URL.Raw, d.Pos(), other.URL.Raw)
+			}
+			imported[imp.As] = l
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
+// bootstrapName finds name of the link directive: the one linking the
+// link specification itself. Defaults to "link".
+func bootstrapName(directives []*ast.Directive) string {
+	for _, d := range directives {
+		raw, ok := stringArg(d, "url")
+		if !ok {
+			continue
+		}
+		u, err := ParseURL(raw)
+		if err != nil || u.Identity != SpecIdentity {
+			continue
+		}
+		l, err := parseLink(d)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimPrefix(l.LocalName("@link"), "@")
+		if name == d.Name.Value {
+			return name
+		}
+	}
+	return "link"
+}
+
+func parseLink(d *ast.Directive) (*Link, error) {
+	raw, ok := stringArg(d, "url")
+	if !ok {
+		return nil, fmt.Errorf("@%s at %d: missing url argument", d.Name.Value, d.Pos())
+	}
+	u, err := ParseURL(raw)
+	if err != nil {
+		return nil, fmt.Errorf("@%s at %d: %w", d.Name.Value, d.Pos(), err)
+	}
+	l := &Link{URL: u, Directive: d}
+
+	if as, ok := stringArg(d, "as"); ok {
+		if !isName(as) || strings.Contains(as, "__") {
+			return nil, fmt.Errorf("@%s at %d: invalid namespace %q", d.Name.Value, d.Pos(), as)
+		}
+		l.As = as
+	}
+	if purpose, ok := argument(d, "for").(*ast.EnumValue); ok {
+		l.For = purpose.Value
+	}
+
+	switch v := argument(d, "import").(type) {
+	case nil, *ast.NullValue:
+	case *ast.ListValue:
+		for _, item := range v.Values {
+			imp, err := parseImport(item)
+			if err != nil {
+				return nil, fmt.Errorf("@%s at %d: %w", d.Name.Value, d.Pos(), err)
+			}
+			l.Imports = append(l.Imports, imp)
+		}
+	default:
+		return nil, fmt.Errorf("@%s at %d: import must be a list", d.Name.Value, d.Pos())
+	}
+	return l, nil
+}
+
+func parseImport(v ast.Value) (Import, error) {
+	var imp Import
+	switch v := v.(type) {
+	case *ast.StringValue:
+		imp = Import{Name: v.Value, As: v.Value}
+	case *ast.ObjectValue:
+		for _, f := range v.Fields {
+			s, ok := f.Value.(*ast.StringValue)
+			if !ok {
+				return Import{}, fmt.Errorf("import field %q at %d must be a string", f.Name.Value, f.Pos())
+			}
+			switch f.Name.Value {
+			case "name":
+				imp.Name = s.Value
+			case "as":
+				imp.As = s.Value
+			default:
+				return Import{}, fmt.Errorf("unknown import field %q at %d", f.Name.Value, f.Pos())
+			}
+		}
+		if imp.As == "" {
+			imp.As = imp.Name
+		}
+	default:
+		return Import{}, fmt.Errorf("import at %d must be a string or an object", v.Pos())
+	}
+
+	if !isElementName(imp.Name) {
+		return Import{}, fmt.Errorf("invalid import name %q at %d", imp.Name, v.Pos())
+	}
+	if !isElementName(imp.As) {
+		return Import{}, fmt.Errorf("invalid import alias %q at %d", imp.As, v.Pos())
+	}
+	if strings.HasPrefix(imp.Name, "@") != strings.HasPrefix(imp.As, "@") {
+		return Import{}, fmt.Errorf("import %q at %d: directive can only be renamed to directive and type to type", imp.Name, v.Pos())
+	}
+	return imp, nil
+}
+
+func argument(d *ast.Directive, name string) ast.Value {
+	for _, arg := range d.Arguments {
+		if arg.Name.Value == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
+
+func stringArg(d *ast.Directive, name string) (string, bool) {
+	s, ok := argument(d, name).(*ast.StringValue)
+	if !ok {
+		return "", false
+	}
+	return s.Value, true
+}
+
+func isElementName(s string) bool {
+	return isName(strings.TrimPrefix(s, "@"))
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch == '_' || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') || (i > 0 && '0' <= ch && ch <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+package link
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		identity string
+		name     string
+		version  Version
+		err      bool
+	}{
+		{"https://specs.apollo.dev/federation/v2.3", "https://specs.apollo.dev/federation", "federation", Version{2, 3}, false},
+		{"https://specs.apollo.dev/link/v1.0/", "https://specs.apollo.dev/link", "link", Version{1, 0}, false},
+		{"https://example.com/my-spec/v0.1", "https://example.com/my-spec", "", Version{0, 1}, false},
+		{"https://example.com/spec", "https://example.com/spec", "spec", Version{}, false},
+		{"federation/v2.3", "", "", Version{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			u, err := ParseURL(tt.raw)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u.Identity != tt.identity || u.Name != tt.name || u.Version != tt.version {
+				t.Errorf("got %+v", u)
+			}
+		})
+	}
+}
+
+func TestVersion_Satisfies(t *testing.T) {
+	tests := []struct {
+		v, required Version
+		expected    bool
+	}{
+		{Version{2, 3}, Version{2, 0}, true},
+		{Version{2, 0}, Version{2, 3}, false},
+		{Version{3, 0}, Version{2, 0}, false},
+		{Version{0, 2}, Version{0, 2}, true},
+		{Version{0, 3}, Version{0, 2}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Satisfies(tt.required); got != tt.expected {
+			t.Errorf("%s satisfies %s: expected %v, got %v", tt.v, tt.required, tt.expected, got)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	links, err := Extract(asttest.Parse(t, `
+schema @link(url: "https://specs.apollo.dev/link/v1.0") { query: Query }
+extend schema
+  @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", { name: "@shareable", as: "@share" }, "FieldSet"])
+  @link(url: "https://specs.apollo.dev/tag/v0.3", as: "label", for: EXECUTION)
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(links) != 3 {
+		t.Fatalf("expected 3 links, got %d", len(links))
+	}
+
+	fed := links[1]
+	if !reflect.DeepEqual(fed.Imports, []Import{{"@key", "@key"}, {"@shareable", "@share"}, {"FieldSet", "FieldSet"}}) {
+		t.Errorf("unexpected imports: %+v", fed.Imports)
+	}
+	if links[2].For != "EXECUTION" || links[2].Namespace() != "label" {
+		t.Errorf("unexpected tag link: %+v", links[2])
+	}
+
+	localNames := []struct {
+		link     *Link
+		element  string
+		expected string
+	}{
+		{fed, "@key", "@key"},
+		{fed, "@shareable", "@share"},
+		{fed, "@requires", "@federation__requires"},
+		{fed, "Policy", "federation__Policy"},
+		{links[2], "@tag", "@label"},
+		{links[0], "@link", "@link"},
+		{links[0], "Purpose", "link__Purpose"},
+	}
+	for _, tt := range localNames {
+		if got := tt.link.LocalName(tt.element); got != tt.expected {
+			t.Errorf("local name of %s: expected %s, got %s", tt.element, tt.expected, got)
+		}
+	}
+
+	lookups := []struct {
+		local    string
+		identity string
+		element  string
+	}{
+		{"@share", "https://specs.apollo.dev/federation", "@shareable"},
+		{"@federation__requires", "https://specs.apollo.dev/federation", "@requires"},
+		{"FieldSet", "https://specs.apollo.dev/federation", "FieldSet"},
+		{"@label", "https://specs.apollo.dev/tag", "@tag"},
+		{"link__Import", "https://specs.apollo.dev/link", "Import"},
+	}
+	for _, tt := range lookups {
+		l, element, ok := links.Lookup(tt.local)
+		if !ok || l.URL.Identity != tt.identity || element != tt.element {
+			t.Errorf("lookup %s: got %v %q %v", tt.local, l, element, ok)
+		}
+	}
+	if _, _, ok := links.Lookup("@shareable"); ok {
+		t.Errorf("expected renamed @shareable not to resolve")
+	}
+}
+
+func TestExtract_RenamedLink(t *testing.T) {
+	links, err := Extract(asttest.Parse(t, `
+schema
+  @core(url: "https://specs.apollo.dev/link/v1.0", import: [{ name: "@link", as: "@core" }])
+  @core(url: "https://specs.apollo.dev/federation/v2.0")
+  @link(url: "https://example.com/ignored/v1.0")
+{ query: Query }
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(links) != 2 || links[1].URL.Name != "federation" {
+		t.Fatalf("unexpected links: %+v", links)
+	}
+}
+
+func TestExtract_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing url", `extend schema @link(as: "x")`},
+		{"relative url", `extend schema @link(url: "federation/v2.0")`},
+		{"import not list", `extend schema @link(url: "https://a.dev/a/v1.0", import: "@a")`},
+		{"bad import", `extend schema @link(url: "https://a.dev/a/v1.0", import: [1])`},
+		{"directive renamed to type", `extend schema @link(url: "https://a.dev/a/v1.0", import: [{ name: "@a", as: "A" }])`},
+		{"unknown import field", `extend schema @link(url: "https://a.dev/a/v1.0", import: [{ name: "@a", alias: "@b" }])`},
+		{"invalid namespace", `extend schema @link(url: "https://a.dev/a/v1.0", as: "a__b")`},
+		{"duplicate namespace", `extend schema @link(url: "https://a.dev/a/v1.0") @link(url: "https://b.dev/a/v1.0")`},
+		{"duplicate import", `extend schema @link(url: "https://a.dev/a/v1.0", import: ["@x"]) @link(url: "https://b.dev/b/v1.0", import: ["@x"])`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Extract(asttest.Parse(t, tt.input)); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestLinks_Require(t *testing.T) {
+	links, err := Extract(asttest.Parse(t, `extend schema @link(url: "https://specs.apollo.dev/federation/v2.3")`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := links.Require("https://specs.apollo.dev/federation", Version{2, 1}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := links.Require("https://specs.apollo.dev/federation", Version{2, 5}); !errors.Is(err, ErrIncompatibleVersion) {
+		t.Errorf("expected ErrIncompatibleVersion, got %v", err)
+	}
+	if _, err := links.Require("https://specs.apollo.dev/tag", Version{0, 3}); err == nil {
+		t.Errorf("expected error for unlinked specification")
+	}
+}
@@ -0,0 +1,282 @@
+// Package lint provides a stable extension point for GraphQL lint rules.
+//
+// Organizations can compile proprietary rules into their tooling by
+// implementing Rule together with one of the hook interfaces and
+// registering it with a Registry (or the package level Register function).
+// Validation rules, e.g. validation.SpecifiedRules, are registered in the
+// same Registry once adapted by ValidationRule.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/edit"
+)
+
+// Rule is implemented by every lint rule. A rule additionally implements
+// DocumentRule, SchemaRule or both to be invoked by the linter.
+type Rule interface {
+	// Name returns unique rule name, e.g. "no-deprecated-fields".
+	Name() string
+}
+
+// DocumentRule is implemented by rules inspecting executable documents
+// (operations and fragments).
+type DocumentRule interface {
+	Rule
+	CheckDocument(ctx *Context, doc *ast.Document)
+}
+
+// SchemaRule is implemented by rules inspecting type system documents.
+type SchemaRule interface {
+	Rule
+	CheckSchema(ctx *Context, schema *ast.Document)
+}
+
+// Severity of a diagnostic.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+var severities = [...]string{
+	SeverityError:   "error",
+	SeverityWarning: "warning",
+	SeverityInfo:    "info",
+}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severities) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severities[s]
+}
+
+// Diagnostic is a single problem reported by a rule.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Message  string
+	Position int // Byte offset in source, same as ast.Node.Pos().
+	Fixes    []Fix
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d: %s: %s (%s)", d.Position, d.Severity, d.Message, d.Rule)
+}
+
+// Fix is a suggested change resolving a diagnostic. All edits of a fix
+// must be applied together. Edits are usually built from nodes with
+// edit.Replace, edit.Delete, edit.InsertBefore and edit.InsertAfter.
+type Fix struct {
+	Message string
+	Edits   []edit.Edit
+}
+
+// Context is passed to rule hooks. It gives access to the schema (when
+// linting operations against one) and collects reported diagnostics.
+type Context struct {
+	// Schema is type system document operations are checked against.
+	// It is nil if no schema was provided.
+	Schema *ast.Document
+
+	rule        Rule
+	severity    Severity
+	diagnostics []Diagnostic
+	built       *builtSchema // Shared by rules of one LintDocument call.
+}
+
+// Report records a diagnostic at given position with optional fixes.
+func (c *Context) Report(pos int, message string, fixes ...Fix) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Rule:     c.rule.Name(),
+		Severity: c.severity,
+		Message:  message,
+		Position: pos,
+		Fixes:    fixes,
+	})
+}
+
+// Reportf records a diagnostic with formatted message.
+func (c *Context) Reportf(pos int, format string, args ...any) {
+	c.Report(pos, fmt.Sprintf(format, args...))
+}
+
+var (
+	ErrEmptyRuleName     = errors.New("lint: rule name must not be empty")
+	ErrDuplicateRuleName = errors.New("lint: duplicate rule name")
+	ErrNoRuleHooks       = errors.New("lint: rule implements neither DocumentRule nor SchemaRule")
+)
+
+type registeredRule struct {
+	rule     Rule
+	severity Severity
+}
+
+// Registry holds registered rules. The zero value is ready to use.
+type Registry struct {
+	rules []registeredRule
+	index map[string]int
+}
+
+// NewRegistry returns empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds rule reporting diagnostics with SeverityError.
+func (r *Registry) Register(rule Rule) error {
+	return r.RegisterWithSeverity(rule, SeverityError)
+}
+
+// RegisterWithSeverity adds rule reporting diagnostics with given severity.
+func (r *Registry) RegisterWithSeverity(rule Rule, severity Severity) error {
+	name := rule.Name()
+	if name == "" {
+		return ErrEmptyRuleName
+	}
+	if _, ok := r.index[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateRuleName, name)
+	}
+	_, isDoc := rule.(DocumentRule)
+	_, isSchema := rule.(SchemaRule)
+	if !isDoc && !isSchema {
+		return fmt.Errorf("%w: %q", ErrNoRuleHooks, name)
+	}
+	if r.index == nil {
+		r.index = make(map[string]int)
+	}
+	r.index[name] = len(r.rules)
+	r.rules = append(r.rules, registeredRule{rule: rule, severity: severity})
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is intended for
+// use in package init functions.
+func (r *Registry) MustRegister(rule Rule) {
+	if err := r.Register(rule); err != nil {
+		panic(err)
+	}
+}
+
+// Rule returns registered rule by name.
+func (r *Registry) Rule(name string) (Rule, bool) {
+	i, ok := r.index[name]
+	if !ok {
+		return nil, false
+	}
+	return r.rules[i].rule, true
+}
+
+// Rules returns registered rules in registration order.
+func (r *Registry) Rules() []Rule {
+	rules := make([]Rule, len(r.rules))
+	for i, rr := range r.rules {
+		rules[i] = rr.rule
+	}
+	return rules
+}
+
+// LintDocument runs every DocumentRule against executable document doc.
+// Schema may be nil. Diagnostics are sorted by position.
+func (r *Registry) LintDocument(schema, doc *ast.Document) []Diagnostic {
+	var diagnostics []Diagnostic
+	built := &builtSchema{}
+	for _, rr := range r.rules {
+		dr, ok := rr.rule.(DocumentRule)
+		if !ok {
+			continue
+		}
+		ctx := &Context{Schema: schema, rule: rr.rule, severity: rr.severity, built: built}
+		dr.CheckDocument(ctx, doc)
+		diagnostics = append(diagnostics, ctx.diagnostics...)
+	}
+	sortDiagnostics(diagnostics)
+	return diagnostics
+}
+
+// LintSchema runs every SchemaRule against type system document schema.
+// Diagnostics are sorted by position.
+func (r *Registry) LintSchema(schema *ast.Document) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, rr := range r.rules {
+		sr, ok := rr.rule.(SchemaRule)
+		if !ok {
+			continue
+		}
+		ctx := &Context{Schema: schema, rule: rr.rule, severity: rr.severity}
+		sr.CheckSchema(ctx, schema)
+		diagnostics = append(diagnostics, ctx.diagnostics...)
+	}
+	sortDiagnostics(diagnostics)
+	return diagnostics
+}
+
+func sortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Position < diagnostics[j].Position
+	})
+}
+
+// Default is registry used by package level functions.
+var Default = NewRegistry()
+
+// Register adds rule to Default registry.
+func Register(rule Rule) error {
+	return Default.Register(rule)
+}
+
+// MustRegister adds rule to Default registry and panics on error.
+func MustRegister(rule Rule) {
+	Default.MustRegister(rule)
+}
+
+// ApplyFixes applies the first fix of every diagnostic to src with a,
+// skipping fixes overlapping already accepted ones; a nil a applies them
+// with edit.Apply. It returns new source together with the number of
+// applied fixes.
+func ApplyFixes(a *edit.Applier, src string, diagnostics []Diagnostic) (string, int, error) {
+	var (
+		accepted []edit.Edit
+		applied  int
+	)
+	for _, d := range diagnostics {
+		if len(d.Fixes) == 0 {
+			continue
+		}
+		edits := d.Fixes[0].Edits
+		if overlaps(accepted, edits) {
+			continue
+		}
+		accepted = append(accepted, edits...)
+		applied++
+	}
+	if applied == 0 {
+		return src, 0, nil
+	}
+	if a == nil {
+		a = &edit.Applier{}
+	}
+	result, err := a.Apply(src, accepted)
+	if err != nil {
+		return "", 0, err
+	}
+	return result, applied, nil
+}
+
+func overlaps(accepted, edits []edit.Edit) bool {
+	for _, e := range edits {
+		for _, a := range accepted {
+			if e.Start < a.End && a.Start < e.End || e.Start == a.Start {
+				return true
+			}
+		}
+	}
+	return false
+}
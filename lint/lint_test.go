@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/edit"
+)
+
+type rootTypenameRule struct{}
+
+func (rootTypenameRule) Name() string { return "no-root-typename" }
+
+func (rootTypenameRule) CheckDocument(ctx *Context, doc *ast.Document) {
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		for _, sel := range op.SelectionSet.Selections {
+			if f, ok := sel.(*ast.Field); ok && f.Name.Value == "__typename" {
+				ctx.Report(f.Pos(), "__typename is useless on root operation type", Fix{
+					Message: "Remove field",
+					Edits:   []edit.Edit{edit.Delete(f)},
+				})
+			}
+		}
+	}
+}
+
+type lowerCaseTypeRule struct{}
+
+func (lowerCaseTypeRule) Name() string { return "type-name-case" }
+
+func (lowerCaseTypeRule) CheckSchema(ctx *Context, schema *ast.Document) {
+	for _, def := range schema.Definitions {
+		obj, ok := def.(*ast.ObjectTypeDefinition)
+		if !ok {
+			continue
+		}
+		name := obj.Name.Value
+		if name[0] >= 'a' && name[0] <= 'z' {
+			fixed := string(name[0]-'a'+'A') + name[1:]
+			ctx.Report(obj.Name.Pos(), "type name must start with upper case letter", Fix{
+				Message: "Rename to " + fixed,
+				Edits:   []edit.Edit{edit.Replace(obj.Name, fixed)},
+			})
+		}
+	}
+}
+
+type noHooksRule struct{}
+
+func (noHooksRule) Name() string { return "no-hooks" }
+
+func TestRegistry_Register(t *testing.T) {
+	var r Registry
+	if err := r.Register(rootTypenameRule{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Register(rootTypenameRule{}); !errors.Is(err, ErrDuplicateRuleName) {
+		t.Errorf("expected ErrDuplicateRuleName, got %v", err)
+	}
+	if err := r.Register(noHooksRule{}); !errors.Is(err, ErrNoRuleHooks) {
+		t.Errorf("expected ErrNoRuleHooks, got %v", err)
+	}
+	if _, ok := r.Rule("no-root-typename"); !ok {
+		t.Errorf("expected rule to be registered")
+	}
+	if got := len(r.Rules()); got != 1 {
+		t.Errorf("expected 1 rule, got %d", got)
+	}
+}
+
+func TestRegistry_LintDocument(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(rootTypenameRule{})
+	r.MustRegister(lowerCaseTypeRule{})
+
+	diagnostics := r.LintDocument(nil, asttest.Parse(t, `query A { __typename a } query B { b }`))
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d: %v", len(diagnostics), diagnostics)
+	}
+	d := diagnostics[0]
+	if d.Rule != "no-root-typename" || d.Position != 10 || d.Severity != SeverityError {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
+
+func TestRegistry_LintSchema(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(rootTypenameRule{})
+	if err := r.RegisterWithSeverity(lowerCaseTypeRule{}, SeverityWarning); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	diagnostics := r.LintSchema(asttest.Parse(t, `type user { id: ID } type Post { id: ID }`))
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d: %v", len(diagnostics), diagnostics)
+	}
+	d := diagnostics[0]
+	if d.Severity != SeverityWarning || d.Position != 5 {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+	if len(d.Fixes) != 1 || len(d.Fixes[0].Edits) != 1 {
+		t.Fatalf("expected single fix with single edit, got %+v", d.Fixes)
+	}
+	expected := edit.Edit{Start: 5, End: 9, NewText: "User"}
+	if got := d.Fixes[0].Edits[0]; got != expected {
+		t.Errorf("expected edit %s, got %s", expected, got)
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(lowerCaseTypeRule{})
+
+	src := `type user { id: ID } type post { id: ID }`
+	diagnostics := r.LintSchema(asttest.Parse(t, src))
+	got, applied, err := ApplyFixes(&edit.Applier{Validate: true}, src, diagnostics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if applied != 2 {
+		t.Errorf("expected 2 applied fixes, got %d", applied)
+	}
+	if expected := `type User { id: ID } type Post { id: ID }`; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestApplyFixes_Overlapping(t *testing.T) {
+	src := `{ __typename a }`
+	field := asttest.Parse(t, src).Definitions[0].(*ast.OperationDefinition).SelectionSet.Selections[0]
+	diagnostics := []Diagnostic{
+		{Fixes: []Fix{{Edits: []edit.Edit{edit.Delete(field)}}}},
+		{Fixes: []Fix{{Edits: []edit.Edit{edit.Replace(field, "id")}}}},
+	}
+	got, applied, err := ApplyFixes(nil, src, diagnostics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if applied != 1 {
+		t.Errorf("expected 1 applied fix, got %d", applied)
+	}
+	if expected := `{  a }`; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSeverity_String(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		expected string
+	}{
+		{SeverityError, "error"},
+		{SeverityInfo, "info"},
+		{Severity(7), "Severity(7)"},
+		{Severity(-1), "Severity(-1)"},
+	}
+	for _, tt := range tests {
+		if got := tt.severity.String(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
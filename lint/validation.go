@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+// ValidationRule adapts validation rule to DocumentRule, so that it is
+// registered, named and given severity like other lint rules:
+//
+//	for _, rule := range validation.SpecifiedRules {
+//		lint.MustRegister(lint.ValidationRule(rule))
+//	}
+//
+// Every validation error is reported at its first position, without
+// fixes. The schema document is built once per LintDocument call; when it
+// is invalid, its error is reported once at position 0 by rule
+// InvalidSchemaRule with SeverityError, and rules run without schema,
+// like validation.Validate with nil schema.
+func ValidationRule(rule validation.Rule) DocumentRule {
+	return validationRule{rule}
+}
+
+type validationRule struct {
+	rule validation.Rule
+}
+
+func (r validationRule) Name() string {
+	return r.rule.Name
+}
+
+func (r validationRule) CheckDocument(ctx *Context, doc *ast.Document) {
+	s := ctx.schema()
+	for _, err := range validation.Validate(s, doc, r.rule) {
+		pos := 0
+		if len(err.Positions) > 0 {
+			pos = err.Positions[0]
+		}
+		ctx.Report(pos, err.Message)
+	}
+}
+
+// InvalidSchemaRule is rule name of diagnostic reporting schema document
+// that ValidationRule cannot build a schema from.
+const InvalidSchemaRule = "invalid-schema"
+
+// builtSchema is schema built from Context.Schema on first use.
+type builtSchema struct {
+	done     bool
+	schema   *schema.Schema
+	err      error
+	reported bool
+}
+
+// schema returns schema built from c.Schema, or nil when there is none or
+// it is invalid. The build error is reported once, whichever rule asks
+// first.
+func (c *Context) schema() *schema.Schema {
+	b := c.built
+	if b == nil {
+		b = &builtSchema{}
+		c.built = b
+	}
+	if !b.done {
+		b.done = true
+		if c.Schema != nil {
+			b.schema, b.err = schema.FromDocument(c.Schema)
+		}
+	}
+	if b.err != nil && !b.reported {
+		b.reported = true
+		c.diagnostics = append(c.diagnostics, Diagnostic{
+			Rule:     InvalidSchemaRule,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("invalid schema: %v", b.err),
+		})
+	}
+	return b.schema
+}
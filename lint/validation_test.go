@@ -0,0 +1,45 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+func TestValidationRule(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(rootTypenameRule{})
+	for _, rule := range validation.SpecifiedRules {
+		if err := r.RegisterWithSeverity(ValidationRule(rule), SeverityWarning); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := r.Register(ValidationRule(validation.SpecifiedRules[0])); err == nil {
+		t.Errorf("expected duplicate validation rule to be rejected")
+	}
+
+	schema := asttest.Parse(t, `type Query { a: Int }`)
+	diagnostics := r.LintDocument(schema, asttest.Parse(t, `query A { __typename b }`))
+	expected := []string{
+		`10: error: __typename is useless on root operation type (no-root-typename)`,
+		`21: warning: Cannot query field "b" on type "Query". (FieldsOnCorrectType)`,
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %v", len(expected), diagnostics)
+	}
+	for i, d := range diagnostics {
+		if d.String() != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], d)
+		}
+	}
+
+	diagnostics = r.LintDocument(asttest.Parse(t, `type Query { a: Missing }`), asttest.Parse(t, `{ a }`))
+	if len(diagnostics) != 1 || diagnostics[0].Position != 0 {
+		t.Fatalf("expected single diagnostic of invalid schema, got %v", diagnostics)
+	}
+	if d := diagnostics[0]; d.Rule != InvalidSchemaRule || d.Severity != SeverityError || !strings.HasPrefix(d.Message, "invalid schema: ") {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
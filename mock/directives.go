@@ -0,0 +1,252 @@
+// Package mock reads mock data directives of a schema, letting schema
+// authors describe realistic, domain-shaped values of fields and scalars
+// for mock data generators without writing Go code:
+//
+//	type User {
+//	  name: String @fake(type: NAME)
+//	  role: String @examples(values: ["admin", "editor"])
+//	}
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+)
+
+// DirectivesSDL declares directives read by this package. It can be
+// appended to schemas that use them.
+const DirectivesSDL = `
+"Kind of realistic value generated for a field or scalar."
+enum FakeType {
+  NAME FIRST_NAME LAST_NAME EMAIL PHONE URL UUID
+  WORD SENTENCE CITY COUNTRY COMPANY DATE DATE_TIME
+}
+
+"Literal used by @examples. Any GraphQL value is accepted."
+scalar ExampleValue
+
+"Generates values of given kind."
+directive @fake(type: FakeType!) on FIELD_DEFINITION | SCALAR
+
+"Picks values from the given list."
+directive @examples(values: [ExampleValue!]!) on FIELD_DEFINITION | SCALAR | ENUM
+`
+
+// FakeType is kind of generated value.
+type FakeType string
+
+const (
+	FakeName      FakeType = "NAME"
+	FakeFirstName FakeType = "FIRST_NAME"
+	FakeLastName  FakeType = "LAST_NAME"
+	FakeEmail     FakeType = "EMAIL"
+	FakePhone     FakeType = "PHONE"
+	FakeURL       FakeType = "URL"
+	FakeUUID      FakeType = "UUID"
+	FakeWord      FakeType = "WORD"
+	FakeSentence  FakeType = "SENTENCE"
+	FakeCity      FakeType = "CITY"
+	FakeCountry   FakeType = "COUNTRY"
+	FakeCompany   FakeType = "COMPANY"
+	FakeDate      FakeType = "DATE"
+	FakeDateTime  FakeType = "DATE_TIME"
+)
+
+var fakers = map[FakeType]func(r *rand.Rand) string{
+	FakeName:      func(r *rand.Rand) string { return pick(r, firstNames) + " " + pick(r, lastNames) },
+	FakeFirstName: func(r *rand.Rand) string { return pick(r, firstNames) },
+	FakeLastName:  func(r *rand.Rand) string { return pick(r, lastNames) },
+	FakeEmail: func(r *rand.Rand) string {
+		return strings.ToLower(pick(r, firstNames)+"."+pick(r, lastNames)) + "@example.com"
+	},
+	FakePhone: func(r *rand.Rand) string {
+		return fmt.Sprintf("+1-555-%03d-%04d", r.IntN(1000), r.IntN(10000))
+	},
+	FakeURL: func(r *rand.Rand) string { return "https://example.com/" + pick(r, words) },
+	FakeUUID: func(r *rand.Rand) string {
+		return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x", r.Uint32(), r.IntN(1<<16), r.IntN(1<<12), 0x8000|r.IntN(1<<14), r.Uint64()&(1<<48-1))
+	},
+	FakeWord: func(r *rand.Rand) string { return pick(r, words) },
+	FakeSentence: func(r *rand.Rand) string {
+		n := 4 + r.IntN(5)
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = pick(r, words)
+		}
+		s := strings.Join(parts, " ")
+		return strings.ToUpper(s[:1]) + s[1:] + "."
+	},
+	FakeCity:    func(r *rand.Rand) string { return pick(r, cities) },
+	FakeCountry: func(r *rand.Rand) string { return pick(r, countries) },
+	FakeCompany: func(r *rand.Rand) string { return pick(r, lastNames) + " " + pick(r, companySuffixes) },
+	FakeDate: func(r *rand.Rand) string {
+		return fmt.Sprintf("%04d-%02d-%02d", 2000+r.IntN(30), 1+r.IntN(12), 1+r.IntN(28))
+	},
+	FakeDateTime: func(r *rand.Rand) string {
+		return fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02dZ", 2000+r.IntN(30), 1+r.IntN(12), 1+r.IntN(28), r.IntN(24), r.IntN(60), r.IntN(60))
+	},
+}
+
+var (
+	firstNames      = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Dennis", "Barbara", "Ken"}
+	lastNames       = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Ritchie", "Liskov", "Thompson"}
+	words           = []string{"graph", "query", "schema", "field", "type", "node", "edge", "cursor", "value", "list"}
+	cities          = []string{"Lisbon", "Oslo", "Kyoto", "Austin", "Nairobi", "Lima", "Tallinn", "Perth"}
+	countries       = []string{"Portugal", "Norway", "Japan", "United States", "Kenya", "Peru", "Estonia", "Australia"}
+	companySuffixes = []string{"Inc.", "LLC", "Group", "Labs"}
+)
+
+func pick(r *rand.Rand, values []string) string {
+	return values[r.IntN(len(values))]
+}
+
+// Spec describes how values of a field or scalar are generated.
+type Spec struct {
+	Fake     FakeType // Set by @fake.
+	Examples []any    // Set by @examples, decoded as by Literal.
+}
+
+// Value returns generated value: one of examples when there are any,
+// otherwise a fake value.
+func (s Spec) Value(r *rand.Rand) any {
+	if len(s.Examples) > 0 {
+		return s.Examples[r.IntN(len(s.Examples))]
+	}
+	if faker, ok := fakers[s.Fake]; ok {
+		return faker(r)
+	}
+	return nil
+}
+
+// Specs returns mock specs declared in schema by field and scalar
+// coordinates. Directives of type extensions are included.
+func Specs(schema *ast.Document) (map[coordinate.Coordinate]Spec, error) {
+	specs := make(map[coordinate.Coordinate]Spec)
+	add := func(c coordinate.Coordinate, directives []*ast.Directive) error {
+		spec, ok, err := specOf(directives)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c, err)
+		}
+		if ok {
+			specs[c] = spec
+		}
+		return nil
+	}
+
+	var err error
+	coordinate.Walk(schema, func(c coordinate.Coordinate, node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FieldDefinition:
+			err = add(c, n.Directives)
+		case *ast.ScalarTypeDefinition:
+			err = add(c, n.Directives)
+		case *ast.EnumTypeDefinition:
+			err = add(c, n.Directives)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, def := range schema.Definitions {
+		switch d := def.(type) {
+		case *ast.ScalarTypeExtension:
+			err = add(coordinate.Type(d.Name.Value), d.Directives)
+		case *ast.EnumTypeExtension:
+			err = add(coordinate.Type(d.Name.Value), d.Directives)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return specs, nil
+}
+
+func specOf(directives []*ast.Directive) (Spec, bool, error) {
+	var spec Spec
+	found := false
+	for _, d := range directives {
+		switch d.Name.Value {
+		case "fake":
+			arg := argument(d, "type")
+			enum, ok := arg.(*ast.EnumValue)
+			if !ok {
+				return Spec{}, false, fmt.Errorf("@fake at %d: type must be a FakeType value", d.Pos())
+			}
+			if _, ok := fakers[FakeType(enum.Value)]; !ok {
+				return Spec{}, false, fmt.Errorf("@fake at %d: unknown type %s", d.Pos(), enum.Value)
+			}
+			spec.Fake = FakeType(enum.Value)
+			found = true
+		case "examples":
+			list, ok := argument(d, "values").(*ast.ListValue)
+			if !ok || len(list.Values) == 0 {
+				return Spec{}, false, fmt.Errorf("@examples at %d: values must be a non-empty list", d.Pos())
+			}
+			for _, v := range list.Values {
+				value, err := Literal(v)
+				if err != nil {
+					return Spec{}, false, fmt.Errorf("@examples at %d: %w", d.Pos(), err)
+				}
+				spec.Examples = append(spec.Examples, value)
+			}
+			found = true
+		}
+	}
+	return spec, found, nil
+}
+
+// Literal converts constant value to its JSON representation: numbers
+// become json.Number, enum values strings, lists []any and input objects
+// map[string]any.
+func Literal(v ast.Value) (any, error) {
+	switch v := v.(type) {
+	case *ast.NullValue:
+		return nil, nil
+	case *ast.IntValue:
+		return json.Number(v.Value), nil
+	case *ast.FloatValue:
+		return json.Number(v.Value), nil
+	case *ast.StringValue:
+		return v.Value, nil
+	case *ast.BooleanValue:
+		return v.Value, nil
+	case *ast.EnumValue:
+		return v.Value, nil
+	case *ast.ListValue:
+		list := make([]any, len(v.Values))
+		for i, item := range v.Values {
+			value, err := Literal(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case *ast.ObjectValue:
+		obj := make(map[string]any, len(v.Fields))
+		for _, f := range v.Fields {
+			value, err := Literal(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			obj[f.Name.Value] = value
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("value at %d is not constant", v.Pos())
+}
+
+func argument(d *ast.Directive, name string) ast.Value {
+	for _, arg := range d.Arguments {
+		if arg.Name.Value == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+)
+
+func TestSpecs(t *testing.T) {
+	specs, err := Specs(asttest.Parse(t, DirectivesSDL+`
+type User {
+  id: ID!
+  name: String @fake(type: NAME)
+  score: Float @examples(values: [1, 2.5])
+  tags: [String] @examples(values: [["a", "b"], null])
+}
+extend type User { address: Address @examples(values: [{ city: "Oslo", zip: 1 }]) }
+scalar Email @fake(type: EMAIL)
+enum Role { ADMIN EDITOR }
+extend enum Role @examples(values: [EDITOR])
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[coordinate.Coordinate]Spec{
+		coordinate.Member("User", "name"):    {Fake: FakeName},
+		coordinate.Member("User", "score"):   {Examples: []any{json.Number("1"), json.Number("2.5")}},
+		coordinate.Member("User", "tags"):    {Examples: []any{[]any{"a", "b"}, nil}},
+		coordinate.Member("User", "address"): {Examples: []any{map[string]any{"city": "Oslo", "zip": json.Number("1")}}},
+		coordinate.Type("Email"):             {Fake: FakeEmail},
+		coordinate.Type("Role"):              {Examples: []any{"EDITOR"}},
+	}
+	if !reflect.DeepEqual(specs, expected) {
+		t.Errorf("expected %v, got %v", expected, specs)
+	}
+}
+
+func TestSpecs_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown fake type", `type T { f: String @fake(type: COLOR) }`},
+		{"fake type as string", `type T { f: String @fake(type: "NAME") }`},
+		{"empty examples", `type T { f: String @examples(values: []) }`},
+		{"variable in examples", `scalar S @examples(values: [$v])`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Specs(asttest.Parse(t, tt.input)); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestSpec_Value(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	patterns := map[FakeType]string{
+		FakeName:     `^[A-Z][a-z]+ [A-Z][a-z]+$`,
+		FakeEmail:    `^[a-z]+\.[a-z]+@example\.com$`,
+		FakePhone:    `^\+1-555-\d{3}-\d{4}$`,
+		FakeUUID:     `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		FakeSentence: `^[A-Z][a-z ]+\.$`,
+		FakeDate:     `^\d{4}-\d{2}-\d{2}$`,
+		FakeDateTime: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`,
+	}
+	for fake, pattern := range patterns {
+		for range 20 {
+			v, _ := Spec{Fake: fake}.Value(r).(string)
+			if !regexp.MustCompile(pattern).MatchString(v) {
+				t.Errorf("%s: %q does not match %s", fake, v, pattern)
+			}
+		}
+	}
+
+	examples := Spec{Fake: FakeName, Examples: []any{"a", "b"}}
+	for range 20 {
+		if v := examples.Value(r); v != "a" && v != "b" {
+			t.Errorf("expected example value, got %v", v)
+		}
+	}
+	if v := (Spec{}).Value(r); v != nil {
+		t.Errorf("expected nil, got %v", v)
+	}
+}
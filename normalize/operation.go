@@ -0,0 +1,362 @@
+package normalize
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// ClientDirective marks fields and fragments resolved by clients, which
+// are removed from canonical forms along with their selections.
+const ClientDirective = "client"
+
+// clientDirectives are stripped from canonical forms by default, as
+// Apollo Client does before sending operations.
+var clientDirectives = []string{ClientDirective, ConnectionDirective, "export", "nonreactive", "unmask"}
+
+// Option configures Operation.
+type Option func(*normalizer)
+
+// WithInlineFragments replaces fragment spreads with inline fragments of
+// their definitions, so operations differing only in how selections are
+// split into fragments have the same canonical form.
+func WithInlineFragments() Option {
+	return func(n *normalizer) {
+		n.inline = true
+	}
+}
+
+// WithClientDirectives sets names of client-only directives stripped from
+// canonical forms, replacing the default client, connection, export,
+// nonreactive and unmask. Selections with ClientDirective are removed
+// only when it is one of names.
+func WithClientDirectives(names ...string) Option {
+	return func(n *normalizer) {
+		n.client = make(map[string]bool, len(names))
+		for _, name := range names {
+			n.client[name] = true
+		}
+	}
+}
+
+// Operation returns canonical form of operation name of doc, or of its
+// only operation when name is empty, for deduplicating persisted
+// queries:
+//
+//   - selections with @client are removed, as are fields and fragments
+//     left without selections, and other client-only directives are
+//     stripped;
+//   - arguments and input object fields are sorted by name;
+//   - variable definitions are sorted by name and unused ones removed;
+//   - duplicate fragment spreads are removed and fragments used by the
+//     operation follow it sorted by name, or are inlined with
+//     WithInlineFragments.
+//
+// Selections keep their order, which determines order of response
+// fields. doc is not modified; the result shares unmodified nodes with
+// it.
+func Operation(doc *ast.Document, name string, opts ...Option) (*ast.Document, error) {
+	n := &normalizer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		used:      make(map[string]*ast.FragmentDefinition),
+		visiting:  make(map[string]bool),
+	}
+	WithClientDirectives(clientDirectives...)(n)
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if name == "" && op != nil {
+				return nil, errors.New("operation name is required for documents with multiple operations")
+			}
+			if name == "" || def.Name != nil && def.Name.Value == name {
+				op = def
+			}
+		case *ast.FragmentDefinition:
+			n.fragments[def.Name.Value] = def
+		}
+	}
+	if op == nil {
+		if name == "" {
+			return nil, errors.New("document has no operations")
+		}
+		return nil, fmt.Errorf("operation %q not found", name)
+	}
+
+	normalized := *op
+	normalized.Directives = n.directives(op.Directives)
+	selections, err := n.selectionSet(op.SelectionSet)
+	if err != nil {
+		return nil, err
+	}
+	if selections == nil {
+		return nil, fmt.Errorf("operation at %d has only client selections", op.Pos())
+	}
+	normalized.SelectionSet = selections
+	normalized.VariableDefs = nil // Not to count as uses.
+
+	result := &ast.Document{Definitions: []ast.Definition{&normalized}}
+	for _, name := range slices.Sorted(maps.Keys(n.used)) {
+		if def := n.used[name]; def != nil {
+			result.Definitions = append(result.Definitions, def)
+		}
+	}
+	normalized.VariableDefs = usedVariables(op.VariableDefs, result)
+	return result, nil
+}
+
+// Print returns canonical source of doc returned by Operation: its
+// shortest source, as printer.PrintCompact prints it.
+func Print(doc *ast.Document) string {
+	return printer.PrintCompact(doc)
+}
+
+// Hash returns SHA-256 hash in hex of canonical source of doc, the
+// automatic persisted query hash of Print(doc), as computed by
+// client.PersistedQueryHash.
+func Hash(doc *ast.Document) string {
+	sum := sha256.Sum256([]byte(Print(doc)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Query returns canonical source and its hash of operation name of query.
+func Query(query, name string, opts ...Option) (source, hash string, err error) {
+	p, err := parser.New(lexer.New(query))
+	if err != nil {
+		return "", "", err
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		return "", "", err
+	}
+	if doc, err = Operation(doc, name, opts...); err != nil {
+		return "", "", err
+	}
+	source = Print(doc)
+	sum := sha256.Sum256([]byte(source))
+	return source, hex.EncodeToString(sum[:]), nil
+}
+
+// normalizer holds state of a single Operation call.
+type normalizer struct {
+	inline bool
+	client map[string]bool
+
+	fragments map[string]*ast.FragmentDefinition
+	used      map[string]*ast.FragmentDefinition // Normalized; nil when left empty.
+	visiting  map[string]bool                    // Fragments being normalized.
+}
+
+// selectionSet returns normalized ss, nil when no selections are left.
+func (n *normalizer) selectionSet(ss *ast.SelectionSet) (*ast.SelectionSet, error) {
+	var selections []ast.Selection
+	spreads := make(map[string]bool)
+	for _, sel := range ss.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if n.isClient(sel.Directives) {
+				continue
+			}
+			f := *sel
+			f.Arguments = sortedArguments(sel.Arguments)
+			f.Directives = n.directives(sel.Directives)
+			if sel.SelectionSet != nil {
+				ss, err := n.selectionSet(sel.SelectionSet)
+				if err != nil {
+					return nil, err
+				}
+				if ss == nil {
+					continue
+				}
+				f.SelectionSet = ss
+			}
+			selections = append(selections, &f)
+
+		case *ast.InlineFragment:
+			if n.isClient(sel.Directives) {
+				continue
+			}
+			ss, err := n.selectionSet(sel.SelectionSet)
+			if err != nil {
+				return nil, err
+			}
+			if ss == nil {
+				continue
+			}
+			f := *sel
+			f.Directives = n.directives(sel.Directives)
+			f.SelectionSet = ss
+			selections = append(selections, &f)
+
+		case *ast.FragmentSpread:
+			if n.isClient(sel.Directives) {
+				continue
+			}
+			def, err := n.fragment(sel)
+			if err != nil {
+				return nil, err
+			}
+			if def == nil {
+				continue
+			}
+			directives := n.directives(sel.Directives)
+			if n.inline {
+				selections = append(selections, &ast.InlineFragment{
+					Position:      sel.Position,
+					EndPosition:   sel.EndPosition,
+					TypeCondition: def.TypeCondition,
+					Directives:    directives,
+					SelectionSet:  def.SelectionSet,
+				})
+				continue
+			}
+			if len(directives) == 0 {
+				if spreads[sel.Name.Value] {
+					continue
+				}
+				spreads[sel.Name.Value] = true
+			}
+			s := *sel
+			s.Directives = directives
+			selections = append(selections, &s)
+		}
+	}
+	if len(selections) == 0 {
+		return nil, nil
+	}
+	return &ast.SelectionSet{Position: ss.Position, EndPosition: ss.EndPosition, Selections: selections}, nil
+}
+
+// fragment returns normalized definition of fragment spread by s, nil
+// when no selections are left in it.
+func (n *normalizer) fragment(s *ast.FragmentSpread) (*ast.FragmentDefinition, error) {
+	name := s.Name.Value
+	if def, ok := n.used[name]; ok {
+		return def, nil
+	}
+	def, ok := n.fragments[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fragment %q at %d", name, s.Pos())
+	}
+	if n.visiting[name] {
+		return nil, fmt.Errorf("fragment %q at %d spreads itself", name, s.Pos())
+	}
+	n.visiting[name] = true
+	defer delete(n.visiting, name)
+
+	ss, err := n.selectionSet(def.SelectionSet)
+	if err != nil {
+		return nil, err
+	}
+	var normalized *ast.FragmentDefinition
+	if ss != nil {
+		d := *def
+		d.Directives = n.directives(def.Directives)
+		d.SelectionSet = ss
+		normalized = &d
+	}
+	if !n.inline {
+		n.used[name] = normalized
+	}
+	return normalized, nil
+}
+
+// isClient reports whether directives mark selection as resolved by
+// client.
+func (n *normalizer) isClient(directives []*ast.Directive) bool {
+	return n.client[ClientDirective] && directive(directives, ClientDirective) != nil
+}
+
+// directives returns directives without client-only ones, their
+// arguments sorted. Order of directives is kept, as it may be
+// significant.
+func (n *normalizer) directives(directives []*ast.Directive) []*ast.Directive {
+	var result []*ast.Directive
+	for _, d := range directives {
+		if n.client[d.Name.Value] {
+			continue
+		}
+		copied := *d
+		copied.Arguments = sortedArguments(d.Arguments)
+		result = append(result, &copied)
+	}
+	return result
+}
+
+// sortedArguments returns args sorted by name, with sorted input objects.
+func sortedArguments(args []*ast.Argument) []*ast.Argument {
+	if len(args) == 0 {
+		return args
+	}
+	result := make([]*ast.Argument, len(args))
+	for i, arg := range args {
+		copied := *arg
+		copied.Value = sortedValue(arg.Value)
+		result[i] = &copied
+	}
+	slices.SortStableFunc(result, func(a, b *ast.Argument) int {
+		return cmp.Compare(a.Name.Value, b.Name.Value)
+	})
+	return result
+}
+
+// sortedValue returns v with fields of input objects sorted by name.
+func sortedValue(v ast.Value) ast.Value {
+	switch v := v.(type) {
+	case *ast.ListValue:
+		list := *v
+		list.Values = make([]ast.Value, len(v.Values))
+		for i, item := range v.Values {
+			list.Values[i] = sortedValue(item)
+		}
+		return &list
+	case *ast.ObjectValue:
+		obj := *v
+		obj.Fields = make([]*ast.ObjectField, len(v.Fields))
+		for i, f := range v.Fields {
+			copied := *f
+			copied.Value = sortedValue(f.Value)
+			obj.Fields[i] = &copied
+		}
+		slices.SortStableFunc(obj.Fields, func(a, b *ast.ObjectField) int {
+			return cmp.Compare(a.Name.Value, b.Name.Value)
+		})
+		return &obj
+	}
+	return v
+}
+
+// usedVariables returns definitions of variables used in doc sorted by
+// name.
+func usedVariables(defs []*ast.VariableDefinition, doc *ast.Document) []*ast.VariableDefinition {
+	used := make(map[string]bool)
+	ast.WalkDocument(ast.VisitorFuncs{EnterFunc: func(n ast.Node) ast.Action {
+		if v, ok := n.(*ast.Variable); ok {
+			used[v.Name.Value] = true
+		}
+		return ast.Continue
+	}}, doc)
+	var result []*ast.VariableDefinition
+	for _, def := range defs {
+		if used[def.Variable.Name.Value] {
+			result = append(result, def)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b *ast.VariableDefinition) int {
+		return cmp.Compare(a.Variable.Name.Value, b.Variable.Name.Value)
+	})
+	return result
+}
@@ -0,0 +1,140 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/client"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opName   string
+		opts     []Option
+		expected string
+	}{
+		{
+			"Sorted arguments",
+			`{ posts(where: { b: 1, a: [{ d: 1, c: 2 }] }, first: 10) @lang(to: "en", from: "de") { id } }`,
+			"", nil,
+			`{posts(first:10 where:{a:[{c:2 d:1}]b:1})@lang(from:"de"to:"en"){id}}`,
+		},
+		{
+			"Sorted and used variables",
+			`query Q($z: Int, $unused: ID, $a: String) { posts(first: $z, after: $a) { id } }`,
+			"", nil,
+			`query Q($a:String$z:Int){posts(after:$a first:$z){id}}`,
+		},
+		{
+			"Client-only directives",
+			`query Q($id: ID!) { me { id local @client { x } feed @connection(key: "feed") { id } } ... @client { y } }`,
+			"", nil,
+			`query Q{me{id feed{id}}}`,
+		},
+		{
+			"Fields left without selections",
+			`{ me { settings { theme @client } id } }`,
+			"", nil,
+			`{me{id}}`,
+		},
+		{
+			"Custom client directives",
+			`{ me @client @local { id } }`,
+			"", []Option{WithClientDirectives("local")},
+			`{me@client{id}}`,
+		},
+		{
+			"Selected operation",
+			`query A { a } query B { ...F } fragment F on Query { b } fragment G on Query { c }`,
+			"B", nil,
+			`query B{...F}fragment F on Query{b}`,
+		},
+		{
+			"Sorted and deduplicated fragments",
+			`{ ...B ...A ...B ...B @include(if: true) } fragment B on Query { b ...A } fragment A on Query { a }`,
+			"", nil,
+			`{...B...A...B@include(if:true)}fragment A on Query{a}fragment B on Query{b...A}`,
+		},
+		{
+			"Client fragments",
+			`{ a ...C } fragment C on Query { c @client }`,
+			"", nil,
+			`{a}`,
+		},
+		{
+			"Inline fragments",
+			`{ ...B @include(if: true) } fragment B on Query { b(y: 1, x: 2) ...A } fragment A on Query { a }`,
+			"", []Option{WithInlineFragments()},
+			`{...on Query@include(if:true){b(x:2 y:1)...on Query{a}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Operation(asttest.Parse(t, tt.input), tt.opName, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := printer.PrintCompact(doc); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOperation_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opName   string
+		expected string
+	}{
+		{"No operations", `fragment F on Query { a }`, "", `document has no operations`},
+		{"Missing name", `query A { a } query B { b }`, "", `operation name is required for documents with multiple operations`},
+		{"Unknown operation", `query A { a }`, "B", `operation "B" not found`},
+		{"Unknown fragment", `{ ...F }`, "", `unknown fragment "F" at 2`},
+		{"Fragment cycle", `{ ...F } fragment F on Query { ...G } fragment G on Query { ...F }`, "", `fragment "F" at 60 spreads itself`},
+		{"Client operation", `{ a @client }`, "", `operation at 0 has only client selections`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Operation(asttest.Parse(t, tt.input), tt.opName)
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tt.expected)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	a, hashA, err := Query(`query Q($b: Int, $a: Int) { posts(b: $b, a: $a) { id @client, title } }`, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, hashB, err := Query("query Q($a: Int $b: Int) {\n  posts(a: $a b: $b) { title }\n}", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a != b || hashA != hashB {
+		t.Errorf("expected equal canonical forms, got %s (%s) and %s (%s)", a, hashA, b, hashB)
+	}
+	if expected := client.PersistedQueryHash(a); hashA != expected {
+		t.Errorf("expected hash %s, got %s", expected, hashA)
+	}
+	doc, err := Operation(asttest.Parse(t, a), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := Hash(doc); got != hashA {
+		t.Errorf("expected hash %s, got %s", hashA, got)
+	}
+
+	if _, _, err := Query(`{`, ""); err == nil {
+		t.Error("expected syntax error, got none")
+	}
+}
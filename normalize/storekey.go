@@ -0,0 +1,185 @@
+// Package normalize computes canonical forms of operations and the keys
+// normalized caches store field values under.
+package normalize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// ConnectionDirective is the client directive overriding store key of a
+// paginated field: @connection(key: "feed", filter: ["type"]).
+const ConnectionDirective = "connection"
+
+// knownDirectives are not part of store keys, as Apollo Client does.
+var knownDirectives = map[string]bool{
+	ConnectionDirective: true,
+	"include":           true,
+	"skip":              true,
+	"client":            true,
+	"rest":              true,
+	"export":            true,
+}
+
+// StoreKey returns key value of field f is stored under in a normalized
+// cache, compatible with Apollo Client:
+//
+//	posts                       no arguments
+//	posts({"first":10})         arguments, keys sorted
+//	feed({"type":"top"})        @connection(key: "feed", filter: ["type"])
+//	feed                        @connection(key: "feed")
+//	posts@lang({"to":"en"})     other directives
+//
+// Arguments bound to variables missing from variables are omitted.
+func StoreKey(f *ast.Field, variables map[string]any) (string, error) {
+	args := arguments(f.Arguments, variables)
+
+	if conn := directive(f.Directives, ConnectionDirective); conn != nil {
+		key, filter, err := connection(conn)
+		if err != nil {
+			return "", err
+		}
+		if len(filter) == 0 {
+			return key, nil
+		}
+		filtered := make(map[string]any, len(filter))
+		for _, name := range filter {
+			if v, ok := args[name]; ok {
+				filtered[name] = v
+			}
+		}
+		return withArgs(key, filtered)
+	}
+
+	var b strings.Builder
+	key, err := withArgs(f.Name.Value, args)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString(key)
+	for _, d := range f.Directives {
+		if knownDirectives[d.Name.Value] {
+			continue
+		}
+		key, err := withArgs("@"+d.Name.Value, arguments(d.Arguments, variables))
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(key)
+	}
+	return b.String(), nil
+}
+
+func withArgs(name string, args map[string]any) (string, error) {
+	if len(args) == 0 {
+		return name, nil
+	}
+	s, err := stringify(args)
+	if err != nil {
+		return "", err
+	}
+	return name + "(" + s + ")", nil
+}
+
+func connection(d *ast.Directive) (key string, filter []string, err error) {
+	for _, arg := range d.Arguments {
+		switch arg.Name.Value {
+		case "key":
+			s, ok := arg.Value.(*ast.StringValue)
+			if !ok {
+				return "", nil, fmt.Errorf("@%s key at %d must be a string", ConnectionDirective, arg.Value.Pos())
+			}
+			key = s.Value
+		case "filter":
+			list, ok := arg.Value.(*ast.ListValue)
+			if !ok {
+				return "", nil, fmt.Errorf("@%s filter at %d must be a list of strings", ConnectionDirective, arg.Value.Pos())
+			}
+			for _, item := range list.Values {
+				s, ok := item.(*ast.StringValue)
+				if !ok {
+					return "", nil, fmt.Errorf("@%s filter at %d must be a list of strings", ConnectionDirective, item.Pos())
+				}
+				filter = append(filter, s.Value)
+			}
+		}
+	}
+	if key == "" {
+		return "", nil, fmt.Errorf("@%s at %d: missing key argument", ConnectionDirective, d.Pos())
+	}
+	return key, filter, nil
+}
+
+func directive(directives []*ast.Directive, name string) *ast.Directive {
+	for _, d := range directives {
+		if d.Name.Value == name {
+			return d
+		}
+	}
+	return nil
+}
+
+func arguments(args []*ast.Argument, variables map[string]any) map[string]any {
+	result := make(map[string]any, len(args))
+	for _, arg := range args {
+		if v, ok := value(arg.Value, variables); ok {
+			result[arg.Name.Value] = v
+		}
+	}
+	return result
+}
+
+// value converts v to JSON value. It reports false for variables missing
+// from variables.
+func value(v ast.Value, variables map[string]any) (any, bool) {
+	switch v := v.(type) {
+	case *ast.Variable:
+		value, ok := variables[v.Name.Value]
+		return value, ok
+	case *ast.IntValue:
+		return json.Number(v.Value), true
+	case *ast.FloatValue:
+		return json.Number(v.Value), true
+	case *ast.StringValue:
+		return v.Value, true
+	case *ast.BooleanValue:
+		return v.Value, true
+	case *ast.EnumValue:
+		return v.Value, true
+	case *ast.ListValue:
+		list := make([]any, 0, len(v.Values))
+		for _, item := range v.Values {
+			value, ok := value(item, variables)
+			if !ok {
+				value = nil
+			}
+			list = append(list, value)
+		}
+		return list, true
+	case *ast.ObjectValue:
+		obj := make(map[string]any, len(v.Fields))
+		for _, f := range v.Fields {
+			if value, ok := value(f.Value, variables); ok {
+				obj[f.Name.Value] = value
+			}
+		}
+		return obj, true
+	}
+	return nil, true
+}
+
+// stringify encodes v as JSON with object keys sorted and without HTML
+// escaping, as JSON.stringify does.
+func stringify(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
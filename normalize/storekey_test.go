@@ -0,0 +1,58 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func firstField(t *testing.T, input string) *ast.Field {
+	t.Helper()
+	op := asttest.Parse(t, input).Definitions[0].(*ast.OperationDefinition)
+	return op.SelectionSet.Selections[0].(*ast.Field)
+}
+
+func TestStoreKey(t *testing.T) {
+	variables := map[string]any{"first": 10, "type": "top"}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"No arguments", `{ posts }`, `posts`},
+		{"Alias is ignored", `{ p: posts }`, `posts`},
+		{"Sorted arguments", `{ posts(z: 1, a: "<b>", e: DESC) }`, `posts({"a":"<b>","e":"DESC","z":1})`},
+		{"Nested objects", `{ posts(where: { b: [1, null], a: true }) }`, `posts({"where":{"a":true,"b":[1,null]}})`},
+		{"Variables", `{ posts(first: $first, after: $after) }`, `posts({"first":10})`},
+		{"Connection key", `{ posts(first: $first) @connection(key: "feed") }`, `feed`},
+		{"Connection filter", `{ posts(first: $first, type: $type) @connection(key: "feed", filter: ["type", "missing"]) }`, `feed({"type":"top"})`},
+		{"Connection empty filter", `{ posts(type: $type) @connection(key: "feed", filter: []) }`, `feed`},
+		{"Known directives", `{ posts @include(if: true) @client }`, `posts`},
+		{"Other directives", `{ posts(first: 1) @lang(to: "en") @cached }`, `posts({"first":1})@lang({"to":"en"})@cached`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := StoreKey(firstField(t, tt.input), variables)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestStoreKey_InvalidConnection(t *testing.T) {
+	for _, input := range []string{
+		`{ posts @connection }`,
+		`{ posts @connection(key: 1) }`,
+		`{ posts @connection(key: "feed", filter: "type") }`,
+		`{ posts @connection(key: "feed", filter: [type]) }`,
+	} {
+		if _, err := StoreKey(firstField(t, input), nil); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
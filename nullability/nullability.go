@@ -0,0 +1,235 @@
+// Package nullability reports non-null fields that fail in practice, so
+// that they can be migrated to @semanticNonNull of the semantic
+// nullability RFC before an error of one of them nulls out its parents:
+//
+//	usage := make(nullability.Usage)
+//	for _, r := range traces {
+//		usage.Observe(s, r.Document, r.Operation, r.Response)
+//	}
+//	report := nullability.Analyze(s, usage, nullability.Options{MinErrorRate: 0.001})
+//	fmt.Print(printer.Print(report.Annotate(schemaDoc)))
+//
+// https://github.com/graphql/graphql-wg/blob/main/rfcs/SemanticNullability.md
+package nullability
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// DirectiveSDL defines @semanticNonNull. Level 0 is the field itself,
+// level 1 items of its list, and so on.
+const DirectiveSDL = `directive @semanticNonNull(levels: [Int] = [0]) on FIELD_DEFINITION`
+
+var directiveDefinition = sync.OnceValue(func() *ast.DirectiveDefinition {
+	p, err := parser.New(lexer.New(DirectiveSDL))
+	if err != nil {
+		panic(err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		panic(err)
+	}
+	return doc.Definitions[0].(*ast.DirectiveDefinition)
+})
+
+// Options configure Analyze.
+type Options struct {
+	// MayError reports whether resolver of field is known to fail, e.g.
+	// because it calls a remote service, regardless of usage.
+	MayError func(field coordinate.Coordinate) bool
+
+	// MinErrorRate is ratio of errors to requests a field must reach to
+	// be reported. Any error is enough when zero.
+	MinErrorRate float64
+}
+
+// Report lists non-null fields to relax in schema order.
+type Report struct {
+	Fields []*Field
+}
+
+// Field is a non-null field of an object or interface type that fails.
+type Field struct {
+	Coordinate coordinate.Coordinate
+	Type       ast.Type // Declared type.
+	Proposed   ast.Type // Type without non-null wrappers.
+	Levels     []int    // Non-null levels of Type, levels of @semanticNonNull.
+	Stats
+
+	Static  bool // Reported by MayError.
+	Implied bool // Relaxed because a field implementing it is.
+}
+
+// Analyze reports non-null fields of s that errors were observed for in
+// usage, or that MayError reports. Interface fields implemented by
+// reported fields are reported as implied, so that relaxed schema stays
+// valid. Types and fields of the introspection system are not reported.
+func Analyze(s *schema.Schema, usage Usage, opts Options) *Report {
+	reported := make(map[coordinate.Coordinate]*Field)
+	var queue []*schema.Type
+	for _, t := range s.Types() {
+		if t.Kind != schema.KindObject && t.Kind != schema.KindInterface || strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		for _, f := range t.Fields {
+			c := coordinate.Member(t.Name, f.Name)
+			levels := nonNullLevels(f.Type)
+			if len(levels) == 0 || strings.HasPrefix(f.Name, "__") {
+				continue
+			}
+			field := &Field{Coordinate: c, Type: f.Type, Proposed: nullable(f.Type), Levels: levels}
+			if stats := usage[c]; stats != nil {
+				field.Stats = *stats
+			}
+			field.Static = opts.MayError != nil && opts.MayError(c)
+			failing := field.Errors > 0 && float64(field.Errors) >= opts.MinErrorRate*float64(field.Requests)
+			if failing || field.Static {
+				reported[c] = field
+				queue = append(queue, t)
+			}
+		}
+	}
+
+	// Fields of interfaces cannot be non-null when fields implementing
+	// them are not.
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for _, name := range t.Interfaces {
+			i := s.Type(name)
+			if i == nil {
+				continue
+			}
+			for _, f := range t.Fields {
+				c := coordinate.Member(i.Name, f.Name)
+				implemented := i.Field(f.Name)
+				if reported[coordinate.Member(t.Name, f.Name)] == nil || reported[c] != nil || implemented == nil {
+					continue
+				}
+				if levels := nonNullLevels(implemented.Type); len(levels) > 0 {
+					field := &Field{Coordinate: c, Type: implemented.Type, Proposed: nullable(implemented.Type), Levels: levels, Implied: true}
+					if stats := usage[c]; stats != nil {
+						field.Stats = *stats
+					}
+					reported[c] = field
+					queue = append(queue, i)
+				}
+			}
+		}
+	}
+
+	r := &Report{}
+	for _, t := range s.Types() {
+		for _, f := range t.Fields {
+			if field := reported[coordinate.Member(t.Name, f.Name)]; field != nil {
+				r.Fields = append(r.Fields, field)
+			}
+		}
+	}
+	return r
+}
+
+// Annotate returns copy of schema doc with types of reported fields
+// replaced by their proposed types annotated with @semanticNonNull, and
+// with definition of the directive added unless doc defines it. Fields
+// are looked up in type definitions and extensions. doc is not modified.
+func (r *Report) Annotate(doc *ast.Document) *ast.Document {
+	fields := make(map[coordinate.Coordinate]*Field, len(r.Fields))
+	for _, f := range r.Fields {
+		fields[f.Coordinate] = f
+	}
+	annotate := func(typeName *ast.Name, defs []*ast.FieldDefinition) []*ast.FieldDefinition {
+		result := slices.Clone(defs)
+		for i, def := range result {
+			if f := fields[coordinate.Member(typeName.Value, def.Name.Value)]; f != nil {
+				annotated := *def
+				annotated.Type = f.Proposed
+				annotated.Directives = append(slices.Clip(def.Directives), directive(f.Levels))
+				result[i] = &annotated
+			}
+		}
+		return result
+	}
+
+	result := &ast.Document{Definitions: make([]ast.Definition, len(doc.Definitions))}
+	defined := false
+	for i, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			o := *d
+			o.Fields = annotate(d.Name, d.Fields)
+			def = &o
+		case *ast.ObjectTypeExtension:
+			o := *d
+			o.Fields = annotate(d.Name, d.Fields)
+			def = &o
+		case *ast.InterfaceTypeDefinition:
+			i := *d
+			i.Fields = annotate(d.Name, d.Fields)
+			def = &i
+		case *ast.InterfaceTypeExtension:
+			i := *d
+			i.Fields = annotate(d.Name, d.Fields)
+			def = &i
+		case *ast.DirectiveDefinition:
+			defined = defined || d.Name.Value == "semanticNonNull"
+		}
+		result.Definitions[i] = def
+	}
+	if !defined && len(r.Fields) > 0 {
+		result.Definitions = append(result.Definitions, directiveDefinition())
+	}
+	return result
+}
+
+// directive returns @semanticNonNull application for levels, leaving out
+// the default levels argument.
+func directive(levels []int) *ast.Directive {
+	d := &ast.Directive{Name: &ast.Name{Value: "semanticNonNull"}}
+	if slices.Equal(levels, []int{0}) {
+		return d
+	}
+	list := &ast.ListValue{}
+	for _, level := range levels {
+		list.Values = append(list.Values, &ast.IntValue{Value: strconv.Itoa(level)})
+	}
+	d.Arguments = []*ast.Argument{{Name: &ast.Name{Value: "levels"}, Value: list}}
+	return d
+}
+
+// nonNullLevels returns levels of non-null wrappers of t.
+func nonNullLevels(t ast.Type) []int {
+	var levels []int
+	for level := 0; ; {
+		switch w := t.(type) {
+		case *ast.NonNullType:
+			levels = append(levels, level)
+			t = w.Type
+		case *ast.ListType:
+			level++
+			t = w.Type
+		default:
+			return levels
+		}
+	}
+}
+
+// nullable returns t without non-null wrappers.
+func nullable(t ast.Type) ast.Type {
+	switch w := t.(type) {
+	case *ast.NonNullType:
+		return nullable(w.Type)
+	case *ast.ListType:
+		return &ast.ListType{Type: nullable(w.Type)}
+	}
+	return t
+}
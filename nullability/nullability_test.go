@@ -0,0 +1,154 @@
+package nullability
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+const testSDL = `
+type Query { user(id: ID!): User! users: [User!]! node: Node search: [Result!] }
+interface Node { id: ID! }
+type User implements Node { id: ID! name: String! friends: [User!]! avatar: String }
+type Post implements Node { id: ID! title: String! }
+union Result = User | Post
+`
+
+// summary returns coordinates of fields of r with their flags.
+func summary(r *Report) []string {
+	var result []string
+	for _, f := range r.Fields {
+		s := f.Coordinate.String()
+		if f.Static {
+			s += " static"
+		}
+		if f.Implied {
+			s += " implied"
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func TestAnalyze(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	tests := []struct {
+		name     string
+		usage    Usage
+		opts     Options
+		expected []string
+	}{
+		{
+			name: "without errors",
+			usage: Usage{
+				coordinate.Member("Query", "user"): {Requests: 10},
+				coordinate.Member("User", "name"):  {Requests: 10},
+			},
+		},
+		{
+			name: "errors",
+			usage: Usage{
+				coordinate.Member("Query", "user"):   {Requests: 10, Errors: 1},
+				coordinate.Member("User", "name"):    {Requests: 10, Errors: 2},
+				coordinate.Member("User", "avatar"):  {Requests: 10, Errors: 5},
+				coordinate.Member("Query", "search"): {Requests: 10, Errors: 1},
+			},
+			expected: []string{"Query.user", "Query.search", "User.name"},
+		},
+		{
+			name: "error rate",
+			usage: Usage{
+				coordinate.Member("Query", "user"): {Requests: 1000, Errors: 1},
+				coordinate.Member("User", "name"):  {Requests: 1000, Errors: 10},
+			},
+			opts:     Options{MinErrorRate: 0.01},
+			expected: []string{"User.name"},
+		},
+		{
+			name: "implemented interface fields",
+			usage: Usage{
+				coordinate.Member("Post", "id"): {Requests: 10, Errors: 1},
+			},
+			expected: []string{"Node.id implied", "Post.id"},
+		},
+		{
+			name: "static",
+			opts: Options{MayError: func(field coordinate.Coordinate) bool {
+				return field.Name == "User" || field == coordinate.Member("Query", "node")
+			}},
+			expected: []string{"Node.id implied", "User.id static", "User.name static", "User.friends static"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summary(Analyze(s, tt.usage, tt.opts))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAnalyze_Field(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	usage := Usage{coordinate.Member("User", "friends"): {Requests: 4, Errors: 1}}
+	r := Analyze(s, usage, Options{})
+	if len(r.Fields) != 1 {
+		t.Fatalf("expected 1 field, got %d", len(r.Fields))
+	}
+	f := r.Fields[0]
+	if got := printer.Print(f.Proposed); got != "[User]" {
+		t.Errorf("expected proposed type [User], got %s", got)
+	}
+	if !reflect.DeepEqual(f.Levels, []int{0, 1}) {
+		t.Errorf("expected levels [0 1], got %v", f.Levels)
+	}
+	if f.Stats != (Stats{Requests: 4, Errors: 1}) {
+		t.Errorf("expected stats %+v, got %+v", Stats{Requests: 4, Errors: 1}, f.Stats)
+	}
+}
+
+func TestReport_Annotate(t *testing.T) {
+	doc := asttest.Parse(t, `
+type Query { user: User! }
+type User { id: ID! }
+extend type User { friends: [User!]! @deprecated }
+`)
+	s, err := schema.FromDocument(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := Analyze(s, Usage{
+		coordinate.Member("Query", "user"):   {Requests: 1, Errors: 1},
+		coordinate.Member("User", "friends"): {Requests: 1, Errors: 1},
+	}, Options{})
+	expected := `type Query {
+  user: User @semanticNonNull
+}
+
+type User {
+  id: ID!
+}
+
+extend type User {
+  friends: [User] @deprecated @semanticNonNull(levels: [0, 1])
+}
+
+directive @semanticNonNull(levels: [Int] = [0]) on FIELD_DEFINITION`
+	if got := printer.PrintDocument(r.Annotate(doc)); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+	if got := printer.PrintDocument(doc); got == expected {
+		t.Error("expected document not to be modified")
+	}
+
+	defined := asttest.Parse(t, "type Query { user: User! } type User { id: ID! }\n"+DirectiveSDL)
+	got := printer.PrintCompact(r.Annotate(defined))
+	if expected := "type Query{user:User@semanticNonNull}type User{id:ID!}directive@semanticNonNull(levels:[Int]=[0])on FIELD_DEFINITION"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,229 @@
+package nullability
+
+import (
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/response"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Stats of a field.
+type Stats struct {
+	Requests int // Responses to operations selecting the field.
+	Errors   int // Responses with errors of the field.
+}
+
+// Usage is observed stats of fields by coordinate. It can be filled by
+// Observe or from field stats of traces of other tools.
+type Usage map[coordinate.Coordinate]*Stats
+
+// Observe counts fields selected by op and fields errors of resp are
+// located at, where resp is response of op executed on s. Fragments of op
+// are looked up in doc. Fields are counted once per response, on the
+// type they are selected on; errors of fields selected in fragments on
+// different types are attributed using __typename of the result when it
+// is selected. Errors without path are not counted.
+func (u Usage) Observe(s *schema.Schema, doc *ast.Document, op *ast.OperationDefinition, resp *response.Response) {
+	o := &observer{schema: s, fragments: make(map[string]*ast.FragmentDefinition)}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			o.fragments[f.Name.Value] = f
+		}
+	}
+	root := s.RootType(op.OperationType)
+	if root == nil {
+		return
+	}
+
+	selected := make(map[coordinate.Coordinate]bool)
+	visited := make(map[string]bool)
+	var walk func(set *ast.SelectionSet, parent string)
+	walk = func(set *ast.SelectionSet, parent string) {
+		for _, sel := range set.Selections {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				t := s.Type(parent)
+				if t == nil || strings.HasPrefix(sel.Name.Value, "__") {
+					continue
+				}
+				f := t.Field(sel.Name.Value)
+				if f == nil {
+					continue
+				}
+				selected[coordinate.Member(parent, f.Name)] = true
+				if sel.SelectionSet != nil {
+					if named := s.NamedType(f.Type); named != nil {
+						walk(sel.SelectionSet, named.Name)
+					}
+				}
+			case *ast.InlineFragment:
+				walk(sel.SelectionSet, typeCondition(sel.TypeCondition, parent))
+			case *ast.FragmentSpread:
+				f := o.fragments[sel.Name.Value]
+				if f != nil && !visited[f.Name.Value] {
+					visited[f.Name.Value] = true
+					walk(f.SelectionSet, typeCondition(f.TypeCondition, parent))
+				}
+			}
+		}
+	}
+	walk(op.SelectionSet, root.Name)
+
+	failed := make(map[coordinate.Coordinate]bool)
+	for _, e := range resp.Errors {
+		if c, ok := o.locate(root.Name, op.SelectionSet, resp.Data, e.Path); ok {
+			failed[c] = true
+		}
+	}
+
+	for c := range selected {
+		u.stats(c).Requests++
+	}
+	for c := range failed {
+		u.stats(c).Errors++
+	}
+}
+
+func (u Usage) stats(c coordinate.Coordinate) *Stats {
+	stats := u[c]
+	if stats == nil {
+		stats = &Stats{}
+		u[c] = stats
+	}
+	return stats
+}
+
+type observer struct {
+	schema    *schema.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// candidate is a field selected under a response key.
+type candidate struct {
+	parent string // Type the field is selected on.
+	field  *ast.Field
+}
+
+// locate returns coordinate of field at path of data, which is result of
+// selection set set on type typeName.
+func (o *observer) locate(typeName string, set *ast.SelectionSet, data any, path []any) (coordinate.Coordinate, bool) {
+	sets := []*ast.SelectionSet{set}
+	for i, elem := range path {
+		switch elem := elem.(type) {
+		case int:
+			if list, ok := data.([]any); ok && elem >= 0 && elem < len(list) {
+				data = list[elem]
+			} else {
+				data = nil
+			}
+			continue
+		case string:
+			var candidates []candidate
+			visited := make(map[string]bool)
+			for _, set := range sets {
+				candidates = o.collect(candidates, visited, set, typeName, elem)
+			}
+			obj, _ := data.(*response.Object)
+			if obj != nil {
+				if runtime, ok := obj.Get("__typename"); ok {
+					candidates = o.filter(candidates, runtime)
+				}
+			}
+			if len(candidates) == 0 {
+				return coordinate.Coordinate{}, false
+			}
+			first := candidates[0]
+			t := o.schema.Type(first.parent)
+			if t == nil {
+				return coordinate.Coordinate{}, false
+			}
+			f := t.Field(first.field.Name.Value)
+			if f == nil {
+				return coordinate.Coordinate{}, false
+			}
+			if i == len(path)-1 {
+				return coordinate.Member(t.Name, f.Name), true
+			}
+			named := o.schema.NamedType(f.Type)
+			if named == nil {
+				return coordinate.Coordinate{}, false
+			}
+			typeName = named.Name
+			sets = sets[:0:0]
+			for _, c := range candidates {
+				if c.field.Name.Value == f.Name && c.field.SelectionSet != nil {
+					sets = append(sets, c.field.SelectionSet)
+				}
+			}
+			data = nil
+			if obj != nil {
+				data, _ = obj.Get(elem)
+			}
+		default:
+			return coordinate.Coordinate{}, false
+		}
+	}
+	return coordinate.Coordinate{}, false
+}
+
+// collect appends fields of set selected on parent under response key.
+func (o *observer) collect(candidates []candidate, visited map[string]bool, set *ast.SelectionSet, parent, key string) []candidate {
+	for _, sel := range set.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if responseKey(sel) == key {
+				candidates = append(candidates, candidate{parent: parent, field: sel})
+			}
+		case *ast.InlineFragment:
+			candidates = o.collect(candidates, visited, sel.SelectionSet, typeCondition(sel.TypeCondition, parent), key)
+		case *ast.FragmentSpread:
+			f := o.fragments[sel.Name.Value]
+			if f != nil && !visited[f.Name.Value] {
+				visited[f.Name.Value] = true
+				candidates = o.collect(candidates, visited, f.SelectionSet, typeCondition(f.TypeCondition, parent), key)
+			}
+		}
+	}
+	return candidates
+}
+
+// filter keeps candidates selected on types applying to runtime type
+// name, unless none are.
+func (o *observer) filter(candidates []candidate, name any) []candidate {
+	runtime, _ := name.(string)
+	var result []candidate
+	for _, c := range candidates {
+		if c.parent == runtime {
+			result = append(result, c)
+			continue
+		}
+		if t := o.schema.Type(c.parent); t != nil && t.IsAbstract() {
+			for _, p := range o.schema.PossibleTypes(t) {
+				if p.Name == runtime {
+					result = append(result, c)
+					break
+				}
+			}
+		}
+	}
+	if len(result) == 0 {
+		return candidates
+	}
+	return result
+}
+
+func typeCondition(condition *ast.NamedType, parent string) string {
+	if condition == nil {
+		return parent
+	}
+	return condition.Name.Value
+}
+
+func responseKey(f *ast.Field) string {
+	if f.Alias != nil {
+		return f.Alias.Value
+	}
+	return f.Name.Value
+}
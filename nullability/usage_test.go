@@ -0,0 +1,106 @@
+package nullability
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/response"
+)
+
+func TestUsage_Observe(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	tests := []struct {
+		name     string
+		query    string
+		response string
+		expected Usage
+	}{
+		{
+			name:     "selected fields",
+			query:    `{ user(id: 1) { __typename id ...F } } fragment F on User { name friends { id } }`,
+			response: `{"data":{"user":{"__typename":"User","id":"1","name":"A","friends":[]}}}`,
+			expected: Usage{
+				coordinate.Member("Query", "user"):   {Requests: 1},
+				coordinate.Member("User", "id"):      {Requests: 1},
+				coordinate.Member("User", "name"):    {Requests: 1},
+				coordinate.Member("User", "friends"): {Requests: 1},
+			},
+		},
+		{
+			name:     "errors",
+			query:    `{ me: user(id: 1) { name friends { name } } }`,
+			response: `{"data":{"me":null},"errors":[{"message":"a","path":["me","name"]},{"message":"b","path":["me","friends",0,"name"]},{"message":"c","path":["me","friends",1,"name"]},{"message":"d"}]}`,
+			expected: Usage{
+				coordinate.Member("Query", "user"):   {Requests: 1},
+				coordinate.Member("User", "name"):    {Requests: 1, Errors: 1},
+				coordinate.Member("User", "friends"): {Requests: 1},
+			},
+		},
+		{
+			name:     "abstract types",
+			query:    `{ search { ... on User { title: name } ... on Post { title } } }`,
+			response: `{"data":{"search":[{"__typename":"Post","title":null},null]},"errors":[{"message":"a","path":["search",0,"title"]},{"message":"b","path":["search",1,"title"]}]}`,
+			expected: Usage{
+				coordinate.Member("Query", "search"): {Requests: 1},
+				coordinate.Member("User", "name"):    {Requests: 1, Errors: 1},
+				coordinate.Member("Post", "title"):   {Requests: 1, Errors: 1},
+			},
+		},
+		{
+			name:     "interface fields",
+			query:    `{ node { id } }`,
+			response: `{"data":{"node":null},"errors":[{"message":"a","path":["node","id"]},{"message":"b","path":["unknown"]}]}`,
+			expected: Usage{
+				coordinate.Member("Query", "node"): {Requests: 1},
+				coordinate.Member("Node", "id"):    {Requests: 1, Errors: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			resp, err := response.Decode([]byte(tt.response))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			usage := make(Usage)
+			usage.Observe(s, doc, doc.Definitions[0].(*ast.OperationDefinition), resp)
+			if !reflect.DeepEqual(usage, tt.expected) {
+				t.Errorf("expected %v, got %v", describe(tt.expected), describe(usage))
+			}
+		})
+	}
+}
+
+func TestUsage_Observe_Accumulates(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	doc := asttest.Parse(t, `{ user(id: 1) { name } }`)
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+	usage := make(Usage)
+	for _, data := range []string{
+		`{"data":{"user":{"name":"A"}}}`,
+		`{"data":{"user":null},"errors":[{"message":"a","path":["user","name"]}]}`,
+		`{"data":{"user":{"name":"B"}}}`,
+	} {
+		resp, err := response.Decode([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		usage.Observe(s, doc, op, resp)
+	}
+	if got := *usage[coordinate.Member("User", "name")]; got != (Stats{Requests: 3, Errors: 1}) {
+		t.Errorf("expected %+v, got %+v", Stats{Requests: 3, Errors: 1}, got)
+	}
+}
+
+// describe returns stats of u by coordinate string.
+func describe(u Usage) map[string]Stats {
+	result := make(map[string]Stats, len(u))
+	for c, stats := range u {
+		result[c.String()] = *stats
+	}
+	return result
+}
@@ -0,0 +1,210 @@
+// Package operation provides utilities for inspecting executable
+// operations.
+package operation
+
+import (
+	"iter"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/introspection"
+)
+
+// Field is a field selection visited by Fields.
+type Field struct {
+	Field *ast.Field
+
+	// Path of response keys (aliases or names) from the operation root
+	// to the field, inclusive. List indices are not part of the path.
+	Path []string
+
+	// ParentType is name of type field is selected on and Definition is
+	// its definition. Both are only set when schema is provided and the
+	// field could be resolved.
+	ParentType string
+	Definition *ast.FieldDefinition
+
+	// Directives applied to enclosing operation, fields, fragment
+	// spreads, inline fragments and fragment definitions, outermost
+	// first. Directives of the field itself are in Field.Directives.
+	Directives []*ast.Directive
+}
+
+// Fields returns depth-first iterator over every field selected by op,
+// including fields of fragments. Fragment definitions are looked up in
+// doc; spreads of unknown fragments and fragment cycles are skipped.
+// When schema is not nil, parent types and field definitions are
+// resolved against it, including meta fields such as __typename and
+// fields selected under __schema and __type, see introspection.MetaField.
+func Fields(doc *ast.Document, op *ast.OperationDefinition, schema *ast.Document) iter.Seq[Field] {
+	return func(yield func(Field) bool) {
+		w := &walker{
+			fragments: make(map[string]*ast.FragmentDefinition),
+			visiting:  make(map[string]bool),
+			yield:     yield,
+		}
+		for _, def := range doc.Definitions {
+			if f, ok := def.(*ast.FragmentDefinition); ok {
+				w.fragments[f.Name.Value] = f
+			}
+		}
+		var root string
+		if schema != nil {
+			w.members = make(map[coordinate.Coordinate]*ast.FieldDefinition)
+			coordinate.Walk(schema, func(c coordinate.Coordinate, node ast.Node) bool {
+				if f, ok := node.(*ast.FieldDefinition); ok {
+					w.members[c] = f
+				}
+				return true
+			})
+			root = rootTypeName(schema, op.OperationType)
+			w.query = rootTypeName(schema, ast.OperationTypeQuery)
+		}
+		w.selectionSet(op.SelectionSet, root, nil, op.Directives)
+	}
+}
+
+type walker struct {
+	fragments map[string]*ast.FragmentDefinition
+	members   map[coordinate.Coordinate]*ast.FieldDefinition
+	query     string // Name of query root type.
+	visiting  map[string]bool
+	yield     func(Field) bool
+}
+
+func (w *walker) selectionSet(set *ast.SelectionSet, parent string, path []string, directives []*ast.Directive) bool {
+	if set == nil {
+		return true
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if !w.field(s, parent, path, directives) {
+				return false
+			}
+		case *ast.InlineFragment:
+			typeName := parent
+			if s.TypeCondition != nil && w.members != nil {
+				typeName = s.TypeCondition.Name.Value
+			}
+			if !w.selectionSet(s.SelectionSet, typeName, path, concat(directives, s.Directives)) {
+				return false
+			}
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			frag, ok := w.fragments[name]
+			if !ok || w.visiting[name] {
+				continue
+			}
+			typeName := parent
+			if w.members != nil {
+				typeName = frag.TypeCondition.Name.Value
+			}
+			w.visiting[name] = true
+			ok = w.selectionSet(frag.SelectionSet, typeName, path, concat(directives, s.Directives, frag.Directives))
+			delete(w.visiting, name)
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (w *walker) field(f *ast.Field, parent string, path []string, directives []*ast.Directive) bool {
+	key := f.Name.Value
+	if f.Alias != nil {
+		key = f.Alias.Value
+	}
+	path = append(path[:len(path):len(path)], key)
+
+	info := Field{Field: f, Path: path, Directives: directives}
+	var fieldType string
+	if parent != "" {
+		info.ParentType = parent
+		if def := w.definition(parent, f.Name.Value); def != nil {
+			info.Definition = def
+			fieldType = namedType(def.Type)
+		}
+	}
+	if !w.yield(info) {
+		return false
+	}
+	return w.selectionSet(f.SelectionSet, fieldType, path, concat(directives, f.Directives))
+}
+
+// definition returns definition of field name of type parent, which may
+// be a meta field or a field of an introspection type, or nil.
+func (w *walker) definition(parent, name string) *ast.FieldDefinition {
+	if def, ok := w.members[coordinate.Member(parent, name)]; ok {
+		return def
+	}
+	f := introspection.MetaField(name, parent, w.query)
+	if t := introspection.SystemType(parent); f == nil && t != nil {
+		f = t.Field(name)
+	}
+	if f == nil {
+		return nil
+	}
+	return f.Definition
+}
+
+// rootTypeName returns name of root type for operation type declared by
+// schema definition or extension, falling back to default names.
+func rootTypeName(schema *ast.Document, opType ast.OperationType) string {
+	for _, def := range schema.Definitions {
+		var roots []*ast.RootOperationTypeDefinition
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			roots = d.RootOperationDefs
+		case *ast.SchemaExtension:
+			roots = d.RootOperationDefs
+		}
+		for _, root := range roots {
+			if root.OperationType == opType {
+				return root.Type.Name.Value
+			}
+		}
+	}
+	switch opType {
+	case ast.OperationTypeMutation:
+		return "Mutation"
+	case ast.OperationTypeSubscription:
+		return "Subscription"
+	default:
+		return "Query"
+	}
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch tt := t.(type) {
+		case *ast.NamedType:
+			return tt.Name.Value
+		case *ast.ListType:
+			t = tt.Type
+		case *ast.NonNullType:
+			t = tt.Type
+		default:
+			return ""
+		}
+	}
+}
+
+// concat returns directives followed by more without modifying backing
+// array of directives.
+func concat(directives []*ast.Directive, more ...[]*ast.Directive) []*ast.Directive {
+	n := len(directives)
+	for _, m := range more {
+		n += len(m)
+	}
+	if n == len(directives) {
+		return directives
+	}
+	result := make([]*ast.Directive, 0, n)
+	result = append(result, directives...)
+	for _, m := range more {
+		result = append(result, m...)
+	}
+	return result
+}
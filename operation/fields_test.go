@@ -0,0 +1,141 @@
+package operation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+const schemaSDL = `
+schema { query: Root }
+type Root { me: User node(id: ID!): Node }
+interface Node { id: ID! }
+type User implements Node { id: ID! name: String friends: [User!]! }
+type Admin implements Node { id: ID! level: Int }
+`
+
+type visited struct {
+	path       string
+	parent     string
+	defined    bool
+	directives []string
+}
+
+func collect(doc *ast.Document, schema *ast.Document) []visited {
+	var result []visited
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+	for f := range Fields(doc, op, schema) {
+		v := visited{path: strings.Join(f.Path, "."), parent: f.ParentType, defined: f.Definition != nil}
+		for _, d := range f.Directives {
+			v.directives = append(v.directives, d.Name.Value)
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+func TestFields(t *testing.T) {
+	doc := asttest.Parse(t, `
+query Q @op {
+  me @auth { id ...UserFields }
+  n: node(id: "1") {
+    __typename
+    ... on Admin @internal { level }
+  }
+}
+fragment UserFields on User @frag { name friends { id } }
+`)
+
+	got := collect(doc, asttest.Parse(t, schemaSDL))
+	expected := []visited{
+		{"me", "Root", true, []string{"op"}},
+		{"me.id", "User", true, []string{"op", "auth"}},
+		{"me.name", "User", true, []string{"op", "auth", "frag"}},
+		{"me.friends", "User", true, []string{"op", "auth", "frag"}},
+		{"me.friends.id", "User", true, []string{"op", "auth", "frag"}},
+		{"n", "Root", true, []string{"op"}},
+		{"n.__typename", "Node", true, []string{"op"}},
+		{"n.level", "Admin", true, []string{"op", "internal"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected\n%+v\ngot\n%+v", expected, got)
+	}
+}
+
+func TestFields_MetaFields(t *testing.T) {
+	doc := asttest.Parse(t, `
+{
+  s: __schema { queryType { name } types { ...TypeFields } }
+  ...Introspection
+  me { t: __typename __schema { description } }
+}
+fragment Introspection on Root { __type(name: "User") { fields { name type { ofType { kind } } } } }
+fragment TypeFields on __Type { name kind }
+`)
+
+	got := collect(doc, asttest.Parse(t, schemaSDL))
+	expected := []visited{
+		{"s", "Root", true, nil},
+		{"s.queryType", "__Schema", true, nil},
+		{"s.queryType.name", "__Type", true, nil},
+		{"s.types", "__Schema", true, nil},
+		{"s.types.name", "__Type", true, nil},
+		{"s.types.kind", "__Type", true, nil},
+		{"__type", "Root", true, nil},
+		{"__type.fields", "__Type", true, nil},
+		{"__type.fields.name", "__Field", true, nil},
+		{"__type.fields.type", "__Field", true, nil},
+		{"__type.fields.type.ofType", "__Type", true, nil},
+		{"__type.fields.type.ofType.kind", "__Type", true, nil},
+		{"me", "Root", true, nil},
+		{"me.t", "User", true, nil},
+		{"me.__schema", "User", false, nil},
+		{"me.__schema.description", "", false, nil},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected\n%+v\ngot\n%+v", expected, got)
+	}
+}
+
+func TestFields_WithoutSchema(t *testing.T) {
+	doc := asttest.Parse(t, `{ a { b ... on T { c } } }`)
+	got := collect(doc, nil)
+	expected := []visited{
+		{"a", "", false, nil},
+		{"a.b", "", false, nil},
+		{"a.c", "", false, nil},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected\n%+v\ngot\n%+v", expected, got)
+	}
+}
+
+func TestFields_FragmentCycle(t *testing.T) {
+	doc := asttest.Parse(t, `
+{ me { ...A } }
+fragment A on User { id ...B }
+fragment B on User { name ...A ...Missing }
+`)
+	got := collect(doc, nil)
+	if len(got) != 3 {
+		t.Errorf("expected 3 fields, got %+v", got)
+	}
+}
+
+func TestFields_Break(t *testing.T) {
+	doc := asttest.Parse(t, `{ a b c }`)
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+	var paths []string
+	for f := range Fields(doc, op, nil) {
+		paths = append(paths, f.Path[0])
+		if len(paths) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(paths, []string{"a", "b"}) {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+}
@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+// Option configures Parser.
+type Option func(*Parser)
+
+// Mode controls how strictly parser follows the specification.
+type Mode int
+
+const (
+	// ModeStrict accepts only documents valid per the current draft. It
+	// is the default, so legacy syntax such as "type A {}", "f()" or
+	// "{ a {} }" is a syntax error unless ModeLenient is set.
+	ModeStrict Mode = iota
+	// ModeLenient additionally accepts common legacy quirks and records
+	// every occurrence, see Parser.Quirks.
+	ModeLenient
+)
+
+// WithMode sets parsing mode. Default is ModeStrict.
+func WithMode(mode Mode) Option {
+	return func(p *Parser) {
+		p.mode = mode
+	}
+}
+
+// WithInterning makes parser intern names and enum values, so equal
+// names across parsed documents share memory and do not retain the source
+// they were parsed from. It reduces memory of large or many retained
+// documents at the cost of a lookup per name.
+func WithInterning() Option {
+	return func(p *Parser) {
+		p.interning = true
+	}
+}
+
+// WithErrorRecovery makes ParseDocument and ParseDocumentContext continue
+// after syntax errors, for editors and linters which need the rest of the
+// document. A broken definition is replaced by *ast.BadNode spanning its
+// source and parsing resumes at the next definition found outside of
+// braces. The partial document is returned along with SyntaxErrors.
+// Lexical errors, e.g. unterminated strings, still stop parsing; the rest
+// of the document is then skipped.
+func WithErrorRecovery() Option {
+	return func(p *Parser) {
+		p.recovery = true
+	}
+}
+
+// WithComments makes parser record comments, which are otherwise
+// discarded, for tools reproducing source such as formatters. See
+// Parser.Comments.
+func WithComments() Option {
+	return func(p *Parser) {
+		p.keepComments = true
+	}
+}
+
+// WithExperimentalOperationDescriptions makes parser accept descriptions
+// on operation, fragment and variable definitions, as proposed by the
+// descriptions on executable definitions RFC. Descriptions are rejected
+// on them otherwise, as the specification requires. The query shorthand
+// still cannot have description.
+func WithExperimentalOperationDescriptions() Option {
+	return func(p *Parser) {
+		p.opDescs = true
+	}
+}
+
+// SyntaxError is a syntax error collected in error recovery mode. Legacy
+// syntax rejected in ModeStrict is reported as SyntaxError too.
+type SyntaxError struct {
+	Position int // Byte offset of the token the error was found at.
+	Err      error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v (at %d)", e.Err, e.Position)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// SyntaxErrors are syntax errors collected in error recovery mode, in
+// source order.
+type SyntaxErrors []*SyntaxError
+
+func (e SyntaxErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+}
+
+// QuirkKind identifies legacy syntax accepted in ModeLenient.
+//
+// Commas, including trailing ones such as in "f(a: 1,)" or "[1, 2,]",
+// are not quirks: they are insignificant tokens per the specification and
+// are skipped in both modes.
+type QuirkKind int
+
+const (
+	// QuirkImplementsWithoutAmpersand is legacy interface list separated by
+	// whitespace or commas, e.g. "type A implements B, C".
+	QuirkImplementsWithoutAmpersand QuirkKind = iota
+	// QuirkEmptyFields is empty fields, enum values or input fields
+	// definition, e.g. "type A {}".
+	QuirkEmptyFields
+	// QuirkEmptyArguments is empty arguments, arguments definition or
+	// variable definitions list, e.g. "field()".
+	QuirkEmptyArguments
+	// QuirkEmptySelectionSet is selection set without selections.
+	QuirkEmptySelectionSet
+)
+
+var quirkKinds = [...]string{
+	QuirkImplementsWithoutAmpersand: "interfaces must be separated by '&'",
+	QuirkEmptyFields:                "empty fields definition",
+	QuirkEmptyArguments:             "empty arguments list",
+	QuirkEmptySelectionSet:          "empty selection set",
+}
+
+func (k QuirkKind) String() string {
+	if k < 0 || int(k) >= len(quirkKinds) {
+		return fmt.Sprintf("QuirkKind(%d)", int(k))
+	}
+	return quirkKinds[k]
+}
+
+// Quirk is an occurrence of legacy syntax accepted in ModeLenient.
+type Quirk struct {
+	Kind     QuirkKind
+	Position int
+}
+
+func (q Quirk) String() string {
+	return fmt.Sprintf("%s at %d", q.Kind, q.Position)
+}
+
+// Quirks returns legacy syntax occurrences accepted so far in ModeLenient.
+func (p *Parser) Quirks() []Quirk {
+	return p.quirks
+}
+
+// Comments returns COMMENT tokens read so far with WithComments, in
+// source order. Literals exclude the leading '#'. The parser reads one
+// token ahead, so after ParseDocument they include every comment of the
+// document.
+func (p *Parser) Comments() []token.Token {
+	return p.comments
+}
+
+// quirk records legacy syntax occurrence in ModeLenient and returns
+// SyntaxError at pos in ModeStrict.
+func (p *Parser) quirk(kind QuirkKind, pos int) error {
+	if p.mode != ModeLenient {
+		return &SyntaxError{Position: pos, Err: errors.New(kind.String())}
+	}
+	p.quirks = append(p.quirks, Quirk{Kind: kind, Position: pos})
+	return nil
+}
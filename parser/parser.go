@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"unique"
 
 	"github.com/gqlhub/gqlhub-core/ast"
 	"github.com/gqlhub/gqlhub-core/lexer"
@@ -13,37 +17,193 @@ type Parser struct {
 	l         *lexer.Lexer
 	curToken  token.Token
 	peekToken token.Token
+	prevEnd   int // End of the last consumed token.
+
+	mode      Mode
+	quirks    []Quirk
+	interning bool
+
+	keepComments bool          // Set by WithComments.
+	opDescs      bool          // Set by WithExperimentalOperationDescriptions.
+	comments     []token.Token // Comments recorded with WithComments.
+
+	recovery bool         // Set by WithErrorRecovery.
+	depth    int          // Brace nesting of consumed tokens in recovery mode.
+	defStart int          // Start of the definition being parsed.
+	errs     SyntaxErrors // Errors collected in recovery mode.
+
+	ctx    context.Context // Set only while parsing with ParseDocumentContext.
+	tokens int
+}
+
+// cancelCheckInterval is number of tokens read between context
+// cancellation checks in ParseDocumentContext.
+const cancelCheckInterval = 1024
+
+func New(l *lexer.Lexer, opts ...Option) (*Parser, error) {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if err := p.Reset(l); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
-func New(l *lexer.Lexer) (*Parser, error) {
-	p := &Parser{l: l}
+// Reset makes parser read tokens from l, keeping its options and
+// discarding recorded quirks, so that parsers can be pooled.
+func (p *Parser) Reset(l *lexer.Lexer) error {
+	*p = Parser{
+		l:            l,
+		mode:         p.mode,
+		interning:    p.interning,
+		recovery:     p.recovery,
+		keepComments: p.keepComments,
+		opDescs:      p.opDescs,
+	}
 	if err := p.next(); err != nil {
-		return nil, fmt.Errorf("failed to initialize parser tokens: %w", err)
+		return fmt.Errorf("failed to initialize parser tokens: %w", err)
 	}
 	if err := p.next(); err != nil {
-		return nil, fmt.Errorf("failed to initialize parser tokens: %w", err)
+		return fmt.Errorf("failed to initialize parser tokens: %w", err)
 	}
-	return p, nil
+	return nil
 }
 
+// ParseDocument parses the whole document. With WithErrorRecovery, the
+// document is returned even when it has syntax errors, which are then
+// returned as SyntaxErrors.
 func (p *Parser) ParseDocument() (*ast.Document, error) {
 	doc := &ast.Document{}
 
 	for p.curToken.Type != token.EOF {
 		def, err := p.parseDefinition()
 		if err != nil {
+			if def = p.recover(err); def == nil {
+				return nil, err
+			}
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+
+	if len(p.errs) > 0 {
+		return doc, p.errs
+	}
+	return doc, nil
+}
+
+// ParseDefinition parses the next definition of document and returns
+// io.EOF once all definitions were parsed. Unlike ParseDocument it does not
+// retain parsed definitions, so huge documents can be processed one
+// definition at a time.
+func (p *Parser) ParseDefinition() (ast.Definition, error) {
+	if p.curToken.Type == token.EOF {
+		return nil, io.EOF
+	}
+	return p.parseDefinition()
+}
+
+// ParseDocumentContext is like ParseDocument but stops with ctx.Err() once
+// ctx is done. Cancellation is checked between definitions and every
+// cancelCheckInterval tokens, so servers can abort parsing of huge
+// documents when the client disconnects.
+func (p *Parser) ParseDocumentContext(ctx context.Context) (*ast.Document, error) {
+	p.ctx = ctx
+	defer func() { p.ctx = nil }()
+
+	doc := &ast.Document{}
+
+	for p.curToken.Type != token.EOF {
+		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
+		def, err := p.parseDefinition()
+		if err != nil {
+			if def = p.recover(err); def == nil {
+				return nil, err
+			}
+		}
 		doc.Definitions = append(doc.Definitions, def)
 	}
 
+	if len(p.errs) > 0 {
+		return doc, p.errs
+	}
 	return doc, nil
 }
 
+// recover records err and skips the rest of the broken definition in error
+// recovery mode, returning its placeholder. It returns nil otherwise.
+func (p *Parser) recover(err error) ast.Definition {
+	if !p.recovery {
+		return nil
+	}
+	if p.ctx != nil && p.ctx.Err() != nil {
+		return nil
+	}
+	var lexErr *lexer.LexError
+	if errors.As(err, &lexErr) {
+		// Lexer cannot continue past the invalid input.
+		p.errs = append(p.errs, &SyntaxError{Position: p.curToken.End, Err: err})
+		end := p.curToken.End
+		p.curToken = token.Token{Type: token.EOF, Start: end, End: end}
+		return &ast.BadNode{Position: p.defStart, EndPosition: end}
+	}
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok {
+		syntaxErr = &SyntaxError{Position: p.curToken.Start, Err: err}
+	}
+	p.errs = append(p.errs, syntaxErr)
+	// Skip at least one token so that parsing makes progress, then up to a
+	// token starting a definition outside of braces.
+	for first := true; p.curToken.Type != token.EOF; first = false {
+		if !first && p.depth == 0 && p.atDefinition() {
+			break
+		}
+		if err := p.next(); err != nil {
+			return p.recover(err)
+		}
+	}
+	return &ast.BadNode{Position: p.defStart, EndPosition: p.prevEnd}
+}
+
+// atDefinition reports whether current token may start a definition.
+func (p *Parser) atDefinition() bool {
+	tok := p.curToken
+	if IsStringValue(tok.Type) {
+		tok = p.peekToken
+	}
+	return tok.Type == token.LBRACE || tok.Type == token.NAME && isDefinitionKeyword(tok.Literal)
+}
+
 func (p *Parser) next() error {
+	if p.ctx != nil {
+		p.tokens++
+		if p.tokens%cancelCheckInterval == 0 {
+			if err := p.ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if p.recovery {
+		switch p.curToken.Type {
+		case token.LBRACE:
+			p.depth++
+		case token.RBRACE:
+			p.depth = max(p.depth-1, 0)
+		}
+	}
+	p.prevEnd = p.curToken.End
 	p.curToken = p.peekToken
 	var err error
 	p.peekToken, err = p.l.NextToken()
+	for err == nil && p.peekToken.Type == token.COMMENT {
+		if p.keepComments {
+			p.comments = append(p.comments, p.peekToken)
+		}
+		p.peekToken, err = p.l.NextToken()
+	}
 	return err
 }
 
@@ -76,6 +236,15 @@ func (p *Parser) expectAndNext(expectedToken token.Type) error {
 	return p.next()
 }
 
+// skipOptional consumes current token if it is of given type, e.g. leading
+// '|' of union members or leading '&' of implemented interfaces.
+func (p *Parser) skipOptional(tokType token.Type) error {
+	if p.curToken.Type != tokType {
+		return nil
+	}
+	return p.next()
+}
+
 func (p *Parser) expectLiteralAndNext(lit string) error {
 	if p.curToken.Literal != lit {
 		return fmt.Errorf("expected %s, got %s", lit, p.curToken.Literal)
@@ -84,7 +253,9 @@ func (p *Parser) expectLiteralAndNext(lit string) error {
 }
 
 func (p *Parser) parseDefinition() (ast.Definition, error) {
-	if p.peekToken.Type == token.LBRACE {
+	p.defStart = p.curToken.Start
+	p.depth = 0
+	if p.curToken.Type == token.LBRACE {
 		return p.parseAnonymousOperationDefinition()
 	}
 
@@ -114,8 +285,14 @@ func (p *Parser) parseDefinition() (ast.Definition, error) {
 		case "directive":
 			return p.parseDirectiveDefinition()
 		case "query", "mutation", "subscription":
+			if err := p.checkOperationDescription(); err != nil {
+				return nil, err
+			}
 			return p.parseOperationDefinition()
 		case "fragment":
+			if err := p.checkOperationDescription(); err != nil {
+				return nil, err
+			}
 			return p.parseFragmentDefinition()
 		case "extend":
 			return p.parseTypeSystemExtension()
@@ -164,6 +341,7 @@ func (p *Parser) parseSchemaDefinition() (ast.Definition, error) {
 		return nil, err
 	}
 
+	schemaDef.EndPosition = p.prevEnd
 	return schemaDef, nil
 }
 
@@ -196,6 +374,7 @@ func (p *Parser) parseScalarTypeDefinition() (ast.Definition, error) {
 	}
 	scalarTypeDef.Directives = directives
 
+	scalarTypeDef.EndPosition = p.prevEnd
 	return scalarTypeDef, nil
 }
 
@@ -244,6 +423,7 @@ func (p *Parser) parseObjectTypeDefinition() (ast.Definition, error) {
 		objTypeDef.Fields = fields
 	}
 
+	objTypeDef.EndPosition = p.prevEnd
 	return objTypeDef, nil
 }
 
@@ -292,6 +472,7 @@ func (p *Parser) parseInterfaceTypeDefinition() (ast.Definition, error) {
 		interfaceTypeDef.Fields = fields
 	}
 
+	interfaceTypeDef.EndPosition = p.prevEnd
 	return interfaceTypeDef, nil
 }
 
@@ -332,6 +513,7 @@ func (p *Parser) parseUnionTypeDefinition() (ast.Definition, error) {
 		unionTypeDef.Types = types
 	}
 
+	unionTypeDef.EndPosition = p.prevEnd
 	return unionTypeDef, nil
 }
 
@@ -372,6 +554,7 @@ func (p *Parser) parseEnumTypeDefinition() (ast.Definition, error) {
 		enumTypeDef.Values = vals
 	}
 
+	enumTypeDef.EndPosition = p.prevEnd
 	return enumTypeDef, nil
 }
 
@@ -412,6 +595,7 @@ func (p *Parser) parseInputObjectTypeDefinition() (ast.Definition, error) {
 		inputObjTypeDef.Fields = fields
 	}
 
+	inputObjTypeDef.EndPosition = p.prevEnd
 	return inputObjTypeDef, nil
 }
 
@@ -466,10 +650,14 @@ func (p *Parser) parseDirectiveDefinition() (ast.Definition, error) {
 	}
 	directiveDef.Locations = locations
 
+	directiveDef.EndPosition = p.prevEnd
 	return directiveDef, nil
 }
 
 func (p *Parser) parseDirectiveLocations() ([]*ast.Name, error) {
+	if err := p.skipOptional(token.PIPE); err != nil {
+		return nil, err
+	}
 	var directiveLocations []*ast.Name
 	for {
 		nt, err := p.parseName()
@@ -500,11 +688,29 @@ func (p *Parser) parseOperationType() (ast.OperationType, error) {
 	return opType, nil
 }
 
+// checkOperationDescription returns error when executable definition
+// starting at the current token has description and descriptions of
+// executable definitions are not enabled.
+func (p *Parser) checkOperationDescription() error {
+	if IsStringValue(p.curToken.Type) && !p.opDescs {
+		return fmt.Errorf("unexpected description of %s definition", p.peekToken.Literal)
+	}
+	return nil
+}
+
 func (p *Parser) parseOperationDefinition() (*ast.OperationDefinition, error) {
 	opDef := &ast.OperationDefinition{
 		Position: p.curToken.Start,
 	}
 
+	if IsStringValue(p.curToken.Type) {
+		desc, err := p.parseDescription()
+		if err != nil {
+			return nil, err
+		}
+		opDef.Description = desc
+	}
+
 	opType, err := p.parseOperationType()
 	if err != nil {
 		return nil, err
@@ -539,29 +745,45 @@ func (p *Parser) parseOperationDefinition() (*ast.OperationDefinition, error) {
 	}
 	opDef.SelectionSet = selectionSet
 
+	opDef.EndPosition = p.prevEnd
 	return opDef, nil
 }
 
+// intern returns canonical copy of s when interning is enabled.
+func (p *Parser) intern(s string) string {
+	if !p.interning {
+		return s
+	}
+	return unique.Make(s).Value()
+}
+
 func (p *Parser) parseName() (*ast.Name, error) {
 	if err := p.expect(token.NAME); err != nil {
 		return nil, err
 	}
 	name := &ast.Name{
 		Position: p.curToken.Start,
-		Value:    p.curToken.Literal,
+		Value:    p.intern(p.curToken.Literal),
 	}
 	if err := p.next(); err != nil {
 		return nil, err
 	}
+	name.EndPosition = p.prevEnd
 	return name, nil
 }
 
 func (p *Parser) parseVariableDefinitions() ([]*ast.VariableDefinition, error) {
 	var varDefs []*ast.VariableDefinition
 
+	pos := p.curToken.Start
 	if err := p.expectAndNext(token.LPAREN); err != nil {
 		return nil, err
 	}
+	if p.curToken.Type == token.RPAREN {
+		if err := p.quirk(QuirkEmptyArguments, pos); err != nil {
+			return nil, err
+		}
+	}
 
 	for p.curToken.Type != token.RPAREN && p.curToken.Type != token.EOF {
 		def, err := p.parseVariableDefinition()
@@ -583,6 +805,14 @@ func (p *Parser) parseVariableDefinition() (*ast.VariableDefinition, error) {
 		Position: p.curToken.Start,
 	}
 
+	if IsStringValue(p.curToken.Type) && p.opDescs {
+		desc, err := p.parseDescription()
+		if err != nil {
+			return nil, err
+		}
+		varDef.Description = desc
+	}
+
 	val, err := p.parseVariable()
 	if err != nil {
 		return nil, err
@@ -620,6 +850,7 @@ func (p *Parser) parseVariableDefinition() (*ast.VariableDefinition, error) {
 	}
 	varDef.Directives = directives
 
+	varDef.EndPosition = p.prevEnd
 	return varDef, nil
 }
 
@@ -642,8 +873,9 @@ func (p *Parser) parseType() (ast.Type, error) {
 		}
 
 		typ = &ast.ListType{
-			Position: pos,
-			Type:     innerType,
+			Position:    pos,
+			EndPosition: p.prevEnd,
+			Type:        innerType,
 		}
 	} else if p.curToken.Type == token.NAME {
 		var err error
@@ -657,8 +889,9 @@ func (p *Parser) parseType() (ast.Type, error) {
 
 	if p.curToken.Type == token.BANG {
 		typ = &ast.NonNullType{
-			Position: p.curToken.Start,
-			Type:     typ,
+			Position:    pos,
+			EndPosition: p.curToken.End,
+			Type:        typ,
 		}
 		if err := p.next(); err != nil {
 			return nil, err
@@ -670,6 +903,7 @@ func (p *Parser) parseType() (ast.Type, error) {
 
 func (p *Parser) parseAnonymousOperationDefinition() (*ast.OperationDefinition, error) {
 	opDef := &ast.OperationDefinition{
+		Position:      p.curToken.Start,
 		OperationType: ast.OperationTypeQuery,
 	}
 
@@ -679,6 +913,7 @@ func (p *Parser) parseAnonymousOperationDefinition() (*ast.OperationDefinition,
 	}
 	opDef.SelectionSet = selectionSet
 
+	opDef.EndPosition = p.prevEnd
 	return opDef, nil
 }
 
@@ -687,6 +922,14 @@ func (p *Parser) parseFragmentDefinition() (*ast.FragmentDefinition, error) {
 		Position: p.curToken.Start,
 	}
 
+	if IsStringValue(p.curToken.Type) {
+		desc, err := p.parseDescription()
+		if err != nil {
+			return nil, err
+		}
+		fragmentDef.Description = desc
+	}
+
 	if err := p.expectLiteralAndNext("fragment"); err != nil {
 		return nil, err
 	}
@@ -703,12 +946,19 @@ func (p *Parser) parseFragmentDefinition() (*ast.FragmentDefinition, error) {
 	}
 	fragmentDef.TypeCondition = typeCond
 
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+	fragmentDef.Directives = directives
+
 	selectionSet, err := p.parseSelectionSet()
 	if err != nil {
 		return nil, err
 	}
 	fragmentDef.SelectionSet = selectionSet
 
+	fragmentDef.EndPosition = p.prevEnd
 	return fragmentDef, nil
 }
 
@@ -727,6 +977,11 @@ func (p *Parser) parseSelectionSet() (*ast.SelectionSet, error) {
 	if err := p.expectAndNext(token.LBRACE); err != nil {
 		return nil, err
 	}
+	if p.curToken.Type == token.RBRACE {
+		if err := p.quirk(QuirkEmptySelectionSet, selectionSet.Position); err != nil {
+			return nil, err
+		}
+	}
 
 	for p.curToken.Type != token.RBRACE && p.curToken.Type != token.EOF {
 		selection, err := p.parseSelection()
@@ -740,6 +995,7 @@ func (p *Parser) parseSelectionSet() (*ast.SelectionSet, error) {
 		return nil, err
 	}
 
+	selectionSet.EndPosition = p.prevEnd
 	return selectionSet, nil
 }
 
@@ -795,33 +1051,36 @@ func (p *Parser) parseField() (ast.Selection, error) {
 		field.SelectionSet = ss
 	}
 
+	field.EndPosition = p.prevEnd
 	return field, nil
 }
 
 func (p *Parser) parseFragment() (ast.Selection, error) {
+	pos := p.curToken.Start
 	if err := p.next(); err != nil {
 		return nil, err
 	}
-	if p.curToken.Literal == "on" {
-		return p.parseInlineFragment()
+	if p.curToken.Literal == "on" || p.curToken.Type == token.LBRACE || p.curToken.Type == token.AT {
+		return p.parseInlineFragment(pos)
 	}
-	return p.parseFragmentSpread()
+	return p.parseFragmentSpread(pos)
 }
 
-func (p *Parser) parseInlineFragment() (*ast.InlineFragment, error) {
+func (p *Parser) parseInlineFragment(pos int) (*ast.InlineFragment, error) {
 	inlineFragment := &ast.InlineFragment{
-		Position: p.curToken.Start,
-	}
-
-	if err := p.next(); err != nil {
-		return nil, err
+		Position: pos,
 	}
 
-	typeCond, err := p.parseNamedType()
-	if err != nil {
-		return nil, err
+	if p.curToken.Literal == "on" {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		typeCond, err := p.parseNamedType()
+		if err != nil {
+			return nil, err
+		}
+		inlineFragment.TypeCondition = typeCond
 	}
-	inlineFragment.TypeCondition = typeCond
 
 	directives, err := p.parseDirectives()
 	if err != nil {
@@ -835,6 +1094,7 @@ func (p *Parser) parseInlineFragment() (*ast.InlineFragment, error) {
 	}
 	inlineFragment.SelectionSet = selectionSet
 
+	inlineFragment.EndPosition = p.prevEnd
 	return inlineFragment, nil
 }
 
@@ -849,6 +1109,7 @@ func (p *Parser) parseNamedType() (*ast.NamedType, error) {
 	}
 	namedType.Name = name
 
+	namedType.EndPosition = p.prevEnd
 	return namedType, nil
 }
 
@@ -889,15 +1150,22 @@ func (p *Parser) parseDirective() (*ast.Directive, error) {
 		directive.Arguments = args
 	}
 
+	directive.EndPosition = p.prevEnd
 	return directive, nil
 }
 
 func (p *Parser) parseArguments() ([]*ast.Argument, error) {
 	var args []*ast.Argument
 
+	pos := p.curToken.Start
 	if err := p.expectAndNext(token.LPAREN); err != nil {
 		return nil, err
 	}
+	if p.curToken.Type == token.RPAREN {
+		if err := p.quirk(QuirkEmptyArguments, pos); err != nil {
+			return nil, err
+		}
+	}
 
 	for p.curToken.Type != token.RPAREN && p.curToken.Type != token.EOF {
 		arg, err := p.parseArgument()
@@ -935,6 +1203,7 @@ func (p *Parser) parseArgument() (*ast.Argument, error) {
 	}
 	arg.Value = value
 
+	arg.EndPosition = p.prevEnd
 	return arg, nil
 }
 
@@ -948,6 +1217,7 @@ func (p *Parser) parseValue() (ast.Value, error) {
 		if err := p.next(); err != nil {
 			return nil, err
 		}
+		val.EndPosition = p.prevEnd
 		return val, nil
 	case token.FLOAT:
 		val := &ast.FloatValue{
@@ -957,6 +1227,7 @@ func (p *Parser) parseValue() (ast.Value, error) {
 		if err := p.next(); err != nil {
 			return nil, err
 		}
+		val.EndPosition = p.prevEnd
 		return val, nil
 	case token.STRING, token.BLOCK_STRING:
 		return p.parseStringValue()
@@ -970,6 +1241,7 @@ func (p *Parser) parseValue() (ast.Value, error) {
 			if err := p.next(); err != nil {
 				return nil, err
 			}
+			val.EndPosition = p.prevEnd
 			return val, nil
 		case "false":
 			val := &ast.BooleanValue{
@@ -979,6 +1251,7 @@ func (p *Parser) parseValue() (ast.Value, error) {
 			if err := p.next(); err != nil {
 				return nil, err
 			}
+			val.EndPosition = p.prevEnd
 			return val, nil
 		case "null":
 			val := &ast.NullValue{
@@ -987,15 +1260,17 @@ func (p *Parser) parseValue() (ast.Value, error) {
 			if err := p.next(); err != nil {
 				return nil, err
 			}
+			val.EndPosition = p.prevEnd
 			return val, nil
 		default:
 			val := &ast.EnumValue{
 				Position: p.curToken.Start,
-				Value:    p.curToken.Literal,
+				Value:    p.intern(p.curToken.Literal),
 			}
 			if err := p.next(); err != nil {
 				return nil, err
 			}
+			val.EndPosition = p.prevEnd
 			return val, nil
 		}
 	case token.DOLLAR:
@@ -1030,6 +1305,7 @@ func (p *Parser) parseListValue() (ast.Value, error) {
 		return nil, err
 	}
 
+	listValue.EndPosition = p.prevEnd
 	return listValue, nil
 }
 
@@ -1054,6 +1330,7 @@ func (p *Parser) parseObjectValue() (ast.Value, error) {
 		return nil, err
 	}
 
+	objValue.EndPosition = p.prevEnd
 	return objValue, nil
 }
 
@@ -1078,6 +1355,7 @@ func (p *Parser) parseObjectField() (*ast.ObjectField, error) {
 	}
 	objField.Value = val
 
+	objField.EndPosition = p.prevEnd
 	return objField, nil
 }
 
@@ -1096,12 +1374,13 @@ func (p *Parser) parseVariable() (ast.Value, error) {
 	}
 	variable.Name = name
 
+	variable.EndPosition = p.prevEnd
 	return variable, nil
 }
 
-func (p *Parser) parseFragmentSpread() (*ast.FragmentSpread, error) {
+func (p *Parser) parseFragmentSpread(pos int) (*ast.FragmentSpread, error) {
 	fragmentSpread := &ast.FragmentSpread{
-		Position: p.curToken.Start,
+		Position: pos,
 	}
 
 	name, err := p.parseName()
@@ -1116,6 +1395,7 @@ func (p *Parser) parseFragmentSpread() (*ast.FragmentSpread, error) {
 	}
 	fragmentSpread.Directives = directives
 
+	fragmentSpread.EndPosition = p.prevEnd
 	return fragmentSpread, nil
 }
 
@@ -1187,15 +1467,22 @@ func (p *Parser) parseInputObjectTypeExtension() (ast.Definition, error) {
 		inputObjTypeExtension.Fields = fields
 	}
 
+	inputObjTypeExtension.EndPosition = p.prevEnd
 	return inputObjTypeExtension, nil
 }
 
 func (p *Parser) parseInputFieldsDefinition() ([]*ast.InputValueDefinition, error) {
 	var inputValueDef []*ast.InputValueDefinition
 
+	pos := p.curToken.Start
 	if err := p.expectAndNext(token.LBRACE); err != nil {
 		return nil, err
 	}
+	if p.curToken.Type == token.RBRACE {
+		if err := p.quirk(QuirkEmptyFields, pos); err != nil {
+			return nil, err
+		}
+	}
 
 	for p.curToken.Type != token.RBRACE && p.curToken.Type != token.EOF {
 		f, err := p.parseInputValueDefinition()
@@ -1244,13 +1531,20 @@ func (p *Parser) parseEnumTypeExtension() (ast.Definition, error) {
 		enumTypeExtension.Values = vals
 	}
 
+	enumTypeExtension.EndPosition = p.prevEnd
 	return enumTypeExtension, nil
 }
 
 func (p *Parser) parseEnumValuesDefinition() ([]*ast.EnumValueDefinition, error) {
+	pos := p.curToken.Start
 	if err := p.expectAndNext(token.LBRACE); err != nil {
 		return nil, err
 	}
+	if p.curToken.Type == token.RBRACE {
+		if err := p.quirk(QuirkEmptyFields, pos); err != nil {
+			return nil, err
+		}
+	}
 
 	var vals []*ast.EnumValueDefinition
 	for p.curToken.Type != token.RBRACE && p.curToken.Type != token.EOF {
@@ -1293,6 +1587,7 @@ func (p *Parser) parseEnumValueDefinition() (*ast.EnumValueDefinition, error) {
 	}
 	ev.Directives = directives
 
+	ev.EndPosition = p.prevEnd
 	return ev, nil
 }
 
@@ -1332,6 +1627,7 @@ func (p *Parser) parseUnionTypeExtension() (ast.Definition, error) {
 		return nil, fmt.Errorf("unexpected: %s", p.curToken.Literal) //TODO: fix msg see https://spec.graphql.org/draft/#UnionTypeDefinition
 	}
 
+	unionTypeExtension.EndPosition = p.prevEnd
 	return unionTypeExtension, nil
 }
 
@@ -1349,6 +1645,9 @@ func (p *Parser) parseUnionMemberTypes() ([]*ast.NamedType, error) {
 	if err := p.expectAndNext(token.EQUALS); err != nil {
 		return nil, err
 	}
+	if err := p.skipOptional(token.PIPE); err != nil {
+		return nil, err
+	}
 	var types []*ast.NamedType
 	for {
 		typ, err := p.parseNamedType()
@@ -1411,6 +1710,7 @@ func (p *Parser) parseInterfaceTypeExtension() (ast.Definition, error) {
 		return nil, fmt.Errorf("unexpected: %s", p.curToken.Literal) //TODO: fix msg see https://spec.graphql.org/draft/#InterfaceTypeExtension
 	}
 
+	interfaceTypeExtension.EndPosition = p.prevEnd
 	return interfaceTypeExtension, nil
 }
 
@@ -1432,7 +1732,7 @@ func (p *Parser) parseSchemaExtension() (*ast.SchemaExtension, error) {
 	}
 	schemaExtension.Directives = directives
 
-	if p.curToken.Type != token.LBRACE {
+	if p.curToken.Type == token.LBRACE {
 		if err := p.next(); err != nil {
 			return nil, err
 		}
@@ -1450,6 +1750,11 @@ func (p *Parser) parseSchemaExtension() (*ast.SchemaExtension, error) {
 		}
 	}
 
+	if schemaExtension.Directives == nil && schemaExtension.RootOperationDefs == nil {
+		return nil, fmt.Errorf("expected directives or root operation types, got %s", p.curToken.Type)
+	}
+
+	schemaExtension.EndPosition = p.prevEnd
 	return schemaExtension, nil
 }
 
@@ -1474,6 +1779,7 @@ func (p *Parser) parseRootOperationTypeDefinition() (*ast.RootOperationTypeDefin
 	}
 	rootOpTypeDef.Type = typ
 
+	rootOpTypeDef.EndPosition = p.prevEnd
 	return rootOpTypeDef, nil
 }
 
@@ -1504,6 +1810,7 @@ func (p *Parser) parseScalarTypeExtension() (*ast.ScalarTypeExtension, error) {
 	}
 	scalarTypeExtension.Directives = directives
 
+	scalarTypeExtension.EndPosition = p.prevEnd
 	return scalarTypeExtension, nil
 }
 
@@ -1547,6 +1854,7 @@ func (p *Parser) parseObjectTypeExtension() (*ast.ObjectTypeExtension, error) {
 		objTypeExtension.Fields = fields
 	}
 
+	objTypeExtension.EndPosition = p.prevEnd
 	return objTypeExtension, nil
 }
 
@@ -1554,6 +1862,9 @@ func (p *Parser) parseImplementsInterfaces() ([]*ast.NamedType, error) {
 	if err := p.expectLiteralAndNext("implements"); err != nil {
 		return nil, err
 	}
+	if err := p.skipOptional(token.AMP); err != nil {
+		return nil, err
+	}
 	var interfaces []*ast.NamedType
 	for {
 		typ, err := p.parseNamedType()
@@ -1567,6 +1878,12 @@ func (p *Parser) parseImplementsInterfaces() ([]*ast.NamedType, error) {
 			}
 			continue
 		}
+		if p.curToken.Type == token.NAME && !isDefinitionKeyword(p.curToken.Literal) {
+			if err := p.quirk(QuirkImplementsWithoutAmpersand, p.curToken.Start); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		break
 	}
 	return interfaces, nil
@@ -1575,9 +1892,15 @@ func (p *Parser) parseImplementsInterfaces() ([]*ast.NamedType, error) {
 func (p *Parser) parseFieldsDefinition() ([]*ast.FieldDefinition, error) {
 	var fieldsDef []*ast.FieldDefinition
 
+	pos := p.curToken.Start
 	if err := p.expectAndNext(token.LBRACE); err != nil {
 		return nil, err
 	}
+	if p.curToken.Type == token.RBRACE {
+		if err := p.quirk(QuirkEmptyFields, pos); err != nil {
+			return nil, err
+		}
+	}
 
 	for p.curToken.Type != token.RBRACE && p.curToken.Type != token.EOF {
 		fieldDef, err := p.parseFieldDefinition()
@@ -1637,15 +1960,22 @@ func (p *Parser) parseFieldDefinition() (*ast.FieldDefinition, error) {
 	}
 	fieldDef.Directives = directives
 
+	fieldDef.EndPosition = p.prevEnd
 	return fieldDef, nil
 }
 
 func (p *Parser) parseArgumentsDefinition() ([]*ast.InputValueDefinition, error) {
 	var argumentsDef []*ast.InputValueDefinition
 
+	pos := p.curToken.Start
 	if err := p.expectAndNext(token.LPAREN); err != nil {
 		return nil, err
 	}
+	if p.curToken.Type == token.RPAREN {
+		if err := p.quirk(QuirkEmptyArguments, pos); err != nil {
+			return nil, err
+		}
+	}
 
 	for p.curToken.Type != token.RPAREN && p.curToken.Type != token.EOF {
 		def, err := p.parseInputValueDefinition()
@@ -1707,16 +2037,22 @@ func (p *Parser) parseInputValueDefinition() (*ast.InputValueDefinition, error)
 		return nil, err
 	}
 	inputValueDef.Directives = directives
+	inputValueDef.EndPosition = p.prevEnd
 
 	return inputValueDef, nil
 }
 
-func (p *Parser) parseDescription() (*ast.StringValue, error) {
+func (p *Parser) parseDescription() (*ast.Description, error) {
 	val, err := p.parseStringValue()
 	if err != nil {
 		return nil, err
 	}
-	return val, nil
+	return &ast.Description{
+		Position:    val.Position,
+		EndPosition: val.EndPosition,
+		Value:       val.Value,
+		Block:       val.Block,
+	}, nil
 }
 
 func (p *Parser) parseStringValue() (*ast.StringValue, error) {
@@ -1734,5 +2070,6 @@ func (p *Parser) parseStringValue() (*ast.StringValue, error) {
 		return nil, err
 	}
 
+	strValue.EndPosition = p.prevEnd
 	return strValue, nil
 }
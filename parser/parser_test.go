@@ -0,0 +1,760 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/token"
+)
+
+func TestParseDocumentContext(t *testing.T) {
+	p := newParser(t, `type A { a: Int } type B { b: Int }`)
+	doc, err := p.ParseDocumentContext(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.Definitions) != 2 {
+		t.Errorf("expected 2 definitions, got %d", len(doc.Definitions))
+	}
+}
+
+func TestParseDocumentContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := newParser(t, `type A { a: Int }`)
+	if _, err := p.ParseDocumentContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// cancelAfter reports cancellation once Err was called n times.
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	c.n--
+	if c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestParseDocumentContext_CancelledWithinDefinition(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("type Huge {")
+	for i := 0; i < 10*cancelCheckInterval; i++ {
+		b.WriteString(" f: Int")
+	}
+	b.WriteString(" }")
+
+	// First check happens before the definition, second one inside it.
+	ctx := &cancelAfter{Context: context.Background(), n: 1}
+	p := newParser(t, b.String())
+	if _, err := p.ParseDocumentContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if p.tokens != cancelCheckInterval {
+		t.Errorf("expected parsing to stop after %d tokens, got %d", cancelCheckInterval, p.tokens)
+	}
+}
+
+func newParser(t *testing.T, input string) *Parser {
+	t.Helper()
+	p, err := New(lexer.New(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return p
+}
+
+func TestParseDefinition(t *testing.T) {
+	p := newParser(t, `type A { a: Int } # comment
+{ a }`)
+	var kinds []string
+	for {
+		def, err := p.ParseDefinition()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		kinds = append(kinds, fmt.Sprintf("%T", def))
+	}
+	expected := []string{"*ast.ObjectTypeDefinition", "*ast.OperationDefinition"}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("expected %v, got %v", expected, kinds)
+	}
+	if _, err := p.ParseDefinition(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF again, got %v", err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	l := lexer.New(`type A {}`)
+	p, err := New(l, WithMode(ModeLenient))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.ParseDocument(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quirks := p.Quirks()
+
+	l.Reset(`{ a {} }`)
+	if err := p.Reset(l); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		t.Fatalf("expected lenient mode to be kept, got %v", err)
+	}
+	if _, ok := doc.Definitions[0].(*ast.OperationDefinition); !ok {
+		t.Errorf("expected operation, got %T", doc.Definitions[0])
+	}
+	expected := []Quirk{{QuirkEmptySelectionSet, 4}}
+	if !reflect.DeepEqual(p.Quirks(), expected) {
+		t.Errorf("expected quirks %v, got %v", expected, p.Quirks())
+	}
+	if !reflect.DeepEqual(quirks, []Quirk{{QuirkEmptyFields, 7}}) {
+		t.Errorf("expected previous quirks to be unchanged, got %v", quirks)
+	}
+
+	l.Reset(`"unterminated`)
+	if err := p.Reset(l); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestParseDocument_Valid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Anonymous query", `{ a }`},
+		{"Query without name", `query { a }`},
+		{"Subscription without name", `subscription { a }`},
+		{"Comments", "# comment\n{ a # trailing\n b }"},
+		{"Fragment directives", `fragment F on T @a { b }`},
+		{"Schema extension with directives", `extend schema @a`},
+		{"Schema extension with operation types", `extend schema { query: Q }`},
+		{"Empty list and object values", `{ a(b: [], c: {}) }`},
+		{"Inline fragment without type condition", `{ ... { a } ... @include(if: true) { b } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newParser(t, tt.input).ParseDocument(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseDocument_Quirks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Quirk
+	}{
+		{"Implements separated by comma", `type A implements B, C { a: Int }`, []Quirk{{QuirkImplementsWithoutAmpersand, 21}}},
+		{"Implements separated by space", `interface A implements B C & D`, []Quirk{{QuirkImplementsWithoutAmpersand, 25}}},
+		{"Empty fields", `type A {} input B {} enum C {}`, []Quirk{{QuirkEmptyFields, 7}, {QuirkEmptyFields, 18}, {QuirkEmptyFields, 28}}},
+		{"Empty arguments definition", `type A { a(): Int }`, []Quirk{{QuirkEmptyArguments, 10}}},
+		{"Empty arguments", `query Q() { a() }`, []Quirk{{QuirkEmptyArguments, 7}, {QuirkEmptyArguments, 13}}},
+		{"Empty selection set", `{ a {} }`, []Quirk{{QuirkEmptySelectionSet, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newParser(t, tt.input).ParseDocument()
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected syntax error in strict mode, got %v", err)
+			}
+			if expected := tt.expected[0]; syntaxErr.Position != expected.Position || syntaxErr.Err.Error() != expected.Kind.String() {
+				t.Errorf("expected %q at %d, got %v", expected.Kind, expected.Position, syntaxErr)
+			}
+
+			p, err := New(lexer.New(tt.input), WithMode(ModeLenient))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := p.ParseDocument(); err != nil {
+				t.Fatalf("unexpected error in lenient mode: %v", err)
+			}
+			if !reflect.DeepEqual(p.Quirks(), tt.expected) {
+				t.Errorf("expected quirks %v, got %v", tt.expected, p.Quirks())
+			}
+		})
+	}
+}
+
+func TestParseDocument_TrailingCommas(t *testing.T) {
+	input := `
+type A implements B & C, { a(x: Int, y: [Int] = [1, 2,],): Int, }
+enum E { X, Y, }
+query Q($v: In = { a: 1, }, ) { a(x: 1, y: [1,],), b, }`
+	for _, mode := range []Mode{ModeStrict, ModeLenient} {
+		p, err := New(lexer.New(input), WithMode(mode))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := p.ParseDocument(); err != nil {
+			t.Fatalf("unexpected error in mode %d: %v", mode, err)
+		}
+		if len(p.Quirks()) > 0 {
+			t.Errorf("expected no quirks in mode %d, got %v", mode, p.Quirks())
+		}
+	}
+}
+
+func TestQuirkKind_String(t *testing.T) {
+	if got := QuirkEmptyFields.String(); got != "empty fields definition" {
+		t.Errorf("expected empty fields definition, got %s", got)
+	}
+	if got := QuirkKind(42).String(); got != "QuirkKind(42)" {
+		t.Errorf("expected QuirkKind(42), got %s", got)
+	}
+}
+
+func TestParseDocument_AnonymousOperations(t *testing.T) {
+	doc, err := newParser(t, `{ a } query { b } mutation M { c } subscription { d }`).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []struct {
+		opType ast.OperationType
+		name   string
+		field  string
+	}{
+		{ast.OperationTypeQuery, "", "a"},
+		{ast.OperationTypeQuery, "", "b"},
+		{ast.OperationTypeMutation, "M", "c"},
+		{ast.OperationTypeSubscription, "", "d"},
+	}
+	if len(doc.Definitions) != len(expected) {
+		t.Fatalf("expected %d definitions, got %d", len(expected), len(doc.Definitions))
+	}
+	for i, e := range expected {
+		op, ok := doc.Definitions[i].(*ast.OperationDefinition)
+		if !ok {
+			t.Fatalf("definition %d: expected *ast.OperationDefinition, got %T", i, doc.Definitions[i])
+		}
+		name := ""
+		if op.Name != nil {
+			name = op.Name.Value
+		}
+		if op.OperationType != e.opType || name != e.name {
+			t.Errorf("definition %d: expected %s %q, got %s %q", i, e.opType, e.name, op.OperationType, name)
+		}
+		if field := op.SelectionSet.Selections[0].(*ast.Field).Name.Value; field != e.field {
+			t.Errorf("definition %d: expected field %s, got %s", i, e.field, field)
+		}
+	}
+}
+
+func TestParseDocument_AnonymousOperationPosition(t *testing.T) {
+	input := "type A { a: Int }\n{ a }"
+	doc, err := newParser(t, input).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	op := doc.Definitions[1].(*ast.OperationDefinition)
+	if got := input[op.Pos():op.End()]; got != "{ a }" {
+		t.Errorf("expected source %q, got %q", "{ a }", got)
+	}
+}
+
+func TestParseDocument_FragmentDirectives(t *testing.T) {
+	doc, err := newParser(t, `fragment F on T @a @b(c: 1) { d }`).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frag := doc.Definitions[0].(*ast.FragmentDefinition)
+	var names []string
+	for _, d := range frag.Directives {
+		names = append(names, d.Name.Value)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("expected directives [a b], got %v", names)
+	}
+	if len(frag.Directives[1].Arguments) != 1 {
+		t.Errorf("expected 1 argument of @b, got %d", len(frag.Directives[1].Arguments))
+	}
+	if field := frag.SelectionSet.Selections[0].(*ast.Field).Name.Value; field != "d" {
+		t.Errorf("expected field d, got %s", field)
+	}
+}
+
+func TestParseDocument_SchemaExtension(t *testing.T) {
+	tests := []struct {
+		input      string
+		directives int
+		roots      []ast.OperationType
+	}{
+		{`extend schema @a`, 1, nil},
+		{`extend schema { query: Q mutation: M }`, 0, []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation}},
+		{`extend schema @a @b { subscription: S }`, 2, []ast.OperationType{ast.OperationTypeSubscription}},
+	}
+	for _, tt := range tests {
+		doc, err := newParser(t, tt.input).ParseDocument()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.input, err)
+		}
+		ext := doc.Definitions[0].(*ast.SchemaExtension)
+		if len(ext.Directives) != tt.directives {
+			t.Errorf("%s: expected %d directives, got %d", tt.input, tt.directives, len(ext.Directives))
+		}
+		var roots []ast.OperationType
+		for _, def := range ext.RootOperationDefs {
+			roots = append(roots, def.OperationType)
+		}
+		if !reflect.DeepEqual(roots, tt.roots) {
+			t.Errorf("%s: expected root operation types %v, got %v", tt.input, tt.roots, roots)
+		}
+	}
+
+	for _, input := range []string{`extend schema`, `extend schema {}`, "extend schema\ntype A { a: Int }"} {
+		if _, err := newParser(t, input).ParseDocument(); err == nil {
+			t.Errorf("%q: expected error for empty schema extension", input)
+		}
+	}
+}
+
+func TestParseDocument_SkipsComments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"# a\nquery # b\nQ # c\n( # d\n$v # e\n: Int # f\n) # g\n{ # h\na # i\n} # j", `query Q($v: Int) { a }`},
+		{"type A # a\n{ # b\na # c\n( # d\nb: Int # e\n): Int # f\n}", `type A { a(b: Int): Int }`},
+		{"# only a comment", ``},
+	}
+	for _, tt := range tests {
+		doc, err := newParser(t, tt.input).ParseDocument()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.input, err)
+		}
+		expected, err := newParser(t, tt.expected).ParseDocument()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ast.EqualDocument(doc, expected) {
+			t.Errorf("%q: expected document equal to %q", tt.input, tt.expected)
+		}
+	}
+}
+
+func TestParseDocument_Comments(t *testing.T) {
+	input := "# header\ntype A { # open\n  a: Int # field\n}\n#end"
+	p, err := New(lexer.New(input), WithComments())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.ParseDocument(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []token.Token{
+		{Type: token.COMMENT, Literal: " header", Start: 0, End: 8},
+		{Type: token.COMMENT, Literal: " open", Start: 18, End: 24},
+		{Type: token.COMMENT, Literal: " field", Start: 34, End: 41},
+		{Type: token.COMMENT, Literal: "end", Start: 44, End: 48},
+	}
+	if !reflect.DeepEqual(p.Comments(), expected) {
+		t.Errorf("expected comments %v, got %v", expected, p.Comments())
+	}
+
+	if p = newParser(t, input); p.Comments() != nil {
+		t.Errorf("expected no comments without WithComments, got %v", p.Comments())
+	}
+}
+
+func TestParseDocument_ErrorRecovery(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  []string // Definition kinds, "bad" for *ast.BadNode.
+		positions []int    // Positions of errors.
+	}{
+		{"Valid", `type A { a: Int }`, []string{"type"}, nil},
+		{"Broken field", "type A { a: }\ntype B { b: Int }", []string{"bad", "type"}, []int{12}},
+		{"Broken selection", "query { a(: 1) { b } }\n{ c }\nfragment F on T { d }", []string{"bad", "op", "fragment"}, []int{10}},
+		{"Unknown keyword", "foo bar\n\"Desc\" type A { a: Int }", []string{"bad", "type"}, []int{0}},
+		{"Several errors", "type A { a: }\n{ b(: 1) }\ntype C { c: Int }", []string{"bad", "bad", "type"}, []int{12, 18}},
+		{"Unclosed braces", "{ a { b }\ntype C { c: Int }", []string{"bad"}, []int{27}},
+		{"Lexical error", "type A { a: Int }\ntype B { b: % }\ntype C { c: Int }", []string{"type", "bad"}, []int{29}},
+		{"Legacy syntax", "type A {}\ntype B { b: Int }", []string{"bad", "type"}, []int{7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(lexer.New(tt.input), WithErrorRecovery())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			doc, err := p.ParseDocument()
+			if doc == nil {
+				t.Fatalf("expected partial document, got error %v", err)
+			}
+			var kinds []string
+			for _, def := range doc.Definitions {
+				switch def.(type) {
+				case *ast.BadNode:
+					kinds = append(kinds, "bad")
+				case *ast.ObjectTypeDefinition:
+					kinds = append(kinds, "type")
+				case *ast.OperationDefinition:
+					kinds = append(kinds, "op")
+				case *ast.FragmentDefinition:
+					kinds = append(kinds, "fragment")
+				}
+			}
+			if !reflect.DeepEqual(kinds, tt.expected) {
+				t.Errorf("expected definitions %v, got %v", tt.expected, kinds)
+			}
+			var positions []int
+			var errs SyntaxErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatalf("expected SyntaxErrors, got %T", err)
+			}
+			for _, e := range errs {
+				positions = append(positions, e.Position)
+			}
+			if !reflect.DeepEqual(positions, tt.positions) {
+				t.Errorf("expected errors at %v, got %v", tt.positions, err)
+			}
+		})
+	}
+}
+
+func TestParseDocument_ErrorRecoveryBadNode(t *testing.T) {
+	input := "type A { a: }\n\"Desc\" type B { b: Int }"
+	p, err := New(lexer.New(input), WithErrorRecovery())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, _ := p.ParseDocument()
+	bad, ok := doc.Definitions[0].(*ast.BadNode)
+	if !ok || input[bad.Pos():bad.End()] != "type A { a: }" {
+		t.Errorf("expected bad node spanning broken definition, got %+v", doc.Definitions[0])
+	}
+	if def, ok := doc.Definitions[1].(*ast.ObjectTypeDefinition); !ok || def.Description == nil {
+		t.Errorf("expected described type B, got %+v", doc.Definitions[1])
+	}
+
+	if _, err := newParser(t, input).ParseDocument(); err == nil {
+		t.Errorf("expected error without recovery")
+	}
+}
+
+func TestParseDocument_ImplementsFollowedByDefinition(t *testing.T) {
+	doc, err := newParser(t, "type A implements B\ntype C implements D").ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.Definitions) != 2 {
+		t.Errorf("expected 2 definitions, got %d", len(doc.Definitions))
+	}
+}
+
+func TestParseDocument_LeadingSeparators(t *testing.T) {
+	doc, err := newParser(t, `
+union U = | A | B
+extend union V = | C
+type T implements & I & J { a: Int }
+extend interface K implements & L
+directive @d on | FIELD | QUERY
+`).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	union := doc.Definitions[0].(*ast.UnionTypeDefinition)
+	if len(union.Types) != 2 || union.Types[0].Name.Value != "A" {
+		t.Errorf("unexpected union members: %+v", union.Types)
+	}
+	unionExt := doc.Definitions[1].(*ast.UnionTypeExtension)
+	if len(unionExt.Types) != 1 || unionExt.Types[0].Name.Value != "C" {
+		t.Errorf("unexpected union extension members: %+v", unionExt.Types)
+	}
+	obj := doc.Definitions[2].(*ast.ObjectTypeDefinition)
+	if len(obj.Interfaces) != 2 || obj.Interfaces[0].Name.Value != "I" {
+		t.Errorf("unexpected interfaces: %+v", obj.Interfaces)
+	}
+	ifaceExt := doc.Definitions[3].(*ast.InterfaceTypeExtension)
+	if len(ifaceExt.Interfaces) != 1 || ifaceExt.Interfaces[0].Name.Value != "L" {
+		t.Errorf("unexpected interface extension interfaces: %+v", ifaceExt.Interfaces)
+	}
+	directive := doc.Definitions[4].(*ast.DirectiveDefinition)
+	if len(directive.Locations) != 2 || directive.Locations[0].Value != "FIELD" {
+		t.Errorf("unexpected directive locations: %+v", directive.Locations)
+	}
+}
+
+func TestParseDocument_DoubleLeadingSeparators(t *testing.T) {
+	for _, input := range []string{
+		`union U = | | A`,
+		`type T implements & & I`,
+		`directive @d on | | FIELD`,
+		`union U = |`,
+	} {
+		if _, err := newParser(t, input).ParseDocument(); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseDocument_Descriptions(t *testing.T) {
+	input := `"schema" schema { query: Q }
+"""
+object
+"""
+type Q {
+  "field" f("arg" a: Int): Int
+}
+"enum" enum E { "value" V }
+"directive" directive @d on FIELD`
+	doc, err := newParser(t, input).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj := doc.Definitions[1].(*ast.ObjectTypeDefinition)
+	enum := doc.Definitions[2].(*ast.EnumTypeDefinition)
+	nodes := []ast.Describable{
+		doc.Definitions[0].(*ast.SchemaDefinition),
+		obj,
+		obj.Fields[0],
+		obj.Fields[0].Arguments[0],
+		enum,
+		enum.Values[0],
+		doc.Definitions[3].(*ast.DirectiveDefinition),
+	}
+	expected := []string{"schema", "object", "field", "arg", "enum", "value", "directive"}
+	for i, node := range nodes {
+		desc := node.GetDescription()
+		if desc == nil {
+			t.Fatalf("%T: expected description", node)
+		}
+		if desc.Value != expected[i] {
+			t.Errorf("%T: expected description %q, got %q", node, expected[i], desc.Value)
+		}
+		if desc.Pos() != node.Pos() {
+			t.Errorf("%T: expected description to start node", node)
+		}
+		if src := input[desc.Pos():desc.End()]; src[0] != '"' || src[len(src)-1] != '"' {
+			t.Errorf("%T: unexpected description span %q", node, src)
+		}
+	}
+	if !obj.Description.Block || obj.Fields[0].Description.Block {
+		t.Errorf("unexpected block flags")
+	}
+}
+
+func TestParseDocument_OperationDescriptions(t *testing.T) {
+	input := `"op" query Q("var" $a: Int, $b: Int) { a }
+"""
+fragment
+"""
+fragment F on Q { a }`
+	p, err := New(lexer.New(input), WithExperimentalOperationDescriptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+	nodes := []ast.Describable{op, op.VariableDefs[0], doc.Definitions[1].(*ast.FragmentDefinition)}
+	expected := []string{"op", "var", "fragment"}
+	for i, node := range nodes {
+		desc := node.GetDescription()
+		if desc == nil {
+			t.Fatalf("%T: expected description", node)
+		}
+		if desc.Value != expected[i] {
+			t.Errorf("%T: expected description %q, got %q", node, expected[i], desc.Value)
+		}
+		if desc.Pos() != node.Pos() {
+			t.Errorf("%T: expected description to start node", node)
+		}
+	}
+	if op.VariableDefs[1].Description != nil {
+		t.Errorf("expected no description, got %q", op.VariableDefs[1].Description.Value)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"op" query { a }`, "unexpected description of query definition"},
+		{`"fragment" fragment F on Q { a }`, "unexpected description of fragment definition"},
+		{`query ("var" $a: Int) { a }`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := newParser(t, tt.input).ParseDocument()
+			if err == nil {
+				t.Fatal("expected error without WithExperimentalOperationDescriptions, got none")
+			}
+			if tt.expected != "" && !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %q", tt.expected, err)
+			}
+		})
+	}
+
+	p, err = New(lexer.New(`"shorthand" { a }`), WithExperimentalOperationDescriptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.ParseDocument(); err == nil {
+		t.Error("expected error for description of query shorthand, got none")
+	}
+}
+
+func TestParseDocument_FragmentPositions(t *testing.T) {
+	doc, err := newParser(t, `{ ...F ... on T { a } ... { b } }`).ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	selections := doc.Definitions[0].(*ast.OperationDefinition).SelectionSet.Selections
+	for i, expected := range []int{2, 7, 22} {
+		if got := selections[i].Pos(); got != expected {
+			t.Errorf("selection %d: expected position %d, got %d", i, expected, got)
+		}
+	}
+	if selections[2].(*ast.InlineFragment).TypeCondition != nil {
+		t.Errorf("expected inline fragment without type condition")
+	}
+}
+
+func TestParseDocument_NodeSpans(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected map[string]string // Source of first node of each type.
+	}{
+		{
+			input: `query Q($v: [Int!]! = [1] @d) @d { a: f(x: $v, y: 1.5, s: "s", b: true, n: null, e: E, o: {k: 1}) @d { ...F @d ... on T { b } } } fragment F on T @d { c }`,
+			expected: map[string]string{
+				"*ast.OperationDefinition": `query Q($v: [Int!]! = [1] @d) @d { a: f(x: $v, y: 1.5, s: "s", b: true, n: null, e: E, o: {k: 1}) @d { ...F @d ... on T { b } } }`,
+				"*ast.Name":                "Q",
+				"*ast.VariableDefinition":  "$v: [Int!]! = [1] @d",
+				"*ast.Variable":            "$v",
+				"*ast.NonNullType":         "[Int!]!",
+				"*ast.ListType":            "[Int!]",
+				"*ast.NamedType":           "Int",
+				"*ast.ListValue":           "[1]",
+				"*ast.IntValue":            "1",
+				"*ast.Directive":           "@d",
+				"*ast.SelectionSet":        `{ a: f(x: $v, y: 1.5, s: "s", b: true, n: null, e: E, o: {k: 1}) @d { ...F @d ... on T { b } } }`,
+				"*ast.Field":               `a: f(x: $v, y: 1.5, s: "s", b: true, n: null, e: E, o: {k: 1}) @d { ...F @d ... on T { b } }`,
+				"*ast.Argument":            "x: $v",
+				"*ast.FloatValue":          "1.5",
+				"*ast.StringValue":         `"s"`,
+				"*ast.BooleanValue":        "true",
+				"*ast.NullValue":           "null",
+				"*ast.EnumValue":           "E",
+				"*ast.ObjectValue":         "{k: 1}",
+				"*ast.ObjectField":         "k: 1",
+				"*ast.FragmentSpread":      "...F @d",
+				"*ast.InlineFragment":      "... on T { b }",
+				"*ast.FragmentDefinition":  "fragment F on T @d { c }",
+			},
+		},
+		{
+			input: `"d" schema @d { query: Q } extend schema @d { mutation: M } scalar S @d extend scalar S @d "t" type T implements I @d { "f" f("a" a: Int = 1 @d): [T!] } extend type T { g: Int } interface I { f: Int } extend interface I @d union U = A | B extend union U = C enum E { A @d } extend enum E { B } input In { a: Int = 1 @d } extend input In { b: Int } directive @d("x" x: Int) repeatable on FIELD | QUERY`,
+			expected: map[string]string{
+				"*ast.SchemaDefinition":            `"d" schema @d { query: Q }`,
+				"*ast.Description":                 `"d"`,
+				"*ast.Directive":                   "@d",
+				"*ast.Name":                        "d",
+				"*ast.RootOperationTypeDefinition": "query: Q",
+				"*ast.NamedType":                   "Q",
+				"*ast.SchemaExtension":             "extend schema @d { mutation: M }",
+				"*ast.ScalarTypeDefinition":        "scalar S @d",
+				"*ast.ScalarTypeExtension":         "extend scalar S @d",
+				"*ast.ObjectTypeDefinition":        `"t" type T implements I @d { "f" f("a" a: Int = 1 @d): [T!] }`,
+				"*ast.FieldDefinition":             `"f" f("a" a: Int = 1 @d): [T!]`,
+				"*ast.InputValueDefinition":        `"a" a: Int = 1 @d`,
+				"*ast.IntValue":                    "1",
+				"*ast.ListType":                    "[T!]",
+				"*ast.NonNullType":                 "T!",
+				"*ast.ObjectTypeExtension":         "extend type T { g: Int }",
+				"*ast.InterfaceTypeDefinition":     "interface I { f: Int }",
+				"*ast.InterfaceTypeExtension":      "extend interface I @d",
+				"*ast.UnionTypeDefinition":         "union U = A | B",
+				"*ast.UnionTypeExtension":          "extend union U = C",
+				"*ast.EnumTypeDefinition":          "enum E { A @d }",
+				"*ast.EnumValueDefinition":         "A @d",
+				"*ast.EnumTypeExtension":           "extend enum E { B }",
+				"*ast.InputObjectTypeDefinition":   "input In { a: Int = 1 @d }",
+				"*ast.InputObjectTypeExtension":    "extend input In { b: Int }",
+				"*ast.DirectiveDefinition":         `directive @d("x" x: Int) repeatable on FIELD | QUERY`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		doc, err := newParser(t, tt.input).ParseDocument()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := make(map[string]string)
+		ast.WalkDocument(ast.VisitorFuncs{EnterFunc: func(n ast.Node) ast.Action {
+			kind := fmt.Sprintf("%T", n)
+			if _, ok := got[kind]; !ok {
+				got[kind] = tt.input[n.Pos():n.End()]
+			}
+			return ast.Continue
+		}}, doc)
+		for kind, expected := range tt.expected {
+			if got[kind] != expected {
+				t.Errorf("%s: expected source %q, got %q", kind, expected, got[kind])
+			}
+		}
+		for kind := range got {
+			if _, ok := tt.expected[kind]; !ok {
+				t.Errorf("%s: unexpected node", kind)
+			}
+		}
+	}
+}
+
+func TestParseDocument_Interning(t *testing.T) {
+	parse := func(input string, opts ...Option) *ast.ObjectTypeDefinition {
+		p, err := New(lexer.New(input), opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		doc, err := p.ParseDocument()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return doc.Definitions[0].(*ast.ObjectTypeDefinition)
+	}
+	data := func(name *ast.Name) *byte { return unsafe.StringData(name.Value) }
+
+	input := `type Account { id: ID }`
+	a := parse(input, WithInterning())
+	b := parse(strings.Clone(input), WithInterning())
+	if a.Name.Value != "Account" || data(a.Name) != data(b.Name) || data(a.Fields[0].Name) != data(b.Fields[0].Name) {
+		t.Errorf("expected names to be interned")
+	}
+	if data(a.Name) == unsafe.StringData(input[5:]) {
+		t.Errorf("expected interned name not to refer to source")
+	}
+
+	if c := parse(input); data(c.Name) != unsafe.StringData(input[5:]) {
+		t.Errorf("expected names to refer to source without interning")
+	}
+}
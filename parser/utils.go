@@ -5,3 +5,13 @@ import "github.com/gqlhub/gqlhub-core/token"
 func IsStringValue(tok token.Type) bool {
 	return tok == token.STRING || tok == token.BLOCK_STRING
 }
+
+// isDefinitionKeyword reports whether name starts a definition.
+func isDefinitionKeyword(name string) bool {
+	switch name {
+	case "schema", "scalar", "type", "interface", "union", "enum", "input", "directive",
+		"query", "mutation", "subscription", "fragment", "extend":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package printer
+
+import "github.com/gqlhub/gqlhub-core/ast"
+
+// Comments are comments printed around a node. Texts exclude the leading
+// '#', as literals of COMMENT tokens do.
+type Comments struct {
+	Leading  []string // Lines before node.
+	Trailing string   // End of the last line of node.
+	After    []string // Lines after node, e.g. comments before closing brace.
+}
+
+// WithComments makes printer print comments returned by comments around
+// nodes printed on lines of their own: definitions of documents,
+// selections, field definitions, input field definitions, enum value
+// definitions and root operation type definitions. comments returns nil
+// for nodes without comments.
+func WithComments(comments func(ast.Node) *Comments) Option {
+	return func(p *Printer) {
+		p.comments = comments
+	}
+}
+
+// item prints node printed on a line of its own, with its comments.
+func (w *writer) item(n ast.Node) {
+	var c *Comments
+	if w.comments != nil {
+		c = w.comments(n)
+	}
+	if c == nil {
+		w.node(n)
+		return
+	}
+	for _, text := range c.Leading {
+		w.str("#" + text)
+		w.newline()
+	}
+	w.node(n)
+	if c.Trailing != "" {
+		w.str(" #" + c.Trailing)
+	}
+	for _, text := range c.After {
+		w.newline()
+		w.str("#" + text)
+	}
+}
@@ -0,0 +1,402 @@
+package printer
+
+import (
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// PrintCompact returns the shortest source of doc printed with opts, for
+// persisted query registries and cache keys. See Printer.Compact.
+func PrintCompact(doc *ast.Document, opts ...Option) string {
+	return New(opts...).Compact(doc)
+}
+
+// Compact returns the shortest source of doc: tokens are separated by a
+// space only where they would merge otherwise, commas are omitted, block
+// strings are printed as strings unless block string is shorter, and
+// anonymous queries without description, variables and directives use
+// the query shorthand. Number formats and string encodings of p apply; layout
+// options, comments and source maps do not.
+func (p *Printer) Compact(doc *ast.Document) string {
+	p.reset()
+	c := &compactWriter{Printer: p}
+	for _, def := range doc.Definitions {
+		c.node(def)
+	}
+	return c.b.String()
+}
+
+// compactWriter holds state of a single Compact call.
+type compactWriter struct {
+	*Printer
+	b strings.Builder
+}
+
+// tok writes token s, separated by a space from the previous token when
+// they would otherwise be read as one: names, numbers and keywords, or
+// adjacent strings, which could read as block string quotes.
+func (c *compactWriter) tok(s string) {
+	if c.b.Len() > 0 && s != "" {
+		prev := c.b.String()[c.b.Len()-1]
+		if isNameContinue(prev) && isNameContinue(s[0]) || prev == '"' && s[0] == '"' {
+			c.b.WriteByte(' ')
+		}
+	}
+	c.b.WriteString(s)
+}
+
+func isNameContinue(ch byte) bool {
+	return ch == '_' || '0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z'
+}
+
+func (c *compactWriter) node(n ast.Node) {
+	switch n := n.(type) {
+	case ast.Value:
+		c.value(n)
+	case *ast.OperationDefinition:
+		c.description(n.Description)
+		if n.OperationType != ast.OperationTypeQuery || n.Description != nil || n.Name != nil || len(n.VariableDefs) > 0 || len(n.Directives) > 0 {
+			c.tok(string(n.OperationType))
+			c.name(n.Name)
+		}
+		if len(n.VariableDefs) > 0 {
+			c.tok("(")
+			for _, v := range n.VariableDefs {
+				c.node(v)
+			}
+			c.tok(")")
+		}
+		c.directives(n.Directives)
+		c.node(n.SelectionSet)
+	case *ast.FragmentDefinition:
+		c.description(n.Description)
+		c.tok("fragment")
+		c.name(n.Name)
+		c.tok("on")
+		c.name(n.TypeCondition.Name)
+		c.directives(n.Directives)
+		c.node(n.SelectionSet)
+	case *ast.SelectionSet:
+		c.tok("{")
+		for _, sel := range n.Selections {
+			c.node(sel)
+		}
+		c.tok("}")
+	case *ast.Field:
+		if n.Alias != nil {
+			c.name(n.Alias)
+			c.tok(":")
+		}
+		c.name(n.Name)
+		c.arguments(n.Arguments)
+		c.directives(n.Directives)
+		if n.SelectionSet != nil {
+			c.node(n.SelectionSet)
+		}
+	case *ast.FragmentSpread:
+		c.tok("...")
+		c.name(n.Name)
+		c.directives(n.Directives)
+	case *ast.InlineFragment:
+		c.tok("...")
+		if n.TypeCondition != nil {
+			c.tok("on")
+			c.name(n.TypeCondition.Name)
+		}
+		c.directives(n.Directives)
+		c.node(n.SelectionSet)
+	case *ast.VariableDefinition:
+		c.description(n.Description)
+		c.value(n.Variable)
+		c.tok(":")
+		c.node(n.Type)
+		if n.DefaultValue != nil {
+			c.tok("=")
+			c.value(n.DefaultValue)
+		}
+		c.directives(n.Directives)
+	case *ast.Directive:
+		c.tok("@")
+		c.name(n.Name)
+		c.arguments(n.Arguments)
+	case *ast.Argument:
+		c.name(n.Name)
+		c.tok(":")
+		c.value(n.Value)
+	case *ast.Name:
+		c.name(n)
+	case *ast.NamedType:
+		c.name(n.Name)
+	case *ast.ListType:
+		c.tok("[")
+		c.node(n.Type)
+		c.tok("]")
+	case *ast.NonNullType:
+		c.node(n.Type)
+		c.tok("!")
+	case *ast.Description:
+		c.str(n.Value, n.Block)
+
+	case *ast.SchemaDefinition:
+		c.description(n.Description)
+		c.tok("schema")
+		c.directives(n.Directives)
+		c.operationTypes(n.RootOperationDefs)
+	case *ast.SchemaExtension:
+		c.tok("extend schema")
+		c.directives(n.Directives)
+		c.operationTypes(n.RootOperationDefs)
+	case *ast.RootOperationTypeDefinition:
+		c.tok(string(n.OperationType))
+		c.tok(":")
+		c.name(n.Type.Name)
+	case *ast.ScalarTypeDefinition:
+		c.description(n.Description)
+		c.keyword("scalar", n.Name)
+		c.directives(n.Directives)
+	case *ast.ScalarTypeExtension:
+		c.keyword("extend scalar", n.Name)
+		c.directives(n.Directives)
+	case *ast.ObjectTypeDefinition:
+		c.description(n.Description)
+		c.keyword("type", n.Name)
+		c.interfaces(n.Interfaces)
+		c.directives(n.Directives)
+		c.fieldDefinitions(n.Fields)
+	case *ast.ObjectTypeExtension:
+		c.keyword("extend type", n.Name)
+		c.interfaces(n.Interfaces)
+		c.directives(n.Directives)
+		c.fieldDefinitions(n.Fields)
+	case *ast.InterfaceTypeDefinition:
+		c.description(n.Description)
+		c.keyword("interface", n.Name)
+		c.interfaces(n.Interfaces)
+		c.directives(n.Directives)
+		c.fieldDefinitions(n.Fields)
+	case *ast.InterfaceTypeExtension:
+		c.keyword("extend interface", n.Name)
+		c.interfaces(n.Interfaces)
+		c.directives(n.Directives)
+		c.fieldDefinitions(n.Fields)
+	case *ast.UnionTypeDefinition:
+		c.description(n.Description)
+		c.keyword("union", n.Name)
+		c.directives(n.Directives)
+		c.members(n.Types)
+	case *ast.UnionTypeExtension:
+		c.keyword("extend union", n.Name)
+		c.directives(n.Directives)
+		c.members(n.Types)
+	case *ast.EnumTypeDefinition:
+		c.description(n.Description)
+		c.keyword("enum", n.Name)
+		c.directives(n.Directives)
+		c.enumValues(n.Values)
+	case *ast.EnumTypeExtension:
+		c.keyword("extend enum", n.Name)
+		c.directives(n.Directives)
+		c.enumValues(n.Values)
+	case *ast.EnumValueDefinition:
+		c.description(n.Description)
+		c.name(n.Name)
+		c.directives(n.Directives)
+	case *ast.InputObjectTypeDefinition:
+		c.description(n.Description)
+		c.keyword("input", n.Name)
+		c.directives(n.Directives)
+		c.inputValues("{", n.Fields, "}")
+	case *ast.InputObjectTypeExtension:
+		c.keyword("extend input", n.Name)
+		c.directives(n.Directives)
+		c.inputValues("{", n.Fields, "}")
+	case *ast.FieldDefinition:
+		c.description(n.Description)
+		c.name(n.Name)
+		c.inputValues("(", n.Arguments, ")")
+		c.tok(":")
+		c.node(n.Type)
+		c.directives(n.Directives)
+	case *ast.InputValueDefinition:
+		c.description(n.Description)
+		c.name(n.Name)
+		c.tok(":")
+		c.node(n.Type)
+		if n.DefaultValue != nil {
+			c.tok("=")
+			c.value(n.DefaultValue)
+		}
+		c.directives(n.Directives)
+	case *ast.DirectiveDefinition:
+		c.description(n.Description)
+		c.tok("directive")
+		c.tok("@")
+		c.name(n.Name)
+		c.inputValues("(", n.Arguments, ")")
+		if n.Repeatable {
+			c.tok("repeatable")
+		}
+		c.tok("on")
+		for i, loc := range n.Locations {
+			if i > 0 {
+				c.tok("|")
+			}
+			c.name(loc)
+		}
+	}
+}
+
+func (c *compactWriter) name(n *ast.Name) {
+	if n != nil {
+		c.tok(n.Value)
+	}
+}
+
+func (c *compactWriter) keyword(keyword string, name *ast.Name) {
+	c.tok(keyword)
+	c.name(name)
+}
+
+func (c *compactWriter) description(d *ast.Description) {
+	if d != nil {
+		c.node(d)
+	}
+}
+
+func (c *compactWriter) directives(directives []*ast.Directive) {
+	for _, d := range directives {
+		c.node(d)
+	}
+}
+
+func (c *compactWriter) arguments(args []*ast.Argument) {
+	if len(args) == 0 {
+		return
+	}
+	c.tok("(")
+	for _, arg := range args {
+		c.node(arg)
+	}
+	c.tok(")")
+}
+
+func (c *compactWriter) inputValues(open string, values []*ast.InputValueDefinition, close string) {
+	if len(values) == 0 {
+		return
+	}
+	c.tok(open)
+	for _, v := range values {
+		c.node(v)
+	}
+	c.tok(close)
+}
+
+func (c *compactWriter) interfaces(interfaces []*ast.NamedType) {
+	for i, t := range interfaces {
+		if i == 0 {
+			c.tok("implements")
+		} else {
+			c.tok("&")
+		}
+		c.name(t.Name)
+	}
+}
+
+func (c *compactWriter) members(types []*ast.NamedType) {
+	for i, t := range types {
+		if i == 0 {
+			c.tok("=")
+		} else {
+			c.tok("|")
+		}
+		c.name(t.Name)
+	}
+}
+
+func (c *compactWriter) operationTypes(defs []*ast.RootOperationTypeDefinition) {
+	if len(defs) == 0 {
+		return
+	}
+	c.tok("{")
+	for _, def := range defs {
+		c.node(def)
+	}
+	c.tok("}")
+}
+
+func (c *compactWriter) fieldDefinitions(fields []*ast.FieldDefinition) {
+	if len(fields) == 0 {
+		return
+	}
+	c.tok("{")
+	for _, f := range fields {
+		c.node(f)
+	}
+	c.tok("}")
+}
+
+func (c *compactWriter) enumValues(values []*ast.EnumValueDefinition) {
+	if len(values) == 0 {
+		return
+	}
+	c.tok("{")
+	for _, v := range values {
+		c.node(v)
+	}
+	c.tok("}")
+}
+
+func (c *compactWriter) value(v ast.Value) {
+	switch v := v.(type) {
+	case *ast.Variable:
+		c.tok("$")
+		c.name(v.Name)
+	case *ast.IntValue:
+		c.tok(c.formatInt(v.Value))
+	case *ast.FloatValue:
+		c.tok(c.formatFloat(v.Value))
+	case *ast.StringValue:
+		c.str(v.Value, v.Block)
+	case *ast.BooleanValue:
+		if v.Value {
+			c.tok("true")
+		} else {
+			c.tok("false")
+		}
+	case *ast.NullValue:
+		c.tok("null")
+	case *ast.EnumValue:
+		c.tok(v.Value)
+	case *ast.ListValue:
+		c.tok("[")
+		for _, item := range v.Values {
+			c.value(item)
+		}
+		c.tok("]")
+	case *ast.ObjectValue:
+		c.tok("{")
+		for _, f := range v.Fields {
+			c.name(f.Name)
+			c.tok(":")
+			c.value(f.Value)
+		}
+		c.tok("}")
+	}
+}
+
+// str writes the shorter of string and, for block strings which can be
+// represented by one, block string literals of s.
+func (c *compactWriter) str(s string, block bool) {
+	var quoted strings.Builder
+	c.writeString(&quoted, s, false)
+	if block && c.isBlockString(s) {
+		var b strings.Builder
+		writeBlockString(&b, s, false)
+		if b.Len() < quoted.Len() {
+			c.tok(b.String())
+			return
+		}
+	}
+	c.tok(quoted.String())
+}
@@ -0,0 +1,77 @@
+package printer_test
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+func TestPrintCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"Query shorthand",
+			`query { a, b }`,
+			`{a b}`,
+		},
+		{
+			"Operation",
+			`query Q($id: ID! = "1" @v, $n: Int = -1) @op { user: node(id: $id, n: [1, -2, 3.5, A, "x", "", "y"]) @include(if: true) { ...F ... on User { name } ... @skip(if: false) { id } } }`,
+			`query Q($id:ID!="1"@v$n:Int=-1)@op{user:node(id:$id n:[1-2 3.5 A"x" "" "y"])@include(if:true){...F...on User{name}...@skip(if:false){id}}}`,
+		},
+		{
+			"Object values",
+			`{ a(o: {x: 1, y: {z: null}, w: ENUM}) }`,
+			`{a(o:{x:1 y:{z:null}w:ENUM})}`,
+		},
+		{
+			"Fragment",
+			`fragment F on User @f { id }`,
+			`fragment F on User@f{id}`,
+		},
+		{
+			"Block strings",
+			"{ a(s: \"\"\"line\n  next\"\"\", q: \"\"\"say \"hi\" \"now\" \"\\\\\" \"\\\\\" ok\"\"\") }",
+			`{a(s:"line\nnext"q:"""say "hi" "now" "\\" "\\" ok""")}`,
+		},
+		{
+			"Schema",
+			`"Root" schema @s { query: Query mutation: M }
+extend schema { subscription: S }
+"""Scalar""" scalar Date @specifiedBy(url: "u")
+type Query implements A & B @key(fields: "id") { "Field" f(a: Int = 1, b: [String!]! @d): [Int] }
+extend type Query { g: Int }
+interface A implements B { f: Int }
+union U @u = A | B
+enum E { X @deprecated Y }
+input I { a: Int = 1 b: I }
+directive @d(a: Int) repeatable on FIELD | ARGUMENT_DEFINITION`,
+			`"Root"schema@s{query:Query mutation:M}extend schema{subscription:S}"Scalar"scalar Date@specifiedBy(url:"u")type Query implements A&B@key(fields:"id"){"Field"f(a:Int=1 b:[String!]!@d):[Int]}extend type Query{g:Int}interface A implements B{f:Int}union U@u=A|B enum E{X@deprecated Y}input I{a:Int=1 b:I}directive@d(a:Int)repeatable on FIELD|ARGUMENT_DEFINITION`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.input)
+			got := printer.PrintCompact(doc)
+			if got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+			if !ast.EqualDocument(asttest.Parse(t, got), doc) {
+				t.Errorf("expected compact source to parse into the same document")
+			}
+		})
+	}
+}
+
+func TestPrintCompact_Options(t *testing.T) {
+	doc := asttest.Parse(t, `{ a(f: 1.50E+2, s: "é") }`)
+	expected := `{a(f:1.5e2 s:"\u00E9")}`
+	if got := printer.PrintCompact(doc, printer.WithNumberFormat(printer.NumbersCanonical), printer.WithStringEncoding(printer.StringsASCII)); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
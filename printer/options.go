@@ -0,0 +1,121 @@
+package printer
+
+// Option configures Printer.
+type Option func(*Printer)
+
+// NumberFormat controls how Int and Float literals are printed.
+type NumberFormat int
+
+const (
+	// NumbersAsWritten prints numeric literals exactly as they were parsed.
+	NumbersAsWritten NumberFormat = iota
+	// NumbersLowercaseExponent only replaces exponent indicator 'E' with
+	// 'e'.
+	NumbersLowercaseExponent
+	// NumbersCanonical prints Float literals in canonical form: lowercase
+	// exponent without '+' sign and leading zeros, no zero exponent, no
+	// trailing zeros in fraction (keeping at least one digit), and Int
+	// literal "-0" as "0". Numbers keep their value and literal kind.
+	NumbersCanonical
+)
+
+// WithNumberFormat sets how numeric literals are printed. Default is
+// NumbersAsWritten.
+func WithNumberFormat(format NumberFormat) Option {
+	return func(p *Printer) {
+		p.numbers = format
+	}
+}
+
+// StringEncoding controls how non-ASCII characters in strings are printed.
+type StringEncoding int
+
+const (
+	// StringsUTF8 prints non-ASCII characters as raw UTF-8.
+	StringsUTF8 StringEncoding = iota
+	// StringsASCII escapes non-ASCII characters so that output contains
+	// ASCII only. Block strings which cannot contain escapes are printed
+	// as regular strings when they contain non-ASCII characters.
+	StringsASCII
+)
+
+// WithStringEncoding sets how non-ASCII characters in strings are printed.
+// Default is StringsUTF8.
+func WithStringEncoding(encoding StringEncoding) Option {
+	return func(p *Printer) {
+		p.strings = encoding
+	}
+}
+
+// UnicodeEscape selects form of Unicode escape sequences.
+type UnicodeEscape int
+
+const (
+	// EscapeFixedWidth uses \uXXXX, with surrogate pairs for characters
+	// outside of the Basic Multilingual Plane. Understood by every
+	// GraphQL implementation.
+	EscapeFixedWidth UnicodeEscape = iota
+	// EscapeVariableWidth uses \u{X...} introduced in the October 2021
+	// specification.
+	EscapeVariableWidth
+)
+
+// WithUnicodeEscape sets form of Unicode escape sequences used for control
+// and, with StringsASCII, non-ASCII characters. Default is
+// EscapeFixedWidth.
+func WithUnicodeEscape(escape UnicodeEscape) Option {
+	return func(p *Printer) {
+		p.escape = escape
+	}
+}
+
+// WithIndent sets string indenting nested lines by one level. Default is
+// two spaces.
+func WithIndent(indent string) Option {
+	return func(p *Printer) {
+		p.indentUnit = indent
+	}
+}
+
+// WithLineWidth sets length of field with arguments, excluding
+// indentation, above which its arguments are printed on separate lines.
+// Default is 80.
+func WithLineWidth(width int) Option {
+	return func(p *Printer) {
+		p.lineWidth = width
+	}
+}
+
+// WithArgumentDefinitionWrapping makes argument definitions of fields and
+// directives which do not fit into line width printed on separate lines,
+// like arguments of fields. By default they are printed on separate lines
+// only when some of them have description.
+func WithArgumentDefinitionWrapping() Option {
+	return func(p *Printer) {
+		p.wrapArgDefs = true
+	}
+}
+
+// DescriptionStyle controls how descriptions are printed.
+type DescriptionStyle int
+
+const (
+	// DescriptionsAsWritten prints block string descriptions as block
+	// strings and string descriptions as strings.
+	DescriptionsAsWritten DescriptionStyle = iota
+	// DescriptionsBlock prints descriptions as block strings, keeping
+	// short ones on a single line, e.g. """Text""".
+	DescriptionsBlock
+	// DescriptionsBlockMultiline prints descriptions as block strings with
+	// quotes on lines of their own.
+	DescriptionsBlockMultiline
+)
+
+// WithDescriptionStyle sets how descriptions are printed. Descriptions
+// which cannot be represented by block strings are printed as strings
+// regardless. Default is DescriptionsAsWritten.
+func WithDescriptionStyle(style DescriptionStyle) Option {
+	return func(p *Printer) {
+		p.descriptions = style
+	}
+}
@@ -0,0 +1,496 @@
+package printer
+
+import (
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// maxLineLength is default length of field with arguments above which
+// arguments are printed on separate lines.
+const maxLineLength = 80
+
+// Print returns source of node printed with opts.
+func Print(node ast.Node, opts ...Option) string {
+	return New(opts...).Print(node)
+}
+
+// PrintDocument returns source of doc printed with opts.
+func PrintDocument(doc *ast.Document, opts ...Option) string {
+	return New(opts...).Document(doc)
+}
+
+// Print returns source of node. Definitions and selection sets are printed
+// in multi-line form indented by two spaces.
+func (p *Printer) Print(node ast.Node) string {
+	p.reset()
+	w := &writer{Printer: p}
+	w.node(node)
+	return w.b.String()
+}
+
+// Document returns source of doc with definitions separated by blank lines.
+func (p *Printer) Document(doc *ast.Document) string {
+	p.reset()
+	w := &writer{Printer: p}
+	for i, def := range doc.Definitions {
+		if i > 0 {
+			w.b.WriteString("\n\n")
+		}
+		w.item(def)
+	}
+	return w.b.String()
+}
+
+// writer holds state of a single Print call.
+type writer struct {
+	*Printer
+	b      strings.Builder
+	indent string
+}
+
+func (w *writer) str(s string) {
+	w.b.WriteString(s)
+}
+
+// newline starts new line at current indentation.
+func (w *writer) newline() {
+	w.b.WriteByte('\n')
+	w.b.WriteString(w.indent)
+}
+
+func (w *writer) node(n ast.Node) {
+	if v, ok := n.(ast.Value); ok {
+		w.writeValue(&w.b, v)
+		return
+	}
+	defer w.end(&w.b, w.begin(&w.b, n))
+
+	switch n := n.(type) {
+	case *ast.OperationDefinition:
+		w.operation(n)
+	case *ast.FragmentDefinition:
+		w.description(n.Description)
+		w.str("fragment ")
+		w.node(n.Name)
+		w.str(" on ")
+		w.node(n.TypeCondition)
+		w.directives(n.Directives)
+		w.str(" ")
+		w.node(n.SelectionSet)
+	case *ast.SelectionSet:
+		w.block(len(n.Selections), func(i int) { w.item(n.Selections[i]) })
+	case *ast.Field:
+		w.field(n)
+	case *ast.FragmentSpread:
+		w.str("...")
+		w.node(n.Name)
+		w.directives(n.Directives)
+	case *ast.InlineFragment:
+		w.str("...")
+		if n.TypeCondition != nil {
+			w.str(" on ")
+			w.node(n.TypeCondition)
+		}
+		w.directives(n.Directives)
+		w.str(" ")
+		w.node(n.SelectionSet)
+	case *ast.VariableDefinition:
+		w.description(n.Description)
+		w.node(n.Variable)
+		w.str(": ")
+		w.node(n.Type)
+		if n.DefaultValue != nil {
+			w.str(" = ")
+			w.node(n.DefaultValue)
+		}
+		w.directives(n.Directives)
+	case *ast.Directive:
+		w.str("@")
+		w.node(n.Name)
+		w.arguments(n.Arguments)
+	case *ast.Argument:
+		w.node(n.Name)
+		w.str(": ")
+		w.node(n.Value)
+	case *ast.ObjectField:
+		w.node(n.Name)
+		w.str(": ")
+		w.node(n.Value)
+	case *ast.Name:
+		w.str(n.Value)
+	case *ast.NamedType:
+		w.node(n.Name)
+	case *ast.ListType:
+		w.str("[")
+		w.node(n.Type)
+		w.str("]")
+	case *ast.NonNullType:
+		w.node(n.Type)
+		w.str("!")
+	case *ast.Description:
+		var s strings.Builder
+		w.writeDescription(&s, n)
+		for i, line := range strings.Split(s.String(), "\n") {
+			if i > 0 {
+				w.str("\n")
+				if line != "" {
+					w.str(w.indent)
+				}
+			}
+			w.str(line)
+		}
+
+	case *ast.SchemaDefinition:
+		w.description(n.Description)
+		w.str("schema")
+		w.directives(n.Directives)
+		w.operationTypes(n.RootOperationDefs)
+	case *ast.SchemaExtension:
+		w.str("extend schema")
+		w.directives(n.Directives)
+		w.operationTypes(n.RootOperationDefs)
+	case *ast.RootOperationTypeDefinition:
+		w.str(string(n.OperationType) + ": ")
+		w.node(n.Type)
+	case *ast.ScalarTypeDefinition:
+		w.description(n.Description)
+		w.keyword("scalar", n.Name)
+		w.directives(n.Directives)
+	case *ast.ScalarTypeExtension:
+		w.keyword("extend scalar", n.Name)
+		w.directives(n.Directives)
+	case *ast.ObjectTypeDefinition:
+		w.description(n.Description)
+		w.keyword("type", n.Name)
+		w.interfaces(n.Interfaces)
+		w.directives(n.Directives)
+		w.fieldDefinitions(n.Fields)
+	case *ast.ObjectTypeExtension:
+		w.keyword("extend type", n.Name)
+		w.interfaces(n.Interfaces)
+		w.directives(n.Directives)
+		w.fieldDefinitions(n.Fields)
+	case *ast.InterfaceTypeDefinition:
+		w.description(n.Description)
+		w.keyword("interface", n.Name)
+		w.interfaces(n.Interfaces)
+		w.directives(n.Directives)
+		w.fieldDefinitions(n.Fields)
+	case *ast.InterfaceTypeExtension:
+		w.keyword("extend interface", n.Name)
+		w.interfaces(n.Interfaces)
+		w.directives(n.Directives)
+		w.fieldDefinitions(n.Fields)
+	case *ast.UnionTypeDefinition:
+		w.description(n.Description)
+		w.keyword("union", n.Name)
+		w.directives(n.Directives)
+		w.members(n.Types)
+	case *ast.UnionTypeExtension:
+		w.keyword("extend union", n.Name)
+		w.directives(n.Directives)
+		w.members(n.Types)
+	case *ast.EnumTypeDefinition:
+		w.description(n.Description)
+		w.keyword("enum", n.Name)
+		w.directives(n.Directives)
+		w.enumValues(n.Values)
+	case *ast.EnumTypeExtension:
+		w.keyword("extend enum", n.Name)
+		w.directives(n.Directives)
+		w.enumValues(n.Values)
+	case *ast.EnumValueDefinition:
+		w.description(n.Description)
+		w.node(n.Name)
+		w.directives(n.Directives)
+	case *ast.InputObjectTypeDefinition:
+		w.description(n.Description)
+		w.keyword("input", n.Name)
+		w.directives(n.Directives)
+		w.inputFields(n.Fields)
+	case *ast.InputObjectTypeExtension:
+		w.keyword("extend input", n.Name)
+		w.directives(n.Directives)
+		w.inputFields(n.Fields)
+	case *ast.FieldDefinition:
+		w.description(n.Description)
+		w.node(n.Name)
+		w.argumentDefinitions(n.Arguments, n.Type)
+		w.str(": ")
+		w.node(n.Type)
+		w.directives(n.Directives)
+	case *ast.InputValueDefinition:
+		w.description(n.Description)
+		w.node(n.Name)
+		w.str(": ")
+		w.node(n.Type)
+		if n.DefaultValue != nil {
+			w.str(" = ")
+			w.node(n.DefaultValue)
+		}
+		w.directives(n.Directives)
+	case *ast.DirectiveDefinition:
+		w.description(n.Description)
+		w.str("directive @")
+		w.node(n.Name)
+		w.argumentDefinitions(n.Arguments, nil)
+		if n.Repeatable {
+			w.str(" repeatable")
+		}
+		w.str(" on ")
+		for i, loc := range n.Locations {
+			if i > 0 {
+				w.str(" | ")
+			}
+			w.node(loc)
+		}
+	}
+}
+
+// operation prints operation, using query shorthand for anonymous queries
+// without description, variables and directives. Variable definitions are
+// printed on separate lines when some of them have description.
+func (w *writer) operation(n *ast.OperationDefinition) {
+	if n.OperationType == ast.OperationTypeQuery && n.Description == nil && n.Name == nil && len(n.VariableDefs) == 0 && len(n.Directives) == 0 {
+		w.node(n.SelectionSet)
+		return
+	}
+	w.description(n.Description)
+	w.str(string(n.OperationType))
+	if n.Name != nil {
+		w.str(" ")
+		w.node(n.Name)
+	}
+	if len(n.VariableDefs) > 0 {
+		if n.Name == nil {
+			w.str(" ")
+		}
+		multiline := false
+		for _, v := range n.VariableDefs {
+			multiline = multiline || v.Description != nil
+		}
+		w.str("(")
+		if multiline {
+			w.lines(len(n.VariableDefs), func(i int) { w.node(n.VariableDefs[i]) })
+			w.newline()
+		} else {
+			for i, v := range n.VariableDefs {
+				if i > 0 {
+					w.str(", ")
+				}
+				w.node(v)
+			}
+		}
+		w.str(")")
+	}
+	w.directives(n.Directives)
+	w.str(" ")
+	w.node(n.SelectionSet)
+}
+
+// field prints field, with arguments on separate lines when they do not
+// fit into line width.
+func (w *writer) field(n *ast.Field) {
+	if n.Alias != nil {
+		w.node(n.Alias)
+		w.str(": ")
+	}
+	w.node(n.Name)
+	if len(n.Arguments) > 0 {
+		if w.fieldLength(n) > w.width() {
+			w.str("(")
+			w.lines(len(n.Arguments), func(i int) { w.node(n.Arguments[i]) })
+			w.newline()
+			w.str(")")
+		} else {
+			w.arguments(n.Arguments)
+		}
+	}
+	w.directives(n.Directives)
+	if n.SelectionSet != nil {
+		w.str(" ")
+		w.node(n.SelectionSet)
+	}
+}
+
+// fieldLength returns length of alias, name and inline arguments of n.
+func (w *writer) fieldLength(n *ast.Field) int {
+	m := &writer{Printer: w.plain()}
+	if n.Alias != nil {
+		m.str(n.Alias.Value + ": ")
+	}
+	m.str(n.Name.Value)
+	m.arguments(n.Arguments)
+	return m.b.Len()
+}
+
+// width returns line width arguments are wrapped at.
+func (w *writer) width() int {
+	if w.lineWidth > 0 {
+		return w.lineWidth
+	}
+	return maxLineLength
+}
+
+// plain returns copy of printer measuring lengths of nodes printed
+// inline, without source map, comments and wrapping.
+func (w *writer) plain() *Printer {
+	plain := *w.Printer
+	plain.sourceMap, plain.comments, plain.wrapArgDefs = nil, nil, false
+	return &plain
+}
+
+func (w *writer) keyword(keyword string, name *ast.Name) {
+	w.str(keyword + " ")
+	w.node(name)
+}
+
+func (w *writer) directives(directives []*ast.Directive) {
+	for _, d := range directives {
+		w.str(" ")
+		w.node(d)
+	}
+}
+
+func (w *writer) arguments(args []*ast.Argument) {
+	if len(args) == 0 {
+		return
+	}
+	w.str("(")
+	for i, arg := range args {
+		if i > 0 {
+			w.str(", ")
+		}
+		w.node(arg)
+	}
+	w.str(")")
+}
+
+// argumentDefinitions prints arguments inline unless some of them have
+// description or, with WithArgumentDefinitionWrapping, the current line
+// followed by them and by fieldType, when given, does not fit into line
+// width.
+func (w *writer) argumentDefinitions(args []*ast.InputValueDefinition, fieldType ast.Type) {
+	if len(args) == 0 {
+		return
+	}
+	multiline := false
+	for _, arg := range args {
+		multiline = multiline || arg.Description != nil
+	}
+	if w.wrapArgDefs && !multiline {
+		m := &writer{Printer: w.plain()}
+		m.argumentDefinitions(args, nil)
+		if fieldType != nil {
+			m.str(": ")
+			m.node(fieldType)
+		}
+		line := w.b.String()
+		line = line[strings.LastIndexByte(line, '\n')+1:]
+		multiline = len(line)-len(w.indent)+m.b.Len() > w.width()
+	}
+	w.str("(")
+	if multiline {
+		w.lines(len(args), func(i int) { w.node(args[i]) })
+		w.newline()
+	} else {
+		for i, arg := range args {
+			if i > 0 {
+				w.str(", ")
+			}
+			w.node(arg)
+		}
+	}
+	w.str(")")
+}
+
+func (w *writer) interfaces(interfaces []*ast.NamedType) {
+	for i, t := range interfaces {
+		if i == 0 {
+			w.str(" implements ")
+		} else {
+			w.str(" & ")
+		}
+		w.node(t)
+	}
+}
+
+func (w *writer) members(types []*ast.NamedType) {
+	for i, t := range types {
+		if i == 0 {
+			w.str(" = ")
+		} else {
+			w.str(" | ")
+		}
+		w.node(t)
+	}
+}
+
+func (w *writer) operationTypes(defs []*ast.RootOperationTypeDefinition) {
+	if len(defs) == 0 {
+		return
+	}
+	w.str(" ")
+	w.block(len(defs), func(i int) { w.item(defs[i]) })
+}
+
+func (w *writer) fieldDefinitions(fields []*ast.FieldDefinition) {
+	if len(fields) == 0 {
+		return
+	}
+	w.str(" ")
+	w.block(len(fields), func(i int) { w.item(fields[i]) })
+}
+
+func (w *writer) inputFields(fields []*ast.InputValueDefinition) {
+	if len(fields) == 0 {
+		return
+	}
+	w.str(" ")
+	w.block(len(fields), func(i int) { w.item(fields[i]) })
+}
+
+func (w *writer) enumValues(values []*ast.EnumValueDefinition) {
+	if len(values) == 0 {
+		return
+	}
+	w.str(" ")
+	w.block(len(values), func(i int) { w.item(values[i]) })
+}
+
+// block prints n items in braces, each on its own indented line.
+func (w *writer) block(n int, item func(i int)) {
+	w.str("{")
+	if n == 0 {
+		w.str("}")
+		return
+	}
+	w.lines(n, item)
+	w.newline()
+	w.str("}")
+}
+
+// lines prints n items each on its own line indented one level deeper.
+func (w *writer) lines(n int, item func(i int)) {
+	outer := w.indent
+	w.indent += w.indentUnit
+	if w.indentUnit == "" {
+		w.indent += "  "
+	}
+	for i := range n {
+		w.newline()
+		item(i)
+	}
+	w.indent = outer
+}
+
+// description prints description followed by line break. Lines of block
+// strings are indented, which does not change their value.
+func (w *writer) description(d *ast.Description) {
+	if d == nil {
+		return
+	}
+	w.node(d)
+	w.newline()
+}
@@ -0,0 +1,316 @@
+package printer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asthash"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+func TestPrintDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"Query shorthand",
+			`query { a }`,
+			"{\n  a\n}",
+		},
+		{
+			"Operation",
+			`query Q($id: ID! = "1" @v, $list: [Int!]) @op { user: node(id: $id) @include(if: true) { ...F ... on User { name } ... @skip(if: false) { id } } }`,
+			`query Q($id: ID! = "1" @v, $list: [Int!]) @op {
+  user: node(id: $id) @include(if: true) {
+    ...F
+    ... on User {
+      name
+    }
+    ... @skip(if: false) {
+      id
+    }
+  }
+}`,
+		},
+		{
+			"Anonymous operations",
+			`query ($a: Int) { a } mutation { b } subscription @s { c }`,
+			"query ($a: Int) {\n  a\n}\n\nmutation {\n  b\n}\n\nsubscription @s {\n  c\n}",
+		},
+		{
+			"Fragment",
+			`fragment F on User @f { id }`,
+			"fragment F on User @f {\n  id\n}",
+		},
+		{
+			"Long arguments",
+			`{ field(argument1: "aaaaaaaaaaaaaaaaaaaa", argument2: "bbbbbbbbbbbbbbbbbbbb", argument3: 3) { a } }`,
+			`{
+  field(
+    argument1: "aaaaaaaaaaaaaaaaaaaa"
+    argument2: "bbbbbbbbbbbbbbbbbbbb"
+    argument3: 3
+  ) {
+    a
+  }
+}`,
+		},
+		{
+			"Schema",
+			`"""Schema""" schema @a { query: Q mutation: M } extend schema @b`,
+			"\"\"\"Schema\"\"\"\nschema @a {\n  query: Q\n  mutation: M\n}\n\nextend schema @b",
+		},
+		{
+			"Object",
+			`"""
+Multi
+  line
+"""
+type A implements B & C @d {
+  "Field" f("Arg" x: Int = 1 @e, y: String): [A!]! @deprecated(reason: "no")
+  g(x: Int, y: [String] = ["a"]): A
+}`,
+			`"""
+Multi
+  line
+"""
+type A implements B & C @d {
+  "Field"
+  f(
+    "Arg"
+    x: Int = 1 @e
+    y: String
+  ): [A!]! @deprecated(reason: "no")
+  g(x: Int, y: [String] = ["a"]): A
+}`,
+		},
+		{
+			"Other types",
+			`scalar S @specifiedBy(url: "u") interface I implements J { a: Int } union U @u = A | B enum E { "V" V @v W } input In { a: Int = 1 b: In! }`,
+			`scalar S @specifiedBy(url: "u")
+
+interface I implements J {
+  a: Int
+}
+
+union U @u = A | B
+
+enum E {
+  "V"
+  V @v
+  W
+}
+
+input In {
+  a: Int = 1
+  b: In!
+}`,
+		},
+		{
+			"Extensions",
+			`extend scalar S @a extend type T implements I { a: Int } extend interface I @b extend union U = C extend enum E { X } extend input In { c: Int }`,
+			`extend scalar S @a
+
+extend type T implements I {
+  a: Int
+}
+
+extend interface I @b
+
+extend union U = C
+
+extend enum E {
+  X
+}
+
+extend input In {
+  c: Int
+}`,
+		},
+		{
+			"Directive definition",
+			`"Directive" directive @d(a: Int = 1) repeatable on FIELD | QUERY`,
+			"\"Directive\"\ndirective @d(a: Int = 1) repeatable on FIELD | QUERY",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.input)
+			got := printer.PrintDocument(doc)
+			if got != tt.expected {
+				t.Fatalf("expected\n%s\ngot\n%s", tt.expected, got)
+			}
+			if asthash.Document(asttest.Parse(t, got)) != asthash.Document(doc) {
+				t.Errorf("printed document does not parse into the same AST")
+			}
+		})
+	}
+}
+
+func TestPrintDocument_OperationDescriptions(t *testing.T) {
+	input := `"Op" query { a } query Q("Var" $a: Int, $b: Int) { a } """
+Fragment
+""" fragment F on T { a }`
+	expected := `"Op"
+query {
+  a
+}
+
+query Q(
+  "Var"
+  $a: Int
+  $b: Int
+) {
+  a
+}
+
+"""Fragment"""
+fragment F on T {
+  a
+}`
+	p, err := parser.New(lexer.New(input), parser.WithExperimentalOperationDescriptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := printer.PrintDocument(doc); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+	compact := `"Op"query{a}query Q("Var"$a:Int$b:Int){a}"Fragment"fragment F on T{a}`
+	if got := printer.PrintCompact(doc); got != compact {
+		t.Errorf("expected %s, got %s", compact, got)
+	}
+}
+
+func TestPrint_Nodes(t *testing.T) {
+	doc := asttest.Parse(t, `type A { f(x: Int = 1): [A!] }`)
+	field := doc.Definitions[0].(*ast.ObjectTypeDefinition).Fields[0]
+	tests := []struct {
+		node     ast.Node
+		expected string
+	}{
+		{field, "f(x: Int = 1): [A!]"},
+		{field.Arguments[0], "x: Int = 1"},
+		{field.Type, "[A!]"},
+		{field.Arguments[0].DefaultValue, "1"},
+		{field.Name, "f"},
+	}
+	for _, tt := range tests {
+		if got := printer.Print(tt.node); got != tt.expected {
+			t.Errorf("%T: expected %q, got %q", tt.node, tt.expected, got)
+		}
+	}
+}
+
+func TestPrint_IndentedDescriptions(t *testing.T) {
+	input := "type A {\n  \"\"\"\n  First\n\n    Second\n  \"\"\"\n  a: Int\n}"
+	doc := asttest.Parse(t, input)
+	got := printer.PrintDocument(doc)
+	if got != input {
+		t.Errorf("expected\n%s\ngot\n%s", input, got)
+	}
+	field := asttest.Parse(t, got).Definitions[0].(*ast.ObjectTypeDefinition).Fields[0]
+	if field.Description.Value != "First\n\n  Second" {
+		t.Errorf("unexpected description %q", field.Description.Value)
+	}
+}
+
+func TestPrintDocument_Style(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []printer.Option
+		expected string
+	}{
+		{
+			"Indent",
+			`{ a { b } }`,
+			[]printer.Option{printer.WithIndent("\t")},
+			"{\n\ta {\n\t\tb\n\t}\n}",
+		},
+		{
+			"Line width",
+			`{ field(first: 10, after: "abc") { a } }`,
+			[]printer.Option{printer.WithLineWidth(20)},
+			"{\n  field(\n    first: 10\n    after: \"abc\"\n  ) {\n    a\n  }\n}",
+		},
+		{
+			"Argument definitions kept inline",
+			`type Query { search(text: String, first: Int): [String] }`,
+			[]printer.Option{printer.WithLineWidth(20)},
+			"type Query {\n  search(text: String, first: Int): [String]\n}",
+		},
+		{
+			"Argument definitions wrapped",
+			`type Query { search(text: String, first: Int): [String] short(a: Int): Int } directive @d(a: Int) on FIELD`,
+			[]printer.Option{printer.WithLineWidth(20), printer.WithArgumentDefinitionWrapping()},
+			"type Query {\n  search(\n    text: String\n    first: Int\n  ): [String]\n  short(a: Int): Int\n}\n\ndirective @d(a: Int) on FIELD",
+		},
+		{
+			"Block descriptions",
+			`"Query" type Query { "a\nb" a: Int """c""" c: Int }`,
+			[]printer.Option{printer.WithDescriptionStyle(printer.DescriptionsBlock)},
+			"\"\"\"Query\"\"\"\ntype Query {\n  \"\"\"\n  a\n  b\n  \"\"\"\n  a: Int\n  \"\"\"c\"\"\"\n  c: Int\n}",
+		},
+		{
+			"Multiline block descriptions",
+			`"Query" type Query { "\n" a: Int }`,
+			[]printer.Option{printer.WithDescriptionStyle(printer.DescriptionsBlockMultiline)},
+			"\"\"\"\nQuery\n\"\"\"\ntype Query {\n  \"\\n\"\n  a: Int\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := printer.PrintDocument(asttest.Parse(t, tt.input), tt.opts...); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPrintDocument_Comments(t *testing.T) {
+	doc := asttest.Parse(t, `type Query { a: Int b: Int } { a }`)
+	typ := doc.Definitions[0].(*ast.ObjectTypeDefinition)
+	comments := map[ast.Node]*printer.Comments{
+		typ:           {Leading: []string{" Root"}},
+		typ.Fields[0]: {Trailing: " first", After: []string{" end"}},
+		doc.Definitions[1].(*ast.OperationDefinition).SelectionSet.Selections[0]: {Leading: []string{"x", "y"}},
+	}
+	got := printer.PrintDocument(doc, printer.WithComments(func(n ast.Node) *printer.Comments { return comments[n] }))
+	expected := "# Root\ntype Query {\n  a: Int # first\n  # end\n  b: Int\n}\n\n{\n  #x\n  #y\n  a\n}"
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestPrint_SourceMap(t *testing.T) {
+	input := `query Q{user(id:1){name}}`
+	doc := asttest.Parse(t, input)
+	var m printer.SourceMap
+	output := printer.New(printer.WithSourceMap(&m)).Document(doc)
+
+	start := strings.Index(output, "name")
+	node, ok := m.Node(start)
+	if !ok {
+		t.Fatalf("expected node")
+	}
+	if name, ok := node.(*ast.Name); !ok || name.Value != "name" {
+		t.Errorf("unexpected node %T", node)
+	}
+	if pos, ok := m.Input(start); !ok || pos != strings.Index(input, "name") {
+		t.Errorf("unexpected input offset %d", pos)
+	}
+	outStart, outEnd, ok := m.Output(strings.Index(input, "id:1"))
+	if !ok || output[outStart:outEnd] != "id" {
+		t.Errorf("unexpected output range %q", output[outStart:outEnd])
+	}
+}
@@ -0,0 +1,45 @@
+// Package printer serializes AST nodes back to GraphQL source.
+package printer
+
+import (
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// Printer serializes AST nodes. The zero value prints with default options.
+type Printer struct {
+	numbers NumberFormat
+	strings StringEncoding
+	escape  UnicodeEscape
+
+	indentUnit   string // Empty for two spaces.
+	lineWidth    int    // Zero for maxLineLength.
+	descriptions DescriptionStyle
+	wrapArgDefs  bool
+	comments     func(ast.Node) *Comments
+
+	sourceMap *SourceMap
+}
+
+// New returns printer configured with opts.
+func New(opts ...Option) *Printer {
+	p := &Printer{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Value returns source of value v.
+func (p *Printer) Value(v ast.Value) string {
+	p.reset()
+	var b strings.Builder
+	p.writeValue(&b, v)
+	return b.String()
+}
+
+// Value returns source of value v printed with opts.
+func Value(v ast.Value, opts ...Option) string {
+	return New(opts...).Value(v)
+}
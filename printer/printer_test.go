@@ -0,0 +1,168 @@
+package printer_test
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// parseValue parses value used as argument of a field.
+func parseValue(t *testing.T, input string) ast.Value {
+	t.Helper()
+	doc := asttest.Parse(t, "{ f(v: "+input+") }")
+	field := doc.Definitions[0].(*ast.OperationDefinition).SelectionSet.Selections[0].(*ast.Field)
+	return field.Arguments[0].Value
+}
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Variable", `$v`, `$v`},
+		{"Scalars", `[1, -2.5e3, true, null, RED]`, `[1, -2.5e3, true, null, RED]`},
+		{"Object", `{a: 1 b: {c: []}}`, `{a: 1, b: {c: []}}`},
+		{"String escapes", `"a\"b\\c\n\u0001\u0085é"`, `"a\"b\\c\n\u0001\u0085é"`},
+		{"Block string", `"""a "quoted" value"""`, `"""a "quoted" value"""`},
+		{"Block string with triple quotes", `"""a \""" b"""`, `"""a \""" b"""`},
+		{"Multiline block string", "\"\"\"\n  a\n    b\n\"\"\"", "\"\"\"\na\n  b\n\"\"\""},
+		{"Block string with leading spaces", `"""  a"""`, `"""  a"""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := printer.Value(parseValue(t, tt.input)); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValue_BlockStringTrailingQuote(t *testing.T) {
+	v := &ast.StringValue{Value: `say "hi"`, Block: true}
+	if got, expected := printer.Value(v), "\"\"\"\nsay \"hi\"\n\"\"\""; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestValue_BlockStringFallback(t *testing.T) {
+	for _, value := range []string{"\na", "a\n", "a\u0001", "  a\n  b", "a\r\nb"} {
+		v := &ast.StringValue{Value: value, Block: true}
+		got := printer.Value(v)
+		if got[:3] == `"""` {
+			t.Errorf("%q: expected regular string, got %s", value, got)
+		}
+		if s := parseValue(t, got).(*ast.StringValue).Value; s != value {
+			t.Errorf("%q: round trip gave %q", value, s)
+		}
+	}
+}
+
+func TestValue_NumberFormat(t *testing.T) {
+	tests := []struct {
+		input     string
+		lowercase string
+		canonical string
+	}{
+		{`1`, `1`, `1`},
+		{`-0`, `-0`, `0`},
+		{`1.50`, `1.50`, `1.5`},
+		{`1.000`, `1.000`, `1.0`},
+		{`1.5E+03`, `1.5e+03`, `1.5e3`},
+		{`2E-010`, `2e-010`, `2e-10`},
+		{`1.5E0`, `1.5e0`, `1.5`},
+		{`3e+00`, `3e+00`, `3.0`},
+		{`-0.0e-0`, `-0.0e-0`, `-0.0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v := parseValue(t, tt.input)
+			if got := printer.Value(v); got != tt.input {
+				t.Errorf("as written: expected %s, got %s", tt.input, got)
+			}
+			if got := printer.Value(v, printer.WithNumberFormat(printer.NumbersLowercaseExponent)); got != tt.lowercase {
+				t.Errorf("lowercase exponent: expected %s, got %s", tt.lowercase, got)
+			}
+			canonical := printer.Value(v, printer.WithNumberFormat(printer.NumbersCanonical))
+			if canonical != tt.canonical {
+				t.Errorf("canonical: expected %s, got %s", tt.canonical, canonical)
+			}
+			if _, isFloat := v.(*ast.FloatValue); isFloat {
+				if _, ok := parseValue(t, canonical).(*ast.FloatValue); !ok {
+					t.Errorf("canonical form %s is not a Float literal", canonical)
+				}
+			}
+		})
+	}
+}
+
+func TestValue_StringEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []printer.Option
+		expected string
+	}{
+		{"UTF-8", `"é😀"`, nil, `"é😀"`},
+		{"ASCII fixed width", `"é😀"`, []printer.Option{printer.WithStringEncoding(printer.StringsASCII)}, `"\u00E9\uD83D\uDE00"`},
+		{"ASCII variable width", `"é😀"`, []printer.Option{printer.WithStringEncoding(printer.StringsASCII), printer.WithUnicodeEscape(printer.EscapeVariableWidth)}, `"\u{E9}\u{1F600}"`},
+		{"Control variable width", `"\u0001"`, []printer.Option{printer.WithUnicodeEscape(printer.EscapeVariableWidth)}, `"\u{1}"`},
+		{"ASCII block string", `"""plain"""`, []printer.Option{printer.WithStringEncoding(printer.StringsASCII)}, `"""plain"""`},
+		{"Non-ASCII block string", `"""é"""`, []printer.Option{printer.WithStringEncoding(printer.StringsASCII)}, `"\u00E9"`},
+		{"UTF-8 block string", `"""é"""`, nil, `"""é"""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := parseValue(t, tt.input)
+			got := printer.Value(v, tt.opts...)
+			if got != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+			if s := parseValue(t, got).(*ast.StringValue).Value; s != v.(*ast.StringValue).Value {
+				t.Errorf("round trip gave %q", s)
+			}
+		})
+	}
+}
+
+func TestValue_SourceMap(t *testing.T) {
+	// Field "f(v: " takes 7 bytes of "{ f(v: ...) }".
+	input := `{a:1,   b: ["x"]}`
+	v := parseValue(t, input)
+	var m printer.SourceMap
+	p := printer.New(printer.WithSourceMap(&m))
+	output := p.Value(v)
+	if output != `{a: 1, b: ["x"]}` {
+		t.Fatalf("unexpected output %s", output)
+	}
+
+	if len(m) != 6 || m[0].Node != v || m[0].Start != 0 || m[0].End != len(output) {
+		t.Fatalf("unexpected source map %+v", m)
+	}
+	node, ok := m.Node(12) // "x" in output.
+	if !ok {
+		t.Fatalf("expected node")
+	}
+	if s, ok := node.(*ast.StringValue); !ok || s.Value != "x" {
+		t.Errorf("unexpected node %T", node)
+	}
+	if pos, ok := m.Input(12); !ok || pos != 7+13 {
+		t.Errorf("expected input offset %d, got %d", 7+13, pos)
+	}
+	if start, end, ok := m.Output(7 + 3); !ok || output[start:end] != "1" {
+		t.Errorf("unexpected output range %d-%d", start, end)
+	}
+	if start, end, ok := m.Output(7 + 8); !ok || output[start:end] != `b: ["x"]` {
+		t.Errorf("unexpected output range %d-%d", start, end)
+	}
+	if _, _, ok := m.Output(0); ok {
+		t.Errorf("expected no output range outside of value")
+	}
+
+	p.Value(&ast.NullValue{})
+	if len(m) != 1 {
+		t.Errorf("expected source map to be replaced, got %+v", m)
+	}
+}
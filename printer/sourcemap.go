@@ -0,0 +1,92 @@
+package printer
+
+import (
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// Mapping relates range [Start, End) of printed output to node it was
+// printed from. Input range of the node is [Node.Pos(), Node.End()).
+type Mapping struct {
+	Start int
+	End   int
+	Node  ast.Node
+}
+
+// SourceMap lists mappings of printed nodes ordered by Start, enclosing
+// nodes before nodes nested in them.
+type SourceMap []Mapping
+
+// Node returns the innermost node printed at output offset.
+func (m SourceMap) Node(offset int) (ast.Node, bool) {
+	for i := len(m) - 1; i >= 0; i-- {
+		if m[i].Start <= offset && offset < m[i].End {
+			return m[i].Node, true
+		}
+	}
+	return nil, false
+}
+
+// Input translates output offset into input offset: offset within the
+// innermost printed node is kept when it fits into the node source,
+// otherwise start of the node is returned.
+func (m SourceMap) Input(offset int) (int, bool) {
+	for i := len(m) - 1; i >= 0; i-- {
+		mp := m[i]
+		if mp.Start <= offset && offset < mp.End {
+			if pos := mp.Node.Pos() + offset - mp.Start; pos < mp.Node.End() {
+				return pos, true
+			}
+			return mp.Node.Pos(), true
+		}
+	}
+	return 0, false
+}
+
+// Output translates input offset into output range of the innermost node
+// whose source contains it.
+func (m SourceMap) Output(offset int) (start, end int, ok bool) {
+	found := -1
+	for i, mp := range m {
+		if mp.Node.Pos() <= offset && offset < mp.Node.End() {
+			found = i
+		}
+	}
+	if found < 0 {
+		return 0, 0, false
+	}
+	return m[found].Start, m[found].End, true
+}
+
+// WithSourceMap makes printer store source map of every printed output
+// into m, replacing the previous one.
+func WithSourceMap(m *SourceMap) Option {
+	return func(p *Printer) {
+		p.sourceMap = m
+	}
+}
+
+// begin starts mapping of node printed at the end of b and returns its
+// index for end, or -1 when source map is not requested.
+func (p *Printer) begin(b *strings.Builder, node ast.Node) int {
+	if p.sourceMap == nil {
+		return -1
+	}
+	*p.sourceMap = append(*p.sourceMap, Mapping{Start: b.Len(), Node: node})
+	return len(*p.sourceMap) - 1
+}
+
+// end completes mapping i started with begin.
+func (p *Printer) end(b *strings.Builder, i int) {
+	if i >= 0 {
+		(*p.sourceMap)[i].End = b.Len()
+	}
+}
+
+// reset clears source map before printing new output.
+func (p *Printer) reset() {
+	if p.sourceMap != nil {
+		*p.sourceMap = nil
+	}
+}
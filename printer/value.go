@@ -0,0 +1,254 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+func (p *Printer) writeValue(b *strings.Builder, v ast.Value) {
+	defer p.end(b, p.begin(b, v))
+	switch v := v.(type) {
+	case *ast.Variable:
+		b.WriteString("$" + v.Name.Value)
+	case *ast.IntValue:
+		b.WriteString(p.formatInt(v.Value))
+	case *ast.FloatValue:
+		b.WriteString(p.formatFloat(v.Value))
+	case *ast.StringValue:
+		p.writeString(b, v.Value, v.Block)
+	case *ast.BooleanValue:
+		if v.Value {
+			b.WriteString("true")
+		} else {
+			b.WriteString("false")
+		}
+	case *ast.NullValue:
+		b.WriteString("null")
+	case *ast.EnumValue:
+		b.WriteString(v.Value)
+	case *ast.ListValue:
+		b.WriteByte('[')
+		for i, item := range v.Values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			p.writeValue(b, item)
+		}
+		b.WriteByte(']')
+	case *ast.ObjectValue:
+		b.WriteByte('{')
+		for i, f := range v.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			i := p.begin(b, f)
+			b.WriteString(f.Name.Value + ": ")
+			p.writeValue(b, f.Value)
+			p.end(b, i)
+		}
+		b.WriteByte('}')
+	}
+}
+
+func (p *Printer) formatInt(s string) string {
+	if p.numbers == NumbersCanonical && s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func (p *Printer) formatFloat(s string) string {
+	switch p.numbers {
+	case NumbersLowercaseExponent:
+		return strings.ReplaceAll(s, "E", "e")
+	case NumbersCanonical:
+		return canonicalFloat(s)
+	}
+	return s
+}
+
+// canonicalFloat normalizes Float literal s, see NumbersCanonical.
+func canonicalFloat(s string) string {
+	mantissa, exponent, hasExponent := strings.Cut(strings.ToLower(s), "e")
+	intPart, frac, hasFrac := strings.Cut(mantissa, ".")
+	if hasFrac {
+		frac = strings.TrimRight(frac, "0")
+		if frac == "" {
+			frac = "0"
+		}
+	}
+
+	if hasExponent {
+		sign := ""
+		switch {
+		case strings.HasPrefix(exponent, "+"):
+			exponent = exponent[1:]
+		case strings.HasPrefix(exponent, "-"):
+			sign, exponent = "-", exponent[1:]
+		}
+		exponent = strings.TrimLeft(exponent, "0")
+		if exponent == "" {
+			hasExponent = false
+		} else {
+			exponent = sign + exponent
+		}
+	}
+
+	result := intPart
+	if hasFrac {
+		result += "." + frac
+	}
+	if hasExponent {
+		return result + "e" + exponent
+	}
+	if !hasFrac {
+		// Dropped exponent must not turn the literal into an Int.
+		result += ".0"
+	}
+	return result
+}
+
+// writeString writes s as a block string when block is set and s can be
+// represented by one, otherwise as a quoted string literal.
+func (p *Printer) writeString(b *strings.Builder, s string, block bool) {
+	if block && p.isBlockString(s) {
+		writeBlockString(b, s, false)
+		return
+	}
+	b.WriteByte('"')
+	for _, ch := range s {
+		switch {
+		case ch == '"':
+			b.WriteString(`\"`)
+		case ch == '\\':
+			b.WriteString(`\\`)
+		case ch == '\b':
+			b.WriteString(`\b`)
+		case ch == '\f':
+			b.WriteString(`\f`)
+		case ch == '\n':
+			b.WriteString(`\n`)
+		case ch == '\r':
+			b.WriteString(`\r`)
+		case ch == '\t':
+			b.WriteString(`\t`)
+		case ch < 0x20 || (0x7F <= ch && ch <= 0x9F) || (ch >= utf8.RuneSelf && p.strings == StringsASCII):
+			p.writeEscape(b, ch)
+		default:
+			b.WriteRune(ch)
+		}
+	}
+	b.WriteByte('"')
+}
+
+// writeDescription writes description d in description style of p.
+func (p *Printer) writeDescription(b *strings.Builder, d *ast.Description) {
+	switch {
+	case p.descriptions == DescriptionsBlockMultiline && p.isBlockString(d.Value):
+		writeBlockString(b, d.Value, true)
+	default:
+		p.writeString(b, d.Value, d.Block || p.descriptions != DescriptionsAsWritten)
+	}
+}
+
+// isBlockString reports whether s can be printed as block string.
+func (p *Printer) isBlockString(s string) bool {
+	return isPrintableAsBlockString(s) && (p.strings == StringsUTF8 || isASCII(s))
+}
+
+func (p *Printer) writeEscape(b *strings.Builder, ch rune) {
+	switch {
+	case p.escape == EscapeVariableWidth:
+		fmt.Fprintf(b, `\u{%X}`, ch)
+	case ch > 0xFFFF:
+		r1, r2 := utf16.EncodeRune(ch)
+		fmt.Fprintf(b, `\u%04X\u%04X`, r1, r2)
+	default:
+		fmt.Fprintf(b, `\u%04X`, ch)
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// writeBlockString writes s as a block string literal which yields s
+// after the block string value algorithm is applied. With multiline,
+// quotes are put on lines of their own whenever it keeps the value.
+func writeBlockString(b *strings.Builder, s string, multiline bool) {
+	escaped := strings.ReplaceAll(s, `"""`, `\"""`)
+	lines := strings.Split(s, "\n")
+	singleLine := len(lines) == 1
+
+	forceLeadingNewLine := len(lines) > 1
+	for _, line := range lines[1:] {
+		if line != "" && !isWhiteSpace(line[0]) {
+			forceLeadingNewLine = false
+			break
+		}
+	}
+	hasTrailingTripleQuotes := strings.HasSuffix(escaped, `\"""`)
+	hasTrailingQuote := strings.HasSuffix(s, `"`) && !hasTrailingTripleQuotes
+	hasTrailingSlash := strings.HasSuffix(s, `\`)
+	forceTrailingNewLine := hasTrailingQuote || hasTrailingSlash
+	multipleLines := multiline || !singleLine || len(s) > 70 || forceTrailingNewLine || forceLeadingNewLine || hasTrailingTripleQuotes
+	skipLeadingNewLine := singleLine && s != "" && isWhiteSpace(s[0])
+
+	b.WriteString(`"""`)
+	if (multipleLines && !skipLeadingNewLine) || forceLeadingNewLine {
+		b.WriteByte('\n')
+	}
+	b.WriteString(escaped)
+	if multipleLines || forceTrailingNewLine {
+		b.WriteByte('\n')
+	}
+	b.WriteString(`"""`)
+}
+
+// isPrintableAsBlockString reports whether s survives a round trip
+// through block string: it must not contain characters block strings
+// cannot represent, leading or trailing blank lines, or common indent.
+func isPrintableAsBlockString(s string) bool {
+	if s == "" {
+		return true
+	}
+	emptyLine, indent, commonIndent, seenNonEmptyLine := true, false, true, false
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '\n':
+			if emptyLine && !seenNonEmptyLine {
+				return false // Leading blank line.
+			}
+			seenNonEmptyLine = true
+			emptyLine = true
+			indent = false
+		case isWhiteSpace(ch):
+			indent = indent || emptyLine
+		case ch < 0x20:
+			return false
+		default:
+			commonIndent = commonIndent && indent
+			emptyLine = false
+		}
+	}
+	if emptyLine {
+		return false // Trailing blank line.
+	}
+	if commonIndent && seenNonEmptyLine {
+		return false
+	}
+	return true
+}
+
+func isWhiteSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t'
+}
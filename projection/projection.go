@@ -0,0 +1,288 @@
+// Package projection converts selections into a neutral "projection and
+// filter" representation that resolvers backed by databases translate to
+// SQL, Cypher or other query languages, fetching only selected data.
+package projection
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Node is a projection of a selected field.
+type Node struct {
+	Field string // Field name.
+	Key   string // Response key, alias or field name.
+	Type  string // Named type of the field.
+	List  bool   // Whether field type is a list at any level.
+
+	// On is type condition the field was selected under when it differs
+	// from the parent type, e.g. "Book" for `... on Book { isbn }`.
+	On string
+
+	// Filter holds argument values with variables substituted and
+	// argument defaults applied. Numbers are json.Number and input objects
+	// map[string]any.
+	Filter map[string]any
+
+	// Fields are selected sub-fields of composite types in selection
+	// order. Meta fields like __typename are not included.
+	Fields []*Node
+}
+
+func (n *Node) setFilter(name string, value any) {
+	if n.Filter == nil {
+		n.Filter = make(map[string]any)
+	}
+	n.Filter[name] = value
+}
+
+// Leaf reports whether n selects a scalar or enum value.
+func (n *Node) Leaf() bool {
+	return len(n.Fields) == 0
+}
+
+// Columns returns distinct names of leaf sub-fields in selection order.
+func (n *Node) Columns() []string {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, f := range n.Fields {
+		if f.Leaf() && !seen[f.Field] {
+			seen[f.Field] = true
+			columns = append(columns, f.Field)
+		}
+	}
+	return columns
+}
+
+// Relations returns composite sub-fields, i.e. joins or traversals.
+func (n *Node) Relations() []*Node {
+	var relations []*Node
+	for _, f := range n.Fields {
+		if !f.Leaf() {
+			relations = append(relations, f)
+		}
+	}
+	return relations
+}
+
+// Builder builds projections of selections of operations in Document.
+type Builder struct {
+	Schema    *schema.Schema
+	Document  *ast.Document  // Used to look up fragments.
+	Variables map[string]any // Coerced values of operation variables.
+}
+
+// Build returns projection of field selected on type parentType. Fields
+// skipped by @skip or @include are left out.
+func (b *Builder) Build(parentType string, field *ast.Field) (*Node, error) {
+	return b.build(parentType, "", field, map[string]bool{})
+}
+
+func (b *Builder) build(parentType, on string, field *ast.Field, visiting map[string]bool) (*Node, error) {
+	t := b.Schema.Type(parentType)
+	if t == nil {
+		return nil, fmt.Errorf("unknown type %q", parentType)
+	}
+	def := t.Field(field.Name.Value)
+	if def == nil {
+		return nil, fmt.Errorf("field %q is not defined on type %q", field.Name.Value, parentType)
+	}
+
+	n := &Node{Field: field.Name.Value, Key: field.Name.Value, On: on}
+	if field.Alias != nil {
+		n.Key = field.Alias.Value
+	}
+	n.Type, n.List = unwrap(def.Type)
+
+	for _, arg := range def.Arguments {
+		if arg.DefaultValue != nil {
+			v, err := b.value(arg.DefaultValue)
+			if err != nil {
+				return nil, err
+			}
+			n.setFilter(arg.Name, v)
+		}
+	}
+	for _, arg := range field.Arguments {
+		v, err := b.value(arg.Value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q of field %q: %w", arg.Name.Value, n.Key, err)
+		}
+		n.setFilter(arg.Name.Value, v)
+	}
+
+	if field.SelectionSet != nil {
+		if err := b.selections(n, n.Type, n.Type, field.SelectionSet, visiting); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// selections adds projections of set selected on typeName to n, where
+// fieldType is the type of n itself.
+func (b *Builder) selections(n *Node, fieldType, typeName string, set *ast.SelectionSet, visiting map[string]bool) error {
+	for _, sel := range set.Selections {
+		var directives []*ast.Directive
+		switch s := sel.(type) {
+		case *ast.Field:
+			directives = s.Directives
+		case *ast.InlineFragment:
+			directives = s.Directives
+		case *ast.FragmentSpread:
+			directives = s.Directives
+		}
+		include, err := b.included(directives)
+		if err != nil {
+			return err
+		}
+		if !include {
+			continue
+		}
+
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			on := ""
+			if typeName != fieldType {
+				on = typeName
+			}
+			child, err := b.build(typeName, on, s, visiting)
+			if err != nil {
+				return err
+			}
+			n.Fields = append(n.Fields, child)
+		case *ast.InlineFragment:
+			condition := typeName
+			if s.TypeCondition != nil {
+				condition = s.TypeCondition.Name.Value
+			}
+			if err := b.selections(n, fieldType, condition, s.SelectionSet, visiting); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			frag := b.fragment(name)
+			if frag == nil {
+				return fmt.Errorf("unknown fragment %q", name)
+			}
+			if visiting[name] {
+				return fmt.Errorf("fragment %q spreads itself", name)
+			}
+			visiting[name] = true
+			err := b.selections(n, fieldType, frag.TypeCondition.Name.Value, frag.SelectionSet, visiting)
+			delete(visiting, name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) fragment(name string) *ast.FragmentDefinition {
+	if b.Document == nil {
+		return nil
+	}
+	for _, def := range b.Document.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name.Value == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// included evaluates @skip and @include.
+func (b *Builder) included(directives []*ast.Directive) (bool, error) {
+	for _, d := range directives {
+		if d.Name.Value != "skip" && d.Name.Value != "include" {
+			continue
+		}
+		for _, arg := range d.Arguments {
+			if arg.Name.Value != "if" {
+				continue
+			}
+			v, err := b.value(arg.Value)
+			if err != nil {
+				return false, err
+			}
+			cond, ok := v.(bool)
+			if !ok {
+				return false, fmt.Errorf("@%s(if:) must be a Boolean", d.Name.Value)
+			}
+			if cond == (d.Name.Value == "skip") {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// value returns Go value of v substituting variables.
+func (b *Builder) value(v ast.Value) (any, error) {
+	switch v := v.(type) {
+	case *ast.Variable:
+		value, ok := b.Variables[v.Name.Value]
+		if !ok {
+			return nil, nil
+		}
+		return value, nil
+	case *ast.NullValue:
+		return nil, nil
+	case *ast.IntValue:
+		return json.Number(v.Value), nil
+	case *ast.FloatValue:
+		return json.Number(v.Value), nil
+	case *ast.StringValue:
+		return v.Value, nil
+	case *ast.BooleanValue:
+		return v.Value, nil
+	case *ast.EnumValue:
+		return v.Value, nil
+	case *ast.ListValue:
+		list := make([]any, len(v.Values))
+		for i, item := range v.Values {
+			value, err := b.value(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case *ast.ObjectValue:
+		obj := make(map[string]any, len(v.Fields))
+		for _, f := range v.Fields {
+			value, err := b.value(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			obj[f.Name.Value] = value
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unsupported value at %d", v.Pos())
+}
+
+// unwrap returns named type of t and whether t is a list at any level.
+func unwrap(t ast.Type) (string, bool) {
+	list := false
+	for {
+		switch tt := t.(type) {
+		case *ast.NamedType:
+			return tt.Name.Value, list
+		case *ast.ListType:
+			list = true
+			t = tt.Type
+		case *ast.NonNullType:
+			t = tt.Type
+		default:
+			return "", list
+		}
+	}
+}
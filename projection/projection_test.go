@@ -0,0 +1,98 @@
+package projection
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+const testSchema = `
+type Query { authors(where: AuthorFilter, limit: Int = 10): [Author!]! search(term: String!): [Result] }
+type Author { id: ID! name: String books(first: Int): [Book] }
+type Book { id: ID! title: String isbn: String }
+type Magazine { id: ID! issue: Int }
+union Result = Book | Magazine
+input AuthorFilter { name: String }
+`
+
+func build(t *testing.T, query string, variables map[string]any) (*Node, error) {
+	t.Helper()
+	s := asttest.Schema(t, testSchema)
+	doc := asttest.Parse(t, query)
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+	b := &Builder{Schema: s, Document: doc, Variables: variables}
+	return b.Build("Query", op.SelectionSet.Selections[0].(*ast.Field))
+}
+
+func TestBuilder_Build(t *testing.T) {
+	n, err := build(t, `query ($name: String, $withBooks: Boolean!) {
+  list: authors(where: { name: $name }) {
+    __typename
+    id
+    name
+    alias: name
+    books(first: 2) @include(if: $withBooks) { title }
+    ...Extra
+  }
+}
+fragment Extra on Author { books(first: 5) @skip(if: false) { ... on Book { isbn } } }`, map[string]any{"name": "Ann", "withBooks": false})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n.Field != "authors" || n.Key != "list" || n.Type != "Author" || !n.List {
+		t.Errorf("unexpected node: %+v", n)
+	}
+	expectedFilter := map[string]any{"where": map[string]any{"name": "Ann"}, "limit": json.Number("10")}
+	if !reflect.DeepEqual(n.Filter, expectedFilter) {
+		t.Errorf("expected filter %v, got %v", expectedFilter, n.Filter)
+	}
+	if got := n.Columns(); !reflect.DeepEqual(got, []string{"id", "name"}) {
+		t.Errorf("unexpected columns: %v", got)
+	}
+	relations := n.Relations()
+	if len(relations) != 1 {
+		t.Fatalf("expected 1 relation, got %d", len(relations))
+	}
+	books := relations[0]
+	if books.Type != "Book" || !reflect.DeepEqual(books.Filter, map[string]any{"first": json.Number("5")}) ||
+		!reflect.DeepEqual(books.Columns(), []string{"isbn"}) || books.Fields[0].On != "" {
+		t.Errorf("unexpected books relation: %+v", books)
+	}
+}
+
+func TestBuilder_BuildAbstract(t *testing.T) {
+	n, err := build(t, `{ search(term: "go") { ... on Book { title } ... on Magazine { issue } } }`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got [][2]string
+	for _, f := range n.Fields {
+		got = append(got, [2]string{f.On, f.Field})
+	}
+	if !reflect.DeepEqual(got, [][2]string{{"Book", "title"}, {"Magazine", "issue"}}) {
+		t.Errorf("unexpected fields: %v", got)
+	}
+}
+
+func TestBuilder_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown field", `{ authors { age } }`},
+		{"unknown fragment", `{ authors { ...Missing } }`},
+		{"fragment cycle", `{ authors { ...A } } fragment A on Author { ...A }`},
+		{"non-boolean condition", `{ authors { id @skip(if: 1) } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := build(t, tt.query, nil); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
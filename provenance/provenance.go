@@ -0,0 +1,225 @@
+// Package provenance stamps schemas with metadata describing how they
+// were built, so gateways can report exactly which schema they serve.
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExtensionKey is key of response extensions provenance is exposed under.
+const ExtensionKey = "schemaProvenance"
+
+// headerTitle opens provenance SDL header comment.
+const headerTitle = "# Schema provenance"
+
+// Provenance describes origin of a built schema.
+type Provenance struct {
+	Version         string            // Schema version, e.g. a release tag or content hash.
+	GitSHA          string            // Commit schema sources were taken from.
+	BuildTime       time.Time         // When schema was built.
+	ComposerVersion string            // Version of tool that composed schema.
+	Extra           map[string]string // Additional metadata, see fieldKeys.
+}
+
+// fieldKeys are keys of fields of Provenance in SDL header and JSON. Extra
+// entries with these keys are ignored, so they cannot override fields.
+var fieldKeys = map[string]bool{"version": true, "gitSha": true, "buildTime": true, "composerVersion": true}
+
+// MarshalJSON encodes p as object with keys used in SDL header.
+func (p Provenance) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range p.fields() {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(f[0])
+		value, _ := json.Marshal(f[1])
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// IsZero reports whether p holds no metadata.
+func (p Provenance) IsZero() bool {
+	return p.Version == "" && p.GitSHA == "" && p.BuildTime.IsZero() && p.ComposerVersion == "" && len(p.Extra) == 0
+}
+
+// fields returns metadata as ordered key-value pairs.
+func (p Provenance) fields() [][2]string {
+	var fields [][2]string
+	add := func(k, v string) {
+		if v != "" {
+			fields = append(fields, [2]string{k, v})
+		}
+	}
+	add("version", p.Version)
+	add("gitSha", p.GitSHA)
+	if !p.BuildTime.IsZero() {
+		add("buildTime", p.BuildTime.UTC().Format(time.RFC3339))
+	}
+	add("composerVersion", p.ComposerVersion)
+	for _, k := range slices.Sorted(maps.Keys(p.Extra)) {
+		if !fieldKeys[k] {
+			add(k, p.Extra[k])
+		}
+	}
+	return fields
+}
+
+// Header returns SDL comment describing p, e.g.
+//
+//	# Schema provenance
+//	# gitSha: 3f2a9c1
+//	# buildTime: 2024-05-01T12:00:00Z
+//
+// It is empty when p is zero. Backslashes, line breaks and colons of keys
+// are escaped by a backslash, so that FromSDL reads every key and value
+// back.
+func (p Provenance) Header() string {
+	if p.IsZero() {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(headerTitle)
+	b.WriteByte('\n')
+	for _, f := range p.fields() {
+		b.WriteString("# ")
+		b.WriteString(keyEscaper.Replace(f[0]))
+		b.WriteString(": ")
+		b.WriteString(valueEscaper.Replace(f[1]))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Stamp returns sdl prefixed by header of p. Existing provenance header
+// is replaced.
+func (p Provenance) Stamp(sdl string) string {
+	_, rest, _, _ := cutHeader(sdl)
+	if p.IsZero() {
+		return rest
+	}
+	return p.Header() + "\n" + rest
+}
+
+// Expose returns response extensions with p added under ExtensionKey,
+// e.g. of introspection responses:
+//
+//	resp.Extensions = p.Expose(resp.Extensions)
+//
+// Extensions are returned unchanged when p is zero.
+func (p Provenance) Expose(extensions map[string]any) map[string]any {
+	if p.IsZero() {
+		return extensions
+	}
+	if extensions == nil {
+		extensions = make(map[string]any)
+	}
+	extensions[ExtensionKey] = p
+	return extensions
+}
+
+// FromSDL reads provenance header written by Stamp. It reports false when
+// sdl has no header.
+func FromSDL(sdl string) (Provenance, bool, error) {
+	header, _, ok, err := cutHeader(sdl)
+	return header, ok, err
+}
+
+// cutHeader splits sdl into provenance header and the rest.
+func cutHeader(sdl string) (Provenance, string, bool, error) {
+	if !strings.HasPrefix(sdl, headerTitle+"\n") {
+		return Provenance{}, sdl, false, nil
+	}
+	var p Provenance
+	rest := sdl[len(headerTitle)+1:]
+	scanner := bufio.NewScanner(strings.NewReader(rest))
+	consumed := len(headerTitle) + 1
+	for scanner.Scan() {
+		line := scanner.Text()
+		kv, ok := strings.CutPrefix(line, "# ")
+		if !ok {
+			break
+		}
+		consumed += len(line) + 1
+		key, value, ok := cutField(kv)
+		if !ok {
+			return Provenance{}, sdl, false, fmt.Errorf("invalid provenance line %q", line)
+		}
+		switch key {
+		case "version":
+			p.Version = value
+		case "gitSha":
+			p.GitSHA = value
+		case "buildTime":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return Provenance{}, sdl, false, fmt.Errorf("invalid provenance build time: %w", err)
+			}
+			p.BuildTime = t
+		case "composerVersion":
+			p.ComposerVersion = value
+		default:
+			if p.Extra == nil {
+				p.Extra = make(map[string]string)
+			}
+			p.Extra[key] = value
+		}
+	}
+	rest = strings.TrimPrefix(sdl[min(consumed, len(sdl)):], "\n")
+	return p, rest, true, nil
+}
+
+var (
+	keyEscaper   = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, ":", `\:`)
+	valueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`)
+)
+
+// cutField splits header line kv at the first unescaped ": " into
+// unescaped key and value.
+func cutField(kv string) (key, value string, ok bool) {
+	for i := 0; i < len(kv); i++ {
+		switch {
+		case kv[i] == '\\':
+			i++
+		case strings.HasPrefix(kv[i:], ": "):
+			return unescape(kv[:i]), unescape(kv[i+2:]), true
+		}
+	}
+	return "", "", false
+}
+
+// unescape reverses escaping of keys and values by Header.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
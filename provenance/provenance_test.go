@@ -0,0 +1,104 @@
+package provenance
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var stamp = Provenance{
+	Version:         "v42",
+	GitSHA:          "3f2a9c1",
+	BuildTime:       time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	ComposerVersion: "1.2.3",
+	Extra:           map[string]string{"team": "core", "env": "prod"},
+}
+
+func TestProvenance_Stamp(t *testing.T) {
+	sdl := stamp.Stamp("type Query { a: Int }\n")
+	expected := `# Schema provenance
+# version: v42
+# gitSha: 3f2a9c1
+# buildTime: 2024-05-01T12:00:00Z
+# composerVersion: 1.2.3
+# env: prod
+# team: core
+
+type Query { a: Int }
+`
+	if sdl != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, sdl)
+	}
+
+	got, ok, err := FromSDL(sdl)
+	if err != nil || !ok {
+		t.Fatalf("expected header, got %v %v", ok, err)
+	}
+	if !reflect.DeepEqual(got, stamp) {
+		t.Errorf("expected %+v, got %+v", stamp, got)
+	}
+
+	restamped := Provenance{GitSHA: "abc"}.Stamp(sdl)
+	if restamped != "# Schema provenance\n# gitSha: abc\n\ntype Query { a: Int }\n" {
+		t.Errorf("unexpected restamped SDL:\n%s", restamped)
+	}
+	if unstamped := (Provenance{}).Stamp(sdl); unstamped != "type Query { a: Int }\n" {
+		t.Errorf("unexpected unstamped SDL:\n%s", unstamped)
+	}
+}
+
+func TestProvenance_Header(t *testing.T) {
+	p := Provenance{
+		Version: "v1",
+		Extra: map[string]string{
+			"version":   "v2",
+			"notes":     "first\nsecond: third",
+			"key: with": `back\slash`,
+		},
+	}
+	expected := `# Schema provenance
+# version: v1
+# key\: with: back\\slash
+# notes: first\nsecond: third
+`
+	if got := p.Header(); got != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, got)
+	}
+
+	got, _, err := FromSDL(p.Stamp("type Query { a: Int }"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delete(p.Extra, "version")
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("expected %+v, got %+v", p, got)
+	}
+}
+
+func TestFromSDL(t *testing.T) {
+	if _, ok, err := FromSDL("type Query { a: Int }"); ok || err != nil {
+		t.Errorf("expected no header, got %v %v", ok, err)
+	}
+	if _, _, err := FromSDL("# Schema provenance\n# buildTime: yesterday\n"); err == nil {
+		t.Errorf("expected error for invalid build time")
+	}
+	if _, _, err := FromSDL("# Schema provenance\n# gitSha\n"); err == nil {
+		t.Errorf("expected error for invalid line")
+	}
+}
+
+func TestProvenance_Expose(t *testing.T) {
+	extensions := stamp.Expose(map[string]any{"cost": 1})
+	out, err := json.Marshal(extensions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"cost":1,"schemaProvenance":{"version":"v42","gitSha":"3f2a9c1","buildTime":"2024-05-01T12:00:00Z","composerVersion":"1.2.3","env":"prod","team":"core"}}`
+	if string(out) != expected {
+		t.Errorf("expected %s, got %s", expected, out)
+	}
+	if got := (Provenance{}).Expose(nil); got != nil {
+		t.Errorf("expected nil extensions, got %v", got)
+	}
+}
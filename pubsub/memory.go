@@ -0,0 +1,136 @@
+// Package pubsub implements subscription.Bus in memory, for single
+// instance deployments and tests, and with Redis, fanning events out to
+// subscribers of every instance:
+//
+//	bus := pubsub.NewRedis(pubsub.RedisOptions{Addr: "localhost:6379", Prefix: "gql:"})
+//	defer bus.Close()
+//	results, err := subscription.Stream(ctx, bus, "messages", opts)
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gqlhub/gqlhub-core/subscription"
+)
+
+// ErrClosed is returned by buses after Close.
+var ErrClosed = errors.New("pubsub: bus closed")
+
+// defaultBuffer is number of events buffered for a subscriber by default.
+const defaultBuffer = 64
+
+// Memory is an in-process bus. Payloads are delivered as published,
+// shared by subscribers, which must not modify them.
+type Memory struct {
+	buffer  int
+	dropped atomic.Uint64
+
+	mu     sync.RWMutex
+	topics map[string]map[chan subscription.Event]struct{}
+	closed bool
+	done   chan struct{} // Closed by Close.
+}
+
+var _ subscription.Bus = (*Memory)(nil)
+
+// NewMemory returns bus buffering buffer events for every subscriber, 64
+// when zero or less.
+func NewMemory(buffer int) *Memory {
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+	return &Memory{
+		buffer: buffer,
+		topics: make(map[string]map[chan subscription.Event]struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Publish delivers payload to subscribers of topic. Events are dropped
+// for subscribers whose buffers are full, so slow subscribers do not
+// block publishers; see Dropped.
+func (m *Memory) Publish(ctx context.Context, topic string, payload any) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrClosed
+	}
+	e := subscription.Event{Topic: topic, Payload: payload}
+	for ch := range m.topics[topic] {
+		select {
+		case ch <- e:
+		default:
+			m.dropped.Add(1)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns channel of events published to topic, closed when
+// ctx is done or bus is closed.
+func (m *Memory) Subscribe(ctx context.Context, topic string) (<-chan subscription.Event, error) {
+	ch := make(chan subscription.Event, m.buffer)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	if m.topics[topic] == nil {
+		m.topics[topic] = make(map[chan subscription.Event]struct{})
+	}
+	m.topics[topic][ch] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-m.done:
+			return
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := m.topics[topic][ch]; !ok {
+			return // Closed by Close meanwhile.
+		}
+		delete(m.topics[topic], ch)
+		if len(m.topics[topic]) == 0 {
+			delete(m.topics, topic)
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// Subscribers returns number of subscribers of topic.
+func (m *Memory) Subscribers(topic string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.topics[topic])
+}
+
+// Dropped returns number of events dropped for subscribers with full
+// buffers.
+func (m *Memory) Dropped() uint64 {
+	return m.dropped.Load()
+}
+
+// Close closes channels of all subscribers. Publish and Subscribe fail
+// with ErrClosed afterwards.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	for _, subscribers := range m.topics {
+		for ch := range subscribers {
+			close(ch)
+		}
+	}
+	m.topics = nil
+	return nil
+}
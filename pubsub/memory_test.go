@@ -0,0 +1,123 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/subscription"
+)
+
+func receive(t *testing.T, events <-chan subscription.Event) subscription.Event {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("expected event, got closed channel")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected event, got none")
+	}
+	return subscription.Event{}
+}
+
+func closed(t *testing.T, events <-chan subscription.Event) {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if ok {
+			t.Fatalf("expected closed channel, got %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected closed channel, got none")
+	}
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(1)
+	defer m.Close()
+
+	a, err := m.Subscribe(ctx, "messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subCtx, cancel := context.WithCancel(ctx)
+	b, err := m.Subscribe(subCtx, "messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := m.Subscribe(ctx, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Subscribers("messages"); got != 2 {
+		t.Errorf("expected 2 subscribers, got %d", got)
+	}
+
+	if err := m.Publish(ctx, "messages", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	for _, events := range []<-chan subscription.Event{a, b} {
+		if e := receive(t, events); e.Topic != "messages" || e.Payload != "hello" {
+			t.Errorf("expected hello on messages, got %v on %s", e.Payload, e.Topic)
+		}
+	}
+	select {
+	case e := <-other:
+		t.Errorf("expected no event on other, got %v", e)
+	default:
+	}
+
+	m.Publish(ctx, "messages", 1)
+	m.Publish(ctx, "messages", 2)
+	if got := m.Dropped(); got != 2 {
+		t.Errorf("expected 2 dropped events, got %d", got)
+	}
+	if e := receive(t, a); e.Payload != 1 {
+		t.Errorf("expected 1, got %v", e.Payload)
+	}
+
+	cancel()
+	receive(t, b)
+	closed(t, b)
+	if got := m.Subscribers("messages"); got != 1 {
+		t.Errorf("expected 1 subscriber, got %d", got)
+	}
+
+	m.Close()
+	closed(t, a)
+	closed(t, other)
+	if err := m.Publish(ctx, "messages", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if _, err := m.Subscribe(ctx, "messages"); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestMemory_Stream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemory(0)
+	defer m.Close()
+
+	results, err := subscription.Stream(ctx, m, "messages", subscription.Options{
+		Filter: subscription.ArgumentFilter(map[string]any{"room": "7"}, nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Publish(ctx, "messages", map[string]any{"room": 8, "text": "skipped"})
+	m.Publish(ctx, "messages", map[string]any{"room": 7, "text": "hello"})
+	select {
+	case r := <-results:
+		if text := r.Data.(map[string]any)["text"]; text != "hello" {
+			t.Errorf("expected hello, got %v", text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected result, got none")
+	}
+}
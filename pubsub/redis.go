@@ -0,0 +1,308 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/subscription"
+)
+
+// RedisOptions configure Redis.
+type RedisOptions struct {
+	// Addr is address of Redis server, "localhost:6379" when empty.
+	Addr string
+
+	// Dial opens connections to Redis server, e.g. over TLS; TCP
+	// connections to Addr are opened when nil.
+	Dial func(ctx context.Context) (net.Conn, error)
+
+	// Username and Password authenticate connections when Password is
+	// not empty.
+	Username string
+	Password string
+
+	// Prefix is prepended to topics to get Redis channels, so that
+	// deployments can share a server.
+	Prefix string
+
+	// Buffer is number of events buffered for a subscriber, 64 when zero.
+	Buffer int
+}
+
+// Redis is a bus publishing events to Redis channels, so that they are
+// delivered to subscribers of every instance connected to the server.
+// Payloads are encoded as JSON and delivered decoded into any, numbers as
+// json.Number.
+//
+// Publishers share a single connection, as do subscribers of an instance:
+// channels are subscribed when first subscriber of a topic arrives and
+// unsubscribed when the last one leaves. Lost subscriber connection is
+// reestablished with growing delays and events published meanwhile are
+// lost.
+type Redis struct {
+	opts  RedisOptions
+	local *Memory // Subscribers of this instance.
+
+	pubMu sync.Mutex
+	pub   *conn
+
+	mu      sync.Mutex
+	sub     *conn          // Nil while reconnecting.
+	running bool           // Whether receive is running.
+	topics  map[string]int // Numbers of subscribers.
+	closed  bool
+	done    chan struct{} // Closed by Close.
+}
+
+var _ subscription.Bus = (*Redis)(nil)
+
+// NewRedis returns bus using Redis server of opts. Connections are opened
+// on first use.
+func NewRedis(opts RedisOptions) *Redis {
+	if opts.Addr == "" {
+		opts.Addr = "localhost:6379"
+	}
+	return &Redis{
+		opts:   opts,
+		local:  NewMemory(opts.Buffer),
+		topics: make(map[string]int),
+		done:   make(chan struct{}),
+	}
+}
+
+// Publish publishes payload encoded as JSON to channel of topic.
+func (r *Redis) Publish(ctx context.Context, topic string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	r.pubMu.Lock()
+	defer r.pubMu.Unlock()
+	if r.isClosed() {
+		return ErrClosed
+	}
+	if r.pub == nil {
+		if r.pub, err = r.dial(ctx); err != nil {
+			return err
+		}
+	}
+	r.pub.deadline(ctx)
+	if _, err := r.pub.do("PUBLISH", r.opts.Prefix+topic, string(data)); err != nil {
+		if _, ok := err.(RedisError); !ok {
+			r.pub.Close()
+			r.pub = nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Subscribe returns channel of events published to topic by any
+// instance, closed when ctx is done or bus is closed. It fails when
+// subscriber connection cannot be opened initially.
+func (r *Redis) Subscribe(ctx context.Context, topic string) (<-chan subscription.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ErrClosed
+	}
+	if !r.running {
+		c, err := r.dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.sub, r.running = c, true
+		go r.receive(c)
+	}
+	events, err := r.local.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	r.topics[topic]++
+	if r.topics[topic] == 1 && r.sub != nil {
+		// Failures are noticed and recovered from by receive.
+		r.sub.send("SUBSCRIBE", r.opts.Prefix+topic)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-r.done:
+			return
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.topics[topic]--; r.topics[topic] > 0 {
+			return
+		}
+		delete(r.topics, topic)
+		if r.sub != nil && !r.closed {
+			r.sub.send("UNSUBSCRIBE", r.opts.Prefix+topic)
+		}
+	}()
+	return events, nil
+}
+
+// Subscribers returns number of subscribers of topic in this instance.
+func (r *Redis) Subscribers(topic string) int {
+	return r.local.Subscribers(topic)
+}
+
+// Dropped returns number of events dropped for subscribers of this
+// instance with full buffers.
+func (r *Redis) Dropped() uint64 {
+	return r.local.Dropped()
+}
+
+// Close closes connections and channels of all subscribers. Publish and
+// Subscribe fail with ErrClosed afterwards.
+func (r *Redis) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.done)
+	if r.sub != nil {
+		r.sub.Close()
+		r.sub = nil
+	}
+	r.mu.Unlock()
+
+	r.pubMu.Lock()
+	if r.pub != nil {
+		r.pub.Close()
+		r.pub = nil
+	}
+	r.pubMu.Unlock()
+	return r.local.Close()
+}
+
+func (r *Redis) isClosed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+// dial opens and authenticates a connection.
+func (r *Redis) dial(ctx context.Context) (*conn, error) {
+	var nc net.Conn
+	var err error
+	if r.opts.Dial != nil {
+		nc, err = r.opts.Dial(ctx)
+	} else {
+		var d net.Dialer
+		nc, err = d.DialContext(ctx, "tcp", r.opts.Addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := newConn(nc)
+	if r.opts.Password != "" {
+		args := []string{"AUTH", r.opts.Password}
+		if r.opts.Username != "" {
+			args = []string{"AUTH", r.opts.Username, r.opts.Password}
+		}
+		c.deadline(ctx)
+		if _, err := c.do(args...); err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.SetDeadline(time.Time{})
+	}
+	return c, nil
+}
+
+// receive delivers messages of subscriber connection c to local
+// subscribers, reconnecting until bus is closed.
+func (r *Redis) receive(c *conn) {
+	for c != nil {
+		for {
+			reply, err := c.read()
+			if err != nil {
+				break
+			}
+			r.deliver(reply)
+		}
+		c.Close()
+		r.mu.Lock()
+		if r.sub == c {
+			r.sub = nil
+		}
+		r.mu.Unlock()
+		c = r.reconnect()
+	}
+}
+
+// deliver publishes payload of message reply to local subscribers.
+func (r *Redis) deliver(reply any) {
+	msg, ok := reply.([]any)
+	if !ok || len(msg) != 3 || msg[0] != "message" {
+		return // Confirmations of (un)subscriptions.
+	}
+	channel, _ := msg[1].(string)
+	data, _ := msg[2].(string)
+	topic, ok := strings.CutPrefix(channel, r.opts.Prefix)
+	if !ok {
+		return
+	}
+	d := json.NewDecoder(bytes.NewReader([]byte(data)))
+	d.UseNumber()
+	var payload any
+	if err := d.Decode(&payload); err != nil {
+		return
+	}
+	r.local.Publish(context.Background(), topic, payload)
+}
+
+// dialTimeout limits reconnect attempts.
+const dialTimeout = 5 * time.Second
+
+// reconnect opens subscriber connection with growing delays and
+// subscribes it to channels of all topics. It returns nil when bus is
+// closed.
+func (r *Redis) reconnect() *conn {
+	var delay time.Duration
+	for {
+		delay = backoff(delay)
+		t := time.NewTimer(delay)
+		select {
+		case <-r.done:
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		c, err := r.dial(ctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			c.Close()
+			return nil
+		}
+		if len(r.topics) > 0 {
+			args := []string{"SUBSCRIBE"}
+			for topic := range r.topics {
+				args = append(args, r.opts.Prefix+topic)
+			}
+			if err := c.send(args...); err != nil {
+				r.mu.Unlock()
+				c.Close()
+				continue
+			}
+		}
+		r.sub = c
+		r.mu.Unlock()
+		return c
+	}
+}
@@ -0,0 +1,234 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves publish and subscribe commands of Redis over
+// connections of Dial.
+type fakeRedis struct {
+	password string
+
+	mu       sync.Mutex
+	conns    map[*fakeConn]struct{}
+	channels map[string]map[*fakeConn]struct{}
+}
+
+type fakeConn struct {
+	*conn
+	mu sync.Mutex // Guards writes.
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		conns:    make(map[*fakeConn]struct{}),
+		channels: make(map[string]map[*fakeConn]struct{}),
+	}
+}
+
+func (s *fakeRedis) Dial(ctx context.Context) (net.Conn, error) {
+	client, server := net.Pipe()
+	c := &fakeConn{conn: newConn(server)}
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+	go s.serve(c)
+	return client, nil
+}
+
+// write writes reply items, strings as bulk strings.
+func (c *fakeConn) write(items ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(items) > 1 {
+		fmt.Fprintf(c.w, "*%d\r\n", len(items))
+	}
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(item), item)
+		case int:
+			fmt.Fprintf(c.w, ":%d\r\n", item)
+		case RedisError:
+			fmt.Fprintf(c.w, "-%s\r\n", string(item))
+		}
+	}
+	c.w.Flush()
+}
+
+func (s *fakeRedis) serve(c *fakeConn) {
+	defer s.drop(c)
+	for {
+		reply, err := c.read()
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, item := range reply.([]any) {
+			args = append(args, item.(string))
+		}
+		switch args[0] {
+		case "AUTH":
+			if args[len(args)-1] != s.password {
+				c.write(RedisError("WRONGPASS invalid password"))
+				continue
+			}
+			c.write(1)
+		case "SUBSCRIBE", "UNSUBSCRIBE":
+			for _, channel := range args[1:] {
+				s.mu.Lock()
+				if args[0] == "SUBSCRIBE" {
+					if s.channels[channel] == nil {
+						s.channels[channel] = make(map[*fakeConn]struct{})
+					}
+					s.channels[channel][c] = struct{}{}
+				} else {
+					delete(s.channels[channel], c)
+				}
+				s.mu.Unlock()
+				c.write(map[string]string{"SUBSCRIBE": "subscribe", "UNSUBSCRIBE": "unsubscribe"}[args[0]], channel, 1)
+			}
+		case "PUBLISH":
+			s.mu.Lock()
+			var subscribers []*fakeConn
+			for sc := range s.channels[args[1]] {
+				subscribers = append(subscribers, sc)
+			}
+			s.mu.Unlock()
+			for _, sc := range subscribers {
+				sc.write("message", args[1], args[2])
+			}
+			c.write(len(subscribers))
+		default:
+			c.write(RedisError("ERR unknown command"))
+		}
+	}
+}
+
+func (s *fakeRedis) drop(c *fakeConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, c)
+	for _, subscribers := range s.channels {
+		delete(subscribers, c)
+	}
+	c.Close()
+}
+
+// disconnect closes all connections.
+func (s *fakeRedis) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+// subscribers returns number of connections subscribed to channel.
+func (s *fakeRedis) subscribers(channel string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.channels[channel])
+}
+
+// await waits for channel to have n subscribers.
+func (s *fakeRedis) await(t *testing.T, channel string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.subscribers(channel) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers of %s, got %d", n, channel, s.subscribers(channel))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis()
+	server.password = "secret"
+	opts := RedisOptions{Dial: server.Dial, Password: "secret", Prefix: "gql:"}
+	a, b := NewRedis(opts), NewRedis(opts)
+	defer a.Close()
+	defer b.Close()
+
+	subCtx, cancel := context.WithCancel(ctx)
+	events, err := a.Subscribe(subCtx, "messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.await(t, "gql:messages", 1)
+
+	type message struct {
+		ID   int    `json:"id"`
+		Text string `json:"text"`
+	}
+	if err := b.Publish(ctx, "messages", message{7, "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	e := receive(t, events)
+	expected := map[string]any{"id": json.Number("7"), "text": "hello"}
+	if e.Topic != "messages" || fmt.Sprint(e.Payload) != fmt.Sprint(expected) {
+		t.Errorf("expected %v on messages, got %v on %s", expected, e.Payload, e.Topic)
+	}
+
+	// Subscriber connection is reestablished and resubscribed.
+	server.disconnect()
+	server.await(t, "gql:messages", 0)
+	server.await(t, "gql:messages", 1)
+	if err := b.Publish(ctx, "messages", "again"); err != nil {
+		// Publisher connection was closed too, so the first attempt may
+		// fail.
+		if err := b.Publish(ctx, "messages", "again"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if e := receive(t, events); e.Payload != "again" {
+		t.Errorf("expected again, got %v", e.Payload)
+	}
+
+	cancel()
+	closed(t, events)
+	server.await(t, "gql:messages", 0)
+
+	a.Close()
+	if _, err := a.Subscribe(ctx, "messages"); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if err := a.Publish(ctx, "messages", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestRedis_Errors(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis()
+	server.password = "secret"
+
+	r := NewRedis(RedisOptions{Dial: server.Dial, Password: "wrong"})
+	defer r.Close()
+	var redisErr RedisError
+	if _, err := r.Subscribe(ctx, "messages"); !errors.As(err, &redisErr) {
+		t.Errorf("expected RedisError, got %v", err)
+	}
+	if err := r.Publish(ctx, "messages", nil); !errors.As(err, &redisErr) {
+		t.Errorf("expected RedisError, got %v", err)
+	}
+
+	dialErr := errors.New("connection refused")
+	r = NewRedis(RedisOptions{Dial: func(context.Context) (net.Conn, error) { return nil, dialErr }})
+	defer r.Close()
+	if _, err := r.Subscribe(ctx, "messages"); !errors.Is(err, dialErr) {
+		t.Errorf("expected %v, got %v", dialErr, err)
+	}
+	if err := r.Publish(ctx, "messages", func() {}); err == nil {
+		t.Error("expected error for payload not encodable as JSON, got none")
+	}
+}
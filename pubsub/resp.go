@@ -0,0 +1,112 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// RedisError is an error reply of Redis server.
+type RedisError string
+
+func (e RedisError) Error() string {
+	return "pubsub: redis: " + string(e)
+}
+
+// conn is a connection speaking Redis serialization protocol, RESP2.
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func newConn(c net.Conn) *conn {
+	return &conn{Conn: c, r: bufio.NewReader(c), w: bufio.NewWriter(c)}
+}
+
+// deadline sets deadline of c from ctx, clearing it when ctx has none.
+func (c *conn) deadline(ctx context.Context) {
+	d, _ := ctx.Deadline()
+	c.SetDeadline(d)
+}
+
+// send writes command args and flushes it.
+func (c *conn) send(args ...string) error {
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return c.w.Flush()
+}
+
+// do sends command args and returns its reply, or error reply as error.
+func (c *conn) do(args ...string) (any, error) {
+	if err := c.send(args...); err != nil {
+		return nil, err
+	}
+	reply, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := reply.(RedisError); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+// read returns next reply: string for simple and bulk strings, int64 for
+// integers, []any for arrays and pushes, nil for nulls and RedisError for
+// errors.
+func (c *conn) read() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("pubsub: redis: malformed reply")
+	}
+	kind, line := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return line, nil
+	case '-':
+		return RedisError(line), nil
+	case ':':
+		return strconv.ParseInt(line, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*', '>':
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("pubsub: redis: unexpected reply type %q", kind)
+}
+
+// backoff returns delay before reconnect attempt following one delayed by
+// d.
+func backoff(d time.Duration) time.Duration {
+	const minDelay, maxDelay = 100 * time.Millisecond, 5 * time.Second
+	return min(max(2*d, minDelay), maxDelay)
+}
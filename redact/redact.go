@@ -0,0 +1,295 @@
+// Package redact omits schema elements named by schema coordinates from
+// SDL documents, producing partner-facing artifacts of a schema, without
+// internal fields or experimental types, from a single source:
+//
+//	sdl, err := redact.SDL(doc, []string{"Query.debug", "ExperimentalWidget", "@internal"})
+package redact
+
+import (
+	"fmt"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// Document returns doc without elements named by coordinates: types and
+// their extensions, fields, arguments, input fields, enum values,
+// directive definitions and directive arguments. Omitting a type also
+// omits fields, arguments and input fields of that type, and union
+// members, implemented interfaces and root operation types naming it.
+// Omitting a directive definition or argument also omits its
+// applications. Extensions left empty are omitted.
+//
+// Coordinates must refer to elements of doc, so redaction configuration
+// does not go stale unnoticed when the schema changes. doc is not
+// modified; returned document shares unchanged nodes with it.
+func Document(doc *ast.Document, coordinates []string) (*ast.Document, error) {
+	r := &redactor{omitted: make(map[coordinate.Coordinate]bool)}
+	for _, s := range coordinates {
+		c, err := coordinate.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("redact: %w", err)
+		}
+		if _, err := coordinate.Resolve(doc, c); err != nil {
+			return nil, fmt.Errorf("redact: %w", err)
+		}
+		r.omitted[c] = true
+	}
+
+	result := &ast.Document{}
+	for _, def := range doc.Definitions {
+		if def = r.definition(def); def != nil && !isEmptyExtension(def) {
+			result.Definitions = append(result.Definitions, def)
+		}
+	}
+	return result, nil
+}
+
+// SDL returns doc without elements named by coordinates, as by Document,
+// printed with opts.
+func SDL(doc *ast.Document, coordinates []string, opts ...printer.Option) (string, error) {
+	result, err := Document(doc, coordinates)
+	if err != nil {
+		return "", err
+	}
+	return printer.PrintDocument(result, opts...), nil
+}
+
+type redactor struct {
+	omitted map[coordinate.Coordinate]bool
+}
+
+func (r *redactor) typeOmitted(t ast.Type) bool {
+	for {
+		switch wrapped := t.(type) {
+		case *ast.NonNullType:
+			t = wrapped.Type
+		case *ast.ListType:
+			t = wrapped.Type
+		case *ast.NamedType:
+			return r.omitted[coordinate.Type(wrapped.Name.Value)]
+		default:
+			return false
+		}
+	}
+}
+
+// definition returns copy of def without omitted elements, or nil when def
+// is omitted.
+func (r *redactor) definition(def ast.Definition) ast.Definition {
+	switch d := def.(type) {
+	case *ast.SchemaDefinition:
+		c := *d
+		c.Directives, c.RootOperationDefs = r.directives(d.Directives), r.rootOperations(d.RootOperationDefs)
+		return &c
+	case *ast.SchemaExtension:
+		c := *d
+		c.Directives, c.RootOperationDefs = r.directives(d.Directives), r.rootOperations(d.RootOperationDefs)
+		return &c
+	case *ast.DirectiveDefinition:
+		if r.omitted[coordinate.Directive(d.Name.Value)] {
+			return nil
+		}
+		c := *d
+		c.Arguments = r.inputValues(d.Arguments, func(arg string) coordinate.Coordinate {
+			return coordinate.DirectiveArgument(d.Name.Value, arg)
+		})
+		return &c
+	}
+
+	if name := typeName(def); name != "" && r.omitted[coordinate.Type(name)] {
+		return nil
+	}
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		c := *d
+		c.Directives = r.directives(d.Directives)
+		return &c
+	case *ast.ScalarTypeExtension:
+		c := *d
+		c.Directives = r.directives(d.Directives)
+		return &c
+	case *ast.ObjectTypeDefinition:
+		c := *d
+		c.Interfaces, c.Directives, c.Fields = r.namedTypes(d.Interfaces), r.directives(d.Directives), r.fields(d.Name.Value, d.Fields)
+		return &c
+	case *ast.ObjectTypeExtension:
+		c := *d
+		c.Interfaces, c.Directives, c.Fields = r.namedTypes(d.Interfaces), r.directives(d.Directives), r.fields(d.Name.Value, d.Fields)
+		return &c
+	case *ast.InterfaceTypeDefinition:
+		c := *d
+		c.Interfaces, c.Directives, c.Fields = r.namedTypes(d.Interfaces), r.directives(d.Directives), r.fields(d.Name.Value, d.Fields)
+		return &c
+	case *ast.InterfaceTypeExtension:
+		c := *d
+		c.Interfaces, c.Directives, c.Fields = r.namedTypes(d.Interfaces), r.directives(d.Directives), r.fields(d.Name.Value, d.Fields)
+		return &c
+	case *ast.UnionTypeDefinition:
+		c := *d
+		c.Directives, c.Types = r.directives(d.Directives), r.namedTypes(d.Types)
+		return &c
+	case *ast.UnionTypeExtension:
+		c := *d
+		c.Directives, c.Types = r.directives(d.Directives), r.namedTypes(d.Types)
+		return &c
+	case *ast.EnumTypeDefinition:
+		c := *d
+		c.Directives, c.Values = r.directives(d.Directives), r.enumValues(d.Name.Value, d.Values)
+		return &c
+	case *ast.EnumTypeExtension:
+		c := *d
+		c.Directives, c.Values = r.directives(d.Directives), r.enumValues(d.Name.Value, d.Values)
+		return &c
+	case *ast.InputObjectTypeDefinition:
+		c := *d
+		c.Directives, c.Fields = r.directives(d.Directives), r.inputValues(d.Fields, func(field string) coordinate.Coordinate {
+			return coordinate.Member(d.Name.Value, field)
+		})
+		return &c
+	case *ast.InputObjectTypeExtension:
+		c := *d
+		c.Directives, c.Fields = r.directives(d.Directives), r.inputValues(d.Fields, func(field string) coordinate.Coordinate {
+			return coordinate.Member(d.Name.Value, field)
+		})
+		return &c
+	}
+	return def
+}
+
+func (r *redactor) fields(typeName string, fields []*ast.FieldDefinition) []*ast.FieldDefinition {
+	var result []*ast.FieldDefinition
+	for _, f := range fields {
+		if r.omitted[coordinate.Member(typeName, f.Name.Value)] || r.typeOmitted(f.Type) {
+			continue
+		}
+		c := *f
+		c.Directives = r.directives(f.Directives)
+		c.Arguments = r.inputValues(f.Arguments, func(arg string) coordinate.Coordinate {
+			return coordinate.Argument(typeName, f.Name.Value, arg)
+		})
+		result = append(result, &c)
+	}
+	return result
+}
+
+// inputValues returns arguments or input fields, whose coordinates are
+// given by coord, without omitted ones.
+func (r *redactor) inputValues(values []*ast.InputValueDefinition, coord func(string) coordinate.Coordinate) []*ast.InputValueDefinition {
+	var result []*ast.InputValueDefinition
+	for _, v := range values {
+		if r.omitted[coord(v.Name.Value)] || r.typeOmitted(v.Type) {
+			continue
+		}
+		c := *v
+		c.Directives = r.directives(v.Directives)
+		result = append(result, &c)
+	}
+	return result
+}
+
+func (r *redactor) enumValues(typeName string, values []*ast.EnumValueDefinition) []*ast.EnumValueDefinition {
+	var result []*ast.EnumValueDefinition
+	for _, v := range values {
+		if r.omitted[coordinate.Member(typeName, v.Name.Value)] {
+			continue
+		}
+		c := *v
+		c.Directives = r.directives(v.Directives)
+		result = append(result, &c)
+	}
+	return result
+}
+
+func (r *redactor) namedTypes(types []*ast.NamedType) []*ast.NamedType {
+	var result []*ast.NamedType
+	for _, t := range types {
+		if !r.typeOmitted(t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func (r *redactor) rootOperations(roots []*ast.RootOperationTypeDefinition) []*ast.RootOperationTypeDefinition {
+	var result []*ast.RootOperationTypeDefinition
+	for _, root := range roots {
+		if !r.typeOmitted(root.Type) {
+			result = append(result, root)
+		}
+	}
+	return result
+}
+
+// directives returns applied directives without applications and
+// arguments of omitted directive definitions.
+func (r *redactor) directives(directives []*ast.Directive) []*ast.Directive {
+	var result []*ast.Directive
+	for _, d := range directives {
+		if r.omitted[coordinate.Directive(d.Name.Value)] {
+			continue
+		}
+		c := *d
+		c.Arguments = nil
+		for _, arg := range d.Arguments {
+			if !r.omitted[coordinate.DirectiveArgument(d.Name.Value, arg.Name.Value)] {
+				c.Arguments = append(c.Arguments, arg)
+			}
+		}
+		result = append(result, &c)
+	}
+	return result
+}
+
+// isEmptyExtension reports whether extension was left without anything
+// to extend with, which is not valid SDL.
+func isEmptyExtension(def ast.Definition) bool {
+	switch d := def.(type) {
+	case *ast.SchemaExtension:
+		return len(d.Directives) == 0 && len(d.RootOperationDefs) == 0
+	case *ast.ScalarTypeExtension:
+		return len(d.Directives) == 0
+	case *ast.ObjectTypeExtension:
+		return len(d.Interfaces) == 0 && len(d.Directives) == 0 && len(d.Fields) == 0
+	case *ast.InterfaceTypeExtension:
+		return len(d.Interfaces) == 0 && len(d.Directives) == 0 && len(d.Fields) == 0
+	case *ast.UnionTypeExtension:
+		return len(d.Directives) == 0 && len(d.Types) == 0
+	case *ast.EnumTypeExtension:
+		return len(d.Directives) == 0 && len(d.Values) == 0
+	case *ast.InputObjectTypeExtension:
+		return len(d.Directives) == 0 && len(d.Fields) == 0
+	}
+	return false
+}
+
+func typeName(def ast.Definition) string {
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		return d.Name.Value
+	case *ast.ScalarTypeExtension:
+		return d.Name.Value
+	case *ast.ObjectTypeDefinition:
+		return d.Name.Value
+	case *ast.ObjectTypeExtension:
+		return d.Name.Value
+	case *ast.InterfaceTypeDefinition:
+		return d.Name.Value
+	case *ast.InterfaceTypeExtension:
+		return d.Name.Value
+	case *ast.UnionTypeDefinition:
+		return d.Name.Value
+	case *ast.UnionTypeExtension:
+		return d.Name.Value
+	case *ast.EnumTypeDefinition:
+		return d.Name.Value
+	case *ast.EnumTypeExtension:
+		return d.Name.Value
+	case *ast.InputObjectTypeDefinition:
+		return d.Name.Value
+	case *ast.InputObjectTypeExtension:
+		return d.Name.Value
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package redact
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+const testSchema = `schema { query: Query mutation: Mutation }
+directive @internal on FIELD_DEFINITION | OBJECT
+directive @cache(maxAge: Int, scope: String) on FIELD_DEFINITION
+type Query {
+  user(id: ID!, debug: Boolean): User @cache(maxAge: 60, scope: "private")
+  widget: Widget
+  widgets(filter: WidgetFilter): [Widget!]!
+  debug: String @internal
+}
+type Mutation { deleteUser(id: ID!): Boolean }
+type User implements Node & Experimental { id: ID! name: String role: Role }
+interface Node { id: ID! }
+interface Experimental { id: ID! }
+type Widget @internal { id: ID! }
+union SearchResult = User | Widget
+enum Role { ADMIN STAFF MEMBER }
+input WidgetFilter { name: String }
+extend type Widget { size: Int }
+extend type User { widget: Widget }`
+
+func TestSDL(t *testing.T) {
+	tests := []struct {
+		name        string
+		coordinates []string
+		expected    string
+	}{
+		{
+			name:        "fields and arguments",
+			coordinates: []string{"Query.debug", "Query.user(debug:)", "Role.STAFF", "Query.widgets(filter:)"},
+			expected: `schema {
+  query: Query
+  mutation: Mutation
+}
+
+directive @internal on FIELD_DEFINITION | OBJECT
+
+directive @cache(maxAge: Int, scope: String) on FIELD_DEFINITION
+
+type Query {
+  user(id: ID!): User @cache(maxAge: 60, scope: "private")
+  widget: Widget
+  widgets: [Widget!]!
+}
+
+type Mutation {
+  deleteUser(id: ID!): Boolean
+}
+
+type User implements Node & Experimental {
+  id: ID!
+  name: String
+  role: Role
+}
+
+interface Node {
+  id: ID!
+}
+
+interface Experimental {
+  id: ID!
+}
+
+type Widget @internal {
+  id: ID!
+}
+
+union SearchResult = User | Widget
+
+enum Role {
+  ADMIN
+  MEMBER
+}
+
+input WidgetFilter {
+  name: String
+}
+
+extend type Widget {
+  size: Int
+}
+
+extend type User {
+  widget: Widget
+}`,
+		},
+		{
+			name:        "types and directives",
+			coordinates: []string{"Widget", "WidgetFilter", "Experimental", "Mutation", "@internal", "@cache(scope:)"},
+			expected: `schema {
+  query: Query
+}
+
+directive @cache(maxAge: Int) on FIELD_DEFINITION
+
+type Query {
+  user(id: ID!, debug: Boolean): User @cache(maxAge: 60)
+  debug: String
+}
+
+type User implements Node {
+  id: ID!
+  name: String
+  role: Role
+}
+
+interface Node {
+  id: ID!
+}
+
+union SearchResult = User
+
+enum Role {
+  ADMIN
+  STAFF
+  MEMBER
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SDL(asttest.Parse(t, testSchema), tt.coordinates)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDocument_NotModified(t *testing.T) {
+	doc := asttest.Parse(t, testSchema)
+	before := printer.PrintDocument(doc)
+	if _, err := Document(doc, []string{"Widget", "Query.user(debug:)", "@cache(scope:)"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after := printer.PrintDocument(doc); after != before {
+		t.Errorf("expected document not to be modified, got:\n%s", after)
+	}
+}
+
+func TestDocument_Errors(t *testing.T) {
+	tests := []struct {
+		coordinate string
+		expected   string
+	}{
+		{"Query.", `redact: invalid schema coordinate "Query.": expected member name after '.'`},
+		{"Query.missing", "redact: schema element not found: Query.missing"},
+		{"@missing", "redact: schema element not found: @missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.coordinate, func(t *testing.T) {
+			_, err := Document(asttest.Parse(t, testSchema), []string{tt.coordinate})
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+	_, err := Document(asttest.Parse(t, testSchema), []string{"Missing"})
+	if !errors.Is(err, coordinate.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/astdiff"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/operation"
+	"github.com/gqlhub/gqlhub-core/schema"
+	"github.com/gqlhub/gqlhub-core/validation"
+)
+
+// Report is impact of a schema change on registered clients.
+type Report struct {
+	// Changes are Breaking and Dangerous changes of the schema, in order
+	// of astdiff.Diff.
+	Changes []astdiff.Change
+
+	// Clients are reports of every registered client, ordered by name and
+	// version.
+	Clients []ClientReport
+}
+
+// ClientReport is impact of a schema change on a client version.
+type ClientReport struct {
+	Client     Client
+	Operations int      // Number of operations of client.
+	Impacts    []Impact // Affected operations, in manifest order.
+}
+
+// Impact is impact of a schema change on an operation.
+type Impact struct {
+	ID, Name string // Of the operation in manifest.
+
+	// Severity is Breaking when operation is invalid against the new
+	// schema or uses element changed in a breaking way, and Dangerous
+	// otherwise.
+	Severity astdiff.Severity
+
+	// Errors are validation errors of operation against the new schema.
+	Errors []*validation.Error
+
+	// Changes are Breaking and Dangerous changes of elements operation
+	// uses: fields it selects, arguments it passes, types of both,
+	// types of its variables, fragment type conditions and directives.
+	Changes []astdiff.Change
+}
+
+// Check returns impact of changing schema document old to new on
+// registered clients. Operations are validated against new, which must be
+// a valid schema, and their usage of old is matched with changes.
+func (r *Registry) Check(old, new *ast.Document) (*Report, error) {
+	merged, err := schema.Merge(new)
+	if err != nil {
+		return nil, fmt.Errorf("registry: invalid new schema: %w", err)
+	}
+	s, err := schema.FromDocument(merged)
+	if err != nil {
+		return nil, fmt.Errorf("registry: invalid new schema: %w", err)
+	}
+	report := &Report{}
+	for _, c := range astdiff.Diff(old, new) {
+		if c.Severity != astdiff.Safe {
+			report.Changes = append(report.Changes, c)
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for client, ops := range r.clients {
+		cr := ClientReport{Client: client, Operations: len(ops)}
+		for _, op := range ops {
+			if impact, ok := check(op, old, s, report.Changes); ok {
+				cr.Impacts = append(cr.Impacts, impact)
+			}
+		}
+		report.Clients = append(report.Clients, cr)
+	}
+	slices.SortFunc(report.Clients, func(a, b ClientReport) int {
+		return compareClients(a.Client, b.Client)
+	})
+	return report, nil
+}
+
+// check returns impact of changes on op, and whether there is any.
+func check(op *clientOperation, old *ast.Document, s *schema.Schema, changes []astdiff.Change) (Impact, bool) {
+	impact := Impact{ID: op.id, Name: op.name, Severity: astdiff.Dangerous}
+	impact.Errors = validation.Validate(s, op.doc)
+	if len(impact.Errors) > 0 {
+		impact.Severity = astdiff.Breaking
+	}
+	used := usage(op, old)
+	for _, c := range changes {
+		if !affects(c, used) {
+			continue
+		}
+		impact.Changes = append(impact.Changes, c)
+		if c.Severity == astdiff.Breaking {
+			impact.Severity = astdiff.Breaking
+		}
+	}
+	return impact, len(impact.Errors) > 0 || len(impact.Changes) > 0
+}
+
+// usage returns coordinates of elements of schema document old used by
+// op.
+func usage(op *clientOperation, old *ast.Document) map[coordinate.Coordinate]bool {
+	used := make(map[coordinate.Coordinate]bool)
+	for _, v := range op.op.VariableDefs {
+		used[coordinate.Type(namedType(v.Type))] = true
+	}
+	directives := func(directives []*ast.Directive) {
+		for _, d := range directives {
+			used[coordinate.Directive(d.Name.Value)] = true
+			for _, arg := range d.Arguments {
+				used[coordinate.DirectiveArgument(d.Name.Value, arg.Name.Value)] = true
+			}
+		}
+	}
+	for f := range operation.Fields(op.doc, op.op, old) {
+		directives(f.Directives)
+		directives(f.Field.Directives)
+		if f.ParentType == "" {
+			continue
+		}
+		used[coordinate.Type(f.ParentType)] = true
+		if f.Definition == nil {
+			continue
+		}
+		name := f.Field.Name.Value
+		used[coordinate.Member(f.ParentType, name)] = true
+		used[coordinate.Type(namedType(f.Definition.Type))] = true
+		for _, arg := range f.Field.Arguments {
+			used[coordinate.Argument(f.ParentType, name, arg.Name.Value)] = true
+			for _, def := range f.Definition.Arguments {
+				if def.Name.Value == arg.Name.Value {
+					used[coordinate.Type(namedType(def.Type))] = true
+				}
+			}
+		}
+	}
+	return used
+}
+
+// affects reports whether change c affects operation using elements
+// used. Changes of fields and arguments affect operations selecting the
+// field, or passing the argument when it is removed or changed; changes
+// of types and their members affect operations using the type. Changes of
+// root types are assumed to affect every operation.
+func affects(c astdiff.Change, used map[coordinate.Coordinate]bool) bool {
+	cc := c.Coordinate
+	switch {
+	case cc == coordinate.Coordinate{}:
+		return true
+	case cc.Directive:
+		return used[coordinate.Directive(cc.Name)]
+	}
+	switch c.Kind {
+	case astdiff.FieldRemoved, astdiff.FieldTypeChanged, astdiff.ArgumentAdded:
+		return used[coordinate.Member(cc.Name, cc.Member)]
+	case astdiff.ArgumentRemoved, astdiff.ArgumentTypeChanged, astdiff.ArgumentDefaultChanged:
+		return used[cc]
+	}
+	return used[coordinate.Type(cc.Name)]
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch wrapped := t.(type) {
+		case *ast.NonNullType:
+			t = wrapped.Type
+		case *ast.ListType:
+			t = wrapped.Type
+		case *ast.NamedType:
+			return wrapped.Name.Value
+		default:
+			return ""
+		}
+	}
+}
+
+// Breaking reports whether the schema change breaks some client.
+func (r *Report) Breaking() bool {
+	for _, c := range r.Clients {
+		if c.Severity() == astdiff.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity returns the highest severity of impacts on client, Safe when
+// none of its operations are affected.
+func (c ClientReport) Severity() astdiff.Severity {
+	severity := astdiff.Safe
+	for _, impact := range c.Impacts {
+		if impact.Severity == astdiff.Breaking {
+			return astdiff.Breaking
+		}
+		severity = astdiff.Dangerous
+	}
+	return severity
+}
+
+// Client returns report of client c, or nil when it is not registered.
+func (r *Report) Client(c Client) *ClientReport {
+	for i := range r.Clients {
+		if r.Clients[i].Client == c {
+			return &r.Clients[i]
+		}
+	}
+	return nil
+}
+
+// WriteText writes human readable report, a line per client followed by
+// lines of affected operations:
+//
+//	ios@4.2.0: BREAKING (1 of 12 operations affected)
+//	  Me: BREAKING
+//	    Cannot query field "email" on type "User".
+//	    BREAKING field User.email removed
+func (r *Report) WriteText(w io.Writer) error {
+	for _, c := range r.Clients {
+		if _, err := fmt.Fprintf(w, "%s: %s (%d of %d operations affected)\n", c.Client, c.Severity(), len(c.Impacts), c.Operations); err != nil {
+			return err
+		}
+		for _, impact := range c.Impacts {
+			name := impact.Name
+			if name == "" {
+				name = impact.ID
+			}
+			if _, err := fmt.Fprintf(w, "  %s: %s\n", name, impact.Severity); err != nil {
+				return err
+			}
+			for _, e := range impact.Errors {
+				if _, err := fmt.Fprintf(w, "    %s\n", e.Message); err != nil {
+					return err
+				}
+			}
+			for _, change := range impact.Changes {
+				if _, err := fmt.Fprintf(w, "    %s %s\n", change.Severity, change.Message); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonClient is JSON encoding of ClientReport.
+type jsonClient struct {
+	Client
+	Severity   astdiff.Severity `json:"severity"`
+	Operations int              `json:"operations"`
+	Impacts    []jsonImpact     `json:"impacts"`
+}
+
+type jsonImpact struct {
+	ID       string           `json:"id"`
+	Name     string           `json:"name,omitempty"`
+	Severity astdiff.Severity `json:"severity"`
+	Errors   []string         `json:"errors,omitempty"`
+	Changes  []jsonChange     `json:"changes,omitempty"`
+}
+
+type jsonChange struct {
+	Kind       astdiff.ChangeKind `json:"kind"`
+	Coordinate string             `json:"coordinate,omitempty"`
+	Severity   astdiff.Severity   `json:"severity"`
+	Message    string             `json:"message"`
+}
+
+// WriteJSON writes report as JSON object with "clients" array of objects
+// with "name", "version", "severity", "operations" and "impacts" of
+// affected operations, each with "id", "name", "severity", validation
+// "errors" and "changes" with "kind", "coordinate", "severity" and
+// "message".
+func (r *Report) WriteJSON(w io.Writer) error {
+	clients := make([]jsonClient, 0, len(r.Clients))
+	for _, c := range r.Clients {
+		jc := jsonClient{Client: c.Client, Severity: c.Severity(), Operations: c.Operations, Impacts: []jsonImpact{}}
+		for _, impact := range c.Impacts {
+			ji := jsonImpact{ID: impact.ID, Name: impact.Name, Severity: impact.Severity}
+			for _, e := range impact.Errors {
+				ji.Errors = append(ji.Errors, e.Message)
+			}
+			for _, change := range impact.Changes {
+				ch := jsonChange{Kind: change.Kind, Severity: change.Severity, Message: change.Message}
+				if change.Coordinate != (coordinate.Coordinate{}) {
+					ch.Coordinate = change.Coordinate.String()
+				}
+				ji.Changes = append(ji.Changes, ch)
+			}
+			jc.Impacts = append(jc.Impacts, ji)
+		}
+		clients = append(clients, jc)
+	}
+	return json.NewEncoder(w).Encode(struct {
+		Clients []jsonClient `json:"clients"`
+	}{clients})
+}
@@ -0,0 +1,166 @@
+// Package registry records operations client versions send, from their
+// persisted query manifests, and reports which client versions a schema
+// change breaks:
+//
+//	r := registry.New()
+//	r.Register(registry.Client{Name: "ios", Version: "4.2.0"}, manifest)
+//	report, err := r.Check(oldSchema, newSchema)
+//	if report.Breaking() {
+//		report.WriteText(os.Stderr)
+//	}
+package registry
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+)
+
+// ManifestFormat is format of persisted query manifests.
+const ManifestFormat = "apollo-persisted-query-manifest"
+
+// Manifest is a persisted query manifest listing operations a client
+// build sends, as generated by Apollo tooling:
+//
+//	{"format": "apollo-persisted-query-manifest", "version": 1, "operations": [
+//	  {"id": "5b6e...", "name": "Me", "type": "query", "body": "query Me { me { id } }"}
+//	]}
+type Manifest struct {
+	Format     string              `json:"format"`
+	Version    int                 `json:"version"`
+	Operations []ManifestOperation `json:"operations"`
+}
+
+// ManifestOperation is an operation of Manifest. Body is a document
+// with the operation and fragments it spreads.
+type ManifestOperation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
+// ParseManifest decodes JSON encoded manifest, checking its format and
+// version.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("registry: invalid manifest: %w", err)
+	}
+	if m.Format != ManifestFormat {
+		return nil, fmt.Errorf("registry: unsupported manifest format %q", m.Format)
+	}
+	if m.Version != 1 {
+		return nil, fmt.Errorf("registry: unsupported manifest version %d", m.Version)
+	}
+	return &m, nil
+}
+
+// Client identifies a client app version.
+type Client struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+func (c Client) String() string {
+	return c.Name + "@" + c.Version
+}
+
+func compareClients(a, b Client) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+}
+
+// Registry records operations of client versions. It is safe for
+// concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[Client][]*clientOperation
+}
+
+// clientOperation is a parsed operation of a manifest.
+type clientOperation struct {
+	id, name string
+	doc      *ast.Document
+	op       *ast.OperationDefinition
+}
+
+// New returns empty registry.
+func New() *Registry {
+	return &Registry{clients: make(map[Client][]*clientOperation)}
+}
+
+// Register records operations of manifest m as sent by client c,
+// replacing operations registered for c before. Bodies of operations must
+// be documents with operation named by the manifest, or a single
+// operation when it has no name.
+func (r *Registry) Register(c Client, m *Manifest) error {
+	ops := make([]*clientOperation, 0, len(m.Operations))
+	for _, mo := range m.Operations {
+		op, err := parseOperation(mo)
+		if err != nil {
+			return fmt.Errorf("registry: client %s: operation %q: %w", c, cmp.Or(mo.Name, mo.ID), err)
+		}
+		ops = append(ops, op)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[c] = ops
+	return nil
+}
+
+// Unregister forgets client c, e.g. when its version is no longer
+// supported, and reports whether it was registered.
+func (r *Registry) Unregister(c Client) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.clients[c]
+	delete(r.clients, c)
+	return ok
+}
+
+// Clients returns registered clients ordered by name and version.
+func (r *Registry) Clients() []Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clients := make([]Client, 0, len(r.clients))
+	for c := range r.clients {
+		clients = append(clients, c)
+	}
+	slices.SortFunc(clients, compareClients)
+	return clients
+}
+
+func parseOperation(mo ManifestOperation) (*clientOperation, error) {
+	p, err := parser.New(lexer.New(mo.Body))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := p.ParseDocument()
+	if err != nil {
+		return nil, err
+	}
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if mo.Name == "" && found != nil {
+			return nil, errors.New("body has many operations")
+		}
+		if mo.Name == "" || op.Name != nil && op.Name.Value == mo.Name {
+			found = op
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("body has no operation %q", mo.Name)
+	}
+	return &clientOperation{id: mo.ID, name: mo.Name, doc: doc, op: found}, nil
+}
@@ -0,0 +1,193 @@
+package registry
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/astdiff"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+const oldSchema = `
+type Query { me: User search(text: String, first: Int): [User] }
+type User { id: ID! name: String email: String role: Role }
+enum Role { ADMIN USER }
+`
+
+const newSchema = `
+type Query { me: User search(text: String!, first: Int): [User] }
+type User { id: ID! name: String role: Role }
+enum Role { ADMIN USER GUEST }
+`
+
+func manifest(t *testing.T, ops ...ManifestOperation) *Manifest {
+	t.Helper()
+	return &Manifest{Format: ManifestFormat, Version: 1, Operations: ops}
+}
+
+func TestCheck(t *testing.T) {
+	r := New()
+	web := Client{Name: "web", Version: "2.0.0"}
+	ios := Client{Name: "ios", Version: "4.2.0"}
+	android := Client{Name: "android", Version: "1.0.0"}
+	for c, m := range map[Client]*Manifest{
+		web: manifest(t,
+			ManifestOperation{ID: "1", Name: "Me", Type: "query", Body: `query Me { me { ...U } } fragment U on User { id name }`},
+			ManifestOperation{ID: "2", Name: "Search", Type: "query", Body: `query Search($text: String) { search(text: $text) { id } }`},
+		),
+		ios: manifest(t,
+			ManifestOperation{ID: "3", Name: "Me", Type: "query", Body: `query Me { me { id email } }`},
+		),
+		android: manifest(t,
+			ManifestOperation{ID: "4", Type: "query", Body: `{ me { role } }`},
+		),
+	} {
+		if err := r.Register(c, m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	report, err := r.Check(asttest.Parse(t, oldSchema), asttest.Parse(t, newSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Breaking() {
+		t.Errorf("expected breaking report")
+	}
+
+	tests := []struct {
+		client   Client
+		severity astdiff.Severity
+		impacts  []string
+	}{
+		{android, astdiff.Dangerous, []string{"4 DANGEROUS ENUM_VALUE_ADDED"}},
+		{ios, astdiff.Breaking, []string{"Me BREAKING 1 FIELD_REMOVED"}},
+		{web, astdiff.Breaking, []string{"Search BREAKING 1 ARGUMENT_TYPE_CHANGED"}},
+	}
+	if len(report.Clients) != len(tests) {
+		t.Fatalf("expected %d clients, got %d", len(tests), len(report.Clients))
+	}
+	for i, tt := range tests {
+		t.Run(tt.client.String(), func(t *testing.T) {
+			c := report.Clients[i]
+			if c.Client != tt.client {
+				t.Fatalf("expected client %s, got %s", tt.client, c.Client)
+			}
+			if c.Severity() != tt.severity {
+				t.Errorf("expected severity %s, got %s", tt.severity, c.Severity())
+			}
+			var impacts []string
+			for _, impact := range c.Impacts {
+				s := []string{impact.Name, string(impact.Severity)}
+				if impact.Name == "" {
+					s[0] = impact.ID
+				}
+				if len(impact.Errors) > 0 {
+					s = append(s, string(rune('0'+len(impact.Errors))))
+				}
+				for _, change := range impact.Changes {
+					s = append(s, string(change.Kind))
+				}
+				impacts = append(impacts, strings.Join(s, " "))
+			}
+			if strings.Join(impacts, "\n") != strings.Join(tt.impacts, "\n") {
+				t.Errorf("expected impacts %q, got %q", tt.impacts, impacts)
+			}
+		})
+	}
+
+	if c := report.Client(web); c == nil || c.Operations != 2 {
+		t.Errorf("expected report of web with 2 operations, got %+v", c)
+	}
+
+	var text bytes.Buffer
+	if err := report.WriteText(&text); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `android@1.0.0: DANGEROUS (1 of 1 operations affected)
+  4: DANGEROUS
+    DANGEROUS enum value Role.GUEST added
+ios@4.2.0: BREAKING (1 of 1 operations affected)
+  Me: BREAKING
+    Cannot query field "email" on type "User".
+    BREAKING field User.email removed
+web@2.0.0: BREAKING (1 of 2 operations affected)
+  Search: BREAKING
+    Variable "$text" of type "String" used in position expecting type "String!".
+    BREAKING argument Query.search(text:) changed type from String to String!
+`
+	if text.String() != expected {
+		t.Errorf("expected text:\n%s\ngot:\n%s", expected, text.String())
+	}
+
+	var js bytes.Buffer
+	if err := report.WriteJSON(&js); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(js.String(), `{"clients":[{"name":"android","version":"1.0.0","severity":"DANGEROUS","operations":1,"impacts":[{"id":"4","severity":"DANGEROUS","changes":[{"kind":"ENUM_VALUE_ADDED","coordinate":"Role.GUEST"`) {
+		t.Errorf("unexpected JSON %s", js.String())
+	}
+}
+
+func TestCheck_Unaffected(t *testing.T) {
+	r := New()
+	c := Client{Name: "web", Version: "1"}
+	if err := r.Register(c, manifest(t, ManifestOperation{ID: "1", Name: "Me", Body: `query Me { me { id } }`})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := r.Check(asttest.Parse(t, oldSchema), asttest.Parse(t, newSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Breaking() || report.Clients[0].Severity() != astdiff.Safe || len(report.Clients[0].Impacts) != 0 {
+		t.Errorf("expected unaffected client, got %+v", report.Clients[0])
+	}
+
+	if !r.Unregister(c) || r.Unregister(c) {
+		t.Errorf("expected client to be unregistered once")
+	}
+	if len(r.Clients()) != 0 {
+		t.Errorf("expected no clients, got %v", r.Clients())
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{`{"format":"apollo-persisted-query-manifest","version":1,"operations":[{"id":"1","name":"A","type":"query","body":"query A { a }"}]}`, ""},
+		{`{"format":"other","version":1}`, `registry: unsupported manifest format "other"`},
+		{`{"format":"apollo-persisted-query-manifest","version":2}`, `registry: unsupported manifest version 2`},
+		{`[]`, `registry: invalid manifest: json: cannot unmarshal array into Go value of type registry.Manifest`},
+	}
+	for _, tt := range tests {
+		m, err := ParseManifest([]byte(tt.input))
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("unexpected error: %v", err)
+		case tt.err == "" && (len(m.Operations) != 1 || m.Operations[0].Body != "query A { a }"):
+			t.Errorf("unexpected manifest %+v", m)
+		case tt.err != "" && (err == nil || err.Error() != tt.err):
+			t.Errorf("expected error %q, got %v", tt.err, err)
+		}
+	}
+}
+
+func TestRegister_Errors(t *testing.T) {
+	tests := []struct {
+		op  ManifestOperation
+		err string
+	}{
+		{ManifestOperation{ID: "1", Name: "A", Body: "query A {"}, `registry: client web@1: operation "A": `},
+		{ManifestOperation{ID: "2", Name: "B", Body: "query A { a }"}, `registry: client web@1: operation "B": body has no operation "B"`},
+		{ManifestOperation{ID: "3", Body: "{ a } { b }"}, `registry: client web@1: operation "3": body has many operations`},
+	}
+	for _, tt := range tests {
+		err := New().Register(Client{Name: "web", Version: "1"}, manifest(t, tt.op))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("expected error %q, got %v", tt.err, err)
+		}
+	}
+}
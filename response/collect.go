@@ -0,0 +1,230 @@
+package response
+
+import (
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// collectedField is a response key with the fields merged under it.
+type collectedField struct {
+	Key        string
+	ParentType string // Type the first field is selected on.
+	Fields     []*ast.Field
+}
+
+// Name returns name of the selected field.
+func (f *collectedField) Name() string {
+	return f.Fields[0].Name.Value
+}
+
+// SelectionSets returns selection sets of merged fields.
+func (f *collectedField) SelectionSets() []*ast.SelectionSet {
+	var sets []*ast.SelectionSet
+	for _, field := range f.Fields {
+		if field.SelectionSet != nil {
+			sets = append(sets, field.SelectionSet)
+		}
+	}
+	return sets
+}
+
+// collector implements CollectFields over result trees. Conditional
+// directives are not evaluated: a field is known to be included when its
+// key is present in the result.
+//
+// https://spec.graphql.org/draft/#CollectFields()
+type collector struct {
+	schema    *schema.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+func newCollector(s *schema.Schema, doc *ast.Document) *collector {
+	c := &collector{schema: s, fragments: make(map[string]*ast.FragmentDefinition)}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			c.fragments[f.Name.Value] = f
+		}
+	}
+	return c
+}
+
+// collect groups fields of sets selected on object of type typeName by
+// response key, in selection order. When typeName is not a known object
+// type every fragment is assumed to apply.
+func (c *collector) collect(typeName string, sets []*ast.SelectionSet) []*collectedField {
+	var fields []*collectedField
+	byKey := make(map[string]*collectedField)
+	visited := make(map[string]bool)
+
+	var walk func(set *ast.SelectionSet, parent string)
+	walk = func(set *ast.SelectionSet, parent string) {
+		for _, sel := range set.Selections {
+			switch s := sel.(type) {
+			case *ast.Field:
+				key := s.Name.Value
+				if s.Alias != nil {
+					key = s.Alias.Value
+				}
+				if f, ok := byKey[key]; ok {
+					f.Fields = append(f.Fields, s)
+					continue
+				}
+				f := &collectedField{Key: key, ParentType: parent, Fields: []*ast.Field{s}}
+				byKey[key] = f
+				fields = append(fields, f)
+			case *ast.InlineFragment:
+				condition := parent
+				if s.TypeCondition != nil {
+					condition = s.TypeCondition.Name.Value
+				}
+				if c.applies(condition, typeName) {
+					walk(s.SelectionSet, condition)
+				}
+			case *ast.FragmentSpread:
+				frag, ok := c.fragments[s.Name.Value]
+				if !ok || visited[s.Name.Value] {
+					continue
+				}
+				visited[s.Name.Value] = true
+				condition := frag.TypeCondition.Name.Value
+				if c.applies(condition, typeName) {
+					walk(frag.SelectionSet, condition)
+				}
+			}
+		}
+	}
+	for _, set := range sets {
+		walk(set, typeName)
+	}
+	return fields
+}
+
+func (c *collector) applies(condition, typeName string) bool {
+	return !isObject(c.schema, typeName) || applies(c.schema, condition, typeName)
+}
+
+// runtimeType returns type of obj reported by __typename or typeName when
+// obj does not include it.
+func runtimeType(obj *Object, typeName string) string {
+	if v, ok := obj.Get("__typename"); ok {
+		if name, ok := v.(string); ok {
+			return name
+		}
+	}
+	return typeName
+}
+
+// defaultRoots are root type names of operation types assumed when schema
+// is nil or does not define them.
+var defaultRoots = map[ast.OperationType]string{
+	ast.OperationTypeQuery:        "Query",
+	ast.OperationTypeMutation:     "Mutation",
+	ast.OperationTypeSubscription: "Subscription",
+}
+
+// rootType returns name of root type of operation type op in s.
+func rootType(s *schema.Schema, op ast.OperationType) string {
+	if s != nil {
+		if t := s.RootType(op); t != nil {
+			return t.Name
+		}
+	}
+	return defaultRoots[op]
+}
+
+// namedSchemaType returns type name of s, or nil when s is nil or does not
+// define it.
+func namedSchemaType(s *schema.Schema, name string) *schema.Type {
+	if s == nil {
+		return nil
+	}
+	return s.Type(name)
+}
+
+// isObject reports whether typeName is an object type of s.
+func isObject(s *schema.Schema, typeName string) bool {
+	t := namedSchemaType(s, typeName)
+	return t != nil && t.Kind == schema.KindObject
+}
+
+// field returns field name of type typeName of s, or nil.
+func field(s *schema.Schema, typeName, name string) *schema.Field {
+	if t := namedSchemaType(s, typeName); t != nil {
+		return t.Field(name)
+	}
+	return nil
+}
+
+// inputValue returns argument or input field of s with coordinate c, or
+// nil.
+func inputValue(s *schema.Schema, c coordinate.Coordinate) *schema.InputValue {
+	switch {
+	case c.Directive:
+		if s == nil {
+			return nil
+		}
+		if d := s.Directive(c.Name); d != nil {
+			return d.Argument(c.Argument)
+		}
+	case c.Argument != "":
+		if f := field(s, c.Name, c.Member); f != nil {
+			return f.Argument(c.Argument)
+		}
+	default:
+		if t := namedSchemaType(s, c.Name); t != nil {
+			return t.InputField(c.Member)
+		}
+	}
+	return nil
+}
+
+// lookupField returns type collected field f is checked against in object of
+// type typeName and its field of s. It is typeName when it is a known
+// object type and type f is selected on otherwise.
+func lookupField(s *schema.Schema, typeName string, f *collectedField) (string, *schema.Field) {
+	parent := f.ParentType
+	if isObject(s, typeName) {
+		parent = typeName
+	}
+	return parent, field(s, parent, f.Name())
+}
+
+// applies reports whether fragment with type condition applies to value of
+// runtime type typeName.
+func applies(s *schema.Schema, condition, typeName string) bool {
+	if condition == typeName {
+		return true
+	}
+	if t := namedSchemaType(s, typeName); t != nil && slices.Contains(t.Interfaces, condition) {
+		return true
+	}
+	t := namedSchemaType(s, condition)
+	return t != nil && t.Kind == schema.KindUnion && slices.Contains(t.Types, typeName)
+}
+
+// unwrapNonNull returns type wrapped by t and whether t is non-null.
+func unwrapNonNull(t ast.Type) (ast.Type, bool) {
+	if nn, ok := t.(*ast.NonNullType); ok {
+		return nn.Type, true
+	}
+	return t, false
+}
+
+// namedType returns name of type t wraps.
+func namedType(t ast.Type) string {
+	for {
+		switch tt := t.(type) {
+		case *ast.NamedType:
+			return tt.Name.Value
+		case *ast.ListType:
+			t = tt.Type
+		case *ast.NonNullType:
+			t = tt.Type
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,583 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// IDCodec translates IDs between the internal form used by resolvers and
+// the opaque form exposed to clients, e.g. with hashids or authenticated
+// encryption, so that internal numeric IDs do not leak.
+type IDCodec interface {
+	Encode(id string) (string, error)
+	Decode(id string) (string, error)
+}
+
+// IDs encodes values of type ID in responses and decodes them in
+// arguments and variables of requests, so resolvers work with internal IDs
+// only.
+//
+// A value is translated by codec of the schema element it belongs to: a
+// field, an argument, an input field or a directive argument. Lists of IDs
+// are translated item by item. Custom scalars are not translated.
+//
+// IDs fails closed: values of fields, arguments and input fields not
+// defined in Schema, e.g. all of them when Schema is nil, may be IDs, so
+// Encode replaces them by null with an error and Decode fails.
+type IDs struct {
+	Schema *schema.Schema
+
+	// Codec returns codec of ID values of element with given coordinate,
+	// or nil to leave them as they are. Fields are looked up on their
+	// runtime type when result contains __typename and on the type they
+	// were selected on otherwise. No values are translated when Codec is
+	// nil.
+	Codec func(c coordinate.Coordinate) IDCodec
+}
+
+// IDCodecs returns Codec function choosing codec by coordinate of the
+// element or, when codecs has none for it, by name of the type defining
+// it, e.g. "User" for field User.id and input field UserInput.id when
+// codecs has "UserInput" too. Arguments are usually configured by their
+// coordinate, e.g. "Query.user(id:)". Other elements use fallback, which
+// may be nil.
+func IDCodecs(codecs map[string]IDCodec, fallback IDCodec) func(coordinate.Coordinate) IDCodec {
+	return func(c coordinate.Coordinate) IDCodec {
+		if codec, ok := codecs[c.String()]; ok {
+			return codec
+		}
+		if codec, ok := codecs[c.Name]; ok && !c.Directive && c.Argument == "" {
+			return codec
+		}
+		return fallback
+	}
+}
+
+// Encode encodes IDs in data of resp produced by executing op. Fragments of
+// op are looked up in doc. A value that fails to encode is replaced by null
+// and an error is added at its path, as is a value of field not defined
+// in Schema; nulls are then propagated as by Propagate.
+func (ids *IDs) Encode(resp *Response, doc *ast.Document, op *ast.OperationDefinition) {
+	collector := newCollector(ids.Schema, doc)
+	e := &idEncoder{IDs: ids, collector: collector, resp: resp}
+	e.value(resp.Data, rootType(ids.Schema, op.OperationType), []*ast.SelectionSet{op.SelectionSet}, nil)
+	if e.failed {
+		p := &propagator{schema: ids.Schema, collector: collector, resp: resp}
+		p.propagate(op)
+	}
+}
+
+type idEncoder struct {
+	*IDs
+	collector *collector
+	resp      *Response
+	failed    bool
+}
+
+func (e *idEncoder) value(v any, typeName string, sets []*ast.SelectionSet, path []any) {
+	switch v := v.(type) {
+	case []any:
+		for i, item := range v {
+			e.value(item, typeName, sets, appendPath(path, i))
+		}
+	case *Object:
+		e.object(v, typeName, sets, path)
+	}
+}
+
+func (e *idEncoder) object(obj *Object, typeName string, sets []*ast.SelectionSet, path []any) {
+	typeName = runtimeType(obj, typeName)
+	for _, f := range e.collector.collect(typeName, sets) {
+		v, ok := obj.Get(f.Key)
+		if !ok || f.Name() == "__typename" {
+			continue
+		}
+		parent, def := lookupField(e.Schema, typeName, f)
+		fieldPath := appendPath(path, f.Key)
+		if def == nil {
+			if v != nil {
+				e.error(fmt.Sprintf("Cannot encode IDs of %s: not defined in schema", coordinate.Member(parent, f.Name())), fieldPath)
+				obj.Set(f.Key, nil)
+			}
+			continue
+		}
+		if name := namedType(def.Type); name != "ID" {
+			e.value(v, name, f.SelectionSets(), fieldPath)
+		} else if codec := e.codec(coordinate.Member(parent, f.Name())); codec != nil {
+			obj.Set(f.Key, e.encode(v, codec, fieldPath))
+		}
+	}
+}
+
+// encode returns ID or list of IDs v encoded by codec.
+func (e *idEncoder) encode(v any, codec IDCodec, path []any) any {
+	var id string
+	switch v := v.(type) {
+	case []any:
+		for i, item := range v {
+			v[i] = e.encode(item, codec, appendPath(path, i))
+		}
+		return v
+	case string:
+		id = v
+	case json.Number:
+		id = v.String()
+	default:
+		return v
+	}
+	encoded, err := codec.Encode(id)
+	if err != nil {
+		e.error("Cannot encode ID: "+err.Error(), path)
+		return nil
+	}
+	return encoded
+}
+
+func (e *idEncoder) error(msg string, path []any) {
+	e.failed = true
+	e.resp.Errors = append(e.resp.Errors, &Error{Message: msg, Path: path})
+}
+
+// codec returns codec of ID values of element with coordinate c, or nil.
+func (ids *IDs) codec(c coordinate.Coordinate) IDCodec {
+	if ids.Codec == nil {
+		return nil
+	}
+	return ids.Codec(c)
+}
+
+// Decode decodes IDs in arguments of op and of fragments it uses, and in
+// variables passed to them. It returns document with decoded literals and
+// decoded copy of variables; doc and variables are not modified, and the
+// returned document shares unchanged nodes with doc. Default values of
+// variables which are not provided are decoded in their definitions.
+//
+// Decoding fails when a codec rejects an ID, when a variable used in
+// several arguments decodes differently for them, or when an argument,
+// input field or field with selections is not defined in Schema.
+func (ids *IDs) Decode(doc *ast.Document, op *ast.OperationDefinition, variables map[string]any) (*ast.Document, map[string]any, error) {
+	d := &idDecoder{
+		IDs:       ids,
+		fragments: newCollector(ids.Schema, doc).fragments,
+		decoded:   make(map[string]*ast.FragmentDefinition),
+		vars:      make(map[string]*ast.VariableDefinition),
+		input:     variables,
+		output:    make(map[string]any),
+		defaults:  make(map[string]ast.Value),
+		used:      make(map[string]coordinate.Coordinate),
+	}
+	for _, def := range op.VariableDefs {
+		d.vars[def.Variable.Name.Value] = def
+	}
+
+	decodedOp, err := d.operation(op)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &ast.Document{Definitions: slices.Clone(doc.Definitions)}
+	for i, def := range result.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if def == op {
+				result.Definitions[i] = decodedOp
+			}
+		case *ast.FragmentDefinition:
+			if f, ok := d.decoded[def.Name.Value]; ok && f != nil {
+				result.Definitions[i] = f
+			}
+		}
+	}
+	decodedVariables := maps.Clone(variables)
+	maps.Copy(decodedVariables, d.output)
+	return result, decodedVariables, nil
+}
+
+type idDecoder struct {
+	*IDs
+	fragments map[string]*ast.FragmentDefinition
+	decoded   map[string]*ast.FragmentDefinition // Walked fragments, nil when unchanged.
+	vars      map[string]*ast.VariableDefinition
+	input     map[string]any
+	output    map[string]any                   // Decoded variables.
+	defaults  map[string]ast.Value             // Decoded default values.
+	used      map[string]coordinate.Coordinate // First ID position of variables.
+}
+
+func (d *idDecoder) operation(op *ast.OperationDefinition) (*ast.OperationDefinition, error) {
+	directives, err := d.directives(op.Directives)
+	if err != nil {
+		return nil, err
+	}
+	set, err := d.selectionSet(op.SelectionSet, rootType(d.Schema, op.OperationType))
+	if err != nil {
+		return nil, err
+	}
+	defs, _ := rewrite(op.VariableDefs, func(def *ast.VariableDefinition) (*ast.VariableDefinition, error) {
+		v, ok := d.defaults[def.Variable.Name.Value]
+		if !ok || v == def.DefaultValue {
+			return def, nil
+		}
+		c := *def
+		c.DefaultValue = v
+		return &c, nil
+	})
+	if slices.Equal(directives, op.Directives) && set == op.SelectionSet && slices.Equal(defs, op.VariableDefs) {
+		return op, nil
+	}
+	c := *op
+	c.Directives, c.SelectionSet, c.VariableDefs = directives, set, defs
+	return &c, nil
+}
+
+func (d *idDecoder) selectionSet(set *ast.SelectionSet, parent string) (*ast.SelectionSet, error) {
+	selections, err := rewrite(set.Selections, func(sel ast.Selection) (ast.Selection, error) {
+		return d.selection(sel, parent)
+	})
+	if err != nil || slices.Equal(selections, set.Selections) {
+		return set, err
+	}
+	c := *set
+	c.Selections = selections
+	return &c, nil
+}
+
+func (d *idDecoder) selection(sel ast.Selection, parent string) (ast.Selection, error) {
+	switch s := sel.(type) {
+	case *ast.Field:
+		name := s.Name.Value
+		args, err := rewrite(s.Arguments, func(arg *ast.Argument) (*ast.Argument, error) {
+			return d.argument(arg, coordinate.Argument(parent, name, arg.Name.Value))
+		})
+		if err != nil {
+			return nil, err
+		}
+		directives, err := d.directives(s.Directives)
+		if err != nil {
+			return nil, err
+		}
+		set := s.SelectionSet
+		if set != nil {
+			def := field(d.Schema, parent, name)
+			if def == nil {
+				return nil, undefined(coordinate.Member(parent, name))
+			}
+			if set, err = d.selectionSet(set, namedType(def.Type)); err != nil {
+				return nil, err
+			}
+		}
+		if slices.Equal(args, s.Arguments) && slices.Equal(directives, s.Directives) && set == s.SelectionSet {
+			return s, nil
+		}
+		c := *s
+		c.Arguments, c.Directives, c.SelectionSet = args, directives, set
+		return &c, nil
+	case *ast.InlineFragment:
+		condition := parent
+		if s.TypeCondition != nil {
+			condition = s.TypeCondition.Name.Value
+		}
+		directives, err := d.directives(s.Directives)
+		if err != nil {
+			return nil, err
+		}
+		set, err := d.selectionSet(s.SelectionSet, condition)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Equal(directives, s.Directives) && set == s.SelectionSet {
+			return s, nil
+		}
+		c := *s
+		c.Directives, c.SelectionSet = directives, set
+		return &c, nil
+	case *ast.FragmentSpread:
+		if err := d.fragment(s.Name.Value); err != nil {
+			return nil, err
+		}
+		directives, err := d.directives(s.Directives)
+		if err != nil || slices.Equal(directives, s.Directives) {
+			return s, err
+		}
+		c := *s
+		c.Directives = directives
+		return &c, nil
+	}
+	return sel, nil
+}
+
+// fragment decodes fragment name once, however many times it is spread.
+// Fields of a fragment are selected on its type condition, so they decode
+// the same for every spread.
+func (d *idDecoder) fragment(name string) error {
+	frag, ok := d.fragments[name]
+	if _, done := d.decoded[name]; done || !ok {
+		return nil
+	}
+	d.decoded[name] = nil
+	directives, err := d.directives(frag.Directives)
+	if err != nil {
+		return err
+	}
+	set, err := d.selectionSet(frag.SelectionSet, frag.TypeCondition.Name.Value)
+	if err != nil {
+		return err
+	}
+	if slices.Equal(directives, frag.Directives) && set == frag.SelectionSet {
+		return nil
+	}
+	c := *frag
+	c.Directives, c.SelectionSet = directives, set
+	d.decoded[name] = &c
+	return nil
+}
+
+func (d *idDecoder) directives(directives []*ast.Directive) ([]*ast.Directive, error) {
+	return rewrite(directives, func(dir *ast.Directive) (*ast.Directive, error) {
+		args, err := rewrite(dir.Arguments, func(arg *ast.Argument) (*ast.Argument, error) {
+			return d.argument(arg, coordinate.DirectiveArgument(dir.Name.Value, arg.Name.Value))
+		})
+		if err != nil || slices.Equal(args, dir.Arguments) {
+			return dir, err
+		}
+		c := *dir
+		c.Arguments = args
+		return &c, nil
+	})
+}
+
+// argument decodes argument with coordinate c.
+func (d *idDecoder) argument(arg *ast.Argument, c coordinate.Coordinate) (*ast.Argument, error) {
+	def := inputValue(d.Schema, c)
+	if def == nil {
+		if c.Directive && specifiedDirectives[c.Name] {
+			// Arguments of specified directives are not IDs.
+			return arg, nil
+		}
+		return nil, undefined(c)
+	}
+	v, err := d.literal(arg.Value, def.Type, c)
+	if err != nil || v == arg.Value {
+		return arg, err
+	}
+	copied := *arg
+	copied.Value = v
+	return &copied, nil
+}
+
+// literal decodes IDs in value v of type t at element with coordinate c.
+func (d *idDecoder) literal(v ast.Value, t ast.Type, c coordinate.Coordinate) (ast.Value, error) {
+	t, _ = unwrapNonNull(t)
+	if variable, ok := v.(*ast.Variable); ok {
+		return v, d.variable(variable.Name.Value, t, c)
+	}
+	if list, ok := t.(*ast.ListType); ok {
+		values, ok := v.(*ast.ListValue)
+		if !ok {
+			// Single value is coerced to a list of one item.
+			return d.literal(v, list.Type, c)
+		}
+		items, err := rewrite(values.Values, func(item ast.Value) (ast.Value, error) {
+			return d.literal(item, list.Type, c)
+		})
+		if err != nil || slices.Equal(items, values.Values) {
+			return v, err
+		}
+		copied := *values
+		copied.Values = items
+		return &copied, nil
+	}
+
+	name := namedType(t)
+	switch v := v.(type) {
+	case *ast.StringValue:
+		return d.id(v, v.Value, name, c)
+	case *ast.IntValue:
+		return d.id(v, v.Value, name, c)
+	case *ast.ObjectValue:
+		fields, err := rewrite(v.Fields, func(f *ast.ObjectField) (*ast.ObjectField, error) {
+			fc := coordinate.Member(name, f.Name.Value)
+			def := inputValue(d.Schema, fc)
+			if def == nil {
+				return nil, undefined(fc)
+			}
+			value, err := d.literal(f.Value, def.Type, fc)
+			if err != nil || value == f.Value {
+				return f, err
+			}
+			copied := *f
+			copied.Value = value
+			return &copied, nil
+		})
+		if err != nil || slices.Equal(fields, v.Fields) {
+			return v, err
+		}
+		copied := *v
+		copied.Fields = fields
+		return &copied, nil
+	}
+	return v, nil
+}
+
+// id decodes literal v holding id when it is of named type name.
+func (d *idDecoder) id(v ast.Value, id, name string, c coordinate.Coordinate) (ast.Value, error) {
+	codec := d.codec(name, c)
+	if codec == nil {
+		return v, nil
+	}
+	decoded, err := d.decode(codec, id, c)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.StringValue{Position: v.Pos(), EndPosition: v.End(), Value: decoded}, nil
+}
+
+// variable decodes value of variable name used at position of type t of
+// element with coordinate c, or its default value when it is not
+// provided.
+func (d *idDecoder) variable(name string, t ast.Type, c coordinate.Coordinate) error {
+	def := d.vars[name]
+	if def == nil {
+		return nil
+	}
+	first, seen := d.used[name]
+	same := true
+	if value, ok := d.input[name]; ok {
+		decoded, err := d.value(value, t, c)
+		if err != nil {
+			return err
+		}
+		same = !seen || reflect.DeepEqual(d.output[name], decoded)
+		d.output[name] = decoded
+	} else if def.DefaultValue != nil {
+		decoded, err := d.literal(def.DefaultValue, t, c)
+		if err != nil {
+			return err
+		}
+		same = !seen || ast.Equal(d.defaults[name], decoded)
+		d.defaults[name] = decoded
+	}
+	if !same {
+		return fmt.Errorf("variable \"$%s\" decodes differently for %s and %s", name, first, c)
+	}
+	if !seen {
+		d.used[name] = c
+	}
+	return nil
+}
+
+// value decodes IDs in variable value v of type t at element with
+// coordinate c. Input objects are copied.
+func (d *idDecoder) value(v any, t ast.Type, c coordinate.Coordinate) (any, error) {
+	t, _ = unwrapNonNull(t)
+	if v == nil {
+		return nil, nil
+	}
+	if list, ok := t.(*ast.ListType); ok {
+		items, ok := v.([]any)
+		if !ok {
+			return d.value(v, list.Type, c)
+		}
+		decoded := make([]any, len(items))
+		for i, item := range items {
+			var err error
+			if decoded[i], err = d.value(item, list.Type, c); err != nil {
+				return nil, err
+			}
+		}
+		return decoded, nil
+	}
+
+	name := namedType(t)
+	if obj, ok := v.(map[string]any); ok {
+		decoded := make(map[string]any, len(obj))
+		for key, value := range obj {
+			fc := coordinate.Member(name, key)
+			def := inputValue(d.Schema, fc)
+			if def == nil {
+				return nil, undefined(fc)
+			}
+			var err error
+			if decoded[key], err = d.value(value, def.Type, fc); err != nil {
+				return nil, err
+			}
+		}
+		return decoded, nil
+	}
+	codec := d.codec(name, c)
+	if codec == nil {
+		return v, nil
+	}
+	var id string
+	switch v := v.(type) {
+	case string:
+		id = v
+	case json.Number:
+		id = v.String()
+	case float64:
+		id = strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		id = strconv.Itoa(v)
+	case int64:
+		id = strconv.FormatInt(v, 10)
+	default:
+		return v, nil
+	}
+	return d.decode(codec, id, c)
+}
+
+// codec returns codec of values of named type name at element with
+// coordinate c, or nil when they are not IDs.
+func (d *idDecoder) codec(name string, c coordinate.Coordinate) IDCodec {
+	if name != "ID" {
+		return nil
+	}
+	return d.IDs.codec(c)
+}
+
+func (d *idDecoder) decode(codec IDCodec, id string, c coordinate.Coordinate) (string, error) {
+	decoded, err := codec.Decode(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid ID %q for %s: %w", id, c, err)
+	}
+	return decoded, nil
+}
+
+// specifiedDirectives are executable directives of the specification,
+// which schemas need not define.
+var specifiedDirectives = map[string]bool{"skip": true, "include": true}
+
+// undefined returns error of element with coordinate c not defined in
+// schema.
+func undefined(c coordinate.Coordinate) error {
+	return fmt.Errorf("cannot decode IDs of %s: not defined in schema", c)
+}
+
+// rewrite returns s with every element replaced by fn, or s itself when fn
+// returns all elements unchanged.
+func rewrite[T comparable](s []T, fn func(T) (T, error)) ([]T, error) {
+	var result []T
+	for i, v := range s {
+		r, err := fn(v)
+		if err != nil {
+			return nil, err
+		}
+		if r != v && result == nil {
+			result = slices.Clone(s)
+		}
+		if result != nil {
+			result[i] = r
+		}
+	}
+	if result == nil {
+		return s, nil
+	}
+	return result, nil
+}
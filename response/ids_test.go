@@ -0,0 +1,299 @@
+package response
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// prefixCodec encodes IDs by prefixing them.
+type prefixCodec string
+
+func (p prefixCodec) Encode(id string) (string, error) {
+	if id == "" {
+		return "", errors.New("empty ID")
+	}
+	return string(p) + id, nil
+}
+
+func (p prefixCodec) Decode(id string) (string, error) {
+	decoded, ok := strings.CutPrefix(id, string(p))
+	if !ok {
+		return "", errors.New("unknown prefix")
+	}
+	return decoded, nil
+}
+
+const idsSchema = `
+type Query { node(id: ID!): Node users(ids: [ID!], filter: UserFilter): [User!] }
+interface Node { id: ID! }
+type User implements Node { id: ID! friendIds: [ID] name: String }
+type Post implements Node { id: ID! author: User! }
+input UserFilter { ids: [ID!] name: String }
+extend type User { bestFriendId: ID }
+directive @ref(id: ID) on FIELD
+`
+
+func testIDs(t *testing.T) *IDs {
+	return &IDs{
+		Schema: asttest.Schema(t, idsSchema),
+		Codec: IDCodecs(map[string]IDCodec{
+			"User":              prefixCodec("u"),
+			"Post":              prefixCodec("p"),
+			"UserFilter":        prefixCodec("u"),
+			"Query.users(ids:)": prefixCodec("u"),
+			"@ref(id:)":         prefixCodec(""),
+		}, prefixCodec("n")),
+	}
+}
+
+func TestIDCodecs(t *testing.T) {
+	codec := IDCodecs(map[string]IDCodec{
+		"User":            prefixCodec("u"),
+		"Query.user(id:)": prefixCodec("q"),
+	}, nil)
+	tests := []struct {
+		coord    coordinate.Coordinate
+		expected IDCodec
+	}{
+		{coordinate.Member("User", "id"), prefixCodec("u")},
+		{coordinate.Argument("Query", "user", "id"), prefixCodec("q")},
+		{coordinate.Argument("User", "friend", "id"), nil},
+		{coordinate.Member("Post", "id"), nil},
+		{coordinate.DirectiveArgument("User", "id"), nil},
+	}
+	for _, tt := range tests {
+		if got := codec(tt.coord); got != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.coord, tt.expected, got)
+		}
+	}
+}
+
+func TestIDs_Encode(t *testing.T) {
+	ids := testIDs(t)
+	tests := []struct {
+		name     string
+		query    string
+		data     string
+		expected string
+	}{
+		{
+			name:     "runtime types",
+			query:    `{ node(id: "1") { __typename id ... on Post { author { id } } } }`,
+			data:     `{"node":{"__typename":"Post","id":"1","author":{"id":2}}}`,
+			expected: `{"data":{"node":{"__typename":"Post","id":"p1","author":{"id":"u2"}}}}`,
+		},
+		{
+			name:     "interface type",
+			query:    `{ node(id: "1") { id } }`,
+			data:     `{"node":{"id":"1"}}`,
+			expected: `{"data":{"node":{"id":"n1"}}}`,
+		},
+		{
+			name:     "lists and aliases",
+			query:    `{ users { key: id friendIds ...F } } fragment F on User { name }`,
+			data:     `{"users":[{"key":"1","friendIds":["2",null],"name":"1"}]}`,
+			expected: `{"data":{"users":[{"key":"u1","friendIds":["u2",null],"name":"1"}]}}`,
+		},
+		{
+			name:     "extension field",
+			query:    `{ users { bestFriendId } }`,
+			data:     `{"users":[{"bestFriendId":"2"}]}`,
+			expected: `{"data":{"users":[{"bestFriendId":"u2"}]}}`,
+		},
+		{
+			name:     "error propagates",
+			query:    `{ users { id name } }`,
+			data:     `{"users":[{"id":"","name":"A"}]}`,
+			expected: `{"data":{"users":null},"errors":[{"message":"Cannot encode ID: empty ID","path":["users",0,"id"]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			resp := decode(t, `{"data":`+tt.data+`}`)
+			ids.Encode(resp, doc, doc.Definitions[0].(*ast.OperationDefinition))
+			out, err := resp.Encode()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestIDs_Decode(t *testing.T) {
+	ids := testIDs(t)
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expected  string
+		vars      string
+	}{
+		{
+			name:     "literals",
+			query:    `{ node(id: "n7") @ref(id: 7) { id } users(ids: "u1", filter: {ids: ["u2", "u3"], name: "u"}) { id } }`,
+			expected: `{node(id:"7")@ref(id:"7"){id}users(ids:"1"filter:{ids:["2" "3"]name:"u"}){id}}`,
+		},
+		{
+			name:      "variables",
+			query:     `query($id: ID!, $f: UserFilter, $ids: [ID!]) { node(id: $id) { id } users(ids: $ids, filter: $f) { id } }`,
+			variables: map[string]any{"id": "n1", "f": map[string]any{"ids": []any{"u2"}, "name": "u"}, "ids": "u3"},
+			expected:  `query($id:ID!$f:UserFilter$ids:[ID!]){node(id:$id){id}users(ids:$ids filter:$f){id}}`,
+			vars:      `{"f":{"ids":["2"],"name":"u"},"id":"1","ids":"3"}`,
+		},
+		{
+			name:     "default values",
+			query:    `query($id: ID! = "n1", $ids: [ID!] = ["u2"]) { node(id: $id) { id } users(ids: $ids) { id } }`,
+			expected: `query($id:ID!="1"$ids:[ID!]=["2"]){node(id:$id){id}users(ids:$ids){id}}`,
+		},
+		{
+			name:     "specified directives",
+			query:    `query($skip: Boolean!) { node(id: "n1") @skip(if: $skip) { id @include(if: true) } }`,
+			expected: `query($skip:Boolean!){node(id:"1")@skip(if:$skip){id@include(if:true)}}`,
+		},
+		{
+			name:     "fragments and directives",
+			query:    `{ ...F ...F } fragment F on Query { node(id: "n1") @ref(id: "n2") { ... on User { id } } } fragment G on Query { node(id: "n3") { id } }`,
+			expected: `{...F...F}fragment F on Query{node(id:"1")@ref(id:"n2"){...on User{id}}}fragment G on Query{node(id:"n3"){id}}`,
+		},
+		{
+			name:      "same decoding",
+			query:     `query($id: ID!) { a: node(id: $id) { id } b: node(id: $id) { id } }`,
+			variables: map[string]any{"id": "n1"},
+			expected:  `query($id:ID!){a:node(id:$id){id}b:node(id:$id){id}}`,
+			vars:      `{"id":"1"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			source := printer.PrintCompact(doc)
+			decoded, vars, err := ids.Decode(doc, doc.Definitions[0].(*ast.OperationDefinition), tt.variables)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := printer.PrintCompact(decoded); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+			if got := printer.PrintCompact(doc); got != source {
+				t.Errorf("expected document unchanged, got %s", got)
+			}
+			if tt.vars != "" {
+				assertJSON(t, vars, tt.vars)
+			}
+		})
+	}
+}
+
+func TestIDs_DecodeErrors(t *testing.T) {
+	ids := testIDs(t)
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expected  string
+	}{
+		{
+			name:     "literal",
+			query:    `{ node(id: "x1") { id } }`,
+			expected: `invalid ID "x1" for Query.node(id:): unknown prefix`,
+		},
+		{
+			name:      "variable",
+			query:     `query($f: UserFilter) { users(filter: $f) { id } }`,
+			variables: map[string]any{"f": map[string]any{"ids": []any{"u1", 2}}},
+			expected:  `invalid ID "2" for UserFilter.ids: unknown prefix`,
+		},
+		{
+			name:      "different decoding",
+			query:     `query($id: ID!) { node(id: $id) { id } users(ids: [$id]) { id } }`,
+			variables: map[string]any{"id": "n1"},
+			expected:  `invalid ID "n1" for Query.users(ids:): unknown prefix`,
+		},
+		{
+			name:      "variable decodes differently",
+			query:     `query($id: ID!) { node(id: $id) @ref(id: $id) { id } }`,
+			variables: map[string]any{"id": "n1"},
+			expected:  `variable "$id" decodes differently for Query.node(id:) and @ref(id:)`,
+		},
+		{
+			name:     "undefined argument",
+			query:    `{ node(id: "n1", ref: "n2") { id } }`,
+			expected: `cannot decode IDs of Query.node(ref:): not defined in schema`,
+		},
+		{
+			name:     "undefined input field",
+			query:    `{ users(filter: {ids: ["u1"], ref: "u2"}) { id } }`,
+			expected: `cannot decode IDs of UserFilter.ref: not defined in schema`,
+		},
+		{
+			name:      "undefined input field in variable",
+			query:     `query($f: UserFilter) { users(filter: $f) { id } }`,
+			variables: map[string]any{"f": map[string]any{"ref": "u1"}},
+			expected:  `cannot decode IDs of UserFilter.ref: not defined in schema`,
+		},
+		{
+			name:     "undefined field",
+			query:    `{ viewer { node(id: "n1") { id } } }`,
+			expected: `cannot decode IDs of Query.viewer: not defined in schema`,
+		},
+		{
+			name:     "undefined directive",
+			query:    `{ node(id: "n1") @key(id: "n2") { id } }`,
+			expected: `cannot decode IDs of @key(id:): not defined in schema`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			_, _, err := ids.Decode(doc, doc.Definitions[0].(*ast.OperationDefinition), tt.variables)
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tt.expected)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %q", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestIDs_FailsClosed(t *testing.T) {
+	doc := asttest.Parse(t, `{ node(id: "n1") { __typename id } }`)
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+
+	nilSchema := &IDs{Codec: testIDs(t).Codec}
+	resp := decode(t, `{"data":{"node":{"__typename":"User","id":"1"}}}`)
+	nilSchema.Encode(resp, doc, op)
+	assertJSON(t, resp, `{"data":{"node":null},"errors":[{"message":"Cannot encode IDs of Query.node: not defined in schema","path":["node"]}]}`)
+	if _, _, err := nilSchema.Decode(doc, op, nil); err == nil || err.Error() != `cannot decode IDs of Query.node(id:): not defined in schema` {
+		t.Errorf("expected error for nil schema, got %v", err)
+	}
+
+	ids := testIDs(t)
+	doc = asttest.Parse(t, `{ node(id: "n1") { id ... on User { legacyId } } }`)
+	op = doc.Definitions[0].(*ast.OperationDefinition)
+	resp = decode(t, `{"data":{"node":{"__typename":"User","id":"1","legacyId":"2"}}}`)
+	ids.Encode(resp, doc, op)
+	assertJSON(t, resp, `{"data":{"node":{"__typename":"User","id":"u1","legacyId":null}},"errors":[{"message":"Cannot encode IDs of User.legacyId: not defined in schema","path":["node","legacyId"]}]}`)
+
+	nilCodec := &IDs{Schema: ids.Schema}
+	resp = decode(t, `{"data":{"node":{"id":"1"}}}`)
+	nilCodec.Encode(resp, doc, op)
+	assertJSON(t, resp, `{"data":{"node":{"id":"1"}}}`)
+	decoded, _, err := nilCodec.Decode(doc, op, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Definitions[0] != op {
+		t.Errorf("expected operation unchanged without codecs")
+	}
+}
@@ -0,0 +1,128 @@
+package response
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// DefaultMaskMessage is message of errors reported for masked fields.
+const DefaultMaskMessage = "Not authorized to access this field"
+
+// Masker hides fields of a response that are not part of an allowed field
+// set, e.g. one derived from roles of a user.
+//
+// By default a disallowed field is replaced by null and an error is added
+// at its path. Null of a non-null field propagates to the closest nullable
+// parent, so masked response stays valid for the operation and schema.
+//
+// Masker fails closed: fields not defined in Schema, e.g. all fields when
+// Schema is nil, are masked as disallowed, since their selections cannot
+// be checked.
+type Masker struct {
+	Schema *schema.Schema
+
+	// Allow reports whether field with given coordinate may be returned.
+	// Fields are checked on their runtime type when result contains
+	// __typename and on the type they were selected on otherwise.
+	// __typename is always allowed. All other fields are disallowed when
+	// Allow is nil.
+	Allow func(field coordinate.Coordinate) bool
+
+	// Omit removes disallowed fields from objects without reporting errors
+	// instead of nulling them. Such responses no longer match the
+	// operation shape.
+	Omit bool
+
+	// Message of errors added for masked fields. DefaultMaskMessage is
+	// used when empty.
+	Message string
+}
+
+// AllowFields returns Allow function permitting listed fields. A type
+// coordinate permits all fields of the type.
+func AllowFields(coords ...coordinate.Coordinate) func(coordinate.Coordinate) bool {
+	set := make(map[coordinate.Coordinate]bool, len(coords))
+	for _, c := range coords {
+		set[c] = true
+	}
+	return func(c coordinate.Coordinate) bool {
+		return set[c] || set[coordinate.Type(c.Name)]
+	}
+}
+
+// Mask masks data of resp produced by executing op. Fragments of op are
+// looked up in doc. Keys of result objects not selected by op are removed.
+// Unless disallowed fields are omitted, nulls are propagated afterwards
+// as by Propagate.
+func (m *Masker) Mask(resp *Response, doc *ast.Document, op *ast.OperationDefinition) {
+	collector := newCollector(m.Schema, doc)
+	w := &masker{Masker: m, collector: collector, resp: resp}
+	w.value(resp.Data, rootType(m.Schema, op.OperationType), []*ast.SelectionSet{op.SelectionSet}, nil)
+	if !m.Omit {
+		p := &propagator{schema: m.Schema, collector: collector, resp: resp}
+		p.propagate(op)
+	}
+}
+
+type masker struct {
+	*Masker
+	collector *collector
+	resp      *Response
+}
+
+func (m *masker) value(v any, typeName string, sets []*ast.SelectionSet, path []any) {
+	switch v := v.(type) {
+	case []any:
+		for i, item := range v {
+			m.value(item, typeName, sets, appendPath(path, i))
+		}
+	case *Object:
+		m.object(v, typeName, sets, path)
+	}
+}
+
+func (m *masker) object(obj *Object, typeName string, sets []*ast.SelectionSet, path []any) {
+	typeName = runtimeType(obj, typeName)
+	fields := m.collector.collect(typeName, sets)
+
+	selected := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		selected[f.Key] = true
+	}
+	for _, key := range obj.Keys() {
+		if !selected[key] {
+			obj.Delete(key)
+		}
+	}
+
+	for _, f := range fields {
+		v, ok := obj.Get(f.Key)
+		if !ok || f.Name() == "__typename" {
+			continue
+		}
+		parent, def := lookupField(m.Schema, typeName, f)
+		fieldPath := appendPath(path, f.Key)
+
+		if def == nil || m.Allow == nil || !m.Allow(coordinate.Member(parent, f.Name())) {
+			if m.Omit {
+				obj.Delete(f.Key)
+				continue
+			}
+			if v != nil {
+				m.error(fieldPath)
+			}
+			obj.Set(f.Key, nil)
+			continue
+		}
+		m.value(v, namedType(def.Type), f.SelectionSets(), fieldPath)
+	}
+}
+
+func (m *masker) error(path []any) {
+	msg := m.Message
+	if msg == "" {
+		msg = DefaultMaskMessage
+	}
+	m.resp.Errors = append(m.resp.Errors, &Error{Message: msg, Path: path})
+}
@@ -0,0 +1,202 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+)
+
+func decode(t *testing.T, input string) *Response {
+	t.Helper()
+	resp, err := Decode([]byte(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+// assertJSON compares v to expected JSON regardless of key order.
+func assertJSON(t *testing.T, v any, expected string) {
+	t.Helper()
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var gotValue, expectedValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Fatalf("invalid expected JSON: %v", err)
+	}
+	if !reflect.DeepEqual(gotValue, expectedValue) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+const testSchema = `
+type Query { me: User! users: [User!] node(id: ID!): Node }
+interface Node { id: ID! }
+type User implements Node { id: ID! name: String email: String ssn: String! }
+type Admin implements Node { id: ID! secret: String }
+`
+
+func TestMasker_Mask(t *testing.T) {
+	schema := asttest.Schema(t, testSchema)
+	allow := AllowFields(
+		coordinate.Type("Query"),
+		coordinate.Member("User", "id"),
+		coordinate.Member("User", "name"),
+		coordinate.Member("Admin", "id"),
+	)
+
+	tests := []struct {
+		name     string
+		query    string
+		data     string
+		omit     bool
+		expected string
+		errors   [][]any
+	}{
+		{
+			name:     "nullable field",
+			query:    `{ me { id email } }`,
+			data:     `{"me": {"id": "1", "email": "a@b.c"}}`,
+			expected: `{"me": {"id": "1", "email": null}}`,
+			errors:   [][]any{{"me", "email"}},
+		},
+		{
+			name:     "non-null field propagates",
+			query:    `{ users { id ssn } }`,
+			data:     `{"users": [{"id": "1", "ssn": "x"}]}`,
+			expected: `{"users": null}`,
+			errors:   [][]any{{"users", 0, "ssn"}},
+		},
+		{
+			name:     "propagates to data",
+			query:    `{ me { ssn } }`,
+			data:     `{"me": {"ssn": "x"}}`,
+			expected: `null`,
+			errors:   [][]any{{"me", "ssn"}},
+		},
+		{
+			name:     "runtime type",
+			query:    `{ node(id: "1") { __typename id ... on Admin { secret } ... on User { n: name } } }`,
+			data:     `{"node": {"__typename": "Admin", "id": "1", "secret": "s"}}`,
+			expected: `{"node": {"__typename": "Admin", "id": "1", "secret": null}}`,
+			errors:   [][]any{{"node", "secret"}},
+		},
+		{
+			name:     "fragment spread and alias",
+			query:    `query { me { ...F } } fragment F on User { mail: email name }`,
+			data:     `{"me": {"mail": "a@b.c", "name": "A", "extra": 1}}`,
+			expected: `{"me": {"mail": null, "name": "A"}}`,
+			errors:   [][]any{{"me", "mail"}},
+		},
+		{
+			name:     "null is not reported",
+			query:    `{ me { email } }`,
+			data:     `{"me": {"email": null}}`,
+			expected: `{"me": {"email": null}}`,
+		},
+		{
+			name:     "omit",
+			query:    `{ me { id email ssn } }`,
+			data:     `{"me": {"id": "1", "email": "a@b.c", "ssn": "x"}}`,
+			omit:     true,
+			expected: `{"me": {"id": "1"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			var op *ast.OperationDefinition
+			for _, def := range doc.Definitions {
+				if o, ok := def.(*ast.OperationDefinition); ok {
+					op = o
+				}
+			}
+			resp := decode(t, `{"data": `+tt.data+`}`)
+			m := &Masker{Schema: schema, Allow: allow, Omit: tt.omit}
+			m.Mask(resp, doc, op)
+
+			assertJSON(t, resp.Data, tt.expected)
+			var paths [][]any
+			for _, e := range resp.Errors {
+				if e.Message != DefaultMaskMessage {
+					t.Errorf("unexpected message %q", e.Message)
+				}
+				paths = append(paths, e.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.errors) {
+				t.Errorf("expected error paths %v, got %v", tt.errors, paths)
+			}
+		})
+	}
+}
+
+func TestMasker_Mask_FailsClosed(t *testing.T) {
+	schema := asttest.Schema(t, testSchema)
+	allow := AllowFields(coordinate.Member("Query", "me"), coordinate.Member("User", "name"))
+	tests := []struct {
+		name     string
+		masker   *Masker
+		query    string
+		data     string
+		expected string
+		errors   [][]any
+	}{
+		{
+			name:     "nil schema",
+			masker:   &Masker{Allow: allow},
+			query:    `{ me { name ssn } }`,
+			data:     `{"me": {"name": "A", "ssn": "123"}}`,
+			expected: `{"me": null}`,
+			errors:   [][]any{{"me"}},
+		},
+		{
+			name:     "unresolvable field",
+			masker:   &Masker{Schema: schema, Allow: func(coordinate.Coordinate) bool { return true }},
+			query:    `{ me { name unknown { ssn } } }`,
+			data:     `{"me": {"name": "A", "unknown": {"ssn": "123"}}}`,
+			expected: `{"me": {"name": "A", "unknown": null}}`,
+			errors:   [][]any{{"me", "unknown"}},
+		},
+		{
+			name:     "unresolvable root type",
+			masker:   &Masker{Schema: asttest.Schema(t, `type Root { me: User } type User { name: String }`), Allow: func(coordinate.Coordinate) bool { return true }},
+			query:    `{ me { name } }`,
+			data:     `{"me": {"name": "A"}}`,
+			expected: `{"me": null}`,
+			errors:   [][]any{{"me"}},
+		},
+		{
+			name:     "nil allow",
+			masker:   &Masker{Schema: schema},
+			query:    `{ me { __typename name } }`,
+			data:     `{"me": {"__typename": "User", "name": "A"}}`,
+			expected: `null`,
+			errors:   [][]any{{"me"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			resp := decode(t, `{"data": `+tt.data+`}`)
+			tt.masker.Mask(resp, doc, doc.Definitions[0].(*ast.OperationDefinition))
+
+			assertJSON(t, resp.Data, tt.expected)
+			var paths [][]any
+			for _, e := range resp.Errors {
+				paths = append(paths, e.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.errors) {
+				t.Errorf("expected error paths %v, got %v", tt.errors, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Object is a result object. Unlike Go maps it keeps keys in insertion
+// order, which GraphQL requires to match the order fields were collected
+// in.
+//
+// https://spec.graphql.org/draft/#sec-Serialized-Map-Ordering
+type Object struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewObject returns empty object.
+func NewObject() *Object {
+	return &Object{values: make(map[string]any)}
+}
+
+// Len returns number of keys.
+func (o *Object) Len() int {
+	return len(o.keys)
+}
+
+// Get returns value of key.
+func (o *Object) Get(key string) (any, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// Set sets value of key. New keys are appended to the end.
+func (o *Object) Set(key string, value any) {
+	if o.values == nil {
+		o.values = make(map[string]any)
+	}
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Delete removes key.
+func (o *Object) Delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+	o.keys = slices.DeleteFunc(o.keys, func(k string) bool { return k == key })
+}
+
+// Keys returns keys in order.
+func (o *Object) Keys() []string {
+	return slices.Clone(o.keys)
+}
+
+// All returns iterator over key-value pairs in order.
+func (o *Object) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, k := range o.keys {
+			if !yield(k, o.values[k]) {
+				return
+			}
+		}
+	}
+}
+
+// reorder moves keys to the front in given order. Unknown keys are
+// ignored and keys not listed keep their relative order after them.
+func (o *Object) reorder(keys []string) {
+	ordered := make([]string, 0, len(o.keys))
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if _, ok := o.values[k]; ok && !seen[k] {
+			seen[k] = true
+			ordered = append(ordered, k)
+		}
+	}
+	for _, k := range o.keys {
+		if !seen[k] {
+			ordered = append(ordered, k)
+		}
+	}
+	o.keys = ordered
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes JSON object keeping key order. Nested objects are
+// decoded as *Object, lists as []any and numbers as json.Number.
+func (o *Object) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return err
+	}
+	obj, ok := v.(*Object)
+	if !ok {
+		return fmt.Errorf("expected JSON object, got %s", data)
+	}
+	*o = *obj
+	return nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := NewObject()
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.Set(keyTok.(string), value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	return tok, nil
+}
@@ -0,0 +1,56 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestObject(t *testing.T) {
+	obj := NewObject()
+	obj.Set("b", 1)
+	obj.Set("a", 2)
+	obj.Set("c", 3)
+	obj.Set("b", 4)
+	obj.Delete("a")
+	obj.Delete("missing")
+
+	if !reflect.DeepEqual(obj.Keys(), []string{"b", "c"}) {
+		t.Errorf("unexpected keys: %v", obj.Keys())
+	}
+	if v, ok := obj.Get("b"); !ok || v != 4 {
+		t.Errorf("unexpected value of b: %v", v)
+	}
+	var pairs []any
+	for k, v := range obj.All() {
+		pairs = append(pairs, k, v)
+	}
+	if !reflect.DeepEqual(pairs, []any{"b", 4, "c", 3}) {
+		t.Errorf("unexpected pairs: %v", pairs)
+	}
+}
+
+func TestObject_JSON(t *testing.T) {
+	input := `{"z":1,"a":{"y":[{"q":true,"b":null}],"x":"s"},"m":2.5}`
+	var obj Object
+	if err := json.Unmarshal([]byte(input), &obj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(obj.Keys(), []string{"z", "a", "m"}) {
+		t.Errorf("unexpected keys: %v", obj.Keys())
+	}
+	if v, _ := obj.Get("m"); v != json.Number("2.5") {
+		t.Errorf("expected json.Number, got %#v", v)
+	}
+	out, err := json.Marshal(&obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != input {
+		t.Errorf("expected %s, got %s", input, out)
+	}
+
+	if err := json.Unmarshal([]byte(`[1]`), &obj); err == nil {
+		t.Errorf("expected error for non-object")
+	}
+}
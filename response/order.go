@@ -0,0 +1,52 @@
+package response
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Order reorders keys of result objects in resp to match the order fields
+// of op are collected in, including fields merged from fragments. Keys not
+// selected by op are kept after the selected ones. Fragments are looked
+// up in doc.
+//
+// Schema is used to resolve field types and fragment applicability and
+// may be nil, in which case every fragment is assumed to apply.
+func Order(resp *Response, s *schema.Schema, doc *ast.Document, op *ast.OperationDefinition) {
+	o := &orderer{schema: s, collector: newCollector(s, doc)}
+	o.value(resp.Data, rootType(s, op.OperationType), []*ast.SelectionSet{op.SelectionSet})
+}
+
+type orderer struct {
+	schema    *schema.Schema
+	collector *collector
+}
+
+func (o *orderer) value(v any, typeName string, sets []*ast.SelectionSet) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			o.value(item, typeName, sets)
+		}
+	case *Object:
+		typeName = runtimeType(v, typeName)
+		fields := o.collector.collect(typeName, sets)
+		keys := make([]string, len(fields))
+		for i, f := range fields {
+			keys[i] = f.Key
+		}
+		v.reorder(keys)
+
+		for _, f := range fields {
+			child, ok := v.Get(f.Key)
+			if !ok {
+				continue
+			}
+			var fieldType string
+			if _, def := lookupField(o.schema, typeName, f); def != nil {
+				fieldType = namedType(def.Type)
+			}
+			o.value(child, fieldType, f.SelectionSets())
+		}
+	}
+}
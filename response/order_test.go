@@ -0,0 +1,77 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+// Field ordering examples follow the specification:
+// https://spec.graphql.org/draft/#sec-Objects
+func TestOrder(t *testing.T) {
+	schema := asttest.Schema(t, `
+type Query { me: User! node(id: ID!): Node }
+interface Node { id: ID! }
+type User implements Node { id: ID! name: String friends: [User!]! }
+type Bot implements Node { id: ID! owner: User }
+`)
+
+	tests := []struct {
+		name     string
+		query    string
+		data     string
+		noSchema bool
+		expected string
+	}{
+		{
+			name:     "selection order",
+			query:    `{ me { name id } }`,
+			data:     `{"me":{"id":"1","name":"A"}}`,
+			expected: `{"data":{"me":{"name":"A","id":"1"}}}`,
+		},
+		{
+			name:     "fragment merged fields keep first position",
+			query:    `{ me { id ...F name } } fragment F on User { name friends { name id } id }`,
+			data:     `{"me":{"friends":[{"id":"2","name":"B"}],"name":"A","id":"1"}}`,
+			expected: `{"data":{"me":{"id":"1","name":"A","friends":[{"name":"B","id":"2"}]}}}`,
+		},
+		{
+			name:     "runtime type selects fragments",
+			query:    `{ node(id: "1") { ... on User { name } __typename ... on Bot { owner { id name } id } id } }`,
+			data:     `{"node":{"id":"1","owner":{"name":"A","id":"2"},"__typename":"Bot"}}`,
+			expected: `{"data":{"node":{"__typename":"Bot","owner":{"id":"2","name":"A"},"id":"1"}}}`,
+		},
+		{
+			name:     "unselected keys last",
+			query:    `{ me { name } }`,
+			data:     `{"me":{"extra":1,"name":"A"}}`,
+			expected: `{"data":{"me":{"name":"A","extra":1}}}`,
+		},
+		{
+			name:     "without schema",
+			query:    `{ b a { d c } }`,
+			data:     `{"a":{"c":1,"d":2},"b":3}`,
+			noSchema: true,
+			expected: `{"data":{"b":3,"a":{"d":2,"c":1}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			resp := decode(t, `{"data":`+tt.data+`}`)
+			s := schema
+			if tt.noSchema {
+				s = nil
+			}
+			Order(resp, s, doc, doc.Definitions[0].(*ast.OperationDefinition))
+			out, err := resp.Encode()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package response
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Propagate applies field error and null propagation to resp as
+// CompleteValue() does during execution, so code assembling results
+// outside of an executor reuses the same nullability behavior:
+//
+//   - a value at path of an error in resp.Errors is replaced by null;
+//   - null of a non-null field or list item is propagated to the closest
+//     nullable parent, or to data when there is none.
+//
+// A null in a non-null position without an error at or below its path is
+// reported by a new error. Fragments of op are looked up in doc.
+//
+// https://spec.graphql.org/draft/#sec-Handling-Execution-Errors
+func Propagate(resp *Response, s *schema.Schema, doc *ast.Document, op *ast.OperationDefinition) {
+	p := &propagator{schema: s, collector: newCollector(s, doc), resp: resp}
+	p.propagate(op)
+}
+
+type propagator struct {
+	schema    *schema.Schema
+	collector *collector
+	resp      *Response
+}
+
+func (p *propagator) propagate(op *ast.OperationDefinition) {
+	for _, e := range p.resp.Errors {
+		nullAt(p.resp.Data, e.Path)
+	}
+	data, ok := p.resp.Data.(*Object)
+	if !ok {
+		return
+	}
+	if p.object(data, rootType(p.schema, op.OperationType), []*ast.SelectionSet{op.SelectionSet}, nil) {
+		p.resp.Data = nil
+	}
+}
+
+// complete propagates nulls inside v of type t and returns either v or
+// nil when null propagated to v itself.
+func (p *propagator) complete(v any, t ast.Type, sets []*ast.SelectionSet, path []any) any {
+	t, _ = unwrapNonNull(t)
+	switch v := v.(type) {
+	case []any:
+		list, ok := t.(*ast.ListType)
+		if !ok {
+			return v
+		}
+		_, nonNull := list.Type.(*ast.NonNullType)
+		for i, item := range v {
+			itemPath := appendPath(path, i)
+			v[i] = p.complete(item, list.Type, sets, itemPath)
+			if v[i] == nil && nonNull {
+				p.report(itemPath, "Cannot return null for non-nullable list item.")
+				return nil
+			}
+		}
+	case *Object:
+		if p.object(v, namedType(t), sets, path) {
+			return nil
+		}
+	}
+	return v
+}
+
+// object propagates nulls inside obj and reports whether null propagated
+// to obj itself.
+func (p *propagator) object(obj *Object, typeName string, sets []*ast.SelectionSet, path []any) bool {
+	typeName = runtimeType(obj, typeName)
+	for _, f := range p.collector.collect(typeName, sets) {
+		v, ok := obj.Get(f.Key)
+		if !ok {
+			continue
+		}
+		parent, def := lookupField(p.schema, typeName, f)
+		if def == nil {
+			continue
+		}
+		fieldPath := appendPath(path, f.Key)
+		v = p.complete(v, def.Type, f.SelectionSets(), fieldPath)
+		obj.Set(f.Key, v)
+		if _, nonNull := def.Type.(*ast.NonNullType); v == nil && nonNull {
+			p.report(fieldPath, fmt.Sprintf("Cannot return null for non-nullable field %s.%s.", parent, f.Name()))
+			return true
+		}
+	}
+	return false
+}
+
+// report adds error at path unless an error was already raised at path or
+// below it.
+func (p *propagator) report(path []any, msg string) {
+	for _, e := range p.resp.Errors {
+		if len(e.Path) >= len(path) && slices.Equal(e.Path[:len(path)], path) {
+			return
+		}
+	}
+	p.resp.Errors = append(p.resp.Errors, &Error{Message: msg, Path: slices.Clone(path)})
+}
+
+// nullAt replaces value at path in result tree v by null. Paths that do
+// not exist are ignored.
+func nullAt(v any, path []any) {
+	if len(path) == 0 {
+		return
+	}
+	elem := path[len(path)-1]
+	switch parent := lookup(v, path[:len(path)-1]).(type) {
+	case *Object:
+		if key, ok := elem.(string); ok {
+			if _, ok := parent.Get(key); ok {
+				parent.Set(key, nil)
+			}
+		}
+	case []any:
+		if i, ok := elem.(int); ok && i >= 0 && i < len(parent) {
+			parent[i] = nil
+		}
+	}
+}
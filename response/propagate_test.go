@@ -0,0 +1,93 @@
+package response
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestPropagate(t *testing.T) {
+	schema := asttest.Schema(t, `
+type Query { me: User! user: User list: [User!] items: [User]! }
+type User { id: ID! name: String friend: User }
+`)
+
+	type expectedError struct {
+		message string
+		path    []any
+	}
+	tests := []struct {
+		name     string
+		query    string
+		response string
+		data     string
+		errors   []expectedError
+	}{
+		{
+			name:     "nullable field",
+			query:    `{ user { id name } }`,
+			response: `{"data":{"user":{"id":"1","name":null}}}`,
+			data:     `{"user":{"id":"1","name":null}}`,
+		},
+		{
+			name:     "non-null field to nullable parent",
+			query:    `{ user { friend { id } } }`,
+			response: `{"data":{"user":{"friend":{"id":null}}}}`,
+			data:     `{"user":{"friend":null}}`,
+			errors:   []expectedError{{"Cannot return null for non-nullable field User.id.", []any{"user", "friend", "id"}}},
+		},
+		{
+			name:     "to data",
+			query:    `{ user { id } me { id } }`,
+			response: `{"data":{"user":{"id":"1"},"me":null}}`,
+			data:     `null`,
+			errors:   []expectedError{{"Cannot return null for non-nullable field Query.me.", []any{"me"}}},
+		},
+		{
+			name:     "non-null list item",
+			query:    `{ list { id } }`,
+			response: `{"data":{"list":[{"id":"1"},null]}}`,
+			data:     `{"list":null}`,
+			errors:   []expectedError{{"Cannot return null for non-nullable list item.", []any{"list", 1}}},
+		},
+		{
+			name:     "nullable list item",
+			query:    `{ items { id } }`,
+			response: `{"data":{"items":[{"id":"1"},{"id":null}]}}`,
+			data:     `{"items":[{"id":"1"},null]}`,
+			errors:   []expectedError{{"Cannot return null for non-nullable field User.id.", []any{"items", 1, "id"}}},
+		},
+		{
+			name:     "error path is nulled",
+			query:    `{ user { a: id name } }`,
+			response: `{"data":{"user":{"a":"1","name":"A"}},"errors":[{"message":"boom","path":["user","a"]}]}`,
+			data:     `{"user":null}`,
+			errors:   []expectedError{{"boom", []any{"user", "a"}}},
+		},
+		{
+			name:     "unknown error path",
+			query:    `{ user { id } }`,
+			response: `{"data":{"user":null},"errors":[{"message":"boom","path":["user","id"]}]}`,
+			data:     `{"user":null}`,
+			errors:   []expectedError{{"boom", []any{"user", "id"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := asttest.Parse(t, tt.query)
+			resp := decode(t, tt.response)
+			Propagate(resp, schema, doc, doc.Definitions[0].(*ast.OperationDefinition))
+
+			assertJSON(t, resp.Data, tt.data)
+			var errors []expectedError
+			for _, e := range resp.Errors {
+				errors = append(errors, expectedError{e.Message, e.Path})
+			}
+			if !reflect.DeepEqual(errors, tt.errors) {
+				t.Errorf("expected errors %v, got %v", tt.errors, errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Package response provides transforms of GraphQL responses driven by the
+// executed operation and schema: masking, ID encoding, null propagation and
+// merging of partial results.
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Location of error in request document.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Path of response field an error refers to. Elements are either string
+// field names (response keys) or int list indices.
+type Path []any
+
+func (p *Path) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	path := make(Path, len(raw))
+	for i, elem := range raw {
+		var key string
+		if err := json.Unmarshal(elem, &key); err == nil {
+			path[i] = key
+			continue
+		}
+		var index int
+		if err := json.Unmarshal(elem, &index); err != nil {
+			return fmt.Errorf("invalid path element %s", elem)
+		}
+		path[i] = index
+	}
+	*p = path
+	return nil
+}
+
+// String returns path in form "user.friends[0].name".
+func (p Path) String() string {
+	var b strings.Builder
+	for i, elem := range p {
+		switch e := elem.(type) {
+		case int:
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(e))
+			b.WriteByte(']')
+		default:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			fmt.Fprint(&b, e)
+		}
+	}
+	return b.String()
+}
+
+// HasPrefix reports whether p starts with prefix.
+func (p Path) HasPrefix(prefix Path) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Error is a GraphQL error as defined by the response format, shared by
+// servers writing responses and clients reading them.
+//
+// https://spec.graphql.org/draft/#sec-Errors
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       Path           `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Path) > 0 {
+		return fmt.Sprintf("graphql: %s (path: %s)", e.Message, e.Path)
+	}
+	return "graphql: " + e.Message
+}
+
+// IsFieldError reports whether error was raised during field execution.
+func (e *Error) IsFieldError() bool {
+	return len(e.Path) > 0
+}
+
+// Response is a GraphQL response. Data holds decoded result tree: objects
+// are *Object, lists are []any and numbers are json.Number.
+//
+// https://spec.graphql.org/draft/#sec-Response-Format
+type Response struct {
+	Data       any            `json:"data"`
+	Errors     []*Error       `json:"errors,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Decode decodes JSON encoded response keeping order of result object
+// keys.
+func Decode(data []byte) (*Response, error) {
+	var resp Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return &resp, nil
+}
+
+func (r *Response) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Data       json.RawMessage `json:"data"`
+		Errors     []*Error        `json:"errors"`
+		Extensions map[string]any  `json:"extensions"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	*r = Response{Errors: raw.Errors, Extensions: raw.Extensions}
+	if len(raw.Data) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(raw.Data))
+		dec.UseNumber()
+		data, err := decodeValue(dec)
+		if err != nil {
+			return err
+		}
+		r.Data = data
+	}
+	return nil
+}
+
+// Encode returns JSON encoding of response.
+func (r *Response) Encode() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// appendPath returns path extended by elem without modifying backing array
+// of path.
+func appendPath(path []any, elem any) []any {
+	return append(path[:len(path):len(path)], elem)
+}
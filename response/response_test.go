@@ -0,0 +1,16 @@
+package response
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	resp := decode(t, `{"data": {"a": [1]}, "errors": [{"message": "boom", "path": ["a", 0]}]}`)
+	if !reflect.DeepEqual(resp.Errors[0].Path, Path{"a", 0}) {
+		t.Errorf("unexpected path: %#v", resp.Errors[0].Path)
+	}
+	if _, err := Decode([]byte(`{"errors": [{"message": "boom", "path": [1.5]}]}`)); err == nil {
+		t.Errorf("expected error for fractional path index")
+	}
+}
@@ -0,0 +1,226 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// EntitiesField is the field entity fetches select entities with.
+const EntitiesField = "_entities"
+
+// Stitcher merges partial responses of upstream fetches, e.g. of split
+// operations or subgraph fetches, into a single response to the original
+// operation.
+type Stitcher struct {
+	schema *schema.Schema
+	doc    *ast.Document
+	op     *ast.OperationDefinition
+	resp   *Response
+}
+
+// NewStitcher returns stitcher building response to op. Fragments of op
+// are looked up in doc.
+func NewStitcher(s *schema.Schema, doc *ast.Document, op *ast.OperationDefinition) *Stitcher {
+	return &Stitcher{schema: s, doc: doc, op: op, resp: &Response{}}
+}
+
+// Merge deep merges data of part into object at path at of merged result
+// and adds errors of part with their paths prefixed by at. Data of parts
+// merged at paths that do not exist or are null is dropped.
+func (s *Stitcher) Merge(part *Response, at []any) {
+	if part.Data != nil {
+		if len(at) == 0 && s.resp.Data == nil {
+			s.resp.Data = NewObject()
+		}
+		if target, ok := lookup(s.resp.Data, at).(*Object); ok {
+			merge(target, part.Data)
+		}
+	}
+	for _, e := range part.Errors {
+		if len(e.Path) == 0 {
+			s.addError(e)
+			continue
+		}
+		s.addError(withPath(e, slices.Concat(at, e.Path)))
+	}
+	s.mergeExtensions(part)
+}
+
+// MergeEntities merges part returned by entity fetch. Item i of
+// "_entities" list is merged into objects at every path of targets[i];
+// gateways fetching an entity found at several places once list all of
+// them. Errors of entities are reported at each target path.
+func (s *Stitcher) MergeEntities(part *Response, targets [][][]any) error {
+	var entities []any
+	if part.Data != nil {
+		data, ok := part.Data.(*Object)
+		if !ok {
+			return errors.New("entity response data is not an object")
+		}
+		v, _ := data.Get(EntitiesField)
+		if v != nil {
+			if entities, ok = v.([]any); !ok {
+				return fmt.Errorf("%s is not a list", EntitiesField)
+			}
+		}
+		if v != nil && len(entities) != len(targets) {
+			return fmt.Errorf("expected %d entities, got %d", len(targets), len(entities))
+		}
+	}
+
+	for i, entity := range entities {
+		for _, path := range targets[i] {
+			if target, ok := lookup(s.resp.Data, path).(*Object); ok && entity != nil {
+				merge(target, entity)
+			}
+		}
+	}
+	for _, e := range part.Errors {
+		i, ok := entityIndex(e.Path)
+		if !ok || i >= len(targets) {
+			s.addError(withPath(e, nil))
+			continue
+		}
+		for _, path := range targets[i] {
+			s.addError(withPath(e, slices.Concat(path, e.Path[2:])))
+		}
+	}
+	s.mergeExtensions(part)
+	return nil
+}
+
+// Response returns merged response with keys ordered as selected by the
+// operation and nulls propagated.
+func (s *Stitcher) Response() *Response {
+	Order(s.resp, s.schema, s.doc, s.op)
+	Propagate(s.resp, s.schema, s.doc, s.op)
+	return s.resp
+}
+
+// addError adds e unless an error with the same message and path was
+// already added.
+func (s *Stitcher) addError(e *Error) {
+	for _, other := range s.resp.Errors {
+		if other.Message == e.Message && slices.Equal(other.Path, e.Path) {
+			return
+		}
+	}
+	s.resp.Errors = append(s.resp.Errors, e)
+}
+
+func (s *Stitcher) mergeExtensions(part *Response) {
+	for k, v := range part.Extensions {
+		if s.resp.Extensions == nil {
+			s.resp.Extensions = make(map[string]any)
+		}
+		if _, ok := s.resp.Extensions[k]; !ok {
+			s.resp.Extensions[k] = v
+		}
+	}
+}
+
+// merge deep merges src into dst. Objects are merged key by key and lists
+// of equal length item by item. Null never replaces existing value. Values
+// of src are copied, so src may be merged into several objects.
+func merge(dst *Object, src any) {
+	obj, ok := src.(*Object)
+	if !ok {
+		return
+	}
+	for k, v := range obj.All() {
+		existing, ok := dst.Get(k)
+		if !ok || existing == nil {
+			dst.Set(k, clone(v))
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		dst.Set(k, mergeValue(existing, v))
+	}
+}
+
+func mergeValue(dst, src any) any {
+	switch d := dst.(type) {
+	case *Object:
+		if _, ok := src.(*Object); ok {
+			merge(d, src)
+			return d
+		}
+	case []any:
+		if s, ok := src.([]any); ok && len(s) == len(d) {
+			for i := range d {
+				switch {
+				case d[i] == nil:
+					d[i] = clone(s[i])
+				case s[i] != nil:
+					d[i] = mergeValue(d[i], s[i])
+				}
+			}
+			return d
+		}
+	}
+	return clone(src)
+}
+
+// clone returns deep copy of result tree v.
+func clone(v any) any {
+	switch v := v.(type) {
+	case *Object:
+		obj := NewObject()
+		for k, item := range v.All() {
+			obj.Set(k, clone(item))
+		}
+		return obj
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = clone(item)
+		}
+		return list
+	}
+	return v
+}
+
+// lookup returns value at path in result tree v or nil.
+func lookup(v any, path []any) any {
+	for _, elem := range path {
+		switch elem := elem.(type) {
+		case string:
+			obj, ok := v.(*Object)
+			if !ok {
+				return nil
+			}
+			v, _ = obj.Get(elem)
+		case int:
+			list, ok := v.([]any)
+			if !ok || elem < 0 || elem >= len(list) {
+				return nil
+			}
+			v = list[elem]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// entityIndex returns index of entity error path of form
+// ["_entities", i, ...] refers to.
+func entityIndex(path []any) (int, bool) {
+	if len(path) < 2 || path[0] != EntitiesField {
+		return 0, false
+	}
+	i, ok := path[1].(int)
+	return i, ok && i >= 0
+}
+
+func withPath(e *Error, path []any) *Error {
+	copied := *e
+	copied.Path = path
+	return &copied
+}
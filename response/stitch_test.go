@@ -0,0 +1,95 @@
+package response
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestStitcher(t *testing.T) {
+	schema := asttest.Schema(t, `
+type Query { me: User posts: [Post!]! }
+type User { id: ID! name: String reviews: [Review] }
+type Post { id: ID! title: String author: User! }
+type Review { body: String }
+`)
+	doc := asttest.Parse(t, `{ posts { title author { name reviews { body } id } id } me { id name } }`)
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+
+	s := NewStitcher(schema, doc, op)
+	s.Merge(decode(t, `{"data":{"posts":[{"id":"p1","title":"A","author":{"id":"u1"}},{"id":"p2","title":"B","author":{"id":"u1"}},{"id":"p3","title":"C","author":{"id":"u2"}}]}}`), nil)
+	s.Merge(decode(t, `{"data":{"me":{"id":"u1"}},"extensions":{"cost":1}}`), nil)
+	s.Merge(decode(t, `{"data":{"name":"Me"}}`), []any{"me"})
+	err := s.MergeEntities(decode(t, `{
+		"data":{"_entities":[{"name":"Ann","reviews":[{"body":"ok"}]},{"name":null}]},
+		"errors":[{"message":"no name","path":["_entities",1,"name"]},{"message":"rate limited"}],
+		"extensions":{"cost":2}
+	}`), [][][]any{
+		{{"posts", 0, "author"}, {"posts", 1, "author"}},
+		{{"posts", 2, "author"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Merging the same entity again is idempotent and errors are not
+	// duplicated.
+	err = s.MergeEntities(decode(t, `{"data":{"_entities":[{"name":null}]},"errors":[{"message":"no name","path":["_entities",0,"name"]}]}`), [][][]any{
+		{{"posts", 2, "author"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Merge(decode(t, `{"data":{"id":"x"}}`), []any{"missing"})
+
+	resp := s.Response()
+	first, _ := lookup(resp.Data, []any{"posts", 0, "author", "reviews", 0}).(*Object)
+	second, _ := lookup(resp.Data, []any{"posts", 1, "author", "reviews", 0}).(*Object)
+	if first == nil || first == second {
+		t.Errorf("expected entity to be copied into each target")
+	}
+	out, err := resp.Encode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"data":{"posts":[` +
+		`{"title":"A","author":{"name":"Ann","reviews":[{"body":"ok"}],"id":"u1"},"id":"p1"},` +
+		`{"title":"B","author":{"name":"Ann","reviews":[{"body":"ok"}],"id":"u1"},"id":"p2"},` +
+		`{"title":"C","author":{"name":null,"id":"u2"},"id":"p3"}]` +
+		`,"me":{"id":"u1","name":"Me"}},` +
+		`"errors":[{"message":"no name","path":["posts",2,"author","name"]},{"message":"rate limited"}],` +
+		`"extensions":{"cost":1}}`
+	if string(out) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out)
+	}
+}
+
+func TestStitcher_MergeEntitiesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		targets [][][]any
+	}{
+		{"not an object", `{"data":[1]}`, nil},
+		{"not a list", `{"data":{"_entities":{}}}`, nil},
+		{"length mismatch", `{"data":{"_entities":[{}]}}`, [][][]any{{{"a"}}, {{"b"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStitcher(nil, asttest.Parse(t, `{ a }`), nil)
+			if err := s.MergeEntities(decode(t, tt.data), tt.targets); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dst := decode(t, `{"data":{"a":{"b":1,"l":[{"x":1},null]},"n":null}}`).Data.(*Object)
+	merge(dst, decode(t, `{"data":{"a":{"c":2,"b":null,"l":[{"y":2},{"z":3}]},"n":5,"m":6}}`).Data)
+	assertJSON(t, dst, `{"a":{"b":1,"c":2,"l":[{"x":1,"y":2},{"z":3}]},"n":5,"m":6}`)
+	if !reflect.DeepEqual(dst.Keys(), []string{"a", "n", "m"}) {
+		t.Errorf("unexpected keys: %v", dst.Keys())
+	}
+}
@@ -0,0 +1,393 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// FromDocument builds schema from SDL document. Type extensions are merged
+// into the types they extend and type references are checked to refer to
+// defined types of the right kind; built-in scalars need not be defined.
+// All errors found are returned as Errors.
+func FromDocument(doc *ast.Document, opts ...Option) (*Schema, error) {
+	b := &builder{
+		schema: &Schema{
+			types:         make(map[string]*Type),
+			directiveDefs: make(map[string]*Directive),
+			roots:         make(map[ast.OperationType]string),
+		},
+		rootPos: make(map[ast.OperationType]int),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.build(doc)
+	if len(b.errs) > 0 {
+		return nil, b.errs
+	}
+	return b.schema, nil
+}
+
+type builder struct {
+	namePolicy NamePolicy
+	schema     *Schema
+	rootPos    map[ast.OperationType]int // Positions of root operation types.
+	errs       Errors
+}
+
+func (b *builder) errorf(pos int, format string, args ...any) {
+	b.errs = append(b.errs, &Error{Message: fmt.Sprintf(format, args...), Positions: []int{pos}})
+}
+
+// duplicate reports element defined more than once at positions of its
+// first and duplicate definition.
+func (b *builder) duplicate(what string, first, dup ast.Node) {
+	b.errs = append(b.errs, &Error{
+		Message:   fmt.Sprintf("%s is defined more than once", what),
+		Positions: []int{first.Pos(), dup.Pos()},
+	})
+}
+
+func (b *builder) build(doc *ast.Document) {
+	var schemaDef *ast.SchemaDefinition
+	var extensions []ast.Definition
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			if schemaDef != nil {
+				b.duplicate("schema definition", schemaDef, d)
+				continue
+			}
+			schemaDef = d
+			if d.Description != nil {
+				b.schema.Description = d.Description.Value
+			}
+			b.schema.Directives = append(b.schema.Directives, d.Directives...)
+			b.addRoots(d.RootOperationDefs)
+		case *ast.DirectiveDefinition:
+			b.addDirective(d)
+		case *ast.OperationDefinition, *ast.FragmentDefinition:
+			b.errorf(d.Pos(), "executable definitions are not allowed in schema document")
+		default:
+			if t := newType(def); t != nil {
+				b.addType(t)
+			} else {
+				extensions = append(extensions, def)
+			}
+		}
+	}
+	for _, ext := range extensions {
+		b.extend(ext)
+	}
+	b.checkMembers()
+	b.checkReferences()
+	if schemaDef == nil && len(b.schema.roots) == 0 {
+		for op, name := range map[ast.OperationType]string{
+			ast.OperationTypeQuery:        "Query",
+			ast.OperationTypeMutation:     "Mutation",
+			ast.OperationTypeSubscription: "Subscription",
+		} {
+			if _, ok := b.schema.types[name]; ok {
+				b.schema.roots[op] = name
+			}
+		}
+	}
+}
+
+func (b *builder) addRoots(roots []*ast.RootOperationTypeDefinition) {
+	for _, root := range roots {
+		b.schema.roots[root.OperationType] = root.Type.Name.Value
+		b.rootPos[root.OperationType] = root.Type.Pos()
+	}
+}
+
+func (b *builder) addType(t *Type) {
+	b.checkName("type", t.Name, typeName(t.Definition), introspectionTypes[t.Name])
+	if first, ok := b.schema.types[t.Name]; ok {
+		b.duplicate(fmt.Sprintf("type %q", t.Name), typeName(first.Definition), typeName(t.Definition))
+		return
+	}
+	meta := introspectionTypes[t.Name]
+	b.checkFields(t.Name, t.Fields, meta)
+	b.checkInputValues("input field", t.Name+".", t.InputFields, meta)
+	b.checkEnumValues(t.Name, t.EnumValues, meta)
+	b.schema.types[t.Name] = t
+	b.schema.typeOrder = append(b.schema.typeOrder, t.Name)
+}
+
+func (b *builder) addDirective(def *ast.DirectiveDefinition) {
+	d := &Directive{
+		Name:        def.Name.Value,
+		Description: description(def.Description),
+		Arguments:   inputValues(def.Arguments),
+		Repeatable:  def.Repeatable,
+		Definition:  def,
+	}
+	for _, loc := range def.Locations {
+		d.Locations = append(d.Locations, ast.DirectiveLocation(loc.Value))
+	}
+	b.checkName("directive", "@"+d.Name, def.Name, false)
+	if first, ok := b.schema.directiveDefs[d.Name]; ok {
+		b.duplicate("directive @"+d.Name, first.Definition.Name, def.Name)
+		return
+	}
+	b.checkInputValues("argument", "@"+d.Name+"(", d.Arguments, false)
+	b.schema.directiveDefs[d.Name] = d
+	b.schema.directiveOrder = append(b.schema.directiveOrder, d.Name)
+}
+
+// extend merges type or schema extension.
+func (b *builder) extend(def ast.Definition) {
+	if ext, ok := def.(*ast.SchemaExtension); ok {
+		b.schema.Directives = append(b.schema.Directives, ext.Directives...)
+		b.addRoots(ext.RootOperationDefs)
+		return
+	}
+
+	ext := newType(extensionAsDefinition(def))
+	if ext == nil {
+		return
+	}
+	t, ok := b.schema.types[ext.Name]
+	if !ok {
+		b.errorf(def.Pos(), "cannot extend undefined type %q", ext.Name)
+		return
+	}
+	if t.Kind != ext.Kind {
+		b.errorf(def.Pos(), "cannot extend %s type %q with %s extension", t.Kind, t.Name, ext.Kind)
+		return
+	}
+	meta := introspectionTypes[t.Name]
+	b.checkFields(t.Name, ext.Fields, meta)
+	b.checkInputValues("input field", t.Name+".", ext.InputFields, meta)
+	b.checkEnumValues(t.Name, ext.EnumValues, meta)
+
+	t.Directives = append(t.Directives, ext.Directives...)
+	t.Interfaces = append(t.Interfaces, ext.Interfaces...)
+	t.Fields = append(t.Fields, ext.Fields...)
+	t.Types = append(t.Types, ext.Types...)
+	t.EnumValues = append(t.EnumValues, ext.EnumValues...)
+	t.InputFields = append(t.InputFields, ext.InputFields...)
+	t.Extensions = append(t.Extensions, def)
+}
+
+func (b *builder) checkFields(typeName string, fields []*Field, meta bool) {
+	for _, f := range fields {
+		metaField := meta || metaFields[f.Name]
+		b.checkName("field", typeName+"."+f.Name, f.Definition.Name, metaField)
+		b.checkInputValues("argument", typeName+"."+f.Name+"(", f.Arguments, metaField)
+	}
+}
+
+// checkInputValues checks names of arguments or input fields. Coordinates
+// are formed by prefix and argument names are closed by ":)".
+func (b *builder) checkInputValues(what, prefix string, values []*InputValue, meta bool) {
+	for _, v := range values {
+		coordinate := prefix + v.Name
+		if strings.HasSuffix(prefix, "(") {
+			coordinate += ":)"
+		}
+		b.checkName(what, coordinate, v.Definition.Name, meta)
+	}
+}
+
+func (b *builder) checkEnumValues(typeName string, values []*EnumValue, meta bool) {
+	for _, v := range values {
+		b.checkName("enum value", typeName+"."+v.Name, v.Definition.Name, meta)
+	}
+}
+
+// checkMembers reports fields, arguments, enum values and input fields
+// defined more than once within their type, field or directive, including
+// members added by extensions.
+func (b *builder) checkMembers() {
+	for _, t := range b.schema.Types() {
+		fieldNames := make([]*ast.Name, len(t.Fields))
+		for i, f := range t.Fields {
+			fieldNames[i] = f.Definition.Name
+			b.checkUnique("argument", t.Name+"."+f.Name+"(", inputValueNames(f.Arguments))
+		}
+		b.checkUnique("field", t.Name+".", fieldNames)
+
+		valueNames := make([]*ast.Name, len(t.EnumValues))
+		for i, v := range t.EnumValues {
+			valueNames[i] = v.Definition.Name
+		}
+		b.checkUnique("enum value", t.Name+".", valueNames)
+		b.checkUnique("input field", t.Name+".", inputValueNames(t.InputFields))
+	}
+	for _, d := range b.schema.DirectiveDefinitions() {
+		b.checkUnique("argument", "@"+d.Name+"(", inputValueNames(d.Arguments))
+	}
+}
+
+// checkUnique reports names defined more than once. Elements are named by
+// schema coordinates starting with prefix.
+func (b *builder) checkUnique(what, prefix string, names []*ast.Name) {
+	first := make(map[string]*ast.Name, len(names))
+	for _, name := range names {
+		other, ok := first[name.Value]
+		if !ok {
+			first[name.Value] = name
+			continue
+		}
+		coordinate := prefix + name.Value
+		if strings.HasSuffix(prefix, "(") {
+			coordinate += ":)"
+		}
+		b.duplicate(what+" "+coordinate, other, name)
+	}
+}
+
+func inputValueNames(values []*InputValue) []*ast.Name {
+	names := make([]*ast.Name, len(values))
+	for i, v := range values {
+		names[i] = v.Definition.Name
+	}
+	return names
+}
+
+// checkName reports name of element with given schema coordinate when it
+// is reserved and not allowed by name policy. Meta elements belong to the
+// introspection system.
+func (b *builder) checkName(what, coordinate string, name *ast.Name, meta bool) {
+	if !strings.HasPrefix(name.Value, "__") {
+		return
+	}
+	switch b.namePolicy {
+	case AllowReservedNames:
+		return
+	case AllowIntrospectionNames:
+		if meta {
+			return
+		}
+	}
+	b.errorf(name.Pos(), `%s %s: name %q must not begin with "__", which is reserved by GraphQL introspection`, what, coordinate, name.Value)
+}
+
+// typeName returns name node of type definition.
+func typeName(def ast.Definition) *ast.Name {
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		return d.Name
+	case *ast.ObjectTypeDefinition:
+		return d.Name
+	case *ast.InterfaceTypeDefinition:
+		return d.Name
+	case *ast.UnionTypeDefinition:
+		return d.Name
+	case *ast.EnumTypeDefinition:
+		return d.Name
+	case *ast.InputObjectTypeDefinition:
+		return d.Name
+	}
+	return nil
+}
+
+// newType returns type of type definition or nil for other definitions.
+func newType(def ast.Definition) *Type {
+	var t *Type
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		t = &Type{Kind: KindScalar, Name: d.Name.Value, Description: description(d.Description), Directives: d.Directives}
+	case *ast.ObjectTypeDefinition:
+		t = &Type{Kind: KindObject, Name: d.Name.Value, Description: description(d.Description), Directives: d.Directives,
+			Interfaces: names(d.Interfaces), Fields: fields(d.Fields)}
+	case *ast.InterfaceTypeDefinition:
+		t = &Type{Kind: KindInterface, Name: d.Name.Value, Description: description(d.Description), Directives: d.Directives,
+			Interfaces: names(d.Interfaces), Fields: fields(d.Fields)}
+	case *ast.UnionTypeDefinition:
+		t = &Type{Kind: KindUnion, Name: d.Name.Value, Description: description(d.Description), Directives: d.Directives,
+			Types: names(d.Types)}
+	case *ast.EnumTypeDefinition:
+		t = &Type{Kind: KindEnum, Name: d.Name.Value, Description: description(d.Description), Directives: d.Directives,
+			EnumValues: enumValues(d.Values)}
+	case *ast.InputObjectTypeDefinition:
+		t = &Type{Kind: KindInputObject, Name: d.Name.Value, Description: description(d.Description), Directives: d.Directives,
+			InputFields: inputValues(d.Fields)}
+	default:
+		return nil
+	}
+	t.Definition = def
+	return t
+}
+
+// extensionAsDefinition returns definition with members of type extension
+// so it can be converted by newType.
+func extensionAsDefinition(def ast.Definition) ast.Definition {
+	switch d := def.(type) {
+	case *ast.ScalarTypeExtension:
+		return &ast.ScalarTypeDefinition{Position: d.Position, EndPosition: d.EndPosition, Name: d.Name, Directives: d.Directives}
+	case *ast.ObjectTypeExtension:
+		return &ast.ObjectTypeDefinition{Position: d.Position, EndPosition: d.EndPosition, Name: d.Name, Interfaces: d.Interfaces, Directives: d.Directives, Fields: d.Fields}
+	case *ast.InterfaceTypeExtension:
+		return &ast.InterfaceTypeDefinition{Position: d.Position, EndPosition: d.EndPosition, Name: d.Name, Interfaces: d.Interfaces, Directives: d.Directives, Fields: d.Fields}
+	case *ast.UnionTypeExtension:
+		return &ast.UnionTypeDefinition{Position: d.Position, EndPosition: d.EndPosition, Name: d.Name, Directives: d.Directives, Types: d.Types}
+	case *ast.EnumTypeExtension:
+		return &ast.EnumTypeDefinition{Position: d.Position, EndPosition: d.EndPosition, Name: d.Name, Directives: d.Directives, Values: d.Values}
+	case *ast.InputObjectTypeExtension:
+		return &ast.InputObjectTypeDefinition{Position: d.Position, EndPosition: d.EndPosition, Name: d.Name, Directives: d.Directives, Fields: d.Fields}
+	}
+	return nil
+}
+
+func description(d *ast.Description) string {
+	if d == nil {
+		return ""
+	}
+	return d.Value
+}
+
+func names(types []*ast.NamedType) []string {
+	result := make([]string, len(types))
+	for i, t := range types {
+		result[i] = t.Name.Value
+	}
+	return result
+}
+
+func fields(defs []*ast.FieldDefinition) []*Field {
+	result := make([]*Field, len(defs))
+	for i, def := range defs {
+		result[i] = &Field{
+			Name:        def.Name.Value,
+			Description: description(def.Description),
+			Arguments:   inputValues(def.Arguments),
+			Type:        def.Type,
+			Directives:  def.Directives,
+			Definition:  def,
+		}
+	}
+	return result
+}
+
+func inputValues(defs []*ast.InputValueDefinition) []*InputValue {
+	result := make([]*InputValue, len(defs))
+	for i, def := range defs {
+		result[i] = &InputValue{
+			Name:         def.Name.Value,
+			Description:  description(def.Description),
+			Type:         def.Type,
+			DefaultValue: def.DefaultValue,
+			Directives:   def.Directives,
+			Definition:   def,
+		}
+	}
+	return result
+}
+
+func enumValues(defs []*ast.EnumValueDefinition) []*EnumValue {
+	result := make([]*EnumValue, len(defs))
+	for i, def := range defs {
+		result[i] = &EnumValue{
+			Name:        def.Name.Value,
+			Description: description(def.Description),
+			Directives:  def.Directives,
+			Definition:  def,
+		}
+	}
+	return result
+}
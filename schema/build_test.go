@@ -0,0 +1,291 @@
+package schema_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+func TestFromDocument(t *testing.T) {
+	s := asttest.Schema(t, `
+"Root"
+schema @a { query: Root }
+extend schema @b { mutation: Mutation }
+type Root implements Node { "Identifier" id: ID! search(term: String = "x"): [Result!] }
+extend type Root @c { extra: Int }
+type Mutation { noop: Boolean }
+interface Node { id: ID! }
+union Result = Root
+extend union Result = Mutation
+enum Role { ADMIN }
+extend enum Role { EDITOR }
+input Filter { role: Role }
+extend input Filter { limit: Int }
+scalar Date
+directive @c(reason: String) repeatable on OBJECT | FIELD_DEFINITION
+`)
+
+	if s.Description != "Root" || len(s.Directives) != 2 {
+		t.Errorf("unexpected schema: %+v", s)
+	}
+	if s.QueryType() != s.Type("Root") || s.MutationType() != s.Type("Mutation") || s.SubscriptionType() != nil {
+		t.Errorf("unexpected root types")
+	}
+	if got := s.TypeNames(); !reflect.DeepEqual(got, []string{"Date", "Filter", "Mutation", "Node", "Result", "Role", "Root"}) {
+		t.Errorf("unexpected type names: %v", got)
+	}
+	if got := s.Types()[0].Name; got != "Root" {
+		t.Errorf("expected types in definition order, got %s first", got)
+	}
+
+	root := s.Type("Root")
+	if root.Kind != schema.KindObject || len(root.Fields) != 3 || len(root.Directives) != 1 || len(root.Extensions) != 1 {
+		t.Errorf("unexpected Root: %+v", root)
+	}
+	if !reflect.DeepEqual(root.Interfaces, []string{"Node"}) || root.Field("id").Description != "Identifier" {
+		t.Errorf("unexpected Root: %+v", root)
+	}
+	if arg := root.Field("search").Argument("term"); arg == nil || arg.DefaultValue == nil {
+		t.Errorf("expected search(term:) with default value")
+	}
+	if got := s.Type("Result").Types; !reflect.DeepEqual(got, []string{"Root", "Mutation"}) {
+		t.Errorf("unexpected union members: %v", got)
+	}
+	if s.Type("Role").EnumValue("EDITOR") == nil || s.Type("Filter").InputField("limit") == nil {
+		t.Errorf("expected extension members to be merged")
+	}
+
+	d := s.Directive("c")
+	if d == nil || !d.Repeatable || d.Argument("reason") == nil ||
+		!reflect.DeepEqual(d.Locations, []ast.DirectiveLocation{ast.DirectiveLocationObject, ast.DirectiveLocationFieldDefinition}) {
+		t.Errorf("unexpected directive: %+v", d)
+	}
+	if len(s.DirectiveDefinitions()) != 1 {
+		t.Errorf("unexpected directive definitions")
+	}
+}
+
+func TestFromDocument_DefaultRoots(t *testing.T) {
+	s := asttest.Schema(t, `type Query { a: Int } type Subscription { b: Int }`)
+	if s.QueryType().Name != "Query" || s.SubscriptionType().Name != "Subscription" || s.MutationType() != nil {
+		t.Errorf("unexpected root types")
+	}
+}
+
+func TestFromDocument_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"duplicate schema", `schema { query: Q } schema { query: Q } type Q { a: Int }`, "schema definition is defined more than once"},
+		{"executable definition", `type Q { a: Int } { a }`, "executable definitions are not allowed in schema document"},
+		{"extend undefined", `extend type Q { a: Int }`, `cannot extend undefined type "Q"`},
+		{"extend other kind", `type Q { a: Int } extend input Q { b: Int }`, `cannot extend OBJECT type "Q" with INPUT_OBJECT extension`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := schema.FromDocument(asttest.Parse(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestFromDocument_References(t *testing.T) {
+	input := `schema { query: Q mutation: In subscription: Missing }
+type Q implements U { a: In b(x: Q): Int c: [Unknown!] d(y: [String!]): E }
+union U = Q | I
+extend union U = Nope
+interface I implements Q { a: Int }
+input In { q: Q e: E }
+enum E { V }
+directive @d(a: U) on FIELD`
+	_, err := schema.FromDocument(asttest.Parse(t, input))
+	var errs schema.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+	expected := []string{
+		`root mutation type "In" is not an object type`,
+		`root subscription type "Missing" is not defined`,
+		`type Q: implemented type "U" is not an interface`,
+		`field Q.a: type "In" is not an output type`,
+		`argument Q.b(x:): type "Q" is not an input type`,
+		`field Q.c: unknown type "Unknown"`,
+		`union U: member "I" is not an object type`,
+		`type U: unknown type "Nope"`,
+		`type I: implemented type "Q" is not an interface`,
+		`input field In.q: type "Q" is not an input type`,
+		`argument @d(a:): type "U" is not an input type`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range errs {
+		if e.Message != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], e.Message)
+		}
+	}
+	if errs[0].Positions[0] != strings.Index(input, "In ") {
+		t.Errorf("expected error at root type reference, got %v", errs[0].Positions)
+	}
+}
+
+func TestSchema_Lookups(t *testing.T) {
+	s := asttest.Schema(t, `
+type Query { node: Node old: Int @deprecated legacy: Int @deprecated(reason: "Use node.") }
+interface Node { id: ID! }
+type User implements Node { id: ID! }
+type Group implements Node { id: ID! }
+union Entity = Group | User
+scalar URL @specifiedBy(url: "https://url.spec.whatwg.org/")
+input Key @oneOf { id: ID name: String }
+enum Role { ADMIN GUEST @deprecated }
+`)
+	q := s.QueryType()
+	if got := s.NamedType(q.Field("node").Type); got != s.Type("Node") {
+		t.Errorf("expected Node, got %v", got)
+	}
+	if s.NamedType(&ast.NonNullType{Type: &ast.NamedType{Name: &ast.Name{Value: "ID"}}}) != nil {
+		t.Errorf("expected undefined built-in scalar not to resolve")
+	}
+	possible := func(name string) []string {
+		var names []string
+		for _, t := range s.PossibleTypes(s.Type(name)) {
+			names = append(names, t.Name)
+		}
+		return names
+	}
+	if got := possible("Node"); !reflect.DeepEqual(got, []string{"User", "Group"}) {
+		t.Errorf("unexpected implementations: %v", got)
+	}
+	if got := possible("Entity"); !reflect.DeepEqual(got, []string{"Group", "User"}) {
+		t.Errorf("unexpected members: %v", got)
+	}
+	if !s.Type("Entity").IsAbstract() || s.Type("User").IsAbstract() || !s.Type("Key").IsInputType() || s.Type("Key").IsOutputType() {
+		t.Errorf("unexpected type classification")
+	}
+
+	if _, ok := q.Field("node").Deprecated(); ok {
+		t.Errorf("expected node not to be deprecated")
+	}
+	if reason, ok := q.Field("old").Deprecated(); !ok || reason != "No longer supported" {
+		t.Errorf("unexpected deprecation of old: %q, %v", reason, ok)
+	}
+	if reason, ok := q.Field("legacy").Deprecated(); !ok || reason != "Use node." {
+		t.Errorf("unexpected deprecation of legacy: %q, %v", reason, ok)
+	}
+	if _, ok := s.Type("Role").EnumValue("GUEST").Deprecated(); !ok {
+		t.Errorf("expected GUEST to be deprecated")
+	}
+	if got := s.Type("URL").SpecifiedByURL(); got != "https://url.spec.whatwg.org/" {
+		t.Errorf("unexpected specifiedBy URL %q", got)
+	}
+	if !s.Type("Key").IsOneOf() {
+		t.Errorf("expected Key to be oneOf")
+	}
+}
+
+func TestFromDocument_ReservedNames(t *testing.T) {
+	input := `
+type __Type { name: String }
+type Query {
+  __schema: __Schema!
+  __custom(__arg: Int): Int
+}
+type __Schema { types: [__Type!]! }
+enum __TypeKind { SCALAR }
+enum Kind { __HIDDEN }
+input __Filter { __f: Int }
+directive @__internal on FIELD_DEFINITION
+`
+	tests := []struct {
+		policy   schema.NamePolicy
+		expected []string
+	}{
+		{schema.ReserveNames, []string{
+			`type __Type: name "__Type" must not begin with "__"`,
+			`field Query.__schema: name "__schema" must not begin with "__"`,
+			`field Query.__custom: name "__custom" must not begin with "__"`,
+			`argument Query.__custom(__arg:): name "__arg" must not begin with "__"`,
+			`type __Schema: name "__Schema" must not begin with "__"`,
+			`type __TypeKind: name "__TypeKind" must not begin with "__"`,
+			`enum value Kind.__HIDDEN: name "__HIDDEN" must not begin with "__"`,
+			`type __Filter: name "__Filter" must not begin with "__"`,
+			`input field __Filter.__f: name "__f" must not begin with "__"`,
+			`directive @__internal: name "__internal" must not begin with "__"`,
+		}},
+		{schema.AllowIntrospectionNames, []string{
+			`field Query.__custom: name "__custom" must not begin with "__"`,
+			`argument Query.__custom(__arg:): name "__arg" must not begin with "__"`,
+			`enum value Kind.__HIDDEN: name "__HIDDEN" must not begin with "__"`,
+			`type __Filter: name "__Filter" must not begin with "__"`,
+			`input field __Filter.__f: name "__f" must not begin with "__"`,
+			`directive @__internal: name "__internal" must not begin with "__"`,
+		}},
+		{schema.AllowReservedNames, nil},
+	}
+	for _, tt := range tests {
+		_, err := schema.FromDocument(asttest.Parse(t, input), schema.WithNamePolicy(tt.policy))
+		var errs schema.Errors
+		if err != nil && !errors.As(err, &errs) {
+			t.Fatalf("expected Errors, got %T", err)
+		}
+		if len(errs) != len(tt.expected) {
+			t.Fatalf("policy %d: expected %d errors, got %v", tt.policy, len(tt.expected), errs)
+		}
+		for i, e := range errs {
+			if !strings.HasPrefix(e.Message, tt.expected[i]) {
+				t.Errorf("policy %d: expected %q, got %q", tt.policy, tt.expected[i], e.Message)
+			}
+		}
+	}
+
+	_, err := schema.FromDocument(asttest.Parse(t, "type Query {\n  __x: Int\n}"))
+	var errs schema.Errors
+	if !errors.As(err, &errs) || !reflect.DeepEqual(errs[0].Positions, []int{15}) {
+		t.Errorf("expected error at name position, got %v", err)
+	}
+}
+
+func TestFromDocument_Duplicates(t *testing.T) {
+	input := `type A { f: Int g(x: Int, x: Int): Int }
+extend type A { f: Int }
+enum E { V V }
+input I { f: Int f: Int }
+type A { h: Int }
+directive @d(a: Int a: Int) on FIELD
+directive @d on FIELD`
+	_, err := schema.FromDocument(asttest.Parse(t, input))
+	var errs schema.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+	expected := []struct {
+		message   string
+		positions []int
+	}{
+		{`type "A" is defined more than once`, []int{5, 112}},
+		{`directive @d is defined more than once`, []int{136, 173}},
+		{`argument A.g(x:) is defined more than once`, []int{18, 26}},
+		{`field A.f is defined more than once`, []int{9, 57}},
+		{`enum value E.V is defined more than once`, []int{75, 77}},
+		{`input field I.f is defined more than once`, []int{91, 98}},
+		{`argument @d(a:) is defined more than once`, []int{138, 145}},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range errs {
+		if e.Message != expected[i].message || !reflect.DeepEqual(e.Positions, expected[i].positions) {
+			t.Errorf("expected %s %v, got %s %v", expected[i].message, expected[i].positions, e.Message, e.Positions)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a type system error. Positions are byte offsets of offending
+// nodes in the source document.
+type Error struct {
+	Message   string
+	Positions []int
+}
+
+func (e *Error) Error() string {
+	if len(e.Positions) == 0 {
+		return e.Message
+	}
+	positions := make([]string, len(e.Positions))
+	for i, pos := range e.Positions {
+		positions[i] = fmt.Sprint(pos)
+	}
+	return fmt.Sprintf("%s (at %s)", e.Message, strings.Join(positions, ", "))
+}
+
+// Errors is a list of type system errors returned by FromDocument.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+}
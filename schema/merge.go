@@ -0,0 +1,337 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asthash"
+)
+
+// Merge merges SDL documents, e.g. of a schema split into modules, into a
+// single document without type extensions. A type may be defined in more
+// than one document and extended in any of them: its members are merged
+// by name, and members defined more than once must agree on type,
+// arguments and default values. Descriptions of the first definition
+// win; directives are merged leaving out duplicates. Directive
+// definitions must be identical but for descriptions.
+//
+// Definitions are ordered by first appearance. Their nodes are shared
+// with docs, which are not modified, and positions refer to the
+// documents they come from. Errors are returned as Errors naming
+// documents by number; build the result with FromDocument to check it.
+func Merge(docs ...*ast.Document) (*ast.Document, error) {
+	m := &merger{
+		types:      make(map[string]*mergedType),
+		directives: make(map[string]*mergedDirective),
+	}
+	var extensions []docDefinition
+	for i, doc := range docs {
+		for _, def := range doc.Definitions {
+			if isExtension(def) {
+				extensions = append(extensions, docDefinition{i, def})
+				continue
+			}
+			m.define(i, def)
+		}
+	}
+	for _, ext := range extensions {
+		m.extend(ext.doc, ext.def)
+	}
+	if len(m.errs) > 0 {
+		return nil, m.errs
+	}
+	if m.schemaDef == nil && m.schemaExt != nil {
+		m.defs = append(m.defs, m.schemaExt)
+	}
+	return &ast.Document{Definitions: m.defs}, nil
+}
+
+type docDefinition struct {
+	doc int
+	def ast.Definition
+}
+
+type merger struct {
+	defs       []ast.Definition
+	schemaDef  *ast.SchemaDefinition
+	schemaDoc  int
+	schemaExt  *ast.SchemaExtension // Extensions of schema without definition.
+	types      map[string]*mergedType
+	directives map[string]*mergedDirective
+	errs       Errors
+}
+
+// mergedType is a copy of type definition with members merged into it.
+// Origins are documents members were first defined in, by coordinate.
+type mergedType struct {
+	def     ast.Definition
+	doc     int
+	origins map[string]int
+}
+
+type mergedDirective struct {
+	def *ast.DirectiveDefinition
+	doc int
+}
+
+// conflict reports element of document doc conflicting with its first
+// definition.
+func (m *merger) conflict(what string, doc int, n ast.Node, firstDoc int, first ast.Node) {
+	m.errs = append(m.errs, &Error{
+		Message:   fmt.Sprintf("%s in document %d conflicts with its definition in document %d", what, doc+1, firstDoc+1),
+		Positions: []int{first.Pos(), n.Pos()},
+	})
+}
+
+func (m *merger) errorf(doc, pos int, format string, args ...any) {
+	m.errs = append(m.errs, &Error{Message: fmt.Sprintf("document %d: ", doc+1) + fmt.Sprintf(format, args...), Positions: []int{pos}})
+}
+
+func (m *merger) define(doc int, def ast.Definition) {
+	switch d := def.(type) {
+	case *ast.SchemaDefinition:
+		if m.schemaDef != nil {
+			m.errorf(doc, d.Pos(), "schema definition is defined more than once, first in document %d", m.schemaDoc+1)
+			return
+		}
+		m.schemaDef = &ast.SchemaDefinition{
+			Position:          d.Position,
+			EndPosition:       d.EndPosition,
+			Description:       d.Description,
+			Directives:        clone(d.Directives),
+			RootOperationDefs: clone(d.RootOperationDefs),
+		}
+		m.schemaDoc = doc
+		m.defs = append(m.defs, m.schemaDef)
+	case *ast.DirectiveDefinition:
+		first, ok := m.directives[d.Name.Value]
+		if !ok {
+			m.directives[d.Name.Value] = &mergedDirective{def: d, doc: doc}
+			m.defs = append(m.defs, d)
+			return
+		}
+		if directiveHash(first.def) != directiveHash(d) {
+			m.conflict("directive @"+d.Name.Value, doc, d, first.doc, first.def)
+		}
+	case *ast.OperationDefinition, *ast.FragmentDefinition:
+		m.errorf(doc, d.Pos(), "executable definitions are not allowed in schema document")
+	default:
+		name := typeName(def)
+		t, ok := m.types[name.Value]
+		if !ok {
+			t = &mergedType{def: copyDefinition(def), doc: doc, origins: make(map[string]int)}
+			m.types[name.Value] = t
+			m.defs = append(m.defs, t.def)
+			m.mergeMembers(t, doc, def)
+			return
+		}
+		if kind, other := newType(t.def).Kind, newType(def).Kind; kind != other {
+			m.errorf(doc, name.Pos(), "type %q is defined as %s, but as %s in document %d", name.Value, other, kind, t.doc+1)
+			return
+		}
+		m.mergeMembers(t, doc, def)
+	}
+}
+
+func (m *merger) extend(doc int, def ast.Definition) {
+	if ext, ok := def.(*ast.SchemaExtension); ok {
+		if m.schemaDef != nil {
+			m.schemaDef.Directives = mergeDirectives(m.schemaDef.Directives, ext.Directives)
+			m.schemaDef.RootOperationDefs = m.mergeRoots(doc, m.schemaDef.RootOperationDefs, ext.RootOperationDefs)
+			return
+		}
+		if m.schemaExt == nil {
+			m.schemaExt = &ast.SchemaExtension{Position: ext.Position, EndPosition: ext.EndPosition}
+		}
+		m.schemaExt.Directives = mergeDirectives(m.schemaExt.Directives, ext.Directives)
+		m.schemaExt.RootOperationDefs = m.mergeRoots(doc, m.schemaExt.RootOperationDefs, ext.RootOperationDefs)
+		return
+	}
+
+	asDef := extensionAsDefinition(def)
+	name := typeName(asDef)
+	t, ok := m.types[name.Value]
+	if !ok {
+		m.errorf(doc, def.Pos(), "cannot extend undefined type %q", name.Value)
+		return
+	}
+	if kind, other := newType(t.def).Kind, newType(asDef).Kind; kind != other {
+		m.errorf(doc, def.Pos(), "cannot extend %s type %q with %s extension", kind, name.Value, other)
+		return
+	}
+	m.mergeMembers(t, doc, asDef)
+}
+
+func (m *merger) mergeRoots(doc int, roots, add []*ast.RootOperationTypeDefinition) []*ast.RootOperationTypeDefinition {
+	for _, r := range add {
+		i := indexOf(roots, func(other *ast.RootOperationTypeDefinition) bool { return other.OperationType == r.OperationType })
+		switch {
+		case i < 0:
+			roots = append(roots, r)
+		case roots[i].Type.Name.Value != r.Type.Name.Value:
+			m.errorf(doc, r.Pos(), "%s root type %q differs from %q", r.OperationType, r.Type.Name.Value, roots[i].Type.Name.Value)
+		}
+	}
+	return roots
+}
+
+// mergeMembers merges directives and members of type definition def of
+// document doc into t.
+func (m *merger) mergeMembers(t *mergedType, doc int, def ast.Definition) {
+	name := typeName(t.def).Value
+	fields := func(into []*ast.FieldDefinition, add []*ast.FieldDefinition) []*ast.FieldDefinition {
+		for _, f := range add {
+			into = mergeMember(m, t, doc, "field "+name+"."+f.Name.Value, into, f, fieldHash, func(f *ast.FieldDefinition) string { return f.Name.Value })
+		}
+		return into
+	}
+	inputFields := func(into []*ast.InputValueDefinition, add []*ast.InputValueDefinition) []*ast.InputValueDefinition {
+		for _, f := range add {
+			into = mergeMember(m, t, doc, "input field "+name+"."+f.Name.Value, into, f, inputValueHash, func(f *ast.InputValueDefinition) string { return f.Name.Value })
+		}
+		return into
+	}
+
+	switch into := t.def.(type) {
+	case *ast.ScalarTypeDefinition:
+		src := def.(*ast.ScalarTypeDefinition)
+		into.Directives = mergeDirectives(into.Directives, src.Directives)
+	case *ast.ObjectTypeDefinition:
+		src := def.(*ast.ObjectTypeDefinition)
+		into.Directives = mergeDirectives(into.Directives, src.Directives)
+		into.Interfaces = mergeNamedTypes(into.Interfaces, src.Interfaces)
+		into.Fields = fields(into.Fields, src.Fields)
+	case *ast.InterfaceTypeDefinition:
+		src := def.(*ast.InterfaceTypeDefinition)
+		into.Directives = mergeDirectives(into.Directives, src.Directives)
+		into.Interfaces = mergeNamedTypes(into.Interfaces, src.Interfaces)
+		into.Fields = fields(into.Fields, src.Fields)
+	case *ast.UnionTypeDefinition:
+		src := def.(*ast.UnionTypeDefinition)
+		into.Directives = mergeDirectives(into.Directives, src.Directives)
+		into.Types = mergeNamedTypes(into.Types, src.Types)
+	case *ast.EnumTypeDefinition:
+		src := def.(*ast.EnumTypeDefinition)
+		into.Directives = mergeDirectives(into.Directives, src.Directives)
+		for _, v := range src.Values {
+			into.Values = mergeMember(m, t, doc, "enum value "+name+"."+v.Name.Value, into.Values, v,
+				enumValueHash, func(v *ast.EnumValueDefinition) string { return v.Name.Value })
+		}
+	case *ast.InputObjectTypeDefinition:
+		src := def.(*ast.InputObjectTypeDefinition)
+		into.Directives = mergeDirectives(into.Directives, src.Directives)
+		into.Fields = inputFields(into.Fields, src.Fields)
+	}
+}
+
+// mergeMember appends member to members unless a member of the same name
+// is already there, which must have the same hash.
+func mergeMember[T ast.Node](m *merger, t *mergedType, doc int, what string, members []T, member T, hash func(T) asthash.Sum, name func(T) string) []T {
+	i := indexOf(members, func(other T) bool { return name(other) == name(member) })
+	if i < 0 {
+		t.origins[what] = doc
+		return append(members, member)
+	}
+	if hash(members[i]) != hash(member) {
+		m.conflict(what, doc, member, t.origins[what], members[i])
+	}
+	return members
+}
+
+// copyDefinition returns copy of type definition without directives and
+// members, which are merged into it, so merging does not modify the
+// source document.
+func copyDefinition(def ast.Definition) ast.Definition {
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		c := *d
+		c.Directives = nil
+		return &c
+	case *ast.ObjectTypeDefinition:
+		c := *d
+		c.Directives, c.Interfaces, c.Fields = nil, nil, nil
+		return &c
+	case *ast.InterfaceTypeDefinition:
+		c := *d
+		c.Directives, c.Interfaces, c.Fields = nil, nil, nil
+		return &c
+	case *ast.UnionTypeDefinition:
+		c := *d
+		c.Directives, c.Types = nil, nil
+		return &c
+	case *ast.EnumTypeDefinition:
+		c := *d
+		c.Directives, c.Values = nil, nil
+		return &c
+	case *ast.InputObjectTypeDefinition:
+		c := *d
+		c.Directives, c.Fields = nil, nil
+		return &c
+	}
+	return nil
+}
+
+func isExtension(def ast.Definition) bool {
+	_, ok := def.(ast.TypeSystemExtension)
+	return ok
+}
+
+// mergeDirectives appends applied directives leaving out exact duplicates.
+func mergeDirectives(into, add []*ast.Directive) []*ast.Directive {
+	for _, d := range add {
+		sum := asthash.Hash(d)
+		if indexOf(into, func(other *ast.Directive) bool { return asthash.Hash(other) == sum }) < 0 {
+			into = append(into, d)
+		}
+	}
+	return into
+}
+
+func mergeNamedTypes(into, add []*ast.NamedType) []*ast.NamedType {
+	for _, t := range add {
+		if indexOf(into, func(other *ast.NamedType) bool { return other.Name.Value == t.Name.Value }) < 0 {
+			into = append(into, t)
+		}
+	}
+	return into
+}
+
+// fieldHash hashes type and arguments of field definition.
+func fieldHash(f *ast.FieldDefinition) asthash.Sum {
+	stripped := &ast.FieldDefinition{Name: f.Name, Type: f.Type}
+	for _, arg := range f.Arguments {
+		stripped.Arguments = append(stripped.Arguments, &ast.InputValueDefinition{Name: arg.Name, Type: arg.Type, DefaultValue: arg.DefaultValue})
+	}
+	return asthash.Hash(stripped)
+}
+
+// inputValueHash hashes type and default value of input value definition.
+func inputValueHash(v *ast.InputValueDefinition) asthash.Sum {
+	return asthash.Hash(&ast.InputValueDefinition{Name: v.Name, Type: v.Type, DefaultValue: v.DefaultValue})
+}
+
+// enumValueHash hashes nothing, as enum values of the same name never
+// conflict.
+func enumValueHash(*ast.EnumValueDefinition) asthash.Sum {
+	return asthash.Sum{}
+}
+
+// directiveHash hashes directive definition without its description.
+func directiveHash(d *ast.DirectiveDefinition) asthash.Sum {
+	c := *d
+	c.Description = nil
+	return asthash.Hash(&c)
+}
+
+func clone[T any](s []T) []T {
+	return append([]T(nil), s...)
+}
+
+func indexOf[T any](s []T, match func(T) bool) int {
+	for i, v := range s {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
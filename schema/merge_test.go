@@ -0,0 +1,144 @@
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+func TestMerge(t *testing.T) {
+	users := `
+schema { query: Query }
+directive @auth(role: String) on FIELD_DEFINITION
+"Users root."
+type Query { user(id: ID!): User @auth }
+type User implements Node { id: ID! name: String }
+interface Node { id: ID! }
+enum Role { ADMIN }`
+	posts := `
+"Posts root."
+type Query { user(id: ID!): User @auth posts(first: Int = 10): [Post!]! }
+"Duplicate with another description."
+directive @auth(role: String) on FIELD_DEFINITION
+type Post implements Node { id: ID! author: User }
+extend type User @key { posts: [Post!]! }
+extend enum Role { EDITOR }
+extend schema { mutation: Mutation }
+type Mutation { publish: Post }`
+	docs := []*ast.Document{asttest.Parse(t, users), asttest.Parse(t, posts)}
+	before := printer.PrintDocument(docs[0]) + printer.PrintDocument(docs[1])
+
+	merged, err := schema.Merge(docs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `schema {
+  query: Query
+  mutation: Mutation
+}
+
+directive @auth(role: String) on FIELD_DEFINITION
+
+"Users root."
+type Query {
+  user(id: ID!): User @auth
+  posts(first: Int = 10): [Post!]!
+}
+
+type User implements Node @key {
+  id: ID!
+  name: String
+  posts: [Post!]!
+}
+
+interface Node {
+  id: ID!
+}
+
+enum Role {
+  ADMIN
+  EDITOR
+}
+
+type Post implements Node {
+  id: ID!
+  author: User
+}
+
+type Mutation {
+  publish: Post
+}`
+	if got := printer.PrintDocument(merged); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if printer.PrintDocument(docs[0])+printer.PrintDocument(docs[1]) != before {
+		t.Errorf("expected documents to be unmodified")
+	}
+	if _, err := schema.FromDocument(merged); err != nil {
+		t.Errorf("unexpected error building merged document: %v", err)
+	}
+}
+
+func TestMerge_SchemaExtension(t *testing.T) {
+	merged, err := schema.Merge(asttest.Parse(t, `type Query { a: Int } extend schema @a`), asttest.Parse(t, `extend schema @b { query: Query }`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "type Query {\n  a: Int\n}\n\nextend schema @a @b {\n  query: Query\n}"
+	if got := printer.PrintDocument(merged); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestMerge_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		docs     []string
+		expected []string
+	}{
+		{"Field type", []string{`type T { a: Int }`, `type T { a: String }`},
+			[]string{"field T.a in document 2 conflicts with its definition in document 1"}},
+		{"Field arguments", []string{`type T { a(x: Int): Int }`, `extend type T { a(x: Int = 1): Int }`},
+			[]string{"field T.a in document 2 conflicts with its definition in document 1"}},
+		{"Input field", []string{`input I { a: Int }`, `input I { a: Int! }`},
+			[]string{"input field I.a in document 2 conflicts with its definition in document 1"}},
+		{"Kind", []string{`type T { a: Int }`, `enum T { A }`},
+			[]string{`document 2: type "T" is defined as ENUM, but as OBJECT in document 1`}},
+		{"Directive", []string{`directive @d on FIELD`, `directive @d repeatable on FIELD`},
+			[]string{"directive @d in document 2 conflicts with its definition in document 1"}},
+		{"Schema", []string{`schema { query: Q }`, `schema { query: Q }`},
+			[]string{"document 2: schema definition is defined more than once, first in document 1"}},
+		{"Root type", []string{`schema { query: Q }`, `extend schema { query: R }`},
+			[]string{`document 2: query root type "R" differs from "Q"`}},
+		{"Undefined type", []string{`type Query { a: Int }`, `extend type T { a: Int }`},
+			[]string{`document 2: cannot extend undefined type "T"`}},
+		{"Extension kind", []string{`type T { a: Int }`, `extend enum T { A }`},
+			[]string{`document 2: cannot extend OBJECT type "T" with ENUM extension`}},
+		{"Executable", []string{`{ a }`},
+			[]string{"document 1: executable definitions are not allowed in schema document"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var docs []*ast.Document
+			for _, input := range tt.docs {
+				docs = append(docs, asttest.Parse(t, input))
+			}
+			_, err := schema.Merge(docs...)
+			errs, ok := err.(schema.Errors)
+			if !ok {
+				t.Fatalf("expected Errors, got %v", err)
+			}
+			var messages []string
+			for _, e := range errs {
+				messages = append(messages, e.Message)
+			}
+			if got, expected := strings.Join(messages, "\n"), strings.Join(tt.expected, "\n"); got != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+			}
+		})
+	}
+}
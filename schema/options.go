@@ -0,0 +1,61 @@
+package schema
+
+import "github.com/gqlhub/gqlhub-core/provenance"
+
+// Option configures FromDocument.
+type Option func(*builder)
+
+// NamePolicy governs names starting with "__", which GraphQL reserves for
+// the introspection system.
+//
+// https://spec.graphql.org/draft/#sec-Names.Reserved-Names
+type NamePolicy int
+
+const (
+	// ReserveNames rejects every user defined name starting with "__".
+	ReserveNames NamePolicy = iota
+
+	// AllowIntrospectionNames additionally accepts types of the
+	// introspection system (__Schema, __Type, ...) and the __typename,
+	// __schema and __type fields, for tooling that manufactures meta types
+	// in SDL. Other reserved names are rejected.
+	AllowIntrospectionNames
+
+	// AllowReservedNames accepts all reserved names.
+	AllowReservedNames
+)
+
+// WithNamePolicy sets policy of reserved names. Default is ReserveNames.
+func WithNamePolicy(policy NamePolicy) Option {
+	return func(b *builder) {
+		b.namePolicy = policy
+	}
+}
+
+// WithProvenance attaches provenance metadata to built schema, so servers
+// can report which schema they serve, e.g. in extensions of introspection
+// responses.
+func WithProvenance(p provenance.Provenance) Option {
+	return func(b *builder) {
+		b.schema.Provenance = p
+	}
+}
+
+// introspectionTypes are names of types of the introspection system.
+var introspectionTypes = map[string]bool{
+	"__Schema":            true,
+	"__Type":              true,
+	"__TypeKind":          true,
+	"__Field":             true,
+	"__InputValue":        true,
+	"__EnumValue":         true,
+	"__Directive":         true,
+	"__DirectiveLocation": true,
+}
+
+// metaFields are names of fields implicitly available on types.
+var metaFields = map[string]bool{
+	"__typename": true,
+	"__schema":   true,
+	"__type":     true,
+}
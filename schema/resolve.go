@@ -0,0 +1,243 @@
+package schema
+
+import "github.com/gqlhub/gqlhub-core/ast"
+
+// builtInScalars are scalars every schema has. They may be referenced
+// without being defined.
+var builtInScalars = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// checkReferences reports references to undefined types and types of the
+// wrong kind: root types must be objects, implemented types interfaces,
+// union members objects, fields of output types and arguments and input
+// fields of input types.
+func (b *builder) checkReferences() {
+	for _, op := range []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription} {
+		name, ok := b.schema.roots[op]
+		if !ok {
+			continue
+		}
+		if t := b.schema.types[name]; t == nil {
+			b.errorf(b.rootPos[op], "root %s type %q is not defined", op, name)
+		} else if t.Kind != KindObject {
+			b.errorf(b.rootPos[op], "root %s type %q is not an object type", op, name)
+		}
+	}
+	for _, t := range b.schema.Types() {
+		for _, ref := range memberTypes(t) {
+			member := b.schema.types[ref.Name.Value]
+			switch {
+			case member == nil:
+				b.errorf(ref.Pos(), "type %s: unknown type %q", t.Name, ref.Name.Value)
+			case t.Kind == KindUnion && member.Kind != KindObject:
+				b.errorf(ref.Pos(), "union %s: member %q is not an object type", t.Name, member.Name)
+			case t.Kind != KindUnion && member.Kind != KindInterface:
+				b.errorf(ref.Pos(), "type %s: implemented type %q is not an interface", t.Name, member.Name)
+			}
+		}
+		for _, f := range t.Fields {
+			b.checkTypeRef("field", t.Name+"."+f.Name, f.Type, false)
+			for _, arg := range f.Arguments {
+				b.checkTypeRef("argument", t.Name+"."+f.Name+"("+arg.Name+":)", arg.Type, true)
+			}
+		}
+		for _, f := range t.InputFields {
+			b.checkTypeRef("input field", t.Name+"."+f.Name, f.Type, true)
+		}
+	}
+	for _, d := range b.schema.DirectiveDefinitions() {
+		for _, arg := range d.Arguments {
+			b.checkTypeRef("argument", "@"+d.Name+"("+arg.Name+":)", arg.Type, true)
+		}
+	}
+}
+
+// checkTypeRef reports type reference of element with given schema
+// coordinate that is undefined or is not an input or output type.
+func (b *builder) checkTypeRef(what, coordinate string, ref ast.Type, input bool) {
+	named := namedTypeOf(ref)
+	if named == nil {
+		return
+	}
+	name := named.Name.Value
+	if builtInScalars[name] {
+		return
+	}
+	t := b.schema.types[name]
+	switch {
+	case t == nil:
+		b.errorf(named.Pos(), "%s %s: unknown type %q", what, coordinate, name)
+	case input && !t.IsInputType():
+		b.errorf(named.Pos(), "%s %s: type %q is not an input type", what, coordinate, name)
+	case !input && !t.IsOutputType():
+		b.errorf(named.Pos(), "%s %s: type %q is not an output type", what, coordinate, name)
+	}
+}
+
+// memberTypes returns implemented interfaces of object and interface type
+// or members of union type t, from its definition and extensions.
+func memberTypes(t *Type) []*ast.NamedType {
+	var refs []*ast.NamedType
+	for _, def := range append([]ast.Definition{t.Definition}, t.Extensions...) {
+		switch d := def.(type) {
+		case *ast.ObjectTypeDefinition:
+			refs = append(refs, d.Interfaces...)
+		case *ast.ObjectTypeExtension:
+			refs = append(refs, d.Interfaces...)
+		case *ast.InterfaceTypeDefinition:
+			refs = append(refs, d.Interfaces...)
+		case *ast.InterfaceTypeExtension:
+			refs = append(refs, d.Interfaces...)
+		case *ast.UnionTypeDefinition:
+			refs = append(refs, d.Types...)
+		case *ast.UnionTypeExtension:
+			refs = append(refs, d.Types...)
+		}
+	}
+	return refs
+}
+
+func namedTypeOf(t ast.Type) *ast.NamedType {
+	for {
+		switch tt := t.(type) {
+		case *ast.NamedType:
+			return tt
+		case *ast.ListType:
+			t = tt.Type
+		case *ast.NonNullType:
+			t = tt.Type
+		default:
+			return nil
+		}
+	}
+}
+
+// NamedType returns named type type reference t refers to, unwrapping list
+// and non-null types, or nil when it is not defined. Built-in scalars are
+// only returned when the document defines them.
+func (s *Schema) NamedType(t ast.Type) *Type {
+	named := namedTypeOf(t)
+	if named == nil {
+		return nil
+	}
+	return s.types[named.Name.Value]
+}
+
+// IsInputType reports whether values of t can be used as arguments and
+// input fields.
+func (t *Type) IsInputType() bool {
+	return t.Kind == KindScalar || t.Kind == KindEnum || t.Kind == KindInputObject
+}
+
+// IsOutputType reports whether t can be type of a field.
+func (t *Type) IsOutputType() bool {
+	return t.Kind != KindInputObject
+}
+
+// IsAbstract reports whether t is an interface or union.
+func (t *Type) IsAbstract() bool {
+	return t.Kind == KindInterface || t.Kind == KindUnion
+}
+
+// PossibleTypes returns object types t may resolve to: t itself for
+// objects, implementations for interfaces and members for unions, in
+// definition order.
+func (s *Schema) PossibleTypes(t *Type) []*Type {
+	switch t.Kind {
+	case KindObject:
+		return []*Type{t}
+	case KindUnion:
+		var types []*Type
+		for _, name := range t.Types {
+			if member := s.types[name]; member != nil && member.Kind == KindObject {
+				types = append(types, member)
+			}
+		}
+		return types
+	case KindInterface:
+		var types []*Type
+		for _, candidate := range s.Types() {
+			if candidate.Kind != KindObject {
+				continue
+			}
+			for _, name := range candidate.Interfaces {
+				if name == t.Name {
+					types = append(types, candidate)
+					break
+				}
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// deprecation returns reason of @deprecated among directives.
+func deprecation(directives []*ast.Directive) (string, bool) {
+	d := findDirective(directives, "deprecated")
+	if d == nil {
+		return "", false
+	}
+	if reason, ok := stringArgument(d, "reason"); ok {
+		return reason, true
+	}
+	return defaultDeprecationReason, true
+}
+
+// defaultDeprecationReason is default value of @deprecated(reason:).
+const defaultDeprecationReason = "No longer supported"
+
+func findDirective(directives []*ast.Directive, name string) *ast.Directive {
+	for _, d := range directives {
+		if d.Name.Value == name {
+			return d
+		}
+	}
+	return nil
+}
+
+func stringArgument(d *ast.Directive, name string) (string, bool) {
+	for _, arg := range d.Arguments {
+		if arg.Name.Value == name {
+			if s, ok := arg.Value.(*ast.StringValue); ok {
+				return s.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Deprecated returns reason given by @deprecated directive, if applied.
+func (f *Field) Deprecated() (reason string, ok bool) {
+	return deprecation(f.Directives)
+}
+
+// Deprecated returns reason given by @deprecated directive, if applied.
+func (v *InputValue) Deprecated() (reason string, ok bool) {
+	return deprecation(v.Directives)
+}
+
+// Deprecated returns reason given by @deprecated directive, if applied.
+func (v *EnumValue) Deprecated() (reason string, ok bool) {
+	return deprecation(v.Directives)
+}
+
+// SpecifiedByURL returns URL given by @specifiedBy directive of scalar
+// type or empty string.
+func (t *Type) SpecifiedByURL() string {
+	if d := findDirective(t.Directives, "specifiedBy"); d != nil {
+		url, _ := stringArgument(d, "url")
+		return url
+	}
+	return ""
+}
+
+// IsOneOf reports whether input object type has @oneOf directive.
+func (t *Type) IsOneOf() bool {
+	return t.Kind == KindInputObject && findDirective(t.Directives, "oneOf") != nil
+}
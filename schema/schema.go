@@ -0,0 +1,194 @@
+// Package schema builds typed schemas from SDL documents, merging type
+// extensions and checking type system rules.
+package schema
+
+import (
+	"sort"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/provenance"
+)
+
+// Kind of named type.
+type Kind string
+
+const (
+	KindScalar      Kind = "SCALAR"
+	KindObject      Kind = "OBJECT"
+	KindInterface   Kind = "INTERFACE"
+	KindUnion       Kind = "UNION"
+	KindEnum        Kind = "ENUM"
+	KindInputObject Kind = "INPUT_OBJECT"
+)
+
+// Schema is a built GraphQL schema.
+type Schema struct {
+	Description string
+	Directives  []*ast.Directive      // Applied to schema definition and extensions.
+	Provenance  provenance.Provenance // Origin of schema, see WithProvenance.
+
+	types          map[string]*Type
+	directiveDefs  map[string]*Directive
+	roots          map[ast.OperationType]string
+	typeOrder      []string
+	directiveOrder []string
+}
+
+// Type returns named type or nil.
+func (s *Schema) Type(name string) *Type {
+	return s.types[name]
+}
+
+// Types returns named types in definition order.
+func (s *Schema) Types() []*Type {
+	types := make([]*Type, len(s.typeOrder))
+	for i, name := range s.typeOrder {
+		types[i] = s.types[name]
+	}
+	return types
+}
+
+// TypeNames returns sorted names of named types.
+func (s *Schema) TypeNames() []string {
+	names := make([]string, len(s.typeOrder))
+	copy(names, s.typeOrder)
+	sort.Strings(names)
+	return names
+}
+
+// Directive returns directive definition or nil.
+func (s *Schema) Directive(name string) *Directive {
+	return s.directiveDefs[name]
+}
+
+// DirectiveDefinitions returns directive definitions in definition order.
+func (s *Schema) DirectiveDefinitions() []*Directive {
+	defs := make([]*Directive, len(s.directiveOrder))
+	for i, name := range s.directiveOrder {
+		defs[i] = s.directiveDefs[name]
+	}
+	return defs
+}
+
+// RootType returns root type of operation type or nil.
+func (s *Schema) RootType(op ast.OperationType) *Type {
+	return s.types[s.roots[op]]
+}
+
+// QueryType returns query root type or nil.
+func (s *Schema) QueryType() *Type {
+	return s.RootType(ast.OperationTypeQuery)
+}
+
+// MutationType returns mutation root type or nil.
+func (s *Schema) MutationType() *Type {
+	return s.RootType(ast.OperationTypeMutation)
+}
+
+// SubscriptionType returns subscription root type or nil.
+func (s *Schema) SubscriptionType() *Type {
+	return s.RootType(ast.OperationTypeSubscription)
+}
+
+// Type is a named type with its extensions merged.
+type Type struct {
+	Kind        Kind
+	Name        string
+	Description string
+	Directives  []*ast.Directive
+	Interfaces  []string      // OBJECT, INTERFACE
+	Fields      []*Field      // OBJECT, INTERFACE
+	Types       []string      // UNION members
+	EnumValues  []*EnumValue  // ENUM
+	InputFields []*InputValue // INPUT_OBJECT
+
+	Definition ast.Definition   // Definition of the type.
+	Extensions []ast.Definition // Extensions of the type in document order.
+}
+
+// Field returns field of OBJECT or INTERFACE type or nil.
+func (t *Type) Field(name string) *Field {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// InputField returns input field of INPUT_OBJECT type or nil.
+func (t *Type) InputField(name string) *InputValue {
+	for _, f := range t.InputFields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// EnumValue returns value of ENUM type or nil.
+func (t *Type) EnumValue(name string) *EnumValue {
+	for _, v := range t.EnumValues {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// Field of an object or interface type.
+type Field struct {
+	Name        string
+	Description string
+	Arguments   []*InputValue
+	Type        ast.Type
+	Directives  []*ast.Directive
+	Definition  *ast.FieldDefinition
+}
+
+// Argument returns argument or nil.
+func (f *Field) Argument(name string) *InputValue {
+	return findInputValue(f.Arguments, name)
+}
+
+// InputValue is an argument or input field.
+type InputValue struct {
+	Name         string
+	Description  string
+	Type         ast.Type
+	DefaultValue ast.Value // nil when there is no default.
+	Directives   []*ast.Directive
+	Definition   *ast.InputValueDefinition
+}
+
+// EnumValue is a value of enum type.
+type EnumValue struct {
+	Name        string
+	Description string
+	Directives  []*ast.Directive
+	Definition  *ast.EnumValueDefinition
+}
+
+// Directive is a directive definition.
+type Directive struct {
+	Name        string
+	Description string
+	Arguments   []*InputValue
+	Repeatable  bool
+	Locations   []ast.DirectiveLocation
+	Definition  *ast.DirectiveDefinition
+}
+
+// Argument returns argument or nil.
+func (d *Directive) Argument(name string) *InputValue {
+	return findInputValue(d.Arguments, name)
+}
+
+func findInputValue(values []*InputValue, name string) *InputValue {
+	for _, v := range values {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
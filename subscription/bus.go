@@ -0,0 +1,34 @@
+// Package subscription provides helpers for resolvers of subscription
+// fields: an adapter interface of event buses events are received from,
+// filters matching events to subscribers by field arguments, and streams
+// mapping events to field results with backpressure for slow clients:
+//
+//	func (r *Resolver) MessageAdded(ctx context.Context, args map[string]any) (<-chan subscription.Result, error) {
+//		return subscription.Stream(ctx, r.Bus, "messages", subscription.Options{
+//			Filter: subscription.ArgumentFilter(args, map[string]string{"roomId": "room.id"}),
+//		})
+//	}
+package subscription
+
+import "context"
+
+// Event is a payload published to a topic.
+type Event struct {
+	Topic   string
+	Payload any
+}
+
+// Bus is an event bus subscriptions receive events from. Implementations
+// adapt in-process or shared brokers and must be safe for concurrent use.
+type Bus interface {
+	// Publish delivers payload to subscribers of topic. Payloads of buses
+	// crossing processes must be encodable to JSON.
+	Publish(ctx context.Context, topic string, payload any) error
+
+	// Subscribe returns channel of events published to topic after it
+	// returns, in order they were published. The channel is closed when
+	// ctx is done or the bus stops delivering, e.g. on shutdown. Slow
+	// receivers must not block publishers indefinitely; Stream receives
+	// events as they arrive unless its overflow is Block.
+	Subscribe(ctx context.Context, topic string) (<-chan Event, error)
+}
@@ -0,0 +1,164 @@
+package subscription
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Filter reports whether event payload is delivered to a subscriber.
+type Filter func(payload any) bool
+
+// ArgumentFilter returns Filter accepting payloads matching argument
+// values args of a subscription field, e.g. Node.Filter of package
+// projection. Arguments are matched with payload fields of the same name
+// or, when paths is not nil, only arguments in paths are matched with
+// payload fields at dot separated paths, e.g. "roomId": "room.id".
+//
+// Null arguments match any payload, lists match payloads equal to any of
+// their items, and input objects match payload objects whose fields match
+// their fields. Numbers and strings are compared by text, so ID arguments
+// match both forms of IDs. Payloads which are not map[string]any are
+// converted through JSON.
+func ArgumentFilter(args map[string]any, paths map[string]string) Filter {
+	type criterion struct {
+		path  []string
+		value any
+	}
+	var criteria []criterion
+	for name, value := range args {
+		path := name
+		if paths != nil {
+			var ok bool
+			if path, ok = paths[name]; !ok {
+				continue
+			}
+		}
+		if value != nil {
+			criteria = append(criteria, criterion{strings.Split(path, "."), value})
+		}
+	}
+	return func(payload any) bool {
+		if len(criteria) == 0 {
+			return true
+		}
+		fields, ok := object(payload)
+		if !ok {
+			return false
+		}
+		for _, c := range criteria {
+			if !match(c.value, lookup(fields, c.path)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// object returns payload as JSON object.
+func object(payload any) (map[string]any, bool) {
+	if fields, ok := payload.(map[string]any); ok {
+		return fields, true
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var fields map[string]any
+	if err := d.Decode(&fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+func lookup(fields map[string]any, path []string) any {
+	var value any = fields
+	for _, name := range path {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = obj[name]
+	}
+	return value
+}
+
+// match reports whether payload value matches argument value arg.
+func match(arg, value any) bool {
+	switch arg := arg.(type) {
+	case nil:
+		return true
+	case []any:
+		for _, item := range arg {
+			if match(item, value) {
+				return true
+			}
+		}
+		return false
+	case map[string]any:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		for name, field := range arg {
+			if !match(field, obj[name]) {
+				return false
+			}
+		}
+		return true
+	}
+	a, ok := text(arg)
+	if !ok {
+		return false
+	}
+	b, ok := text(value)
+	return ok && a == b
+}
+
+// text returns canonical text of scalar value, the same for equal numbers
+// of any Go type.
+func text(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return strconv.FormatInt(i, 10), true
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return "", false
+		}
+		return float(f), true
+	case int:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float32:
+		return float(float64(v)), true
+	case float64:
+		return float(v), true
+	}
+	return "", false
+}
+
+func float(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,148 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSlowConsumer ends streams of subscribers not keeping up with events
+// with Disconnect overflow.
+var ErrSlowConsumer = errors.New("subscription: consumer too slow")
+
+// defaultBuffer is number of results buffered for a subscriber by
+// default.
+const defaultBuffer = 16
+
+// Overflow is handling of events arriving when buffer of a subscriber is
+// full.
+type Overflow int
+
+const (
+	// DropOldest drops the oldest buffered result, so subscribers catch up
+	// with the latest events.
+	DropOldest Overflow = iota
+	// DropNewest drops the arriving event.
+	DropNewest
+	// Block waits for subscriber to receive, pushing back on the bus,
+	// which may in turn drop events or slow publishers down.
+	Block
+	// Disconnect ends the stream with ErrSlowConsumer.
+	Disconnect
+)
+
+// Options configure Stream.
+type Options struct {
+	// Filter selects events delivered to subscriber; all events are
+	// delivered when nil.
+	Filter Filter
+
+	// Map maps payloads of events to results of the subscription field,
+	// e.g. loads entities events refer to. Errors are delivered as
+	// results, so executors report them for the event and keep the
+	// stream. Payloads are delivered as they are when nil.
+	Map func(ctx context.Context, payload any) (any, error)
+
+	// Buffer is number of results buffered for subscriber, 16 when zero.
+	Buffer int
+
+	// Overflow handles events arriving when buffer is full. Default is
+	// DropOldest.
+	Overflow Overflow
+
+	// OnDrop is called for every event dropped by overflow, e.g. to
+	// count them.
+	OnDrop func()
+}
+
+// Result is an event mapped to result of a subscription field.
+type Result struct {
+	Data any
+	Err  error
+}
+
+// Stream subscribes to topic of bus and returns channel of results of
+// events accepted by Filter, mapped by Map. Events are received as they
+// arrive, unless Overflow is Block, and results are buffered for
+// subscriber. The channel is closed when ctx is done, subscription of bus
+// ends, or, with Disconnect, after ErrSlowConsumer result.
+func Stream(ctx context.Context, bus Bus, topic string, opts Options) (<-chan Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	events, err := bus.Subscribe(ctx, topic)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = defaultBuffer
+	}
+	out := make(chan Result, opts.Buffer)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				if opts.Filter != nil && !opts.Filter(e.Payload) {
+					continue
+				}
+				r := Result{Data: e.Payload}
+				if opts.Map != nil {
+					r.Data, r.Err = opts.Map(ctx, e.Payload)
+				}
+				if !deliver(ctx, out, r, opts) {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// deliver sends r to out, handling overflow, and reports whether stream
+// goes on.
+func deliver(ctx context.Context, out chan Result, r Result, opts Options) bool {
+	select {
+	case out <- r:
+		return true
+	default:
+	}
+	switch opts.Overflow {
+	case DropNewest:
+		dropped(opts)
+		return true
+	case Block:
+		select {
+		case out <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	case Disconnect:
+		dropped(opts)
+		r = Result{Err: ErrSlowConsumer}
+	}
+	// Make room for r, racing with subscriber receiving.
+	for {
+		select {
+		case out <- r:
+			return opts.Overflow != Disconnect
+		default:
+		}
+		select {
+		case <-out:
+			dropped(opts)
+		default:
+		}
+	}
+}
+
+func dropped(opts Options) {
+	if opts.OnDrop != nil {
+		opts.OnDrop()
+	}
+}
@@ -0,0 +1,196 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestArgumentFilter(t *testing.T) {
+	type message struct {
+		Room struct {
+			ID int `json:"id"`
+		} `json:"room"`
+		Kind string `json:"kind"`
+	}
+	var msg message
+	msg.Room.ID, msg.Kind = 7, "TEXT"
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		paths    map[string]string
+		payload  any
+		expected bool
+	}{
+		{"No arguments", nil, nil, "anything", true},
+		{"Equal string", map[string]any{"kind": "TEXT"}, nil, map[string]any{"kind": "TEXT"}, true},
+		{"Different string", map[string]any{"kind": "TEXT"}, nil, map[string]any{"kind": "IMAGE"}, false},
+		{"Missing field", map[string]any{"kind": "TEXT"}, nil, map[string]any{}, false},
+		{"Null argument", map[string]any{"kind": nil}, nil, map[string]any{}, true},
+		{"ID as string", map[string]any{"id": "7"}, nil, map[string]any{"id": 7}, true},
+		{"Numbers", map[string]any{"n": json.Number("7")}, nil, map[string]any{"n": 7.0}, true},
+		{"List", map[string]any{"kind": []any{"IMAGE", "TEXT"}}, nil, map[string]any{"kind": "TEXT"}, true},
+		{"List without match", map[string]any{"kind": []any{"IMAGE"}}, nil, map[string]any{"kind": "TEXT"}, false},
+		{"Input object", map[string]any{"room": map[string]any{"id": "7", "name": nil}}, nil, map[string]any{"room": map[string]any{"id": 7}}, true},
+		{"Paths", map[string]any{"roomId": json.Number("7"), "first": json.Number("10")}, map[string]string{"roomId": "room.id"}, msg, true},
+		{"Paths without match", map[string]any{"roomId": json.Number("8")}, map[string]string{"roomId": "room.id"}, msg, false},
+		{"Struct", map[string]any{"kind": "TEXT"}, nil, msg, true},
+		{"Not an object", map[string]any{"kind": "TEXT"}, nil, "TEXT", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArgumentFilter(tt.args, tt.paths)(tt.payload); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+// testBus delivers events sent to events to its single subscriber.
+type testBus struct {
+	events chan Event
+	err    error
+}
+
+func (b *testBus) Publish(ctx context.Context, topic string, payload any) error {
+	select {
+	case b.events <- Event{Topic: topic, Payload: payload}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *testBus) Subscribe(ctx context.Context, topic string) (<-chan Event, error) {
+	return b.events, b.err
+}
+
+func collect(results <-chan Result) []any {
+	var got []any
+	for r := range results {
+		if r.Err != nil {
+			got = append(got, r.Err.Error())
+		} else {
+			got = append(got, r.Data)
+		}
+	}
+	return got
+}
+
+func equal(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStream(t *testing.T) {
+	bus := &testBus{events: make(chan Event, 4)}
+	results, err := Stream(context.Background(), bus, "numbers", Options{
+		Filter: func(payload any) bool { return payload.(int)%2 == 0 },
+		Map: func(ctx context.Context, payload any) (any, error) {
+			if payload.(int) == 4 {
+				return nil, errors.New("four")
+			}
+			return payload.(int) * 10, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 1; i <= 6; i++ {
+		if err := bus.Publish(context.Background(), "numbers", i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	close(bus.events)
+
+	expected := []any{20, "four", 60}
+	if got := collect(results); !equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestStream_SubscribeError(t *testing.T) {
+	bus := &testBus{err: errors.New("unavailable")}
+	if _, err := Stream(context.Background(), bus, "t", Options{}); err == nil || err.Error() != "unavailable" {
+		t.Errorf("expected error %q, got %v", "unavailable", err)
+	}
+}
+
+func TestStream_Cancel(t *testing.T) {
+	bus := &testBus{events: make(chan Event)}
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := Stream(ctx, bus, "t", Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	if got := collect(results); len(got) != 0 {
+		t.Errorf("expected no results, got %v", got)
+	}
+}
+
+func TestStream_Overflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow Overflow
+		drops    int
+		expected []any
+	}{
+		{"Drop oldest", DropOldest, 3, []any{4, 5}},
+		{"Drop newest", DropNewest, 3, []any{1, 2}},
+		{"Disconnect", Disconnect, 2, []any{2, ErrSlowConsumer.Error()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &testBus{events: make(chan Event, 5)}
+			var drops sync.WaitGroup
+			drops.Add(tt.drops)
+			results, err := Stream(context.Background(), bus, "t", Options{
+				Buffer:   2,
+				Overflow: tt.overflow,
+				OnDrop:   drops.Done,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i := 1; i <= 5; i++ {
+				bus.events <- Event{Payload: i}
+			}
+			drops.Wait()
+			if tt.overflow != Disconnect {
+				close(bus.events)
+			}
+			if got := collect(results); !equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestStream_Block(t *testing.T) {
+	bus := &testBus{events: make(chan Event)}
+	results, err := Stream(context.Background(), bus, "t", Options{Buffer: 1, Overflow: Block})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go func() {
+		for i := 1; i <= 3; i++ {
+			bus.events <- Event{Payload: i}
+		}
+		close(bus.events)
+	}()
+	expected := []any{1, 2, 3}
+	if got := collect(results); !equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
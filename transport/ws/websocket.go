@@ -0,0 +1,262 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes.
+//
+// https://www.rfc-editor.org/rfc/rfc6455#section-5.2
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close codes of WebSocket and of the protocol.
+const (
+	CloseNormal          = 1000
+	CloseInvalidMessage  = 4400
+	CloseUnauthorized    = 4401
+	CloseForbidden       = 4403
+	CloseInitTimeout     = 4408
+	CloseSubscriberInUse = 4409
+)
+
+// maxMessageSize limits size of received messages.
+const maxMessageSize = 32 << 20
+
+// acceptGUID is appended to key of opening handshake to compute accept
+// value of server.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// CloseError is returned when the connection is closed by the server, e.g.
+// with CloseUnauthorized when connection_init payload is rejected.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("ws: connection closed with code %d", e.Code)
+	}
+	return fmt.Sprintf("ws: connection closed with code %d: %s", e.Code, e.Reason)
+}
+
+// conn is a WebSocket connection. Frames sent by clients are masked,
+// frames of servers are not.
+type conn struct {
+	nc     net.Conn
+	r      *bufio.Reader
+	client bool
+
+	mu sync.Mutex // Serializes writes.
+}
+
+func newConn(nc net.Conn, r *bufio.Reader, client bool) *conn {
+	if r == nil {
+		r = bufio.NewReader(nc)
+	}
+	return &conn{nc: nc, r: r, client: client}
+}
+
+// handshake performs opening handshake of client over nc requesting
+// subprotocol protocol.
+//
+// https://www.rfc-editor.org/rfc/rfc6455#section-4.1
+func handshake(ctx context.Context, nc net.Conn, u *url.URL, header http.Header, protocol string) (*conn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		nc.SetDeadline(deadline)
+		defer nc.SetDeadline(time.Time{})
+	}
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header.Clone(),
+		Host:       u.Host,
+	}
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Protocol", protocol)
+	if err := req.Write(nc); err != nil {
+		return nil, fmt.Errorf("ws: handshake: %w", err)
+	}
+
+	r := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		return nil, fmt.Errorf("ws: handshake: %w", err)
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode != http.StatusSwitchingProtocols:
+		return nil, fmt.Errorf("ws: handshake: unexpected status %s", resp.Status)
+	case !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket"):
+		return nil, errors.New("ws: handshake: connection not upgraded to websocket")
+	case resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key):
+		return nil, errors.New("ws: handshake: invalid Sec-WebSocket-Accept")
+	case resp.Header.Get("Sec-WebSocket-Protocol") != protocol:
+		return nil, fmt.Errorf("ws: handshake: server does not support subprotocol %s", protocol)
+	}
+	return newConn(nc, r, true), nil
+}
+
+// acceptKey returns Sec-WebSocket-Accept value for key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// write sends payload in a single frame.
+func (c *conn) write(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode // FIN
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if c.client {
+		header[1] |= 0x80
+		var mask [4]byte
+		rand.Read(mask[:])
+		header = append(header, mask[:]...)
+		masked := make([]byte, len(payload))
+		for i, b := range payload {
+			masked[i] = b ^ mask[i%4]
+		}
+		payload = masked
+	}
+	if _, err := c.nc.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("ws: %w", err)
+	}
+	return nil
+}
+
+// close sends close frame with code and closes the connection.
+func (c *conn) close(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	c.nc.SetWriteDeadline(time.Now().Add(time.Second))
+	c.write(opClose, append(payload, reason...))
+	return c.nc.Close()
+}
+
+// read returns payload of the next data message. Pings are answered and
+// pongs are skipped; close frame of the peer is returned as *CloseError.
+func (c *conn) read() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.frame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.write(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			closeErr := &CloseError{Code: 1005} // No status received.
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			return nil, closeErr
+		case opText, opBinary, opContinuation:
+		default:
+			return nil, fmt.Errorf("ws: unknown opcode %d", opcode)
+		}
+		if len(message)+len(payload) > maxMessageSize {
+			return nil, errors.New("ws: message too large")
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// frame reads a single frame.
+func (c *conn) frame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = header[0]&0x80 != 0, header[0]&0x0F
+	n := uint64(header[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxMessageSize {
+		return false, 0, nil, errors.New("ws: message too large")
+	}
+	var mask [4]byte
+	masked := header[1]&0x80 != 0
+	if masked {
+		if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
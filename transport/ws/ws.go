@@ -0,0 +1,469 @@
+// Package ws implements a client of the graphql-transport-ws protocol,
+// which carries GraphQL operations, subscriptions in particular, over a
+// WebSocket connection:
+//
+//	c, err := ws.Dial(ctx, "wss://example.com/graphql", ws.Options{KeepAlive: 15 * time.Second})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//	sub, err := c.Subscribe(ctx, &client.Request{Query: `subscription { messageAdded { text } }`})
+//	if err != nil {
+//		return err
+//	}
+//	for resp := range sub.C {
+//		...
+//	}
+//	return sub.Err()
+//
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+package ws
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/client"
+)
+
+// Protocol is WebSocket subprotocol implemented by this package.
+const Protocol = "graphql-transport-ws"
+
+// Types of protocol messages.
+const (
+	typeConnectionInit = "connection_init"
+	typeConnectionAck  = "connection_ack"
+	typePing           = "ping"
+	typePong           = "pong"
+	typeSubscribe      = "subscribe"
+	typeNext           = "next"
+	typeError          = "error"
+	typeComplete       = "complete"
+)
+
+// ErrClosed is returned by Subscribe and Subscription.Err once the client
+// is closed.
+var ErrClosed = errors.New("ws: client closed")
+
+// Options configure Dial.
+type Options struct {
+	// Dial opens connection to the server, e.g. through a proxy; TCP
+	// connections to host of the URL, over TLS for wss URLs, are opened
+	// when nil.
+	Dial func(ctx context.Context) (net.Conn, error)
+
+	// Header is sent with the opening handshake, e.g. Authorization or
+	// Origin.
+	Header http.Header
+
+	// InitPayload is payload of connection_init message, typically
+	// credentials.
+	InitPayload map[string]any
+
+	// AckTimeout limits wait for connection_ack, 10 seconds when zero.
+	AckTimeout time.Duration
+
+	// KeepAlive is interval of ping messages; they are not sent when zero.
+	// The connection is considered lost when server does not answer a
+	// ping with pong until the next one is due.
+	KeepAlive time.Duration
+
+	// Buffer is number of results buffered for a subscription, 16 when
+	// zero.
+	Buffer int
+}
+
+// message is a protocol message.
+type message struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Client is a graphql-transport-ws connection. Operations are multiplexed
+// over it; each runs until completed by the server, canceled by its
+// context, or the connection is lost. Lost connections are not
+// reestablished.
+type Client struct {
+	opts Options
+	conn *conn
+
+	mu     sync.Mutex
+	subs   map[string]*Subscription
+	nextID int
+	err    error         // Why the connection ended.
+	done   chan struct{} // Closed when the connection ends.
+	pinged bool          // Whether a ping waits for pong.
+
+	// A subscription with full buffer blocks receive, so pongs are not
+	// read meanwhile. keepAlive does not count them as lost while
+	// delivering or when receive was delivering since the last ping.
+	delivering bool
+	delivered  bool
+}
+
+// Dial connects to GraphQL server at rawURL, a ws, wss, http or https URL,
+// and initializes the connection. ctx bounds connecting only.
+func Dial(ctx context.Context, rawURL string, opts Options) (*Client, error) {
+	if opts.AckTimeout == 0 {
+		opts.AckTimeout = 10 * time.Second
+	}
+	if opts.Buffer == 0 {
+		opts.Buffer = 16
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("ws: %w", err)
+	}
+	secure := false
+	switch u.Scheme {
+	case "ws", "http":
+	case "wss", "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("ws: unsupported URL scheme %q", u.Scheme)
+	}
+
+	dial := opts.Dial
+	if dial == nil {
+		dial = func(ctx context.Context) (net.Conn, error) {
+			host, port := u.Hostname(), u.Port()
+			if port == "" {
+				port = "80"
+				if secure {
+					port = "443"
+				}
+			}
+			var d net.Dialer
+			nc, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+			if err != nil || !secure {
+				return nc, err
+			}
+			tc := tls.Client(nc, &tls.Config{ServerName: host})
+			if err := tc.HandshakeContext(ctx); err != nil {
+				nc.Close()
+				return nil, err
+			}
+			return tc, nil
+		}
+	}
+	nc, err := dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ws: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.AckTimeout)
+	defer cancel()
+	c, err := handshake(ctx, nc, u, opts.Header, Protocol)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	cl := &Client{opts: opts, conn: c, subs: make(map[string]*Subscription), done: make(chan struct{})}
+	if err := cl.init(ctx); err != nil {
+		c.nc.Close()
+		return nil, err
+	}
+	go cl.receive()
+	if opts.KeepAlive > 0 {
+		go cl.keepAlive()
+	}
+	return cl, nil
+}
+
+// init sends connection_init and waits for connection_ack, answering
+// pings meanwhile.
+func (c *Client) init(ctx context.Context) error {
+	deadline, _ := ctx.Deadline()
+	c.conn.nc.SetDeadline(deadline)
+	defer c.conn.nc.SetDeadline(time.Time{})
+
+	var payload any
+	if c.opts.InitPayload != nil {
+		payload = c.opts.InitPayload
+	}
+	if err := c.send("", typeConnectionInit, payload); err != nil {
+		return err
+	}
+	for {
+		msg, err := c.read()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return errors.New("ws: connection_ack not received")
+			}
+			return err
+		}
+		switch msg.Type {
+		case typeConnectionAck:
+			return nil
+		case typePing:
+			if err := c.send("", typePong, nil); err != nil {
+				return err
+			}
+		case typePong:
+		default:
+			c.conn.close(CloseInvalidMessage, "Unexpected "+msg.Type+" before connection_ack")
+			return fmt.Errorf("ws: unexpected %s message before connection_ack", msg.Type)
+		}
+	}
+}
+
+// Subscribe starts operation of req, usually a subscription, and returns
+// subscription delivering its results. Canceling ctx stops the operation.
+func (c *Client) Subscribe(ctx context.Context, req *client.Request) (*Subscription, error) {
+	ch := make(chan *client.Response, c.opts.Buffer)
+	sub := &Subscription{C: ch, c: ch, done: make(chan struct{})}
+
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.nextID++
+	sub.id = strconv.Itoa(c.nextID)
+	c.subs[sub.id] = sub
+	c.mu.Unlock()
+
+	if err := c.send(sub.id, typeSubscribe, req); err != nil {
+		c.remove(sub.id)
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			if c.remove(sub.id) != nil {
+				c.send(sub.id, typeComplete, nil)
+			}
+			sub.finish(ctx.Err())
+		case <-sub.done:
+		}
+	}()
+	return sub, nil
+}
+
+// Close closes the connection, ending subscriptions with ErrClosed.
+func (c *Client) Close() error {
+	if !c.shutdown(ErrClosed) {
+		return nil
+	}
+	return c.conn.close(CloseNormal, "Normal Closure")
+}
+
+// Done returns channel closed when the connection ends, either by Close or
+// because it was lost, see Err.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns why the connection ended, or nil while it is open.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// shutdown ends the connection and subscriptions with err. It reports
+// whether the connection was open.
+func (c *Client) shutdown(err error) bool {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return false
+	}
+	c.err = err
+	subs := c.subs
+	c.subs = nil
+	close(c.done)
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.finish(err)
+	}
+	return true
+}
+
+// remove unregisters subscription id and returns it, or nil when it is
+// not registered.
+func (c *Client) remove(id string) *Subscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sub := c.subs[id]
+	delete(c.subs, id)
+	return sub
+}
+
+func (c *Client) send(id, typ string, payload any) error {
+	msg := message{ID: id, Type: typ}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("ws: %w", err)
+		}
+		msg.Payload = data
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("ws: %w", err)
+	}
+	return c.conn.write(opText, data)
+}
+
+func (c *Client) read() (*message, error) {
+	data, err := c.conn.read()
+	if err != nil {
+		return nil, err
+	}
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+		c.conn.close(CloseInvalidMessage, "Invalid message received")
+		return nil, fmt.Errorf("ws: invalid message %q", data)
+	}
+	return &msg, nil
+}
+
+// receive dispatches messages until the connection ends.
+func (c *Client) receive() {
+	for {
+		msg, err := c.read()
+		if err == nil {
+			err = c.dispatch(msg)
+		}
+		if err != nil {
+			if c.shutdown(err) {
+				c.conn.nc.Close()
+			}
+			return
+		}
+	}
+}
+
+func (c *Client) dispatch(msg *message) error {
+	switch msg.Type {
+	case typePing:
+		return c.send("", typePong, nil)
+	case typePong:
+		c.mu.Lock()
+		c.pinged = false
+		c.mu.Unlock()
+	case typeNext:
+		var resp client.Response
+		if err := json.Unmarshal(msg.Payload, &resp); err != nil {
+			c.conn.close(CloseInvalidMessage, "Invalid next message payload")
+			return fmt.Errorf("ws: invalid next message payload: %w", err)
+		}
+		c.mu.Lock()
+		sub := c.subs[msg.ID]
+		c.mu.Unlock()
+		if sub != nil {
+			c.mu.Lock()
+			c.delivering, c.delivered = true, true
+			c.mu.Unlock()
+			sub.deliver(&resp)
+			c.mu.Lock()
+			c.delivering = false
+			c.mu.Unlock()
+		}
+	case typeError:
+		var errs client.ErrorList
+		if err := json.Unmarshal(msg.Payload, &errs); err != nil {
+			c.conn.close(CloseInvalidMessage, "Invalid error message payload")
+			return fmt.Errorf("ws: invalid error message payload: %w", err)
+		}
+		if sub := c.remove(msg.ID); sub != nil {
+			sub.finish(&client.RequestError{Errors: errs})
+		}
+	case typeComplete:
+		if sub := c.remove(msg.ID); sub != nil {
+			sub.finish(nil)
+		}
+	default:
+		c.conn.close(CloseInvalidMessage, "Unexpected "+msg.Type+" message")
+		return fmt.Errorf("ws: unexpected %s message", msg.Type)
+	}
+	return nil
+}
+
+// keepAlive pings the server until the connection ends.
+func (c *Client) keepAlive() {
+	ticker := time.NewTicker(c.opts.KeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+		c.mu.Lock()
+		lost := c.pinged && !c.delivered
+		c.pinged = true
+		c.delivered = c.delivering
+		c.mu.Unlock()
+		if lost {
+			if c.shutdown(errors.New("ws: keepalive timeout")) {
+				c.conn.nc.Close()
+			}
+			return
+		}
+		c.send("", typePing, nil)
+	}
+}
+
+// Subscription is an operation started by Subscribe.
+type Subscription struct {
+	// C delivers results of the operation. It is closed when the
+	// operation ends, see Err. Results are not dropped: when C is full,
+	// reading of other operations of the connection waits. Keepalive
+	// timeouts are suspended meanwhile, since pongs are not read either.
+	C <-chan *client.Response
+
+	id   string
+	c    chan *client.Response
+	mu   sync.Mutex // Guards sends to and close of c.
+	once sync.Once
+	done chan struct{} // Closed when the operation ends.
+	err  error
+}
+
+// Err returns why the subscription ended once C is closed: nil when the
+// server completed it, *client.RequestError when the server rejected it,
+// error of its context when it was canceled and error of the connection
+// when it ended first.
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Subscription) deliver(resp *client.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+	case s.c <- resp:
+	}
+}
+
+// finish ends the subscription with err unless it has ended already.
+func (s *Subscription) finish(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+		s.mu.Lock()
+		close(s.c)
+		s.mu.Unlock()
+	})
+}
@@ -0,0 +1,372 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/client"
+)
+
+// server is the server side of a connection of a test.
+type server struct {
+	t    *testing.T
+	conn *conn
+}
+
+// dial connects a client to serve running as the server.
+func dial(t *testing.T, opts Options, serve func(s *server)) (*Client, error) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	opts.Dial = func(ctx context.Context) (net.Conn, error) {
+		go func() {
+			sc, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer sc.Close()
+			r := bufio.NewReader(sc)
+			req, err := http.ReadRequest(r)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if got := req.Header.Get("Sec-WebSocket-Protocol"); got != Protocol {
+				t.Errorf("expected protocol %s, got %s", Protocol, got)
+			}
+			resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+				"Upgrade: websocket\r\n" +
+				"Connection: Upgrade\r\n" +
+				"Sec-WebSocket-Accept: " + acceptKey(req.Header.Get("Sec-WebSocket-Key")) + "\r\n" +
+				"Sec-WebSocket-Protocol: " + Protocol + "\r\n\r\n"
+			if _, err := sc.Write([]byte(resp)); err != nil {
+				return
+			}
+			serve(&server{t: t, conn: newConn(sc, r, false)})
+		}()
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", l.Addr().String())
+	}
+	return Dial(context.Background(), "ws://example.com/graphql", opts)
+}
+
+// ack reads connection_init and acknowledges it.
+func (s *server) ack() {
+	if msg := s.read(); msg == nil || msg.Type != typeConnectionInit {
+		s.t.Errorf("expected connection_init, got %+v", msg)
+	}
+	s.send("", typeConnectionAck, "")
+}
+
+// read returns the next message, or nil when the connection ended.
+func (s *server) read() *message {
+	data, err := s.conn.read()
+	if err != nil {
+		return nil
+	}
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		s.t.Errorf("unexpected error: %v", err)
+		return nil
+	}
+	return &msg
+}
+
+func (s *server) send(id, typ, payload string) {
+	msg := message{ID: id, Type: typ}
+	if payload != "" {
+		msg.Payload = json.RawMessage(payload)
+	}
+	data, _ := json.Marshal(msg)
+	s.conn.write(opText, data)
+}
+
+// collect returns data of results of sub until it ends.
+func collect(sub *Subscription) []string {
+	var got []string
+	for resp := range sub.C {
+		got = append(got, string(resp.Data))
+	}
+	return got
+}
+
+func TestDial(t *testing.T) {
+	init := make(chan json.RawMessage, 1)
+	c, err := dial(t, Options{InitPayload: map[string]any{"token": "secret"}}, func(s *server) {
+		msg := s.read()
+		init <- msg.Payload
+		s.send("", typePing, "")
+		if msg := s.read(); msg == nil || msg.Type != typePong {
+			t.Errorf("expected pong, got %+v", msg)
+		}
+		s.send("", typeConnectionAck, "")
+		s.read()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+	if got := string(<-init); got != `{"token":"secret"}` {
+		t.Errorf("expected init payload %s, got %s", `{"token":"secret"}`, got)
+	}
+}
+
+func TestDial_Rejected(t *testing.T) {
+	_, err := dial(t, Options{}, func(s *server) {
+		s.read()
+		s.conn.close(CloseForbidden, "Forbidden")
+	})
+	var closeErr *CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != CloseForbidden {
+		t.Fatalf("expected close error with code %d, got %v", CloseForbidden, err)
+	}
+	if expected := "ws: connection closed with code 4403: Forbidden"; err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err)
+	}
+}
+
+func TestDial_AckTimeout(t *testing.T) {
+	_, err := dial(t, Options{AckTimeout: 10 * time.Millisecond}, func(s *server) {
+		s.read()
+		s.read()
+	})
+	if err == nil || err.Error() != "ws: connection_ack not received" {
+		t.Errorf("expected ack timeout, got %v", err)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	c, err := dial(t, Options{}, func(s *server) {
+		s.ack()
+		for {
+			msg := s.read()
+			if msg == nil {
+				return
+			}
+			switch msg.Type {
+			case typeSubscribe:
+				var req client.Request
+				json.Unmarshal(msg.Payload, &req)
+				switch req.Query {
+				case "subscription { tick }":
+					s.send(msg.ID, typeNext, `{"data":{"tick":1}}`)
+					s.send("", typePing, "")
+					s.send(msg.ID, typeNext, `{"data":{"tick":2}}`)
+					s.send(msg.ID, typeComplete, "")
+				case "subscription { denied }":
+					s.send(msg.ID, typeError, `[{"message":"Forbidden"}]`)
+				}
+			case typePong:
+			default:
+				t.Errorf("unexpected message %+v", msg)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	sub, err := c.Subscribe(context.Background(), &client.Request{Query: "subscription { tick }"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{`{"tick":1}`, `{"tick":2}`}
+	if got := collect(sub); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if err := sub.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	sub, err = c.Subscribe(context.Background(), &client.Request{Query: "subscription { denied }"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := collect(sub); len(got) != 0 {
+		t.Errorf("expected no results, got %v", got)
+	}
+	var reqErr *client.RequestError
+	if !errors.As(sub.Err(), &reqErr) || len(reqErr.Errors) != 1 || reqErr.Errors[0].Message != "Forbidden" {
+		t.Errorf("expected request error Forbidden, got %v", sub.Err())
+	}
+}
+
+func TestSubscribe_Cancel(t *testing.T) {
+	completed := make(chan string, 1)
+	c, err := dial(t, Options{}, func(s *server) {
+		s.ack()
+		msg := s.read()
+		s.send(msg.ID, typeNext, `{"data":{"tick":1}}`)
+		if msg := s.read(); msg != nil && msg.Type == typeComplete {
+			completed <- msg.ID
+		}
+		s.read()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := c.Subscribe(ctx, &client.Request{Query: "subscription { tick }"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-sub.C
+	cancel()
+	for range sub.C {
+	}
+	if !errors.Is(sub.Err(), context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, sub.Err())
+	}
+	select {
+	case id := <-completed:
+		if id != "1" {
+			t.Errorf("expected complete of 1, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected complete message")
+	}
+}
+
+func TestClient_ConnectionLost(t *testing.T) {
+	c, err := dial(t, Options{}, func(s *server) {
+		s.ack()
+		s.read()
+		s.conn.close(CloseInvalidMessage, "Invalid message received")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sub, err := c.Subscribe(context.Background(), &client.Request{Query: "subscription { tick }"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	collect(sub)
+	var closeErr *CloseError
+	if !errors.As(sub.Err(), &closeErr) || closeErr.Code != CloseInvalidMessage {
+		t.Errorf("expected close error with code %d, got %v", CloseInvalidMessage, sub.Err())
+	}
+	<-c.Done()
+	if c.Err() != sub.Err() {
+		t.Errorf("expected client error %v, got %v", sub.Err(), c.Err())
+	}
+	if _, err := c.Subscribe(context.Background(), &client.Request{}); err != c.Err() {
+		t.Errorf("expected error %v, got %v", c.Err(), err)
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	closed := make(chan error, 1)
+	c, err := dial(t, Options{}, func(s *server) {
+		s.ack()
+		s.read()
+		_, err := s.conn.read()
+		closed <- err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sub, err := c.Subscribe(context.Background(), &client.Request{Query: "subscription { tick }"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Close()
+	collect(sub)
+	if sub.Err() != ErrClosed {
+		t.Errorf("expected %v, got %v", ErrClosed, sub.Err())
+	}
+	var closeErr *CloseError
+	if err := <-closed; !errors.As(err, &closeErr) || closeErr.Code != CloseNormal {
+		t.Errorf("expected close error with code %d, got %v", CloseNormal, err)
+	}
+}
+
+func TestClient_KeepAlive(t *testing.T) {
+	pings := make(chan struct{}, 10)
+	c, err := dial(t, Options{KeepAlive: 10 * time.Millisecond}, func(s *server) {
+		s.ack()
+		for i := 0; ; i++ {
+			msg := s.read()
+			if msg == nil {
+				return
+			}
+			if msg.Type == typePing {
+				pings <- struct{}{}
+				// Only the first two pings are answered.
+				if i < 2 {
+					s.send("", typePong, "")
+				}
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected connection to end")
+	}
+	if len(pings) != 3 {
+		t.Errorf("expected 3 pings, got %d", len(pings))
+	}
+	if err := c.Err(); err == nil || err.Error() != "ws: keepalive timeout" {
+		t.Errorf("expected keepalive timeout, got %v", err)
+	}
+}
+
+func TestClient_KeepAliveStalledReader(t *testing.T) {
+	c, err := dial(t, Options{KeepAlive: 10 * time.Millisecond, Buffer: 1}, func(s *server) {
+		s.ack()
+		for {
+			msg := s.read()
+			if msg == nil {
+				return
+			}
+			switch msg.Type {
+			case typeSubscribe:
+				for i := 1; i <= 3; i++ {
+					s.send(msg.ID, typeNext, fmt.Sprintf(`{"data":{"tick":%d}}`, i))
+				}
+				s.send(msg.ID, typeComplete, "")
+			case typePing:
+				s.send("", typePong, "")
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	sub, err := c.Subscribe(context.Background(), &client.Request{Query: "subscription { tick }"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Results fill the buffer and block receiving for several keepalive
+	// intervals.
+	time.Sleep(100 * time.Millisecond)
+	expected := []string{`{"tick":1}`, `{"tick":2}`, `{"tick":3}`}
+	if got := collect(sub); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if err := sub.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("unexpected connection error: %v", err)
+	}
+}
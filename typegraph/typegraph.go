@@ -0,0 +1,242 @@
+// Package typegraph exports dependency graph of schema types, with types
+// as nodes and field type references, implemented interfaces and union
+// members as edges, to DOT and JSON for architecture reviews:
+//
+//	g := typegraph.New(s, typegraph.Options{FromRoots: true, ExcludeScalars: true})
+//	err := g.WriteDOT(w) // Render with: dot -Tsvg schema.dot
+package typegraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// EdgeKind is kind of dependency between types.
+type EdgeKind string
+
+const (
+	EdgeField      EdgeKind = "field"      // Type of field.
+	EdgeArgument   EdgeKind = "argument"   // Type of field argument.
+	EdgeInputField EdgeKind = "inputField" // Type of input field.
+	EdgeImplements EdgeKind = "implements" // Interface implemented by type.
+	EdgeMember     EdgeKind = "member"     // Member of union.
+)
+
+// Node is a named type.
+type Node struct {
+	Name string      `json:"name"`
+	Kind schema.Kind `json:"kind"`
+}
+
+// Edge is a dependency of type From on type To. Label is name of field,
+// "field(argument:)" or input field; empty for other kinds.
+type Edge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Kind  EdgeKind `json:"kind"`
+	Label string   `json:"label,omitempty"`
+}
+
+// Graph is type dependency graph. Nodes are in definition order, with
+// built-in scalars last; edges are ordered by their From node.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Options filter graph. The zero value includes all types and edges.
+type Options struct {
+	// FromRoots restricts graph to types reachable from root operation
+	// types.
+	FromRoots bool
+
+	// ReachableFrom restricts graph to types reachable from named types,
+	// in addition to root types with FromRoots. A type reaches types of
+	// its fields, arguments and input fields, interfaces it implements,
+	// union members and implementations of interfaces.
+	ReachableFrom []string
+
+	// ExcludeScalars leaves out scalar types and edges to them.
+	ExcludeScalars bool
+
+	// ExcludeArguments leaves out argument edges, which also no longer
+	// make types reachable.
+	ExcludeArguments bool
+}
+
+// New returns dependency graph of types of s filtered by opts.
+func New(s *schema.Schema, opts Options) *Graph {
+	b := &builder{schema: s, opts: opts, kinds: make(map[string]schema.Kind), edges: make(map[string][]Edge)}
+	for _, t := range s.Types() {
+		b.node(t.Name)
+	}
+	for _, t := range s.Types() {
+		b.add(t)
+	}
+
+	include := func(name string) bool {
+		return !opts.ExcludeScalars || b.kinds[name] != schema.KindScalar
+	}
+	if opts.FromRoots || len(opts.ReachableFrom) > 0 {
+		reached := b.reachable()
+		filter := include
+		include = func(name string) bool { return reached[name] && filter(name) }
+	}
+
+	g := &Graph{}
+	for _, name := range append(b.order, b.builtIns...) {
+		if !include(name) {
+			continue
+		}
+		g.Nodes = append(g.Nodes, Node{Name: name, Kind: b.kinds[name]})
+		for _, e := range b.edges[name] {
+			if include(e.To) {
+				g.Edges = append(g.Edges, e)
+			}
+		}
+	}
+	return g
+}
+
+type builder struct {
+	schema   *schema.Schema
+	opts     Options
+	order    []string
+	builtIns []string
+	kinds    map[string]schema.Kind
+	edges    map[string][]Edge // Edges by From.
+}
+
+// node adds node for type name unless it is known, which are built-in
+// scalars when the schema does not define them.
+func (b *builder) node(name string) {
+	if _, ok := b.kinds[name]; ok {
+		return
+	}
+	t := b.schema.Type(name)
+	if t == nil {
+		b.kinds[name] = schema.KindScalar
+		b.builtIns = append(b.builtIns, name)
+		return
+	}
+	b.kinds[name] = t.Kind
+	b.order = append(b.order, name)
+}
+
+func (b *builder) edge(from string, to ast.Type, kind EdgeKind, label string) {
+	name := namedType(to)
+	b.node(name)
+	b.edges[from] = append(b.edges[from], Edge{From: from, To: name, Kind: kind, Label: label})
+}
+
+func (b *builder) add(t *schema.Type) {
+	for _, name := range t.Interfaces {
+		b.edge(t.Name, named(name), EdgeImplements, "")
+	}
+	for _, f := range t.Fields {
+		b.edge(t.Name, f.Type, EdgeField, f.Name)
+		if b.opts.ExcludeArguments {
+			continue
+		}
+		for _, arg := range f.Arguments {
+			b.edge(t.Name, arg.Type, EdgeArgument, f.Name+"("+arg.Name+":)")
+		}
+	}
+	for _, name := range t.Types {
+		b.edge(t.Name, named(name), EdgeMember, "")
+	}
+	for _, f := range t.InputFields {
+		b.edge(t.Name, f.Type, EdgeInputField, f.Name)
+	}
+}
+
+// reachable returns names of types reachable from roots of the filter.
+func (b *builder) reachable() map[string]bool {
+	var queue []string
+	if b.opts.FromRoots {
+		for _, op := range []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription} {
+			if t := b.schema.RootType(op); t != nil {
+				queue = append(queue, t.Name)
+			}
+		}
+	}
+	queue = append(queue, b.opts.ReachableFrom...)
+
+	reached := make(map[string]bool)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reached[name] {
+			continue
+		}
+		if _, ok := b.kinds[name]; !ok {
+			continue
+		}
+		reached[name] = true
+		for _, e := range b.edges[name] {
+			queue = append(queue, e.To)
+		}
+		if t := b.schema.Type(name); t != nil && t.Kind == schema.KindInterface {
+			for _, impl := range b.schema.PossibleTypes(t) {
+				queue = append(queue, impl.Name)
+			}
+		}
+	}
+	return reached
+}
+
+// WriteDOT writes g in Graphviz DOT language. Nodes are labeled with type
+// kinds; implements edges are dashed and member edges dotted.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph schema {\n  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "  %s [label=%s];\n", strconv.Quote(n.Name), strconv.Quote(n.Name+"\n"+string(n.Kind)))
+	}
+	for _, e := range g.Edges {
+		var attrs string
+		switch e.Kind {
+		case EdgeImplements:
+			attrs = " [style=dashed]"
+		case EdgeMember:
+			attrs = " [style=dotted]"
+		default:
+			attrs = " [label=" + strconv.Quote(e.Label) + "]"
+		}
+		fmt.Fprintf(bw, "  %s -> %s%s;\n", strconv.Quote(e.From), strconv.Quote(e.To), attrs)
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// WriteJSON writes g as JSON object with nodes and edges.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+func named(name string) *ast.NamedType {
+	return &ast.NamedType{Name: &ast.Name{Value: name}}
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch wrapped := t.(type) {
+		case *ast.NonNullType:
+			t = wrapped.Type
+		case *ast.ListType:
+			t = wrapped.Type
+		case *ast.NamedType:
+			return wrapped.Name.Value
+		default:
+			return ""
+		}
+	}
+}
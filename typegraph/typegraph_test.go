@@ -0,0 +1,120 @@
+package typegraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+const testSDL = `
+type Query { node(id: ID!): Node search(filter: Filter): [Result!]! }
+interface Node { id: ID! }
+type User implements Node { id: ID! name: String }
+type Post implements Node { id: ID! author: User }
+union Result = User | Post
+input Filter { term: String }
+type Orphan { tag: Tag }
+enum Tag { A }
+`
+
+// summary returns nodes and edges of g as strings.
+func summary(g *Graph) ([]string, []string) {
+	var nodes, edges []string
+	for _, n := range g.Nodes {
+		nodes = append(nodes, n.Name)
+	}
+	for _, e := range g.Edges {
+		edges = append(edges, fmt.Sprintf("%s -%s %s-> %s", e.From, e.Kind, e.Label, e.To))
+	}
+	return nodes, edges
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		nodes []string
+		edges []string
+	}{
+		{"All", Options{},
+			[]string{"Query", "Node", "User", "Post", "Result", "Filter", "Orphan", "Tag", "ID", "String"},
+			[]string{
+				"Query -field node-> Node", "Query -argument node(id:)-> ID", "Query -field search-> Result", "Query -argument search(filter:)-> Filter",
+				"Node -field id-> ID",
+				"User -implements -> Node", "User -field id-> ID", "User -field name-> String",
+				"Post -implements -> Node", "Post -field id-> ID", "Post -field author-> User",
+				"Result -member -> User", "Result -member -> Post",
+				"Filter -inputField term-> String",
+				"Orphan -field tag-> Tag",
+			}},
+		{"From roots without scalars", Options{FromRoots: true, ExcludeScalars: true},
+			[]string{"Query", "Node", "User", "Post", "Result", "Filter"},
+			[]string{
+				"Query -field node-> Node", "Query -field search-> Result", "Query -argument search(filter:)-> Filter",
+				"User -implements -> Node", "Post -implements -> Node", "Post -field author-> User",
+				"Result -member -> User", "Result -member -> Post",
+			}},
+		{"Reachable without arguments", Options{ReachableFrom: []string{"Node", "Orphan"}, ExcludeScalars: true, ExcludeArguments: true},
+			[]string{"Node", "User", "Post", "Orphan", "Tag"},
+			[]string{"User -implements -> Node", "Post -implements -> Node", "Post -field author-> User", "Orphan -field tag-> Tag"}},
+		{"Unknown type", Options{ReachableFrom: []string{"Missing"}}, nil, nil},
+	}
+	s := asttest.Schema(t, testSDL)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, edges := summary(New(s, tt.opts))
+			if !reflect.DeepEqual(nodes, tt.nodes) {
+				t.Errorf("expected nodes %v, got %v", tt.nodes, nodes)
+			}
+			if !reflect.DeepEqual(edges, tt.edges) {
+				t.Errorf("expected edges:\n%q\ngot:\n%q", tt.edges, edges)
+			}
+		})
+	}
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	g := New(asttest.Schema(t, testSDL), Options{ReachableFrom: []string{"Result"}, ExcludeScalars: true})
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `digraph schema {
+  node [shape=box];
+  "Node" [label="Node\nINTERFACE"];
+  "User" [label="User\nOBJECT"];
+  "Post" [label="Post\nOBJECT"];
+  "Result" [label="Result\nUNION"];
+  "User" -> "Node" [style=dashed];
+  "Post" -> "Node" [style=dashed];
+  "Post" -> "User" [label="author"];
+  "Result" -> "User" [style=dotted];
+  "Result" -> "Post" [style=dotted];
+}
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGraph_WriteJSON(t *testing.T) {
+	g := New(asttest.Schema(t, testSDL), Options{ReachableFrom: []string{"Filter"}})
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, g) {
+		t.Errorf("expected %+v, got %+v", g, decoded)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"kind": "inputField"`)) {
+		t.Errorf("unexpected JSON:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// KnownArgumentNames checks that arguments of fields and directives are
+// defined by them. Fields and directives unknown to schema are not
+// checked.
+//
+// https://spec.graphql.org/draft/#sec-Argument-Names
+var KnownArgumentNames = Rule{
+	Name: "KnownArgumentNames",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		walkSelections(ctx, func(parent *schema.Type, sel ast.Selection) {
+			f, ok := sel.(*ast.Field)
+			if !ok {
+				return
+			}
+			def := fieldDefinition(ctx.Schema, parent, f.Name.Value)
+			if def == nil {
+				return
+			}
+			for _, arg := range f.Arguments {
+				if def.Argument(arg.Name.Value) == nil {
+					ctx.Reportf([]ast.Node{arg}, "Unknown argument %q on field \"%s.%s\".", arg.Name.Value, parent.Name, f.Name.Value)
+				}
+			}
+		})
+		walkDirectives(ctx.Document, func(_ ast.DirectiveLocation, directives []*ast.Directive) {
+			for _, d := range directives {
+				def := lookupDirective(ctx.Schema, d.Name.Value)
+				if def == nil {
+					continue
+				}
+				for _, arg := range d.Arguments {
+					if def.Argument(arg.Name.Value) == nil {
+						ctx.Reportf([]ast.Node{arg}, "Unknown argument %q on directive \"@%s\".", arg.Name.Value, d.Name.Value)
+					}
+				}
+			}
+		})
+	},
+}
+
+// UniqueArgumentNames checks that each argument of a field or directive is
+// given once. All arguments with the same name are reported together.
+//
+// https://spec.graphql.org/draft/#sec-Argument-Uniqueness
+var UniqueArgumentNames = Rule{
+	Name: "UniqueArgumentNames",
+	Check: func(ctx *Context) {
+		var v ast.TypeVisitor
+		ast.OnEnter(&v, func(n *ast.Field) ast.Action {
+			uniqueArguments(ctx, n.Arguments)
+			return ast.Continue
+		})
+		ast.OnEnter(&v, func(n *ast.Directive) ast.Action {
+			uniqueArguments(ctx, n.Arguments)
+			return ast.Skip
+		})
+		ast.WalkDocument(&v, ctx.Document)
+	},
+}
+
+func uniqueArguments(ctx *Context, args []*ast.Argument) {
+	for _, group := range duplicates(args, func(arg *ast.Argument) string { return arg.Name.Value }) {
+		names := make([]ast.Node, len(group))
+		for i, arg := range group {
+			names[i] = arg.Name
+		}
+		ctx.Reportf(names, "There can be only one argument named %q.", group[0].Name.Value)
+	}
+}
+
+// ProvidedRequiredArguments checks that arguments of non-null types
+// without default values are given to fields and directives.
+//
+// https://spec.graphql.org/draft/#sec-Required-Arguments
+var ProvidedRequiredArguments = Rule{
+	Name: "ProvidedRequiredArguments",
+	Check: func(ctx *Context) {
+		walkSelections(ctx, func(parent *schema.Type, sel ast.Selection) {
+			f, ok := sel.(*ast.Field)
+			if !ok {
+				return
+			}
+			def := fieldDefinition(ctx.Schema, parent, f.Name.Value)
+			if def == nil {
+				return
+			}
+			for _, arg := range missingArguments(def.Arguments, f.Arguments) {
+				ctx.Reportf([]ast.Node{f}, "Field %q argument %q of type %q is required, but it was not provided.",
+					f.Name.Value, arg.Name, printer.Print(arg.Type))
+			}
+		})
+		walkDirectives(ctx.Document, func(_ ast.DirectiveLocation, directives []*ast.Directive) {
+			for _, d := range directives {
+				def := lookupDirective(ctx.Schema, d.Name.Value)
+				if def == nil {
+					continue
+				}
+				for _, arg := range missingArguments(def.Arguments, d.Arguments) {
+					ctx.Reportf([]ast.Node{d}, "Directive \"@%s\" argument %q of type %q is required, but it was not provided.",
+						d.Name.Value, arg.Name, printer.Print(arg.Type))
+				}
+			}
+		})
+	},
+}
+
+// missingArguments returns required arguments of defs missing from args.
+func missingArguments(defs []*schema.InputValue, args []*ast.Argument) []*schema.InputValue {
+	var missing []*schema.InputValue
+	for _, def := range defs {
+		if _, ok := def.Type.(*ast.NonNullType); !ok || def.DefaultValue != nil {
+			continue
+		}
+		found := false
+		for _, arg := range args {
+			found = found || arg.Name.Value == def.Name
+		}
+		if !found {
+			missing = append(missing, def)
+		}
+	}
+	return missing
+}
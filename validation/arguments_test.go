@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestKnownArgumentNames(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := KnownArgumentNames
+	input := `{ dog(id: 1, x: 2) { name(y: 3) } pet @skip(if: true, z: 4) @unknown(w: 5) { name } }`
+	expectErrors(t, rule, input)
+	expectSchemaErrors(t, s, rule, `{ dog(id: 1, name: "a") { name } search(in: {}) @d(x: 1, req: 2) { __typename } unknown(a: 1) }`)
+	expectSchemaErrors(t, s, rule, input,
+		expectedError{`Unknown argument "x" on field "Query.dog".`, []int{13}},
+		expectedError{`Unknown argument "y" on field "Dog.name".`, []int{26}},
+		expectedError{`Unknown argument "z" on directive "@skip".`, []int{54}},
+	)
+}
+
+func TestUniqueArgumentNames(t *testing.T) {
+	rule := UniqueArgumentNames
+	expectErrors(t, rule, `{ a(x: 1, y: 2) @d(x: 1) { b(x: 1) } }`)
+	expectErrors(t, rule, `{ a(x: 1, y: 2, x: 3, x: 4) @d(y: 1, y: 2) }`,
+		expectedError{`There can be only one argument named "x".`, []int{4, 16, 22}},
+		expectedError{`There can be only one argument named "y".`, []int{31, 37}},
+	)
+}
+
+func TestProvidedRequiredArguments(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := ProvidedRequiredArguments
+	expectErrors(t, rule, `{ dog { name } pet @skip(if: true) { name } }`)
+	expectSchemaErrors(t, s, rule, `{ dog(id: 1) { name } pet @d(req: 1) { name } }`)
+	expectSchemaErrors(t, s, rule, `{ dog { name } pet @skip @include(if: true) @d { name } }`,
+		expectedError{`Field "dog" argument "id" of type "ID!" is required, but it was not provided.`, []int{2}},
+		expectedError{`Directive "@skip" argument "if" of type "Boolean!" is required, but it was not provided.`, []int{19}},
+		expectedError{`Directive "@d" argument "req" of type "Int!" is required, but it was not provided.`, []int{44}},
+	)
+}
@@ -0,0 +1,97 @@
+package validation
+
+import "github.com/gqlhub/gqlhub-core/ast"
+
+// ExecutableDefinitions checks that document contains only operations and
+// fragments.
+//
+// https://spec.graphql.org/draft/#sec-Executable-Definitions
+var ExecutableDefinitions = Rule{
+	Name: "ExecutableDefinitions",
+	Check: func(ctx *Context) {
+		for _, def := range ctx.Document.Definitions {
+			switch def.(type) {
+			case *ast.OperationDefinition, *ast.FragmentDefinition:
+				continue
+			}
+			if name := definitionName(def); name != "" {
+				ctx.Reportf([]ast.Node{def}, "The %q definition is not executable.", name)
+			} else {
+				ctx.Reportf([]ast.Node{def}, "The schema definition is not executable.")
+			}
+		}
+	},
+}
+
+// TypeSystemDefinitions checks that document contains only type system
+// definitions and extensions. It is the counterpart of
+// ExecutableDefinitions for schema registries.
+var TypeSystemDefinitions = Rule{
+	Name: "TypeSystemDefinitions",
+	Check: func(ctx *Context) {
+		for _, def := range ctx.Document.Definitions {
+			switch d := def.(type) {
+			case *ast.OperationDefinition:
+				if d.Name != nil {
+					ctx.Reportf([]ast.Node{def}, "The %q operation is not allowed in a type system document.", d.Name.Value)
+				} else {
+					ctx.Reportf([]ast.Node{def}, "The anonymous operation is not allowed in a type system document.")
+				}
+			case *ast.FragmentDefinition:
+				ctx.Reportf([]ast.Node{def}, "The %q fragment is not allowed in a type system document.", d.Name.Value)
+			}
+		}
+	},
+}
+
+// RequireExecutable returns an error for every type system definition or
+// extension in doc. It is a guard for endpoints accepting only executable
+// documents that skip full validation.
+func RequireExecutable(doc *ast.Document) []*Error {
+	return Validate(nil, doc, ExecutableDefinitions)
+}
+
+// RequireTypeSystem returns an error for every operation or fragment in
+// doc.
+func RequireTypeSystem(doc *ast.Document) []*Error {
+	return Validate(nil, doc, TypeSystemDefinitions)
+}
+
+// definitionName returns name of type system definition or extension.
+// Directive names are prefixed with "@". It is empty for schema definition
+// and extension.
+func definitionName(def ast.Definition) string {
+	var name *ast.Name
+	switch d := def.(type) {
+	case *ast.ScalarTypeDefinition:
+		name = d.Name
+	case *ast.ObjectTypeDefinition:
+		name = d.Name
+	case *ast.InterfaceTypeDefinition:
+		name = d.Name
+	case *ast.UnionTypeDefinition:
+		name = d.Name
+	case *ast.EnumTypeDefinition:
+		name = d.Name
+	case *ast.InputObjectTypeDefinition:
+		name = d.Name
+	case *ast.ScalarTypeExtension:
+		name = d.Name
+	case *ast.ObjectTypeExtension:
+		name = d.Name
+	case *ast.InterfaceTypeExtension:
+		name = d.Name
+	case *ast.UnionTypeExtension:
+		name = d.Name
+	case *ast.EnumTypeExtension:
+		name = d.Name
+	case *ast.InputObjectTypeExtension:
+		name = d.Name
+	case *ast.DirectiveDefinition:
+		return "@" + d.Name.Value
+	}
+	if name == nil {
+		return ""
+	}
+	return name.Value
+}
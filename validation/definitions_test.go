@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestExecutableDefinitions(t *testing.T) {
+	expectErrors(t, ExecutableDefinitions, `query Q { a } fragment F on T { a }`)
+	expectErrors(t, ExecutableDefinitions, `{ a }
+type T { a: Int }
+extend schema @a
+directive @d on FIELD
+extend input I { a: Int }`,
+		expectedError{`The "T" definition is not executable.`, []int{6}},
+		expectedError{`The schema definition is not executable.`, []int{24}},
+		expectedError{`The "@d" definition is not executable.`, []int{41}},
+		expectedError{`The "I" definition is not executable.`, []int{63}},
+	)
+}
+
+func TestTypeSystemDefinitions(t *testing.T) {
+	expectErrors(t, TypeSystemDefinitions, `type T { a: Int } extend type T @a`)
+	expectErrors(t, TypeSystemDefinitions, `type T { a: Int } query Q { a } { a } fragment F on T { a }`,
+		expectedError{`The "Q" operation is not allowed in a type system document.`, []int{18}},
+		expectedError{`The anonymous operation is not allowed in a type system document.`, []int{32}},
+		expectedError{`The "F" fragment is not allowed in a type system document.`, []int{38}},
+	)
+}
+
+func TestRequireExecutable(t *testing.T) {
+	if errs := RequireExecutable(asttest.Parse(t, `{ a }`)); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if errs := RequireExecutable(asttest.Parse(t, `{ a } scalar S`)); len(errs) != 1 || errs[0].Positions[0] != 6 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if errs := RequireTypeSystem(asttest.Parse(t, `scalar S { a }`)); len(errs) != 1 || errs[0].Positions[0] != 9 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
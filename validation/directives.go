@@ -0,0 +1,156 @@
+package validation
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// specifiedDirectives are directives of the specification, which schemas
+// need not define.
+var specifiedDirectives = map[string]*schema.Directive{
+	"skip": {
+		Name:      "skip",
+		Arguments: []*schema.InputValue{{Name: "if", Type: &ast.NonNullType{Type: namedTypeRef("Boolean")}}},
+		Locations: []ast.DirectiveLocation{ast.DirectiveLocationField, ast.DirectiveLocationFragmentSpread, ast.DirectiveLocationInlineFragment},
+	},
+	"include": {
+		Name:      "include",
+		Arguments: []*schema.InputValue{{Name: "if", Type: &ast.NonNullType{Type: namedTypeRef("Boolean")}}},
+		Locations: []ast.DirectiveLocation{ast.DirectiveLocationField, ast.DirectiveLocationFragmentSpread, ast.DirectiveLocationInlineFragment},
+	},
+	"deprecated": {
+		Name: "deprecated",
+		Arguments: []*schema.InputValue{{
+			Name:         "reason",
+			Type:         namedTypeRef("String"),
+			DefaultValue: &ast.StringValue{Value: "No longer supported"},
+		}},
+		Locations: []ast.DirectiveLocation{
+			ast.DirectiveLocationFieldDefinition, ast.DirectiveLocationArgumentDefinition,
+			ast.DirectiveLocationInputFieldDefinition, ast.DirectiveLocationEnumValue,
+		},
+	},
+	"specifiedBy": {
+		Name:      "specifiedBy",
+		Arguments: []*schema.InputValue{{Name: "url", Type: &ast.NonNullType{Type: namedTypeRef("String")}}},
+		Locations: []ast.DirectiveLocation{ast.DirectiveLocationScalar},
+	},
+	"oneOf": {
+		Name:      "oneOf",
+		Locations: []ast.DirectiveLocation{ast.DirectiveLocationInputObject},
+	},
+}
+
+// lookupDirective returns directive definition of s or specified directive
+// name, or nil.
+func lookupDirective(s *schema.Schema, name string) *schema.Directive {
+	if s != nil {
+		if d := s.Directive(name); d != nil {
+			return d
+		}
+	}
+	return specifiedDirectives[name]
+}
+
+// KnownDirectives checks that directives of executable definitions are
+// defined and used in locations they allow.
+//
+// https://spec.graphql.org/draft/#sec-Directives-Are-Defined
+// https://spec.graphql.org/draft/#sec-Directives-Are-In-Valid-Locations
+var KnownDirectives = Rule{
+	Name: "KnownDirectives",
+	Check: func(ctx *Context) {
+		walkDirectives(ctx.Document, func(location ast.DirectiveLocation, directives []*ast.Directive) {
+			for _, d := range directives {
+				def := lookupDirective(ctx.Schema, d.Name.Value)
+				if def == nil {
+					if ctx.Schema != nil {
+						ctx.Reportf([]ast.Node{d}, "Unknown directive \"@%s\".", d.Name.Value)
+					}
+					continue
+				}
+				if !containsLocation(def.Locations, location) {
+					ctx.Reportf([]ast.Node{d}, "Directive \"@%s\" may not be used on %s.", d.Name.Value, location)
+				}
+			}
+		})
+	},
+}
+
+func containsLocation(locations []ast.DirectiveLocation, location ast.DirectiveLocation) bool {
+	for _, l := range locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
+// UniqueDirectivesPerLocation checks that non-repeatable directives are
+// applied at most once to each node. Unknown directives are not checked.
+//
+// https://spec.graphql.org/draft/#sec-Directives-Are-Unique-Per-Location
+var UniqueDirectivesPerLocation = Rule{
+	Name: "UniqueDirectivesPerLocation",
+	Check: func(ctx *Context) {
+		walkDirectives(ctx.Document, func(_ ast.DirectiveLocation, directives []*ast.Directive) {
+			first := make(map[string]*ast.Directive)
+			for _, d := range directives {
+				def := lookupDirective(ctx.Schema, d.Name.Value)
+				if def == nil || def.Repeatable {
+					continue
+				}
+				if other, ok := first[d.Name.Value]; ok {
+					ctx.Reportf([]ast.Node{other, d}, "The directive \"@%s\" can only be used once at this location.", d.Name.Value)
+					continue
+				}
+				first[d.Name.Value] = d
+			}
+		})
+	},
+}
+
+var operationLocations = map[ast.OperationType]ast.DirectiveLocation{
+	ast.OperationTypeQuery:        ast.DirectiveLocationQuery,
+	ast.OperationTypeMutation:     ast.DirectiveLocationMutation,
+	ast.OperationTypeSubscription: ast.DirectiveLocationSubscription,
+}
+
+// walkDirectives calls fn with directives applied to each node of
+// executable definitions of doc and the location of the node.
+func walkDirectives(doc *ast.Document, fn func(location ast.DirectiveLocation, directives []*ast.Directive)) {
+	var v ast.TypeVisitor
+	ast.OnEnter(&v, func(n *ast.OperationDefinition) ast.Action {
+		fn(operationLocations[n.OperationType], n.Directives)
+		return ast.Continue
+	})
+	ast.OnEnter(&v, func(n *ast.VariableDefinition) ast.Action {
+		fn(ast.DirectiveLocationVariableDefinition, n.Directives)
+		return ast.Skip
+	})
+	ast.OnEnter(&v, func(n *ast.FragmentDefinition) ast.Action {
+		fn(ast.DirectiveLocationFragmentDefinition, n.Directives)
+		return ast.Continue
+	})
+	ast.OnEnter(&v, func(n *ast.Field) ast.Action {
+		fn(ast.DirectiveLocationField, n.Directives)
+		return ast.Continue
+	})
+	ast.OnEnter(&v, func(n *ast.FragmentSpread) ast.Action {
+		fn(ast.DirectiveLocationFragmentSpread, n.Directives)
+		return ast.Continue
+	})
+	ast.OnEnter(&v, func(n *ast.InlineFragment) ast.Action {
+		fn(ast.DirectiveLocationInlineFragment, n.Directives)
+		return ast.Continue
+	})
+	// Type system definitions are reported by ExecutableDefinitions.
+	ast.OnEnter(&v, func(n ast.Definition) ast.Action {
+		switch n.(type) {
+		case *ast.OperationDefinition, *ast.FragmentDefinition:
+			return ast.Continue
+		}
+		return ast.Skip
+	})
+	ast.WalkDocument(&v, doc)
+}
@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestKnownDirectives(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := KnownDirectives
+	input := `{ a @skip(if: true) @include(if: true) @unknown b @once @once @d(req: 1) @d(req: 2) } query @once { a }`
+	expectErrors(t, rule, input)
+	expectSchemaErrors(t, s, rule, input,
+		expectedError{`Unknown directive "@unknown".`, []int{39}},
+		expectedError{`Directive "@once" may not be used on QUERY.`, []int{92}},
+	)
+	expectSchemaErrors(t, s, rule, `query ($v: Int @skip(if: true)) @d(req: 1) { ...F @once ... @skip(if: true) { a } } fragment F on T @skip(if: true) { a }`,
+		expectedError{`Directive "@skip" may not be used on VARIABLE_DEFINITION.`, []int{15}},
+		expectedError{`Directive "@skip" may not be used on FRAGMENT_DEFINITION.`, []int{100}},
+	)
+	expectSchemaErrors(t, s, rule, `{ a } type T @unknown { a: Int @unknown }`)
+}
+
+func TestUniqueDirectivesPerLocation(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := UniqueDirectivesPerLocation
+	input := `{ a @skip(if: true) @include(if: true) @unknown b @once @once @d(req: 1) @d(req: 2) @unknown } query @once { a }`
+	expectErrors(t, rule, input)
+	expectSchemaErrors(t, s, rule, input,
+		expectedError{`The directive "@once" can only be used once at this location.`, []int{50, 56}},
+	)
+	expectSchemaErrors(t, s, rule, `{ a @skip(if: true) ... @skip(if: false) @skip(if: true) { b } }`,
+		expectedError{`The directive "@skip" can only be used once at this location.`, []int{24, 41}},
+	)
+}
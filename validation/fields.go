@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// FieldsOnCorrectType checks that selected fields are defined by the type
+// they are selected on. Selections on types unknown to schema are not
+// checked.
+//
+// https://spec.graphql.org/draft/#sec-Field-Selections
+var FieldsOnCorrectType = Rule{
+	Name: "FieldsOnCorrectType",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		walkSelections(ctx, func(parent *schema.Type, sel ast.Selection) {
+			f, ok := sel.(*ast.Field)
+			if !ok || parent == nil || fieldDefinition(ctx.Schema, parent, f.Name.Value) != nil {
+				return
+			}
+			ctx.Reportf([]ast.Node{f}, "Cannot query field %q on type %q.", f.Name.Value, parent.Name)
+		})
+	},
+}
+
+// ScalarLeafs checks that fields of scalar and enum types have no
+// selection set and fields of other types have one.
+//
+// https://spec.graphql.org/draft/#sec-Leaf-Field-Selections
+var ScalarLeafs = Rule{
+	Name: "ScalarLeafs",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		walkSelections(ctx, func(parent *schema.Type, sel ast.Selection) {
+			f, ok := sel.(*ast.Field)
+			if !ok {
+				return
+			}
+			def := fieldDefinition(ctx.Schema, parent, f.Name.Value)
+			if def == nil {
+				return
+			}
+			name := namedType(def.Type)
+			var leaf bool
+			switch t := lookupType(ctx.Schema, name); {
+			case t != nil:
+				leaf = t.Kind == schema.KindScalar || t.Kind == schema.KindEnum
+			case specifiedScalars[name]:
+				leaf = true
+			default:
+				return
+			}
+			switch {
+			case leaf && f.SelectionSet != nil:
+				ctx.Reportf([]ast.Node{f.SelectionSet}, "Field %q must not have a selection since type %q has no subfields.",
+					f.Name.Value, printer.Print(def.Type))
+			case !leaf && f.SelectionSet == nil:
+				ctx.Reportf([]ast.Node{f}, "Field %q of type %q must have a selection of subfields. Did you mean \"%s { ... }\"?",
+					f.Name.Value, printer.Print(def.Type), f.Name.Value)
+			}
+		})
+	},
+}
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestFieldsOnCorrectType(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := FieldsOnCorrectType
+	expectErrors(t, rule, `{ a { b } }`)
+	expectSchemaErrors(t, s, rule, `{ __typename pet { __typename name ... on Dog { barks } } search { __typename } }`)
+	expectSchemaErrors(t, s, rule, `{ pet { name barks ... on Dog { barks x } ... on Result { __typename name } } __schema { a } }`,
+		expectedError{`Cannot query field "barks" on type "Pet".`, []int{13}},
+		expectedError{`Cannot query field "x" on type "Dog".`, []int{38}},
+		expectedError{`Cannot query field "name" on type "Result".`, []int{69}},
+		expectedError{`Cannot query field "a" on type "__Schema".`, []int{89}},
+	)
+	expectSchemaErrors(t, s, rule, `{ s: __schema { types { name } } ...F pet { __type(name: "Dog") { name } } } fragment F on Query { __type(name: "Dog") { kind ofType { b } } }`,
+		expectedError{`Cannot query field "__type" on type "Pet".`, []int{44}},
+		expectedError{`Cannot query field "b" on type "__Type".`, []int{135}},
+	)
+	expectSchemaErrors(t, s, rule, `mutation { rename(name: "a") { name } pet }`,
+		expectedError{`Cannot query field "pet" on type "Mutation".`, []int{38}},
+	)
+}
+
+func TestScalarLeafs(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := ScalarLeafs
+	expectErrors(t, rule, `{ a { b } c }`)
+	expectSchemaErrors(t, s, rule, `{ name color pet { name } __typename unknown { a } }`)
+	expectSchemaErrors(t, s, rule, `{ name { a } pet dog(id: 1) { owner } color }`,
+		expectedError{`Field "name" must not have a selection since type "String" has no subfields.`, []int{7}},
+		expectedError{`Field "pet" of type "Pet" must have a selection of subfields. Did you mean "pet { ... }"?`, []int{13}},
+		expectedError{`Field "owner" of type "Human" must have a selection of subfields. Did you mean "owner { ... }"?`, []int{30}},
+	)
+}
@@ -0,0 +1,277 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// NoFragmentCycles checks that fragment spreads do not form cycles. Each
+// cycle is reported once with positions of every spread along it.
+//
+// https://spec.graphql.org/draft/#sec-Fragment-spreads-must-not-form-cycles
+var NoFragmentCycles = Rule{
+	Name: "NoFragmentCycles",
+	Check: func(ctx *Context) {
+		walkFragmentCycles(ctx.Document, func(name string, cycle []*ast.FragmentSpread) bool {
+			reportCycle(ctx, name, cycle)
+			return true
+		})
+	},
+}
+
+// reportCycle reports cycle of spreads starting and ending at fragment
+// name.
+func reportCycle(ctx *Context, name string, cycle []*ast.FragmentSpread) {
+	nodes := make([]ast.Node, len(cycle))
+	via := make([]string, 0, len(cycle)-1)
+	for i, spread := range cycle {
+		nodes[i] = spread
+		if i < len(cycle)-1 {
+			via = append(via, fmt.Sprintf("%q", spread.Name.Value))
+		}
+	}
+	if len(via) == 0 {
+		ctx.Reportf(nodes, "Cannot spread fragment %q within itself.", name)
+	} else {
+		ctx.Reportf(nodes, "Cannot spread fragment %q within itself via %s.", name, strings.Join(via, ", "))
+	}
+}
+
+// FragmentCycle returns names of fragments forming the first cycle of
+// fragment spreads in doc, starting and ending with the same fragment, or
+// nil when there is none. It is a fast standalone check for servers that
+// skip full validation but must not expand cyclic fragments.
+func FragmentCycle(doc *ast.Document) []string {
+	var result []string
+	walkFragmentCycles(doc, func(name string, cycle []*ast.FragmentSpread) bool {
+		result = append(result, name)
+		for _, spread := range cycle {
+			result = append(result, spread.Name.Value)
+		}
+		return false
+	})
+	return result
+}
+
+// walkFragmentCycles calls fn with fragment name and spreads forming each
+// cycle starting at it. Walk stops when fn returns false.
+func walkFragmentCycles(doc *ast.Document, fn func(name string, cycle []*ast.FragmentSpread) bool) {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	var order []*ast.FragmentDefinition
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			if _, ok := fragments[f.Name.Value]; !ok {
+				fragments[f.Name.Value] = f
+				order = append(order, f)
+			}
+		}
+	}
+
+	visited := make(map[string]bool)
+	indexByName := make(map[string]int)
+	var path []*ast.FragmentSpread
+
+	var detect func(f *ast.FragmentDefinition) bool
+	detect = func(f *ast.FragmentDefinition) bool {
+		name := f.Name.Value
+		if visited[name] {
+			return true
+		}
+		visited[name] = true
+
+		spreads := fragmentSpreads(f.SelectionSet, nil)
+		indexByName[name] = len(path)
+		defer delete(indexByName, name)
+		for _, spread := range spreads {
+			target := spread.Name.Value
+			index, inPath := indexByName[target]
+			path = append(path, spread)
+			if !inPath {
+				if next, ok := fragments[target]; ok && !detect(next) {
+					return false
+				}
+			} else if !fn(target, path[index:]) {
+				return false
+			}
+			path = path[:len(path)-1]
+		}
+		return true
+	}
+	for _, f := range order {
+		if !detect(f) {
+			return
+		}
+	}
+}
+
+// fragmentSpreads appends spreads of set, including nested ones, to
+// spreads.
+func fragmentSpreads(set *ast.SelectionSet, spreads []*ast.FragmentSpread) []*ast.FragmentSpread {
+	if set == nil {
+		return spreads
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			spreads = fragmentSpreads(s.SelectionSet, spreads)
+		case *ast.InlineFragment:
+			spreads = fragmentSpreads(s.SelectionSet, spreads)
+		case *ast.FragmentSpread:
+			spreads = append(spreads, s)
+		}
+	}
+	return spreads
+}
+
+// UniqueFragmentNames checks that each fragment is defined once.
+//
+// https://spec.graphql.org/draft/#sec-Fragment-Name-Uniqueness
+var UniqueFragmentNames = Rule{
+	Name: "UniqueFragmentNames",
+	Check: func(ctx *Context) {
+		first := make(map[string]*ast.Name)
+		for _, def := range ctx.Document.Definitions {
+			f, ok := def.(*ast.FragmentDefinition)
+			if !ok {
+				continue
+			}
+			if other, ok := first[f.Name.Value]; ok {
+				ctx.Reportf([]ast.Node{other, f.Name}, "There can be only one fragment named %q.", f.Name.Value)
+				continue
+			}
+			first[f.Name.Value] = f.Name
+		}
+	},
+}
+
+// KnownFragmentNames checks that spread fragments are defined.
+//
+// https://spec.graphql.org/draft/#sec-Fragment-spread-target-defined
+var KnownFragmentNames = Rule{
+	Name: "KnownFragmentNames",
+	Check: func(ctx *Context) {
+		fragments := fragmentDefinitions(ctx.Document)
+		var v ast.TypeVisitor
+		ast.OnEnter(&v, func(n *ast.FragmentSpread) ast.Action {
+			if _, ok := fragments[n.Name.Value]; !ok {
+				ctx.Reportf([]ast.Node{n.Name}, "Unknown fragment %q.", n.Name.Value)
+			}
+			return ast.Skip
+		})
+		ast.WalkDocument(&v, ctx.Document)
+	},
+}
+
+// NoUnusedFragments checks that every fragment is spread, directly or
+// through other fragments, into some operation.
+//
+// https://spec.graphql.org/draft/#sec-Fragments-Must-Be-Used
+var NoUnusedFragments = Rule{
+	Name: "NoUnusedFragments",
+	Check: func(ctx *Context) {
+		fragments := fragmentDefinitions(ctx.Document)
+		used := make(map[string]bool)
+		var use func(set *ast.SelectionSet)
+		use = func(set *ast.SelectionSet) {
+			for _, spread := range fragmentSpreads(set, nil) {
+				name := spread.Name.Value
+				if used[name] {
+					continue
+				}
+				used[name] = true
+				if f, ok := fragments[name]; ok {
+					use(f.SelectionSet)
+				}
+			}
+		}
+		for _, def := range ctx.Document.Definitions {
+			if op, ok := def.(*ast.OperationDefinition); ok {
+				use(op.SelectionSet)
+			}
+		}
+		for _, def := range ctx.Document.Definitions {
+			if f, ok := def.(*ast.FragmentDefinition); ok && !used[f.Name.Value] {
+				ctx.Reportf([]ast.Node{f}, "Fragment %q is never used.", f.Name.Value)
+			}
+		}
+	},
+}
+
+// PossibleFragmentSpreads checks that fragments are only spread where
+// their type condition can apply, that is the type condition and the
+// parent type have some possible object type in common.
+//
+// https://spec.graphql.org/draft/#sec-Fragment-spread-is-possible
+var PossibleFragmentSpreads = Rule{
+	Name: "PossibleFragmentSpreads",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		fragments := fragmentDefinitions(ctx.Document)
+		walkSelections(ctx, func(parent *schema.Type, sel ast.Selection) {
+			if parent == nil || !isComposite(parent) {
+				return
+			}
+			switch s := sel.(type) {
+			case *ast.InlineFragment:
+				if s.TypeCondition == nil {
+					return
+				}
+				t := ctx.Schema.Type(s.TypeCondition.Name.Value)
+				if t != nil && isComposite(t) && !overlap(ctx.Schema, parent, t) {
+					ctx.Reportf([]ast.Node{s}, "Fragment cannot be spread here as objects of type %q can never be of type %q.", parent.Name, t.Name)
+				}
+			case *ast.FragmentSpread:
+				f, ok := fragments[s.Name.Value]
+				if !ok {
+					return
+				}
+				t := ctx.Schema.Type(f.TypeCondition.Name.Value)
+				if t != nil && isComposite(t) && !overlap(ctx.Schema, parent, t) {
+					ctx.Reportf([]ast.Node{s}, "Fragment %q cannot be spread here as objects of type %q can never be of type %q.", s.Name.Value, parent.Name, t.Name)
+				}
+			}
+		})
+	},
+}
+
+// overlap reports whether composite types a and b have a possible object
+// type in common.
+func overlap(s *schema.Schema, a, b *schema.Type) bool {
+	if a == b {
+		return true
+	}
+	possible := possibleTypes(s, a)
+	for name := range possibleTypes(s, b) {
+		if possible[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// possibleTypes returns names of object types values of composite type t
+// can be of.
+func possibleTypes(s *schema.Schema, t *schema.Type) map[string]bool {
+	result := make(map[string]bool)
+	switch t.Kind {
+	case schema.KindObject:
+		result[t.Name] = true
+	case schema.KindUnion:
+		for _, name := range t.Types {
+			result[name] = true
+		}
+	case schema.KindInterface:
+		for _, other := range s.Types() {
+			if other.Kind == schema.KindObject && slices.Contains(other.Interfaces, t.Name) {
+				result[other.Name] = true
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestNoFragmentCycles(t *testing.T) {
+	expectErrors(t, NoFragmentCycles, `
+fragment A on T { ...B ... on T { ...C } }
+fragment B on T { a }
+fragment C on T { ...B ...Unknown }
+{ ...A }`)
+
+	expectErrors(t, NoFragmentCycles, `fragment A on T { ...A }`,
+		expectedError{`Cannot spread fragment "A" within itself.`, []int{18}},
+	)
+
+	// fragment A -> B -> C -> A, and B -> B.
+	expectErrors(t, NoFragmentCycles, `fragment A on T { ...B }
+fragment B on T { ...C ...B }
+fragment C on T { f { ...A } }`,
+		expectedError{`Cannot spread fragment "A" within itself via "B", "C".`, []int{18, 43, 77}},
+		expectedError{`Cannot spread fragment "B" within itself.`, []int{48}},
+	)
+}
+
+func TestFragmentCycle(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`fragment A on T { ...B } fragment B on T { a } { ...A }`, nil},
+		{`fragment A on T { ...B } fragment B on T { ...A }`, []string{"A", "B", "A"}},
+		{`{ ...X } fragment X on T { ... { ...X } }`, []string{"X", "X"}},
+	}
+	for _, tt := range tests {
+		if got := FragmentCycle(asttest.Parse(t, tt.input)); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestUniqueFragmentNames(t *testing.T) {
+	rule := UniqueFragmentNames
+	expectErrors(t, rule, `{ ...A ...B } fragment A on T { a } fragment B on T { b }`)
+	expectErrors(t, rule, `{ ...A ...B } fragment A on T { a } fragment A on T { b }`,
+		expectedError{`There can be only one fragment named "A".`, []int{23, 45}},
+	)
+}
+
+func TestKnownFragmentNames(t *testing.T) {
+	rule := KnownFragmentNames
+	expectErrors(t, rule, `{ ...A } fragment A on T { ...A }`)
+	expectErrors(t, rule, `{ ...A ... { ...B } } fragment A on T { ...C }`,
+		expectedError{`Unknown fragment "B".`, []int{16}},
+		expectedError{`Unknown fragment "C".`, []int{43}},
+	)
+}
+
+func TestNoUnusedFragments(t *testing.T) {
+	rule := NoUnusedFragments
+	expectErrors(t, rule, `{ ...A ...Unknown } fragment A on T { ... { ...B } } fragment B on T { ...A }`)
+	expectErrors(t, rule, `{ ...A } fragment A on T { ...B } fragment B on T { a } fragment C on T { ...D } fragment D on T { ...C }`,
+		expectedError{`Fragment "C" is never used.`, []int{56}},
+		expectedError{`Fragment "D" is never used.`, []int{81}},
+	)
+}
+
+func TestPossibleFragmentSpreads(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := PossibleFragmentSpreads
+	expectErrors(t, rule, `{ pet { ... on Human { name } } }`)
+	expectSchemaErrors(t, s, rule, `{ pet { ... on Dog { name } ... on Pet { name } ... { name } ...D } search { ... on Pet { name } ... on Human { name } } } fragment D on Dog { name }`)
+	expectSchemaErrors(t, s, rule, `{ pet { ... on Human { name } ...H ... on Result { __typename } } dog(id: 1) { ...C ... on Pet { name } } } fragment H on Human { name } fragment C on Cat { name }`,
+		expectedError{`Fragment cannot be spread here as objects of type "Pet" can never be of type "Human".`, []int{8}},
+		expectedError{`Fragment "H" cannot be spread here as objects of type "Pet" can never be of type "Human".`, []int{30}},
+		expectedError{`Fragment "C" cannot be spread here as objects of type "Dog" can never be of type "Cat".`, []int{79}},
+	)
+}
@@ -0,0 +1,224 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/operation"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// mutationCorpus are valid documents against testSDL that mutators break.
+var mutationCorpus = []string{
+	`query Q($id: ID!, $in: In = {limit: 10}, $withOwner: Boolean = false) {
+  dog(id: $id) { ...DogFields owner @include(if: $withOwner) { name } }
+  search(in: $in) { __typename ... on Human { name } ...DogFields }
+  color
+}
+fragment DogFields on Dog { name barks }`,
+	`mutation M($name: String!) { rename(name: $name) { name ... on Cat { meows } } }`,
+	`{ dog(id: "1", name: "Rex") { barks owner { name } } search(in: {color: RED, limit: 5}) { ... on Dog { name } } }`,
+	`query P { pets { name ... on Dog { barks } } }`,
+}
+
+// mutator breaks a valid document in a controlled way, so that rule must
+// reject it.
+type mutator struct {
+	name string
+	rule string
+
+	// mutate applies mutation to n-th applicable site of doc, with field
+	// definitions resolved against sdl, and reports whether there was one.
+	mutate func(doc, sdl *ast.Document, n int) bool
+}
+
+// fieldSites calls fn for fields of all operations of doc until it
+// reports that n-th site was mutated.
+func fieldSites(doc, sdl *ast.Document, n int, fn func(f operation.Field, site func() bool) bool) bool {
+	site := func() bool {
+		n--
+		return n == -1
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		for f := range operation.Fields(doc, op, sdl) {
+			if fn(f, site) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// argumentDefinition returns definition of argument of field f.
+func argumentDefinition(f operation.Field, name string) *ast.InputValueDefinition {
+	if f.Definition == nil {
+		return nil
+	}
+	for _, def := range f.Definition.Arguments {
+		if def.Name.Value == name {
+			return def
+		}
+	}
+	return nil
+}
+
+func isRequired(def *ast.InputValueDefinition) bool {
+	_, nonNull := def.Type.(*ast.NonNullType)
+	return nonNull && def.DefaultValue == nil
+}
+
+var mutators = []mutator{
+	{"Drop required argument", "ProvidedRequiredArguments", func(doc, sdl *ast.Document, n int) bool {
+		return fieldSites(doc, sdl, n, func(f operation.Field, site func() bool) bool {
+			for i, arg := range f.Field.Arguments {
+				if def := argumentDefinition(f, arg.Name.Value); def != nil && isRequired(def) && site() {
+					f.Field.Arguments = append(f.Field.Arguments[:i], f.Field.Arguments[i+1:]...)
+					return true
+				}
+			}
+			return false
+		})
+	}},
+	{"Null for non-null argument", "ValuesOfCorrectType", func(doc, sdl *ast.Document, n int) bool {
+		return fieldSites(doc, sdl, n, func(f operation.Field, site func() bool) bool {
+			for _, arg := range f.Field.Arguments {
+				def := argumentDefinition(f, arg.Name.Value)
+				if _, isVar := arg.Value.(*ast.Variable); !isVar && def != nil && isRequired(def) && site() {
+					arg.Value = &ast.NullValue{}
+					return true
+				}
+			}
+			return false
+		})
+	}},
+	{"Nullable variable in non-null position", "VariablesInAllowedPosition", func(doc, sdl *ast.Document, n int) bool {
+		return fieldSites(doc, sdl, n, func(f operation.Field, site func() bool) bool {
+			for _, arg := range f.Field.Arguments {
+				def := argumentDefinition(f, arg.Name.Value)
+				v, isVar := arg.Value.(*ast.Variable)
+				if !isVar || def == nil || !isRequired(def) || !site() {
+					continue
+				}
+				for _, d := range doc.Definitions {
+					op, ok := d.(*ast.OperationDefinition)
+					if !ok {
+						continue
+					}
+					for _, vd := range op.VariableDefs {
+						if nonNull, ok := vd.Type.(*ast.NonNullType); ok && vd.Variable.Name.Value == v.Name.Value {
+							vd.Type = nonNull.Type
+							return true
+						}
+					}
+				}
+			}
+			return false
+		})
+	}},
+	{"Wrong literal type", "ValuesOfCorrectType", func(doc, sdl *ast.Document, n int) bool {
+		return fieldSites(doc, sdl, n, func(f operation.Field, site func() bool) bool {
+			for _, arg := range f.Field.Arguments {
+				if _, isVar := arg.Value.(*ast.Variable); !isVar && argumentDefinition(f, arg.Name.Value) != nil && site() {
+					// Not a valid scalar, enum or input object value.
+					arg.Value = &ast.ObjectValue{Fields: []*ast.ObjectField{{Name: &ast.Name{Value: "unknown"}, Value: &ast.IntValue{Value: "1"}}}}
+					return true
+				}
+			}
+			return false
+		})
+	}},
+	{"Rename argument", "KnownArgumentNames", func(doc, sdl *ast.Document, n int) bool {
+		return fieldSites(doc, sdl, n, func(f operation.Field, site func() bool) bool {
+			for _, arg := range f.Field.Arguments {
+				if site() {
+					arg.Name = &ast.Name{Value: arg.Name.Value + "Renamed"}
+					return true
+				}
+			}
+			return false
+		})
+	}},
+	{"Rename field", "FieldsOnCorrectType", func(doc, sdl *ast.Document, n int) bool {
+		return fieldSites(doc, sdl, n, func(f operation.Field, site func() bool) bool {
+			if site() {
+				f.Field.Name = &ast.Name{Value: f.Field.Name.Value + "Renamed"}
+				return true
+			}
+			return false
+		})
+	}},
+	{"Drop selection set", "ScalarLeafs", func(doc, sdl *ast.Document, n int) bool {
+		return fieldSites(doc, sdl, n, func(f operation.Field, site func() bool) bool {
+			if f.Field.SelectionSet != nil && site() {
+				f.Field.SelectionSet = nil
+				return true
+			}
+			return false
+		})
+	}},
+	{"Drop variable definition", "NoUndefinedVariables", func(doc, sdl *ast.Document, n int) bool {
+		for _, def := range doc.Definitions {
+			if op, ok := def.(*ast.OperationDefinition); ok {
+				if n < len(op.VariableDefs) {
+					op.VariableDefs = append(op.VariableDefs[:n], op.VariableDefs[n+1:]...)
+					return true
+				}
+				n -= len(op.VariableDefs)
+			}
+		}
+		return false
+	}},
+	{"Drop fragment definition", "KnownFragmentNames", func(doc, sdl *ast.Document, n int) bool {
+		for i, def := range doc.Definitions {
+			if _, ok := def.(*ast.FragmentDefinition); ok {
+				if n == 0 {
+					doc.Definitions = append(doc.Definitions[:i], doc.Definitions[i+1:]...)
+					return true
+				}
+				n--
+			}
+		}
+		return false
+	}},
+}
+
+// TestValidate_Mutations checks that every mutation of every document of
+// the corpus is rejected by the rule guarding against it.
+func TestValidate_Mutations(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	sdl := asttest.Parse(t, testSDL)
+	for _, input := range mutationCorpus {
+		if errs := Validate(s, asttest.Parse(t, input)); len(errs) != 0 {
+			t.Fatalf("corpus document is invalid: %v\n%s", errs, input)
+		}
+	}
+	for _, m := range mutators {
+		t.Run(m.name, func(t *testing.T) {
+			mutants := 0
+			for _, input := range mutationCorpus {
+				for n := 0; ; n++ {
+					doc := asttest.Parse(t, input)
+					if !m.mutate(doc, sdl, n) {
+						break
+					}
+					mutants++
+					found := false
+					for _, e := range Validate(s, doc) {
+						found = found || e.Rule == m.rule
+					}
+					if !found {
+						t.Errorf("expected %s to reject mutant:\n%s", m.rule, printer.PrintDocument(doc))
+					}
+				}
+			}
+			if mutants == 0 {
+				t.Errorf("corpus has no site for mutation")
+			}
+		})
+	}
+}
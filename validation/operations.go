@@ -0,0 +1,27 @@
+package validation
+
+import "github.com/gqlhub/gqlhub-core/ast"
+
+// LoneAnonymousOperation checks that an anonymous operation is the only
+// operation of the document.
+//
+// https://spec.graphql.org/draft/#sec-Lone-Anonymous-Operation
+var LoneAnonymousOperation = Rule{
+	Name: "LoneAnonymousOperation",
+	Check: func(ctx *Context) {
+		var operations []*ast.OperationDefinition
+		for _, def := range ctx.Document.Definitions {
+			if op, ok := def.(*ast.OperationDefinition); ok {
+				operations = append(operations, op)
+			}
+		}
+		if len(operations) < 2 {
+			return
+		}
+		for _, op := range operations {
+			if op.Name == nil {
+				ctx.Reportf([]ast.Node{op}, "This anonymous operation must be the only defined operation.")
+			}
+		}
+	},
+}
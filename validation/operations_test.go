@@ -0,0 +1,13 @@
+package validation
+
+import "testing"
+
+func TestLoneAnonymousOperation(t *testing.T) {
+	rule := LoneAnonymousOperation
+	expectErrors(t, rule, `{ a } fragment F on T { a }`)
+	expectErrors(t, rule, `query A { a } query B { b }`)
+	expectErrors(t, rule, `{ a } query Q { a } { b }`,
+		expectedError{`This anonymous operation must be the only defined operation.`, []int{0}},
+		expectedError{`This anonymous operation must be the only defined operation.`, []int{20}},
+	)
+}
@@ -0,0 +1,456 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// OverlappingFieldsCanBeMerged checks that fields selected under the same
+// response name, directly or through fragments, can be merged: they select
+// the same field with the same arguments unless their parents are
+// different object types, and they return compatible types. Without schema
+// only names and arguments are compared.
+//
+// The check follows the algorithm of graphql-js: fields of each selection
+// set and fragment are collected once, and comparisons of fragment pairs
+// and of fields with fragments are memoized, so documents spreading many
+// fragments into each other take polynomial rather than exponential time.
+//
+// https://spec.graphql.org/draft/#sec-Field-Selection-Merging
+var OverlappingFieldsCanBeMerged = Rule{
+	Name: "OverlappingFieldsCanBeMerged",
+	Check: func(ctx *Context) {
+		m := newMerger(ctx)
+		for _, def := range ctx.Document.Definitions {
+			switch d := def.(type) {
+			case *ast.OperationDefinition:
+				var root *schema.Type
+				if ctx.Schema != nil {
+					root = ctx.Schema.RootType(d.OperationType)
+				}
+				m.check(root, d.SelectionSet)
+			case *ast.FragmentDefinition:
+				m.check(m.typ(d.TypeCondition.Name.Value), d.SelectionSet)
+			}
+		}
+	},
+}
+
+// fieldAndDef is a field node with its parent type and definition, either
+// of which is nil when unknown.
+type fieldAndDef struct {
+	parent *schema.Type
+	node   *ast.Field
+	def    *schema.Field
+}
+
+// fieldMap groups fields of a selection set by response name in order of
+// first appearance.
+type fieldMap struct {
+	keys   []string
+	fields map[string][]fieldAndDef
+}
+
+// fieldsAndFragments are fields of a selection set with names of the
+// fragments it spreads, without descending into them.
+type fieldsAndFragments struct {
+	fields    *fieldMap
+	fragments []string
+}
+
+// conflict of fields under responseName. Either message or sub is set.
+type conflict struct {
+	responseName string
+	message      string
+	sub          []conflict
+	fields1      []*ast.Field
+	fields2      []*ast.Field
+}
+
+func (c conflict) reason() string {
+	if c.sub == nil {
+		return c.message
+	}
+	reasons := make([]string, len(c.sub))
+	for i, sub := range c.sub {
+		reasons[i] = fmt.Sprintf("subfields %q conflict because %s", sub.responseName, sub.reason())
+	}
+	return strings.Join(reasons, " and ")
+}
+
+// fragmentPair is a key of compared fragment pairs, with names in order.
+type fragmentPair struct {
+	a, b string
+}
+
+// fieldsFragmentPair is a key of compared pairs of fields and fragment.
+type fieldsFragmentPair struct {
+	fields   *fieldMap
+	fragment string
+}
+
+// merger finds conflicts of fields in a document.
+type merger struct {
+	ctx       *Context
+	fragments map[string]*ast.FragmentDefinition
+
+	// cache holds collected fields by selection set.
+	cache map[*ast.SelectionSet]fieldsAndFragments
+	// Compared pairs map to whether they were compared as mutually
+	// exclusive. Comparison of pairs that are not exclusive covers the
+	// exclusive one, but not vice versa.
+	fragmentPairs       map[fragmentPair]bool
+	fieldsFragmentPairs map[fieldsFragmentPair]bool
+}
+
+func newMerger(ctx *Context) *merger {
+	return &merger{
+		ctx:                 ctx,
+		fragments:           fragmentDefinitions(ctx.Document),
+		cache:               make(map[*ast.SelectionSet]fieldsAndFragments),
+		fragmentPairs:       make(map[fragmentPair]bool),
+		fieldsFragmentPairs: make(map[fieldsFragmentPair]bool),
+	}
+}
+
+func (m *merger) typ(name string) *schema.Type {
+	return lookupType(m.ctx.Schema, name)
+}
+
+// check reports conflicts within set and then checks selection sets nested
+// in it.
+func (m *merger) check(parent *schema.Type, set *ast.SelectionSet) {
+	if set == nil {
+		return
+	}
+	for _, c := range m.conflictsWithin(parent, set) {
+		nodes := make([]ast.Node, 0, len(c.fields1)+len(c.fields2))
+		for _, f := range c.fields1 {
+			nodes = append(nodes, f)
+		}
+		for _, f := range c.fields2 {
+			nodes = append(nodes, f)
+		}
+		m.ctx.Reportf(nodes, "Fields %q conflict because %s. Use different aliases on the fields to fetch both if this was intentional.",
+			c.responseName, c.reason())
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			var t *schema.Type
+			if parent != nil {
+				if f := parent.Field(s.Name.Value); f != nil {
+					t = m.typ(namedType(f.Type))
+				}
+			}
+			m.check(t, s.SelectionSet)
+		case *ast.InlineFragment:
+			t := parent
+			if s.TypeCondition != nil {
+				t = m.typ(s.TypeCondition.Name.Value)
+			}
+			m.check(t, s.SelectionSet)
+		}
+	}
+}
+
+// conflictsWithin returns conflicts of fields selected by set, including
+// fields of spread fragments.
+func (m *merger) conflictsWithin(parent *schema.Type, set *ast.SelectionSet) []conflict {
+	var conflicts []conflict
+	ff := m.fieldsAndFragments(parent, set)
+
+	for _, key := range ff.fields.keys {
+		fields := ff.fields.fields[key]
+		for i := range fields {
+			for j := i + 1; j < len(fields); j++ {
+				if c, ok := m.findConflict(false, key, fields[i], fields[j]); ok {
+					conflicts = append(conflicts, c)
+				}
+			}
+		}
+	}
+	for i, name := range ff.fragments {
+		conflicts = m.betweenFieldsAndFragment(conflicts, false, ff.fields, name)
+		for _, other := range ff.fragments[i+1:] {
+			conflicts = m.betweenFragments(conflicts, false, name, other)
+		}
+	}
+	return conflicts
+}
+
+// betweenSubSelections returns conflicts of fields of two selection sets,
+// which are merged under the same response name.
+func (m *merger) betweenSubSelections(exclusive bool, parent1 *schema.Type, set1 *ast.SelectionSet, parent2 *schema.Type, set2 *ast.SelectionSet) []conflict {
+	var conflicts []conflict
+	ff1 := m.fieldsAndFragments(parent1, set1)
+	ff2 := m.fieldsAndFragments(parent2, set2)
+
+	conflicts = m.between(conflicts, exclusive, ff1.fields, ff2.fields)
+	for _, name := range ff2.fragments {
+		conflicts = m.betweenFieldsAndFragment(conflicts, exclusive, ff1.fields, name)
+	}
+	for _, name := range ff1.fragments {
+		conflicts = m.betweenFieldsAndFragment(conflicts, exclusive, ff2.fields, name)
+	}
+	for _, name1 := range ff1.fragments {
+		for _, name2 := range ff2.fragments {
+			conflicts = m.betweenFragments(conflicts, exclusive, name1, name2)
+		}
+	}
+	return conflicts
+}
+
+// betweenFieldsAndFragment appends conflicts of fields with fields of
+// fragment name and fragments spread into it.
+func (m *merger) betweenFieldsAndFragment(conflicts []conflict, exclusive bool, fields *fieldMap, name string) []conflict {
+	key := fieldsFragmentPair{fields, name}
+	if compared, ok := m.fieldsFragmentPairs[key]; ok && (exclusive || !compared) {
+		return conflicts
+	}
+	m.fieldsFragmentPairs[key] = exclusive
+
+	fragment, ok := m.fragments[name]
+	if !ok {
+		return conflicts
+	}
+	ff := m.fragmentFields(fragment)
+	// Fragment spread into its own selection set is compared within it.
+	if ff.fields == fields {
+		return conflicts
+	}
+	conflicts = m.between(conflicts, exclusive, fields, ff.fields)
+	for _, spread := range ff.fragments {
+		conflicts = m.betweenFieldsAndFragment(conflicts, exclusive, fields, spread)
+	}
+	return conflicts
+}
+
+// betweenFragments appends conflicts of fields of two fragments and
+// fragments spread into them.
+func (m *merger) betweenFragments(conflicts []conflict, exclusive bool, name1, name2 string) []conflict {
+	if name1 == name2 {
+		return conflicts
+	}
+	key := fragmentPair{min(name1, name2), max(name1, name2)}
+	if compared, ok := m.fragmentPairs[key]; ok && (exclusive || !compared) {
+		return conflicts
+	}
+	m.fragmentPairs[key] = exclusive
+
+	fragment1, ok1 := m.fragments[name1]
+	fragment2, ok2 := m.fragments[name2]
+	if !ok1 || !ok2 {
+		return conflicts
+	}
+	ff1 := m.fragmentFields(fragment1)
+	ff2 := m.fragmentFields(fragment2)
+
+	conflicts = m.between(conflicts, exclusive, ff1.fields, ff2.fields)
+	for _, spread := range ff2.fragments {
+		conflicts = m.betweenFragments(conflicts, exclusive, name1, spread)
+	}
+	for _, spread := range ff1.fragments {
+		conflicts = m.betweenFragments(conflicts, exclusive, spread, name2)
+	}
+	return conflicts
+}
+
+// between appends conflicts of fields of fields1 with fields of fields2
+// under the same response name.
+func (m *merger) between(conflicts []conflict, exclusive bool, fields1, fields2 *fieldMap) []conflict {
+	for _, key := range fields1.keys {
+		for _, f2 := range fields2.fields[key] {
+			for _, f1 := range fields1.fields[key] {
+				if c, ok := m.findConflict(exclusive, key, f1, f2); ok {
+					conflicts = append(conflicts, c)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// findConflict compares two fields under responseName. Parents of fields
+// are mutually exclusive when they can never apply to the same object.
+func (m *merger) findConflict(parentsExclusive bool, responseName string, f1, f2 fieldAndDef) (conflict, bool) {
+	exclusive := parentsExclusive || (f1.parent != f2.parent && isObject(f1.parent) && isObject(f2.parent))
+	fail := func(format string, args ...any) (conflict, bool) {
+		return conflict{
+			responseName: responseName,
+			message:      fmt.Sprintf(format, args...),
+			fields1:      []*ast.Field{f1.node},
+			fields2:      []*ast.Field{f2.node},
+		}, true
+	}
+
+	if !exclusive {
+		if name1, name2 := f1.node.Name.Value, f2.node.Name.Value; name1 != name2 {
+			return fail("%q and %q are different fields", name1, name2)
+		}
+		if !sameArguments(f1.node.Arguments, f2.node.Arguments) {
+			return fail("they have differing arguments")
+		}
+	}
+
+	var type1, type2 ast.Type
+	if f1.def != nil {
+		type1 = f1.def.Type
+	}
+	if f2.def != nil {
+		type2 = f2.def.Type
+	}
+	if type1 != nil && type2 != nil && m.typesConflict(type1, type2) {
+		return fail("they return conflicting types %q and %q", printer.Print(type1), printer.Print(type2))
+	}
+
+	set1, set2 := f1.node.SelectionSet, f2.node.SelectionSet
+	if set1 == nil || set2 == nil {
+		return conflict{}, false
+	}
+	var parent1, parent2 *schema.Type
+	if type1 != nil {
+		parent1 = m.typ(namedType(type1))
+	}
+	if type2 != nil {
+		parent2 = m.typ(namedType(type2))
+	}
+	sub := m.betweenSubSelections(exclusive, parent1, set1, parent2, set2)
+	if len(sub) == 0 {
+		return conflict{}, false
+	}
+	c := conflict{
+		responseName: responseName,
+		sub:          sub,
+		fields1:      []*ast.Field{f1.node},
+		fields2:      []*ast.Field{f2.node},
+	}
+	for _, s := range sub {
+		c.fields1 = append(c.fields1, s.fields1...)
+		c.fields2 = append(c.fields2, s.fields2...)
+	}
+	return c, true
+}
+
+// typesConflict reports whether values of types t1 and t2 cannot be merged:
+// their list and non-null wrappers differ or they are different leaf
+// types.
+func (m *merger) typesConflict(t1, t2 ast.Type) bool {
+	switch t1 := t1.(type) {
+	case *ast.ListType:
+		if t2, ok := t2.(*ast.ListType); ok {
+			return m.typesConflict(t1.Type, t2.Type)
+		}
+		return true
+	case *ast.NonNullType:
+		if t2, ok := t2.(*ast.NonNullType); ok {
+			return m.typesConflict(t1.Type, t2.Type)
+		}
+		return true
+	}
+	switch t2.(type) {
+	case *ast.ListType, *ast.NonNullType:
+		return true
+	}
+	name1, name2 := namedType(t1), namedType(t2)
+	if m.isLeaf(name1) || m.isLeaf(name2) {
+		return name1 != name2
+	}
+	return false
+}
+
+// isLeaf reports whether name is a scalar or enum type. Built-in scalars
+// need not be defined by schema.
+func (m *merger) isLeaf(name string) bool {
+	t := m.typ(name)
+	if t == nil {
+		return m.ctx.Schema != nil && specifiedScalars[name]
+	}
+	return t.Kind == schema.KindScalar || t.Kind == schema.KindEnum
+}
+
+var specifiedScalars = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+func isObject(t *schema.Type) bool {
+	return t != nil && t.Kind == schema.KindObject
+}
+
+// sameArguments reports whether both lists have the same arguments with
+// equal values, in any order.
+func sameArguments(args1, args2 []*ast.Argument) bool {
+	if len(args1) != len(args2) {
+		return false
+	}
+	for _, a1 := range args1 {
+		found := false
+		for _, a2 := range args2 {
+			if a1.Name.Value == a2.Name.Value {
+				found = printer.Print(a1.Value) == printer.Print(a2.Value)
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldsAndFragments returns fields of set and names of fragments it
+// spreads, collected once per selection set.
+func (m *merger) fieldsAndFragments(parent *schema.Type, set *ast.SelectionSet) fieldsAndFragments {
+	if ff, ok := m.cache[set]; ok {
+		return ff
+	}
+	ff := fieldsAndFragments{fields: &fieldMap{fields: make(map[string][]fieldAndDef)}}
+	m.collect(parent, set, &ff, make(map[string]bool))
+	m.cache[set] = ff
+	return ff
+}
+
+// fragmentFields returns fields of fragment with its type condition as
+// parent type.
+func (m *merger) fragmentFields(fragment *ast.FragmentDefinition) fieldsAndFragments {
+	return m.fieldsAndFragments(m.typ(fragment.TypeCondition.Name.Value), fragment.SelectionSet)
+}
+
+func (m *merger) collect(parent *schema.Type, set *ast.SelectionSet, ff *fieldsAndFragments, spread map[string]bool) {
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			key := s.Name.Value
+			if s.Alias != nil {
+				key = s.Alias.Value
+			}
+			var def *schema.Field
+			if parent != nil {
+				def = parent.Field(s.Name.Value)
+			}
+			if _, ok := ff.fields.fields[key]; !ok {
+				ff.fields.keys = append(ff.fields.keys, key)
+			}
+			ff.fields.fields[key] = append(ff.fields.fields[key], fieldAndDef{parent: parent, node: s, def: def})
+		case *ast.FragmentSpread:
+			if !spread[s.Name.Value] {
+				spread[s.Name.Value] = true
+				ff.fragments = append(ff.fragments, s.Name.Value)
+			}
+		case *ast.InlineFragment:
+			t := parent
+			if s.TypeCondition != nil {
+				t = m.typ(s.TypeCondition.Name.Value)
+			}
+			m.collect(t, s.SelectionSet, ff, spread)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+func TestOverlappingFieldsCanBeMerged(t *testing.T) {
+	rule := OverlappingFieldsCanBeMerged
+	expectErrors(t, rule, `{ a a b: c b: c d(x: 1, y: $v) d(y: $v, x: 1) ... { a } }`)
+	expectErrors(t, rule, `{ ...F ...F } fragment F on T { a }`)
+	expectErrors(t, rule, `{ f { a } f { b } }`)
+	expectErrors(t, rule, `{ a: b a: c }`,
+		expectedError{`Fields "a" conflict because "b" and "c" are different fields. Use different aliases on the fields to fetch both if this was intentional.`, []int{2, 7}},
+	)
+	expectErrors(t, rule, `{ d(x: 1) d(x: 2) d }`,
+		expectedError{`Fields "d" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional.`, []int{2, 10}},
+		expectedError{`Fields "d" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional.`, []int{2, 18}},
+		expectedError{`Fields "d" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional.`, []int{10, 18}},
+	)
+	expectErrors(t, rule, `{ f { x: a } ...F } fragment F on T { f { x: b y: c } f { y: d } }`,
+		expectedError{`Fields "f" conflict because subfields "x" conflict because "a" and "b" are different fields. Use different aliases on the fields to fetch both if this was intentional.`, []int{2, 6, 38, 42}},
+		expectedError{`Fields "f" conflict because subfields "y" conflict because "c" and "d" are different fields. Use different aliases on the fields to fetch both if this was intentional.`, []int{38, 47, 54, 58}},
+	)
+}
+
+func TestOverlappingFieldsCanBeMerged_Schema(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, `
+type Query { pet: Pet u: U }
+interface Pet { name: String }
+type Dog implements Pet { name: String barks: Boolean size: Int nick: String }
+type Cat implements Pet { name: String meows: Boolean size: String nick: String! }
+union U = Dog | Cat`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"Exclusive parents", `{ pet { ... on Dog { x: barks } ... on Cat { x: meows } } }`, nil},
+		{"Same parent", `{ u { ... on Dog { x: barks } ... on Dog { x: nick } } }`, []string{
+			`Fields "x" conflict because "barks" and "nick" are different fields.`,
+		}},
+		{"Abstract parent", `{ pet { x: name ... on Dog { x: nick } } }`, []string{
+			`Fields "x" conflict because "name" and "nick" are different fields.`,
+		}},
+		{"Different leaf types", `{ u { ... on Dog { size } ... on Cat { size } } }`, []string{
+			`Fields "size" conflict because they return conflicting types "Int" and "String".`,
+		}},
+		{"Different nullability", `{ pet { ... on Dog { nick } ...C } } fragment C on Cat { nick }`, []string{
+			`Fields "nick" conflict because they return conflicting types "String" and "String!".`,
+		}},
+		{"Nested exclusive", `{ a: u { ... on Dog { x: size } } a: u { ... on Cat { x: name } } }`, []string{
+			`Fields "a" conflict because subfields "x" conflict because they return conflicting types "Int" and "String".`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Validate(s, asttest.Parse(t, tt.input), OverlappingFieldsCanBeMerged) {
+				got = append(got, strings.TrimSuffix(e.Message, " Use different aliases on the fields to fetch both if this was intentional."))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOverlappingFieldsCanBeMerged_ManyFragments(t *testing.T) {
+	// Every fragment spreads all following ones, which takes exponential
+	// time without memoization.
+	const n = 100
+	var b strings.Builder
+	b.WriteString("{ ...F0 }")
+	for i := range n {
+		fmt.Fprintf(&b, " fragment F%d on T { a f%d: b", i, i)
+		for j := i + 1; j < n; j++ {
+			fmt.Fprintf(&b, " ...F%d", j)
+		}
+		b.WriteString(" }")
+	}
+	fmt.Fprintf(&b, " fragment G on T { a: c ...F0 }")
+	errs := Validate(nil, asttest.Parse(t, b.String()), OverlappingFieldsCanBeMerged)
+	if len(errs) != n {
+		t.Errorf("expected %d errors, got %d", n, len(errs))
+	}
+}
@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/parser"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Stream validates a document one definition at a time, so huge documents
+// can be validated while they are parsed without retaining them. Only
+// operation names and fragment spreads of checked definitions are kept.
+//
+// Rules are checked against a document consisting of the current
+// definition only, so they must not depend on other definitions. Operation
+// name uniqueness and fragment cycles are checked by Stream itself; a
+// cycle is reported at the definition of the fragment closing it.
+type Stream struct {
+	schema *schema.Schema
+	rules  []Rule
+
+	operations map[string]*ast.Name
+	fragments  map[string][]*ast.FragmentSpread
+}
+
+// NewStream returns stream validating definitions against s with rules,
+// ExecutableDefinitions when none are given.
+func NewStream(s *schema.Schema, rules ...Rule) *Stream {
+	if len(rules) == 0 {
+		rules = []Rule{ExecutableDefinitions}
+	}
+	return &Stream{
+		schema:     s,
+		rules:      rules,
+		operations: make(map[string]*ast.Name),
+		fragments:  make(map[string][]*ast.FragmentSpread),
+	}
+}
+
+// Check validates def, the next definition of the document.
+func (st *Stream) Check(def ast.Definition) []*Error {
+	ctx := &Context{Schema: st.schema, Document: &ast.Document{Definitions: []ast.Definition{def}}}
+	for _, rule := range st.rules {
+		ctx.rule = rule.Name
+		rule.Check(ctx)
+	}
+
+	switch d := def.(type) {
+	case *ast.OperationDefinition:
+		if d.Name == nil {
+			break
+		}
+		ctx.rule = UniqueOperationNames.Name
+		if other, ok := st.operations[d.Name.Value]; ok {
+			ctx.Reportf([]ast.Node{other, d.Name}, "There can be only one operation named %q.", d.Name.Value)
+		} else {
+			st.operations[d.Name.Value] = d.Name
+		}
+	case *ast.FragmentDefinition:
+		name := d.Name.Value
+		if _, ok := st.fragments[name]; ok {
+			break
+		}
+		st.fragments[name] = fragmentSpreads(d.SelectionSet, nil)
+		ctx.rule = NoFragmentCycles.Name
+		st.cycles(name, func(cycle []*ast.FragmentSpread) {
+			reportCycle(ctx, name, cycle)
+		})
+	}
+	return ctx.errs
+}
+
+// cycles calls fn with every cycle of spreads leading from fragment name
+// back to it.
+func (st *Stream) cycles(name string, fn func(cycle []*ast.FragmentSpread)) {
+	visited := make(map[string]bool)
+	var path []*ast.FragmentSpread
+	var walk func(from string)
+	walk = func(from string) {
+		visited[from] = true
+		for _, spread := range st.fragments[from] {
+			path = append(path, spread)
+			if target := spread.Name.Value; target == name {
+				fn(append([]*ast.FragmentSpread(nil), path...))
+			} else if !visited[target] {
+				walk(target)
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	walk(name)
+}
+
+// ValidateStream parses definitions with p and validates them with a
+// Stream, calling fn with every definition and its errors as soon as it
+// is parsed. It stops when fn returns false and returns parse error, if
+// any.
+func ValidateStream(s *schema.Schema, p *parser.Parser, fn func(def ast.Definition, errs []*Error) bool, rules ...Rule) error {
+	st := NewStream(s, rules...)
+	for {
+		def, err := p.ParseDefinition()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !fn(def, st.Check(def)) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/lexer"
+	"github.com/gqlhub/gqlhub-core/parser"
+)
+
+func TestValidateStream(t *testing.T) {
+	input := `query A { a } fragment F on T { ...G } type X { a: Int } query A { b } fragment G on T { ...F } { ...G }`
+	p, err := parser.New(lexer.New(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got [][]expectedError
+	err = ValidateStream(nil, p, func(def ast.Definition, errs []*Error) bool {
+		var defErrors []expectedError
+		for _, e := range errs {
+			defErrors = append(defErrors, expectedError{e.Rule + ": " + e.Message, e.Positions})
+		}
+		got = append(got, defErrors)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]expectedError{
+		nil,
+		nil,
+		{{`ExecutableDefinitions: The "X" definition is not executable.`, []int{strings.Index(input, "type X")}}},
+		{{`UniqueOperationNames: There can be only one operation named "A".`, []int{6, strings.LastIndex(input, "A {")}}},
+		{{`NoFragmentCycles: Cannot spread fragment "G" within itself via "F".`, []int{strings.Index(input, "...F"), strings.Index(input, "...G")}}},
+		nil,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestValidateStream_Stop(t *testing.T) {
+	p, err := parser.New(lexer.New(`type X { a: Int } { a } {`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	count := 0
+	err = ValidateStream(nil, p, func(def ast.Definition, errs []*Error) bool {
+		count++
+		return len(errs) == 0
+	})
+	if err != nil || count != 1 {
+		t.Errorf("expected to stop at the first invalid definition, got %d definitions and error %v", count, err)
+	}
+
+	p, err = parser.New(lexer.New(`{ a } {`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	count = 0
+	err = ValidateStream(nil, p, func(ast.Definition, []*Error) bool {
+		count++
+		return true
+	})
+	if err == nil || count != 1 {
+		t.Errorf("expected parse error after the first definition, got %d definitions and error %v", count, err)
+	}
+}
+
+func TestStream_SelfSpread(t *testing.T) {
+	st := NewStream(nil)
+	doc := asttest.Parse(t, `fragment F on T { ...F ...F }`)
+	errs := st.Check(doc.Definitions[0])
+	if len(errs) != 2 || errs[0].Message != `Cannot spread fragment "F" within itself.` {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if errs := st.Check(doc.Definitions[0]); len(errs) != 0 {
+		t.Errorf("expected duplicate fragment to be skipped, got %v", errs)
+	}
+}
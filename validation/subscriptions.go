@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+)
+
+// SingleFieldSubscriptions checks that subscription operations select
+// exactly one root field, which is not an introspection field. Fields are
+// collected without variables; without schema every fragment is assumed
+// to apply to the subscription root type.
+//
+// https://spec.graphql.org/draft/#sec-Single-root-field
+var SingleFieldSubscriptions = Rule{
+	Name: "SingleFieldSubscriptions",
+	Check: func(ctx *Context) {
+		fragments := fragmentDefinitions(ctx.Document)
+
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok || op.OperationType != ast.OperationTypeSubscription {
+				continue
+			}
+			c := rootFieldCollector{ctx: ctx, fragments: fragments, visited: make(map[string]bool)}
+			if ctx.Schema != nil {
+				if root := ctx.Schema.SubscriptionType(); root != nil {
+					c.rootType = root.Name
+				}
+			}
+			c.collect(op.SelectionSet)
+
+			subject := "Anonymous Subscription"
+			if op.Name != nil {
+				subject = `Subscription "` + op.Name.Value + `"`
+			}
+			if len(c.keys) > 1 {
+				var extra []ast.Node
+				for _, key := range c.keys[1:] {
+					for _, f := range c.fields[key] {
+						extra = append(extra, f)
+					}
+				}
+				ctx.Reportf(extra, "%s must select only one top level field.", subject)
+			}
+			for _, key := range c.keys {
+				fields := c.fields[key]
+				if !strings.HasPrefix(fields[0].Name.Value, "__") {
+					continue
+				}
+				nodes := make([]ast.Node, len(fields))
+				for i, f := range fields {
+					nodes[i] = f
+				}
+				ctx.Reportf(nodes, "%s must not select an introspection top level field.", subject)
+			}
+		}
+	},
+}
+
+// rootFieldCollector groups root fields of an operation by response key.
+type rootFieldCollector struct {
+	ctx       *Context
+	fragments map[string]*ast.FragmentDefinition
+	rootType  string // Empty when unknown.
+	visited   map[string]bool
+
+	keys   []string
+	fields map[string][]*ast.Field
+}
+
+func (c *rootFieldCollector) collect(set *ast.SelectionSet) {
+	if set == nil {
+		return
+	}
+	if c.fields == nil {
+		c.fields = make(map[string][]*ast.Field)
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if skipped(s.Directives) {
+				continue
+			}
+			key := s.Name.Value
+			if s.Alias != nil {
+				key = s.Alias.Value
+			}
+			if _, ok := c.fields[key]; !ok {
+				c.keys = append(c.keys, key)
+			}
+			c.fields[key] = append(c.fields[key], s)
+		case *ast.InlineFragment:
+			if skipped(s.Directives) || (s.TypeCondition != nil && !c.applies(s.TypeCondition.Name.Value)) {
+				continue
+			}
+			c.collect(s.SelectionSet)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			if skipped(s.Directives) || c.visited[name] {
+				continue
+			}
+			c.visited[name] = true
+			f, ok := c.fragments[name]
+			if !ok || !c.applies(f.TypeCondition.Name.Value) {
+				continue
+			}
+			c.collect(f.SelectionSet)
+		}
+	}
+}
+
+// applies reports whether fragment with type condition applies to the
+// root type.
+func (c *rootFieldCollector) applies(condition string) bool {
+	if c.rootType == "" || condition == c.rootType {
+		return true
+	}
+	t := c.ctx.Schema.Type(condition)
+	if t == nil {
+		return false
+	}
+	if slices.Contains(t.Types, c.rootType) {
+		return true
+	}
+	root := c.ctx.Schema.Type(c.rootType)
+	return slices.Contains(root.Interfaces, condition)
+}
+
+// skipped reports whether @skip or @include with literal condition
+// excludes selection. Conditions depending on variables are assumed to
+// include it.
+func skipped(directives []*ast.Directive) bool {
+	for _, d := range directives {
+		for _, arg := range d.Arguments {
+			b, ok := arg.Value.(*ast.BooleanValue)
+			if !ok || arg.Name.Value != "if" {
+				continue
+			}
+			if (d.Name.Value == "skip" && b.Value) || (d.Name.Value == "include" && !b.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
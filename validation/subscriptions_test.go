@@ -0,0 +1,48 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+func TestSingleFieldSubscriptions(t *testing.T) {
+	rule := SingleFieldSubscriptions
+	expectErrors(t, rule, `subscription S { a } query Q { a b }`)
+	expectErrors(t, rule, `subscription S { a a } subscription T { x: a ... { x: a } }`)
+	expectErrors(t, rule, `subscription S { a b @skip(if: true) c @include(if: false) }`)
+	expectErrors(t, rule, `subscription S { a b c }`,
+		expectedError{`Subscription "S" must select only one top level field.`, []int{19, 21}},
+	)
+	expectErrors(t, rule, `subscription { a ...F } fragment F on Subscription { b ...F }`,
+		expectedError{`Anonymous Subscription must select only one top level field.`, []int{53}},
+	)
+	expectErrors(t, rule, `subscription S { __typename }`,
+		expectedError{`Subscription "S" must not select an introspection top level field.`, []int{17}},
+	)
+	expectErrors(t, rule, `subscription S { a t: __typename }`,
+		expectedError{`Subscription "S" must select only one top level field.`, []int{19}},
+		expectedError{`Subscription "S" must not select an introspection top level field.`, []int{19}},
+	)
+}
+
+func TestSingleFieldSubscriptions_Schema(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, `
+type Query { a: Int }
+interface Node { id: ID }
+type Subscription implements Node { id: ID a: Int b: Int }
+type Other { b: Int }
+union U = Subscription | Other`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input := `subscription { a ... on Other { b } ... on Node { id } ... on U { c: b } ...F } fragment F on Query { b }`
+	errs := Validate(s, asttest.Parse(t, input), SingleFieldSubscriptions)
+	if len(errs) != 1 || len(errs[0].Positions) != 2 {
+		t.Fatalf("expected error at fields from Node and U fragments, got %v", errs)
+	}
+	if errs[0].Positions[0] != 50 || errs[0].Positions[1] != 66 {
+		t.Errorf("unexpected positions %v", errs[0].Positions)
+	}
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+)
+
+// KnownTypeNames checks that types of variables and type conditions of
+// fragments are defined by schema.
+//
+// https://spec.graphql.org/draft/#sec-Fragment-Spread-Type-Existence
+var KnownTypeNames = Rule{
+	Name: "KnownTypeNames",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		var v ast.TypeVisitor
+		ast.OnEnter(&v, func(n ast.Definition) ast.Action {
+			switch n.(type) {
+			case *ast.OperationDefinition, *ast.FragmentDefinition:
+				return ast.Continue
+			}
+			return ast.Skip
+		})
+		// Named types of executable definitions are only found in
+		// variable types and type conditions.
+		ast.OnEnter(&v, func(n *ast.NamedType) ast.Action {
+			if !knownType(ctx.Schema, n.Name.Value) {
+				ctx.Reportf([]ast.Node{n}, "Unknown type %q.", n.Name.Value)
+			}
+			return ast.Skip
+		})
+		ast.WalkDocument(&v, ctx.Document)
+	},
+}
+
+// FragmentsOnCompositeTypes checks that type conditions of fragments are
+// object, interface or union types. Unknown types are reported by
+// KnownTypeNames.
+//
+// https://spec.graphql.org/draft/#sec-Fragments-On-Composite-Types
+var FragmentsOnCompositeTypes = Rule{
+	Name: "FragmentsOnCompositeTypes",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		composite := func(condition *ast.NamedType) bool {
+			if specifiedScalars[condition.Name.Value] {
+				return false
+			}
+			t := ctx.Schema.Type(condition.Name.Value)
+			return t == nil || isComposite(t)
+		}
+		var v ast.TypeVisitor
+		ast.OnEnter(&v, func(n *ast.InlineFragment) ast.Action {
+			if n.TypeCondition != nil && !composite(n.TypeCondition) {
+				ctx.Reportf([]ast.Node{n.TypeCondition}, "Fragment cannot condition on non composite type %q.", n.TypeCondition.Name.Value)
+			}
+			return ast.Continue
+		})
+		ast.OnEnter(&v, func(n *ast.FragmentDefinition) ast.Action {
+			if !composite(n.TypeCondition) {
+				ctx.Reportf([]ast.Node{n.TypeCondition}, "Fragment %q cannot condition on non composite type %q.", n.Name.Value, n.TypeCondition.Name.Value)
+			}
+			return ast.Continue
+		})
+		ast.WalkDocument(&v, ctx.Document)
+	},
+}
+
+// VariablesAreInputTypes checks that variables are of scalar, enum or
+// input object types. Unknown types are reported by KnownTypeNames.
+//
+// https://spec.graphql.org/draft/#sec-Variables-Are-Input-Types
+var VariablesAreInputTypes = Rule{
+	Name: "VariablesAreInputTypes",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok {
+				continue
+			}
+			for _, v := range op.VariableDefs {
+				name := namedType(v.Type)
+				if knownType(ctx.Schema, name) && !isInputType(ctx.Schema, name) {
+					ctx.Reportf([]ast.Node{v.Type}, "Variable \"$%s\" cannot be non-input type %q.", v.Variable.Name.Value, printer.Print(v.Type))
+				}
+			}
+		}
+	},
+}
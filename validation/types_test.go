@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+)
+
+func TestKnownTypeNames(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := KnownTypeNames
+	expectErrors(t, rule, `query ($a: Unknown) { ... on Unknown { a } }`)
+	expectSchemaErrors(t, s, rule, `query ($a: [Int!], $b: In, $c: ID) { ... on Pet { name } } fragment F on Dog { name }`)
+	expectSchemaErrors(t, s, rule, `query ($a: In, $b: [Pet!], $c: Unknown) { pet { ... on Unknown { name } } } fragment F on Foo { name }`,
+		expectedError{`Unknown type "Unknown".`, []int{31}},
+		expectedError{`Unknown type "Unknown".`, []int{55}},
+		expectedError{`Unknown type "Foo".`, []int{90}},
+	)
+	expectSchemaErrors(t, s, rule, `{ name } type T implements Unknown { a: Unknown }`)
+}
+
+func TestFragmentsOnCompositeTypes(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := FragmentsOnCompositeTypes
+	expectSchemaErrors(t, s, rule, `{ ... on Pet { name } ... { name } ... on Unknown { a } } fragment F on Result { __typename }`)
+	expectSchemaErrors(t, s, rule, `{ ... on Color { a } ...F } fragment F on In { a } fragment G on Int { a } fragment H on Result { a }`,
+		expectedError{`Fragment cannot condition on non composite type "Color".`, []int{9}},
+		expectedError{`Fragment "F" cannot condition on non composite type "In".`, []int{42}},
+		expectedError{`Fragment "G" cannot condition on non composite type "Int".`, []int{65}},
+	)
+}
+
+func TestVariablesAreInputTypes(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	rule := VariablesAreInputTypes
+	expectErrors(t, rule, `query ($a: Pet) { name }`)
+	expectSchemaErrors(t, s, rule, `query ($a: In, $b: [Pet!], $c: Unknown, $d: [Color]!, $e: String) { name }`,
+		expectedError{`Variable "$b" cannot be non-input type "[Pet!]".`, []int{19}},
+	)
+}
@@ -0,0 +1,24 @@
+package validation
+
+import "github.com/gqlhub/gqlhub-core/ast"
+
+// UniqueOperationNames checks that each named operation is defined once.
+//
+// https://spec.graphql.org/draft/#sec-Operation-Name-Uniqueness
+var UniqueOperationNames = Rule{
+	Name: "UniqueOperationNames",
+	Check: func(ctx *Context) {
+		first := make(map[string]*ast.Name)
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok || op.Name == nil {
+				continue
+			}
+			if other, ok := first[op.Name.Value]; ok {
+				ctx.Reportf([]ast.Node{other, op.Name}, "There can be only one operation named %q.", op.Name.Value)
+				continue
+			}
+			first[op.Name.Value] = op.Name
+		}
+	},
+}
@@ -0,0 +1,93 @@
+// Package validation checks executable documents against the validation
+// rules of the GraphQL specification.
+//
+// https://spec.graphql.org/draft/#sec-Validation
+package validation
+
+import (
+	"fmt"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Error is a validation error. Positions are byte offsets of offending
+// nodes in the document, e.g. of both conflicting definitions.
+type Error struct {
+	Rule      string
+	Message   string
+	Positions []int
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Rule is a validation rule.
+type Rule struct {
+	Name  string
+	Check func(ctx *Context)
+}
+
+// Context is passed to rules.
+type Context struct {
+	Schema   *schema.Schema // May be nil for rules not depending on schema.
+	Document *ast.Document
+
+	rule string
+	errs []*Error
+}
+
+// Reportf reports error at positions of nodes.
+func (c *Context) Reportf(nodes []ast.Node, format string, args ...any) {
+	positions := make([]int, len(nodes))
+	for i, n := range nodes {
+		positions[i] = n.Pos()
+	}
+	c.errs = append(c.errs, &Error{Rule: c.rule, Message: fmt.Sprintf(format, args...), Positions: positions})
+}
+
+// SpecifiedRules are rules of the specification in order they are
+// checked. Rules depending on types are skipped without schema.
+var SpecifiedRules = []Rule{
+	ExecutableDefinitions,
+	UniqueOperationNames,
+	LoneAnonymousOperation,
+	SingleFieldSubscriptions,
+	KnownTypeNames,
+	FragmentsOnCompositeTypes,
+	VariablesAreInputTypes,
+	ScalarLeafs,
+	FieldsOnCorrectType,
+	UniqueFragmentNames,
+	KnownFragmentNames,
+	NoUnusedFragments,
+	PossibleFragmentSpreads,
+	NoFragmentCycles,
+	UniqueVariableNames,
+	NoUndefinedVariables,
+	NoUnusedVariables,
+	KnownDirectives,
+	UniqueDirectivesPerLocation,
+	KnownArgumentNames,
+	UniqueArgumentNames,
+	ValuesOfCorrectType,
+	ProvidedRequiredArguments,
+	VariablesInAllowedPosition,
+	OverlappingFieldsCanBeMerged,
+	UniqueInputFieldNames,
+}
+
+// Validate checks doc with rules, SpecifiedRules when none are given, and
+// returns errors in order they were found.
+func Validate(s *schema.Schema, doc *ast.Document, rules ...Rule) []*Error {
+	if len(rules) == 0 {
+		rules = SpecifiedRules
+	}
+	ctx := &Context{Schema: s, Document: doc}
+	for _, rule := range rules {
+		ctx.rule = rule.Name
+		rule.Check(ctx)
+	}
+	return ctx.errs
+}
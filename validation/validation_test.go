@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+type expectedError struct {
+	message   string
+	positions []int
+}
+
+// expectErrors validates input with rule and compares reported errors.
+func expectErrors(t *testing.T, rule Rule, input string, expected ...expectedError) {
+	t.Helper()
+	expectSchemaErrors(t, nil, rule, input, expected...)
+}
+
+// expectSchemaErrors validates input against s with rule and compares
+// reported errors.
+func expectSchemaErrors(t *testing.T, s *schema.Schema, rule Rule, input string, expected ...expectedError) {
+	t.Helper()
+	var got []expectedError
+	for _, e := range Validate(s, asttest.Parse(t, input), rule) {
+		if e.Rule != rule.Name {
+			t.Errorf("expected rule %s, got %s", rule.Name, e.Rule)
+		}
+		got = append(got, expectedError{e.Message, e.Positions})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+// testSDL is schema shared by tests of rules depending on types.
+const testSDL = `
+schema { query: Query mutation: Mutation }
+directive @d(x: Int, req: Int!) repeatable on FIELD | QUERY
+directive @once on FIELD | FRAGMENT_SPREAD
+type Query {
+  pet: Pet
+  dog(id: ID!, name: String = "x"): Dog
+  pets: [Pet]
+  search(in: In): [Result]
+  name: String
+  color: Color
+}
+type Mutation { rename(name: String!): Pet }
+interface Pet { name: String }
+type Dog implements Pet { name: String barks: Boolean owner: Human }
+type Cat implements Pet { name: String meows: Boolean }
+type Human { name: String }
+union Result = Dog | Human
+enum Color { RED }
+input In { color: Color limit: Int }`
+
+func TestUniqueOperationNames(t *testing.T) {
+	expectErrors(t, UniqueOperationNames, `query A { a } query B { a } { a }`)
+	expectErrors(t, UniqueOperationNames, `query A { a } mutation A { a } subscription A { a }`,
+		expectedError{`There can be only one operation named "A".`, []int{6, 23}},
+		expectedError{`There can be only one operation named "A".`, []int{6, 44}},
+	)
+}
+
+func TestValidate_SpecifiedRules(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	valid := `
+query Q($id: ID!, $in: In = {limit: 10}, $withOwner: Boolean = false) {
+  dog(id: $id) { ...DogFields owner @include(if: $withOwner) { name } }
+  search(in: $in) { __typename ... on Human { name } ...DogFields }
+}
+fragment DogFields on Dog { name barks }
+mutation M { rename(name: "x") { name ... on Cat { meows } } }`
+	if errs := Validate(s, asttest.Parse(t, valid)); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	invalid := `
+query Q($id: ID) {
+  dog(id: $id) { name: barks name }
+  pet { ...Unknown }
+  color(x: 1)
+}
+fragment Unused on Dog { name }`
+	var rules []string
+	for _, e := range Validate(s, asttest.Parse(t, invalid)) {
+		rules = append(rules, e.Rule)
+	}
+	expected := []string{
+		"KnownFragmentNames",
+		"NoUnusedFragments",
+		"KnownArgumentNames",
+		"VariablesInAllowedPosition",
+		"OverlappingFieldsCanBeMerged",
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected %v, got %v", expected, rules)
+	}
+}
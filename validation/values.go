@@ -0,0 +1,280 @@
+package validation
+
+import (
+	"strconv"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// LiteralValidator checks literal value of a custom scalar type. Literals
+// are never variables or null.
+type LiteralValidator func(v ast.Value) error
+
+// ValuesOfCorrectType checks that literal values of arguments, variable
+// defaults and their nested fields and items are of the type expected in
+// their position. Literals of custom scalars are accepted. The rule
+// requires schema.
+//
+// https://spec.graphql.org/draft/#sec-Values-of-Correct-Type
+var ValuesOfCorrectType = ValuesOfCorrectTypeWith(nil)
+
+// ValuesOfCorrectTypeWith returns ValuesOfCorrectType checking literals of
+// custom scalars with validators by scalar name, such as
+//
+//	validation.ValuesOfCorrectTypeWith(map[string]validation.LiteralValidator{
+//		"DateTime": func(v ast.Value) error {
+//			s, ok := v.(*ast.StringValue)
+//			if !ok {
+//				return errors.New("expected RFC 3339 string")
+//			}
+//			_, err := time.Parse(time.RFC3339, s.Value)
+//			return err
+//		},
+//	})
+//
+// Literals rejected by a validator are reported as
+// `Expected value of type "DateTime", found "x"; <error>`.
+func ValuesOfCorrectTypeWith(scalars map[string]LiteralValidator) Rule {
+	return Rule{
+		Name: "ValuesOfCorrectType",
+		Check: func(ctx *Context) {
+			if ctx.Schema == nil {
+				return
+			}
+			c := valueChecker{ctx: ctx, scalars: scalars}
+			for _, def := range ctx.Document.Definitions {
+				switch d := def.(type) {
+				case *ast.OperationDefinition:
+					for _, v := range d.VariableDefs {
+						if v.DefaultValue != nil {
+							c.value(v.DefaultValue, v.Type)
+						}
+						c.directives(v.Directives)
+					}
+					c.directives(d.Directives)
+					c.selectionSet(ctx.Schema.RootType(d.OperationType), d.SelectionSet)
+				case *ast.FragmentDefinition:
+					c.directives(d.Directives)
+					c.selectionSet(ctx.Schema.Type(d.TypeCondition.Name.Value), d.SelectionSet)
+				}
+			}
+		},
+	}
+}
+
+// valueChecker checks literals of a document.
+type valueChecker struct {
+	ctx     *Context
+	scalars map[string]LiteralValidator
+}
+
+func (c *valueChecker) selectionSet(parent *schema.Type, set *ast.SelectionSet) {
+	if set == nil {
+		return
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			c.directives(s.Directives)
+			var field *schema.Field
+			if parent != nil {
+				field = parent.Field(s.Name.Value)
+			}
+			if field == nil {
+				c.selectionSet(nil, s.SelectionSet)
+				continue
+			}
+			c.arguments(field.Arguments, s.Arguments)
+			c.selectionSet(c.ctx.Schema.Type(namedType(field.Type)), s.SelectionSet)
+		case *ast.InlineFragment:
+			c.directives(s.Directives)
+			t := parent
+			if s.TypeCondition != nil {
+				t = c.ctx.Schema.Type(s.TypeCondition.Name.Value)
+			}
+			c.selectionSet(t, s.SelectionSet)
+		case *ast.FragmentSpread:
+			c.directives(s.Directives)
+		}
+	}
+}
+
+func (c *valueChecker) directives(directives []*ast.Directive) {
+	for _, d := range directives {
+		if def := lookupDirective(c.ctx.Schema, d.Name.Value); def != nil {
+			c.arguments(def.Arguments, d.Arguments)
+		}
+	}
+}
+
+// arguments checks values of args defined by defs. Unknown arguments are
+// skipped.
+func (c *valueChecker) arguments(defs []*schema.InputValue, args []*ast.Argument) {
+	for _, arg := range args {
+		for _, def := range defs {
+			if def.Name == arg.Name.Value {
+				c.value(arg.Value, def.Type)
+				break
+			}
+		}
+	}
+}
+
+// value checks v in position of type t.
+func (c *valueChecker) value(v ast.Value, t ast.Type) {
+	if _, ok := v.(*ast.Variable); ok {
+		return
+	}
+	if nonNull, ok := t.(*ast.NonNullType); ok {
+		if _, ok := v.(*ast.NullValue); ok {
+			c.ctx.Reportf([]ast.Node{v}, "Expected value of type %q, found null.", printer.Print(t))
+			return
+		}
+		t = nonNull.Type
+	}
+	if _, ok := v.(*ast.NullValue); ok {
+		return
+	}
+	if list, ok := t.(*ast.ListType); ok {
+		// Single value is coerced to a list of one item.
+		if l, ok := v.(*ast.ListValue); ok {
+			for _, item := range l.Values {
+				c.value(item, list.Type)
+			}
+		} else {
+			c.value(v, list.Type)
+		}
+		return
+	}
+
+	name := namedType(t)
+	typ := c.ctx.Schema.Type(name)
+	switch {
+	case typ == nil && specifiedScalars[name]:
+		c.specifiedScalar(v, name)
+	case typ == nil:
+	case typ.Kind == schema.KindInputObject:
+		c.object(v, typ)
+	case typ.Kind == schema.KindEnum:
+		c.enum(v, typ)
+	case typ.Kind == schema.KindScalar && specifiedScalars[name]:
+		c.specifiedScalar(v, name)
+	case typ.Kind == schema.KindScalar:
+		validate, ok := c.scalars[name]
+		if !ok {
+			return
+		}
+		if err := validate(v); err != nil {
+			c.ctx.Reportf([]ast.Node{v}, "Expected value of type %q, found %s; %v", name, printer.Print(v), err)
+		}
+	}
+}
+
+func (c *valueChecker) object(v ast.Value, t *schema.Type) {
+	obj, ok := v.(*ast.ObjectValue)
+	if !ok {
+		c.ctx.Reportf([]ast.Node{v}, "Expected value of type %q, found %s.", t.Name, printer.Print(v))
+		return
+	}
+	for _, def := range t.InputFields {
+		_, required := def.Type.(*ast.NonNullType)
+		if !required || def.DefaultValue != nil {
+			continue
+		}
+		if objectField(obj, def.Name) == nil {
+			c.ctx.Reportf([]ast.Node{obj}, "Field \"%s.%s\" of required type %q was not provided.", t.Name, def.Name, printer.Print(def.Type))
+		}
+	}
+	for _, f := range obj.Fields {
+		def := t.InputField(f.Name.Value)
+		if def == nil {
+			c.ctx.Reportf([]ast.Node{f}, "Field %q is not defined by type %q.", f.Name.Value, t.Name)
+			continue
+		}
+		c.value(f.Value, def.Type)
+	}
+}
+
+func objectField(obj *ast.ObjectValue, name string) *ast.ObjectField {
+	for _, f := range obj.Fields {
+		if f.Name.Value == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (c *valueChecker) enum(v ast.Value, t *schema.Type) {
+	e, ok := v.(*ast.EnumValue)
+	if !ok {
+		c.ctx.Reportf([]ast.Node{v}, "Enum %q cannot represent non-enum value: %s.", t.Name, printer.Print(v))
+		return
+	}
+	if t.EnumValue(e.Value) == nil {
+		c.ctx.Reportf([]ast.Node{v}, "Value %q does not exist in %q enum.", e.Value, t.Name)
+	}
+}
+
+// specifiedScalar checks literal of a built-in scalar as its parseLiteral
+// in graphql-js does.
+func (c *valueChecker) specifiedScalar(v ast.Value, name string) {
+	var message string
+	switch name {
+	case "Int":
+		switch v := v.(type) {
+		case *ast.IntValue:
+			if _, err := strconv.ParseInt(v.Value, 10, 32); err != nil {
+				message = "Int cannot represent non 32-bit signed integer value: " + v.Value
+			}
+		default:
+			message = "Int cannot represent non-integer value: " + printer.Print(v)
+		}
+	case "Float":
+		switch v.(type) {
+		case *ast.IntValue, *ast.FloatValue:
+		default:
+			message = "Float cannot represent non numeric value: " + printer.Print(v)
+		}
+	case "String":
+		if _, ok := v.(*ast.StringValue); !ok {
+			message = "String cannot represent a non string value: " + printer.Print(v)
+		}
+	case "Boolean":
+		if _, ok := v.(*ast.BooleanValue); !ok {
+			message = "Boolean cannot represent a non boolean value: " + printer.Print(v)
+		}
+	case "ID":
+		switch v.(type) {
+		case *ast.StringValue, *ast.IntValue:
+		default:
+			message = "ID cannot represent a non-string and non-integer value: " + printer.Print(v)
+		}
+	}
+	if message != "" {
+		c.ctx.Reportf([]ast.Node{v}, "%s", message)
+	}
+}
+
+// UniqueInputFieldNames checks that each field of an input object value
+// is given once. All fields with the same name are reported together.
+//
+// https://spec.graphql.org/draft/#sec-Input-Object-Field-Uniqueness
+var UniqueInputFieldNames = Rule{
+	Name: "UniqueInputFieldNames",
+	Check: func(ctx *Context) {
+		var v ast.TypeVisitor
+		ast.OnEnter(&v, func(n *ast.ObjectValue) ast.Action {
+			for _, group := range duplicates(n.Fields, func(f *ast.ObjectField) string { return f.Name.Value }) {
+				names := make([]ast.Node, len(group))
+				for i, f := range group {
+					names[i] = f.Name
+				}
+				ctx.Reportf(names, "There can be only one input field named %q.", group[0].Name.Value)
+			}
+			return ast.Continue
+		})
+		ast.WalkDocument(&v, ctx.Document)
+	},
+}
@@ -0,0 +1,161 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+const valuesSchema = `
+scalar DateTime
+scalar JSON
+directive @d(at: DateTime!) on FIELD
+type Query {
+  f(i: Int, fl: Float, s: String, b: Boolean, id: ID, e: E, in: In, l: [Int!], at: DateTime, j: JSON): Int
+}
+enum E { A B }
+input In { a: Int! b: Int! = 1 c: [In!] }`
+
+func TestValuesOfCorrectType(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, valuesSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"Valid", `query ($v: Int = 1, $w: In = {a: 1}) { f(i: -1, fl: 1, s: "s", b: true, id: 1, e: A, in: {a: $v, c: [{a: 2}]}, l: 1, at: "x", j: {x: [y]}) }`, nil},
+		{"Null", `{ f(i: null, in: {a: null}, l: [null]) }`, []string{
+			`Expected value of type "Int!", found null.`,
+			`Expected value of type "Int!", found null.`,
+		}},
+		{"Built-in scalars", `{ f(i: 1.5, fl: "1", s: 1, b: "true", id: 1.0) f(i: 2147483648) }`, []string{
+			`Int cannot represent non-integer value: 1.5`,
+			`Float cannot represent non numeric value: "1"`,
+			`String cannot represent a non string value: 1`,
+			`Boolean cannot represent a non boolean value: "true"`,
+			`ID cannot represent a non-string and non-integer value: 1.0`,
+			`Int cannot represent non 32-bit signed integer value: 2147483648`,
+		}},
+		{"Enum", `{ f(e: C) f(e: "A") }`, []string{
+			`Value "C" does not exist in "E" enum.`,
+			`Enum "E" cannot represent non-enum value: "A".`,
+		}},
+		{"Input object", `query ($v: In = {b: 2, x: 1}) { f(in: 1, l: [1, "2"]) }`, []string{
+			`Field "In.a" of required type "Int!" was not provided.`,
+			`Field "x" is not defined by type "In".`,
+			`Expected value of type "In", found 1.`,
+			`Int cannot represent non-integer value: "2"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Validate(s, asttest.Parse(t, tt.input), ValuesOfCorrectType) {
+				got = append(got, e.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValuesOfCorrectTypeWith(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, valuesSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rule := ValuesOfCorrectTypeWith(map[string]LiteralValidator{
+		"DateTime": func(v ast.Value) error {
+			s, ok := v.(*ast.StringValue)
+			if !ok {
+				return errors.New("expected RFC 3339 string")
+			}
+			_, err := time.Parse(time.RFC3339, s.Value)
+			return err
+		},
+	})
+	input := `{ f(at: "2024-01-02T03:04:05Z", j: 1) @d(at: 5) f(at: "yesterday") }`
+	errs := Validate(s, asttest.Parse(t, input), rule)
+	expected := []string{
+		`Expected value of type "DateTime", found 5; expected RFC 3339 string`,
+		`Expected value of type "DateTime", found "yesterday"; parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range errs {
+		if e.Rule != "ValuesOfCorrectType" || e.Message != expected[i] {
+			t.Errorf("expected %q, got %s: %q", expected[i], e.Rule, e.Message)
+		}
+	}
+	if errs[0].Positions[0] != strings.Index(input, "at: 5")+4 {
+		t.Errorf("unexpected position %v", errs[0].Positions)
+	}
+}
+
+func TestUniqueInputFieldNames(t *testing.T) {
+	rule := UniqueInputFieldNames
+	expectErrors(t, rule, `{ f(in: {a: 1, b: {a: 2}}, x: [{a: 1}, {a: 2}]) }`)
+	expectErrors(t, rule, `{ f(in: {a: 1, b: {a: 2, a: 3}, a: 4}) }`,
+		expectedError{`There can be only one input field named "a".`, []int{9, 32}},
+		expectedError{`There can be only one input field named "a".`, []int{19, 25}},
+	)
+}
+
+func TestVariableValues(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, valuesSchema+`
+input One @oneOf { a: Int b: String }`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	op := asttest.Parse(t, `query ($i: Int, $r: Int!, $d: Int! = 1, $e: E, $in: In, $l: [In!], $id: ID, $at: DateTime, $one: One) { f }`).Definitions[0].(*ast.OperationDefinition)
+	tests := []struct {
+		name     string
+		values   map[string]any
+		expected []string
+	}{
+		{"Valid", map[string]any{"i": 1.0, "r": json.Number("2"), "e": "A", "in": map[string]any{"a": 1}, "l": map[string]any{"a": 2, "c": []any{}}, "id": 7, "at": 1, "one": map[string]any{"b": "x"}}, nil},
+		{"Required", map[string]any{"d": nil}, []string{
+			`Variable "$r" of required type "Int!" was not provided.`,
+			`Variable "$d" of non-null type "Int!" must not be null.`,
+		}},
+		{"Scalars", map[string]any{"i": 1.5, "r": 1 << 31, "id": true}, []string{
+			`Variable "$i" got invalid value 1.5; Int cannot represent non-integer value: 1.5`,
+			`Variable "$r" got invalid value 2147483648; Int cannot represent non 32-bit signed integer value: 2147483648`,
+			`Variable "$id" got invalid value true; ID cannot represent value: true`,
+		}},
+		{"Enum", map[string]any{"r": 1, "e": "C"}, []string{
+			`Variable "$e" got invalid value "C"; Value "C" does not exist in "E" enum.`,
+		}},
+		{"Input object", map[string]any{"r": 1, "in": map[string]any{"x": 1, "c": []any{map[string]any{"a": nil}}}, "l": []any{"x"}}, []string{
+			`Variable "$in" got invalid value {"c":[{"a":null}],"x":1}; Field "a" of required type "Int!" was not provided.`,
+			`Variable "$in" got invalid value null at "in.c[0].a"; Expected non-nullable type "Int!" not to be null.`,
+			`Variable "$in" got invalid value {"c":[{"a":null}],"x":1}; Field "x" is not defined by type "In".`,
+			`Variable "$l" got invalid value "x" at "l[0]"; Expected type "In" to be an object.`,
+		}},
+		{"OneOf", map[string]any{"r": 1, "one": map[string]any{"a": 1, "b": "x"}}, []string{
+			`Variable "$one" got invalid value {"a":1,"b":"x"}; Exactly one key must be specified for OneOf type "One".`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range VariableValues(s, op, tt.values) {
+				got = append(got, e.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
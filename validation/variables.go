@@ -0,0 +1,320 @@
+package validation
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// VariablesInAllowedPosition checks that variables are only used where
+// values of their type are accepted, including usages in fragments spread
+// into the operation. A nullable variable may be used in a non-null
+// position when either the variable or the location has a non-null
+// default value. The rule requires schema; usages of undefined variables
+// and in positions unknown to schema are ignored.
+//
+// https://spec.graphql.org/draft/#sec-All-Variable-Usages-Are-Allowed
+var VariablesInAllowedPosition = Rule{
+	Name: "VariablesInAllowedPosition",
+	Check: func(ctx *Context) {
+		if ctx.Schema == nil {
+			return
+		}
+		fragments := fragmentDefinitions(ctx.Document)
+
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok {
+				continue
+			}
+			defs := make(map[string]*ast.VariableDefinition, len(op.VariableDefs))
+			for _, v := range op.VariableDefs {
+				if _, ok := defs[v.Variable.Name.Value]; !ok {
+					defs[v.Variable.Name.Value] = v
+				}
+			}
+			c := usageCollector{schema: ctx.Schema, fragments: fragments, visited: make(map[string]bool)}
+			c.directives(op.Directives)
+			if root := ctx.Schema.RootType(op.OperationType); root != nil {
+				c.selectionSet(root, op.SelectionSet)
+			}
+			for _, u := range c.usages {
+				def, ok := defs[u.variable.Name.Value]
+				if !ok || AllowedInPosition(def, u.location, u.hasDefault) {
+					continue
+				}
+				ctx.Reportf([]ast.Node{def, u.variable}, "Variable \"$%s\" of type \"%s\" used in position expecting type \"%s\".",
+					u.variable.Name.Value, printer.Print(def.Type), printer.Print(u.location))
+			}
+		}
+	},
+}
+
+// AllowedInPosition reports whether variable defined by def may be used
+// in location of type location, which has default value when hasDefault
+// is set. It is the check of VariablesInAllowedPosition for a single
+// usage.
+func AllowedInPosition(def *ast.VariableDefinition, location ast.Type, hasDefault bool) bool {
+	if nonNull, ok := location.(*ast.NonNullType); ok {
+		if _, ok := def.Type.(*ast.NonNullType); !ok {
+			_, isNull := def.DefaultValue.(*ast.NullValue)
+			if !hasDefault && (def.DefaultValue == nil || isNull) {
+				return false
+			}
+			return subType(def.Type, nonNull.Type)
+		}
+	}
+	return subType(def.Type, location)
+}
+
+// subType reports whether values of input type t are accepted by type of.
+func subType(t, of ast.Type) bool {
+	switch of := of.(type) {
+	case *ast.NonNullType:
+		if t, ok := t.(*ast.NonNullType); ok {
+			return subType(t.Type, of.Type)
+		}
+		return false
+	case *ast.ListType:
+		switch t := t.(type) {
+		case *ast.NonNullType:
+			return subType(t.Type, of)
+		case *ast.ListType:
+			return subType(t.Type, of.Type)
+		}
+		return false
+	case *ast.NamedType:
+		switch t := t.(type) {
+		case *ast.NonNullType:
+			return subType(t.Type, of)
+		case *ast.NamedType:
+			return t.Name.Value == of.Name.Value
+		}
+	}
+	return false
+}
+
+// variableUsage is a variable used in a position of type location.
+type variableUsage struct {
+	variable   *ast.Variable
+	location   ast.Type
+	hasDefault bool
+}
+
+// usageCollector collects variable usages of an operation with types of
+// their positions.
+type usageCollector struct {
+	schema    *schema.Schema
+	fragments map[string]*ast.FragmentDefinition
+	visited   map[string]bool
+
+	usages []variableUsage
+}
+
+func (c *usageCollector) selectionSet(parent *schema.Type, set *ast.SelectionSet) {
+	if set == nil {
+		return
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			c.directives(s.Directives)
+			var field *schema.Field
+			if parent != nil {
+				field = parent.Field(s.Name.Value)
+			}
+			if field == nil {
+				c.selectionSet(nil, s.SelectionSet)
+				continue
+			}
+			c.arguments(field.Arguments, s.Arguments)
+			c.selectionSet(c.schema.Type(namedType(field.Type)), s.SelectionSet)
+		case *ast.InlineFragment:
+			c.directives(s.Directives)
+			t := parent
+			if s.TypeCondition != nil {
+				t = c.schema.Type(s.TypeCondition.Name.Value)
+			}
+			c.selectionSet(t, s.SelectionSet)
+		case *ast.FragmentSpread:
+			c.directives(s.Directives)
+			name := s.Name.Value
+			if c.visited[name] {
+				continue
+			}
+			c.visited[name] = true
+			if f, ok := c.fragments[name]; ok {
+				c.directives(f.Directives)
+				c.selectionSet(c.schema.Type(f.TypeCondition.Name.Value), f.SelectionSet)
+			}
+		}
+	}
+}
+
+func (c *usageCollector) directives(directives []*ast.Directive) {
+	for _, d := range directives {
+		if def := lookupDirective(c.schema, d.Name.Value); def != nil {
+			c.arguments(def.Arguments, d.Arguments)
+		}
+	}
+}
+
+// arguments collects usages in args defined by defs. Unknown arguments
+// are skipped.
+func (c *usageCollector) arguments(defs []*schema.InputValue, args []*ast.Argument) {
+	for _, arg := range args {
+		for _, def := range defs {
+			if def.Name == arg.Name.Value {
+				c.value(arg.Value, def.Type, def.DefaultValue != nil)
+				break
+			}
+		}
+	}
+}
+
+func (c *usageCollector) value(v ast.Value, location ast.Type, hasDefault bool) {
+	switch v := v.(type) {
+	case *ast.Variable:
+		c.usages = append(c.usages, variableUsage{variable: v, location: location, hasDefault: hasDefault})
+	case *ast.ListValue:
+		if nonNull, ok := location.(*ast.NonNullType); ok {
+			location = nonNull.Type
+		}
+		list, ok := location.(*ast.ListType)
+		if !ok {
+			return
+		}
+		for _, item := range v.Values {
+			c.value(item, list.Type, false)
+		}
+	case *ast.ObjectValue:
+		t := c.schema.Type(namedType(location))
+		if t == nil || t.Kind != schema.KindInputObject {
+			return
+		}
+		for _, f := range v.Fields {
+			if def := t.InputField(f.Name.Value); def != nil {
+				c.value(f.Value, def.Type, def.DefaultValue != nil)
+			}
+		}
+	}
+}
+
+// UniqueVariableNames checks that each variable of an operation is
+// defined once. All definitions with the same name are reported together.
+//
+// https://spec.graphql.org/draft/#sec-Variable-Uniqueness
+var UniqueVariableNames = Rule{
+	Name: "UniqueVariableNames",
+	Check: func(ctx *Context) {
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok {
+				continue
+			}
+			for _, group := range duplicates(op.VariableDefs, func(v *ast.VariableDefinition) string { return v.Variable.Name.Value }) {
+				vars := make([]ast.Node, len(group))
+				for i, v := range group {
+					vars[i] = v.Variable
+				}
+				ctx.Reportf(vars, "There can be only one variable named \"$%s\".", group[0].Variable.Name.Value)
+			}
+		}
+	},
+}
+
+// NoUndefinedVariables checks that variables used by an operation,
+// directly or in spread fragments, are defined by it.
+//
+// https://spec.graphql.org/draft/#sec-All-Variable-Uses-Defined
+var NoUndefinedVariables = Rule{
+	Name: "NoUndefinedVariables",
+	Check: func(ctx *Context) {
+		fragments := fragmentDefinitions(ctx.Document)
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok {
+				continue
+			}
+			defined := make(map[string]bool, len(op.VariableDefs))
+			for _, v := range op.VariableDefs {
+				defined[v.Variable.Name.Value] = true
+			}
+			for _, v := range variableUsages(op, fragments) {
+				name := v.Name.Value
+				if defined[name] {
+					continue
+				}
+				if op.Name != nil {
+					ctx.Reportf([]ast.Node{v, op}, "Variable \"$%s\" is not defined by operation %q.", name, op.Name.Value)
+				} else {
+					ctx.Reportf([]ast.Node{v, op}, "Variable \"$%s\" is not defined.", name)
+				}
+			}
+		}
+	},
+}
+
+// NoUnusedVariables checks that variables defined by an operation are
+// used by it, directly or in spread fragments.
+//
+// https://spec.graphql.org/draft/#sec-All-Variables-Used
+var NoUnusedVariables = Rule{
+	Name: "NoUnusedVariables",
+	Check: func(ctx *Context) {
+		fragments := fragmentDefinitions(ctx.Document)
+		for _, def := range ctx.Document.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok || len(op.VariableDefs) == 0 {
+				continue
+			}
+			used := make(map[string]bool)
+			for _, v := range variableUsages(op, fragments) {
+				used[v.Name.Value] = true
+			}
+			for _, v := range op.VariableDefs {
+				name := v.Variable.Name.Value
+				if used[name] {
+					continue
+				}
+				if op.Name != nil {
+					ctx.Reportf([]ast.Node{v}, "Variable \"$%s\" is never used in operation %q.", name, op.Name.Value)
+				} else {
+					ctx.Reportf([]ast.Node{v}, "Variable \"$%s\" is never used.", name)
+				}
+			}
+		}
+	},
+}
+
+// variableUsages returns variables used by op and fragments spread into
+// it, each fragment walked once. Variables of variable definitions are
+// not usages.
+func variableUsages(op *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition) []*ast.Variable {
+	var usages []*ast.Variable
+	var queue []*ast.FragmentDefinition
+	visited := make(map[string]bool)
+
+	var v ast.TypeVisitor
+	ast.OnEnter(&v, func(n *ast.VariableDefinition) ast.Action {
+		return ast.Skip
+	})
+	ast.OnEnter(&v, func(n *ast.Variable) ast.Action {
+		usages = append(usages, n)
+		return ast.Skip
+	})
+	ast.OnEnter(&v, func(n *ast.FragmentSpread) ast.Action {
+		if f, ok := fragments[n.Name.Value]; ok && !visited[n.Name.Value] {
+			visited[n.Name.Value] = true
+			queue = append(queue, f)
+		}
+		return ast.Continue
+	})
+	ast.Walk(&v, op)
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		ast.Walk(&v, f)
+	}
+	return usages
+}
@@ -0,0 +1,146 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+func TestVariablesInAllowedPosition(t *testing.T) {
+	s, err := schema.FromDocument(asttest.Parse(t, `
+directive @d(if: Boolean!) on FIELD | FRAGMENT_SPREAD
+type Query {
+  int(x: Int): Int
+  nonNull(x: Int!): Int
+  withDefault(x: Int! = 1): Int
+  list(x: [Int]): Int
+  nonNullList(x: [Int!]!): Int
+  input(x: In): Int
+  child: Query
+}
+input In { a: Int! b: Int! = 1 c: [String!] }`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected []expectedError
+	}{
+		{"Same type", `query ($a: Int, $b: Int!) { int(x: $a) nonNull(x: $b) }`, nil},
+		{"Non-null to nullable", `query ($a: Int!, $b: [Int!]!) { int(x: $a) list(x: $b) }`, nil},
+		{"Item of list", `query ($a: Int, $b: Int!) { list(x: [$a, 1]) nonNullList(x: [$b]) }`, nil},
+		{"Variable default", `query ($a: Int = 1) { nonNull(x: $a) }`, nil},
+		{"Location default", `query ($a: Int) { withDefault(x: $a) input(x: {a: 1, b: $a}) }`, nil},
+		{"Undefined variable", `{ nonNull(x: $a) }`, nil},
+		{"Unknown field", `query ($a: Int) { unknown(x: $a) child { unknown(x: $a) } }`, nil},
+		{
+			"Nullable to non-null",
+			`query ($a: Int) { child { nonNull(x: $a) } }`,
+			[]expectedError{{`Variable "$a" of type "Int" used in position expecting type "Int!".`, []int{7, 37}}},
+		},
+		{
+			"Null default",
+			`query ($a: Int = null) { nonNull(x: $a) }`,
+			[]expectedError{{`Variable "$a" of type "Int" used in position expecting type "Int!".`, []int{7, 36}}},
+		},
+		{
+			"List mismatch",
+			`query ($a: [Int], $b: Int) { nonNullList(x: $a) list(x: $b) }`,
+			[]expectedError{
+				{`Variable "$a" of type "[Int]" used in position expecting type "[Int!]!".`, []int{7, 44}},
+				{`Variable "$b" of type "Int" used in position expecting type "[Int]".`, []int{18, 56}},
+			},
+		},
+		{
+			"Different types",
+			`query ($a: String, $b: String) { int(x: $a) input(x: {a: 1, c: [$b]}) }`,
+			[]expectedError{
+				{`Variable "$a" of type "String" used in position expecting type "Int".`, []int{7, 40}},
+				{`Variable "$b" of type "String" used in position expecting type "String!".`, []int{19, 64}},
+			},
+		},
+		{
+			"Directives and fragments",
+			`query Q($a: Boolean) { ...F @d(if: $a) } fragment F on Query { child { ... { nonNull(x: $n) } } }
+query R($n: Int) { ...F ...F }`,
+			[]expectedError{
+				{`Variable "$a" of type "Boolean" used in position expecting type "Boolean!".`, []int{8, 35}},
+				{`Variable "$n" of type "Int" used in position expecting type "Int!".`, []int{106, 88}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []expectedError
+			for _, e := range Validate(s, asttest.Parse(t, tt.input), VariablesInAllowedPosition) {
+				got = append(got, expectedError{e.Message, e.Positions})
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAllowedInPosition(t *testing.T) {
+	doc := asttest.Parse(t, `query ($a: Int, $b: Int = 2, $c: [Int!]!) { f(x: 1, y: [1]) }`)
+	op := doc.Definitions[0].(*ast.OperationDefinition)
+	a, b, c := op.VariableDefs[0], op.VariableDefs[1], op.VariableDefs[2]
+	typeOf := func(input string) ast.Type {
+		d := asttest.Parse(t, `query ($v: `+input+`) { f }`)
+		return d.Definitions[0].(*ast.OperationDefinition).VariableDefs[0].Type
+	}
+	tests := []struct {
+		def        *ast.VariableDefinition
+		location   string
+		hasDefault bool
+		expected   bool
+	}{
+		{a, "Int", false, true},
+		{a, "Int!", false, false},
+		{a, "Int!", true, true},
+		{b, "Int!", false, true},
+		{b, "Float", false, false},
+		{c, "[Int]", false, true},
+		{c, "[Int!]!", false, true},
+		{c, "[[Int]]", false, false},
+		{c, "Int", false, false},
+	}
+	for _, tt := range tests {
+		if got := AllowedInPosition(tt.def, typeOf(tt.location), tt.hasDefault); got != tt.expected {
+			t.Errorf("$%s in %s (default %t): expected %t", tt.def.Variable.Name.Value, tt.location, tt.hasDefault, tt.expected)
+		}
+	}
+}
+
+func TestUniqueVariableNames(t *testing.T) {
+	rule := UniqueVariableNames
+	expectErrors(t, rule, `query A($a: Int, $b: Int) { a } query B($a: Int) { a }`)
+	expectErrors(t, rule, `query ($a: Int, $b: Int, $a: String, $a: ID) { a }`,
+		expectedError{`There can be only one variable named "$a".`, []int{7, 25, 37}},
+	)
+}
+
+func TestNoUndefinedVariables(t *testing.T) {
+	rule := NoUndefinedVariables
+	expectErrors(t, rule, `query ($a: Int, $b: Int) { f(x: $a) ...F } fragment F on T { ...G } fragment G on T { g(x: {y: [$b]}) }`)
+	expectErrors(t, rule, `query Q($a: Int) { f(x: $a, y: $b) ...F } { g(z: $c) } fragment F on T { h(x: $d) }`,
+		expectedError{`Variable "$b" is not defined by operation "Q".`, []int{31, 0}},
+		expectedError{`Variable "$d" is not defined by operation "Q".`, []int{78, 0}},
+		expectedError{`Variable "$c" is not defined.`, []int{49, 42}},
+	)
+}
+
+func TestNoUnusedVariables(t *testing.T) {
+	rule := NoUnusedVariables
+	expectErrors(t, rule, `query ($a: Int, $b: Boolean) @d(x: $a) { ...F } fragment F on T { f @skip(if: $b) }`)
+	expectErrors(t, rule, `query Q($a: Int, $b: Int @d(x: $b), $c: Int) { ...F } query ($d: Int) { a } fragment F on T { f(x: $c) }`,
+		expectedError{`Variable "$a" is never used in operation "Q".`, []int{8}},
+		expectedError{`Variable "$b" is never used in operation "Q".`, []int{17}},
+		expectedError{`Variable "$d" is never used.`, []int{61}},
+	)
+}
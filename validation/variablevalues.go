@@ -0,0 +1,215 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/printer"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// VariableValues checks variable values of operation op as the server
+// coerces them. Values are in form decoded by encoding/json: nil, bool,
+// string, float64 or json.Number, []any and map[string]any; Go integers
+// are accepted as well. Values of custom scalars are not checked. Errors
+// are reported at variable definitions under rule name "VariableValues".
+//
+// https://spec.graphql.org/draft/#sec-Coercing-Variable-Values
+func VariableValues(s *schema.Schema, op *ast.OperationDefinition, values map[string]any) []*Error {
+	c := &variableCoercion{schema: s}
+	for _, def := range op.VariableDefs {
+		c.def = def
+		name := def.Variable.Name.Value
+		value, ok := values[name]
+		_, nonNull := def.Type.(*ast.NonNullType)
+		switch {
+		case !ok && def.DefaultValue == nil && nonNull:
+			c.report("Variable \"$%s\" of required type %q was not provided.", name, printer.Print(def.Type))
+		case !ok:
+		case value == nil && nonNull:
+			c.report("Variable \"$%s\" of non-null type %q must not be null.", name, printer.Print(def.Type))
+		default:
+			c.value(value, def.Type, []string{name})
+		}
+	}
+	return c.errs
+}
+
+// variableCoercion checks values of variable def.
+type variableCoercion struct {
+	schema *schema.Schema
+	def    *ast.VariableDefinition
+	errs   []*Error
+}
+
+func (c *variableCoercion) report(format string, args ...any) {
+	c.errs = append(c.errs, &Error{
+		Rule:      "VariableValues",
+		Message:   fmt.Sprintf(format, args...),
+		Positions: []int{c.def.Pos()},
+	})
+}
+
+// invalid reports value at path, which starts with variable name.
+func (c *variableCoercion) invalid(value any, path []string, format string, args ...any) {
+	at := ""
+	if len(path) > 1 {
+		at = fmt.Sprintf(" at %q", strings.Join(path, ""))
+	}
+	c.report("Variable \"$%s\" got invalid value %s%s; %s", path[0], jsonValue(value), at, fmt.Sprintf(format, args...))
+}
+
+func (c *variableCoercion) value(value any, t ast.Type, path []string) {
+	if nonNull, ok := t.(*ast.NonNullType); ok {
+		if value == nil {
+			c.invalid(value, path, "Expected non-nullable type %q not to be null.", printer.Print(t))
+			return
+		}
+		t = nonNull.Type
+	}
+	if value == nil {
+		return
+	}
+	if list, ok := t.(*ast.ListType); ok {
+		// Single value is coerced to a list of one item.
+		items, ok := value.([]any)
+		if !ok {
+			c.value(value, list.Type, path)
+			return
+		}
+		for i, item := range items {
+			c.value(item, list.Type, append(path[:len(path):len(path)], "["+strconv.Itoa(i)+"]"))
+		}
+		return
+	}
+
+	name := namedType(t)
+	typ := lookupType(c.schema, name)
+	switch {
+	case specifiedScalars[name] && (typ == nil || typ.Kind == schema.KindScalar):
+		if message := scalarValue(value, name); message != "" {
+			c.invalid(value, path, "%s", message)
+		}
+	case typ == nil, typ.Kind == schema.KindScalar:
+	case typ.Kind == schema.KindEnum:
+		s, ok := value.(string)
+		switch {
+		case !ok:
+			c.invalid(value, path, "Enum %q cannot represent non-string value: %s.", typ.Name, jsonValue(value))
+		case typ.EnumValue(s) == nil:
+			c.invalid(value, path, "Value %q does not exist in %q enum.", s, typ.Name)
+		}
+	case typ.Kind == schema.KindInputObject:
+		c.object(value, typ, path)
+	}
+}
+
+func (c *variableCoercion) object(value any, t *schema.Type, path []string) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		c.invalid(value, path, "Expected type %q to be an object.", t.Name)
+		return
+	}
+	for _, def := range t.InputFields {
+		field, ok := obj[def.Name]
+		_, nonNull := def.Type.(*ast.NonNullType)
+		switch {
+		case !ok && def.DefaultValue == nil && nonNull:
+			c.invalid(value, path, "Field %q of required type %q was not provided.", def.Name, printer.Print(def.Type))
+		case ok:
+			c.value(field, def.Type, append(path[:len(path):len(path)], "."+def.Name))
+		}
+	}
+	// Report unknown fields in a stable order.
+	var unknown []string
+	for name := range obj {
+		if t.InputField(name) == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	for _, name := range unknown {
+		c.invalid(value, path, "Field %q is not defined by type %q.", name, t.Name)
+	}
+	if t.IsOneOf() {
+		set := 0
+		for _, v := range obj {
+			if v != nil {
+				set++
+			}
+		}
+		if len(obj) != 1 || set != 1 {
+			c.invalid(value, path, "Exactly one key must be specified for OneOf type %q.", t.Name)
+		}
+	}
+}
+
+// scalarValue returns message when value cannot be coerced to built-in
+// scalar name, as its parseValue in graphql-js does.
+func scalarValue(value any, name string) string {
+	switch name {
+	case "Int":
+		f, ok := number(value)
+		switch {
+		case !ok:
+			return "Int cannot represent non-integer value: " + jsonValue(value)
+		case f != math.Trunc(f):
+			return "Int cannot represent non-integer value: " + jsonValue(value)
+		case f > math.MaxInt32 || f < math.MinInt32:
+			return "Int cannot represent non 32-bit signed integer value: " + jsonValue(value)
+		}
+	case "Float":
+		if _, ok := number(value); !ok {
+			return "Float cannot represent non numeric value: " + jsonValue(value)
+		}
+	case "String":
+		if _, ok := value.(string); !ok {
+			return "String cannot represent a non string value: " + jsonValue(value)
+		}
+	case "Boolean":
+		if _, ok := value.(bool); !ok {
+			return "Boolean cannot represent a non boolean value: " + jsonValue(value)
+		}
+	case "ID":
+		if _, ok := value.(string); ok {
+			return ""
+		}
+		if f, ok := number(value); !ok || f != math.Trunc(f) {
+			return "ID cannot represent value: " + jsonValue(value)
+		}
+	}
+	return ""
+}
+
+// number returns numeric value as float64.
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func jsonValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
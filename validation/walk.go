@@ -0,0 +1,162 @@
+package validation
+
+import (
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/introspection"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// walkSelections calls fn for every selection of operations and fragment
+// definitions of ctx.Document, including nested ones, with the type it is
+// selected on, which is nil when unknown. Fragment spreads are not
+// followed; fragment definitions are walked on their own.
+func walkSelections(ctx *Context, fn func(parent *schema.Type, sel ast.Selection)) {
+	var walk func(parent *schema.Type, set *ast.SelectionSet)
+	walk = func(parent *schema.Type, set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+		for _, sel := range set.Selections {
+			fn(parent, sel)
+			switch s := sel.(type) {
+			case *ast.Field:
+				var t *schema.Type
+				if def := fieldDefinition(ctx.Schema, parent, s.Name.Value); def != nil {
+					t = lookupType(ctx.Schema, namedType(def.Type))
+				}
+				walk(t, s.SelectionSet)
+			case *ast.InlineFragment:
+				t := parent
+				if s.TypeCondition != nil {
+					t = lookupType(ctx.Schema, s.TypeCondition.Name.Value)
+				}
+				walk(t, s.SelectionSet)
+			}
+		}
+	}
+	for _, def := range ctx.Document.Definitions {
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			var root *schema.Type
+			if ctx.Schema != nil {
+				root = ctx.Schema.RootType(d.OperationType)
+			}
+			walk(root, d.SelectionSet)
+		case *ast.FragmentDefinition:
+			walk(lookupType(ctx.Schema, d.TypeCondition.Name.Value), d.SelectionSet)
+		}
+	}
+}
+
+// fieldDefinition returns definition of field name of parent, including
+// meta fields, or nil.
+func fieldDefinition(s *schema.Schema, parent *schema.Type, name string) *schema.Field {
+	if parent == nil {
+		return nil
+	}
+	if f := parent.Field(name); f != nil {
+		return f
+	}
+	if !isComposite(parent) {
+		return nil
+	}
+	var query string
+	if t := s.QueryType(); t != nil {
+		query = t.Name
+	}
+	return introspection.MetaField(name, parent.Name, query)
+}
+
+// lookupType returns named type of s or of the introspection system, or
+// nil, also when s is nil.
+func lookupType(s *schema.Schema, name string) *schema.Type {
+	if s == nil {
+		return nil
+	}
+	if t := s.Type(name); t != nil {
+		return t
+	}
+	return introspection.SystemType(name)
+}
+
+func isComposite(t *schema.Type) bool {
+	switch t.Kind {
+	case schema.KindObject, schema.KindInterface, schema.KindUnion:
+		return true
+	}
+	return false
+}
+
+// isInputType reports whether named type is an input type. Built-in
+// scalars need not be defined by s.
+func isInputType(s *schema.Schema, name string) bool {
+	t := lookupType(s, name)
+	if t == nil {
+		return specifiedScalars[name]
+	}
+	switch t.Kind {
+	case schema.KindScalar, schema.KindEnum, schema.KindInputObject:
+		return true
+	}
+	return false
+}
+
+// knownType reports whether type name is defined by s or is a built-in
+// scalar.
+func knownType(s *schema.Schema, name string) bool {
+	return specifiedScalars[name] || lookupType(s, name) != nil
+}
+
+func namedTypeRef(name string) *ast.NamedType {
+	return &ast.NamedType{Name: &ast.Name{Value: name}}
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch tt := t.(type) {
+		case *ast.NamedType:
+			return tt.Name.Value
+		case *ast.ListType:
+			t = tt.Type
+		case *ast.NonNullType:
+			t = tt.Type
+		default:
+			return ""
+		}
+	}
+}
+
+// fragmentDefinitions returns fragments of doc by name. The first one wins
+// when names are duplicated.
+func fragmentDefinitions(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			if _, ok := fragments[f.Name.Value]; !ok {
+				fragments[f.Name.Value] = f
+			}
+		}
+	}
+	return fragments
+}
+
+// duplicates returns groups of items sharing a name, in order of first
+// appearance.
+func duplicates[T any](items []T, name func(T) string) [][]T {
+	byName := make(map[string][]T)
+	var order []string
+	for _, item := range items {
+		n := name(item)
+		if _, ok := byName[n]; !ok {
+			order = append(order, n)
+		}
+		byName[n] = append(byName[n], item)
+	}
+	var result [][]T
+	for _, n := range order {
+		if group := byName[n]; len(group) > 1 {
+			result = append(result, group)
+		}
+	}
+	return result
+}
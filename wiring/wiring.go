@@ -0,0 +1,160 @@
+// Package wiring checks registrations of an executor against the schema it
+// serves, so that servers fail fast at startup on a missing resolver or a
+// resolver left behind by a removed field, rather than erroring at request
+// time:
+//
+//	report := wiring.Check(s, &wiring.Wiring{
+//		Resolvers:     []coordinate.Coordinate{coordinate.Member("Query", "user")},
+//		Scalars:       []string{"DateTime"},
+//		TypeResolvers: []string{"Node"},
+//	})
+//	if err := report.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+package wiring
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gqlhub/gqlhub-core/ast"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+	"github.com/gqlhub/gqlhub-core/schema"
+)
+
+// Wiring lists what is registered with an executor.
+type Wiring struct {
+	Resolvers     []coordinate.Coordinate // Fields of object types with resolvers.
+	Scalars       []string                // Custom scalars with serialization.
+	TypeResolvers []string                // Interfaces and unions resolving their object types.
+	IsTypeOf      []string                // Object types checking their values.
+
+	// RequireResolver reports whether field must have a resolver. By
+	// default only fields of root operation types must, other fields may
+	// be resolved from values of their parents.
+	RequireResolver func(field coordinate.Coordinate) bool
+}
+
+// Report lists missing and orphaned registrations. Missing ones are in
+// schema order, orphaned ones in registration order.
+type Report struct {
+	MissingResolvers     []coordinate.Coordinate
+	MissingScalars       []string
+	MissingTypeResolvers []string // Abstract types with object types not checking values.
+
+	OrphanedResolvers     []coordinate.Coordinate // Not fields of object types.
+	OrphanedScalars       []string                // Not custom scalars.
+	OrphanedTypeResolvers []string                // Not interfaces or unions.
+	OrphanedIsTypeOf      []string                // Not object types.
+}
+
+// builtinScalars need no registration.
+var builtinScalars = map[string]bool{"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true}
+
+// Check cross-references registrations of w with types of s. Types and
+// fields of the introspection system are not checked.
+//
+// An abstract type is wired when it has a type resolver or every object
+// type implementing it checks its values with isTypeOf.
+func Check(s *schema.Schema, w *Wiring) *Report {
+	r := &Report{}
+	require := w.RequireResolver
+	if require == nil {
+		roots := make(map[string]bool)
+		for _, op := range []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription} {
+			if t := s.RootType(op); t != nil {
+				roots[t.Name] = true
+			}
+		}
+		require = func(field coordinate.Coordinate) bool {
+			return roots[field.Name]
+		}
+	}
+	resolvers := make(map[coordinate.Coordinate]bool, len(w.Resolvers))
+	for _, c := range w.Resolvers {
+		resolvers[c] = true
+	}
+
+	for _, t := range s.Types() {
+		if strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		switch t.Kind {
+		case schema.KindObject:
+			for _, f := range t.Fields {
+				c := coordinate.Member(t.Name, f.Name)
+				if !resolvers[c] && !strings.HasPrefix(f.Name, "__") && require(c) {
+					r.MissingResolvers = append(r.MissingResolvers, c)
+				}
+			}
+		case schema.KindScalar:
+			if !builtinScalars[t.Name] && !slices.Contains(w.Scalars, t.Name) {
+				r.MissingScalars = append(r.MissingScalars, t.Name)
+			}
+		case schema.KindInterface, schema.KindUnion:
+			if slices.Contains(w.TypeResolvers, t.Name) {
+				continue
+			}
+			possible := s.PossibleTypes(t)
+			checked := len(possible) > 0
+			for _, p := range possible {
+				checked = checked && slices.Contains(w.IsTypeOf, p.Name)
+			}
+			if !checked {
+				r.MissingTypeResolvers = append(r.MissingTypeResolvers, t.Name)
+			}
+		}
+	}
+
+	for _, c := range w.Resolvers {
+		t := s.Type(c.Name)
+		if c.Directive || c.Argument != "" || t == nil || t.Kind != schema.KindObject || t.Field(c.Member) == nil {
+			r.OrphanedResolvers = append(r.OrphanedResolvers, c)
+		}
+	}
+	for _, name := range w.Scalars {
+		if t := s.Type(name); t == nil || t.Kind != schema.KindScalar || builtinScalars[name] {
+			r.OrphanedScalars = append(r.OrphanedScalars, name)
+		}
+	}
+	for _, name := range w.TypeResolvers {
+		if t := s.Type(name); t == nil || t.Kind != schema.KindInterface && t.Kind != schema.KindUnion {
+			r.OrphanedTypeResolvers = append(r.OrphanedTypeResolvers, name)
+		}
+	}
+	for _, name := range w.IsTypeOf {
+		if t := s.Type(name); t == nil || t.Kind != schema.KindObject {
+			r.OrphanedIsTypeOf = append(r.OrphanedIsTypeOf, name)
+		}
+	}
+	return r
+}
+
+// Err returns error listing every problem of r, one per line, or nil when
+// there are none.
+func (r *Report) Err() error {
+	var errs []error
+	add := func(format string, items ...any) {
+		for _, item := range items {
+			errs = append(errs, fmt.Errorf(format, item))
+		}
+	}
+	add("wiring: missing resolver of %s", toAny(r.MissingResolvers)...)
+	add("wiring: missing scalar %s", toAny(r.MissingScalars)...)
+	add("wiring: missing type resolver of %s", toAny(r.MissingTypeResolvers)...)
+	add("wiring: resolver of %s does not match a field of an object type", toAny(r.OrphanedResolvers)...)
+	add("wiring: scalar %s is not a custom scalar", toAny(r.OrphanedScalars)...)
+	add("wiring: type resolver of %s does not match an interface or union", toAny(r.OrphanedTypeResolvers)...)
+	add("wiring: isTypeOf of %s does not match an object type", toAny(r.OrphanedIsTypeOf)...)
+	return errors.Join(errs...)
+}
+
+func toAny[T any](s []T) []any {
+	result := make([]any, len(s))
+	for i, v := range s {
+		result[i] = v
+	}
+	return result
+}
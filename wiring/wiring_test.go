@@ -0,0 +1,114 @@
+package wiring
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gqlhub/gqlhub-core/asttest"
+	"github.com/gqlhub/gqlhub-core/coordinate"
+)
+
+const testSDL = `
+type Query { user(id: ID!): User node(id: ID!): Node search: [Result!]! }
+type Mutation { rename(name: String!): User }
+interface Node { id: ID! }
+type User implements Node { id: ID! name: String createdAt: DateTime }
+type Post implements Node { id: ID! }
+union Result = User | Post
+scalar DateTime
+scalar String
+`
+
+func TestCheck(t *testing.T) {
+	s := asttest.Schema(t, testSDL)
+	tests := []struct {
+		name     string
+		wiring   *Wiring
+		expected *Report
+	}{
+		{
+			name: "wired",
+			wiring: &Wiring{
+				Resolvers: []coordinate.Coordinate{
+					coordinate.Member("Query", "user"),
+					coordinate.Member("Query", "node"),
+					coordinate.Member("Query", "search"),
+					coordinate.Member("Mutation", "rename"),
+					coordinate.Member("User", "name"),
+				},
+				Scalars:       []string{"DateTime"},
+				TypeResolvers: []string{"Node"},
+				IsTypeOf:      []string{"User", "Post"},
+			},
+			expected: &Report{},
+		},
+		{
+			name:   "missing",
+			wiring: &Wiring{IsTypeOf: []string{"User"}},
+			expected: &Report{
+				MissingResolvers: []coordinate.Coordinate{
+					coordinate.Member("Query", "user"),
+					coordinate.Member("Query", "node"),
+					coordinate.Member("Query", "search"),
+					coordinate.Member("Mutation", "rename"),
+				},
+				MissingScalars:       []string{"DateTime"},
+				MissingTypeResolvers: []string{"Node", "Result"},
+			},
+		},
+		{
+			name: "orphaned",
+			wiring: &Wiring{
+				Resolvers: []coordinate.Coordinate{
+					coordinate.Member("Query", "user"),
+					coordinate.Member("Query", "removed"),
+					coordinate.Member("Node", "id"),
+					coordinate.Argument("User", "name", "x"),
+					coordinate.Member("Unknown", "id"),
+				},
+				Scalars:       []string{"DateTime", "String", "Date"},
+				TypeResolvers: []string{"Node", "Result", "User"},
+				IsTypeOf:      []string{"Node", "Gone"},
+				RequireResolver: func(field coordinate.Coordinate) bool {
+					return field == coordinate.Member("Query", "user")
+				},
+			},
+			expected: &Report{
+				OrphanedResolvers: []coordinate.Coordinate{
+					coordinate.Member("Query", "removed"),
+					coordinate.Member("Node", "id"),
+					coordinate.Argument("User", "name", "x"),
+					coordinate.Member("Unknown", "id"),
+				},
+				OrphanedScalars:       []string{"String", "Date"},
+				OrphanedTypeResolvers: []string{"User"},
+				OrphanedIsTypeOf:      []string{"Node", "Gone"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Check(s, tt.wiring)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReport_Err(t *testing.T) {
+	if err := (&Report{}).Err(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	r := &Report{
+		MissingResolvers: []coordinate.Coordinate{coordinate.Member("Query", "user")},
+		MissingScalars:   []string{"DateTime"},
+		OrphanedIsTypeOf: []string{"Gone"},
+	}
+	expected := "wiring: missing resolver of Query.user\n" +
+		"wiring: missing scalar DateTime\n" +
+		"wiring: isTypeOf of Gone does not match an object type"
+	if err := r.Err(); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}